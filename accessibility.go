@@ -0,0 +1,292 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AccessibilityIssueKind is the kind of an accessibility issue found by CheckAccessibility.
+type AccessibilityIssueKind string
+
+const (
+	// AccessibilitySkippedHeading is a heading whose level is more than one below the level of the previous heading,
+	// e.g. a level 3 heading following a level 1 heading. Screen reader users navigate by heading levels.
+	AccessibilitySkippedHeading AccessibilityIssueKind = "skipped-heading"
+	// AccessibilityMissingHeaderRow is a table whose first row is not marked as header row, so screen readers do not
+	// announce the column headers.
+	AccessibilityMissingHeaderRow AccessibilityIssueKind = "missing-header-row"
+)
+
+var (
+	// outlineLevelRegex matches the outline level of paragraph properties and captures the level, 0 for headings of
+	// level 1 and 9 for body text.
+	outlineLevelRegex = regexp.MustCompile(`<w:outlineLvl\s+w:val="([0-9])"\s*/>`)
+	// tableHeaderRegex matches the header row mark of row properties which is switched on.
+	tableHeaderRegex = regexp.MustCompile(`<w:tblHeader(?:\s+w:val="(?:1|true|on)")?\s*/>`)
+
+	// rowPropertiesOrder is the order of the child elements of row properties required by the schema.
+	rowPropertiesOrder = []string{
+		"cnfStyle", "divId", "gridBefore", "gridAfter", "wBefore", "wAfter", "cantSplit", "trHeight", "tblHeader",
+		"tblCellSpacing", "jc", "hidden", "ins", "del", "trPrChange",
+	}
+)
+
+// AccessibilityIssue is a violation of the accessibility rules for generated content, see CheckAccessibility.
+type AccessibilityIssue struct {
+	Kind AccessibilityIssueKind
+	// Text is the text of the heading, or the text of the first row of the table.
+	Text    string
+	Message string
+	// Fixed is true if the issue was fixed by FixAccessibility.
+	Fixed bool
+}
+
+// String returns the message of the issue.
+func (i AccessibilityIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+// AccessibilityFixes selects the issues fixed by FixAccessibility.
+type AccessibilityFixes struct {
+	// Headings lowers the level of headings which skip levels to one below the previous heading. Headings are
+	// only fixed if the document defines a heading style of the new level, or if they use a direct outline level.
+	Headings bool
+	// HeaderRows marks the first row of tables without header row as header row.
+	HeaderRows bool
+}
+
+// CheckAccessibility checks the document body for headings which skip levels (e.g. H1 followed by H3) and tables
+// with more than one row whose first row is not marked as header row. Headings are paragraphs with a heading style
+// (e.g. 'Heading 2') or with an outline level. It is meant for documents with generated content, e.g.
+// headings and tables inserted from data, see FixAccessibility to repair them.
+func (d *Document) CheckAccessibility() ([]AccessibilityIssue, error) {
+	return d.accessibility(AccessibilityFixes{})
+}
+
+// FixAccessibility checks the document like CheckAccessibility and fixes the selected issues. All issues are
+// returned, fixed issues are marked as such.
+//
+// Example:
+//
+//	issues, err := doc.FixAccessibility(docx.AccessibilityFixes{Headings: true, HeaderRows: true})
+//	for _, issue := range issues {
+//	    if !issue.Fixed {
+//	        log.Printf("accessibility: %s", issue)
+//	    }
+//	}
+func (d *Document) FixAccessibility(fixes AccessibilityFixes) ([]AccessibilityIssue, error) {
+	return d.accessibility(fixes)
+}
+
+// accessibility checks the document body and applies the selected fixes.
+func (d *Document) accessibility(fixes AccessibilityFixes) ([]AccessibilityIssue, error) {
+	issues, err := d.checkHeadings(fixes.Headings)
+	if err != nil {
+		return nil, err
+	}
+	tableIssues, err := d.checkHeaderRows(fixes.HeaderRows)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, tableIssues...), nil
+}
+
+// headingLevel returns the heading level (1-9) of the paragraph style, 0 if it is no heading style.
+func headingLevel(style styleInfo) int {
+	if style.styleType != "paragraph" {
+		return 0
+	}
+	for _, name := range []string{style.id, style.name} {
+		if match := headingStyleRegex.FindStringSubmatch(name); match != nil {
+			level, _ := strconv.Atoi(match[1])
+			return level
+		}
+	}
+	if match := outlineLevelRegex.FindStringSubmatch(style.definition); match != nil && match[1] != "9" {
+		level, _ := strconv.Atoi(match[1])
+		return level + 1
+	}
+	return 0
+}
+
+// checkHeadings returns all headings of the body which skip levels, and lowers their level if fix is true.
+func (d *Document) checkHeadings(fix bool) ([]AccessibilityIssue, error) {
+	_, styles, err := d.styles()
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]int)
+	levelStyles := make(map[int]string)
+	for _, style := range styles {
+		if level := headingLevel(style); level > 0 {
+			levels[style.id] = level
+			if _, exists := levelStyles[level]; !exists {
+				levelStyles[level] = style.id
+			}
+		}
+	}
+
+	data := string(d.files[DocumentXml])
+	var paragraphs [][2]int
+	collectParagraphs(data, 0, &paragraphs)
+	var issues []AccessibilityIssue
+	type change struct {
+		start, end int
+		properties string
+	}
+	var changes []change
+	previous := 0
+	for _, position := range paragraphs {
+		paragraph := data[position[0]:position[1]]
+		match := paragraphPropertiesRegex.FindStringSubmatchIndex(paragraph)
+		if match == nil || match[4] < 0 {
+			continue
+		}
+		properties := paragraph[match[4]:match[5]]
+		level := 0
+		outline := outlineLevelRegex.FindStringSubmatch(properties)
+		if outline != nil {
+			level, _ = strconv.Atoi(outline[1])
+			level = (level + 1) % 10
+		} else if style := paragraphStyleRegex.FindStringSubmatch(properties); style != nil {
+			level = levels[style[1]]
+		}
+		if level == 0 {
+			continue
+		}
+		if level <= previous+1 {
+			previous = level
+			continue
+		}
+
+		issue := AccessibilityIssue{
+			Kind:    AccessibilitySkippedHeading,
+			Text:    elementText([]byte(paragraph)),
+			Message: fmt.Sprintf("heading of level %d follows a heading of level %d", level, previous),
+		}
+		if fix {
+			expected := previous + 1
+			fixed := ""
+			if outline != nil {
+				fixed = outlineLevelRegex.ReplaceAllString(properties, fmt.Sprintf(`<w:outlineLvl w:val="%d"/>`, expected-1))
+			} else if id, exists := levelStyles[expected]; exists {
+				fixed = paragraphStyleRegex.ReplaceAllString(properties, fmt.Sprintf(`<w:pStyle w:val="%s"`, id))
+			}
+			if fixed != "" {
+				changes = append(changes, change{position[0] + match[4], position[0] + match[5], fixed})
+				issue.Fixed, level = true, expected
+			}
+		}
+		issues = append(issues, issue)
+		previous = level
+	}
+
+	if len(changes) == 0 {
+		return issues, nil
+	}
+	// paragraphs are changed from the end so the offsets of the preceding paragraphs stay valid
+	for i := len(changes) - 1; i >= 0; i-- {
+		data = data[:changes[i].start] + changes[i].properties + data[changes[i].end:]
+	}
+	if err := d.SetFile(DocumentXml, []byte(data)); err != nil {
+		return nil, err
+	}
+	return issues, d.parseFile(DocumentXml)
+}
+
+// checkHeaderRows returns all tables of the body with more than one row but without header row, and marks their
+// first row as header row if fix is true.
+func (d *Document) checkHeaderRows(fix bool) ([]AccessibilityIssue, error) {
+	data := string(d.files[DocumentXml])
+	var tables [][2]int
+	collectTables(data, 0, &tables)
+	// tables are fixed from the end so the offsets of the preceding tables stay valid, nested tables start behind
+	// the first row of the table containing them
+	sort.Slice(tables, func(i, j int) bool { return tables[i][0] > tables[j][0] })
+
+	var issues []AccessibilityIssue
+	// inserted are the lengths of the inserted properties by their offsets, the tables containing them grow
+	inserted := make(map[int]int)
+	for _, table := range tables {
+		end := table[1]
+		for pos, length := range inserted {
+			if pos < table[1] {
+				end += length
+			}
+		}
+		openTag, content, _ := splitElement(data[table[0]:end])
+		var rows []childElement
+		for _, child := range childElements(content) {
+			if child.name == "tr" {
+				rows = append(rows, child)
+			}
+		}
+		if len(rows) < 2 {
+			continue
+		}
+		row := content[rows[0].start:rows[0].end]
+		rowOpenTag, rowContent, _ := splitElement(row)
+		insertAt, properties := 0, ""
+		for _, child := range childElements(rowContent) {
+			if child.name == "tblPrEx" {
+				insertAt = child.end
+			}
+			if child.name == "trPr" {
+				insertAt, properties = child.start, rowContent[child.start:child.end]
+				break
+			}
+		}
+		if tableHeaderRegex.MatchString(properties) {
+			continue
+		}
+
+		issue := AccessibilityIssue{
+			Kind:    AccessibilityMissingHeaderRow,
+			Text:    strings.TrimSpace(elementText([]byte(row))),
+			Message: fmt.Sprintf("table with %d rows has no header row", len(rows)),
+		}
+		if fix {
+			trPrOpen, trPrContent, trPrClose := "<w:trPr>", "", "</w:trPr>"
+			if properties != "" {
+				trPrOpen, trPrContent, trPrClose = splitElement(properties)
+			}
+			fixed := trPrOpen + setOrderedElement(trPrContent, rowPropertiesOrder, "tblHeader", "<w:tblHeader/>") + trPrClose
+			start := table[0] + len(openTag) + rows[0].start + len(rowOpenTag) + insertAt
+			data = data[:start] + fixed + data[start+len(properties):]
+			inserted[start] = len(fixed) - len(properties)
+			issue.Fixed = true
+		}
+		issues = append(issues, issue)
+	}
+	// issues are reported in document order
+	for i, j := 0, len(issues)-1; i < j; i, j = i+1, j-1 {
+		issues[i], issues[j] = issues[j], issues[i]
+	}
+
+	if len(inserted) == 0 {
+		return issues, nil
+	}
+	if err := d.SetFile(DocumentXml, []byte(data)); err != nil {
+		return nil, err
+	}
+	return issues, d.parseFile(DocumentXml)
+}
+
+// collectTables adds the positions of all tables inside the content to the tables, including nested tables.
+func collectTables(content string, offset int, tables *[][2]int) {
+	for _, child := range childElements(content) {
+		switch child.name {
+		case "p", "sectPr", "tblPr", "tblGrid", "trPr", "tcPr", "sdtPr", "sdtEndPr", "del", "moveFrom":
+			continue
+		case "tbl":
+			*tables = append(*tables, [2]int{offset + child.start, offset + child.end})
+		}
+		openTag, inner, _ := splitElement(content[child.start:child.end])
+		if inner != "" {
+			collectTables(inner, offset+child.start+len(openTag), tables)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_FixAccessibility(t *testing.T) {
+	heading := func(style, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	row := func(text string) string {
+		return `<w:tr><w:tc><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:tc></w:tr>`
+	}
+	body := heading("Heading1", "Report") + heading("Heading3", "Details") + heading("Heading4", "More") +
+		heading("Heading2", "Summary") + `<w:p><w:pPr><w:outlineLvl w:val="3"/></w:pPr><w:r><w:t>Outline</w:t></w:r></w:p>` +
+		`<w:tbl><w:tblPr/>` + row("Name") + row("Jane") + `</w:tbl>` +
+		`<w:tbl><w:tr><w:trPr><w:tblHeader/></w:trPr><w:tc><w:p/></w:tc></w:tr>` + row("Jane") + `</w:tbl>` +
+		`<w:tbl>` + row("Layout") + `</w:tbl>` +
+		`<w:sectPr/>`
+	template := buildTestDocx(t, body, StylesXml, builderStyles())
+
+	doc, err := OpenBytes(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := doc.CheckAccessibility()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []AccessibilityIssue{
+		{Kind: AccessibilitySkippedHeading, Text: "Details", Message: "heading of level 3 follows a heading of level 1"},
+		{Kind: AccessibilitySkippedHeading, Text: "Outline", Message: "heading of level 4 follows a heading of level 2"},
+		{Kind: AccessibilityMissingHeaderRow, Text: "Name", Message: "table with 2 rows has no header row"},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("expected %+v, have %+v", expected, issues)
+	}
+
+	issues, err = doc.FixAccessibility(AccessibilityFixes{Headings: true, HeaderRows: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 4 {
+		t.Fatalf("expected 4 issues, have %+v", issues)
+	}
+	for _, issue := range issues {
+		if !issue.Fixed {
+			t.Errorf("expected fixed issue, have %+v", issue)
+		}
+	}
+	document := string(doc.files[DocumentXml])
+	for _, expected := range []string{
+		heading("Heading2", "Details") + heading("Heading3", "More") + heading("Heading2", "Summary") +
+			`<w:p><w:pPr><w:outlineLvl w:val="2"/></w:pPr><w:r><w:t>Outline</w:t></w:r></w:p>`,
+		`<w:tbl><w:tblPr/><w:tr><w:trPr><w:tblHeader/></w:trPr><w:tc>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+	if issues, err := doc.CheckAccessibility(); err != nil || len(issues) != 0 {
+		t.Errorf("expected no issues after fixing, have %+v (%v)", issues, err)
+	}
+}
@@ -0,0 +1,145 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// annotationReferenceRegex matches the references to footnotes, endnotes and comments and the ranges of comments,
+// which are removed from appended documents.
+var annotationReferenceRegex = regexp.MustCompile(`<w:(?:footnoteReference|endnoteReference|commentReference|` +
+	`commentRangeStart|commentRangeEnd)\s[^>]*/>`)
+
+// Merge concatenates the documents: the body of every document is appended to the first one, see Document.Append.
+//
+// Example:
+//
+//	outputBytes, err := docx.Merge(coverPageBytes, reportBytes)
+func Merge(docs ...[]byte) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to merge")
+	}
+	doc, err := OpenBytes(docs[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document from bytes: %w", err)
+	}
+	defer doc.Close()
+
+	for i := 1; i < len(docs); i++ {
+		other, err := OpenBytes(docs[i])
+		if err != nil {
+			return nil, fmt.Errorf("document %d: failed to open document from bytes: %w", i, err)
+		}
+		err = doc.Append(other)
+		other.Close()
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Append appends the body of the other document to the end of the document, e.g. a report to its cover page.
+// The appended content starts on a new page and keeps the page setup of its sections. Images, hyperlinks,
+// numbering, the headers and footers of the sections and the styles used by the other document are copied, so the
+// result opens in Word without repairs. Styles which exist in both documents keep the definition of the document.
+// Sections of the other document without headers or footers continue those of the document.
+//
+// Footnotes, endnotes and comments are not copied, their references are removed. Documents using other related
+// parts (e.g. charts) are rejected. The other document is not modified.
+func (d *Document) Append(other *Document) error {
+	match := BodyContentRegex.FindSubmatch(other.files[DocumentXml])
+	if match == nil {
+		return fmt.Errorf("invalid document, %s has no body", DocumentXml)
+	}
+	body := annotationReferenceRegex.ReplaceAllString(string(match[1]), "")
+
+	source := &contentSource{name: "appended document", part: other.part, numIds: make(map[string]string)}
+	numbering, exists, err := other.part(NumberingXml)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if source.numIds, err = d.copyNumbering(numbering); err != nil {
+			return fmt.Errorf("unable to copy numbering of %s: %w", source.name, err)
+		}
+	}
+
+	// headers and footers referenced by the sections are copied as new parts
+	rels, _, err := other.part(relationshipsPart(DocumentXml))
+	if err != nil {
+		return err
+	}
+	referenced := make(map[string]bool)
+	for _, reference := range RelationshipReferenceRegex.FindAllStringSubmatch(body, -1) {
+		referenced[html.UnescapeString(reference[2])] = true
+	}
+	relIds := make(map[string]string)
+	for _, rel := range parseRelationships(rels) {
+		kind := headerKind
+		switch {
+		case !referenced[rel.id] || rel.external:
+			continue
+		case rel.relType == RelationshipTypeFooter:
+			kind = footerKind
+		case rel.relType != RelationshipTypeHeader:
+			continue
+		}
+		if relIds[rel.id], err = d.copyHeaderFooter(kind, source, rel.partName(DocumentXml)); err != nil {
+			return err
+		}
+	}
+
+	content, err := d.importContent(DocumentXml, source, DocumentXml, body, relIds)
+	if err != nil {
+		return err
+	}
+
+	data := d.files[DocumentXml]
+	bodyMatch := BodyContentRegex.FindSubmatchIndex(data)
+	_, end, err := bodyContent(data)
+	if err != nil {
+		return err
+	}
+	var merged string
+	if children := childElements(content); len(children) > 0 && children[len(children)-1].name == "sectPr" {
+		// the final section of the document ends in front of the appended content, whose final section ends the body
+		sectPr := strings.TrimSpace(string(data[end:bodyMatch[3]]))
+		if sectPr == "" {
+			sectPr = "<w:sectPr/>"
+		}
+		merged = string(data[:end]) + "<w:p><w:pPr>" + sectPr + "</w:pPr></w:p>" + content + string(data[bodyMatch[3]:])
+	} else {
+		merged = string(data[:end]) + recordSeparatorXml + content + string(data[end:])
+	}
+	if err := d.SetFile(DocumentXml, []byte(merged)); err != nil {
+		return err
+	}
+	return d.parseFile(DocumentXml)
+}
+
+// copyHeaderFooter copies the header or footer part of the source into a new part of the document and returns the
+// ID of the relationship of the new part.
+func (d *Document) copyHeaderFooter(kind headerFooterKind, source *contentSource, name string) (string, error) {
+	data, exists, err := source.part(name)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("invalid %s, %s does not exist", source.name, name)
+	}
+	copied := d.newHeaderFooterName(kind.root)
+	content := annotationReferenceRegex.ReplaceAllString(string(data), "")
+	if content, err = d.importContent(copied, source, name, content, make(map[string]string)); err != nil {
+		return "", err
+	}
+	return d.addHeaderFooterPart(kind, copied, []byte(content))
+}
@@ -0,0 +1,117 @@
+package docx
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := func(text string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:hdr>`
+	}
+	cover := buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Annual Report</w:t></w:r></w:p>`+
+			`<w:sectPr><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`,
+		"word/_rels/document.xml.rels", testRelationshipsPart(`<Relationship Id="rId1" Type="`+RelationshipTypeHeader+`" Target="header1.xml"/>`),
+		"word/header1.xml", header("Cover header"),
+		StylesXml, testStylesXml,
+	)
+	report := buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="Quote"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr>`+
+			`<w:r><w:t>Revenue grew</w:t></w:r><w:r><w:footnoteReference w:id="1"/></w:r>`+
+			`<w:hyperlink r:id="rId2"><w:r><w:t>details</w:t></w:r></w:hyperlink></w:p>`+
+			`<w:p><w:r><w:drawing><wp:inline><wp:docPr id="1" name="Chart"/><a:blip r:embed="rId3"/></wp:inline></w:drawing></w:r></w:p>`+
+			`<w:sectPr><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/></w:sectPr>`,
+		"word/_rels/document.xml.rels", testRelationshipsPart(`<Relationship Id="rId1" Type="`+RelationshipTypeHeader+`" Target="header1.xml"/>`+
+			`<Relationship Id="rId2" Type="`+RelationshipTypeHyperlink+`" Target="https://example.com/report" TargetMode="External"/>`+
+			`<Relationship Id="rId3" Type="`+RelationshipTypeImage+`" Target="media/image1.jpeg"/>`+
+			`<Relationship Id="rId4" Type="`+RelationshipTypeNumbering+`" Target="numbering.xml"/>`),
+		"word/header1.xml", header("Report header"),
+		"word/media/image1.jpeg", string(imageBytes),
+		NumberingXml, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:abstractNum w:abstractNumId="0"><w:nsid w:val="1A2B3C4D"/><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>`+
+			`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num></w:numbering>`,
+		StylesXml, `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Report Quote"/></w:style></w:styles>`,
+	)
+
+	output, err := Merge(cover, report, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{
+		// the section of the cover page ends in front of the report
+		`Annual Report</w:t></w:r></w:p><w:p><w:pPr><w:sectPr><w:headerReference w:type="default" r:id="rId1"/><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:pPr></w:p>`,
+		`<w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Revenue grew</w:t></w:r><w:r></w:r><w:hyperlink r:id="rId4">`,
+		`<w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t>Revenue grew</w:t></w:r><w:r></w:r><w:hyperlink r:id="rId7">`,
+		`<wp:docPr id="1" name="Chart"/><a:blip r:embed="rId5"/>`,
+		// the image is stored once
+		`<wp:docPr id="2" name="Chart"/><a:blip r:embed="rId5"/>`,
+		`<w:sectPr><w:headerReference w:type="default" r:id="rId3"/><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/></w:sectPr></w:pPr></w:p>`,
+		`<w:sectPr><w:headerReference w:type="default" r:id="rId6"/><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/></w:sectPr></w:body>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "footnoteReference") {
+		t.Errorf("expected footnote references to be removed: %s", document)
+	}
+
+	rels := readTestPart(t, output, "word/_rels/document.xml.rels")
+	for _, expected := range []string{
+		`<Relationship Id="rId3" Type="` + RelationshipTypeHeader + `" Target="header2.xml"/>`,
+		`<Relationship Id="rId6" Type="` + RelationshipTypeHeader + `" Target="header3.xml"/>`,
+		`Type="` + RelationshipTypeNumbering + `" Target="numbering.xml"/>`,
+	} {
+		if !strings.Contains(rels, expected) {
+			t.Errorf("expected %s in relationships: %s", expected, rels)
+		}
+	}
+	if n := strings.Count(rels, RelationshipTypeImage); n != 1 {
+		t.Errorf("expected the image to be stored once, have %d relationships: %s", n, rels)
+	}
+	if text := readTestPart(t, output, "word/header3.xml"); !strings.Contains(text, "Report header") {
+		t.Errorf("unexpected header: %s", text)
+	}
+	if types := readTestPart(t, output, ContentTypesXml); !strings.Contains(types, `PartName="/word/header3.xml"`) {
+		t.Errorf("expected content type of the header: %s", types)
+	}
+
+	numbering := readTestPart(t, output, NumberingXml)
+	for _, expected := range []string{
+		`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0">`,
+		`<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0">`,
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num><w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num></w:numbering>`,
+	} {
+		if !strings.Contains(numbering, expected) {
+			t.Errorf("expected %s in numbering: %s", expected, numbering)
+		}
+	}
+	// styles of the document win
+	if styles := readTestPart(t, output, StylesXml); strings.Contains(styles, "Report Quote") {
+		t.Errorf("expected the existing style to be kept: %s", styles)
+	}
+}
+
+func TestMerge_WithoutSections(t *testing.T) {
+	output, err := Merge(buildTestDocx(t, `<w:p><w:r><w:t>first</w:t></w:r></w:p><w:sectPr/>`), buildTestDocx(t, `<w:p><w:r><w:t>second</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `first</w:t></w:r></w:p>` + recordSeparatorXml + `<w:p><w:r><w:t>second</w:t></w:r></w:p><w:sectPr/></w:body>`
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, expected) {
+		t.Errorf("expected %s in document: %s", expected, document)
+	}
+
+	if _, err := Merge(); err == nil {
+		t.Error("expected error without documents")
+	}
+}
@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// readArchiveParts opens the given DOCX bytes and reads every part for which match returns true.
+// The returned map is keyed by the part name inside the archive.
+// The archive and all parts which are read must stay within the DefaultParseLimits.
+func readArchiveParts(input []byte, match func(name string) bool) (FileMap, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(input), int64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	limits := currentParseLimits()
+	if err := limits.checkArchive(zipReader); err != nil {
+		return nil, err
+	}
+
+	parts := make(FileMap)
+	for _, file := range zipReader.File {
+		if !match(file.Name) {
+			continue
+		}
+		data, err := limits.readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := limits.checkXML(file.Name, data); err != nil {
+			return nil, err
+		}
+		parts[file.Name] = data
+	}
+	return parts, nil
+}
+
+// rewriteArchive copies the DOCX archive given by input into a new archive, replacing the contents
+// of every part which exists in the given FileMap. All other parts are copied unchanged.
+func rewriteArchive(input []byte, parts FileMap) ([]byte, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(input), int64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+
+	for _, file := range zipReader.File {
+		fw, err := zipWriter.Create(file.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create writer: %w", err)
+		}
+
+		if _, modified := parts[file.Name]; modified {
+			if err := parts.Write(fw, file.Name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		readCloser, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %w", file.Name, err)
+		}
+		_, err = io.Copy(fw, readCloser)
+		readCloser.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to write %s: %w", file.Name, err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("unable to close ZIP writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
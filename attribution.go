@@ -0,0 +1,175 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// RevisionIdRegex matches the w:id attribute of annotations (e.g. <w:ins>, <w:del>, <w:comment>) and captures the id.
+	RevisionIdRegex = regexp.MustCompile(`w:id="(-?[0-9]+)"`)
+)
+
+// AttributeAuthor attributes all values which replace one of the given placeholder keys to the given author.
+// The values are inserted as tracked insertions (<w:ins>) of that author, so reviewers can see
+// which system (e.g. "HR System" or "Payroll") produced each value of a generated document.
+// The keys may be given with or without delimiters. It must be called before ReplaceAll or Replace.
+//
+// Example:
+//
+//	doc.AttributeAuthor("Payroll", "salary", "bonus")
+//	doc.AttributeAuthor("HR System", "name", "position")
+//	doc.ReplaceAll(values)
+func (d *Document) AttributeAuthor(author string, keys ...string) {
+	if d.authors == nil {
+		d.authors = make(map[string]string)
+	}
+	for _, key := range keys {
+		d.authors[RemovePlaceholderDelimiter(key)] = author
+	}
+}
+
+// TrackReplacements inserts the values of all placeholders as tracked insertions (<w:ins>) of the given author,
+// so generated documents can be reviewed like documents edited with Track Changes, e.g. for legal review.
+// Paragraphs and tables inserted by values are tracked as well. The date is the timestamp of the insertions, the
+// time of the replacement is used if it is zero. Values attributed to another author by AttributeAuthor keep that author.
+// It must be called before ReplaceAll or Replace.
+//
+// Example:
+//
+//	doc.TrackReplacements("Contract Generator", time.Now())
+//	doc.ReplaceAll(values)
+func (d *Document) TrackReplacements(author string, date time.Time) {
+	d.trackReplacements = true
+	d.trackAuthor = author
+	d.revisionDate = date
+}
+
+// revisionAttributes returns the attributes of a new revision mark (e.g. <w:ins>) of author.
+// Word expects every revision to have a date, the current time is used if no date was set by TrackReplacements.
+func (d *Document) revisionAttributes(author string) string {
+	date := d.revisionDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return fmt.Sprintf(`w:id="%d" w:author="%s" w:date="%s"`, d.nextRevisionId(), html.EscapeString(author),
+		date.UTC().Truncate(time.Second).Format(time.RFC3339))
+}
+
+// trackedInsertion wraps the given runs into a tracked insertion (<w:ins>) of author.
+// Runs inside of other elements, e.g. hyperlinks, are wrapped separately, see trackedContent.
+func (d *Document) trackedInsertion(author, runsXml string) string {
+	for _, child := range childElements(runsXml) {
+		if child.name != "r" {
+			return d.trackedContent(author, runsXml)
+		}
+	}
+	return `<w:ins ` + d.revisionAttributes(author) + `>` + runsXml + `</w:ins>`
+}
+
+// trackedContent marks all runs, paragraph marks and table rows of the content as tracked insertions of author.
+// Adjacent runs share a single insertion.
+func (d *Document) trackedContent(author, content string) string {
+	children := childElements(content)
+	var out strings.Builder
+	pos := 0
+	for i := 0; i < len(children); i++ {
+		child := children[i]
+		out.WriteString(content[pos:child.start])
+		pos = child.end
+		open, inner, close := splitElement(content[child.start:child.end])
+		switch {
+		case child.name == "r":
+			for i+1 < len(children) && children[i+1].name == "r" && children[i+1].start == pos {
+				i++
+				pos = children[i].end
+			}
+			out.WriteString(`<w:ins ` + d.revisionAttributes(author) + `>` + content[child.start:pos] + `</w:ins>`)
+		case child.name == "p":
+			// the inserted paragraph mark is marked inside the run properties of the paragraph mark
+			mark := `<w:ins ` + d.revisionAttributes(author) + `/>`
+			inner = d.trackedContent(author, inner)
+			properties := childElements(inner)
+			if len(properties) == 0 || properties[0].name != "pPr" {
+				inner = `<w:pPr><w:rPr>` + mark + `</w:rPr></w:pPr>` + inner
+			} else {
+				pOpen, pInner, pClose := splitElement(inner[properties[0].start:properties[0].end])
+				pInner = insertChildElement(pInner, "rPr", mark, `<w:rPr>`+mark+`</w:rPr>`, "sectPr", "pPrChange")
+				inner = pOpen + pInner + pClose + inner[properties[0].end:]
+			}
+			out.WriteString(open + inner + close)
+		case child.name == "tr":
+			mark := `<w:ins ` + d.revisionAttributes(author) + `/>`
+			inner = d.trackedContent(author, inner)
+			rowProperties := `<w:trPr>` + mark + `</w:trPr>`
+			for _, c := range childElements(inner) {
+				if c.name == "trPr" {
+					pOpen, pInner, pClose := splitElement(inner[c.start:c.end])
+					pInner = insertChildElement(pInner, "", mark, mark, "trPrChange")
+					inner = inner[:c.start] + pOpen + pInner + pClose + inner[c.end:]
+					rowProperties = ""
+					break
+				}
+			}
+			if rowProperties != "" {
+				inner = insertChildElement(inner, "", "", rowProperties, "tc", "customXml", "sdt", "bookmarkStart")
+			}
+			out.WriteString(open + inner + close)
+		case strings.HasSuffix(child.name, "Pr") || inner == "":
+			out.WriteString(content[child.start:child.end])
+		default:
+			out.WriteString(open + d.trackedContent(author, inner) + close)
+		}
+	}
+	out.WriteString(content[pos:])
+	return out.String()
+}
+
+// insertChildElement inserts element into the content of an XML element in front of the first child with one of
+// the given names, or at the end. If parent is not empty and content has a child of that name, the first child is
+// inserted at the start of that child instead, e.g. a mark into the existing run properties of a paragraph mark.
+func insertChildElement(content, parent, child, element string, before ...string) string {
+	children := childElements(content)
+	for _, c := range children {
+		if parent != "" && c.name == parent {
+			open, inner, close := splitElement(content[c.start:c.end])
+			return content[:c.start] + open + child + inner + close + content[c.end:]
+		}
+	}
+	for _, c := range children {
+		for _, name := range before {
+			if c.name == name {
+				return content[:c.start] + element + content[c.start:]
+			}
+		}
+	}
+	return content + element
+}
+
+// nextRevisionId returns an ID for a new revision mark which does not collide with any ID already used inside the document.
+func (d *Document) nextRevisionId() int {
+	if d.revisionId == 0 {
+		for _, name := range d.xmlFiles() {
+			for _, match := range RevisionIdRegex.FindAllSubmatch(d.files[name], -1) {
+				id, err := strconv.Atoi(string(match[1]))
+				if err == nil && id > d.revisionId {
+					d.revisionId = id
+				}
+			}
+		}
+	}
+	d.revisionId++
+	return d.revisionId
+}
+
+// xmlFiles returns the names of all XML files (document, headers, footers, footnotes and endnotes) which can be modified.
+func (d *Document) xmlFiles() []string {
+	files := []string{DocumentXml}
+	files = append(files, d.headerFiles...)
+	files = append(files, d.footerFiles...)
+	return append(files, d.noteFiles...)
+}
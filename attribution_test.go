@@ -0,0 +1,127 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_AttributeAuthor(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:bookmarkStart w:id="7" w:name="b"/><w:r><w:rPr><w:b/></w:rPr><w:t>Salary: {salary} for {name}</w:t></w:r></w:p>`)
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.AttributeAuthor("Payroll", "salary")
+	doc.AttributeAuthor("HR System", "{name}")
+
+	err = doc.ReplaceAll(PlaceholderMap{"salary": "4.500 & more", "name": "Jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("document is not well-formed: %s", err)
+	}
+	expected := []*regexp.Regexp{
+		regexp.MustCompile(`w:author="Payroll" w:date="([^"]*)"><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">4.500 &amp; more</w:t></w:r></w:ins>`),
+		regexp.MustCompile(`w:author="HR System" w:date="([^"]*)"><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Jane</w:t></w:r></w:ins>`),
+	}
+	for _, e := range expected {
+		match := e.FindStringSubmatch(document)
+		if match == nil {
+			t.Errorf("expected %s in document, have %s", e, document)
+			continue
+		}
+		// without a date of TrackReplacements, the insertions are dated with the time of the replacement
+		if _, err := time.Parse(time.RFC3339, match[1]); err != nil {
+			t.Errorf("expected an RFC 3339 date, have %s", match[1])
+		}
+	}
+
+	// ids of the insertions must not collide with the existing bookmark id
+	if !strings.Contains(document, `<w:ins w:id="8"`) || !strings.Contains(document, `<w:ins w:id="9"`) {
+		t.Errorf("expected revision ids 8 and 9, have %s", document)
+	}
+}
+
+func TestDocument_AttributeAuthor_MultiLineRunProperties(t *testing.T) {
+	input := buildTestDocx(t, "<w:p><w:r>\n  <w:rPr>\n    <w:b/>\n  </w:rPr>\n  <w:t>{salary}</w:t>\n</w:r></w:p>")
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.AttributeAuthor("Payroll", "salary")
+	if err := doc.ReplaceAll(PlaceholderMap{"salary": "4.500"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if !strings.Contains(document, "<w:r><w:rPr>\n    <w:b/>\n  </w:rPr><w:t xml:space=\"preserve\">4.500</w:t></w:r></w:ins>") {
+		t.Errorf("expected the inserted run to keep its properties: %s", document)
+	}
+}
+
+func TestDocument_TrackReplacements(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}, see {link}</w:t></w:r></w:p>`+
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{items}</w:t></w:r></w:p>`)
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.TrackReplacements("Contract Generator", time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC))
+	doc.AttributeAuthor("CRM", "link")
+
+	err = doc.ReplaceAll(PlaceholderMap{
+		"name":  "Jane",
+		"link":  Hyperlink{Text: "terms", URL: "https://example.com/terms"},
+		"items": Table{Rows: [][]string{{"a"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("document is not well-formed: %s", err)
+	}
+
+	date := `w:date="2025-03-14T09:30:00Z"`
+	expected := []string{
+		`w:author="Contract Generator" ` + date + `><w:r><w:t xml:space="preserve">Jane</w:t></w:r></w:ins>`,
+		// runs inside of hyperlinks are tracked inside the hyperlink
+		`<w:hyperlink r:id="rId`,
+		`w:author="CRM" ` + date + `><w:r><w:rPr><w:color w:val="0563C1"/>`,
+		// the rows and paragraph marks of tables are tracked
+		`<w:trPr><w:ins w:id=`,
+		`<w:pPr><w:rPr><w:ins w:id=`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(document, e) {
+			t.Errorf("expected %s in document, have %s", e, document)
+		}
+	}
+	if strings.Contains(document, date+`><w:hyperlink`) {
+		t.Errorf("expected the hyperlink outside of the insertion: %s", document)
+	}
+}
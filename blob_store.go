@@ -0,0 +1,294 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// docxContentType is the media type of DOCX documents.
+	docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	// s3Algorithm is the signing algorithm of S3 requests (AWS Signature Version 4).
+	s3Algorithm = "AWS4-HMAC-SHA256"
+	// s3TimeFormat is the format of the time of signed S3 requests.
+	s3TimeFormat = "20060102T150405Z"
+)
+
+var (
+	// ErrBlobNotFound is returned by a BlobStore if the requested object does not exist.
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned by a BlobStore if the key cannot name an object, e.g. because it is empty.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore reads and writes objects of an object storage by key, e.g. templates and generated documents in an
+// S3 bucket. Implementations must be safe for concurrent use.
+type BlobStore interface {
+	// Get returns the object with the key, ErrBlobNotFound if it does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores the data as object with the key, replacing an existing object.
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// ProcessFromStore reads the template with the key templateKey from the store, renders it with the data and the
+// options like Render and stores the document with the key outputKey. This is all a serverless function needs to
+// generate documents from templates in an object storage.
+//
+// Example:
+//
+//	store, err := docx.NewS3Store(docx.S3Config{Region: "eu-central-1", Bucket: "documents",
+//	    AccessKeyID: os.Getenv("AWS_ACCESS_KEY_ID"), SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+//	    SessionToken: os.Getenv("AWS_SESSION_TOKEN")})
+//	err = docx.ProcessFromStore(ctx, store, "templates/invoice.docx", "invoices/2026-0042.docx", invoice)
+func ProcessFromStore(ctx context.Context, store BlobStore, templateKey, outputKey string, data interface{}, opts ...Option) error {
+	template, err := store.Get(ctx, templateKey)
+	if err != nil {
+		return fmt.Errorf("unable to read template %s: %w", templateKey, err)
+	}
+	output, err := Render(FromBytes(template), data, append([]Option{WithContext(ctx)}, opts...)...)
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, outputKey, output); err != nil {
+		return fmt.Errorf("unable to write document %s: %w", outputKey, err)
+	}
+	return nil
+}
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	// Endpoint is the URL of the storage service, e.g. 'http://localhost:9000'. It defaults to the AWS endpoint of
+	// the region.
+	Endpoint string
+	// Region is the region of the bucket, 'us-east-1' if empty.
+	Region string
+	Bucket string
+	// AccessKeyID, SecretAccessKey and SessionToken are the credentials, the session token is only required for
+	// temporary credentials, e.g. those of AWS Lambda functions.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// PathStyle addresses the bucket by path (https://endpoint/bucket/key) instead of by host name
+	// (https://bucket.endpoint/key), which most S3 compatible services like MinIO require.
+	PathStyle bool
+	// Client is used for the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// MaxSize is the maximum size of objects read by Get in bytes, DefaultParseLimits.MaxTotalSize if zero.
+	MaxSize int64
+}
+
+// S3Store is a BlobStore for Amazon S3 and S3 compatible object storages like MinIO or Google Cloud Storage.
+// Requests are signed with AWS Signature Version 4, no SDK is required.
+type S3Store struct {
+	config   S3Config
+	endpoint *url.URL
+}
+
+// NewS3Store returns a BlobStore for the bucket of the configuration.
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("the bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid endpoint %q", config.Endpoint)
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = currentParseLimits().MaxTotalSize
+	}
+	return &S3Store{config: config, endpoint: endpoint}, nil
+}
+
+// NewMinIOStore returns a BlobStore for the bucket of the MinIO server at the endpoint, e.g. 'http://minio:9000'.
+func NewMinIOStore(endpoint, bucket, accessKeyID, secretAccessKey string) (*S3Store, error) {
+	return NewS3Store(S3Config{
+		Endpoint:        endpoint,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		PathStyle:       true,
+	})
+}
+
+// NewGCSStore returns a BlobStore for the Google Cloud Storage bucket. It uses the S3 compatible XML API of Cloud
+// Storage, which requires an HMAC key of a service account instead of OAuth credentials.
+func NewGCSStore(bucket, hmacAccessID, hmacSecret string) (*S3Store, error) {
+	return NewS3Store(S3Config{
+		Endpoint:        "https://storage.googleapis.com",
+		Region:          "auto",
+		Bucket:          bucket,
+		AccessKeyID:     hmacAccessID,
+		SecretAccessKey: hmacSecret,
+		PathStyle:       true,
+	})
+}
+
+// Get returns the object with the key.
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	response, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(response.Body, s.config.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, fmt.Errorf("%w: object %s exceeds %d bytes", ErrLimitExceeded, key, s.config.MaxSize)
+	}
+	return data, nil
+}
+
+// Put stores the object with the key. Objects whose key ends with '.docx' get the DOCX content type.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	response, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// do sends a signed request for the object with the key and returns the response if it was successful.
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidKey)
+	}
+	target := *s.endpoint
+	objectPath := "/" + key
+	if s.config.PathStyle {
+		objectPath = "/" + s.config.Bucket + objectPath
+	} else {
+		target.Host = s.config.Bucket + "." + target.Host
+	}
+	target.Path = strings.TrimSuffix(target.Path, "/") + objectPath
+	target.RawPath = s3EscapePath(target.Path)
+
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodPut {
+		contentType := "application/octet-stream"
+		if strings.HasSuffix(strings.ToLower(key), ".docx") {
+			contentType = docxContentType
+		}
+		request.Header.Set("Content-Type", contentType)
+	}
+	s.sign(request, body, time.Now())
+
+	response, err := s.config.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 == 2 {
+		return response, nil
+	}
+	defer response.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return nil, fmt.Errorf("unexpected status %s for %s %s: %s", response.Status, method, key, strings.TrimSpace(string(message)))
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request. All headers set on the request are signed.
+func (s *S3Store) sign(request *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.UTC().Format(s3TimeFormat)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.config.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		s3CanonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := amzDate[:8] + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), amzDate[:8])
+	for _, part := range []string{s.config.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the data with the key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath escapes every character of the path except unreserved characters and slashes, as required for
+// signed S3 requests.
+func s3EscapePath(p string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(p) {
+		if b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || strings.IndexByte("-_.~/", b) >= 0 {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+// s3CanonicalQuery returns the query parameters sorted by name and escaped like the path.
+func s3CanonicalQuery(query url.Values) string {
+	var params []string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, strings.ReplaceAll(s3EscapePath(name), "/", "%2F")+"="+
+				strings.ReplaceAll(s3EscapePath(value), "/", "%2F"))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
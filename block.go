@@ -0,0 +1,154 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// blockRunOpen and blockRunClose enclose the marker of a block value inside the run written by blockMarkerRun.
+	blockRunOpen  = `<w:r><w:t>`
+	blockRunClose = `</w:t></w:r>`
+)
+
+var (
+	// blockMarkerRegex matches the markers which are written for block values and captures the block index.
+	// The markers use characters from the unicode private use area which are never part of a regular document.
+	blockMarkerRegex = regexp.MustCompile(`\x{E000}block:([0-9]+)\x{E001}`)
+)
+
+// blockValue is implemented by replacement values which insert paragraphs and tables instead of runs,
+// e.g. fragments of other documents. If the placeholder is the only text of its paragraph, the paragraph is replaced,
+// otherwise the paragraph is split at the placeholder. Block values are inserted after all placeholders were replaced.
+type blockValue interface {
+	// blockXml returns the paragraphs and tables which are inserted at the position of the placeholder.
+	// The context holds the properties of the run and the paragraph of the placeholder.
+	blockXml(ctx *valueContext) (string, error)
+}
+
+// pendingBlock is a block value whose placeholder was replaced by a marker.
+type pendingBlock struct {
+	value         blockValue
+	runProperties string
+	// author is the author of the tracked insertion of the block, the block is not tracked if nil
+	author *string
+}
+
+// blockMarkerRun returns a run with the marker of the block value, the value is inserted by insertBlocks.
+func (ctx *valueContext) blockMarkerRun(value blockValue) string {
+	ctx.doc.blocks = append(ctx.doc.blocks, pendingBlock{value: value, runProperties: ctx.runProperties})
+	return blockRunOpen + blockMarker(len(ctx.doc.blocks)-1) + blockRunClose
+}
+
+// blockMarker returns the marker of the block value with the given index.
+func blockMarker(index int) string {
+	return "\uE000block:" + strconv.Itoa(index) + "\uE001"
+}
+
+// insertBlocks replaces the markers of all block values which were inserted by the last replacement with the
+// paragraphs and tables of the values.
+func (d *Document) insertBlocks() error {
+	if len(d.blocks) == 0 {
+		return nil
+	}
+	defer func() { d.blocks = nil }()
+
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		markers := blockMarkerRegex.FindAllSubmatchIndex(data, -1)
+		if len(markers) == 0 {
+			continue
+		}
+
+		// the bodies are prepared in document order, so copied relationships and drawings are numbered in reading order
+		bodies := make([]string, len(markers))
+		for i, marker := range markers {
+			index, _ := strconv.Atoi(string(data[marker[2]:marker[3]]))
+			if index >= len(d.blocks) {
+				return fmt.Errorf("invalid block marker in %s", name)
+			}
+			block := d.blocks[index]
+			ctx := &valueContext{
+				doc:                 d,
+				part:                name,
+				runProperties:       block.runProperties,
+				paragraphProperties: paragraphPropertiesAt(data, int64(marker[0])),
+			}
+			if name == DocumentXml {
+				ctx.sectionProperties = sectionPropertiesAt(data, marker[0])
+			}
+			body, err := block.value.blockXml(ctx)
+			if err != nil {
+				return err
+			}
+			if block.author != nil {
+				body = d.trackedContent(*block.author, body)
+			}
+			bodies[i] = body
+		}
+
+		paragraphStarts := ParagraphOpenRegex.FindAllIndex(data, -1)
+		changed := string(data)
+		// markers are replaced from the end so the offsets of the preceding markers stay valid
+		for i := len(markers) - 1; i >= 0; i-- {
+			marker := markers[i]
+			runStart, runEnd := marker[0]-len(blockRunOpen), marker[1]+len(blockRunClose)
+			p := sort.Search(len(paragraphStarts), func(i int) bool { return paragraphStarts[i][0] > marker[0] }) - 1
+			end := strings.Index(changed[marker[1]:], "</w:p>")
+			if runStart < 0 || changed[runStart:marker[0]] != blockRunOpen ||
+				changed[marker[1]:runEnd] != blockRunClose || p < 0 || end < 0 {
+				return fmt.Errorf("paragraphs and tables must be placed inside of a paragraph in %s", name)
+			}
+			paragraphStart, paragraphEnd := paragraphStarts[p][0], marker[1]+end+len("</w:p>")
+			paragraph := splitParagraph(changed[paragraphStart:paragraphEnd], runStart-paragraphStart, runEnd-paragraphStart, bodies[i])
+			changed = changed[:paragraphStart] + paragraph + changed[paragraphEnd:]
+		}
+
+		if err := d.SetFile(name, []byte(changed)); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitParagraph splits the paragraph at the run given by runStart and runEnd and inserts the body in between.
+// The part in front of the run is omitted if it has no text, the part behind the run is omitted if it has no text
+// and is not needed to end a section or to close a table cell.
+func splitParagraph(paragraph string, runStart, runEnd int, body string) string {
+	properties := paragraphPropertiesRegex.FindStringSubmatch(paragraph)
+	openTag, paragraphProperties := properties[1], properties[2]
+	// a section break belongs to the end of the paragraph, only the part behind the body keeps it
+	withoutSection := paragraphProperties
+	if sections := sectionProperties([]byte(paragraphProperties)); len(sections) > 0 {
+		withoutSection = paragraphProperties[:sections[0][0]] + paragraphProperties[sections[0][1]:]
+	}
+
+	var out strings.Builder
+	before := paragraph[len(properties[0]):runStart]
+	if elementText([]byte(before)) != "" {
+		out.WriteString(openTag + withoutSection + before + "</w:p>")
+	}
+	out.WriteString(body)
+	after := paragraph[runEnd : len(paragraph)-len("</w:p>")]
+	if elementText([]byte(after)) != "" || withoutSection != paragraphProperties || strings.HasSuffix(body, "</w:tbl>") || body == "" {
+		out.WriteString(openTag + paragraphProperties + after + "</w:p>")
+	}
+	return out.String()
+}
+
+// sectionPropertiesAt returns the properties (<w:sectPr>...</w:sectPr>) of the section of the document body which
+// contains the position, empty section properties if the body has none.
+func sectionPropertiesAt(data []byte, pos int) string {
+	for _, section := range sectionProperties(data) {
+		if section[1] > pos {
+			return string(data[section[0]:section[1]])
+		}
+	}
+	return "<w:sectPr/>"
+}
@@ -0,0 +1,231 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	// builderValuePrefix is the prefix of the placeholders which the Builder writes for its content.
+	builderValuePrefix = "docxBuilderValue"
+
+	// builderContentTypes, builderRelationships and builderDocumentRelationships are the package parts of built documents.
+	builderContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+		`<Override PartName="/word/styles.xml" ContentType="` + ContentTypeStyles + `"/>` +
+		`<Override PartName="/word/settings.xml" ContentType="` + ContentTypeSettings + `"/></Types>`
+	builderRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`
+	builderDocumentRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="` + RelationshipTypeStyles + `" Target="styles.xml"/>` +
+		`<Relationship Id="rId2" Type="` + RelationshipTypeSettings + `" Target="settings.xml"/></Relationships>`
+	// builderSettings turns off the compatibility mode of Word.
+	builderSettings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:defaultTabStop w:val="720"/>` +
+		`<w:compat><w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="15"/></w:compat></w:settings>`
+	// builderDocumentOpen and builderDocumentClose enclose the body of built documents, a letter page with margins
+	// of one inch, whose text width is tableTextWidth.
+	builderDocumentOpen = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>`
+	builderDocumentClose = `<w:sectPr><w:pgSz w:w="12240" w:h="15840"/>` +
+		`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+
+	// builderHeadingLevels is the number of heading levels of built documents.
+	builderHeadingLevels = 9
+)
+
+// Builder creates a DOCX document from scratch, e.g. for generated reports without a template. Its methods add
+// content to the end of the document and return the builder, so calls can be chained. The first error, e.g. an
+// invalid heading level, is returned by Document or Bytes.
+//
+// The document is a letter page with margins of one inch and defines the styles 'Normal', 'Title', 'Heading 1' to
+// 'Heading 9', 'List Paragraph', 'Hyperlink' and 'Table Grid', so all replacement values use their styles.
+//
+// Example:
+//
+//	outputBytes, err := docx.New().
+//	    AddHeading("Report", 1).
+//	    AddParagraph("Generated for ", docx.Hyperlink{Text: "ACME Corp", URL: "https://acme.example"}).
+//	    AddTable(docx.Table{Headers: []string{"Name", "Score"}, Rows: [][]string{{"Jane", "98"}}}).
+//	    Bytes()
+type Builder struct {
+	body   strings.Builder
+	values PlaceholderMap
+	err    error
+}
+
+// New returns a Builder for an empty document.
+func New() *Builder {
+	return &Builder{values: make(PlaceholderMap)}
+}
+
+// placeholder returns the run of the placeholder of a new value.
+func (b *Builder) placeholder(value interface{}) string {
+	key := fmt.Sprintf("%s%d", builderValuePrefix, len(b.values)+1)
+	b.values[key] = value
+	return `<w:r><w:t xml:space="preserve">` + AddPlaceholderDelimiter(key) + "</w:t></w:r>"
+}
+
+// paragraph adds a paragraph with the given style whose runs are the given values.
+func (b *Builder) paragraph(style string, values ...interface{}) *Builder {
+	b.body.WriteString("<w:p>")
+	if style != "" {
+		b.body.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	for _, value := range values {
+		b.body.WriteString(b.placeholder(value))
+	}
+	b.body.WriteString("</w:p>")
+	return b
+}
+
+// AddTitle adds a paragraph with the title style.
+func (b *Builder) AddTitle(text string) *Builder {
+	return b.paragraph("Title", text)
+}
+
+// AddHeading adds a heading of the given level, 1 to 9.
+func (b *Builder) AddHeading(text string, level int) *Builder {
+	if level < 1 || level > builderHeadingLevels {
+		if b.err == nil {
+			b.err = fmt.Errorf("invalid heading level %d, expected 1 to %d", level, builderHeadingLevels)
+		}
+		return b
+	}
+	return b.paragraph(fmt.Sprintf("Heading%d", level), text)
+}
+
+// AddParagraph adds a paragraph containing the values, e.g. texts, hyperlinks or images. Like replacement values,
+// texts are inserted according to the text policy, so line breaks inside texts are kept.
+func (b *Builder) AddParagraph(values ...interface{}) *Builder {
+	return b.paragraph("", values...)
+}
+
+// AddList adds a bulleted or numbered list with the given items.
+func (b *Builder) AddList(ordered bool, items ...string) *Builder {
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	var list strings.Builder
+	list.WriteString("<" + tag + ">")
+	for _, item := range items {
+		list.WriteString("<li>" + html.EscapeString(item) + "</li>")
+	}
+	list.WriteString("</" + tag + ">")
+	return b.paragraph("", HTML(list.String()))
+}
+
+// AddHTML adds the paragraphs of the HTML, see HTML.
+func (b *Builder) AddHTML(content string) *Builder {
+	return b.paragraph("", HTML(content))
+}
+
+// AddTable adds the table, see Table.
+func (b *Builder) AddTable(table Table) *Builder {
+	return b.paragraph("", table)
+}
+
+// AddImage adds a paragraph containing the image.
+func (b *Builder) AddImage(image Image) *Builder {
+	return b.paragraph("", image)
+}
+
+// AddPageBreak adds a page break, the following content starts on a new page.
+func (b *Builder) AddPageBreak() *Builder {
+	return b.paragraph("", PageBreak{Break: true})
+}
+
+// Document returns the built document, e.g. to add headers and footers or to replace placeholders of the content.
+func (b *Builder) Document() (*Document, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, part := range []struct{ name, content string }{
+		{ContentTypesXml, builderContentTypes},
+		{"_rels/.rels", builderRelationships},
+		{DocumentXml, builderDocumentOpen + b.body.String() + builderDocumentClose},
+		{relationshipsPart(DocumentXml), builderDocumentRelationships},
+		{StylesXml, builderStyles()},
+		{SettingsXml, builderSettings},
+	} {
+		w, err := zipWriter.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create writer: %w", err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	doc, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.ReplaceAll(b.values); err != nil {
+		doc.Close()
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Bytes returns the DOCX bytes of the built document.
+func (b *Builder) Bytes() ([]byte, error) {
+	doc, err := b.Document()
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// builderStyles returns the style definitions of built documents.
+func builderStyles() string {
+	var styles strings.Builder
+	styles.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Calibri" w:cs="Calibri"/>` +
+		`<w:sz w:val="22"/><w:szCs w:val="22"/><w:lang w:val="en-US"/></w:rPr></w:rPrDefault>` +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="160" w:line="259" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+		`<w:style w:type="character" w:default="1" w:styleId="DefaultParagraphFont"><w:name w:val="Default Paragraph Font"/><w:uiPriority w:val="1"/><w:semiHidden/><w:unhideWhenUsed/></w:style>` +
+		`<w:style w:type="table" w:default="1" w:styleId="TableNormal"><w:name w:val="Normal Table"/><w:uiPriority w:val="99"/><w:semiHidden/><w:unhideWhenUsed/>` +
+		`<w:tblPr><w:tblInd w:w="0" w:type="dxa"/><w:tblCellMar><w:top w:w="0" w:type="dxa"/><w:left w:w="108" w:type="dxa"/>` +
+		`<w:bottom w:w="0" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+		`<w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/><w:contextualSpacing/></w:pPr><w:rPr><w:sz w:val="56"/><w:szCs w:val="56"/></w:rPr></w:style>`)
+	for level := 1; level <= builderHeadingLevels; level++ {
+		size := htmlHeadingSizes[min(level, len(htmlHeadingSizes))-1]
+		fmt.Fprintf(&styles, `<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/>`+
+			`<w:next w:val="Normal"/><w:uiPriority w:val="9"/><w:qFormat/><w:pPr><w:keepNext/><w:keepLines/><w:spacing w:before="240" w:after="80"/>`+
+			`<w:outlineLvl w:val="%d"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="%d"/><w:szCs w:val="%d"/></w:rPr></w:style>`,
+			level, level, level-1, size, size)
+	}
+	styles.WriteString(`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/>` +
+		`<w:uiPriority w:val="34"/><w:qFormat/><w:pPr><w:ind w:left="720"/><w:contextualSpacing/></w:pPr></w:style>` +
+		`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:basedOn w:val="DefaultParagraphFont"/>` +
+		`<w:uiPriority w:val="99"/><w:unhideWhenUsed/><w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr></w:style>` +
+		`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:basedOn w:val="TableNormal"/><w:uiPriority w:val="39"/>` +
+		`<w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:tblPr>` + tableBorders + `</w:tblPr></w:style></w:styles>`)
+	return styles.String()
+}
@@ -0,0 +1,75 @@
+package docx
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := New().
+		AddTitle("Quarterly report").
+		AddHeading("Summary", 1).
+		AddParagraph("Revenue grew {fast} & ", Hyperlink{Text: "details", URL: "https://example.com"}, ".").
+		AddList(false, "first", "second <b>").
+		AddTable(Table{Headers: []string{"Name", "Score"}, Rows: [][]string{{"Jane", "98"}}}).
+		AddPageBreak().
+		AddHeading("Appendix", 2).
+		AddImage(Image{Data: imageBytes, Description: "Camera"}).
+		Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{
+		`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">Quarterly report</w:t></w:r></w:p>`,
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Summary</w:t></w:r></w:p>`,
+		`<w:t xml:space="preserve">Revenue grew {fast} &amp; </w:t>`,
+		`<w:rStyle w:val="Hyperlink"/>`,
+		`<w:pStyle w:val="ListParagraph"/>`,
+		`second &lt;b&gt;`,
+		`<w:tblStyle w:val="TableGrid"/>`,
+		`<w:br w:type="page"/>`,
+		`<w:pStyle w:val="Heading2"/>`,
+		`descr="Camera"`,
+		`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, builderValuePrefix) {
+		t.Errorf("expected all values to be replaced: %s", document)
+	}
+
+	styles := readTestPart(t, output, StylesXml)
+	for _, expected := range []string{`w:styleId="Heading9"`, `w:styleId="TableGrid"`, `w:styleId="Hyperlink"`} {
+		if !strings.Contains(styles, expected) {
+			t.Errorf("expected %s in styles", expected)
+		}
+	}
+
+	// the built document can be opened and processed again
+	doc, err := OpenBytes(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if html, err := doc.ToHTMLWithOptions(HTMLExportOptions{Fragment: true}); err != nil {
+		t.Fatal(err)
+	} else if !strings.HasPrefix(string(html), `<h1>Quarterly report</h1><h1>Summary</h1>`) || !strings.Contains(string(html), `<ul><li>first</li><li>second &lt;b&gt;</li></ul>`) {
+		t.Errorf("unexpected HTML of the built document: %s", html)
+	}
+}
+
+func TestBuilder_Error(t *testing.T) {
+	_, err := New().AddHeading("Too deep", 10).AddParagraph("text").Bytes()
+	if err == nil || !strings.Contains(err.Error(), "invalid heading level 10") {
+		t.Errorf("expected an invalid heading level error, got %v", err)
+	}
+}
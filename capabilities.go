@@ -0,0 +1,231 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Feature is a feature of WordprocessingML documents which the library supports only in part or not at all.
+type Feature string
+
+// The features reported by Capabilities.
+const (
+	FeatureHeadersFooters  Feature = "headers and footers"
+	FeatureTextBoxes       Feature = "text boxes"
+	FeatureContentControls Feature = "content controls"
+	FeatureTrackedChanges  Feature = "tracked changes"
+	FeatureComments        Feature = "comments"
+	FeatureFootnotes       Feature = "footnotes and endnotes"
+	FeatureFormFields      Feature = "legacy form fields"
+	FeatureCharts          Feature = "charts"
+	FeatureSmartArt        Feature = "SmartArt"
+	FeatureEmbeddedObjects Feature = "embedded objects"
+	FeatureAltChunks       Feature = "imported content (altChunk)"
+	FeatureMacros          Feature = "macros"
+	FeatureStrictOOXML     Feature = "strict OOXML"
+	FeatureEncryption      Feature = "encryption"
+)
+
+// Support describes how the library handles a feature.
+type Support int
+
+const (
+	// SupportProcessed features are fully processed, placeholders inside them are replaced.
+	SupportProcessed Support = iota
+	// SupportPartial features are processed with restrictions which are described by the note of the feature.
+	SupportPartial
+	// SupportPreserved features are copied into the output unchanged, placeholders inside them are not replaced.
+	SupportPreserved
+	// SupportNone features cannot be processed, the document cannot be opened or the feature is lost.
+	SupportNone
+)
+
+// String returns the name of the support level.
+func (s Support) String() string {
+	switch s {
+	case SupportProcessed:
+		return "processed"
+	case SupportPartial:
+		return "partial"
+	case SupportPreserved:
+		return "preserved"
+	case SupportNone:
+		return "unsupported"
+	default:
+		return fmt.Sprintf("Support(%d)", int(s))
+	}
+}
+
+// FeatureUsage is a feature which is used by a document.
+type FeatureUsage struct {
+	Feature Feature
+	Support Support
+	// Parts are the names of the parts which use the feature, e.g. 'word/document.xml'.
+	Parts []string
+	// Note describes the restrictions of the support.
+	Note string
+}
+
+// CapabilityReport lists the features used by a document which the library does not handle like regular text.
+// Features which are not listed are not used by the document.
+type CapabilityReport struct {
+	Features []FeatureUsage
+}
+
+// Uses returns true if the document uses the feature.
+func (r CapabilityReport) Uses(feature Feature) bool {
+	_, used := r.Support(feature)
+	return used
+}
+
+// Support returns how the feature is handled, the second value is false if the document does not use the feature.
+func (r CapabilityReport) Support(feature Feature) (Support, bool) {
+	for _, usage := range r.Features {
+		if usage.Feature == feature {
+			return usage.Support, true
+		}
+	}
+	return SupportNone, false
+}
+
+// Limited returns all used features which are handled at the given support level or worse,
+// e.g. Limited(SupportPreserved) returns the features inside which placeholders are not replaced.
+func (r CapabilityReport) Limited(level Support) []FeatureUsage {
+	var limited []FeatureUsage
+	for _, usage := range r.Features {
+		if usage.Support >= level {
+			limited = append(limited, usage)
+		}
+	}
+	return limited
+}
+
+// capabilityTag is a feature which is detected by an element inside the XML parts of the document.
+type capabilityTag struct {
+	feature Feature
+	regex   *regexp.Regexp
+	support Support
+	note    string
+}
+
+var (
+	// compoundFileSignature starts OLE compound files, which is the container of encrypted (password protected) documents.
+	compoundFileSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+	// strictNamespace is the WordprocessingML namespace of the strict conformance class.
+	strictNamespace = "http://purl.oclc.org/ooxml/wordprocessingml/main"
+
+	// noteRegex matches the open tag of a footnote or endnote and captures its attributes.
+	// Separator notes which exist in every document with notes have a w:type attribute.
+	noteRegex = regexp.MustCompile(`<w:(?:footnote|endnote)(\s[^>]*)?>`)
+
+	capabilityTags = []capabilityTag{
+		{FeatureTextBoxes, regexp.MustCompile(`<w:txbxContent[\s>]`), SupportProcessed, ""},
+		{FeatureContentControls, regexp.MustCompile(`<w:sdt[\s>]`), SupportProcessed, "the content is replaced, data bindings are not updated"},
+		{FeatureTrackedChanges, regexp.MustCompile(`<w:(?:ins|del|moveFrom|moveTo)\s`), SupportPartial, "placeholders inside deleted text are not replaced, revisions can be resolved with AcceptAllRevisions"},
+		{FeatureFormFields, regexp.MustCompile(`<w:ffData[\s>]`), SupportPreserved, "form fields are not filled"},
+		{FeatureEmbeddedObjects, regexp.MustCompile(`<w:object[\s>]`), SupportPreserved, ""},
+		{FeatureAltChunks, regexp.MustCompile(`<w:altChunk\s`), SupportPreserved, "placeholders inside the imported content are not replaced"},
+	}
+)
+
+// Capabilities reports the features of the DOCX document which the library handles only in part or not at all,
+// e.g. charts or tracked changes, so callers can reject or route documents before rendering them.
+// Encrypted documents are reported with FeatureEncryption instead of an error since they cannot be opened at all.
+//
+// Example:
+//
+//	report, err := docx.Capabilities(templateBytes)
+//	if unsupported := report.Limited(docx.SupportNone); len(unsupported) > 0 {
+//	    return fmt.Errorf("template uses %s", unsupported[0].Feature)
+//	}
+func Capabilities(input []byte) (CapabilityReport, error) {
+	if bytes.HasPrefix(input, compoundFileSignature) {
+		return CapabilityReport{Features: []FeatureUsage{{
+			Feature: FeatureEncryption,
+			Support: SupportNone,
+			Note:    "encrypted documents must be decrypted before they can be processed",
+		}}}, nil
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(input), int64(len(input)))
+	if err != nil {
+		return CapabilityReport{}, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	limits := currentParseLimits()
+	if err := limits.checkArchive(zipReader); err != nil {
+		return CapabilityReport{}, err
+	}
+
+	usages := make(map[Feature]*FeatureUsage)
+	use := func(feature Feature, support Support, part, note string) {
+		usage, exists := usages[feature]
+		if !exists {
+			usage = &FeatureUsage{Feature: feature, Support: support, Note: note}
+			usages[feature] = usage
+		}
+		if part != "" && !containsString(usage.Parts, part) {
+			usage.Parts = append(usage.Parts, part)
+		}
+	}
+
+	for _, file := range zipReader.File {
+		name := file.Name
+		switch {
+		case HeaderPathRegex.MatchString(name) || FooterPathRegex.MatchString(name):
+			use(FeatureHeadersFooters, SupportProcessed, name, "")
+		case name == "word/comments.xml":
+			use(FeatureComments, SupportPreserved, name, "placeholders inside comments are not replaced")
+		case strings.HasPrefix(name, "word/charts/") && strings.HasSuffix(name, ".xml") && !strings.Contains(name, "/_rels/"):
+			use(FeatureCharts, SupportPartial, name, "placeholders in titles, axis titles, series names and category labels are replaced, use SetChartData for the data")
+		case strings.HasPrefix(name, "word/diagrams/") && !strings.Contains(name, "/_rels/"):
+			use(FeatureSmartArt, SupportPreserved, name, "placeholders inside SmartArt are not replaced")
+		case strings.HasPrefix(name, "word/embeddings/"):
+			use(FeatureEmbeddedObjects, SupportPreserved, name, "")
+		case strings.HasSuffix(name, "vbaProject.bin"):
+			use(FeatureMacros, SupportPreserved, name, "macros are kept, the output must be saved as .docm")
+		}
+
+		if !isXMLPart(name) || !(strings.HasPrefix(name, "word/") || name == "[Content_Types].xml") {
+			continue
+		}
+		data, err := limits.readZipFile(file)
+		if err != nil {
+			return CapabilityReport{}, err
+		}
+		if bytes.Contains(data, []byte(strictNamespace)) {
+			use(FeatureStrictOOXML, SupportNone, name, "only the transitional conformance class can be processed")
+		}
+		if name == "word/footnotes.xml" || name == "word/endnotes.xml" {
+			for _, note := range noteRegex.FindAllSubmatch(data, -1) {
+				if !bytes.Contains(note[1], []byte("w:type=")) {
+					use(FeatureFootnotes, SupportProcessed, name, "")
+					break
+				}
+			}
+		}
+		for _, tag := range capabilityTags {
+			if tag.regex.Match(data) {
+				use(tag.feature, tag.support, name, tag.note)
+			}
+		}
+	}
+
+	report := CapabilityReport{}
+	for _, usage := range usages {
+		report.Features = append(report.Features, *usage)
+	}
+	// the most restricted features first
+	sort.Slice(report.Features, func(i, j int) bool {
+		a, b := report.Features[i], report.Features[j]
+		if a.Support != b.Support {
+			return a.Support > b.Support
+		}
+		return a.Feature < b.Feature
+	})
+	return report, nil
+}
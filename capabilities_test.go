@@ -0,0 +1,61 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:ins w:id="1" w:author="Jane"><w:r><w:t>{name}</w:t></w:r></w:ins></w:p>`+
+			`<w:p><w:r><w:fldChar w:fldCharType="begin"><w:ffData><w:name w:val="Text1"/></w:ffData></w:fldChar></w:r></w:p>`,
+		"word/header1.xml", `<w:hdr><w:p><w:r><w:drawing><w:txbxContent><w:p/></w:txbxContent></w:drawing></w:r></w:p></w:hdr>`,
+		"word/footnotes.xml", `<w:footnotes><w:footnote w:type="separator" w:id="-1"/><w:footnote w:id="1"><w:p/></w:footnote></w:footnotes>`,
+		"word/endnotes.xml", `<w:endnotes><w:endnote w:type="separator" w:id="-1"/></w:endnotes>`,
+		"word/charts/chart1.xml", `<c:chartSpace/>`,
+		"word/charts/_rels/chart1.xml.rels", `<Relationships/>`,
+		"word/vbaProject.bin", "macros",
+	)
+	report, err := Capabilities(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FeatureUsage{
+		{FeatureFormFields, SupportPreserved, []string{DocumentXml}, "form fields are not filled"},
+		{FeatureMacros, SupportPreserved, []string{"word/vbaProject.bin"}, "macros are kept, the output must be saved as .docm"},
+		{FeatureCharts, SupportPartial, []string{"word/charts/chart1.xml"}, "placeholders in titles, axis titles, series names and category labels are replaced, use SetChartData for the data"},
+		{FeatureTrackedChanges, SupportPartial, []string{DocumentXml}, "placeholders inside deleted text are not replaced, revisions can be resolved with AcceptAllRevisions"},
+		{FeatureFootnotes, SupportProcessed, []string{"word/footnotes.xml"}, ""},
+		{FeatureHeadersFooters, SupportProcessed, []string{"word/header1.xml"}, ""},
+		{FeatureTextBoxes, SupportProcessed, []string{"word/header1.xml"}, ""},
+	}
+	if !reflect.DeepEqual(report.Features, expected) {
+		t.Errorf("expected %+v, have %+v", expected, report.Features)
+	}
+	if !report.Uses(FeatureCharts) || report.Uses(FeatureSmartArt) {
+		t.Error("unexpected usage of charts or SmartArt")
+	}
+	if limited := report.Limited(SupportPreserved); len(limited) != 2 {
+		t.Errorf("expected 2 preserved features, have %+v", limited)
+	}
+}
+
+func TestCapabilities_Unsupported(t *testing.T) {
+	report, err := Capabilities([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if support, used := report.Support(FeatureEncryption); !used || support != SupportNone {
+		t.Errorf("expected unsupported encryption, have %+v", report)
+	}
+
+	strict := buildTestDocx(t, "", DocumentXml, `<w:document xmlns:w="http://purl.oclc.org/ooxml/wordprocessingml/main"><w:body/></w:document>`)
+	if report, err := Capabilities(strict); err != nil || len(report.Limited(SupportNone)) != 1 {
+		t.Errorf("expected strict OOXML to be unsupported, have %+v, %v", report, err)
+	}
+
+	if _, err := Capabilities([]byte("not a zip")); err == nil {
+		t.Error("expected error for invalid archive")
+	}
+}
@@ -0,0 +1,378 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RelationshipTypePackage is the relationship type of embedded packages, e.g. the workbook of a chart.
+const RelationshipTypePackage = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/package"
+
+var (
+	// ErrChartNotFound is returned if a chart with the given name does not exist inside the document.
+	ErrChartNotFound = errors.New("chart not found")
+
+	// drawingRegex matches drawings, e.g. images and charts.
+	drawingRegex = regexp.MustCompile(`(?s)<w:drawing>.*?</w:drawing>`)
+	// docPrRegex matches the drawing object properties and captures their attributes.
+	docPrRegex = regexp.MustCompile(`<wp:docPr(\s[^>]*?)/?>`)
+	// chartReferenceRegex matches the reference of a drawing to a chart part and captures the relationship ID.
+	chartReferenceRegex = regexp.MustCompile(`<c:chart\s[^>]*?r:id="([^"]*)"`)
+	// chartSeriesRegex matches the series of a chart.
+	chartSeriesRegex = regexp.MustCompile(`(?s)<c:ser>.*?</c:ser>`)
+	// chartFormulaRegex matches the cell references of chart data and captures the reference.
+	chartFormulaRegex = regexp.MustCompile(`<c:f>([^<]*)</c:f>`)
+	// chartFormatCodeRegex matches the number format of cached chart values and captures the format.
+	chartFormatCodeRegex = regexp.MustCompile(`<c:formatCode>([^<]*)</c:formatCode>`)
+
+	// sheetRegex matches the sheets of a workbook and captures their attributes.
+	sheetRegex = regexp.MustCompile(`<sheet(\s[^>]*?)/?>`)
+	// sheetDataRegex matches the cells of a worksheet.
+	sheetDataRegex = regexp.MustCompile(`(?s)<sheetData\s*/>|<sheetData>.*</sheetData>`)
+	// sheetDimensionRegex matches the used range of a worksheet and captures the part in front of the range.
+	sheetDimensionRegex = regexp.MustCompile(`(<dimension\s+ref=")[^"]*`)
+	// tableRangeRegex matches the range of a table or its filter and captures the part in front of the range.
+	tableRangeRegex = regexp.MustCompile(`(<(?:table|autoFilter)\s[^>]*?ref=")[^"]*`)
+	// tableColumnsRegex matches the columns of a table.
+	tableColumnsRegex = regexp.MustCompile(`(?s)<tableColumns\s[^>]*>.*?</tableColumns>`)
+	// tableColumnNameRegex matches the name of a table column and captures the name.
+	tableColumnNameRegex = regexp.MustCompile(`<tableColumn\s[^>]*?name="([^"]*)"`)
+)
+
+// ChartData is the data of a chart with categories, e.g. of bar, column, line, area or pie charts.
+type ChartData struct {
+	// Categories are the labels of the category axis, e.g. the months of a year.
+	Categories []string
+	Series     []ChartSeries
+}
+
+// ChartSeries is a series of a chart, one value per category. NaN values are left out, e.g. for gaps in line charts.
+type ChartSeries struct {
+	Name   string
+	Values []float64
+}
+
+// SetChartData replaces the data of the chart with the given name, which is the name (e.g. 'Chart 1'), the title or
+// the description (alternative text) of the chart inside the body, a header or a footer.
+//
+// The values cached inside the chart are updated, so the chart shows the new data without being opened in Word,
+// as well as the embedded workbook, so the data stays consistent when "Edit Data" is used. The data is written to
+// the sheet of the chart starting at A1: the categories into the first column and every series into one of the
+// following columns, with the names in the first row. Other cells of the sheet are removed.
+//
+// Series which exist in the chart keep their formatting, additional series use the default formatting of the
+// chart style. Charts without categories (e.g. scatter charts) are not supported.
+//
+// Example:
+//
+//	err := doc.SetChartData("Revenue", docx.ChartData{
+//	    Categories: []string{"Q1", "Q2", "Q3", "Q4"},
+//	    Series: []docx.ChartSeries{
+//	        {Name: "2024", Values: []float64{12.5, 14, 13.1, 17}},
+//	        {Name: "2025", Values: []float64{13, 15.2, 16, 18.4}},
+//	    },
+//	})
+func (d *Document) SetChartData(name string, data ChartData) error {
+	if len(data.Series) == 0 {
+		return fmt.Errorf("chart %s: no series", name)
+	}
+	for _, series := range data.Series {
+		if len(series.Values) != len(data.Categories) {
+			return fmt.Errorf("chart %s: series %s has %d values for %d categories", name, series.Name, len(series.Values), len(data.Categories))
+		}
+	}
+
+	chartPart, err := d.findChart(name)
+	if err != nil {
+		return err
+	}
+	chart, _, err := d.part(chartPart)
+	if err != nil {
+		return err
+	}
+
+	sheet := "Sheet1"
+	if match := chartFormulaRegex.FindSubmatch(chart); match != nil {
+		if pos := strings.LastIndexByte(string(match[1]), '!'); pos > 0 {
+			sheet = html.UnescapeString(string(match[1][:pos]))
+		}
+	}
+	changed, err := updateChartSeries(string(chart), sheet, data)
+	if err != nil {
+		return fmt.Errorf("chart %s: %w", name, err)
+	}
+	if err := d.setPart(chartPart, []byte(changed)); err != nil {
+		return err
+	}
+	// the placeholders of charts are replaced like the ones of the body
+	if _, parsed := d.files[chartPart]; parsed {
+		if err := d.parseFile(chartPart); err != nil {
+			return err
+		}
+	}
+
+	rels, _, err := d.part(relationshipsPart(chartPart))
+	if err != nil {
+		return err
+	}
+	for _, rel := range parseRelationships(rels) {
+		if rel.relType != RelationshipTypePackage || rel.external {
+			continue
+		}
+		workbookPart := rel.partName(chartPart)
+		workbook, exists, err := d.part(workbookPart)
+		if err != nil || !exists {
+			return err
+		}
+		sheetName := strings.ReplaceAll(strings.Trim(sheet, "'"), "''", "'")
+		if workbook, err = updateWorkbook(workbook, sheetName, data); err != nil {
+			return fmt.Errorf("chart %s: unable to update workbook %s: %w", name, workbookPart, err)
+		}
+		return d.setPart(workbookPart, workbook)
+	}
+	return nil
+}
+
+// findChart returns the name of the chart part of the chart with the given name, title or description.
+func (d *Document) findChart(name string) (string, error) {
+	for _, file := range d.xmlFiles() {
+		for _, drawing := range drawingRegex.FindAll(d.files[file], -1) {
+			reference := chartReferenceRegex.FindSubmatch(drawing)
+			docPr := docPrRegex.FindSubmatch(drawing)
+			if reference == nil || docPr == nil {
+				continue
+			}
+			found := false
+			for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(string(docPr[1]), -1) {
+				switch attribute[1] {
+				case "name", "title", "descr":
+					found = found || html.UnescapeString(attribute[2]) == name
+				}
+			}
+			if !found {
+				continue
+			}
+			rels, _, err := d.part(relationshipsPart(file))
+			if err != nil {
+				return "", err
+			}
+			id := html.UnescapeString(string(reference[1]))
+			for _, rel := range parseRelationships(rels) {
+				if rel.id == id && !rel.external {
+					return rel.partName(file), nil
+				}
+			}
+			return "", fmt.Errorf("invalid chart %s, relationship %s does not exist in %s", name, id, file)
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrChartNotFound, name)
+}
+
+// updateChartSeries replaces the series of the chart by the series of the data, whose values are located in the
+// given sheet as written by updateWorkbook. Additional series are copies of the last series of the chart without
+// its shape properties.
+func updateChartSeries(chart, sheet string, data ChartData) (string, error) {
+	matches := chartSeriesRegex.FindAllStringIndex(chart, -1)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("chart has no series")
+	}
+	var out strings.Builder
+	pos := 0
+	for i, match := range matches {
+		out.WriteString(chart[pos:match[0]])
+		pos = match[1]
+		series := chart[match[0]:match[1]]
+		if i < len(data.Series) {
+			updated, err := chartSeriesXml(series, sheet, data, i, false)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(updated)
+		}
+		if i < len(matches)-1 {
+			continue
+		}
+		for j := len(matches); j < len(data.Series); j++ {
+			added, err := chartSeriesXml(series, sheet, data, j, true)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(added)
+		}
+	}
+	out.WriteString(chart[pos:])
+	return out.String(), nil
+}
+
+// chartSeriesXml returns the series element with the name, categories and values of the series with the given
+// index. If added is true, the series is a copy of another series and its shape properties are removed.
+func chartSeriesXml(series, sheet string, data ChartData, index int, added bool) (string, error) {
+	column := spreadsheetColumn(index + 1)
+	last := len(data.Categories) + 1
+	sheet = html.EscapeString(sheet)
+
+	var name strings.Builder
+	fmt.Fprintf(&name, `<c:tx><c:strRef><c:f>%s!$%s$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>%s</c:v></c:pt></c:strCache></c:strRef></c:tx>`,
+		sheet, column, html.EscapeString(data.Series[index].Name))
+
+	var categories strings.Builder
+	fmt.Fprintf(&categories, `<c:cat><c:strRef><c:f>%s!$A$2:$A$%d</c:f><c:strCache><c:ptCount val="%d"/>`, sheet, last, len(data.Categories))
+	for i, category := range data.Categories {
+		fmt.Fprintf(&categories, `<c:pt idx="%d"><c:v>%s</c:v></c:pt>`, i, html.EscapeString(category))
+	}
+	categories.WriteString(`</c:strCache></c:strRef></c:cat>`)
+
+	_, content, _ := splitElement(series)
+	formatCode := "General"
+	var out strings.Builder
+	out.WriteString("<c:ser>")
+	hasValues := false
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch child.name {
+		case "idx", "order":
+			fmt.Fprintf(&out, `<c:%s val="%d"/>`, child.name, index)
+			if child.name == "order" {
+				out.WriteString(name.String())
+			}
+		case "tx", "cat":
+		case "val":
+			if match := chartFormatCodeRegex.FindStringSubmatch(element); match != nil {
+				formatCode = match[1]
+			}
+			hasValues = true
+			out.WriteString(categories.String())
+			fmt.Fprintf(&out, `<c:val><c:numRef><c:f>%s!$%s$2:$%s$%d</c:f><c:numCache><c:formatCode>%s</c:formatCode><c:ptCount val="%d"/>`,
+				sheet, column, column, last, formatCode, len(data.Categories))
+			for i, value := range data.Series[index].Values {
+				if !math.IsNaN(value) {
+					fmt.Fprintf(&out, `<c:pt idx="%d"><c:v>%s</c:v></c:pt>`, i, strconv.FormatFloat(value, 'g', -1, 64))
+				}
+			}
+			out.WriteString(`</c:numCache></c:numRef></c:val>`)
+		case "spPr", "dPt", "extLst":
+			if !added {
+				out.WriteString(element)
+			}
+		default:
+			out.WriteString(element)
+		}
+	}
+	out.WriteString("</c:ser>")
+	if !hasValues {
+		return "", fmt.Errorf("charts without category values are not supported")
+	}
+	return out.String(), nil
+}
+
+// updateWorkbook writes the data into the sheet with the given name (or the first sheet) of the XLSX workbook and
+// updates the range of the tables of the sheet.
+func updateWorkbook(workbook []byte, sheet string, data ChartData) ([]byte, error) {
+	parts, err := readArchiveParts(workbook, isXMLPart)
+	if err != nil {
+		return nil, err
+	}
+	const workbookPart = "xl/workbook.xml"
+	var relId string
+	for _, match := range sheetRegex.FindAllSubmatch(parts[workbookPart], -1) {
+		var name, id string
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(string(match[1]), -1) {
+			switch attribute[1] {
+			case "name":
+				name = html.UnescapeString(attribute[2])
+			case "r:id":
+				id = attribute[2]
+			}
+		}
+		if relId == "" || name == sheet {
+			relId = id
+		}
+		if name == sheet {
+			break
+		}
+	}
+	sheetPart := ""
+	for _, rel := range parseRelationships(parts[relationshipsPart(workbookPart)]) {
+		if rel.id == relId {
+			sheetPart = rel.partName(workbookPart)
+		}
+	}
+	worksheet, exists := parts[sheetPart]
+	if !exists {
+		return nil, fmt.Errorf("sheet %s does not exist", sheet)
+	}
+
+	// the header of the category column is kept, table columns need a name
+	names := []string{" "}
+	var tableParts []string
+	for _, rel := range parseRelationships(parts[relationshipsPart(sheetPart)]) {
+		if strings.HasSuffix(rel.relType, "/table") {
+			tableParts = append(tableParts, rel.partName(sheetPart))
+		}
+	}
+	if len(tableParts) > 0 {
+		if match := tableColumnNameRegex.FindSubmatch(parts[tableParts[0]]); match != nil {
+			names[0] = html.UnescapeString(string(match[1]))
+		}
+	}
+	for _, series := range data.Series {
+		name := series.Name
+		// table column names must be unique
+		for n := 2; containsString(names, name); n++ {
+			name = series.Name + " " + strconv.Itoa(n)
+		}
+		names = append(names, name)
+	}
+
+	lastColumn, lastRow := spreadsheetColumn(len(data.Series)), len(data.Categories)+1
+	cellRange := fmt.Sprintf("A1:%s%d", lastColumn, lastRow)
+	var sheetData strings.Builder
+	sheetData.WriteString(`<sheetData><row r="1">`)
+	for i, name := range names {
+		fmt.Fprintf(&sheetData, `<c r="%s1" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, spreadsheetColumn(i), html.EscapeString(name))
+	}
+	sheetData.WriteString(`</row>`)
+	for row, category := range data.Categories {
+		fmt.Fprintf(&sheetData, `<row r="%d"><c r="A%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, row+2, row+2, html.EscapeString(category))
+		for i, series := range data.Series {
+			if !math.IsNaN(series.Values[row]) {
+				fmt.Fprintf(&sheetData, `<c r="%s%d"><v>%s</v></c>`, spreadsheetColumn(i+1), row+2, strconv.FormatFloat(series.Values[row], 'g', -1, 64))
+			}
+		}
+		sheetData.WriteString(`</row>`)
+	}
+	sheetData.WriteString(`</sheetData>`)
+
+	if !sheetDataRegex.Match(worksheet) {
+		return nil, fmt.Errorf("invalid sheet %s", sheetPart)
+	}
+	changed := FileMap{}
+	worksheet = sheetDataRegex.ReplaceAllLiteral(worksheet, []byte(sheetData.String()))
+	changed[sheetPart] = sheetDimensionRegex.ReplaceAll(worksheet, []byte("${1}"+cellRange))
+
+	for _, tablePart := range tableParts {
+		var columns strings.Builder
+		fmt.Fprintf(&columns, `<tableColumns count="%d">`, len(names))
+		for i, name := range names {
+			fmt.Fprintf(&columns, `<tableColumn id="%d" name="%s"/>`, i+1, html.EscapeString(name))
+		}
+		columns.WriteString(`</tableColumns>`)
+		table := tableRangeRegex.ReplaceAll(parts[tablePart], []byte("${1}"+cellRange))
+		changed[tablePart] = tableColumnsRegex.ReplaceAllLiteral(table, []byte(columns.String()))
+	}
+	return rewriteArchive(workbook, changed)
+}
+
+// spreadsheetColumn returns the name of the column with the given index, e.g. A for 0 and AA for 26.
+func spreadsheetColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
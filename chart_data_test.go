@@ -0,0 +1,145 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// buildTestWorkbook returns an XLSX workbook with the given parts.
+func buildTestWorkbook(t *testing.T, parts ...string) []byte {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := 0; i < len(parts); i += 2 {
+		w, err := zipWriter.Create(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(parts[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDocument_SetChartData(t *testing.T) {
+	const workbookPart = "word/embeddings/Microsoft_Excel_Worksheet.xlsx"
+	workbook := buildTestWorkbook(t,
+		"xl/workbook.xml", `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels", testRelationshipsPart(`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>`),
+		"xl/worksheets/sheet1.xml", `<worksheet><dimension ref="A1:B3"/><sheetData><row r="1"><c r="B1" t="s"><v>0</v></c></row></sheetData><tableParts count="1"><tablePart r:id="rId1"/></tableParts></worksheet>`,
+		"xl/worksheets/_rels/sheet1.xml.rels", testRelationshipsPart(`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/table" Target="../tables/table1.xml"/>`),
+		"xl/tables/table1.xml", `<table id="1" name="Table1" displayName="Table1" ref="A1:B3"><autoFilter ref="A1:B3"/><tableColumns count="2"><tableColumn id="1" name="Quarter"/><tableColumn id="2" name="Sales"/></tableColumns></table>`,
+	)
+	series := `<c:ser><c:idx val="0"/><c:order val="0"/><c:tx><c:strRef><c:f>Sheet1!$B$1</c:f></c:strRef></c:tx>` +
+		`<c:spPr><a:solidFill/></c:spPr><c:cat><c:strRef><c:f>Sheet1!$A$2:$A$3</c:f></c:strRef></c:cat>` +
+		`<c:val><c:numRef><c:f>Sheet1!$B$2:$B$3</c:f><c:numCache><c:formatCode>#,##0</c:formatCode><c:ptCount val="2"/></c:numCache></c:numRef></c:val></c:ser>`
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:drawing><wp:inline><wp:docPr id="1" name="Chart 1" descr="Sales"/><a:graphic><a:graphicData>`+
+			`<c:chart r:id="rId5"/></a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>`,
+		"word/_rels/document.xml.rels", testRelationshipsPart(`<Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart" Target="charts/chart1.xml"/>`),
+		"word/charts/chart1.xml", `<c:chartSpace><c:chart><c:plotArea><c:barChart>`+series+`</c:barChart></c:plotArea></c:chart><c:externalData r:id="rId1"/></c:chartSpace>`,
+		"word/charts/_rels/chart1.xml.rels", testRelationshipsPart(`<Relationship Id="rId1" Type="`+RelationshipTypePackage+`" Target="../embeddings/Microsoft_Excel_Worksheet.xlsx"/>`),
+		workbookPart, string(workbook),
+	)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.SetChartData("Sales", ChartData{
+		Categories: []string{"Q1", "Q2", "Q3"},
+		Series: []ChartSeries{
+			{Name: "2024", Values: []float64{1200, 1350.5, 1100}},
+			{Name: "R&D", Values: []float64{300, math.NaN(), 420}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	chart := readTestPart(t, buf.Bytes(), "word/charts/chart1.xml")
+	for _, expected := range []string{
+		`<c:ser><c:idx val="0"/><c:order val="0"/><c:tx><c:strRef><c:f>Sheet1!$B$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>2024</c:v></c:pt></c:strCache></c:strRef></c:tx><c:spPr><a:solidFill/></c:spPr>`,
+		`<c:cat><c:strRef><c:f>Sheet1!$A$2:$A$4</c:f><c:strCache><c:ptCount val="3"/><c:pt idx="0"><c:v>Q1</c:v></c:pt><c:pt idx="1"><c:v>Q2</c:v></c:pt><c:pt idx="2"><c:v>Q3</c:v></c:pt></c:strCache></c:strRef></c:cat>`,
+		`<c:val><c:numRef><c:f>Sheet1!$B$2:$B$4</c:f><c:numCache><c:formatCode>#,##0</c:formatCode><c:ptCount val="3"/><c:pt idx="0"><c:v>1200</c:v></c:pt><c:pt idx="1"><c:v>1350.5</c:v></c:pt><c:pt idx="2"><c:v>1100</c:v></c:pt></c:numCache></c:numRef></c:val></c:ser>`,
+		`<c:ser><c:idx val="1"/><c:order val="1"/><c:tx><c:strRef><c:f>Sheet1!$C$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>R&amp;D</c:v></c:pt></c:strCache></c:strRef></c:tx><c:cat>`,
+		`<c:f>Sheet1!$C$2:$C$4</c:f><c:numCache><c:formatCode>#,##0</c:formatCode><c:ptCount val="3"/><c:pt idx="0"><c:v>300</c:v></c:pt><c:pt idx="2"><c:v>420</c:v></c:pt></c:numCache>`,
+	} {
+		if !strings.Contains(chart, expected) {
+			t.Errorf("chart does not contain %s:\n%s", expected, chart)
+		}
+	}
+	if strings.Count(chart, "<c:ser>") != 2 || strings.Count(chart, "<c:spPr>") != 1 {
+		t.Errorf("expected two series, the added one without shape properties:\n%s", chart)
+	}
+
+	updated := []byte(readTestPart(t, buf.Bytes(), workbookPart))
+	sheet := readTestPart(t, updated, "xl/worksheets/sheet1.xml")
+	for _, expected := range []string{
+		`<dimension ref="A1:C4"/>`,
+		`<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">Quarter</t></is></c><c r="B1" t="inlineStr"><is><t xml:space="preserve">2024</t></is></c><c r="C1" t="inlineStr"><is><t xml:space="preserve">R&amp;D</t></is></c></row>`,
+		`<row r="3"><c r="A3" t="inlineStr"><is><t xml:space="preserve">Q2</t></is></c><c r="B3"><v>1350.5</v></c></row>`,
+		`<tableParts count="1">`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("sheet does not contain %s:\n%s", expected, sheet)
+		}
+	}
+	table := readTestPart(t, updated, "xl/tables/table1.xml")
+	expectedTable := `<table id="1" name="Table1" displayName="Table1" ref="A1:C4"><autoFilter ref="A1:C4"/><tableColumns count="3">` +
+		`<tableColumn id="1" name="Quarter"/><tableColumn id="2" name="2024"/><tableColumn id="3" name="R&amp;D"/></tableColumns></table>`
+	if table != expectedTable {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+
+	// surplus series are removed
+	doc, err = OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetChartData("Chart 1", ChartData{Series: []ChartSeries{{Name: "Total"}}}); err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := doc.part("word/charts/chart1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart = string(data)
+	if strings.Count(chart, "<c:ser>") != 1 || !strings.Contains(chart, `<c:ptCount val="0"/>`) {
+		t.Errorf("expected a single series without values:\n%s", chart)
+	}
+}
+
+func TestDocument_SetChartDataErrors(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>No charts</w:t></w:r></w:p>`)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.SetChartData("Sales", ChartData{Categories: []string{"Q1"}, Series: []ChartSeries{{Name: "2024", Values: []float64{1}}}})
+	if !errors.Is(err, ErrChartNotFound) {
+		t.Errorf("expected ErrChartNotFound, got %v", err)
+	}
+	err = doc.SetChartData("Sales", ChartData{Categories: []string{"Q1", "Q2"}, Series: []ChartSeries{{Name: "2024", Values: []float64{1}}}})
+	if err == nil || !strings.Contains(err.Error(), "1 values for 2 categories") {
+		t.Errorf("expected a length error, got %v", err)
+	}
+}
+
+func TestSpreadsheetColumn(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if column := spreadsheetColumn(index); column != expected {
+			t.Errorf("spreadsheetColumn(%d) = %s, expected %s", index, column, expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// chartCacheTextRegex matches the cached texts of charts, e.g. of series names and category labels, and captures
+// the open tag, the text and the close tag. Numbers are cached the same way, but never contain placeholders.
+var chartCacheTextRegex = regexp.MustCompile(`(<c:v>)([^<]*)(</c:v>)`)
+
+// replaceChartTexts replaces the placeholders inside the cached texts of the chart file, e.g. of series names which
+// are not part of text runs like the titles. The values are inserted as plain text, see drawingText.
+// The embedded workbook is not changed, "Edit Data" in Word restores the texts of its cells.
+func (d *Document) replaceChartTexts(placeholderMap PlaceholderMap, file string) error {
+	data := d.files[file]
+	matches := chartCacheTextRegex.FindAllSubmatch(data, -1)
+	var texts []string
+	for _, match := range matches {
+		if text := html.UnescapeString(string(match[2])); strings.ContainsRune(text, OpenDelimiter) {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	// only the values of placeholders inside the texts are rendered, other values may not have a text representation
+	var replacements []string
+	for key, value := range placeholderMap {
+		placeholder := AddPlaceholderDelimiter(key)
+		for _, text := range texts {
+			if !strings.Contains(text, placeholder) {
+				continue
+			}
+			var valueText string
+			if _, isRich := value.(inlineValue); !isRich {
+				valueText = d.textPolicy.apply(normalizeText(fmt.Sprint(value)))
+			}
+			valueText, err := d.drawingText(key, value, valueText)
+			if err != nil {
+				return fmt.Errorf("unable to render value of %s in %s: %w", key, file, err)
+			}
+			replacements = append(replacements, placeholder, strings.ReplaceAll(valueText, "\n", " "))
+			break
+		}
+	}
+	if len(replacements) == 0 {
+		return nil
+	}
+	replacer := strings.NewReplacer(replacements...)
+	changed := chartCacheTextRegex.ReplaceAllFunc(data, func(match []byte) []byte {
+		parts := chartCacheTextRegex.FindSubmatch(match)
+		text := html.UnescapeString(string(parts[2]))
+		if replaced := replacer.Replace(text); replaced != text {
+			return []byte(string(parts[1]) + html.EscapeString(replaced) + string(parts[3]))
+		}
+		return match
+	})
+	if err := d.SetFile(file, changed); err != nil {
+		return err
+	}
+	return d.parseFile(file)
+}
+
+// chartTextPlaceholders returns the keys of all placeholders inside the cached texts of the chart file, see
+// replaceChartTexts.
+func chartTextPlaceholders(data []byte) (keys []string) {
+	open, closing := regexp.QuoteMeta(string(OpenDelimiter)), regexp.QuoteMeta(string(CloseDelimiter))
+	placeholderRegex := regexp.MustCompile(open + `[^` + open + closing + `]+` + closing)
+	for _, match := range chartCacheTextRegex.FindAllSubmatch(data, -1) {
+		for _, placeholder := range placeholderRegex.FindAllString(html.UnescapeString(string(match[2])), -1) {
+			keys = append(keys, RemovePlaceholderDelimiter(placeholder))
+		}
+	}
+	return keys
+}
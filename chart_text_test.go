@@ -0,0 +1,64 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// testChart returns a chart part with a title, an axis title and a series with the given texts.
+func testChart(title, axisTitle, series string) string {
+	rich := func(runs string) string {
+		return `<c:title><c:tx><c:rich><a:bodyPr/><a:p>` + runs + `</a:p></c:rich></c:tx></c:title>`
+	}
+	return `<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">` +
+		`<c:chart>` + rich(title) + `<c:plotArea><c:barChart><c:ser><c:idx val="0"/><c:order val="0"/>` +
+		`<c:tx><c:strRef><c:f>Sheet1!$B$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>` + series + `</c:v></c:pt></c:strCache></c:strRef></c:tx>` +
+		`<c:val><c:numRef><c:f>Sheet1!$B$2</c:f><c:numCache><c:ptCount val="1"/><c:pt idx="0"><c:v>42</c:v></c:pt></c:numCache></c:numRef></c:val>` +
+		`</c:ser></c:barChart><c:valAx><c:axId val="1"/>` + rich(axisTitle) + `</c:valAx></c:plotArea></c:chart></c:chartSpace>`
+}
+
+func TestDocument_ReplaceAll_Chart(t *testing.T) {
+	chart := testChart(`<a:r><a:rPr b="1"/><a:t>{report</a:t></a:r><a:r><a:t>}</a:t></a:r><a:r><a:t> {year}</a:t></a:r>`,
+		`<a:r><a:t>Revenue in {unit}</a:t></a:r>`, `{region} &amp; more`)
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{report}</w:t></w:r></w:p>`, "word/charts/chart1.xml", chart))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{"report": "Sales & Costs", "year": 2025, "unit": "EUR", "region": "EMEA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	written := readTestPart(t, buf.Bytes(), "word/charts/chart1.xml")
+	for _, expected := range []string{
+		`<a:t>Sales &amp; Costs</a:t>`,
+		`<a:t> 2025</a:t>`,
+		`<a:t>Revenue in EUR</a:t>`,
+		`<c:v>EMEA &amp; more</c:v>`,
+		`<c:v>42</c:v>`,
+	} {
+		if !strings.Contains(written, expected) {
+			t.Errorf("expected %s in chart: %s", expected, written)
+		}
+	}
+}
+
+func TestProcessTemplateDocx_Chart(t *testing.T) {
+	chart := testChart(`<a:r><a:t>{{.Re</a:t></a:r><a:r><a:t>port}} report</a:t></a:r>`, `<a:r><a:t>Revenue in {{.Unit}}</a:t></a:r>`, `{{.Region}}`)
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Report}}</w:t></w:r></w:p>`, "word/charts/chart1.xml", chart)
+	output, err := ProcessTemplateDocx(input, map[string]string{"Report": "Sales", "Unit": "EUR", "Region": "EMEA\nAPAC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := readTestPart(t, output, "word/charts/chart1.xml")
+	for _, expected := range []string{`<a:t>Sales</a:t></a:r><a:r><a:t> report</a:t>`, `<a:t>Revenue in EUR</a:t>`, `<c:v>EMEA APAC</c:v>`} {
+		if !strings.Contains(written, expected) {
+			t.Errorf("expected %s in chart: %s", expected, written)
+		}
+	}
+}
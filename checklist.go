@@ -0,0 +1,117 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+const (
+	// CheckedGlyph is the default glyph of checked items (☒).
+	CheckedGlyph = "\u2612"
+	// UncheckedGlyph is the default glyph of unchecked items (☐).
+	UncheckedGlyph = "\u2610"
+
+	// checkboxFont is the font used by Word for the glyphs of checkbox content controls.
+	checkboxFont = "MS Gothic"
+)
+
+// CheckboxStyle defines how the checkboxes of a Checklist are rendered.
+type CheckboxStyle int
+
+const (
+	// CheckboxGlyphs renders the checkboxes as static glyphs. This is the default.
+	CheckboxGlyphs CheckboxStyle = iota
+	// CheckboxControls renders the checkboxes as checkbox content controls which can be toggled in Word 2010 and later.
+	CheckboxControls
+)
+
+// ChecklistItem is a single item of a Checklist.
+type ChecklistItem struct {
+	Label   string
+	Checked bool
+}
+
+// Checklist is a replacement value which renders a list of items with a checkbox in front of each item,
+// one item per line. It is commonly used for compliance forms, e.g. to list which requirements are met.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "requirements": docx.Checklist{Items: []docx.ChecklistItem{
+//	        {Label: "Identity verified", Checked: true},
+//	        {Label: "Contract signed"},
+//	    }, Style: docx.CheckboxControls},
+//	})
+type Checklist struct {
+	Items []ChecklistItem
+	Style CheckboxStyle
+	// CheckedGlyph and UncheckedGlyph replace the default glyphs (☒ and ☐) of checked and unchecked items.
+	CheckedGlyph, UncheckedGlyph string
+}
+
+// ChecklistFromMap returns a Checklist of the given items, ordered by label.
+func ChecklistFromMap(items map[string]bool) Checklist {
+	labels := make([]string, 0, len(items))
+	for label := range items {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	checklist := Checklist{Items: make([]ChecklistItem, 0, len(items))}
+	for _, label := range labels {
+		checklist.Items = append(checklist.Items, ChecklistItem{Label: label, Checked: items[label]})
+	}
+	return checklist
+}
+
+// inlineXml returns the runs of all items, separated by line breaks.
+func (c Checklist) inlineXml(ctx *valueContext) (string, error) {
+	checked, unchecked := c.CheckedGlyph, c.UncheckedGlyph
+	if checked == "" {
+		checked = CheckedGlyph
+	}
+	if unchecked == "" {
+		unchecked = UncheckedGlyph
+	}
+	glyphProperties := setRunProperty(ctx.runProperties, "rFonts",
+		fmt.Sprintf(`<w:rFonts w:ascii="%s" w:eastAsia="%s" w:hAnsi="%s" w:hint="eastAsia"/>`, checkboxFont, checkboxFont, checkboxFont))
+
+	var runs strings.Builder
+	for i, item := range c.Items {
+		if i > 0 {
+			fmt.Fprintf(&runs, `<w:r>%s<w:br/></w:r>`, ctx.runProperties)
+		}
+
+		glyph := unchecked
+		if item.Checked {
+			glyph = checked
+		}
+		glyphRun := fmt.Sprintf(`<w:r>%s<w:t>%s</w:t></w:r>`, glyphProperties, html.EscapeString(glyph))
+		if c.Style == CheckboxControls {
+			glyphRun = checkboxControlXml(ctx.doc.nextRevisionId(), item.Checked, checked, unchecked, glyphRun)
+		}
+		runs.WriteString(glyphRun)
+
+		label, err := ctx.valueXml(" " + item.Label)
+		if err != nil {
+			return "", err
+		}
+		runs.WriteString(label)
+	}
+	return runs.String(), nil
+}
+
+// checkboxControlXml returns a checkbox content control (w14:checkbox) with the given glyph run as content.
+func checkboxControlXml(id int, checked bool, checkedGlyph, uncheckedGlyph, glyphRun string) string {
+	value := 0
+	if checked {
+		value = 1
+	}
+	return fmt.Sprintf(`<w:sdt><w:sdtPr><w:id w:val="%d"/>`+
+		`<w14:checkbox xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"><w14:checked w14:val="%d"/>`+
+		`<w14:checkedState w14:val="%04X" w14:font="%s"/><w14:uncheckedState w14:val="%04X" w14:font="%s"/></w14:checkbox>`+
+		`</w:sdtPr><w:sdtContent>%s</w:sdtContent></w:sdt>`,
+		id, value, []rune(checkedGlyph)[0], checkboxFont, []rune(uncheckedGlyph)[0], checkboxFont, glyphRun)
+}
@@ -0,0 +1,49 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Checklist(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>{glyphs}</w:t></w:r></w:p><w:p><w:r><w:t>{controls}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controls := ChecklistFromMap(map[string]bool{"Signed": false, "Identity verified": true})
+	controls.Style = CheckboxControls
+	err = doc.ReplaceAll(PlaceholderMap{
+		"glyphs": Checklist{Items: []ChecklistItem{
+			{Label: "Terms & conditions", Checked: true},
+			{Label: "Newsletter"},
+		}},
+		"controls": controls,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	glyphProperties := `<w:rPr><w:rFonts w:ascii="MS Gothic" w:eastAsia="MS Gothic" w:hAnsi="MS Gothic" w:hint="eastAsia"/><w:sz w:val="20"/></w:rPr>`
+	for _, expected := range []string{
+		`<w:r>` + glyphProperties + `<w:t>` + CheckedGlyph + `</w:t></w:r><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve"> Terms &amp; conditions</w:t></w:r>` +
+			`<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:br/></w:r><w:r>` + glyphProperties + `<w:t>` + UncheckedGlyph + `</w:t></w:r>`,
+		// items of maps are ordered by label
+		`<w14:checked w14:val="1"/><w14:checkedState w14:val="2612" w14:font="MS Gothic"/><w14:uncheckedState w14:val="2610" w14:font="MS Gothic"/></w14:checkbox></w:sdtPr><w:sdtContent>`,
+		`<w:t xml:space="preserve"> Identity verified</w:t></w:r><w:r><w:br/></w:r><w:sdt>`,
+		`<w14:checked w14:val="0"/>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if n := strings.Count(document, "<w:sdt>"); n != 2 {
+		t.Errorf("expected two checkbox controls, have %d", n)
+	}
+}
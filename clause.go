@@ -0,0 +1,117 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ClauseRefFormat is the format used by the 'clauseRef' template function, the verb is replaced with the clause number.
+	ClauseRefFormat = "Clause %s"
+
+	// clauseRefMarkerRegex matches the markers which are written by 'clauseRef' and 'clauseNum' during the first pass.
+	// The markers use characters from the unicode private use area which are never part of a regular document.
+	clauseRefMarkerRegex = regexp.MustCompile("(ref|num):([^]*)")
+)
+
+// clauseNumbering keeps track of numbered clauses during a single template rendering.
+//
+// Clauses are numbered in the order in which they are rendered using the template functions
+//
+//	{{clause "id"}}     -> "7"   (top level clause)
+//	{{clause "id" 2}}   -> "7.2" (clause on the given level, numbered below the last clause of the level above)
+//
+// and can be referenced from anywhere in the document, even before they are numbered:
+//
+//	{{clauseRef "id"}}  -> "Clause 7.2"
+//	{{clauseNum "id"}}  -> "7.2"
+//
+// References are resolved in a second pass after the whole document was rendered and the numbering is fixed.
+type clauseNumbering struct {
+	counters []int
+	numbers  map[string]string
+}
+
+// newClauseNumbering returns an empty clauseNumbering.
+func newClauseNumbering() *clauseNumbering {
+	return &clauseNumbering{
+		numbers: make(map[string]string),
+	}
+}
+
+// funcs returns the template functions which operate on this clauseNumbering.
+func (c *clauseNumbering) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"clause":    c.clause,
+		"clauseRef": c.clauseRef,
+		"clauseNum": c.clauseNum,
+	}
+}
+
+// clause assigns the next number on the given level (1 if omitted) to the clause id and returns the number.
+func (c *clauseNumbering) clause(id string, level ...int) (string, error) {
+	if _, exists := c.numbers[id]; exists {
+		return "", fmt.Errorf("clause %q is numbered more than once", id)
+	}
+
+	depth := 1
+	if len(level) > 0 {
+		depth = level[0]
+	}
+	if depth < 1 {
+		return "", fmt.Errorf("invalid level %d for clause %q", depth, id)
+	}
+
+	for len(c.counters) < depth {
+		c.counters = append(c.counters, 0)
+	}
+	c.counters = c.counters[:depth]
+	c.counters[depth-1]++
+
+	parts := make([]string, depth)
+	for i, counter := range c.counters {
+		parts[i] = strconv.Itoa(counter)
+	}
+	number := strings.Join(parts, ".")
+	c.numbers[id] = number
+
+	return number, nil
+}
+
+// clauseRef returns a marker for a reference to the clause id which is resolved using ClauseRefFormat.
+func (c *clauseNumbering) clauseRef(id string) string {
+	return "ref:" + id + ""
+}
+
+// clauseNum returns a marker for the bare number of the clause id.
+func (c *clauseNumbering) clauseNum(id string) string {
+	return "num:" + id + ""
+}
+
+// resolve replaces all clause markers inside the given data with their final numbers.
+// An error is returned if a referenced clause was never numbered.
+func (c *clauseNumbering) resolve(data []byte) ([]byte, error) {
+	var err error
+	resolved := clauseRefMarkerRegex.ReplaceAllFunc(data, func(marker []byte) []byte {
+		match := clauseRefMarkerRegex.FindSubmatch(marker)
+		id := html.UnescapeString(string(match[2]))
+		number, exists := c.numbers[id]
+		if !exists {
+			if err == nil {
+				err = fmt.Errorf("reference to unknown clause %q", id)
+			}
+			return nil
+		}
+		if string(match[1]) == "ref" {
+			return []byte(fmt.Sprintf(ClauseRefFormat, number))
+		}
+		return []byte(number)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
@@ -0,0 +1,156 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ErrClauseNotFound is returned if no variant of a clause matches the criteria of a rendering.
+var ErrClauseNotFound = errors.New("clause not found")
+
+// ClauseCriteria are the attributes of a rendering which select blocks and clauses, e.g.
+// {"jurisdiction": "DE", "product": "pro", "language": "de"}.
+type ClauseCriteria map[string]string
+
+// Clause is a variant of a shared clause, e.g. the liability clause for German contracts.
+type Clause struct {
+	// Name identifies the clause, all variants of a clause share the name, e.g. 'liability'.
+	Name string
+	// Attributes restrict the criteria for which the variant is selected: every attribute lists the accepted values,
+	// e.g. {"jurisdiction": {"DE", "AT"}}. A variant without attributes is the default of the clause.
+	Attributes map[string][]string
+	// Fragment and Version identify the DOCX document of the variant inside the FragmentProvider of the library.
+	Fragment, Version string
+}
+
+// matches returns true if the criteria have one of the accepted values for every attribute of the variant.
+func (c Clause) matches(criteria ClauseCriteria) bool {
+	for attribute, values := range c.Attributes {
+		value, exists := criteria[attribute]
+		if !exists || !slices.Contains(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClauseLibrary selects the variants of shared clauses by the attributes of a rendering, e.g. the jurisdiction,
+// product and language of a contract, and loads them as fragments from its provider.
+//
+// If several variants of a clause match, the most specific one (with the most attributes) is selected, variants
+// with the same number of attributes in the order of Clauses. So a variant without attributes is used for all
+// criteria which no other variant matches.
+//
+// Example:
+//
+//	library := &docx.ClauseLibrary{
+//	    Provider: docx.NewFSFragmentProvider(os.DirFS("/srv/clauses")),
+//	    Clauses: []docx.Clause{
+//	        {Name: "liability", Fragment: "liability/default"},
+//	        {Name: "liability", Fragment: "liability/dach", Attributes: map[string][]string{"jurisdiction": {"DE", "AT", "CH"}}},
+//	    },
+//	}
+type ClauseLibrary struct {
+	Provider FragmentProvider
+	Clauses  []Clause
+}
+
+// Find returns the variant of the clause with the given name which is selected by the criteria.
+// ErrClauseNotFound is returned if no variant matches.
+func (l *ClauseLibrary) Find(name string, criteria ClauseCriteria) (Clause, error) {
+	found := -1
+	for i, clause := range l.Clauses {
+		if clause.Name != name || !clause.matches(criteria) {
+			continue
+		}
+		if found < 0 || len(clause.Attributes) > len(l.Clauses[found].Attributes) {
+			found = i
+		}
+	}
+	if found < 0 {
+		return Clause{}, fmt.Errorf("%w: no variant of %s matches %s", ErrClauseNotFound, name, criteria)
+	}
+	return l.Clauses[found], nil
+}
+
+// Load returns the fragment of the variant of the clause with the given name which is selected by the criteria,
+// see Find. The fragment can be used as replacement value.
+func (l *ClauseLibrary) Load(ctx context.Context, name string, criteria ClauseCriteria) (Fragment, error) {
+	clause, err := l.Find(name, criteria)
+	if err != nil {
+		return Fragment{}, err
+	}
+	return LoadFragment(ctx, l.Provider, clause.Fragment, clause.Version)
+}
+
+// Funcs returns the template functions which select blocks and clauses for the criteria of a rendering:
+//
+//	{{if matchClause "jurisdiction=DE,AT" "product=pro"}}  -> true if the criteria have one of the listed values for
+//	                                                          every attribute; alone in a paragraph, the paragraphs up
+//	                                                          to {{end}} are removed unless the block matches
+//	{{showBlockIf (matchClause "language=de")}}            -> hides the block instead of removing it, see endHide
+//	{{if hasClause "liability"}}                           -> true if a variant of the clause matches the criteria
+//	{{insertClause "liability"}}                           -> inserts the selected variant of the clause
+//
+// Every fragment is loaded once per set of functions, ctx is used to load them from the provider.
+// Template actions inside the inserted fragments are not rendered.
+//
+// Example:
+//
+//	funcs := library.Funcs(ctx, docx.ClauseCriteria{"jurisdiction": "DE", "product": "pro"})
+//	outputBytes, err := docx.ProcessTemplateDocxWithFuncs(templateBytes, data, funcs)
+func (l *ClauseLibrary) Funcs(ctx context.Context, criteria ClauseCriteria) template.FuncMap {
+	loaded := make(map[string]Fragment)
+	return template.FuncMap{
+		"matchClause": criteria.match,
+		"hasClause": func(name string) bool {
+			_, err := l.Find(name, criteria)
+			return err == nil
+		},
+		"insertClause": func(name string) (Fragment, error) {
+			if fragment, exists := loaded[name]; exists {
+				return fragment, nil
+			}
+			fragment, err := l.Load(ctx, name, criteria)
+			if err != nil {
+				return Fragment{}, err
+			}
+			loaded[name] = fragment
+			return fragment, nil
+		},
+	}
+}
+
+// ProcessClauseTemplate renders the template like ProcessTemplateDocx with the clause functions of the library for
+// the given criteria, see ClauseLibrary.Funcs.
+//
+// Example:
+//
+//	outputBytes, err := docx.ProcessClauseTemplate(ctx, templateBytes, data, library, docx.ClauseCriteria{
+//	    "jurisdiction": "DE",
+//	    "language":     "de",
+//	})
+func ProcessClauseTemplate(ctx context.Context, input []byte, data interface{}, library *ClauseLibrary, criteria ClauseCriteria) ([]byte, error) {
+	return ProcessTemplateDocxWithFuncs(input, data, library.Funcs(ctx, criteria))
+}
+
+// match returns true if the criteria match all of the given tags. A tag is an attribute and its accepted values
+// separated by commas, e.g. 'jurisdiction=DE,AT'.
+func (c ClauseCriteria) match(tags ...string) (bool, error) {
+	for _, tag := range tags {
+		attribute, values, found := strings.Cut(tag, "=")
+		attribute = strings.TrimSpace(attribute)
+		if !found || attribute == "" {
+			return false, fmt.Errorf("invalid clause tag %q, expected attribute=value,...", tag)
+		}
+		value, exists := c[attribute]
+		if !exists || !slices.ContainsFunc(strings.Split(values, ","), func(v string) bool { return strings.TrimSpace(v) == value }) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
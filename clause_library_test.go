@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testClauseLibrary(t *testing.T) *ClauseLibrary {
+	return &ClauseLibrary{
+		Provider: NewFSFragmentProvider(fstest.MapFS{
+			"liability/default.docx":  {Data: buildTestDocx(t, `<w:p><w:r><w:t>Default liability</w:t></w:r></w:p>`)},
+			"liability/dach/1.0.docx": {Data: buildTestDocx(t, `<w:p><w:r><w:t>DACH liability</w:t></w:r></w:p>`)},
+			"liability/dach/2.0.docx": {Data: buildTestDocx(t, `<w:p><w:r><w:t>DACH liability 2</w:t></w:r></w:p>`)},
+			"warranty/pro.docx":       {Data: buildTestDocx(t, `<w:p><w:r><w:t>Pro warranty</w:t></w:r></w:p>`)},
+		}),
+		Clauses: []Clause{
+			{Name: "liability", Fragment: "liability/default"},
+			{Name: "liability", Fragment: "liability/dach", Version: "1.0", Attributes: map[string][]string{"jurisdiction": {"DE", "AT", "CH"}}},
+			{Name: "warranty", Fragment: "warranty/pro", Attributes: map[string][]string{"product": {"pro"}}},
+		},
+	}
+}
+
+func TestClauseLibrary_Find(t *testing.T) {
+	library := testClauseLibrary(t)
+	tests := []struct {
+		name     string
+		criteria ClauseCriteria
+		expected string
+	}{
+		{"liability", ClauseCriteria{"jurisdiction": "AT"}, "liability/dach"},
+		{"liability", ClauseCriteria{"jurisdiction": "US"}, "liability/default"},
+		{"liability", nil, "liability/default"},
+		{"warranty", ClauseCriteria{"product": "pro", "jurisdiction": "US"}, "warranty/pro"},
+		{"warranty", ClauseCriteria{"product": "basic"}, ""},
+		{"unknown", nil, ""},
+	}
+	for _, test := range tests {
+		clause, err := library.Find(test.name, test.criteria)
+		if test.expected == "" {
+			if !errors.Is(err, ErrClauseNotFound) {
+				t.Errorf("%s %v: expected ErrClauseNotFound, got %v", test.name, test.criteria, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %v: %v", test.name, test.criteria, err)
+		} else if clause.Fragment != test.expected {
+			t.Errorf("%s %v: expected %s, got %s", test.name, test.criteria, test.expected, clause.Fragment)
+		}
+	}
+}
+
+func TestProcessClauseTemplate(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>{{if matchClause "jurisdiction=DE,AT" "product=pro"}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>German pro terms</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{if matchClause "jurisdiction=US"}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>US terms</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{insertClause "liability"}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{if hasClause "warranty"}}{{insertClause "warranty"}}{{else}}No warranty{{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{showBlockIf (matchClause "language=en")}}English notice</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{endHide}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessClauseTemplate(context.Background(), input, nil, testClauseLibrary(t),
+		ClauseCriteria{"jurisdiction": "DE", "product": "pro", "language": "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{"German pro terms", "DACH liability", "Pro warranty", `<w:vanish/></w:rPr><w:t xml:space="preserve">English notice`} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	for _, unexpected := range []string{"US terms", "Default liability", "DACH liability 2", "No warranty"} {
+		if strings.Contains(document, unexpected) {
+			t.Errorf("unexpected %s in document: %s", unexpected, document)
+		}
+	}
+
+	_, err = ProcessClauseTemplate(context.Background(), input, nil, testClauseLibrary(t), ClauseCriteria{"product": "pro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input = buildTestDocx(t, `<w:p><w:r><w:t>{{insertClause "warranty"}}</w:t></w:r></w:p>`)
+	if _, err = ProcessClauseTemplate(context.Background(), input, nil, testClauseLibrary(t), nil); !errors.Is(err, ErrClauseNotFound) {
+		t.Errorf("expected ErrClauseNotFound, got %v", err)
+	}
+	input = buildTestDocx(t, `<w:p><w:r><w:t>{{if matchClause "jurisdiction"}}x{{end}}</w:t></w:r></w:p>`)
+	if _, err = ProcessClauseTemplate(context.Background(), input, nil, testClauseLibrary(t), nil); err == nil || !strings.Contains(err.Error(), "invalid clause tag") {
+		t.Errorf("expected an invalid tag error, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClauseNumbering(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>See {{clauseRef "limitation"}}.</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{clause "scope"}} Scope</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{clause "liability"}} Liability</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{clause "general" 2}} General</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{clause "limitation" 2}} Limitation, see {{clauseNum "scope"}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{"See Clause 2.2.", "1 Scope", "2 Liability", "2.1 General", "2.2 Limitation, see 1"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %q in document", expected)
+		}
+	}
+}
+
+func TestClauseNumbering_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown reference": `<w:p><w:r><w:t>{{clauseRef "missing"}}</w:t></w:r></w:p>`,
+		"duplicate clause":  `<w:p><w:r><w:t>{{clause "a"}}{{clause "a"}}</w:t></w:r></w:p>`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ProcessTemplateDocx(buildTestDocx(t, body), nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// paragraphContentRegex matches elements which make a paragraph without text visible or meaningful,
+	// e.g. pictures, breaks, fields or bookmarks which are referenced elsewhere.
+	paragraphContentRegex = regexp.MustCompile(`<w:(?:drawing|pict|object|br|cr|tab|ptab|sym|fldChar|fldSimple|instrText|` +
+		`footnoteReference|endnoteReference|commentReference|bookmarkStart|sdt|oMath|oMathPara)[\s/>]`)
+	// paragraphPropertiesBlockRegex matches all paragraph properties, e.g. inside the cells of a table row.
+	paragraphPropertiesBlockRegex = regexp.MustCompile(`(?s)<w:pPr>.*?</w:pPr>`)
+	// verticalMergeRegex matches the vertical merge of table cells.
+	verticalMergeRegex = regexp.MustCompile(`<w:vMerge[\s/>]`)
+)
+
+// CleanupOptions select the cleanups which remove the blank space left behind by removed content,
+// e.g. by conditional blocks and loops which rendered nothing. All cleanups are disabled by default
+// since some layouts rely on empty paragraphs for spacing.
+type CleanupOptions struct {
+	// CollapseEmptyParagraphs reduces every sequence of consecutive empty paragraphs to a single empty paragraph.
+	CollapseEmptyParagraphs bool
+	// TrimSectionEnds removes the empty paragraphs in front of section breaks and at the end of the document body.
+	TrimSectionEnds bool
+	// RemoveEmptyRows removes table rows without text. Tables without rows are removed as well.
+	// Rows containing vertically merged cells are kept.
+	RemoveEmptyRows bool
+}
+
+// Cleanup removes empty paragraphs and table rows from the body, headers and footers according to the options.
+// Paragraphs and rows are empty if they have no text except whitespace and contain no pictures, breaks, fields, bookmarks or
+// content controls. Paragraphs which end a section are kept.
+func (d *Document) Cleanup(options CleanupOptions) error {
+	if options == (CleanupOptions{}) {
+		return nil
+	}
+	for _, name := range d.xmlFiles() {
+		data := string(d.files[name])
+		var start, end int
+		if name == DocumentXml {
+			match := BodyContentRegex.FindStringSubmatchIndex(data)
+			if match == nil {
+				return fmt.Errorf("invalid document, %s has no body", name)
+			}
+			start, end = match[2], match[3]
+		} else {
+			root := childElements(data)
+			if len(root) == 0 {
+				continue
+			}
+			start = root[0].start + strings.IndexByte(data[root[0].start:], '>') + 1
+			end = strings.LastIndex(data[:root[0].end], "</")
+			if end < start {
+				continue // empty, self-closing root element
+			}
+		}
+
+		content := cleanupContent(data[start:end], options, name == DocumentXml)
+		if content == data[start:end] {
+			continue
+		}
+		if err := d.SetFile(name, []byte(data[:start]+content+data[end:])); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupContent cleans the paragraphs and tables of a body, header, footer or table cell.
+// Section ends are only trimmed if sectionEnds is true.
+func cleanupContent(content string, options CleanupOptions, sectionEnds bool) string {
+	children := childElements(content)
+	if len(children) == 0 {
+		return content
+	}
+	elements := make([]string, len(children))
+	keep := make([]bool, len(children))
+	for i, child := range children {
+		elements[i], keep[i] = content[child.start:child.end], true
+		if child.name == "tbl" {
+			elements[i] = cleanupTable(elements[i], options)
+			keep[i] = elements[i] != ""
+		}
+	}
+	empty := func(i int) bool {
+		return children[i].name == "p" && isEmptyParagraph(elements[i])
+	}
+
+	if options.CollapseEmptyParagraphs {
+		for i := 1; i < len(children); i++ {
+			if empty(i) && empty(i-1) {
+				keep[i] = false
+			}
+		}
+	}
+	if options.TrimSectionEnds && sectionEnds {
+		for i, child := range children {
+			endsSection := child.name == "sectPr" || child.name == "p" && len(sectionProperties([]byte(elements[i]))) > 0
+			if !endsSection {
+				continue
+			}
+			// the first paragraph and the paragraph behind a table are kept, Word requires them
+			for j := i - 1; j > 0; j-- {
+				if !keep[j] {
+					continue
+				}
+				if !empty(j) || children[j-1].name == "tbl" && keep[j-1] {
+					break
+				}
+				keep[j] = false
+			}
+		}
+	}
+
+	var cleaned strings.Builder
+	cleaned.WriteString(content[:children[0].start])
+	for i, child := range children {
+		if keep[i] {
+			cleaned.WriteString(elements[i])
+		}
+		next := len(content)
+		if i+1 < len(children) {
+			next = children[i+1].start
+		}
+		cleaned.WriteString(content[child.end:next])
+	}
+	return cleaned.String()
+}
+
+// cleanupTable cleans the rows and cells of the table and returns the cleaned table, or an empty string if the
+// table has no rows left.
+func cleanupTable(table string, options CleanupOptions) string {
+	start := strings.IndexByte(table, '>') + 1
+	end := strings.LastIndex(table, "</w:tbl>")
+	if start <= 0 || end < start {
+		return table
+	}
+	content := table[start:end]
+
+	var cleaned strings.Builder
+	rows, pos := 0, 0
+	for _, child := range childElements(content) {
+		if child.name != "tr" {
+			continue
+		}
+		row := content[child.start:child.end]
+		cleaned.WriteString(content[pos:child.start])
+		pos = child.end
+		if options.RemoveEmptyRows && isEmptyRow(row) {
+			continue
+		}
+		rows++
+		cleaned.WriteString(cleanupRow(row, options))
+	}
+	if rows == 0 && options.RemoveEmptyRows {
+		return ""
+	}
+	cleaned.WriteString(content[pos:])
+	return table[:start] + cleaned.String() + table[end:]
+}
+
+// cleanupRow cleans the content of all cells of the table row.
+func cleanupRow(row string, options CleanupOptions) string {
+	start := strings.IndexByte(row, '>') + 1
+	end := strings.LastIndex(row, "</w:tr>")
+	if start <= 0 || end < start {
+		return row
+	}
+	content := row[start:end]
+
+	var cleaned strings.Builder
+	pos := 0
+	for _, child := range childElements(content) {
+		if child.name != "tc" {
+			continue
+		}
+		cell := content[child.start:child.end]
+		cellStart := strings.IndexByte(cell, '>') + 1
+		cellEnd := strings.LastIndex(cell, "</w:tc>")
+		if cellStart <= 0 || cellEnd < cellStart {
+			continue
+		}
+		cleaned.WriteString(content[pos:child.start])
+		cleaned.WriteString(cell[:cellStart] + cleanupContent(cell[cellStart:cellEnd], options, false) + cell[cellEnd:])
+		pos = child.end
+	}
+	cleaned.WriteString(content[pos:])
+	return row[:start] + cleaned.String() + row[end:]
+}
+
+// isEmptyParagraph returns true if the paragraph has no text except whitespace, no other visible content and does not
+// end a section.
+func isEmptyParagraph(paragraph string) bool {
+	if len(sectionProperties([]byte(paragraph))) > 0 || strings.TrimSpace(elementText([]byte(paragraph))) != "" {
+		return false
+	}
+	properties := paragraphPropertiesRegex.FindString(paragraph)
+	return !paragraphContentRegex.MatchString(paragraph[len(properties):])
+}
+
+// isEmptyRow returns true if the table row has no text except whitespace and no other visible content.
+// Rows with vertically merged cells are never empty since removing them would break the merged cells.
+func isEmptyRow(row string) bool {
+	if strings.TrimSpace(elementText([]byte(row))) != "" || verticalMergeRegex.MatchString(row) {
+		return false
+	}
+	return !paragraphContentRegex.MatchString(paragraphPropertiesBlockRegex.ReplaceAllString(row, ""))
+}
@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Cleanup(t *testing.T) {
+	empty := `<w:p><w:pPr><w:tabs><w:tab w:val="left" w:pos="720"/></w:tabs></w:pPr><w:r><w:t></w:t></w:r></w:p>`
+	body := `<w:p><w:r><w:t>Title</w:t></w:r></w:p>` + empty + empty + empty +
+		`<w:p><w:r><w:drawing/></w:r></w:p>` + empty + `<w:p><w:bookmarkStart w:id="0" w:name="ref"/><w:bookmarkEnd w:id="0"/></w:p>` +
+		`<w:p><w:pPr><w:sectPr/></w:pPr></w:p>` +
+		`<w:tbl><w:tblPr/><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p>` + empty + empty + `</w:tc></w:tr>` +
+		`<w:tr><w:trPr/><w:tc><w:tcPr/>` + empty + `</w:tc></w:tr>` +
+		`<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr>` + empty + `</w:tc></w:tr></w:tbl>` +
+		empty + `<w:p><w:r><w:t>End</w:t></w:r></w:p>` + empty + empty +
+		`<w:tbl><w:tr><w:tc>` + empty + `</w:tc></w:tr></w:tbl>` + `<w:sectPr/>`
+
+	tests := []struct {
+		options  CleanupOptions
+		expected string
+	}{
+		{CleanupOptions{}, body},
+		{
+			CleanupOptions{CollapseEmptyParagraphs: true},
+			`<w:p><w:r><w:t>Title</w:t></w:r></w:p>` + empty + `<w:p><w:r><w:drawing/></w:r></w:p>` + empty +
+				`<w:p><w:bookmarkStart w:id="0" w:name="ref"/><w:bookmarkEnd w:id="0"/></w:p><w:p><w:pPr><w:sectPr/></w:pPr></w:p>` +
+				`<w:tbl><w:tblPr/><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p>` + empty + `</w:tc></w:tr>` +
+				`<w:tr><w:trPr/><w:tc><w:tcPr/>` + empty + `</w:tc></w:tr>` +
+				`<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr>` + empty + `</w:tc></w:tr></w:tbl>` +
+				empty + `<w:p><w:r><w:t>End</w:t></w:r></w:p>` + empty +
+				`<w:tbl><w:tr><w:tc>` + empty + `</w:tc></w:tr></w:tbl>` + `<w:sectPr/>`,
+		},
+		{
+			CleanupOptions{TrimSectionEnds: true, RemoveEmptyRows: true},
+			`<w:p><w:r><w:t>Title</w:t></w:r></w:p>` + empty + empty + empty +
+				`<w:p><w:r><w:drawing/></w:r></w:p>` + empty + `<w:p><w:bookmarkStart w:id="0" w:name="ref"/><w:bookmarkEnd w:id="0"/></w:p>` +
+				`<w:p><w:pPr><w:sectPr/></w:pPr></w:p>` +
+				`<w:tbl><w:tblPr/><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p>` + empty + empty + `</w:tc></w:tr>` +
+				`<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr>` + empty + `</w:tc></w:tr></w:tbl>` +
+				empty + `<w:p><w:r><w:t>End</w:t></w:r></w:p>` + `<w:sectPr/>`,
+		},
+	}
+	for _, tt := range tests {
+		doc, err := OpenBytes(buildTestDocx(t, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.Cleanup(tt.options); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if document := readTestPart(t, buf.Bytes(), DocumentXml); document != testDocumentOpen+tt.expected+testDocumentClose {
+			t.Errorf("%+v: expected %s, have %s", tt.options, tt.expected, document)
+		}
+	}
+}
+
+func TestPool_Render_Cleanup(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{if .Show}}Shown{{end}}</w:t></w:r></w:p><w:p/><w:p><w:r><w:t>End</w:t></w:r></w:p>`)
+	pool := NewPool(1, RenderOptions{})
+	output, err := pool.Render(context.Background(), input, map[string]bool{"Show": false}, &RenderOptions{
+		Cleanup: CleanupOptions{CollapseEmptyParagraphs: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); strings.Count(document, "<w:p>")+strings.Count(document, "<w:p/>") != 2 {
+		t.Errorf("expected the empty paragraphs to be collapsed: %s", document)
+	}
+}
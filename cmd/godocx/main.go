@@ -0,0 +1,230 @@
+// Command godocx fills DOCX templates with JSON or CSV data without writing Go.
+//
+// Usage:
+//
+//	godocx replace -template letter.docx -data values.json -out letter-filled.docx
+//	godocx template -template report.docx -data report.json -out report.docx
+//	godocx placeholders letter.docx
+//	godocx batch -template letter.docx -data customers.csv -dir out -name "letter-{{slug .name}}.docx"
+//
+// replace fills {placeholders} with the values of a JSON object or of the first CSV record, template renders
+// Go templates ({{ .Name }}) with any JSON data, placeholders lists the placeholders of a template and batch
+// renders one document per record of a JSON array or a CSV file. Data is read from stdin if -data is '-'.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/izetmolla/go-docx"
+)
+
+const usage = `usage: godocx <command> [flags]
+
+commands:
+  replace       replace {placeholders} with the values of a JSON object or the first CSV record
+  template      render a Go template ({{ .Name }}) with JSON data
+  placeholders  list the placeholders of a template
+  batch         render one document per record of a JSON array or a CSV file
+
+run 'godocx <command> -h' for the flags of a command`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "godocx:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run executes the command of the arguments, stdin is used for data read from '-'.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("godocx "+command, flag.ContinueOnError)
+	templatePath := flags.String("template", "", "path of the DOCX template")
+	dataPath := flags.String("data", "", "path of the JSON or CSV data, '-' for stdin")
+	format := flags.String("format", "", "format of the data, 'json' or 'csv', detected from the file extension if empty")
+	locale := flags.String("locale", "", "locale used to format numbers and dates, e.g. 'de-DE'")
+
+	switch command {
+	case "replace", "template":
+		out := flags.String("out", "", "path of the output document")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *templatePath == "" || *out == "" {
+			return errors.New("-template and -out are required")
+		}
+		data, err := readData(*dataPath, *format, stdin)
+		if err != nil {
+			return err
+		}
+		syntax := docx.SyntaxTemplate
+		if command == "replace" {
+			syntax = docx.SyntaxPlaceholders
+			if records, isList := data.([]interface{}); isList {
+				if len(records) == 0 {
+					return errors.New("the data has no records")
+				}
+				data = records[0]
+			}
+		}
+		output, err := docx.Render(docx.FromFile(*templatePath), data, docx.WithSyntax(syntax), docx.WithLocale(*locale))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*out, output, 0644)
+
+	case "placeholders":
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		path := *templatePath
+		if path == "" {
+			path = flags.Arg(0)
+		}
+		if path == "" {
+			return errors.New("the template is required")
+		}
+		return listPlaceholders(path, stdout)
+
+	case "batch":
+		dir := flags.String("dir", ".", "directory of the output documents")
+		name := flags.String("name", "document.docx", "name pattern of the output documents, a Go template of the record")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *templatePath == "" || *dataPath == "" {
+			return errors.New("-template and -data are required")
+		}
+		data, err := readData(*dataPath, *format, stdin)
+		if err != nil {
+			return err
+		}
+		records, isList := data.([]interface{})
+		if !isList {
+			return errors.New("batch data must be a JSON array or a CSV file")
+		}
+		return batch(*templatePath, records, *dir, *name, *locale, stdout)
+
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(stdout, usage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n%s", command, usage)
+}
+
+// readData reads JSON or CSV data. CSV files are read as a list of records, one map per line keyed by the header.
+// No data is read if the path is empty.
+func readData(path, format string, stdin io.Reader) (interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	var r io.Reader = stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch format {
+	case "json", "":
+		var data interface{}
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return nil, fmt.Errorf("invalid JSON data: %w", err)
+		}
+		return data, nil
+	case "csv":
+		lines, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV data: %w", err)
+		}
+		if len(lines) == 0 {
+			return nil, errors.New("the CSV data has no header")
+		}
+		records := make([]interface{}, 0, len(lines)-1)
+		for _, line := range lines[1:] {
+			record := make(map[string]interface{}, len(line))
+			for i, value := range line {
+				record[lines[0][i]] = value
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	}
+	return nil, fmt.Errorf("unsupported data format %q", format)
+}
+
+// listPlaceholders writes the keys of all placeholders of the template, one per line in alphabetical order.
+func listPlaceholders(path string, stdout io.Writer) error {
+	doc, err := docx.Open(path)
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+	placeholders, err := doc.GetPlaceHoldersList()
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]bool)
+	for _, placeholder := range placeholders {
+		keys[docx.RemovePlaceholderDelimiter(placeholder)] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		fmt.Fprintln(stdout, key)
+	}
+	return nil
+}
+
+// batch renders one document per record into the directory and writes the names of the documents.
+func batch(templatePath string, records []interface{}, dir, pattern, locale string, stdout io.Writer) error {
+	template, err := os.ReadFile(templatePath)
+	if err != nil {
+		return err
+	}
+	namer, err := docx.NewOutputNamer(pattern)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, record := range records {
+		name, err := namer.Name(record)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		output, err := docx.Render(docx.FromBytes(template), record, docx.WithLocale(locale))
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), output, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, filepath.Join(dir, name))
+	}
+	return nil
+}
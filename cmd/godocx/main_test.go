@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/izetmolla/go-docx"
+)
+
+const testTemplate = "../../examples/simple/template.docx"
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "customers.csv")
+	if err := os.WriteFile(csvPath, []byte("key,name\nJane Doe,jane\nJohn Doe,john\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{"placeholders", testTemplate}, nil, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), "\nkey\n") {
+		t.Errorf("expected the placeholder keys, have %q", stdout.String())
+	}
+
+	out := filepath.Join(dir, "replaced.docx")
+	stdin := strings.NewReader(`{"key": "Jane Doe"}`)
+	if err := run([]string{"replace", "-template", testTemplate, "-data", "-", "-out", out}, stdin, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	assertText(t, out, "Jane Doe")
+
+	stdout.Reset()
+	args := []string{"batch", "-template", testTemplate, "-data", csvPath, "-dir", dir, "-name", "letter-{{.name}}.docx"}
+	if err := run(args, nil, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(dir, "letter-jane.docx") + "\n" + filepath.Join(dir, "letter-john.docx") + "\n"; stdout.String() != expected {
+		t.Errorf("expected %q, have %q", expected, stdout.String())
+	}
+	assertText(t, filepath.Join(dir, "letter-john.docx"), "John Doe")
+
+	if err := run([]string{"batch", "-template", testTemplate, "-data", "-", "-format", "json"}, strings.NewReader(`{}`), &stdout); err == nil {
+		t.Error("expected error for batch data which is no list")
+	}
+	if err := run([]string{"unknown"}, nil, &stdout); err == nil {
+		t.Error("expected error for unknown command")
+	}
+}
+
+// assertText fails the test if the document at the path does not contain the text.
+func assertText(t *testing.T, path, text string) {
+	t.Helper()
+	doc, err := docx.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if content := string(doc.GetFile(docx.DocumentXml)); !strings.Contains(content, text) {
+		t.Errorf("expected %q in %s", text, path)
+	}
+}
@@ -0,0 +1,116 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	// codeStyle is the ID or name of the style used by code blocks if the document defines it.
+	codeStyle = "Code"
+	// codeFont is the monospace font of code blocks in documents without a code style.
+	codeFont = "Courier New"
+)
+
+// CodeBlock is a replacement value which inserts preformatted text, e.g. configuration files or log excerpts,
+// as monospace paragraphs. Whitespace is preserved, every line of the text becomes a paragraph and the lines are kept
+// together on one page if possible. Use Code to create one.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "config": docx.Code("server:\n  port: 8080\n  tls: true").WithLineNumbers().WithShading("F2F2F2"),
+//	})
+type CodeBlock struct {
+	Text string
+	// StyleName is the ID or name of the paragraph or character style of the code. If it is empty, the style 'Code'
+	// is used if the document defines it, otherwise the text is formatted with a monospace font.
+	StyleName string
+	// Shading is the background color of the paragraphs as hex RGB value, e.g. 'F2F2F2' or '#F2F2F2'.
+	// The paragraphs are not shaded if it is empty.
+	Shading string
+	// LineNumbers prefixes every line with its number.
+	LineNumbers bool
+}
+
+// Code returns a code block of the text, see CodeBlock.
+func Code(text string) CodeBlock {
+	return CodeBlock{Text: text}
+}
+
+// WithShading returns the code block with the background color, see CodeBlock.Shading.
+func (c CodeBlock) WithShading(color string) CodeBlock {
+	c.Shading = color
+	return c
+}
+
+// WithLineNumbers returns the code block with line numbers.
+func (c CodeBlock) WithLineNumbers() CodeBlock {
+	c.LineNumbers = true
+	return c
+}
+
+// String returns the text.
+func (c CodeBlock) String() string {
+	return c.Text
+}
+
+// inlineXml returns a run with the marker of the code block, the paragraphs are inserted by insertBlocks.
+func (c CodeBlock) inlineXml(ctx *valueContext) (string, error) {
+	if c.Shading != "" && !styleColorRegex.MatchString(c.Shading) {
+		return "", fmt.Errorf("invalid shading color %q of code block", c.Shading)
+	}
+	return ctx.blockMarkerRun(c), nil
+}
+
+// blockXml returns a paragraph for every line of the code.
+func (c CodeBlock) blockXml(ctx *valueContext) (string, error) {
+	var paragraphStyle, runProperties string
+	name := c.StyleName
+	if name == "" {
+		name = codeStyle
+	}
+	style, err := ctx.doc.styledStyle(name)
+	switch {
+	case err == nil && style.styleType == "paragraph":
+		paragraphStyle = `<w:pStyle w:val="` + html.EscapeString(style.id) + `"/>`
+	case err == nil:
+		runProperties = `<w:rPr><w:rStyle w:val="` + html.EscapeString(style.id) + `"/></w:rPr>`
+	case c.StyleName != "":
+		return "", err
+	default:
+		runProperties = fmt.Sprintf(`<w:rPr><w:rFonts w:ascii="%[1]s" w:hAnsi="%[1]s" w:cs="%[1]s"/></w:rPr>`, codeFont)
+	}
+	shading := ""
+	if match := styleColorRegex.FindStringSubmatch(c.Shading); match != nil {
+		shading = `<w:shd w:val="clear" w:color="auto" w:fill="` + strings.ToUpper(match[1]) + `"/>`
+	}
+
+	lines := strings.Split(strings.TrimSuffix(normalizeText(c.Text), "\n"), "\n")
+	width := len(strconv.Itoa(len(lines)))
+	lineCtx := *ctx
+	lineCtx.runProperties = runProperties
+	numberCtx := lineCtx
+	numberCtx.runProperties = setRunProperty(runProperties, "color", `<w:color w:val="808080"/>`)
+
+	var out strings.Builder
+	for i, line := range lines {
+		out.WriteString("<w:p><w:pPr>" + paragraphStyle)
+		if i < len(lines)-1 {
+			out.WriteString("<w:keepNext/>")
+		}
+		out.WriteString(shading + `<w:spacing w:before="0" w:after="0"/></w:pPr>`)
+		if c.LineNumbers {
+			out.WriteString(numberCtx.textRun(fmt.Sprintf("%*d  ", width, i+1)))
+		}
+		if line != "" {
+			text := strings.ReplaceAll(html.EscapeString(ctx.doc.textPolicy.apply(line)), "\t", `</w:t><w:tab/><w:t xml:space="preserve">`)
+			// tabs at the start or end of the line leave empty text elements behind
+			out.WriteString(strings.ReplaceAll(lineCtx.textRun(text), `<w:t xml:space="preserve"></w:t>`, ""))
+		}
+		out.WriteString("</w:p>")
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,60 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	codeStyles := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/></w:style></w:styles>`
+
+	tests := []struct {
+		name     string
+		parts    []string
+		value    CodeBlock
+		expected string
+	}{
+		{"monospace", nil, Code("a:\n\tb: <1>\n"),
+			`<w:p><w:pPr><w:keepNext/><w:spacing w:before="0" w:after="0"/></w:pPr>` +
+				`<w:r><w:rPr><w:rFonts w:ascii="Courier New" w:hAnsi="Courier New" w:cs="Courier New"/></w:rPr><w:t xml:space="preserve">a:</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:spacing w:before="0" w:after="0"/></w:pPr>` +
+				`<w:r><w:rPr><w:rFonts w:ascii="Courier New" w:hAnsi="Courier New" w:cs="Courier New"/></w:rPr><w:tab/><w:t xml:space="preserve">b: &lt;1&gt;</w:t></w:r></w:p>`},
+		{"code style", []string{StylesXml, codeStyles}, Code("x  = 1\n\ny = 2").WithShading("#f2f2f2").WithLineNumbers(),
+			`<w:p><w:pPr><w:pStyle w:val="Code"/><w:keepNext/><w:shd w:val="clear" w:color="auto" w:fill="F2F2F2"/><w:spacing w:before="0" w:after="0"/></w:pPr>` +
+				`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:t xml:space="preserve">1  </w:t></w:r><w:r><w:t xml:space="preserve">x  = 1</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:pStyle w:val="Code"/><w:keepNext/><w:shd w:val="clear" w:color="auto" w:fill="F2F2F2"/><w:spacing w:before="0" w:after="0"/></w:pPr>` +
+				`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:t xml:space="preserve">2  </w:t></w:r></w:p>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{config}</w:t></w:r></w:p>`, test.parts...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.ReplaceAll(PlaceholderMap{"config": test.value}); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := doc.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if document := readTestPart(t, buf.Bytes(), DocumentXml); !strings.Contains(document, test.expected) {
+				t.Errorf("expected %s in %s", test.expected, document)
+			}
+		})
+	}
+}
+
+func TestCode_Invalid(t *testing.T) {
+	for _, value := range []CodeBlock{Code("x").WithShading("grey"), {Text: "x", StyleName: "Missing"}} {
+		doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{config}</w:t></w:r></w:p>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.ReplaceAll(PlaceholderMap{"config": value}); err == nil {
+			t.Errorf("expected an error for %+v", value)
+		}
+	}
+}
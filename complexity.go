@@ -0,0 +1,186 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrComplexityExceeded is returned if a generated document exceeds one of the configured OutputLimits.
+	// The error is a *ComplexityError which names the exceeded limit.
+	ErrComplexityExceeded = errors.New("document complexity limit exceeded")
+
+	// paragraphOpenRegex matches the open and self-closing tags of paragraphs.
+	paragraphOpenRegex = regexp.MustCompile(`<w:p[\s/>]`)
+	// tableRowOpenRegex matches the open and self-closing tags of table rows.
+	tableRowOpenRegex = regexp.MustCompile(`<w:tr[\s/>]`)
+)
+
+// OutputLimits restrict the complexity of generated documents, protecting downstream converters (e.g. PDF
+// renderers) which fail on pathological documents such as a loop which rendered a million table rows.
+// A zero value for any of the limits disables that specific limit.
+type OutputLimits struct {
+	// MaxParagraphs is the maximum amount of paragraphs of the body, headers, footers and notes together,
+	// including the paragraphs inside tables.
+	MaxParagraphs int
+	// MaxTableRows is the maximum amount of table rows of the body, headers, footers and notes together.
+	MaxTableRows int
+	// MaxRelationships is the maximum amount of relationships of all parts together.
+	MaxRelationships int
+	// MaxPartSize is the maximum size of a single part in bytes, e.g. of the main document or an image.
+	MaxPartSize int64
+}
+
+// ComplexityError is returned if a document exceeds one of its OutputLimits.
+type ComplexityError struct {
+	// Limit is the exceeded limit: 'paragraphs', 'table rows', 'relationships' or 'part size'.
+	Limit string
+	// Part is the part which exceeds the maximum part size, empty for all other limits.
+	Part  string
+	Value int64
+	Max   int64
+}
+
+// Error describes the exceeded limit.
+func (e *ComplexityError) Error() string {
+	if e.Part != "" {
+		return fmt.Sprintf("%s: %s of %s is %d, maximum is %d", ErrComplexityExceeded, e.Limit, e.Part, e.Value, e.Max)
+	}
+	return fmt.Sprintf("%s: %d %s, maximum is %d", ErrComplexityExceeded, e.Value, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrComplexityExceeded.
+func (e *ComplexityError) Unwrap() error {
+	return ErrComplexityExceeded
+}
+
+// CheckComplexity checks the DOCX document against the limits and returns a *ComplexityError for the first
+// exceeded limit. Nothing is checked if all limits are zero.
+//
+// Example:
+//
+//	err := docx.CheckComplexity(outputBytes, docx.OutputLimits{MaxParagraphs: 50000, MaxTableRows: 10000})
+//	var complexityErr *docx.ComplexityError
+//	if errors.As(err, &complexityErr) {
+//	    log.Printf("document too complex: %s", complexityErr.Limit)
+//	}
+func CheckComplexity(input []byte, limits OutputLimits) error {
+	if limits == (OutputLimits{}) {
+		return nil
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(input), int64(len(input)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	var counter complexityCounter
+	for _, file := range zipReader.File {
+		if limits.MaxPartSize > 0 && file.UncompressedSize64 > uint64(limits.MaxPartSize) {
+			return &ComplexityError{Limit: "part size", Part: file.Name, Value: int64(file.UncompressedSize64), Max: limits.MaxPartSize}
+		}
+		if !isXMLPart(file.Name) {
+			continue
+		}
+		data, err := currentParseLimits().readZipFile(file)
+		if err != nil {
+			return err
+		}
+		counter.add(file.Name, data)
+	}
+	return counter.check(limits)
+}
+
+// SetOutputLimits sets the limits which are checked when the document is written. Write, WriteToFile and
+// WriteInPlace return a *ComplexityError instead of writing a document which exceeds the limits.
+//
+// Example:
+//
+//	doc.SetOutputLimits(docx.OutputLimits{MaxParagraphs: 50000, MaxPartSize: 64 << 20})
+func (d *Document) SetOutputLimits(limits OutputLimits) {
+	d.outputLimits = limits
+}
+
+// checkComplexity checks the current content of the document against its output limits.
+func (d *Document) checkComplexity() error {
+	limits := d.outputLimits
+	if limits == (OutputLimits{}) {
+		return nil
+	}
+
+	var counter complexityCounter
+	check := func(name string, size int64) error {
+		if limits.MaxPartSize > 0 && size > limits.MaxPartSize {
+			return &ComplexityError{Limit: "part size", Part: name, Value: size, Max: limits.MaxPartSize}
+		}
+		if !isXMLPart(name) || limits.MaxParagraphs == 0 && limits.MaxTableRows == 0 && limits.MaxRelationships == 0 {
+			return nil
+		}
+		data, _, err := d.part(name)
+		if err != nil {
+			return err
+		}
+		counter.add(name, data)
+		return nil
+	}
+
+	for _, file := range d.zipFile.File {
+		size := int64(file.UncompressedSize64)
+		if data, exists := d.files[file.Name]; exists {
+			size = int64(len(data))
+		} else if data, exists := d.parts[file.Name]; exists {
+			size = int64(len(data))
+		}
+		if err := check(file.Name, size); err != nil {
+			return err
+		}
+	}
+	for _, name := range d.newParts {
+		var size int64
+		if media, stored := d.storedMedia[name]; stored {
+			size = int64(len(media.data))
+		} else if data, exists := d.files[name]; exists {
+			size = int64(len(data))
+		} else {
+			size = int64(len(d.parts[name]))
+		}
+		if err := check(name, size); err != nil {
+			return err
+		}
+	}
+	return counter.check(limits)
+}
+
+// complexityCounter counts the elements of the parts of a document which are restricted by OutputLimits.
+type complexityCounter struct {
+	paragraphs, rows, relationships int
+}
+
+// add counts the elements of the given XML part.
+func (c *complexityCounter) add(name string, data []byte) {
+	if strings.HasSuffix(name, ".rels") {
+		c.relationships += len(RelationshipRegex.FindAllIndex(data, -1))
+		return
+	}
+	c.paragraphs += len(paragraphOpenRegex.FindAllIndex(data, -1))
+	c.rows += len(tableRowOpenRegex.FindAllIndex(data, -1))
+}
+
+// check returns a *ComplexityError if the counted elements exceed the limits.
+func (c *complexityCounter) check(limits OutputLimits) error {
+	for _, limit := range []struct {
+		name       string
+		value, max int
+	}{
+		{"paragraphs", c.paragraphs, limits.MaxParagraphs},
+		{"table rows", c.rows, limits.MaxTableRows},
+		{"relationships", c.relationships, limits.MaxRelationships},
+	} {
+		if limit.max > 0 && limit.value > limit.max {
+			return &ComplexityError{Limit: limit.name, Value: int64(limit.value), Max: int64(limit.max)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_SetOutputLimits(t *testing.T) {
+	table := `<w:tbl><w:tr><w:tc><w:p/></w:tc></w:tr><w:tr><w:tc><w:p/></w:tc></w:tr></w:tbl>`
+	input := buildTestDocx(t, `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{rows}</w:t></w:r></w:p>`+table+`<w:p/>`)
+
+	for _, tc := range []struct {
+		name   string
+		limits OutputLimits
+		limit  string
+	}{
+		{"unlimited", OutputLimits{}, ""},
+		{"within limits", OutputLimits{MaxParagraphs: 4, MaxTableRows: 2, MaxRelationships: 1, MaxPartSize: 1 << 20}, ""},
+		{"paragraphs", OutputLimits{MaxParagraphs: 3}, "paragraphs"},
+		{"table rows", OutputLimits{MaxTableRows: 1}, "table rows"},
+		{"relationships", OutputLimits{MaxRelationships: 1, MaxParagraphs: 100}, ""},
+		{"part size", OutputLimits{MaxPartSize: 1000}, "part size"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := OpenBytes(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.ReplaceAll(PlaceholderMap{"rows": strings.Repeat("row ", 500)}); err != nil {
+				t.Fatal(err)
+			}
+			doc.SetOutputLimits(tc.limits)
+
+			var buf bytes.Buffer
+			err = doc.Write(&buf)
+			var complexityErr *ComplexityError
+			switch {
+			case tc.limit == "" && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tc.limit != "" && !errors.As(err, &complexityErr):
+				t.Errorf("expected complexity error, have %v", err)
+			case tc.limit != "" && complexityErr.Limit != tc.limit:
+				t.Errorf("expected limit %s, have %s", tc.limit, complexityErr.Limit)
+			case tc.limit != "" && buf.Len() > 0:
+				t.Errorf("expected nothing to be written")
+			}
+			if tc.limit == "part size" && complexityErr != nil && complexityErr.Part != DocumentXml {
+				t.Errorf("expected %s to exceed the part size, have %s", DocumentXml, complexityErr.Part)
+			}
+		})
+	}
+}
+
+func TestCheckComplexity(t *testing.T) {
+	input := buildTestDocx(t, `<w:p/><w:p/>`,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeHyperlink+`" Target="https://example.com" TargetMode="External"/>`+
+			`<Relationship Id="rId2" Type="`+RelationshipTypeHyperlink+`" Target="https://example.org" TargetMode="External"/></Relationships>`)
+
+	// the package relationship and both hyperlinks
+	err := CheckComplexity(input, OutputLimits{MaxRelationships: 2})
+	if !errors.Is(err, ErrComplexityExceeded) || !strings.Contains(err.Error(), "3 relationships, maximum is 2") {
+		t.Errorf("expected relationships to be exceeded, have %v", err)
+	}
+	if err := CheckComplexity(input, OutputLimits{MaxRelationships: 3, MaxParagraphs: 2}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	pool := NewPool(1, RenderOptions{OutputLimits: OutputLimits{MaxParagraphs: 2}})
+	loop := buildTestDocx(t, `<w:p><w:r><w:t>{{range .Items}}</w:t></w:r></w:p><w:p><w:r><w:t>{{.}}</w:t></w:r></w:p><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`)
+	if _, err := pool.Render(context.Background(), loop, map[string]interface{}{"Items": []int{1, 2}}, nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := pool.Render(context.Background(), loop, map[string]interface{}{"Items": []int{1, 2, 3}}, nil); !errors.Is(err, ErrComplexityExceeded) {
+		t.Errorf("expected paragraphs to be exceeded, have %v", err)
+	}
+}
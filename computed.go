@@ -0,0 +1,204 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidExpression is returned if a computed value cannot be evaluated.
+	ErrInvalidExpression = errors.New("invalid expression")
+)
+
+// ComputedValue is a replacement value which is calculated from other values of the same PlaceholderMap.
+// Expressions support numbers, the names of other placeholders, the operators + - * / and parentheses.
+// Nothing else can be evaluated, so expressions may safely come from templates or configuration.
+type ComputedValue struct {
+	Expression string
+	// Format is the fmt verb used to format the result, e.g. '%.2f'. If empty, the shortest exact representation is used.
+	Format string
+}
+
+// Computed returns a ComputedValue for the given expression.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "subtotal":    120.50,
+//	    "tax":         "22.90",
+//	    "discount":    10,
+//	    "grand_total": docx.Computed("subtotal + tax - discount").WithFormat("%.2f"),
+//	})
+func Computed(expression string) ComputedValue {
+	return ComputedValue{Expression: expression}
+}
+
+// WithFormat returns a copy of the ComputedValue which formats the result using the given fmt verb.
+func (c ComputedValue) WithFormat(format string) ComputedValue {
+	c.Format = format
+	return c
+}
+
+// resolveComputed returns a copy of the PlaceholderMap in which all ComputedValues are replaced with their results.
+// If the map does not contain any ComputedValue, it is returned as is.
+func (p PlaceholderMap) resolveComputed() (PlaceholderMap, error) {
+	hasComputed := false
+	for _, value := range p {
+		if _, ok := value.(ComputedValue); ok {
+			hasComputed = true
+			break
+		}
+	}
+	if !hasComputed {
+		return p, nil
+	}
+
+	evaluator := &computedEvaluator{values: p, visiting: make(map[string]bool)}
+	resolved := make(PlaceholderMap, len(p))
+	for key, value := range p {
+		computed, ok := value.(ComputedValue)
+		if !ok {
+			resolved[key] = value
+			continue
+		}
+		result, err := evaluator.field(key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compute %s: %w", key, err)
+		}
+		if computed.Format != "" {
+			resolved[key] = fmt.Sprintf(computed.Format, result)
+		} else {
+			resolved[key] = strconv.FormatFloat(result, 'f', -1, 64)
+		}
+	}
+	return resolved, nil
+}
+
+// computedEvaluator evaluates the expressions of ComputedValues, detecting cyclic references.
+type computedEvaluator struct {
+	values   PlaceholderMap
+	visiting map[string]bool
+}
+
+// field returns the numeric value of the placeholder key, evaluating it if it is computed itself.
+func (e *computedEvaluator) field(key string) (float64, error) {
+	value, exists := e.values[key]
+	if !exists {
+		return 0, fmt.Errorf("%w: unknown field %q", ErrInvalidExpression, key)
+	}
+
+	if computed, ok := value.(ComputedValue); ok {
+		if e.visiting[key] {
+			return 0, fmt.Errorf("%w: cyclic reference to %q", ErrInvalidExpression, key)
+		}
+		e.visiting[key] = true
+		defer delete(e.visiting, key)
+
+		expr, err := parser.ParseExpr(computed.Expression)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidExpression, err)
+		}
+		return e.eval(expr)
+	}
+
+	return toFloat(value)
+}
+
+// eval evaluates the expression tree, only arithmetic nodes are allowed.
+func (e *computedEvaluator) eval(node ast.Expr) (float64, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return 0, fmt.Errorf("%w: unexpected literal %s", ErrInvalidExpression, n.Value)
+		}
+		return strconv.ParseFloat(n.Value, 64)
+
+	case *ast.Ident, *ast.SelectorExpr:
+		name, err := identifierName(n)
+		if err != nil {
+			return 0, err
+		}
+		return e.field(name)
+
+	case *ast.ParenExpr:
+		return e.eval(n.X)
+
+	case *ast.UnaryExpr:
+		x, err := e.eval(n.X)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.SUB:
+			return -x, nil
+		case token.ADD:
+			return x, nil
+		}
+
+	case *ast.BinaryExpr:
+		x, err := e.eval(n.X)
+		if err != nil {
+			return 0, err
+		}
+		y, err := e.eval(n.Y)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, fmt.Errorf("%w: division by zero", ErrInvalidExpression)
+			}
+			return x / y, nil
+		}
+		return 0, fmt.Errorf("%w: unsupported operator %s", ErrInvalidExpression, n.Op)
+	}
+	return 0, fmt.Errorf("%w: unsupported expression %T", ErrInvalidExpression, node)
+}
+
+// identifierName returns the placeholder name of an identifier, dotted names like 'order.tax' are supported.
+func identifierName(node ast.Expr) (string, error) {
+	switch n := node.(type) {
+	case *ast.Ident:
+		return n.Name, nil
+	case *ast.SelectorExpr:
+		prefix, err := identifierName(n.X)
+		if err != nil {
+			return "", err
+		}
+		return prefix + "." + n.Sel.Name, nil
+	}
+	return "", fmt.Errorf("%w: unsupported expression %T", ErrInvalidExpression, node)
+}
+
+// toFloat converts numeric values and numeric strings into a float64.
+func toFloat(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidExpression, v.String())
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("%w: %T is not a number", ErrInvalidExpression, value)
+}
@@ -0,0 +1,57 @@
+package docx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlaceholderMap_ResolveComputed(t *testing.T) {
+	values := PlaceholderMap{
+		"subtotal":    120.5,
+		"tax":         "22.90",
+		"discount":    10,
+		"order.fee":   uint8(2),
+		"net":         Computed("subtotal - discount"),
+		"grand_total": Computed("net + tax + order.fee").WithFormat("%.2f"),
+		"average":     Computed("(subtotal + discount) / 2"),
+		"negative":    Computed("-discount * 3"),
+	}
+
+	resolved, err := values.resolveComputed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"net":         "110.5",
+		"grand_total": "135.40",
+		"average":     "65.25",
+		"negative":    "-30",
+	}
+	for key, want := range expected {
+		if have := resolved[key]; have != want {
+			t.Errorf("%s: want=%s, have=%v", key, want, have)
+		}
+	}
+	if resolved["tax"] != "22.90" {
+		t.Errorf("non-computed values must not be changed")
+	}
+}
+
+func TestPlaceholderMap_ResolveComputed_Errors(t *testing.T) {
+	tests := map[string]PlaceholderMap{
+		"unknown field":    {"a": Computed("b + 1")},
+		"cycle":            {"a": Computed("b + 1"), "b": Computed("a * 2")},
+		"function call":    {"a": Computed("len(b)"), "b": "x"},
+		"not a number":     {"a": Computed("b + 1"), "b": "abc"},
+		"division by zero": {"a": Computed("1 / b"), "b": 0},
+		"string literal":   {"a": Computed(`"foo"`)},
+	}
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := values.resolveComputed(); !errors.Is(err, ErrInvalidExpression) {
+				t.Errorf("expected ErrInvalidExpression, have %v", err)
+			}
+		})
+	}
+}
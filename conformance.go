@@ -0,0 +1,167 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotStrict is returned if a document does not conform to the strict conformance class after the conversion.
+var ErrNotStrict = errors.New("document does not conform to strict OOXML")
+
+// Conformance is the conformance class of OOXML documents (ISO/IEC 29500).
+type Conformance int
+
+const (
+	// ConformanceTransitional is the conformance class written by the library and most applications.
+	ConformanceTransitional Conformance = iota
+	// ConformanceStrict is the strict conformance class required by some archival systems.
+	ConformanceStrict
+)
+
+var (
+	// transitionalNamespaceRegex matches the namespaces and relationship types of the transitional conformance class
+	// which differ in the strict conformance class and captures the schema (e.g. 'wordprocessingml') and the name.
+	// The namespaces of the packaging conventions are the same in both classes.
+	transitionalNamespaceRegex = regexp.MustCompile(`http://schemas\.openxmlformats\.org/(officeDocument|drawingml|wordprocessingml|spreadsheetml|presentationml)/2006/([A-Za-z-]+(?:/[A-Za-z-]+)?)`)
+	// onOffRegex matches WordprocessingML attributes with the transitional on/off values.
+	onOffRegex = regexp.MustCompile(`\sw:\w+="(?:on|off)"`)
+	// justificationRegex matches the transitional left and right justification of paragraphs and tables.
+	justificationRegex = regexp.MustCompile(`<w:jc\s+w:val="(?:left|right)"`)
+	// indentationRegex matches paragraph indentations (<w:ind>).
+	indentationRegex = regexp.MustCompile(`<w:ind\s[^>]*>`)
+	// indentationSideRegex matches the left and right attributes of indentations.
+	indentationSideRegex = regexp.MustCompile(`\sw:(?:left|right)(?:Chars)?=`)
+	// tableSidesRegex matches the borders and margins of tables and cells, which use start and end in strict documents.
+	tableSidesRegex = regexp.MustCompile(`(?s)<w:(?:tblBorders|tcBorders|tblCellMar|tcMar)>.*?</w:(?:tblBorders|tcBorders|tblCellMar|tcMar)>`)
+	// tableSideRegex matches the left and right elements inside table borders and margins.
+	tableSideRegex = regexp.MustCompile(`</?w:(?:left|right)[\s/>]`)
+	// percentWidthRegex matches elements with a width in fiftieths of a percent.
+	percentWidthRegex = regexp.MustCompile(`<w:\w+\s[^>]*w:type="pct"[^>]*>`)
+	// fiftiethsRegex matches a width in fiftieths of a percent and captures the value.
+	fiftiethsRegex = regexp.MustCompile(`(\sw:w=")([0-9]+)(")`)
+	// vmlPictureRegex matches VML pictures, which do not exist in strict documents.
+	vmlPictureRegex = regexp.MustCompile(`<w:pict[\s>]`)
+)
+
+// ConformanceIssue is a violation of the strict conformance class found by ValidateStrict.
+type ConformanceIssue struct {
+	// Part is the name of the part inside the archive, e.g. 'word/document.xml'.
+	Part    string
+	Message string
+}
+
+// ConvertToStrict converts a DOCX document of the transitional conformance class into the strict conformance class.
+// Namespaces and relationship types are replaced by their strict equivalents and values which only exist in
+// transitional documents (e.g. on/off, left/right justification and percentages in fiftieths) are rewritten.
+// The result is validated with ValidateStrict, content without strict equivalent (e.g. VML pictures) returns
+// ErrNotStrict.
+//
+// Example:
+//
+//	strictBytes, err := docx.ConvertToStrict(outputBytes)
+func ConvertToStrict(input []byte) ([]byte, error) {
+	parts, err := readArchiveParts(input, isXMLPart)
+	if err != nil {
+		return nil, err
+	}
+	for name, part := range parts {
+		parts[name] = []byte(strictXml(string(part)))
+	}
+	output, err := rewriteArchive(input, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := ValidateStrict(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("%w: %s in %s (%d issues)", ErrNotStrict, issues[0].Message, issues[0].Part, len(issues))
+	}
+	return output, nil
+}
+
+// ValidateStrict checks that a DOCX document conforms to the strict conformance class and returns all violations
+// ordered by part. The check covers namespaces, relationship types and the values which differ between the classes,
+// it is not a full schema validation.
+func ValidateStrict(input []byte) ([]ConformanceIssue, error) {
+	parts, err := readArchiveParts(input, isXMLPart)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := parts[DocumentXml]; !exists {
+		return nil, fmt.Errorf("%w, %s is missing", ErrInvalidArchive, DocumentXml)
+	}
+
+	var issues []ConformanceIssue
+	for name, part := range parts {
+		data := string(part)
+		if match := transitionalNamespaceRegex.FindString(data); match != "" {
+			issues = append(issues, ConformanceIssue{name, "transitional namespace " + match})
+		}
+		if vmlPictureRegex.MatchString(data) {
+			issues = append(issues, ConformanceIssue{name, "VML picture (w:pict)"})
+		}
+		if onOffRegex.MatchString(data) {
+			issues = append(issues, ConformanceIssue{name, "transitional on/off value"})
+		}
+		if justificationRegex.MatchString(data) {
+			issues = append(issues, ConformanceIssue{name, "transitional left/right justification"})
+		}
+	}
+	if !strings.Contains(string(parts[DocumentXml]), strictNamespace) {
+		issues = append(issues, ConformanceIssue{DocumentXml, "the main document does not use the strict namespace"})
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Part < issues[j].Part })
+	return issues, nil
+}
+
+// strictXml converts the transitional namespaces and values of an XML part into their strict equivalents.
+func strictXml(data string) string {
+	data = transitionalNamespaceRegex.ReplaceAllStringFunc(data, func(namespace string) string {
+		match := transitionalNamespaceRegex.FindStringSubmatch(namespace)
+		return "http://purl.oclc.org/ooxml/" + match[1] + "/" + camelCase(match[2])
+	})
+	data = onOffRegex.ReplaceAllStringFunc(data, func(attribute string) string {
+		if strings.Contains(attribute, `"on"`) {
+			return strings.Replace(attribute, `"on"`, `"true"`, 1)
+		}
+		return strings.Replace(attribute, `"off"`, `"false"`, 1)
+	})
+	data = justificationRegex.ReplaceAllStringFunc(data, strictSide)
+	data = indentationRegex.ReplaceAllStringFunc(data, func(indentation string) string {
+		return indentationSideRegex.ReplaceAllStringFunc(indentation, strictSide)
+	})
+	data = tableSidesRegex.ReplaceAllStringFunc(data, func(sides string) string {
+		return tableSideRegex.ReplaceAllStringFunc(sides, strictSide)
+	})
+	return percentWidthRegex.ReplaceAllStringFunc(data, func(element string) string {
+		return fiftiethsRegex.ReplaceAllStringFunc(element, func(width string) string {
+			match := fiftiethsRegex.FindStringSubmatch(width)
+			fiftieths, _ := strconv.Atoi(match[2])
+			return match[1] + strconv.FormatFloat(float64(fiftieths)/50, 'f', -1, 64) + "%" + match[3]
+		})
+	})
+}
+
+// strictSide replaces left by start and right by end.
+func strictSide(side string) string {
+	return strings.NewReplacer("left", "start", "right", "end").Replace(side)
+}
+
+// camelCase removes the hyphens of the name and capitalizes the following letters,
+// e.g. 'extended-properties' becomes 'extendedProperties'.
+func camelCase(name string) string {
+	words := strings.Split(name, "-")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,73 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertToStrict(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:pPr><w:jc w:val="right"/><w:ind w:left="720" w:rightChars="100" w:hanging="360"/></w:pPr><w:r><w:rPr><w:b w:val="on"/><w:i w:val="off"/></w:rPr><w:t>Left aligned</w:t></w:r></w:p>`+
+			`<w:tbl><w:tblPr><w:tblW w:w="2500" w:type="pct"/><w:tblBorders><w:left w:val="single"/><w:right w:val="single"/></w:tblBorders></w:tblPr>`+
+			`<w:tr><w:tc><w:tcPr><w:tcMar><w:left w:w="100" w:type="dxa"/></w:tcMar></w:tcPr><w:p/></w:tc></w:tr></w:tbl>`,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`,
+		"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>`+
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties" Target="docProps/app.xml"/></Relationships>`,
+	)
+	if issues, err := ValidateStrict(input); err != nil || len(issues) == 0 {
+		t.Fatalf("expected issues of the transitional document, have %v (%v)", issues, err)
+	}
+
+	output, err := ConvertToStrict(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, err := ValidateStrict(output); err != nil || len(issues) > 0 {
+		t.Errorf("expected a strict document, have %v (%v)", issues, err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{
+		`xmlns:w="http://purl.oclc.org/ooxml/wordprocessingml/main"`,
+		`xmlns:r="http://purl.oclc.org/ooxml/officeDocument/relationships"`,
+		`<w:jc w:val="end"/><w:ind w:start="720" w:endChars="100" w:hanging="360"/>`,
+		`<w:b w:val="true"/><w:i w:val="false"/>`,
+		`<w:t>Left aligned</w:t>`,
+		`<w:tblW w:w="50%" w:type="pct"/><w:tblBorders><w:start w:val="single"/><w:end w:val="single"/></w:tblBorders>`,
+		`<w:tcMar><w:start w:w="100" w:type="dxa"/></w:tcMar>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s inside %s", expected, document)
+		}
+	}
+	rels := readTestPart(t, output, "_rels/.rels")
+	for _, expected := range []string{
+		`xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`,
+		`Type="http://purl.oclc.org/ooxml/officeDocument/relationships/officeDocument"`,
+		`Type="http://purl.oclc.org/ooxml/officeDocument/relationships/extendedProperties"`,
+	} {
+		if !strings.Contains(rels, expected) {
+			t.Errorf("expected %s inside %s", expected, rels)
+		}
+	}
+
+	vml := buildTestDocx(t, `<w:p><w:r><w:pict><v:shape/></w:pict></w:r></w:p>`)
+	if _, err := ConvertToStrict(vml); !errors.Is(err, ErrNotStrict) {
+		t.Errorf("expected ErrNotStrict, have %v", err)
+	}
+}
+
+func TestPool_Render_Strict(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Name}}</w:t></w:r></w:p>`)
+	output, err := NewPool(1, RenderOptions{Conformance: ConformanceStrict}).Render(context.Background(), input, map[string]string{"Name": "Jane"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, strictNamespace) || !strings.Contains(document, "Jane") {
+		t.Errorf("expected a strict rendered document: %s", document)
+	}
+}
@@ -0,0 +1,339 @@
+package docx
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultEventDateLayout is the layout of event dates unless Event.DateLayout is set.
+	DefaultEventDateLayout = "Monday, 2 January 2006"
+	// DefaultEventTimeLayout is the layout of event times unless Event.TimeLayout is set.
+	DefaultEventTimeLayout = "15:04"
+
+	// rangeSeparator separates the start and the end of date and time ranges (en dash).
+	rangeSeparator = " – "
+)
+
+// Contact is a replacement value which renders a contact block, one line per field, e.g. the recipient of a letter:
+//
+//	Jane Doe
+//	ACME Corp
+//	Main Street 1
+//	12345 Springfield
+//	Germany
+//	+49 123 456789
+//	jane@example.com
+//
+// Empty fields are skipped. The postal line is 'PostalCode City', or 'City, Region PostalCode' if a region is set.
+type Contact struct {
+	Name         string
+	Organization string
+	// Street holds the street address, one entry per line.
+	Street     []string
+	PostalCode string
+	City       string
+	Region     string
+	Country    string
+	Phone      string
+	Email      string
+}
+
+// Lines returns the lines of the contact block.
+func (c Contact) Lines() []string {
+	var lines []string
+	add := func(line string) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	add(c.Name)
+	add(c.Organization)
+	for _, street := range c.Street {
+		add(street)
+	}
+	if c.Region != "" {
+		add(strings.TrimSuffix(c.City+", "+c.Region, ", ") + " " + c.PostalCode)
+	} else {
+		add(c.PostalCode + " " + c.City)
+	}
+	add(c.Country)
+	add(c.Phone)
+	add(c.Email)
+	return lines
+}
+
+// String returns the lines of the contact block separated by line breaks.
+func (c Contact) String() string {
+	return strings.Join(c.Lines(), "\n")
+}
+
+// inlineXml returns the lines of the contact block separated by line breaks (<w:br/>).
+func (c Contact) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.valueXml(c.String())
+}
+
+// Event is a replacement value which renders a calendar event, e.g. the appointment inside an invitation:
+//
+//	Annual meeting
+//	Monday, 2 March 2026, 14:00 – 16:30 CET
+//	Town hall, room 4
+//
+// The times are shown in TimeZone, or in the time zone of Start if TimeZone is nil, followed by the zone abbreviation.
+// Events starting and ending on the same day show the date once.
+type Event struct {
+	Title    string
+	Start    time.Time
+	End      time.Time
+	Location string
+	// AllDay events show dates only, End is the last day of the event (inclusive).
+	AllDay   bool
+	TimeZone *time.Location
+	// DateLayout and TimeLayout replace DefaultEventDateLayout and DefaultEventTimeLayout.
+	DateLayout, TimeLayout string
+}
+
+// Lines returns the lines of the event: title, date range and location. Empty lines are skipped.
+func (e Event) Lines() []string {
+	var lines []string
+	for _, line := range []string{e.Title, e.When(), e.Location} {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// When returns the date range of the event, e.g. 'Monday, 2 March 2026, 14:00 – 16:30 CET'.
+func (e Event) When() string {
+	if e.Start.IsZero() {
+		return ""
+	}
+	dateLayout, timeLayout := e.DateLayout, e.TimeLayout
+	if dateLayout == "" {
+		dateLayout = DefaultEventDateLayout
+	}
+	if timeLayout == "" {
+		timeLayout = DefaultEventTimeLayout
+	}
+	start, end := e.Start, e.End
+	if e.TimeZone != nil {
+		start, end = start.In(e.TimeZone), end.In(e.TimeZone)
+	} else if !end.IsZero() {
+		end = end.In(start.Location())
+	}
+	sameDay := end.IsZero() || start.Format("2006-01-02") == end.Format("2006-01-02")
+
+	switch {
+	case e.AllDay && sameDay:
+		return start.Format(dateLayout)
+	case e.AllDay:
+		return start.Format(dateLayout) + rangeSeparator + end.Format(dateLayout)
+	case end.IsZero():
+		return start.Format(dateLayout + ", " + timeLayout + " MST")
+	case sameDay:
+		return start.Format(dateLayout+", "+timeLayout) + rangeSeparator + end.Format(timeLayout+" MST")
+	default:
+		return start.Format(dateLayout+", "+timeLayout) + rangeSeparator + end.Format(dateLayout+", "+timeLayout+" MST")
+	}
+}
+
+// String returns the lines of the event separated by line breaks.
+func (e Event) String() string {
+	return strings.Join(e.Lines(), "\n")
+}
+
+// inlineXml returns the lines of the event separated by line breaks (<w:br/>).
+func (e Event) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.valueXml(e.String())
+}
+
+// contentLine is a single unfolded content line of a vCard or iCalendar object, e.g. 'DTSTART;TZID=Europe/Berlin:...'.
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseContentLines unfolds and parses the content lines of a vCard (RFC 6350) or iCalendar (RFC 5545) object.
+func parseContentLines(data string) []contentLine {
+	var unfolded []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(unfolded) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	var lines []contentLine
+	for _, line := range unfolded {
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			continue
+		}
+		fields := strings.Split(line[:colon], ";")
+		parsed := contentLine{name: strings.ToUpper(fields[0]), params: make(map[string]string), value: line[colon+1:]}
+		// group prefixes like 'item1.TEL' are ignored
+		if dot := strings.LastIndexByte(parsed.name, '.'); dot >= 0 {
+			parsed.name = parsed.name[dot+1:]
+		}
+		for _, param := range fields[1:] {
+			if key, value, found := strings.Cut(param, "="); found {
+				parsed.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+			}
+		}
+		lines = append(lines, parsed)
+	}
+	return lines
+}
+
+// unescapeContentValue unescapes a text value of a content line (\n, \, \; and \\).
+func unescapeContentValue(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// splitContentValue splits a structured value (e.g. ADR) at unescaped semicolons and unescapes the components.
+func splitContentValue(value string) []string {
+	var components []string
+	var current strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+		case value[i] == ';':
+			components = append(components, unescapeContentValue(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	return append(components, unescapeContentValue(current.String()))
+}
+
+// ParseVCard returns the Contact of the first vCard inside data. The formatted name (FN, or N if missing), the
+// organization, the first address (ADR), phone number (TEL) and email address are used.
+func ParseVCard(data string) (Contact, error) {
+	var contact Contact
+	var name []string
+	inCard := false
+	for _, line := range parseContentLines(data) {
+		switch {
+		case line.name == "BEGIN" && strings.EqualFold(line.value, "VCARD"):
+			inCard = true
+		case line.name == "END" && strings.EqualFold(line.value, "VCARD") && inCard:
+			if contact.Name == "" && len(name) > 1 {
+				// N is 'family;given;additional;prefixes;suffixes'
+				contact.Name = strings.TrimSpace(strings.Join([]string{component(name, 3), component(name, 1), component(name, 2), component(name, 0), component(name, 4)}, " "))
+				contact.Name = strings.Join(strings.Fields(contact.Name), " ")
+			}
+			return contact, nil
+		case !inCard:
+		case line.name == "FN":
+			contact.Name = unescapeContentValue(line.value)
+		case line.name == "N":
+			name = splitContentValue(line.value)
+		case line.name == "ORG" && contact.Organization == "":
+			contact.Organization = strings.Join(nonEmpty(splitContentValue(line.value)), ", ")
+		case line.name == "ADR" && contact.City == "" && len(contact.Street) == 0:
+			// ADR is 'post office box;extended address;street;locality;region;postal code;country'
+			adr := splitContentValue(line.value)
+			contact.Street = nonEmpty(append(strings.Split(component(adr, 2), "\n"), component(adr, 1), component(adr, 0)))
+			contact.City, contact.Region, contact.PostalCode, contact.Country = component(adr, 3), component(adr, 4), component(adr, 5), component(adr, 6)
+		case line.name == "TEL" && contact.Phone == "":
+			contact.Phone = strings.TrimPrefix(unescapeContentValue(line.value), "tel:")
+		case line.name == "EMAIL" && contact.Email == "":
+			contact.Email = unescapeContentValue(line.value)
+		}
+	}
+	return Contact{}, fmt.Errorf("no complete vCard found")
+}
+
+// ParseICalendarEvent returns the Event of the first VEVENT inside the iCalendar data. SUMMARY, DTSTART, DTEND
+// and LOCATION are used. Times with a TZID parameter are read in that time zone, which must be known to the system.
+// For all-day events (VALUE=DATE) the exclusive DTEND is converted into the last day of the event.
+func ParseICalendarEvent(data string) (Event, error) {
+	var event Event
+	inEvent := false
+	for _, line := range parseContentLines(data) {
+		switch {
+		case line.name == "BEGIN" && strings.EqualFold(line.value, "VEVENT"):
+			inEvent = true
+		case line.name == "END" && strings.EqualFold(line.value, "VEVENT") && inEvent:
+			if event.Start.IsZero() {
+				return Event{}, fmt.Errorf("event has no start")
+			}
+			return event, nil
+		case !inEvent:
+		case line.name == "SUMMARY":
+			event.Title = unescapeContentValue(line.value)
+		case line.name == "LOCATION":
+			event.Location = unescapeContentValue(line.value)
+		case line.name == "DTSTART" || line.name == "DTEND":
+			t, allDay, err := parseICalendarTime(line)
+			if err != nil {
+				return Event{}, err
+			}
+			if line.name == "DTSTART" {
+				event.Start, event.AllDay = t, allDay
+			} else if allDay {
+				event.End = t.AddDate(0, 0, -1)
+			} else {
+				event.End = t
+			}
+		}
+	}
+	return Event{}, fmt.Errorf("no complete VEVENT found")
+}
+
+// parseICalendarTime parses the DATE or DATE-TIME value of the content line.
+func parseICalendarTime(line contentLine) (time.Time, bool, error) {
+	if line.params["VALUE"] == "DATE" || len(line.value) == len("20060102") {
+		t, err := time.Parse("20060102", line.value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %s of %s: %w", line.value, line.name, err)
+		}
+		return t, true, nil
+	}
+	location := time.Local
+	if tzid := line.params["TZID"]; tzid != "" {
+		var err error
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %s of %s: %w", tzid, line.name, err)
+		}
+	}
+	if strings.HasSuffix(line.value, "Z") {
+		location = time.UTC
+	}
+	t, err := time.ParseInLocation("20060102T150405", strings.TrimSuffix(line.value, "Z"), location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %s of %s: %w", line.value, line.name, err)
+	}
+	return t, false, nil
+}
+
+// component returns the element at index i or an empty string.
+func component(values []string, i int) string {
+	if i < len(values) {
+		return strings.TrimSpace(values[i])
+	}
+	return ""
+}
+
+// nonEmpty returns all values which are not empty after trimming spaces.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
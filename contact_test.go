@@ -0,0 +1,109 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_ContactAndEvent(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{recipient}</w:t></w:r></w:p><w:p><w:r><w:t>{meeting}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin := time.FixedZone("CET", 3600)
+	err = doc.ReplaceAll(PlaceholderMap{
+		"recipient": Contact{Name: "Jane Doe", Organization: "Smith & Sons", Street: []string{"Main Street 1"}, PostalCode: "12345", City: "Springfield"},
+		"meeting": Event{
+			Title: "Annual meeting",
+			Start: time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC),
+			// shown in the time zone of the event
+			TimeZone: berlin,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`Jane Doe</w:t><w:br/><w:t xml:space="preserve">Smith &amp; Sons</w:t><w:br/><w:t xml:space="preserve">Main Street 1</w:t><w:br/><w:t xml:space="preserve">12345 Springfield</w:t>`,
+		`Annual meeting</w:t><w:br/><w:t xml:space="preserve">Monday, 2 March 2026, 14:00 – 16:30 CET</w:t>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
+
+func TestContact_Lines(t *testing.T) {
+	contact := Contact{Name: "John Smith", Street: []string{"1 Infinite Loop", ""}, City: "Cupertino", Region: "CA", PostalCode: "95014", Country: "USA", Phone: "+1 408 996 1010"}
+	expected := "John Smith\n1 Infinite Loop\nCupertino, CA 95014\nUSA\n+1 408 996 1010"
+	if have := contact.String(); have != expected {
+		t.Errorf("expected %q, have %q", expected, have)
+	}
+}
+
+func TestEvent_When(t *testing.T) {
+	start := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		event    Event
+		expected string
+	}{
+		{Event{Start: start}, "Monday, 2 March 2026, 14:00 UTC"},
+		{Event{Start: start, End: start.Add(26 * time.Hour)}, "Monday, 2 March 2026, 14:00 – Tuesday, 3 March 2026, 16:00 UTC"},
+		{Event{Start: start, End: start, AllDay: true}, "Monday, 2 March 2026"},
+		{Event{Start: start, End: start.AddDate(0, 0, 2), AllDay: true, DateLayout: "02.01.2006"}, "02.03.2026 – 04.03.2026"},
+		{Event{}, ""},
+	}
+	for _, tt := range tests {
+		if have := tt.event.When(); have != tt.expected {
+			t.Errorf("expected %q, have %q", tt.expected, have)
+		}
+	}
+}
+
+func TestParseVCard(t *testing.T) {
+	contact, err := ParseVCard("BEGIN:VCARD\r\nVERSION:4.0\r\nN:Doe;Jane;;Dr.;\r\nORG:ACME\\, Inc.;Sales\r\n" +
+		"ADR;TYPE=work:;Suite 5;Main Street 1;Springfield;;12345;Ger\r\n many\r\nTEL;TYPE=voice:tel:+49-123\r\n" +
+		"item1.EMAIL:jane@example.com\r\nEND:VCARD\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Dr. Jane Doe\nACME, Inc., Sales\nMain Street 1\nSuite 5\n12345 Springfield\nGermany\n+49-123\njane@example.com"
+	if have := contact.String(); have != expected {
+		t.Errorf("expected %q, have %q", expected, have)
+	}
+	if _, err := ParseVCard("BEGIN:VCARD\nFN:Jane"); err == nil {
+		t.Error("expected error for incomplete vCard")
+	}
+}
+
+func TestParseICalendarEvent(t *testing.T) {
+	event, err := ParseICalendarEvent("BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Kick-off\\, part 1\nDTSTART:20260302T130000Z\n" +
+		"DTEND;TZID=UTC:20260302T150000\nLOCATION:Room 4\nEND:VEVENT\nEND:VCALENDAR\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := event.String(); have != "Kick-off, part 1\nMonday, 2 March 2026, 13:00 – 15:00 UTC\nRoom 4" {
+		t.Errorf("unexpected event %q", have)
+	}
+
+	allDay, err := ParseICalendarEvent("BEGIN:VEVENT\nDTSTART;VALUE=DATE:20260302\nDTEND;VALUE=DATE:20260303\nEND:VEVENT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := allDay.When(); have != "Monday, 2 March 2026" {
+		t.Errorf("expected single all-day event, have %q", have)
+	}
+
+	if _, err := ParseICalendarEvent("BEGIN:VEVENT\nDTSTART:2026\nEND:VEVENT"); err == nil {
+		t.Error("expected error for invalid start")
+	}
+}
@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// contentNoiseRegex matches the elements and attributes which Word changes on every save without changing the
+	// content: revision session IDs, paragraph and text IDs, proofing marks, rendered page breaks, the zoom and proofing
+	// state and the document IDs of the settings, and the XML declaration.
+	contentNoiseRegex = regexp.MustCompile(`\s(?:w:rsid\w*|w14:paraId|w14:textId)="[^"]*"|<w:proofErr\s[^>]*/>|` +
+		`<w:lastRenderedPageBreak/>|(?s)<w:rsids>.*?</w:rsids>|<w:(?:proofState|zoom)\s[^>]*/>|<w\d*:docId\s[^>]*/>|<\?xml[^>]*\?>`)
+	// corePropertiesNoiseRegex matches the core properties which change on every save.
+	corePropertiesNoiseRegex = regexp.MustCompile(`(?s)<(dcterms:modified|cp:lastModifiedBy|cp:revision|cp:lastPrinted)[\s>].*?</(?:dcterms:modified|cp:lastModifiedBy|cp:revision|cp:lastPrinted)>`)
+	// formattingWhitespaceRegex matches whitespace between tags which contains a line break, e.g. of indented XML.
+	formattingWhitespaceRegex = regexp.MustCompile(`>[ \t\r]*\n\s*<`)
+	// openTagRegex matches open and self-closing tags and captures the qualified name, the attributes and the
+	// self-closing slash.
+	openTagRegex = regexp.MustCompile(`<([A-Za-z_][\w.-]*(?::[A-Za-z_][\w.-]*)?)(\s[^>]*?)?(/?)>`)
+	// listEntryRegex matches the entries of content types and relationships parts, whose order has no meaning.
+	listEntryRegex = regexp.MustCompile(`<(?:Default|Override|Relationship)\s[^>]*/>`)
+)
+
+// ContentHash returns the SHA-256 hash (hex encoded) of the logical content of the DOCX document given by input.
+// Unlike a hash of the bytes, it does not depend on the container: the order, compression and timestamps of the
+// archive entries, the order of attributes, relationships and content types, indentation of the XML and the data which
+// Word changes on every save (revision session IDs, paragraph IDs, proofing marks, save statistics, the modification
+// time and the last author). Media and other binary parts are included with their digest.
+//
+// Two documents with the same content hash open with the same text, structure, formatting and media, so the hash can
+// be used to deduplicate generated documents or to detect changes.
+//
+// Example:
+//
+//	hash, err := docx.ContentHash(outputBytes)
+//	if hash == previousHash {
+//	    // nothing changed, skip sending the document
+//	}
+func ContentHash(input []byte) (string, error) {
+	parts, err := readArchiveParts(input, func(name string) bool {
+		// the save statistics and the thumbnail are updated on every save, directories are no parts
+		return name != "docProps/app.xml" && !strings.HasPrefix(name, "docProps/thumbnail.") && !strings.HasSuffix(name, "/")
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read document: %w", err)
+	}
+
+	hash := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(parts)) {
+		data := parts[name]
+		if isXMLPart(name) {
+			data = []byte(normalizeXmlContent(name, string(data)))
+		} else {
+			digest := sha256.Sum256(data)
+			data = digest[:]
+		}
+		fmt.Fprintf(hash, "%s\n%d\n", name, len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// normalizeXmlContent returns the XML part without the data which does not change its content, see ContentHash.
+func normalizeXmlContent(name, data string) string {
+	if name == "docProps/core.xml" {
+		data = corePropertiesNoiseRegex.ReplaceAllString(data, "")
+	}
+	data = contentNoiseRegex.ReplaceAllString(data, "")
+	data = formattingWhitespaceRegex.ReplaceAllString(data, "><")
+	data = strings.TrimSpace(data)
+
+	// attributes are sorted, their order has no meaning
+	data = openTagRegex.ReplaceAllStringFunc(data, func(tag string) string {
+		groups := openTagRegex.FindStringSubmatch(tag)
+		attributes := xmlAttributeRegex.FindAllString(groups[2], -1)
+		if len(attributes) < 2 {
+			return tag
+		}
+		slices.Sort(attributes)
+		return "<" + groups[1] + " " + strings.Join(attributes, " ") + groups[3] + ">"
+	})
+
+	if name == "[Content_Types].xml" || strings.HasSuffix(name, ".rels") {
+		entries := listEntryRegex.FindAllString(data, -1)
+		slices.Sort(entries)
+		data = listEntryRegex.ReplaceAllString(data, "") + strings.Join(entries, "")
+	}
+	return data
+}
@@ -0,0 +1,91 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildTestArchive returns a ZIP archive with the given parts, stored without compression and with the given
+// modification time.
+func buildTestArchive(t *testing.T, modified time.Time, parts ...string) []byte {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := 0; i < len(parts); i += 2 {
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: parts[i], Method: zip.Store, Modified: modified})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(parts[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestContentHash(t *testing.T) {
+	const body = `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Hello </w:t></w:r></w:p>`
+	contentTypes := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`
+	rels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/></Relationships>`
+	core := func(modified, author string) string {
+		return `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">` +
+			`<dc:title>Report</dc:title><cp:lastModifiedBy>` + author + `</cp:lastModifiedBy><cp:revision>3</cp:revision>` +
+			`<dcterms:modified xsi:type="dcterms:W3CDTF">` + modified + `</dcterms:modified></cp:coreProperties>`
+	}
+	original := buildTestDocx(t, body, "docProps/core.xml", core("2026-01-01T10:00:00Z", "Jane"), "docProps/app.xml", `<Properties><TotalTime>3</TotalTime></Properties>`,
+		"word/media/image1.png", "image", "_rels/.rels", rels)
+	hash, err := ContentHash(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same content saved by another application, in another order and with the noise of a Word save
+	resaved := buildTestArchive(t, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		"word/", "",
+		"word/media/image1.png", "image",
+		DocumentXml, testDocumentOpen+"\n  "+`<w:p w:rsidR="00A1" w14:paraId="1A2B" w:rsidRDefault="00A1"><w:pPr><w:jc w:val="center"/></w:pPr>`+"\n    "+
+			`<w:proofErr w:type="spellStart"/><w:r w:rsidRPr="00B2"><w:rPr><w:b/></w:rPr><w:lastRenderedPageBreak/><w:t xml:space="preserve">Hello </w:t></w:r></w:p>`+"\n"+testDocumentClose,
+		"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`,
+		"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Target="docProps/core.xml" Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"/>`+
+			`<Relationship Target="word/document.xml" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Id="rId1"/></Relationships>`,
+		"[Content_Types].xml", contentTypes,
+		"docProps/core.xml", core("2026-05-01T08:30:00Z", "John"),
+		"docProps/app.xml", `<Properties><TotalTime>42</TotalTime></Properties>`,
+	)
+	if resavedHash, err := ContentHash(resaved); err != nil {
+		t.Fatal(err)
+	} else if resavedHash != hash {
+		t.Errorf("expected the same hash for the same content, got %s and %s", hash, resavedHash)
+	}
+
+	for name, changed := range map[string][]byte{
+		"text": buildTestDocx(t, body+`<w:p/>`, "docProps/core.xml", core("2026-01-01T10:00:00Z", "Jane"),
+			"word/media/image1.png", "image", "_rels/.rels", rels),
+		"formatting": buildTestDocx(t, body[:len(body)-len("</w:p>")]+`<w:r><w:rPr><w:i/></w:rPr><w:t>x</w:t></w:r></w:p>`, "docProps/core.xml", core("2026-01-01T10:00:00Z", "Jane"),
+			"word/media/image1.png", "image", "_rels/.rels", rels),
+		"media": buildTestDocx(t, body, "docProps/core.xml", core("2026-01-01T10:00:00Z", "Jane"),
+			"word/media/image1.png", "other image", "_rels/.rels", rels),
+		"title": buildTestDocx(t, body, "docProps/core.xml", strings.Replace(core("2026-01-01T10:00:00Z", "Jane"), "Report", "Other", 1),
+			"word/media/image1.png", "image", "_rels/.rels", rels),
+	} {
+		if changedHash, err := ContentHash(changed); err != nil {
+			t.Fatal(err)
+		} else if changedHash == hash {
+			t.Errorf("expected a different hash after changing the %s", name)
+		}
+	}
+
+	if _, err := ContentHash([]byte("not a zip")); err == nil {
+		t.Error("expected an error for invalid input")
+	}
+}
@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"fmt"
+	"maps"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// Defaults are the package-level defaults of all renders, which a service sets once at startup instead of passing
+// the same options at every call site, see SetDefaults.
+type Defaults struct {
+	// Options are the defaults of Render, Pool.Render, ProcessTemplateDocx, ProcessTemplateDocxWithFuncs,
+	// ProcessClauseTemplate and ProcessValues, e.g. the functions, the locale or the output limits. The options of
+	// a pool and of every call override them like RenderOptions override the options of a pool: set options replace
+	// the defaults and functions are combined. The timeout only applies to Render and Pool.Render.
+	Options RenderOptions
+	// ParseLimits are the limits used instead of DefaultParseLimits by Open, OpenBytes and all processing functions.
+	// Nil uses DefaultParseLimits.
+	ParseLimits *ParseLimits
+	// OpenDelimiter and CloseDelimiter are the delimiters of placeholders, see ChangeOpenCloseDelimiter. Zero keeps
+	// the current delimiters. Like ChangeOpenCloseDelimiter, the delimiters are global and read without
+	// synchronization, so they must only be set at startup before documents are processed.
+	OpenDelimiter, CloseDelimiter rune
+}
+
+// defaults are the current defaults, they are replaced as a whole so renders always see a consistent set.
+var defaults atomic.Pointer[Defaults]
+
+// SetDefaults replaces the package-level defaults, see Defaults. It is safe to call while documents are rendered,
+// renders which already started keep the previous defaults. The functions and limits are copied, so changing them
+// afterwards has no effect. Placeholder delimiters are the exception, they must be set at startup.
+//
+// Example:
+//
+//	err := docx.SetDefaults(docx.Defaults{Options: docx.RenderOptions{
+//	    Funcs:        template.FuncMap{"vatId": formatVatId},
+//	    Locale:       "de-DE",
+//	    Timeout:      10 * time.Second,
+//	    OutputLimits: docx.OutputLimits{MaxParagraphs: 10000},
+//	}})
+func SetDefaults(d Defaults) error {
+	if d.Options.Locale != "" {
+		if _, err := language.Parse(d.Options.Locale); err != nil {
+			return fmt.Errorf("invalid locale %s: %w", d.Options.Locale, err)
+		}
+	}
+	if err := validateFuncs(d.Options.Funcs); err != nil {
+		return err
+	}
+	if (d.OpenDelimiter == 0) != (d.CloseDelimiter == 0) {
+		return fmt.Errorf("both placeholder delimiters must be set")
+	}
+
+	d.Options.Funcs = maps.Clone(d.Options.Funcs)
+	if d.ParseLimits != nil {
+		limits := *d.ParseLimits
+		d.ParseLimits = &limits
+	}
+	if d.OpenDelimiter != 0 {
+		ChangeOpenCloseDelimiter(d.OpenDelimiter, d.CloseDelimiter)
+	}
+	defaults.Store(&d)
+	return nil
+}
+
+// CurrentDefaults returns the package-level defaults set by SetDefaults.
+func CurrentDefaults() Defaults {
+	current := defaults.Load()
+	if current == nil {
+		return Defaults{}
+	}
+	copied := *current
+	copied.Options.Funcs = maps.Clone(current.Options.Funcs)
+	if current.ParseLimits != nil {
+		limits := *current.ParseLimits
+		copied.ParseLimits = &limits
+	}
+	return copied
+}
+
+// defaultRenderOptions returns the default options of renders. The functions are shared with the defaults and must
+// not be changed.
+func defaultRenderOptions() RenderOptions {
+	if current := defaults.Load(); current != nil {
+		return current.Options
+	}
+	return RenderOptions{}
+}
+
+// currentParseLimits returns the limits of all documents which are parsed, see Defaults.ParseLimits.
+func currentParseLimits() ParseLimits {
+	if current := defaults.Load(); current != nil && current.ParseLimits != nil {
+		return *current.ParseLimits
+	}
+	return DefaultParseLimits
+}
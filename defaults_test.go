@@ -0,0 +1,122 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// setTestDefaults sets the defaults for the test and resets them afterwards.
+func setTestDefaults(t *testing.T, d Defaults) {
+	t.Helper()
+	if err := SetDefaults(d); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := SetDefaults(Defaults{}); err != nil {
+			t.Error(err)
+		}
+		ChangeOpenCloseDelimiter('{', '}')
+	})
+}
+
+func TestSetDefaults(t *testing.T) {
+	funcs := template.FuncMap{"vatId": func(s string) string { return "DE" + s }}
+	setTestDefaults(t, Defaults{Options: RenderOptions{Funcs: funcs, Locale: "de-DE"}})
+	// changing the map of the caller does not change the defaults
+	funcs["vatId"] = func(s string) string { return "changed" }
+
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{vatId .Id}} {{locale}} {{currency "EUR" .Total}}</w:t></w:r></w:p>`)
+	data := map[string]interface{}{"Id": "123", "Total": 1234.5}
+
+	output, err := ProcessTemplateDocx(input, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "DE123 de-DE 1.234,50\u00a0€") {
+		t.Errorf("expected the default functions and locale: %s", document)
+	}
+
+	// the options of a call override the defaults, functions are combined
+	output, err = Render(FromBytes(input), data, WithLocale("en-US"), WithFuncs(template.FuncMap{"currency": func(code string, v float64) string { return code }}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "DE123 en-US EUR") {
+		t.Errorf("expected the options of the call to override the defaults: %s", document)
+	}
+
+	// the options of a pool override the defaults as well
+	pool := NewPool(1, RenderOptions{Locale: "fr-FR"})
+	output, err = pool.Render(context.Background(), input, data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "DE123 fr-FR") {
+		t.Errorf("expected the options of the pool to override the defaults: %s", document)
+	}
+
+	if current := CurrentDefaults(); current.Options.Locale != "de-DE" || len(current.Options.Funcs) != 1 {
+		t.Errorf("unexpected current defaults %+v", current)
+	}
+}
+
+func TestSetDefaults_Delimiters(t *testing.T) {
+	// documents parsed before do not block setting the delimiters
+	if _, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{name}</w:t></w:r></w:p>`)); err != nil {
+		t.Fatal(err)
+	}
+	setTestDefaults(t, Defaults{OpenDelimiter: '[', CloseDelimiter: ']'})
+
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Dear [name], {name}</w:t></w:r></w:p>`)
+	output, err := ProcessBytes(input, map[string]string{"name": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "Dear Ada, {name}") {
+		t.Errorf("expected the default delimiters to be used: %s", document)
+	}
+}
+
+func TestSetDefaults_Invalid(t *testing.T) {
+	invalid := []Defaults{
+		{Options: RenderOptions{Locale: "not a locale"}},
+		{Options: RenderOptions{Funcs: template.FuncMap{"broken": "no function"}}},
+		{Options: RenderOptions{Funcs: template.FuncMap{escapeFuncName: strings.ToUpper}}},
+		{OpenDelimiter: '['},
+	}
+	for _, d := range invalid {
+		if err := SetDefaults(d); err == nil {
+			t.Errorf("expected error for defaults %+v", d)
+		}
+	}
+	if current := CurrentDefaults(); current.Options.Locale != "" {
+		t.Errorf("invalid defaults must not be set: %+v", current)
+	}
+}
+
+func TestSetDefaults_Flags(t *testing.T) {
+	setTestDefaults(t, Defaults{Options: RenderOptions{StrictValues: Bool(true)}})
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{total}</w:t></w:r></w:p>`)
+
+	if _, err := ProcessValues(input, PlaceholderMap{"total": 1234.5}, RenderOptions{}); !errors.Is(err, ErrUnsupportedValue) {
+		t.Errorf("expected the default to reject numbers, have %v", err)
+	}
+	if _, err := ProcessValues(input, PlaceholderMap{"total": 1234.5}, RenderOptions{StrictValues: Bool(false)}); err != nil {
+		t.Errorf("expected the options to turn off the default: %v", err)
+	}
+}
+
+func TestSetDefaults_ParseLimits(t *testing.T) {
+	setTestDefaults(t, Defaults{ParseLimits: &ParseLimits{MaxArchiveEntries: 1}})
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{name}</w:t></w:r></w:p>`)
+
+	if _, err := ProcessBytes(input, map[string]string{"name": "Ada"}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected the default limits to be applied, have %v", err)
+	}
+	if _, err := OpenBytes(input); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected the default limits to be applied, have %v", err)
+	}
+}
@@ -3,28 +3,42 @@ package docx
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const (
 	// DocumentXml is the relative path where the actual document content resides inside the DOCX archive.
 	DocumentXml = "word/document.xml"
+	// FootnotesXml is the relative path of the footnotes inside the DOCX archive.
+	FootnotesXml = "word/footnotes.xml"
+	// EndnotesXml is the relative path of the endnotes inside the DOCX archive.
+	EndnotesXml = "word/endnotes.xml"
 )
 
 var (
+	// ErrInvalidArchive is returned if the input is no ZIP archive or lacks the parts every DOCX archive requires.
+	ErrInvalidArchive = errors.New("invalid DOCX archive")
+	// ErrUnknownFile is returned if a file is not one of the parsed files of the document, see SetFile.
+	ErrUnknownFile = errors.New("unknown file")
+
 	// HeaderPathRegex matches all header files inside the DOCX archive.
 	HeaderPathRegex = regexp.MustCompile(`word/header[0-9]*.xml`)
 	// FooterPathRegex matches all footer files inside the DOCX archive.
 	FooterPathRegex = regexp.MustCompile(`word/footer[0-9]*.xml`)
 	// MediaPathRegex matches all media files inside the DOCX archive.
 	MediaPathRegex = regexp.MustCompile(`word/media/*`)
+	// ChartPathRegex matches all chart files inside the DOCX archive.
+	ChartPathRegex = regexp.MustCompile(`^word/charts/chart[0-9]*\.xml$`)
 )
 
 // Document represents a DOCX file and provides methods for manipulating its content.
@@ -34,6 +48,9 @@ type Document struct {
 	path     string
 	docxFile *os.File
 	zipFile  *zip.Reader
+	limits   ParseLimits
+	// source is the file of a file system from which the archive is read, see OpenFS
+	source io.Closer
 
 	// all files from the zip archive which we're interested in
 	files FileMap
@@ -41,14 +58,76 @@ type Document struct {
 	headerFiles []string
 	// paths to all footer files inside the zip archive
 	footerFiles []string
+	// paths to the footnotes and endnotes files inside the zip archive
+	noteFiles []string
 	// paths to all media files inside the zip archive
 	mediaFiles []string
+	// paths to all chart files inside the zip archive
+	chartFiles []string
 	// The document contains multiple files which eventually need a parser each.
 	// The map key is the file path inside the document to which the parser belongs.
 	runParsers map[string]*RunParser
 
 	filePlaceholders map[string][]*Placeholder
 	fileReplacers    map[string]*Replacer
+	// ignorePlaceholders is true if the text of the files is not parsed for placeholders, e.g. of rendered templates
+	// whose text may contain delimiters, see templateValues.replace
+	ignorePlaceholders bool
+
+	// modified holds the names of all files and parts whose content was changed
+	modified map[string]bool
+	// parts holds all other parts of the archive (e.g. relationships) which were loaded or added on demand
+	parts FileMap
+	// newParts are the names of all parts inside parts which do not exist in the original archive, in insertion order
+	newParts []string
+	// streamedParts are the names of all parts which were already written while streaming the archive, see Process
+	streamedParts map[string]bool
+	// relIds holds the last relationship ID (rIdN) used in each relationships part
+	relIds map[string]int
+	// docPrId is the last ID used for drawing objects (<wp:docPr>), 0 if not yet initialized
+	docPrId int
+	// images maps the digest of all inserted images to their media part
+	images map[[sha256.Size]byte]string
+	// imageRels maps part and media part of inserted images to the relationship ID
+	imageRels map[string]string
+	// mediaCount is the counter used to name new media parts
+	mediaCount int
+	// mediaStore is the shared store of images, nil if every image is encoded for this document only
+	mediaStore *MediaStore
+	// storedMedia maps new media parts to the images of the mediaStore from which they are written
+	storedMedia map[string]*storedMedia
+	// blocks are the block values inserted by the current replacement, see blockValue
+	blocks []pendingBlock
+	// footnotes are the footnotes (<w:footnote>) added by the current replacement, see TextWithFootnote
+	footnotes []string
+	// footnoteId is the last ID used for footnotes, 0 if not yet initialized
+	footnoteId int
+
+	// textPolicy is applied to all inserted text values
+	textPolicy TextPolicy
+	// authors maps placeholder keys (without delimiters) to the author to which inserted values are attributed
+	authors map[string]string
+	// lengthLimits maps placeholder keys (without delimiters) to the maximum length of their values
+	lengthLimits map[string]LengthLimit
+	// outputLimits restrict the complexity of the written document
+	outputLimits OutputLimits
+	// fontPolicy restricts the fonts which may be embedded, see EmbedFont
+	fontPolicy FontPolicy
+	// sequences maps the names of SEQ field sequences (e.g. 'Figure') to their last number, see nextSequenceNumber
+	sequences map[string]int
+	// valueChecksums maps placeholder keys (without delimiters) to the checksums of their rendered values, nil if
+	// checksums are not recorded, see RecordValueChecksums
+	valueChecksums      map[string]string
+	valueChecksumSecret []byte
+	// trackReplacements is true if all values are inserted as tracked insertions of trackAuthor, see TrackReplacements
+	trackReplacements bool
+	trackAuthor       string
+	// revisionDate is the date of tracked insertions, they have no date if zero
+	revisionDate time.Time
+	// revisionId is the last ID used for revision marks (e.g. <w:ins>), 0 if not yet initialized
+	revisionId int
+	// timestampPolicy controls the timestamps of the core properties on write, see SetTimestampPolicy
+	timestampPolicy TimestampPolicy
 }
 
 // Open loads a DOCX file from disk and returns a parsed Document ready for manipulation.
@@ -61,22 +140,80 @@ func Open(path string) (*Document, error) {
 
 	rc, err := zip.OpenReader(path)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open ZIP reader: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
 	}
 
-	return newDocument(&rc.Reader, path, fh)
+	return newDocument(&rc.Reader, path, fh, currentParseLimits())
 }
 
 // OpenBytes creates a Document from a byte slice containing DOCX data.
 // This is useful for processing DOCX files that are already loaded in memory.
 // No file handle is opened; the document is parsed directly from the provided bytes.
 func OpenBytes(b []byte) (*Document, error) {
-	rc, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	return OpenBytesWithLimits(b, currentParseLimits())
+}
+
+// OpenBytesWithLimits works like OpenBytes but parses the document using the given ParseLimits
+// instead of DefaultParseLimits.
+func OpenBytesWithLimits(b []byte, limits ParseLimits) (*Document, error) {
+	return OpenReaderAtWithLimits(bytes.NewReader(b), int64(len(b)), limits)
+}
+
+// OpenReaderAt creates a Document from a DOCX archive of the given size, e.g. a blob of a custom storage backend.
+// The archive is read while opening and again when the document is written, r must stay readable until then.
+func OpenReaderAt(r io.ReaderAt, size int64) (*Document, error) {
+	return OpenReaderAtWithLimits(r, size, currentParseLimits())
+}
+
+// OpenReaderAtWithLimits works like OpenReaderAt but parses the document using the given ParseLimits
+// instead of DefaultParseLimits.
+func OpenReaderAtWithLimits(r io.ReaderAt, size int64, limits ParseLimits) (*Document, error) {
+	rc, err := zip.NewReader(r, size)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open ZIP reader: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	return newDocument(rc, "", nil, limits)
+}
+
+// OpenFS creates a Document from the DOCX file with the given name inside the file system, e.g. a template bundled
+// with embed.FS. Files which support random access, like those of embed.FS and os.DirFS, are read in place and
+// stay open until the document is closed, all other files are read into memory first.
+//
+// Example:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	doc, err := docx.OpenFS(templates, "templates/invoice.docx")
+//	defer doc.Close()
+func OpenFS(fsys fs.FS, name string) (*Document, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open DOCX file: %w", err)
+	}
+	readerAt, isReaderAt := file.(io.ReaderAt)
+	if !isReaderAt {
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read DOCX file: %w", err)
+		}
+		return OpenBytes(data)
 	}
 
-	return newDocument(rc, "", nil)
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to stat DOCX file: %w", err)
+	}
+	doc, err := OpenReaderAt(readerAt, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	doc.source = file
+	return doc, nil
 }
 
 // newDocument will create a new document struct given the zipFile.
@@ -85,54 +222,103 @@ func OpenBytes(b []byte) (*Document, error) {
 // newDocument will parse the docx archive and ValidatePositions that at least a 'document.xml' exists.
 // If 'word/document.xml' is missing, an error is returned since the docx cannot be correct.
 // Then all files are parsed for their runs before returning the new document.
-func newDocument(zipFile *zip.Reader, path string, docxFile *os.File) (*Document, error) {
-	doc := &Document{
+// The archive and all XML parts must stay within the given limits.
+func newDocument(zipFile *zip.Reader, path string, docxFile *os.File, limits ParseLimits) (*Document, error) {
+	doc := emptyDocument(zipFile, path, docxFile, limits)
+	if err := doc.load(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// emptyDocument returns a document for the given zipFile whose archive was not yet loaded, see load.
+func emptyDocument(zipFile *zip.Reader, path string, docxFile *os.File, limits ParseLimits) *Document {
+	return &Document{
 		docxFile:         docxFile,
 		zipFile:          zipFile,
 		path:             path,
+		limits:           limits,
 		files:            make(FileMap),
 		runParsers:       make(map[string]*RunParser),
 		filePlaceholders: make(map[string][]*Placeholder),
 		fileReplacers:    make(map[string]*Replacer),
+		modified:         make(map[string]bool),
+		parts:            make(FileMap),
+		relIds:           make(map[string]int),
 	}
+}
 
-	ResetRunIdCounter()
-	ResetFragmentIdCounter()
+// load reads the archive of the document and parses all files.
+func (d *Document) load() error {
+	// the run and fragment IDs are not reset, they are unique across all documents so documents may be opened
+	// and processed concurrently.
 
-	if err := doc.parseArchive(); err != nil {
-		return nil, fmt.Errorf("error parsing archive: %w", err)
+	if err := d.parseArchive(); err != nil {
+		return fmt.Errorf("error parsing archive: %w", err)
 	}
 
 	// a valid docx document should really contain a document.xml :)
-	if _, exists := doc.files[DocumentXml]; !exists {
-		return nil, fmt.Errorf("invalid DOCX archive, %s is missing", DocumentXml)
+	if _, exists := d.files[DocumentXml]; !exists {
+		return fmt.Errorf("%w, %s is missing", ErrInvalidArchive, DocumentXml)
 	}
 
 	// parse all files
-	for name, data := range doc.files {
-
-		// find all runs
-		doc.runParsers[name] = NewRunParser(data)
-		err := doc.runParsers[name].Execute()
-		if err != nil {
-			return nil, err
+	for name, data := range d.files {
+		if err := d.limits.checkXML(name, data); err != nil {
+			return err
 		}
-
-		// parse placeholders and initialize replacers
-		placeholder, err := ParsePlaceholders(doc.runParsers[name].Runs(), data)
-		if err != nil {
-			return nil, err
+		if err := d.parseFile(name); err != nil {
+			return err
 		}
-		doc.filePlaceholders[name] = placeholder
-		doc.fileReplacers[name] = NewReplacer(data, placeholder)
 	}
+	return nil
+}
 
-	return doc, nil
+// parseFile parses the runs and placeholders of the given file and initializes its replacer.
+// It must be called again whenever the structure of the file was changed by other means than the replacer.
+func (d *Document) parseFile(name string) error {
+	data := d.files[name]
+
+	// find all runs
+	d.runParsers[name] = NewRunParser(data)
+	err := d.runParsers[name].Execute()
+	if err != nil {
+		return err
+	}
+
+	// parse placeholders and initialize replacers
+	if d.ignorePlaceholders {
+		d.filePlaceholders[name] = nil
+		d.fileReplacers[name] = NewReplacer(data, nil)
+		return nil
+	}
+	placeholder, err := ParsePlaceholders(d.runParsers[name].Runs(), data)
+	if err != nil {
+		return err
+	}
+	d.filePlaceholders[name] = placeholder
+	d.fileReplacers[name] = NewReplacer(data, placeholder)
+	return nil
 }
 
 // ReplaceAll will iterate over all files and perform the replacement according to the PlaceholderMap.
+// All ComputedValues inside the PlaceholderMap are calculated before replacing.
 func (d *Document) ReplaceAll(placeholderMap PlaceholderMap) error {
+	names := make([]string, 0, len(d.files))
 	for name := range d.files {
+		names = append(names, name)
+	}
+	return d.replaceParts(placeholderMap, names)
+}
+
+// replaceParts replaces the placeholders of the given parts and inserts the footnotes and block values.
+func (d *Document) replaceParts(placeholderMap PlaceholderMap, names []string) error {
+	placeholderMap, err := placeholderMap.resolveComputed()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
 		changedBytes, err := d.replace(placeholderMap, name)
 		if err != nil {
 			return err
@@ -143,7 +329,11 @@ func (d *Document) ReplaceAll(placeholderMap PlaceholderMap) error {
 			return err
 		}
 	}
-	return nil
+	// footnotes are inserted first, they may contain block values as well
+	if err := d.insertFootnotes(); err != nil {
+		return err
+	}
+	return d.insertBlocks()
 }
 
 // Replace will attempt to replace the given key with the value in every file.
@@ -167,7 +357,7 @@ func (d *Document) GetPlaceHoldersList() ([]string, error) {
 
 	for file := range d.files {
 		if _, ok := d.runParsers[file]; !ok {
-			return nil, fmt.Errorf("no parser for file %s", file)
+			return nil, fmt.Errorf("%w: no parser for file %s", ErrUnknownFile, file)
 		}
 		replacer := d.fileReplacers[file]
 		placeholders := replacer.placeholders
@@ -183,14 +373,21 @@ func (d *Document) GetPlaceHoldersList() ([]string, error) {
 // from the placeholderMap.
 func (d *Document) replace(placeholderMap PlaceholderMap, file string) ([]byte, error) {
 	if _, ok := d.runParsers[file]; !ok {
-		return nil, fmt.Errorf("no parser for file %s", file)
+		return nil, fmt.Errorf("%w: no parser for file %s", ErrUnknownFile, file)
+	}
+	// the cached texts of charts are not part of runs, they are replaced up front
+	if ChartPathRegex.MatchString(file) {
+		if err := d.replaceChartTexts(placeholderMap, file); err != nil {
+			return nil, err
+		}
 	}
 	placeholderCount := d.countPlaceholders(file, placeholderMap)
 	placeholders := d.filePlaceholders[file]
 	replacer := d.fileReplacers[file]
+	replaceCount := replacer.ReplaceCount
 
 	for key, value := range placeholderMap {
-		err := replacer.Replace(key, fmt.Sprint(value))
+		err := d.replaceValue(replacer, file, key, value)
 		if err != nil {
 			if errors.Is(err, ErrPlaceholderNotFound) {
 				continue
@@ -201,14 +398,43 @@ func (d *Document) replace(placeholderMap PlaceholderMap, file string) ([]byte,
 	}
 
 	// ensure that all placeholders have been replaced
-	if placeholderCount != replacer.ReplaceCount {
-		return nil, fmt.Errorf("not all placeholders were replaced, want=%d, have=%d", placeholderCount, replacer.ReplaceCount)
+	// the replacer counts all replacements since the file was parsed, only the ones of this call are relevant
+	if replaced := replacer.ReplaceCount - replaceCount; placeholderCount != replaced {
+		return nil, &UnreplacedPlaceholdersError{Part: file, Want: placeholderCount, Have: replaced}
 	}
 
 	d.fileReplacers[file] = replacer
 	d.filePlaceholders[file] = placeholders
+	// the replacer modifies the file bytes in place, SetFile cannot detect the change
+	if replacer.ReplaceCount == replaceCount {
+		return replacer.Bytes(), nil
+	}
+	d.modified[file] = true
+
+	// text which starts or ends with a space after the replacement must preserve it, the offsets of the replacer
+	// are outdated once the text elements are changed
+	data, preserved := preserveEdgeWhitespace(replacer.Bytes())
+	if preserved {
+		d.files[file] = data
+		if err := d.parseFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
 
-	return replacer.Bytes(), nil
+// UnreplacedPlaceholdersError is returned if not all placeholders of a file which match the keys of the
+// replacement values were replaced, e.g. because a placeholder is split in a way the replacer cannot handle.
+type UnreplacedPlaceholdersError struct {
+	// Part is the name of the file, e.g. 'word/document.xml'.
+	Part string
+	// Want is the number of placeholders which should have been replaced, Have the number which was replaced.
+	Want, Have int
+}
+
+// Error implements the error interface.
+func (e *UnreplacedPlaceholdersError) Error() string {
+	return fmt.Sprintf("not all placeholders were replaced in %s, want=%d, have=%d", e.Part, e.Want, e.Have)
 }
 
 // Runs returns all runs from all parsed files.
@@ -230,7 +456,8 @@ func (d *Document) Placeholders() (placeholders []*Placeholder) {
 // countPlaceholders will return the total count of placeholders from the placeholderMap in the given data.
 // Reoccurring placeholders are also counted multiple times.
 func (d *Document) countPlaceholders(file string, placeholderMap PlaceholderMap) int {
-	data := d.GetFile(file)
+	// deleted text of tracked changes is not replaced
+	data := deletedTextRegex.ReplaceAll(d.GetFile(file), nil)
 	plaintext := d.stripXmlTags(string(data))
 	var placeholderCount int
 	for key := range placeholderMap {
@@ -266,8 +493,12 @@ func (d *Document) GetFile(fileName string) []byte {
 // SetFile allows setting the file contents of the given file.
 // The fileName must be known, otherwise an error is returned.
 func (d *Document) SetFile(fileName string, fileBytes []byte) error {
-	if _, exists := d.files[fileName]; !exists {
-		return fmt.Errorf("unregistered file %s", fileName)
+	current, exists := d.files[fileName]
+	if !exists {
+		return fmt.Errorf("%w: unregistered file %s", ErrUnknownFile, fileName)
+	}
+	if !bytes.Equal(current, fileBytes) {
+		d.modified[fileName] = true
 	}
 	d.files[fileName] = fileBytes
 	return nil
@@ -279,37 +510,59 @@ func (d *Document) SetFile(fileName string, fileBytes []byte) error {
 //   - word/document.xml
 //   - word/header*.xml
 //   - word/footer*.xml
+//   - word/footnotes.xml and word/endnotes.xml
+//   - word/charts/chart*.xml
 //   - word/media/*
 func (d *Document) parseArchive() error {
-	readZipFile := func(file *zip.File) []byte {
-		readCloser, err := file.Open()
+	if err := d.limits.checkArchive(d.zipFile); err != nil {
+		return err
+	}
+
+	var totalSize int64
+	readZipFile := func(file *zip.File) ([]byte, error) {
+		fileBytes, err := d.limits.readZipFile(file)
 		if err != nil {
-			return nil
+			return nil, err
 		}
-		defer readCloser.Close()
-		fileBytes, err := io.ReadAll(readCloser)
-		if err != nil {
-			return nil
+		totalSize += int64(len(fileBytes))
+		if d.limits.MaxTotalSize > 0 && totalSize > d.limits.MaxTotalSize {
+			return nil, fmt.Errorf("%w: archive exceeds %d uncompressed bytes", ErrLimitExceeded, d.limits.MaxTotalSize)
 		}
-		return fileBytes
+		return fileBytes, nil
 	}
 
 	for _, file := range d.zipFile.File {
-		if file.Name == DocumentXml {
-			d.files[DocumentXml] = readZipFile(file)
+		isDocument := file.Name == DocumentXml
+		isHeader := HeaderPathRegex.MatchString(file.Name)
+		isFooter := FooterPathRegex.MatchString(file.Name)
+		isMedia := MediaPathRegex.MatchString(file.Name)
+		isNote := file.Name == FootnotesXml || file.Name == EndnotesXml
+		isChart := ChartPathRegex.MatchString(file.Name)
+		if !isDocument && !isHeader && !isFooter && !isMedia && !isNote && !isChart {
+			continue
 		}
-		if HeaderPathRegex.MatchString(file.Name) {
-			d.files[file.Name] = readZipFile(file)
+
+		fileBytes, err := readZipFile(file)
+		if err != nil {
+			return err
+		}
+		d.files[file.Name] = fileBytes
+
+		if isHeader {
 			d.headerFiles = append(d.headerFiles, file.Name)
 		}
-		if FooterPathRegex.MatchString(file.Name) {
-			d.files[file.Name] = readZipFile(file)
+		if isFooter {
 			d.footerFiles = append(d.footerFiles, file.Name)
 		}
-		if MediaPathRegex.MatchString(file.Name) {
-			d.files[file.Name] = readZipFile(file)
+		if isMedia {
 			d.mediaFiles = append(d.mediaFiles, file.Name)
 		}
+		if isNote {
+			d.noteFiles = append(d.noteFiles, file.Name)
+		}
+		if isChart {
+			d.chartFiles = append(d.chartFiles, file.Name)
+		}
 	}
 	return nil
 }
@@ -340,12 +593,41 @@ func (d *Document) WriteToFile(file string) error {
 // Docx files are basically zip archives with many XMLs included.
 // Files which cannot be modified through this lib will just be read from the original docx and copied into the writer.
 func (d *Document) Write(writer io.Writer) error {
+	if err := d.prepareWrite(); err != nil {
+		return err
+	}
 	zipWriter := zip.NewWriter(writer)
 	defer zipWriter.Close()
 
+	return d.writeArchive(zipWriter)
+}
+
+// prepareWrite applies the timestamp policy and the value checksums and checks the output limits and the
+// relationships, it must be called before the archive is written.
+func (d *Document) prepareWrite() error {
+	if err := d.applyTimestampPolicy(); err != nil {
+		return err
+	}
+	if err := d.storeValueChecksums(); err != nil {
+		return err
+	}
+	if err := d.checkComplexity(); err != nil {
+		return err
+	}
+	return d.validateRelationships()
+}
+
+// writeArchive writes all files of the document into the given zipWriter without closing it.
+func (d *Document) writeArchive(zipWriter *zip.Writer) error {
 	// writeModifiedFile will check if the given zipFile is a file which was modified and writes it.
 	// If the file is not one of the modified files, false is returned.
 	writeModifiedFile := func(writer io.Writer, zipFile *zip.File) (bool, error) {
+		if _, isPart := d.parts[zipFile.Name]; isPart {
+			if err := d.parts.Write(writer, zipFile.Name); err != nil {
+				return false, fmt.Errorf("unable to writeFile %s: %s", zipFile.Name, err)
+			}
+			return true, nil
+		}
 		isModified := d.isModifiedFile(zipFile.Name)
 		if !isModified {
 			return false, nil
@@ -386,13 +668,88 @@ func (d *Document) Write(writer io.Writer) error {
 			return fmt.Errorf("unable to close reader for %s: %s", zipFile.Name, err)
 		}
 	}
+
+	// parts which were added to the document do not exist inside the original archive
+	for _, name := range d.newParts {
+		if media, stored := d.storedMedia[name]; stored {
+			if err := media.writeTo(zipWriter, name); err != nil {
+				return err
+			}
+			continue
+		}
+		fw, err := zipWriter.Create(name)
+		if err != nil {
+			return fmt.Errorf("unable to create writer: %s", err)
+		}
+		source := d.parts
+		if _, isFile := d.files[name]; isFile {
+			source = d.files
+		}
+		if err := source.Write(fw, name); err != nil {
+			return fmt.Errorf("unable to writeFile %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// WriteInPlace saves the changes into the DOCX file from which the document was opened.
+// Only the modified parts are written, all other parts of the archive are left untouched on disk, see PatchZipFile.
+// This makes small changes to huge documents fast, but the space of the replaced parts is not reclaimed.
+// ErrPatchUnsupported is returned if the document was not opened from a file or the file cannot be patched,
+// WriteToFile must be used in that case.
+func (d *Document) WriteInPlace() error {
+	if d.docxFile == nil {
+		return fmt.Errorf("%w: document was not opened from a file", ErrPatchUnsupported)
+	}
+	if err := d.applyTimestampPolicy(); err != nil {
+		return err
+	}
+	if err := d.storeValueChecksums(); err != nil {
+		return err
+	}
+	if err := d.checkComplexity(); err != nil {
+		return err
+	}
+	if err := d.validateRelationships(); err != nil {
+		return err
+	}
+
+	changed := make(FileMap, len(d.modified))
+	for name := range d.modified {
+		if data, exists := d.files[name]; exists {
+			changed[name] = data
+		} else {
+			changed[name] = d.parts[name]
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if err := PatchZipFile(d.path, changed); err != nil {
+		return err
+	}
+
+	// the archive now contains all changed and added parts
+	info, err := d.docxFile.Stat()
+	if err != nil {
+		return err
+	}
+	zipFile, err := zip.NewReader(d.docxFile, info.Size())
+	if err != nil {
+		return fmt.Errorf("unable to open patched archive: %w", err)
+	}
+	d.zipFile = zipFile
+	d.modified = make(map[string]bool)
+	d.newParts = nil
 	return nil
 }
 
 // isModifiedFile will look through all modified files and check if the searchFileName exists
 func (d *Document) isModifiedFile(searchFileName string) bool {
 	allFiles := append(d.headerFiles, d.footerFiles...)
+	allFiles = append(allFiles, d.noteFiles...)
 	allFiles = append(allFiles, d.mediaFiles...)
+	allFiles = append(allFiles, d.chartFiles...)
 	allFiles = append(allFiles, DocumentXml)
 
 	for _, file := range allFiles {
@@ -412,6 +769,11 @@ func (d *Document) Close() {
 			log.Println(err)
 		}
 	}
+	if d.source != nil {
+		if err := d.source.Close(); err != nil {
+			log.Println(err)
+		}
+	}
 }
 
 // FileMap is just a convenience type for the map of fileName => fileBytes
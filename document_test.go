@@ -1,6 +1,13 @@
 package docx
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
 
 func BenchmarkDocument_ReplaceAll(b *testing.B) {
 	for n := 0; n < b.N; n++ {
@@ -33,3 +40,87 @@ func BenchmarkDocument_ReplaceAll(b *testing.B) {
 		}
 	}
 }
+
+// readOnlyFS hides all methods of the files of a file system besides those of fs.File.
+type readOnlyFS struct {
+	fs.FS
+}
+
+func (r readOnlyFS) Open(name string) (fs.File, error) {
+	file, err := r.FS.Open(name)
+	return struct{ fs.File }{file}, err
+}
+
+func TestOpenFS(t *testing.T) {
+	template := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}</w:t></w:r></w:p>`)
+	fsys := fstest.MapFS{"templates/letter.docx": {Data: template}}
+
+	open := map[string]func() (*Document, error){
+		"reader at": func() (*Document, error) { return OpenReaderAt(bytes.NewReader(template), int64(len(template))) },
+		"fs":        func() (*Document, error) { return OpenFS(fsys, "templates/letter.docx") },
+		"read only": func() (*Document, error) { return OpenFS(readOnlyFS{fsys}, "templates/letter.docx") },
+	}
+	for name, open := range open {
+		doc, err := open()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane"}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		doc.Close()
+		if document := readTestPart(t, buf.Bytes(), DocumentXml); !strings.Contains(document, "Dear Jane") {
+			t.Errorf("%s: expected replaced placeholder in %s", name, document)
+		}
+	}
+
+	if _, err := OpenFS(fsys, "templates/missing.docx"); err == nil {
+		t.Error("expected error for missing file")
+	}
+	fsys["broken.docx"] = &fstest.MapFile{Data: []byte("no zip")}
+	if _, err := OpenFS(fsys, "broken.docx"); err == nil {
+		t.Error("expected error for invalid archive")
+	}
+}
+
+func TestOpenBytes_InvalidArchive(t *testing.T) {
+	withoutDocument, err := rewriteArchive(buildTestDocx(t, ""), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutDocument = bytes.Replace(withoutDocument, []byte(DocumentXml), []byte("word/missing.xml"), -1)
+
+	for name, input := range map[string][]byte{
+		"no archive":       []byte("not a zip archive"),
+		"missing document": withoutDocument,
+	} {
+		if _, err := OpenBytes(input); !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("%s: expected ErrInvalidArchive, have %v", name, err)
+		}
+	}
+	if _, err := ProcessTemplateDocx([]byte("not a zip archive"), nil); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("expected ErrInvalidArchive from ProcessTemplateDocx, have %v", err)
+	}
+}
+
+func TestOpenBytes_SentinelErrors(t *testing.T) {
+	if _, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>}foo{</w:t></w:r></w:p>`)); !errors.Is(err, ErrUnbalancedDelimiter) {
+		t.Errorf("expected ErrUnbalancedDelimiter, have %v", err)
+	}
+	if _, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>foo</w:p>`)); !errors.Is(err, ErrMalformedXML) {
+		t.Errorf("expected ErrMalformedXML, have %v", err)
+	}
+
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if err := doc.SetFile("word/missing.xml", nil); !errors.Is(err, ErrUnknownFile) {
+		t.Errorf("expected ErrUnknownFile, have %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package docx
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+var (
+	// paragraphStyleRegex matches the style of paragraph properties and captures the style ID.
+	paragraphStyleRegex = regexp.MustCompile(`<w:pStyle\s+w:val="([^"]*)"`)
+	// inlineContentRegex matches the content of paragraphs which is part of a dry run: texts (captured), line breaks
+	// (captures the attributes), tabs, drawings (captures the attributes of the drawing properties) and the start
+	// (captures the attributes) and end of hyperlinks.
+	inlineContentRegex = regexp.MustCompile(`<w:t(?:\s[^>]*)?>([^<]*)</w:t>|<w:br(\s[^>]*)?/>|<w:cr/>|<w:tab/>|` +
+		`<wp:docPr(\s[^>]*?)/?>|<w:hyperlink(\s[^>]*)?>|</w:hyperlink>`)
+	// noteTypeRegex matches the type of footnotes and endnotes, which is only set for separators.
+	noteTypeRegex = regexp.MustCompile(`^<w:(?:footnote|endnote)\s[^>]*w:type=`)
+)
+
+// DryRunNode types.
+const (
+	DryRunParagraph = "paragraph"
+	DryRunTable     = "table"
+	DryRunRow       = "row"
+	DryRunCell      = "cell"
+	DryRunNote      = "note"
+	DryRunText      = "text"
+	DryRunPageBreak = "pagebreak"
+	DryRunImage     = "image"
+	DryRunLink      = "link"
+)
+
+// DryRun is the structure of a rendered template, see DryRunTemplate.
+type DryRun struct {
+	Parts []DryRunPart `json:"parts"`
+}
+
+// DryRunPart is the content of a single part of the rendered document, e.g. the body or a header.
+type DryRunPart struct {
+	// Name is the name of the part inside the archive, e.g. 'word/document.xml'.
+	Name    string        `json:"name"`
+	Kind    PartKind      `json:"kind"`
+	Content []*DryRunNode `json:"content"`
+}
+
+// DryRunNode is a paragraph, a table (with rows and cells), a note or the inline content of a paragraph.
+//
+// Paragraphs contain text, page break, image (the description as text) and link (the URL or the bookmark as target)
+// nodes; their text is the plain text of the whole paragraph with line breaks as '\n' and tabs as '\t'. Tables contain rows, rows
+// contain cells, cells and notes contain paragraphs and tables.
+type DryRunNode struct {
+	Type string `json:"type"`
+	// Style is the style ID of paragraphs, if any.
+	Style    string        `json:"style,omitempty"`
+	Text     string        `json:"text,omitempty"`
+	Target   string        `json:"target,omitempty"`
+	Children []*DryRunNode `json:"children,omitempty"`
+}
+
+// DryRunTemplate executes the template logic of the DOCX template given by input against data exactly like
+// ProcessTemplateDocx, but returns the structure of the result instead of a document: the paragraphs and tables of
+// the body, headers, footers and notes with the text and values which were placed. This allows testing the business
+// logic of templates and reviewing the result without binary artifacts, e.g. by comparing the JSON of the result
+// with a golden file.
+//
+// Example:
+//
+//	result, err := docx.DryRunTemplate(templateBytes, data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	jsonBytes, err := result.JSON()
+func DryRunTemplate(input []byte, data interface{}) (*DryRun, error) {
+	return DryRunTemplateWithFuncs(input, data, nil)
+}
+
+// DryRunTemplateWithFuncs works like DryRunTemplate but makes the given functions available to the template,
+// see ProcessTemplateDocxWithFuncs.
+func DryRunTemplateWithFuncs(input []byte, data interface{}, funcs template.FuncMap) (*DryRun, error) {
+	output, err := ProcessTemplateDocxWithFuncs(input, data, funcs)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := OpenBytes(output)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	names := append([]string{DocumentXml}, slices.Sorted(slices.Values(doc.headerFiles))...)
+	names = append(names, slices.Sorted(slices.Values(doc.footerFiles))...)
+	names = append(names, doc.noteFiles...)
+
+	result := &DryRun{}
+	for _, name := range names {
+		rels, _, err := doc.part(relationshipsPart(name))
+		if err != nil {
+			return nil, err
+		}
+		targets := make(map[string]string)
+		for _, rel := range parseRelationships(rels) {
+			targets[rel.id] = rel.target
+		}
+
+		content := string(doc.files[name])
+		if name == DocumentXml {
+			if match := BodyContentRegex.FindStringSubmatch(content); match != nil {
+				content = match[1]
+			}
+		} else if root := childElements(content); len(root) > 0 {
+			_, content, _ = splitElement(content[root[0].start:root[0].end])
+		}
+		result.Parts = append(result.Parts, DryRunPart{Name: name, Kind: partKind(name), Content: dryRunBlocks(content, targets)})
+	}
+	return result, nil
+}
+
+// JSON returns the indented JSON of the dry run.
+func (r *DryRun) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// dryRunBlocks returns the nodes of the paragraphs, tables and notes of the content. The content of other elements,
+// e.g. content controls, is included as if it was not wrapped. Targets maps relationship IDs to their target.
+func dryRunBlocks(content string, targets map[string]string) []*DryRunNode {
+	var nodes []*DryRunNode
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch child.name {
+		case "p":
+			nodes = append(nodes, dryRunParagraph(element, targets))
+		case "tbl":
+			nodes = append(nodes, dryRunContainer(DryRunTable, element, "tr", func(row string) *DryRunNode {
+				return dryRunContainer(DryRunRow, row, "tc", func(cell string) *DryRunNode {
+					_, cellContent, _ := splitElement(cell)
+					return &DryRunNode{Type: DryRunCell, Children: dryRunBlocks(cellContent, targets)}
+				})
+			}))
+		case "footnote", "endnote":
+			if noteTypeRegex.MatchString(element) {
+				continue // separators
+			}
+			_, noteContent, _ := splitElement(element)
+			nodes = append(nodes, &DryRunNode{Type: DryRunNote, Children: dryRunBlocks(noteContent, targets)})
+		case "sectPr", "tblPr", "tblGrid", "trPr", "tcPr", "sdtPr", "sdtEndPr", "bookmarkStart", "bookmarkEnd":
+		default:
+			if _, inner, _ := splitElement(element); inner != "" {
+				nodes = append(nodes, dryRunBlocks(inner, targets)...)
+			}
+		}
+	}
+	return nodes
+}
+
+// dryRunContainer returns the node of a table or row whose children of the given name are converted by child.
+func dryRunContainer(nodeType, element, childName string, child func(string) *DryRunNode) *DryRunNode {
+	node := &DryRunNode{Type: nodeType}
+	_, content, _ := splitElement(element)
+	for _, c := range childElements(content) {
+		if c.name == childName {
+			node.Children = append(node.Children, child(content[c.start:c.end]))
+		}
+	}
+	return node
+}
+
+// dryRunParagraph returns the node of the paragraph with its inline content.
+func dryRunParagraph(paragraph string, targets map[string]string) *DryRunNode {
+	node := &DryRunNode{Type: DryRunParagraph}
+	properties := paragraphPropertiesRegex.FindString(paragraph)
+	if match := paragraphStyleRegex.FindStringSubmatch(properties); match != nil {
+		node.Style = html.UnescapeString(match[1])
+	}
+
+	var text strings.Builder
+	var link *DryRunNode
+	addText := func(s string) {
+		text.WriteString(s)
+		if link != nil {
+			link.Text += s
+			return
+		}
+		if last := len(node.Children) - 1; last >= 0 && node.Children[last].Type == DryRunText {
+			node.Children[last].Text += s
+			return
+		}
+		node.Children = append(node.Children, &DryRunNode{Type: DryRunText, Text: s})
+	}
+
+	for _, match := range inlineContentRegex.FindAllStringSubmatchIndex(paragraph[len(properties):], -1) {
+		item := paragraph[len(properties)+match[0] : len(properties)+match[1]]
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return paragraph[len(properties)+match[2*i] : len(properties)+match[2*i+1]]
+		}
+		switch {
+		case item == "<w:tab/>":
+			addText("\t")
+		case strings.HasPrefix(item, "<w:t"):
+			if s := html.UnescapeString(group(1)); s != "" {
+				addText(s)
+			}
+		case strings.HasPrefix(item, "<w:br"):
+			if strings.Contains(group(2), `w:type="page"`) {
+				node.Children = append(node.Children, &DryRunNode{Type: DryRunPageBreak})
+			} else {
+				addText("\n")
+			}
+		case item == "<w:cr/>":
+			addText("\n")
+		case strings.HasPrefix(item, "<wp:docPr"):
+			image := &DryRunNode{Type: DryRunImage}
+			for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(group(3), -1) {
+				if attribute[1] == "descr" {
+					image.Text = html.UnescapeString(attribute[2])
+				}
+			}
+			node.Children = append(node.Children, image)
+		case item == "</w:hyperlink>":
+			link = nil
+		default:
+			link = &DryRunNode{Type: DryRunLink}
+			for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(group(4), -1) {
+				switch attribute[1] {
+				case "r:id":
+					link.Target = targets[attribute[2]]
+				case "w:anchor":
+					link.Target = "#" + html.UnescapeString(attribute[2])
+				}
+			}
+			node.Children = append(node.Children, link)
+		}
+	}
+	node.Text = text.String()
+	return node
+}
@@ -0,0 +1,73 @@
+package docx
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDryRunTemplate(t *testing.T) {
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{{.Company}}</w:t></w:r></w:p></w:hdr>`
+	input := buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Invoice {{.Number}}</w:t></w:r></w:p>`+
+			`<w:tbl><w:tblPr/><w:tr><w:tc><w:p><w:r><w:t>{{range .Items}}{{.}}</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>x{{end}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:t>{{if .Paid}}Paid{{else}}Open{{end}}</w:t></w:r><w:r><w:tab/><w:t>{{.Link}}</w:t></w:r><w:r><w:t>{{pagebreak}}</w:t></w:r></w:p>`+
+			`<w:sectPr/>`,
+		"word/header1.xml", header)
+
+	result, err := DryRunTemplate(input, map[string]interface{}{
+		"Company": "ACME & Co",
+		"Number":  42,
+		"Items":   []string{"Apples", "Pears"},
+		"Paid":    false,
+		"Link":    Hyperlink{Text: "pay now", URL: "https://example.com/pay"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Parts) != 2 || result.Parts[0].Kind != PartBody || result.Parts[1].Name != "word/header1.xml" {
+		t.Fatalf("unexpected parts %+v", result.Parts)
+	}
+	body := result.Parts[0].Content
+	if len(body) != 3 {
+		t.Fatalf("expected heading, table and paragraph, have %d nodes", len(body))
+	}
+	if body[0].Type != DryRunParagraph || body[0].Style != "Heading1" || body[0].Text != "Invoice 42" {
+		t.Errorf("unexpected heading %+v", body[0])
+	}
+	table := body[1]
+	if table.Type != DryRunTable || len(table.Children) != 2 || table.Children[1].Type != DryRunRow {
+		t.Fatalf("expected a table with two rows, have %+v", table)
+	}
+	if cell := table.Children[1].Children[0]; cell.Type != DryRunCell || cell.Children[0].Text != "Pears" {
+		t.Errorf("unexpected cell %+v", cell.Children[0])
+	}
+
+	paragraph := body[2]
+	if paragraph.Text != "Open\tpay now" {
+		t.Errorf("unexpected text %q", paragraph.Text)
+	}
+	var types []string
+	for _, child := range paragraph.Children {
+		types = append(types, child.Type)
+	}
+	if strings.Join(types, ",") != "text,link,pagebreak" || paragraph.Children[1].Target != "https://example.com/pay" {
+		t.Errorf("unexpected inline content %s: %+v", types, paragraph.Children)
+	}
+	if text := result.Parts[1].Content[0].Text; text != "ACME & Co" {
+		t.Errorf("unexpected header %q", text)
+	}
+
+	jsonBytes, err := result.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DryRun
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(jsonBytes), `"type": "table"`) || decoded.Parts[0].Content[0].Text != "Invoice 42" {
+		t.Errorf("unexpected JSON %s", jsonBytes)
+	}
+}
@@ -0,0 +1,79 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// xmlTagRegex matches open, close and self-closing tags and captures the closing slash, the qualified name and
+	// the self-closing slash.
+	xmlTagRegex = regexp.MustCompile(`<(/?)([A-Za-z_][\w.-]*(?::[A-Za-z_][\w.-]*)?)(?:\s[^>]*?)?(/?)>`)
+)
+
+// childElement is a direct child element inside an XML fragment, described by byte offsets.
+type childElement struct {
+	// name is the local name of the element, without namespace prefix.
+	name       string
+	start, end int
+}
+
+// childElements returns all top-level elements of the XML fragment in document order.
+func childElements(content string) []childElement {
+	var children []childElement
+	depth := 0
+	var current childElement
+	for _, match := range xmlTagRegex.FindAllStringSubmatchIndex(content, -1) {
+		closing := match[3] > match[2]
+		selfClosing := match[7] > match[6]
+		name := content[match[4]:match[5]]
+		if pos := strings.IndexByte(name, ':'); pos >= 0 {
+			name = name[pos+1:]
+		}
+
+		switch {
+		case closing:
+			depth--
+			if depth == 0 {
+				current.end = match[1]
+				children = append(children, current)
+			}
+		case selfClosing:
+			if depth == 0 {
+				children = append(children, childElement{name: name, start: match[0], end: match[1]})
+			}
+		default:
+			if depth == 0 {
+				current = childElement{name: name, start: match[0]}
+			}
+			depth++
+		}
+	}
+	return children
+}
+
+// setOrderedElement sets the element of the given local name inside content, the children of an XML element whose
+// children must appear in the given order. An existing element of the same name is replaced, otherwise the element
+// is inserted in front of the first child which must follow it. Unknown children are expected at the end.
+func setOrderedElement(content string, order []string, name, element string) string {
+	rank := elementRank(order, name)
+	for _, child := range childElements(content) {
+		if child.name == name {
+			return content[:child.start] + element + content[child.end:]
+		}
+		if elementRank(order, child.name) > rank {
+			return content[:child.start] + element + content[child.start:]
+		}
+	}
+	return content + element
+}
+
+// elementRank returns the position of the element in the given order, unknown elements are ranked last.
+func elementRank(order []string, name string) int {
+	for i, n := range order {
+		if n == name {
+			return i
+		}
+	}
+	return len(order)
+}
@@ -0,0 +1,317 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultFetchConcurrency is the number of concurrent downloads of a Fetcher if FetchLimits.MaxConcurrent is zero.
+	DefaultFetchConcurrency = 4
+	// DefaultFetchTimeout is the timeout of a single download if FetchLimits.Timeout is zero.
+	DefaultFetchTimeout = 30 * time.Second
+)
+
+// ErrFetchDenied is returned by a Fetcher if the URL is not allowed, e.g. because its host is not on the allow list
+// or it resolves to an address of a private network.
+var ErrFetchDenied = errors.New("fetching the resource is not allowed")
+
+// FetchLimits restrict the downloads of a Fetcher.
+type FetchLimits struct {
+	// MaxConcurrent is the maximum number of concurrent downloads, DefaultFetchConcurrency if zero.
+	MaxConcurrent int
+	// RequestsPerSecond is the maximum number of requests per second to the same host, unlimited if zero.
+	RequestsPerSecond float64
+	// AllowHosts are the hosts from which resources may be fetched, all hosts if empty. '*.example.com' allows all
+	// subdomains of example.com.
+	AllowHosts []string
+	// DenyHosts are hosts from which resources must not be fetched, in the same format as AllowHosts. They take
+	// precedence over AllowHosts.
+	DenyHosts []string
+	// AllowPrivateNetworks allows hosts which resolve to loopback, private or link-local addresses. They are refused
+	// by default, so templates and data cannot reach internal services (server side request forgery).
+	AllowPrivateNetworks bool
+	// MaxSize is the maximum size of a single resource in bytes, DefaultParseLimits.MaxTotalSize if zero.
+	MaxSize int64
+	// Timeout is the timeout of a single download including redirects, DefaultFetchTimeout if zero.
+	Timeout time.Duration
+}
+
+// Fetcher downloads external resources, e.g. remote images and fragments, for renders. It is the central place to
+// restrict what templates and data may pull from the network: the downloads are limited in number, rate and size,
+// only http and https URLs of allowed hosts are fetched and redirects are checked like the original URL.
+// The total size of the downloads of a render is limited by WithFetchBudget. A Fetcher is safe for concurrent use,
+// a service usually shares one Fetcher between all renders.
+type Fetcher struct {
+	client *http.Client
+	limits FetchLimits
+	// slots limits the concurrent downloads
+	slots chan struct{}
+
+	mu sync.Mutex
+	// next maps hosts to the earliest time of their next request
+	next map[string]time.Time
+}
+
+// NewFetcher returns a Fetcher with the given limits.
+//
+// Example:
+//
+//	fetcher := docx.NewFetcher(docx.FetchLimits{
+//	    AllowHosts:        []string{"cdn.example.com", "*.images.example.com"},
+//	    RequestsPerSecond: 10,
+//	    MaxSize:           5 << 20,
+//	})
+//	ctx := docx.WithFetchBudget(r.Context(), 20<<20) // per render
+//	logo, err := fetcher.Image(ctx, data.LogoURL)
+func NewFetcher(limits FetchLimits) *Fetcher {
+	if limits.MaxConcurrent <= 0 {
+		limits.MaxConcurrent = DefaultFetchConcurrency
+	}
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = currentParseLimits().MaxTotalSize
+	}
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultFetchTimeout
+	}
+	f := &Fetcher{limits: limits, slots: make(chan struct{}, limits.MaxConcurrent), next: make(map[string]time.Time)}
+
+	dialer := &net.Dialer{Timeout: limits.Timeout, Control: f.checkAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // the addresses are checked when dialing, a proxy would hide them
+	transport.DialContext = dialer.DialContext
+	f.client = &http.Client{
+		Transport: transport,
+		Timeout:   limits.Timeout,
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return f.checkURL(request.URL)
+		},
+	}
+	return f
+}
+
+// Fetch returns the resource at the URL. ErrFetchDenied is returned if the URL is not allowed, ErrLimitExceeded if
+// the resource exceeds FetchLimits.MaxSize or the budget of the context, see WithFetchBudget.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	response, release, err := f.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s for %s", response.Status, rawURL)
+	}
+	return f.read(ctx, response, rawURL)
+}
+
+// Image returns the remote image at the URL as replacement value, see Fetch.
+func (f *Fetcher) Image(ctx context.Context, rawURL string) (Image, error) {
+	data, err := f.Fetch(ctx, rawURL)
+	if err != nil {
+		return Image{}, fmt.Errorf("unable to fetch image: %w", err)
+	}
+	return Image{Data: data}, nil
+}
+
+// get sends a GET request to the URL once a download slot is free and the rate limit of the host allows it.
+// The release function must be called after the body of the response was read.
+func (f *Fetcher) get(ctx context.Context, rawURL string) (*http.Response, func(), error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid URL %q", ErrFetchDenied, rawURL)
+	}
+	if err := f.checkURL(target); err != nil {
+		return nil, nil, err
+	}
+	if err := budgetOf(ctx).check(); err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case f.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+	release := func() { <-f.slots }
+	if err := f.wait(ctx, strings.ToLower(target.Hostname())); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	response, err := f.client.Do(request)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return response, func() {
+		response.Body.Close()
+		release()
+	}, nil
+}
+
+// read returns the body of the response within the size limit and the budget of the context.
+func (f *Fetcher) read(ctx context.Context, response *http.Response, rawURL string) ([]byte, error) {
+	budget := budgetOf(ctx)
+	maxSize := f.limits.MaxSize
+	if budget != nil {
+		maxSize = min(maxSize, budget.remaining())
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.limits.MaxSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrLimitExceeded, rawURL, f.limits.MaxSize)
+	}
+	if err := budget.spend(int64(len(data))); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, rawURL)
+	}
+	return data, nil
+}
+
+// wait blocks until the rate limit allows the next request to the host.
+func (f *Fetcher) wait(ctx context.Context, host string) error {
+	if f.limits.RequestsPerSecond <= 0 {
+		return nil
+	}
+	f.mu.Lock()
+	now := time.Now()
+	at := now
+	if next := f.next[host]; next.After(now) {
+		at = next
+	}
+	f.next[host] = at.Add(time.Duration(float64(time.Second) / f.limits.RequestsPerSecond))
+	f.mu.Unlock()
+	if !at.After(now) {
+		return nil
+	}
+
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// checkURL returns ErrFetchDenied if the scheme or the host of the URL is not allowed.
+func (f *Fetcher) checkURL(target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported scheme of %s", ErrFetchDenied, target.Redacted())
+	}
+	host := strings.ToLower(target.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: %s has no host", ErrFetchDenied, target.Redacted())
+	}
+	if matchesHost(f.limits.DenyHosts, host) {
+		return fmt.Errorf("%w: host %s is denied", ErrFetchDenied, host)
+	}
+	if len(f.limits.AllowHosts) > 0 && !matchesHost(f.limits.AllowHosts, host) {
+		return fmt.Errorf("%w: host %s is not allowed", ErrFetchDenied, host)
+	}
+	return nil
+}
+
+// checkAddress refuses connections to addresses of private networks, it is called for every dialed address so host
+// names resolving to different addresses later cannot bypass the check.
+func (f *Fetcher) checkAddress(network, address string, _ syscall.RawConn) error {
+	if f.limits.AllowPrivateNetworks {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: address %s is private", ErrFetchDenied, host)
+	}
+	return nil
+}
+
+// matchesHost returns true if the host matches one of the patterns, see FetchLimits.AllowHosts.
+func matchesHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if suffix, isWildcard := strings.CutPrefix(pattern, "*."); isWildcard {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchBudgetKey is the context key of the fetch budget.
+type fetchBudgetKey struct{}
+
+// fetchBudget is the number of bytes which may still be downloaded by a render.
+type fetchBudget struct {
+	mu    sync.Mutex
+	total int64
+	left  int64
+}
+
+// WithFetchBudget returns a context which limits the total size of all downloads of Fetchers using it to maxBytes,
+// e.g. the downloads of a single render. Without budget, only the size of every single resource is limited.
+func WithFetchBudget(ctx context.Context, maxBytes int64) context.Context {
+	return context.WithValue(ctx, fetchBudgetKey{}, &fetchBudget{total: maxBytes, left: maxBytes})
+}
+
+// budgetOf returns the fetch budget of the context, nil if it has none.
+func budgetOf(ctx context.Context) *fetchBudget {
+	budget, _ := ctx.Value(fetchBudgetKey{}).(*fetchBudget)
+	return budget
+}
+
+// check returns ErrLimitExceeded if the budget is spent.
+func (b *fetchBudget) check() error {
+	if b != nil && b.remaining() <= 0 {
+		return fmt.Errorf("%w: fetch budget of %d bytes is spent", ErrLimitExceeded, b.total)
+	}
+	return nil
+}
+
+// remaining returns the number of bytes which may still be downloaded.
+func (b *fetchBudget) remaining() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.left
+}
+
+// spend subtracts the size of a download from the budget, ErrLimitExceeded is returned if it does not fit.
+func (b *fetchBudget) spend(size int64) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if size > b.left {
+		b.left = 0
+		return fmt.Errorf("%w: fetch budget of %d bytes exceeded", ErrLimitExceeded, b.total)
+	}
+	b.left -= size
+	return nil
+}
@@ -0,0 +1,117 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetcher(t *testing.T) {
+	var active, maxActive atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			current := maxActive.Load()
+			if n <= current || maxActive.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		switch r.URL.Path {
+		case "/small":
+			w.Write([]byte("0123456789"))
+		case "/large":
+			w.Write(bytes.Repeat([]byte("x"), 100))
+		case "/redirect":
+			http.Redirect(w, r, "http://denied.example.com/small", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+
+	fetcher := NewFetcher(FetchLimits{MaxConcurrent: 2, AllowPrivateNetworks: true, MaxSize: 50, AllowHosts: []string{host}})
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if data, err := fetcher.Fetch(ctx, server.URL+"/small"); err != nil || string(data) != "0123456789" {
+				t.Errorf("expected resource, have %q, %v", data, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxActive.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent downloads, have %d", maxActive.Load())
+	}
+
+	if _, err := fetcher.Fetch(ctx, server.URL+"/large"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for large resource, have %v", err)
+	}
+	if _, err := fetcher.Fetch(ctx, server.URL+"/missing"); err == nil {
+		t.Error("expected error for missing resource")
+	}
+	for _, denied := range []string{"http://other.example.com/small", "file:///etc/passwd", server.URL + "/redirect"} {
+		if _, err := fetcher.Fetch(ctx, denied); !errors.Is(err, ErrFetchDenied) {
+			t.Errorf("%s: expected ErrFetchDenied, have %v", denied, err)
+		}
+	}
+
+	budget := WithFetchBudget(ctx, 25)
+	for i, expected := range []error{nil, nil, ErrLimitExceeded, ErrLimitExceeded} {
+		if _, err := fetcher.Fetch(budget, server.URL+"/small"); !errors.Is(err, expected) {
+			t.Errorf("download %d: expected %v, have %v", i, expected, err)
+		}
+	}
+
+	private := NewFetcher(FetchLimits{})
+	if _, err := private.Image(ctx, server.URL+"/small"); !errors.Is(err, ErrFetchDenied) {
+		t.Errorf("expected ErrFetchDenied for private address, have %v", err)
+	}
+	denied := NewFetcher(FetchLimits{AllowPrivateNetworks: true, DenyHosts: []string{"*.example.com", host}})
+	if _, err := denied.Fetch(ctx, server.URL+"/small"); !errors.Is(err, ErrFetchDenied) {
+		t.Errorf("expected ErrFetchDenied for denied host, have %v", err)
+	}
+
+	limited := NewFetcher(FetchLimits{AllowPrivateNetworks: true, RequestsPerSecond: 20})
+	start := time.Now()
+	for range 3 {
+		if _, err := limited.Fetch(ctx, server.URL+"/small"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected rate limited requests, took %s", elapsed)
+	}
+
+	provider := &HTTPFragmentProvider{BaseURL: server.URL, Fetcher: fetcher}
+	if _, err := provider.Fragment(ctx, "missing", ""); !errors.Is(err, ErrFragmentNotFound) {
+		t.Errorf("expected ErrFragmentNotFound, have %v", err)
+	}
+}
+
+func TestMatchesHost(t *testing.T) {
+	patterns := []string{"cdn.example.com", "*.Images.example.com"}
+	for host, expected := range map[string]bool{
+		"cdn.example.com":      true,
+		"a.images.example.com": true,
+		"images.example.com":   false,
+		"example.com":          false,
+		"evilcdn.example.com":  false,
+	} {
+		if have := matchesHost(patterns, host); have != expected {
+			t.Errorf("%s: expected %v, have %v", host, expected, have)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package docx
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// SimpleFieldRegex matches simple fields (<w:fldSimple w:instr="...">...</w:fldSimple>), capturing the instruction
+	// and the field result.
+	SimpleFieldRegex = regexp.MustCompile(`(?s)<w:fldSimple\s[^>]*?w:instr="([^"]*)"[^>]*?(?:/>|>(.*?)</w:fldSimple>)`)
+	// InstrTextRegex matches the instruction text of complex fields and captures the text.
+	InstrTextRegex = regexp.MustCompile(`<w:instrText(?:\s[^>]*)?>([^<]*)</w:instrText>`)
+	// FieldCharRegex matches the field characters which delimit complex fields and captures their type.
+	FieldCharRegex = regexp.MustCompile(`<w:fldChar\s[^>]*?w:fldCharType="(begin|separate|end)"`)
+)
+
+// field is a Word field inside an XML part, described by byte offsets.
+// Fields are either simple (<w:fldSimple>) or complex, spanning several runs delimited by <w:fldChar> elements.
+type field struct {
+	Position
+	// Instruction is the XML unescaped field instruction, e.g. ` MERGEFIELD name \* Upper `.
+	Instruction string
+	// Result is the position of the current field result. It is empty if the field has no result.
+	Result Position
+	// RunProperties are the properties of the first run of the field which can be used to render the result.
+	RunProperties string
+	// Depth is the nesting level of the field, top-level fields have a depth of 0.
+	Depth int
+	// EndRun is the start of the run holding the end field character of a complex field, 0 for simple fields.
+	EndRun int64
+}
+
+// parseFields returns all simple and complex fields inside the given XML part, ordered by their start position.
+func parseFields(data []byte) ([]*field, error) {
+	var fields []*field
+
+	for _, match := range SimpleFieldRegex.FindAllSubmatchIndex(data, -1) {
+		f := &field{
+			Position:    Position{Start: int64(match[0]), End: int64(match[1])},
+			Instruction: html.UnescapeString(string(data[match[2]:match[3]])),
+		}
+		if match[4] >= 0 {
+			f.Result = Position{Start: int64(match[4]), End: int64(match[5])}
+			f.RunProperties = RunPropertiesRegex.FindString(string(data[match[4]:match[5]]))
+		}
+		fields = append(fields, f)
+	}
+
+	parser := NewRunParser(data)
+	if err := parser.Execute(); err != nil {
+		return nil, err
+	}
+
+	var stack []*field
+	for _, run := range parser.Runs() {
+		runXml := string(data[run.OpenTag.Start:run.CloseTag.End])
+
+		for _, instr := range InstrTextRegex.FindAllStringSubmatch(runXml, -1) {
+			if len(stack) > 0 {
+				stack[len(stack)-1].Instruction += html.UnescapeString(instr[1])
+			}
+		}
+
+		for _, fieldChar := range FieldCharRegex.FindAllStringSubmatch(runXml, -1) {
+			switch fieldChar[1] {
+			case "begin":
+				stack = append(stack, &field{
+					Position:      Position{Start: run.OpenTag.Start},
+					RunProperties: RunPropertiesRegex.FindString(runXml),
+					Depth:         len(stack),
+				})
+			case "separate":
+				if len(stack) > 0 {
+					stack[len(stack)-1].Result = Position{Start: run.CloseTag.End, End: run.CloseTag.End}
+				}
+			case "end":
+				if len(stack) == 0 {
+					continue
+				}
+				f := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				f.End = run.CloseTag.End
+				f.EndRun = run.OpenTag.Start
+				if f.Result.Start > 0 {
+					f.Result.End = run.OpenTag.Start
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Start < fields[j].Start
+	})
+	return fields, nil
+}
+
+// FieldInstruction is a parsed field instruction, e.g. 'MERGEFIELD Total \# "0.00"'.
+type FieldInstruction struct {
+	// Type is the upper-case type of the field, e.g. 'MERGEFIELD' or 'PAGE'.
+	Type string
+	// Arguments are all arguments which are not part of a switch, e.g. the name of a merge field.
+	Arguments []string
+	// Switches are the switches of the field in the order in which they appear.
+	Switches []FieldSwitch
+}
+
+// FieldSwitch is a single switch of a field instruction, e.g. '\# "0.00"'.
+type FieldSwitch struct {
+	// Name is the switch including the backslash, e.g. '\#', '\@' or '\*'.
+	Name string
+	// Argument is the unquoted argument of the switch, if any.
+	Argument string
+}
+
+// ParseFieldInstruction parses the instruction of a field.
+func ParseFieldInstruction(instruction string) FieldInstruction {
+	tokens := tokenizeFieldInstruction(instruction)
+	var parsed FieldInstruction
+	if len(tokens) == 0 {
+		return parsed
+	}
+	parsed.Type = strings.ToUpper(tokens[0])
+	parsed.Arguments, parsed.Switches = parseFieldArguments(tokens[1:])
+	return parsed
+}
+
+// parseFieldArguments separates the tokens following the field type into arguments and switches.
+func parseFieldArguments(tokens []string) (arguments []string, switches []FieldSwitch) {
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if !strings.HasPrefix(token, `\`) {
+			arguments = append(arguments, token)
+			continue
+		}
+		if len(token) > 2 && fieldSwitchHasArgument(token[:2]) {
+			// the argument directly follows the switch, e.g. '\*Upper'
+			switches = append(switches, FieldSwitch{Name: token[:2], Argument: token[2:]})
+			continue
+		}
+		fieldSwitch := FieldSwitch{Name: token}
+		if i+1 < len(tokens) && !strings.HasPrefix(tokens[i+1], `\`) && fieldSwitchHasArgument(token) {
+			fieldSwitch.Argument = tokens[i+1]
+			i++
+		}
+		switches = append(switches, fieldSwitch)
+	}
+	return arguments, switches
+}
+
+// Switch returns the argument of the first switch with the given name and whether it exists.
+func (f FieldInstruction) Switch(name string) (string, bool) {
+	for _, s := range f.Switches {
+		if s.Name == name {
+			return s.Argument, true
+		}
+	}
+	return "", false
+}
+
+// fieldSwitchHasArgument returns true for switches which require an argument.
+func fieldSwitchHasArgument(name string) bool {
+	switch name {
+	case `\#`, `\@`, `\*`, `\b`, `\f`:
+		return true
+	}
+	return false
+}
+
+// tokenizeFieldInstruction splits a field instruction into tokens, respecting quoted arguments.
+// Word uses straight as well as typographic quotes in field instructions.
+func tokenizeFieldInstruction(instruction string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	hasToken := false
+
+	flush := func() {
+		if hasToken {
+			tokens = append(tokens, current.String())
+		}
+		current.Reset()
+		hasToken = false
+	}
+
+	for _, r := range instruction {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			if inQuotes {
+				flush()
+			}
+			inQuotes = !inQuotes
+			hasToken = inQuotes
+		case (r == ' ' || r == '\t' || r == '\n' || r == ' ') && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	flush()
+	return tokens
+}
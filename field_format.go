@@ -0,0 +1,395 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrInvalidFieldSwitch is returned if a field switch is unknown or cannot be applied to the value.
+	ErrInvalidFieldSwitch = errors.New("invalid field switch")
+
+	// fieldDateLayouts are the layouts used to parse string values for date-time picture switches (\@).
+	fieldDateLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+// FormatFieldValue formats the value according to Word field switches, e.g. `\# "#,##0.00"`,
+// `\@ "MMMM d, yyyy"` or `\* Upper`. See FieldInstruction.Format for the supported switches.
+func FormatFieldValue(value interface{}, switches string) (string, error) {
+	var instruction FieldInstruction
+	instruction.Arguments, instruction.Switches = parseFieldArguments(tokenizeFieldInstruction(switches))
+	return instruction.Format(value)
+}
+
+// Format formats the value according to the formatting switches of the field instruction, the way Word
+// renders the result of the field. The following switches are supported:
+//
+//	\# "picture"  numeric picture, e.g. "0.00", "#,##0" or "$#,##0.00;($#,##0.00);-"
+//	\@ "picture"  date-time picture, e.g. "MMMM d, yyyy" or "dd.MM.yyyy HH:mm"
+//	\* format     Upper, Lower, Caps, FirstCap, Arabic, Roman and roman. MERGEFORMAT and CHARFORMAT are ignored.
+//	\b "text"     text inserted before a non-empty result
+//	\f "text"     text inserted after a non-empty result
+//
+// In numeric pictures '0' is a mandatory and '#' an optional digit, missing optional digits are omitted.
+func (f FieldInstruction) Format(value interface{}) (string, error) {
+	text := ""
+	if value != nil {
+		text = fmt.Sprint(value)
+	}
+
+	if picture, ok := f.Switch(`\#`); ok && strings.TrimSpace(text) != "" {
+		number, err := toFloat(value)
+		if err != nil {
+			return "", fmt.Errorf(`%w: \# requires a number: %v`, ErrInvalidFieldSwitch, err)
+		}
+		text = formatNumberPicture(number, picture)
+	}
+
+	if picture, ok := f.Switch(`\@`); ok && strings.TrimSpace(text) != "" {
+		date, err := fieldTime(value)
+		if err != nil {
+			return "", err
+		}
+		text = formatDatePicture(date, picture)
+	}
+
+	for _, s := range f.Switches {
+		if s.Name != `\*` {
+			continue
+		}
+		formatted, err := formatGeneral(text, s.Argument)
+		if err != nil {
+			return "", err
+		}
+		text = formatted
+	}
+
+	if text != "" {
+		before, _ := f.Switch(`\b`)
+		after, _ := f.Switch(`\f`)
+		text = before + text + after
+	}
+	return text, nil
+}
+
+// formatGeneral applies a general formatting switch (\*) to the text.
+func formatGeneral(text, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "mergeformat", "charformat":
+		return text, nil
+	case "upper":
+		return strings.ToUpper(text), nil
+	case "lower":
+		return strings.ToLower(text), nil
+	case "caps":
+		runes := []rune(strings.ToLower(text))
+		for i := range runes {
+			if i == 0 || unicode.IsSpace(runes[i-1]) {
+				runes[i] = unicode.ToUpper(runes[i])
+			}
+		}
+		return string(runes), nil
+	case "firstcap":
+		runes := []rune(text)
+		for i, r := range runes {
+			if !unicode.IsSpace(r) {
+				runes[i] = unicode.ToUpper(r)
+				break
+			}
+		}
+		return string(runes), nil
+	case "arabic", "roman":
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return "", fmt.Errorf(`%w: \* %s requires a number, got %q`, ErrInvalidFieldSwitch, format, text)
+		}
+		if strings.EqualFold(format, "arabic") {
+			return strconv.FormatInt(int64(number), 10), nil
+		}
+		roman := romanNumeral(int(number))
+		if format == "roman" {
+			roman = strings.ToLower(roman)
+		}
+		return roman, nil
+	}
+	return "", fmt.Errorf(`%w: unsupported format \* %s`, ErrInvalidFieldSwitch, format)
+}
+
+// romanNumeral returns the upper-case roman numeral of n. Numbers below 1 are returned as arabic numbers.
+func romanNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var roman strings.Builder
+	for i, value := range values {
+		for n >= value {
+			roman.WriteString(symbols[i])
+			n -= value
+		}
+	}
+	return roman.String()
+}
+
+// formatNumberPicture formats the number according to a numeric picture (\#).
+// A picture may consist of up to three sections separated by ';' for positive, negative and zero values.
+func formatNumberPicture(number float64, picture string) string {
+	sections := splitPictureSections(picture)
+	section := sections[0]
+	negative := number < 0
+	signed := true
+	switch {
+	case number == 0 && len(sections) >= 3:
+		return pictureLiteral(sections[2])
+	case negative && len(sections) >= 2:
+		section = sections[1]
+		number = -number
+		negative = false
+		// the negative section contains its own sign
+		signed = false
+	}
+
+	prefix, pattern, suffix := splitNumberPattern(section)
+	if pattern == "" {
+		return pictureLiteral(section)
+	}
+
+	integerPattern, fractionPattern, _ := strings.Cut(pattern, ".")
+	decimals := strings.Count(fractionPattern, "0") + strings.Count(fractionPattern, "#") + strings.Count(fractionPattern, "x")
+	minDecimals := strings.Count(fractionPattern, "0")
+	minDigits := strings.Count(integerPattern, "0")
+
+	// Word rounds half away from zero, FormatFloat would round half to even
+	scale := math.Pow(10, float64(decimals))
+	formatted := strconv.FormatFloat(math.Round(number*scale)/scale, 'f', decimals, 64)
+	formatted = strings.TrimPrefix(formatted, "-")
+	if formatted == strconv.FormatFloat(0, 'f', decimals, 64) {
+		negative = false
+	}
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	for len(fraction) > minDecimals && strings.HasSuffix(fraction, "0") {
+		fraction = fraction[:len(fraction)-1]
+	}
+	if integer == "0" && minDigits == 0 {
+		integer = ""
+	}
+	for len(integer) < minDigits {
+		integer = "0" + integer
+	}
+	if strings.Contains(integerPattern, ",") {
+		integer = groupThousands(integer)
+	}
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+
+	sign := ""
+	switch {
+	case !signed:
+	case strings.Contains(prefix, "+") || strings.Contains(prefix, "-"):
+		if negative {
+			sign = "-"
+		} else if strings.Contains(prefix, "+") {
+			sign = "+"
+		}
+		prefix = strings.NewReplacer("+", "", "-", "").Replace(prefix)
+	case negative:
+		sign = "-"
+	}
+	return pictureLiteral(prefix) + sign + result + pictureLiteral(suffix)
+}
+
+// splitPictureSections splits a numeric picture into its sections, ignoring separators inside quoted text.
+func splitPictureSections(picture string) []string {
+	var sections []string
+	inQuotes := false
+	start := 0
+	for i, r := range picture {
+		switch {
+		case r == '\'':
+			inQuotes = !inQuotes
+		case r == ';' && !inQuotes:
+			sections = append(sections, picture[start:i])
+			start = i + 1
+		}
+	}
+	return append(sections, picture[start:])
+}
+
+// splitNumberPattern splits a section of a numeric picture into the literal prefix, the digit pattern and
+// the literal suffix, e.g. '$#,##0.00 USD' into '$', '#,##0.00' and ' USD'.
+func splitNumberPattern(section string) (prefix, pattern, suffix string) {
+	inQuotes := false
+	start, end := -1, -1
+	for i, r := range section {
+		if r == '\'' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if inQuotes {
+			continue
+		}
+		isDigit := r == '0' || r == '#' || r == 'x'
+		if start < 0 && (isDigit || r == '.') {
+			start = i
+		}
+		if start >= 0 && end < 0 && !isDigit && r != '.' && r != ',' {
+			end = i
+		}
+	}
+	if start < 0 {
+		return section, "", ""
+	}
+	if end < 0 {
+		end = len(section)
+	}
+	return section[:start], section[start:end], section[end:]
+}
+
+// pictureLiteral returns the literal text of a picture, removing the quotes around quoted text.
+func pictureLiteral(text string) string {
+	return strings.ReplaceAll(text, "'", "")
+}
+
+// groupThousands inserts a comma between every group of three digits.
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var grouped strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteRune(',')
+		}
+		grouped.WriteRune(r)
+	}
+	return grouped.String()
+}
+
+// fieldTime converts the value into a time for date-time picture switches (\@).
+func fieldTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v != nil {
+			return *v, nil
+		}
+	case string:
+		for _, layout := range fieldDateLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf(`%w: \@ requires a date, got %v`, ErrInvalidFieldSwitch, value)
+}
+
+// formatDatePicture formats the time according to a date-time picture (\@), e.g. 'MMMM d, yyyy'.
+// Month and weekday names are English.
+func formatDatePicture(t time.Time, picture string) string {
+	var result strings.Builder
+	runes := []rune(picture)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if r == '\'' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			result.WriteString(string(runes[i+1 : end]))
+			i = end + 1
+			continue
+		}
+
+		if rest := string(runes[i:]); strings.HasPrefix(strings.ToUpper(rest), "AM/PM") || strings.HasPrefix(strings.ToUpper(rest), "A/P") {
+			length := 5
+			marker := "AM"
+			if t.Hour() >= 12 {
+				marker = "PM"
+			}
+			if strings.HasPrefix(strings.ToUpper(rest), "A/P") {
+				length = 3
+				marker = marker[:1]
+			}
+			if unicode.IsLower(r) {
+				marker = strings.ToLower(marker)
+			}
+			result.WriteString(marker)
+			i += length
+			continue
+		}
+
+		count := 1
+		for i+count < len(runes) && runes[i+count] == r {
+			count++
+		}
+
+		switch r {
+		case 'M':
+			switch {
+			case count >= 4:
+				result.WriteString(t.Month().String())
+			case count == 3:
+				result.WriteString(t.Month().String()[:3])
+			default:
+				result.WriteString(padNumber(int(t.Month()), count))
+			}
+		case 'd', 'D':
+			switch {
+			case count >= 4:
+				result.WriteString(t.Weekday().String())
+			case count == 3:
+				result.WriteString(t.Weekday().String()[:3])
+			default:
+				result.WriteString(padNumber(t.Day(), count))
+			}
+		case 'y', 'Y':
+			if count <= 2 {
+				result.WriteString(padNumber(t.Year()%100, 2))
+			} else {
+				result.WriteString(padNumber(t.Year(), 4))
+			}
+		case 'H':
+			result.WriteString(padNumber(t.Hour(), count))
+		case 'h':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			result.WriteString(padNumber(hour, count))
+		case 'm':
+			result.WriteString(padNumber(t.Minute(), count))
+		case 's', 'S':
+			result.WriteString(padNumber(t.Second(), count))
+		default:
+			result.WriteString(strings.Repeat(string(r), count))
+		}
+		i += count
+	}
+	return result.String()
+}
+
+// padNumber returns the number with leading zeros if two digits are requested.
+func padNumber(n, digits int) string {
+	if digits >= 2 {
+		return fmt.Sprintf("%0*d", digits, n)
+	}
+	return strconv.Itoa(n)
+}
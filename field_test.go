@@ -0,0 +1,134 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFieldInstruction(t *testing.T) {
+	instruction := ParseFieldInstruction(` MERGEFIELD  Total \# "#,##0.00" \*Upper \* MERGEFORMAT `)
+
+	expected := FieldInstruction{
+		Type:      "MERGEFIELD",
+		Arguments: []string{"Total"},
+		Switches: []FieldSwitch{
+			{Name: `\#`, Argument: "#,##0.00"},
+			{Name: `\*`, Argument: "Upper"},
+			{Name: `\*`, Argument: "MERGEFORMAT"},
+		},
+	}
+	if !reflect.DeepEqual(instruction, expected) {
+		t.Errorf("expected %+v, have %+v", expected, instruction)
+	}
+}
+
+func TestFormatFieldValue(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	tests := []struct {
+		value    interface{}
+		switches string
+		expected string
+	}{
+		{1234.5, `\# "0.00"`, "1234.50"},
+		{1234.5, `\# "#,##0.00"`, "1,234.50"},
+		{1234567, `\# "#,##0"`, "1,234,567"},
+		{"42.125", `\# "0.##"`, "42.13"},
+		{0.5, `\# "#.00"`, ".50"},
+		{-3, `\# "0.0"`, "-3.0"},
+		{-3, `\# "$#,##0.00;($#,##0.00)"`, "($3.00)"},
+		{0, `\# "0.00;-0.00;'none'"`, "none"},
+		{-3, `\# "0.00;-0.00"`, "-3.00"},
+		{3, `\# "+0;-0"`, "+3"},
+		{7, `\# "000"`, "007"},
+		{12, `\# "$#,##0.00 'USD'"`, "$12.00 USD"},
+		{"", `\# "0.00"`, ""},
+		{date, `\@ "MMMM d, yyyy"`, "March 5, 2024"},
+		{date, `\@ "dd.MM.yy HH:mm:ss"`, "05.03.24 14:07:09"},
+		{date, `\@ "dddd, MMM d 'at' h:mm am/pm"`, "Tuesday, Mar 5 at 2:07 pm"},
+		{"2024-03-05", `\@ "d/M/yyyy"`, "5/3/2024"},
+		{"jane doe", `\* Upper`, "JANE DOE"},
+		{"JANE DOE", `\* Lower`, "jane doe"},
+		{"jane DOE", `\* Caps`, "Jane Doe"},
+		{"jane doe", `\* FirstCap`, "Jane doe"},
+		{14, `\* Roman`, "XIV"},
+		{14, `\* roman`, "xiv"},
+		{"Smith", `\b "Dear Mr. " \f ","`, "Dear Mr. Smith,"},
+		{"", `\b "Dear Mr. " \f ","`, ""},
+		{"text", `\* MERGEFORMAT`, "text"},
+	}
+
+	for _, test := range tests {
+		formatted, err := FormatFieldValue(test.value, test.switches)
+		if err != nil {
+			t.Errorf("%v %s: unexpected error: %s", test.value, test.switches, err)
+			continue
+		}
+		if formatted != test.expected {
+			t.Errorf("%v %s: expected %q, have %q", test.value, test.switches, test.expected, formatted)
+		}
+	}
+}
+
+func TestFormatFieldValue_Invalid(t *testing.T) {
+	for _, test := range []struct {
+		value    interface{}
+		switches string
+	}{
+		{"abc", `\# "0.00"`},
+		{"tomorrow", `\@ "d.M.yyyy"`},
+		{"abc", `\* CardText`},
+	} {
+		if _, err := FormatFieldValue(test.value, test.switches); !errors.Is(err, ErrInvalidFieldSwitch) {
+			t.Errorf("%v %s: expected ErrInvalidFieldSwitch, have %v", test.value, test.switches, err)
+		}
+	}
+}
+
+func TestDocument_MergeFields(t *testing.T) {
+	body := `<w:p><w:fldSimple w:instr=" MERGEFIELD Name \* Upper "><w:r><w:rPr><w:b/></w:rPr><w:t>«Name»</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">Total: </w:t></w:r>` +
+		`<w:r><w:rPr><w:i/></w:rPr><w:fldChar w:fldCharType="begin"/></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> MERGEFIELD Total </w:instrText></w:r>` +
+		`<w:r><w:instrText xml:space="preserve">\# &quot;#,##0.00&quot; </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+		`<w:r><w:t>«Total»</w:t></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:fldSimple w:instr=" MERGEFIELD Unknown "><w:r><w:t>«Unknown»</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:r><w:t>{placeholder}</w:t></w:r></w:p>`
+
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.MergeFields(PlaceholderMap{"Name": "jane & co", "Total": 1234.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"placeholder": "still works"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">JANE &amp; CO</w:t></w:r>`,
+		`<w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">1,234.50</w:t></w:r>`,
+		`MERGEFIELD Unknown`,
+		`still works`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "MERGEFIELD Name") || strings.Contains(document, "fldChar") {
+		t.Errorf("expected merged fields to be removed: %s", document)
+	}
+}
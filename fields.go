@@ -0,0 +1,166 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FieldTypeTOC is the type of table of contents fields.
+	FieldTypeTOC = "TOC"
+	// FieldTypePage is the type of page number fields.
+	FieldTypePage = "PAGE"
+	// FieldTypeDate is the type of current date fields.
+	FieldTypeDate = "DATE"
+	// FieldTypeDocProperty is the type of fields showing a document property, e.g. { DOCPROPERTY Title }.
+	FieldTypeDocProperty = "DOCPROPERTY"
+)
+
+// ErrFieldNotFound is returned if a field does not exist in the document (anymore).
+var ErrFieldNotFound = errors.New("field not found")
+
+// Field is a Word field of the document, e.g. a table of contents, a page number or a document property, see Fields.
+type Field struct {
+	// Part is the name of the file inside the archive which contains the field.
+	Part string
+	// Index is the index of the field among the top-level fields of the part in document order.
+	Index int
+	// Instruction is the parsed instruction of the field, e.g. 'TOC \o "1-3" \h'.
+	Instruction FieldInstruction
+	// Code is the unparsed instruction of the field.
+	Code string
+	// Result is the text of the field result last calculated by Word, paragraphs are separated by newlines.
+	Result string
+}
+
+// Type returns the type of the field, e.g. FieldTypeTOC.
+func (f Field) Type() string {
+	return f.Instruction.Type
+}
+
+// Fields returns the top-level fields of the document body, headers, footers and notes in document order.
+// Fields nested inside other fields, e.g. the PAGEREF fields of the entries of a table of contents, belong to the
+// field containing them.
+//
+// Example:
+//
+//	fields, err := doc.Fields()
+//	for _, field := range fields {
+//	    if field.Type() == docx.FieldTypeDocProperty {
+//	        fmt.Println(field.Instruction.Arguments[0], field.Result)
+//	    }
+//	}
+func (d *Document) Fields() ([]Field, error) {
+	var fields []Field
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		parsed, err := topLevelFields(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse fields in %s: %w", name, err)
+		}
+		for i, f := range parsed {
+			field := Field{
+				Part:        name,
+				Index:       i,
+				Instruction: ParseFieldInstruction(f.Instruction),
+				Code:        strings.TrimSpace(f.Instruction),
+			}
+			if f.Result.End > f.Result.Start {
+				field.Result = resultText(data[f.Result.Start:f.Result.End])
+			}
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// topLevelFields returns the fields of the XML part which are not nested inside another field.
+func topLevelFields(data []byte) ([]*field, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return nil, err
+	}
+	var topLevel []*field
+	end := int64(0)
+	for _, f := range fields {
+		if f.Start < end {
+			continue // nested, simple fields do not know their depth
+		}
+		end = f.End
+		topLevel = append(topLevel, f)
+	}
+	return topLevel, nil
+}
+
+// resultText returns the text of a field result, paragraphs are separated by newlines.
+func resultText(result []byte) string {
+	paragraphs := strings.Split(string(result), "</w:p>")
+	texts := make([]string, 0, len(paragraphs))
+	for _, paragraph := range paragraphs {
+		if text := elementText([]byte(paragraph)); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// SetFieldResult replaces the result of the field with the text, which is shown until Word updates the field.
+// Results spanning multiple paragraphs, like the entries of a table of contents, are replaced by a single
+// paragraph. Newlines of the text become line breaks.
+//
+// Example:
+//
+//	for _, field := range fields {
+//	    if field.Type() == docx.FieldTypeTOC {
+//	        doc.SetFieldResult(field, "Right-click to update the table of contents.")
+//	    }
+//	}
+func (d *Document) SetFieldResult(field Field, text string) error {
+	data, exists := d.files[field.Part]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, field.Part)
+	}
+	fields, err := topLevelFields(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse fields in %s: %w", field.Part, err)
+	}
+	if field.Index < 0 || field.Index >= len(fields) {
+		return fmt.Errorf("%w: %d in %s", ErrFieldNotFound, field.Index, field.Part)
+	}
+	f := fields[field.Index]
+	if code := strings.TrimSpace(f.Instruction); code != field.Code {
+		return fmt.Errorf("%w: %d in %s is %s", ErrFieldNotFound, field.Index, field.Part, code)
+	}
+
+	ctx := &valueContext{doc: d, part: field.Part, runProperties: f.RunProperties}
+	runsXml := d.textPolicy.lineRuns(ctx, d.textPolicy.apply(normalizeText(text)))
+	var start, end int64
+	switch {
+	case f.Result.Start > 0:
+		start, end = f.Result.Start, f.Result.End
+	case f.EndRun > 0:
+		// a complex field without result
+		start, end = f.EndRun, f.EndRun
+		runsXml = `<w:r><w:fldChar w:fldCharType="separate"/></w:r>` + runsXml
+	default:
+		// a simple field without result, <w:fldSimple w:instr="..."/>
+		element := string(data[f.Start:f.End])
+		start, end = f.Start, f.End
+		runsXml = strings.TrimRight(strings.TrimSuffix(element, "/>"), " ") + ">" + runsXml + "</w:fldSimple>"
+	}
+
+	changed := append([]byte{}, data[:start]...)
+	changed = append(changed, runsXml...)
+	changed = append(changed, data[end:]...)
+	if err := d.SetFile(field.Part, changed); err != nil {
+		return err
+	}
+	return d.parseFile(field.Part)
+}
+
+// UpdateFieldsOnOpen asks Word to update all fields when the document is opened, e.g. to rebuild the table of
+// contents after headings were inserted. Word asks the user for permission before updating them.
+func (d *Document) UpdateFieldsOnOpen() error {
+	return d.setSetting("updateFields", `<w:updateFields w:val="true"/>`)
+}
@@ -0,0 +1,103 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fieldsBody holds a table of contents spanning multiple paragraphs with nested PAGEREF fields, a simple PAGE field,
+// a complex DOCPROPERTY field without result and an empty simple DATE field.
+const fieldsBody = `<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> TOC \o "1-3" \h </w:instrText></w:r>` +
+	`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>Introduction</w:t></w:r>` +
+	`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText> PAGEREF _Toc1 </w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>1</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+	`<w:p><w:r><w:t>Results</w:t></w:r><w:fldSimple w:instr=" PAGEREF _Toc2 "><w:r><w:t>2</w:t></w:r></w:fldSimple><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+	`<w:p><w:r><w:t xml:space="preserve">Page </w:t></w:r><w:fldSimple w:instr=" PAGE "><w:r><w:rPr><w:b/></w:rPr><w:t>3</w:t></w:r></w:fldSimple></w:p>` +
+	`<w:p><w:r><w:rPr><w:i/></w:rPr><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText> DOCPROPERTY Title </w:instrText></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+	`<w:p><w:fldSimple w:instr=" DATE \@ &quot;d.M.yyyy&quot; "/></w:p>`
+
+func TestDocument_Fields(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, fieldsBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := doc.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		fieldType, code, result string
+	}{
+		{FieldTypeTOC, `TOC \o "1-3" \h`, "Introduction1\nResults2"},
+		{FieldTypePage, "PAGE", "3"},
+		{FieldTypeDocProperty, "DOCPROPERTY Title", ""},
+		{FieldTypeDate, `DATE \@ "d.M.yyyy"`, ""},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("expected %d fields, have %+v", len(expected), fields)
+	}
+	for i, field := range fields {
+		if field.Type() != expected[i].fieldType || field.Code != expected[i].code || field.Result != expected[i].result || field.Index != i {
+			t.Errorf("expected %+v, have %+v", expected[i], field)
+		}
+	}
+}
+
+func TestDocument_SetFieldResult(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, fieldsBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := doc.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range []string{"Update the table of contents", "7", "Annual report", "1.1.2026"} {
+		if err := doc.SetFieldResult(fields[i], result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := doc.UpdateFieldsOnOpen(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t xml:space="preserve">Update the table of contents</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+			`<w:p><w:r><w:t xml:space="preserve">Page </w:t></w:r>`,
+		`<w:fldSimple w:instr=" PAGE "><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">7</w:t></w:r></w:fldSimple>`,
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">Annual report</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>`,
+		`<w:fldSimple w:instr=" DATE \@ &quot;d.M.yyyy&quot; "><w:r><w:t xml:space="preserve">1.1.2026</w:t></w:r></w:fldSimple>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+	if settings := readTestPart(t, buf.Bytes(), SettingsXml); !strings.Contains(settings, `<w:updateFields w:val="true"/>`) {
+		t.Errorf("expected updateFields in %s", settings)
+	}
+}
+
+func TestDocument_SetFieldResult_NotFound(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, fieldsBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := doc.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := fields[1]
+	stale.Index = 2
+	if err := doc.SetFieldResult(stale, "7"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound for a changed field, have %v", err)
+	}
+	if err := doc.SetFieldResult(Field{Part: "word/header9.xml"}, "7"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound for a missing part, have %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package docx
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// commentMarkRegex matches the references to comments and the ranges which they annotate.
+	commentMarkRegex = regexp.MustCompile(`<w:(?:commentReference|commentRangeStart|commentRangeEnd)\s[^>]*/>`)
+	// commentsPartRegex matches the names of the parts holding comments and their extended information.
+	commentsPartRegex = regexp.MustCompile(`^word/comments\w*\.xml$`)
+	// fieldCodeRegex matches the field characters and the instruction texts of complex fields.
+	fieldCodeRegex = regexp.MustCompile(`(?s)<w:fldChar\s[^>]*?(?:/>|>.*?</w:fldChar>)|<w:instrText(?:\s[^>]*)?>[^<]*</w:instrText>`)
+	// paragraphBoundaryRegex matches the start or the end of a paragraph.
+	paragraphBoundaryRegex = regexp.MustCompile(`<w:p[\s>]|</w:p>`)
+	// emptyRunRegex matches runs without content or with empty text whose properties consist of empty elements only.
+	emptyRunRegex = regexp.MustCompile(`<w:r(?:\s[^>]*)?>(?:<w:rPr>(?:<[^>]*/>)*</w:rPr>)?(?:<w:t(?:\s[^>]*)?></w:t>)?</w:r>`)
+)
+
+// pageFieldTypes are the types of fields whose result depends on the page they are shown on, they are kept by
+// Flatten since their result is only correct for the page on which Word calculated them.
+var pageFieldTypes = map[string]bool{
+	FieldTypePage:         true,
+	FieldTypeNumPages:     true,
+	FieldTypeSectionPages: true,
+	"SECTION":             true,
+}
+
+// Flatten turns the document into a static document without any dynamic or editorial content, e.g. at the end of a
+// generation pipeline before the document is archived:
+//
+//   - all tracked changes are accepted, see AcceptAllRevisions
+//   - all comments are removed
+//   - fields are replaced by their last calculated result, except page numbers (PAGE, NUMPAGES, SECTIONPAGES and
+//     SECTION) which are only correct on the page on which they are shown
+//   - content controls are replaced by their content
+//   - remaining template actions ({{ ... }}) and placeholders are removed
+//
+// The body, headers, footers and notes are flattened. Run UpdateFieldsOnOpen before rendering if the results of
+// the fields must be recalculated, Flatten keeps the results as they are.
+func (d *Document) Flatten() error {
+	if err := d.AcceptAllRevisions(); err != nil {
+		return err
+	}
+	if err := d.removeComments(); err != nil {
+		return err
+	}
+
+	for _, name := range d.xmlFiles() {
+		data := string(d.files[name])
+		root := childElements(data)
+		if len(root) == 0 {
+			continue
+		}
+		start := root[0].start + strings.IndexByte(data[root[0].start:], '>') + 1
+		end := strings.LastIndex(data[:root[0].end], "</")
+		if end < start {
+			continue // empty, self-closing root element
+		}
+
+		content := commentMarkRegex.ReplaceAllString(data[start:end], "")
+		content = unwrapContentControls(content)
+		content = removeTemplateActions(content)
+		content, err := resolveFields(content)
+		if err != nil {
+			return err
+		}
+		content = emptyRunRegex.ReplaceAllString(content, "")
+		if content == data[start:end] {
+			continue
+		}
+		if err := d.SetFile(name, []byte(data[:start]+content+data[end:])); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+
+	unresolved, err := d.UnresolvedPlaceholders()
+	if err != nil {
+		return err
+	}
+	if len(unresolved) == 0 {
+		return nil
+	}
+	placeholderMap := make(PlaceholderMap, len(unresolved))
+	for _, placeholder := range unresolved {
+		placeholderMap[placeholder.Key] = ""
+	}
+	return d.ReplaceAll(placeholderMap)
+}
+
+// removeComments removes all comments of the comments parts. The parts are kept empty since they are referenced by
+// the relationships of the document.
+func (d *Document) removeComments() error {
+	var names []string
+	for _, file := range d.zipFile.File {
+		names = append(names, file.Name)
+	}
+	for _, name := range append(names, d.newParts...) {
+		if !commentsPartRegex.MatchString(name) {
+			continue
+		}
+		data, exists, err := d.part(name)
+		if err != nil {
+			return err
+		}
+		root := childElements(string(data))
+		if !exists || len(root) == 0 {
+			continue
+		}
+		openTag, content, closeTag := splitElement(string(data[root[0].start:root[0].end]))
+		if content == "" {
+			continue
+		}
+		emptied := string(data[:root[0].start]) + openTag + closeTag + string(data[root[0].end:])
+		if err := d.setPart(name, []byte(emptied)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwrapContentControls replaces all content controls inside the content, including nested ones, by their content.
+func unwrapContentControls(content string) string {
+	if !strings.Contains(content, "<w:sdt") {
+		return content
+	}
+	var out strings.Builder
+	pos := 0
+	for _, child := range childElements(content) {
+		out.WriteString(content[pos:child.start])
+		pos = child.end
+		element := content[child.start:child.end]
+		openTag, inner, closeTag := splitElement(element)
+		if child.name != "sdt" {
+			if inner == "" {
+				out.WriteString(element)
+			} else {
+				out.WriteString(openTag + unwrapContentControls(inner) + closeTag)
+			}
+			continue
+		}
+		for _, sdtChild := range childElements(inner) {
+			if sdtChild.name != "sdtContent" {
+				continue
+			}
+			_, sdtContent, _ := splitElement(inner[sdtChild.start:sdtChild.end])
+			out.WriteString(unwrapContentControls(sdtContent))
+		}
+	}
+	out.WriteString(content[pos:])
+	return out.String()
+}
+
+// removeTemplateActions removes all template actions from the text of the content. Actions which are split over
+// multiple text nodes are removed from all of them.
+func removeTemplateActions(content string) string {
+	matches := TextNodeRegex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content
+	}
+	var all strings.Builder
+	nodeStart := make([]int, len(matches))
+	for i, m := range matches {
+		nodeStart[i] = all.Len()
+		all.WriteString(content[m[4]:m[5]])
+	}
+	text := all.String()
+	spans := findActionSpans(text)
+	if len(spans) == 0 {
+		return content
+	}
+
+	var out strings.Builder
+	last := 0
+	for i, m := range matches {
+		start := nodeStart[i]
+		end := start + (m[5] - m[4])
+		pos := start
+		var nodeText strings.Builder
+		for _, span := range spans {
+			if span[1] <= pos || span[0] >= end {
+				continue
+			}
+			if span[0] > pos {
+				nodeText.WriteString(text[pos:span[0]])
+			}
+			pos = min(span[1], end)
+		}
+		if pos < end {
+			nodeText.WriteString(text[pos:end])
+		}
+		out.WriteString(content[last:m[4]])
+		out.WriteString(nodeText.String())
+		last = m[5]
+	}
+	out.WriteString(content[last:])
+	return out.String()
+}
+
+// resolveFields replaces the fields of the content by their results, except the fields of pageFieldTypes.
+func resolveFields(content string) (string, error) {
+	fields, err := parseFields([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	// ranges are the field codes to remove, the field results between them are kept
+	var ranges [][2]int
+	for _, f := range fields {
+		if pageFieldTypes[ParseFieldInstruction(f.Instruction).Type] {
+			continue
+		}
+		start, end := int(f.Start), int(f.End)
+		switch {
+		case f.Result.Start == 0:
+			ranges = append(ranges, [2]int{start, end})
+		case f.EndRun == 0:
+			ranges = append(ranges, [2]int{start, int(f.Result.Start)}, [2]int{int(f.Result.End), end})
+		default:
+			ranges = append(ranges, [2]int{start, int(f.Result.Start)}, [2]int{int(f.EndRun), end})
+		}
+	}
+	if len(ranges) == 0 {
+		return content, nil
+	}
+
+	// the codes of nested fields are part of the codes or the results of the fields containing them
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	var out strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		if r[1] <= pos {
+			continue
+		}
+		if r[0] > pos {
+			out.WriteString(content[pos:r[0]])
+		} else {
+			r[0] = pos
+		}
+		if code := content[r[0]:r[1]]; paragraphBoundaryRegex.MatchString(code) {
+			// the field code spans paragraphs, only the field characters and instructions are removed to keep them
+			out.WriteString(fieldCodeRegex.ReplaceAllString(code, ""))
+		}
+		pos = r[1]
+	}
+	out.WriteString(content[pos:])
+	return out.String(), nil
+}
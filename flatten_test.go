@@ -0,0 +1,45 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDocument_Flatten(t *testing.T) {
+	body := `<w:p><w:commentRangeStart w:id="0"/><w:r><w:t xml:space="preserve">Dear {name}</w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+		`<w:r><w:rPr><w:rStyle w:val="CommentReference"/></w:rPr><w:commentReference w:id="0"/></w:r>` +
+		`<w:ins w:id="1" w:author="A"><w:r><w:t>,</w:t></w:r></w:ins></w:p>` +
+		`<w:sdt><w:sdtPr><w:alias w:val="Terms"/></w:sdtPr><w:sdtContent><w:p><w:r><w:t xml:space="preserve">Due {{if .</w:t></w:r>` +
+		`<w:r><w:t>Paid}}</w:t></w:r><w:r><w:t xml:space="preserve">today</w:t></w:r><w:r><w:t>{{end}}</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> DATE \@ "d.M.yyyy" </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>1.10.2026</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>` +
+		`<w:fldSimple w:instr=" DOCPROPERTY Title "><w:r><w:t>Invoice</w:t></w:r></w:fldSimple>` +
+		`<w:fldSimple w:instr=" PAGE "><w:r><w:t>1</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:sectPr/>`
+	comments := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:comment w:id="0" w:author="A"><w:p><w:r><w:t>Check the name</w:t></w:r></w:p></w:comment></w:comments>`
+	doc, err := OpenBytes(buildTestDocx(t, body, "word/comments.xml", comments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Flatten(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:r><w:t>,</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t xml:space="preserve">Due </w:t></w:r><w:r><w:t xml:space="preserve">today</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>1.10.2026</w:t></w:r><w:r><w:t>Invoice</w:t></w:r>` +
+		`<w:fldSimple w:instr=" PAGE "><w:r><w:t>1</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:sectPr/>`
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); document != testDocumentOpen+expected+testDocumentClose {
+		t.Errorf("expected %s, have %s", expected, document)
+	}
+	expectedComments := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:comments>`
+	if part := readTestPart(t, buf.Bytes(), "word/comments.xml"); part != expectedComments {
+		t.Errorf("expected %s, have %s", expectedComments, part)
+	}
+}
@@ -0,0 +1,356 @@
+package docx
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FontTableXml is the path of the font table part, which declares the fonts used by the document.
+	FontTableXml = "word/fontTable.xml"
+
+	// RelationshipTypeFontTable is the relationship type of the font table part.
+	RelationshipTypeFontTable = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable"
+	// RelationshipTypeFont is the relationship type of embedded fonts, their source is the font table.
+	RelationshipTypeFont = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/font"
+	// ContentTypeFontTable is the content type of the font table part.
+	ContentTypeFontTable = "application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml"
+	// ContentTypeObfuscatedFont is the content type of embedded fonts, which are stored obfuscated.
+	ContentTypeObfuscatedFont = "application/vnd.openxmlformats-officedocument.obfuscatedFont"
+
+	// fsType flags of the OS/2 table of OpenType fonts, see https://learn.microsoft.com/typography/opentype/spec/os2#fstype
+	fsTypeRestricted   = 0x0002
+	fsTypePreviewPrint = 0x0004
+	fsTypeEditable     = 0x0008
+	fsTypeNoSubsetting = 0x0100
+	fsTypeBitmapOnly   = 0x0200
+)
+
+var (
+	// ErrFontNotPermitted is returned by EmbedFont if the font policy refuses to embed the font.
+	ErrFontNotPermitted = errors.New("font embedding not permitted")
+
+	// fontsRegex matches the root element of the font table and captures the open tag and the content.
+	fontsRegex = regexp.MustCompile(`(?s)(<w:fonts(?:\s[^>]*)?>)(.*)</w:fonts>`)
+	// fontNameRegex matches the name attribute of a font of the font table and captures the name.
+	fontNameRegex = regexp.MustCompile(`^<w:font\s[^>]*?w:name="([^"]*)"`)
+
+	// fontOrder is the order of the child elements of a font of the font table required by the schema.
+	fontOrder = []string{
+		"altName", "panose1", "charset", "family", "notTrueType", "pitch", "sig",
+		"embedRegular", "embedBold", "embedItalic", "embedBoldItalic",
+	}
+
+	emptyFontTable = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:fonts xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"></w:fonts>`)
+)
+
+// FontStyle selects the variant of a font family which an embedded font file provides.
+type FontStyle int
+
+const (
+	// FontRegular is the regular variant of a font family.
+	FontRegular FontStyle = iota
+	// FontBold is the bold variant of a font family.
+	FontBold
+	// FontItalic is the italic variant of a font family.
+	FontItalic
+	// FontBoldItalic is the bold italic variant of a font family.
+	FontBoldItalic
+)
+
+// element returns the local name of the element of the font table which references the embedded font.
+func (s FontStyle) element() string {
+	switch s {
+	case FontBold:
+		return "embedBold"
+	case FontItalic:
+		return "embedItalic"
+	case FontBoldItalic:
+		return "embedBoldItalic"
+	}
+	return "embedRegular"
+}
+
+// FontEmbedding is the embedding permission which the license of a font grants, given by the fsType flags of the
+// OS/2 table of the font file.
+type FontEmbedding int
+
+const (
+	// FontInstallable fonts may be embedded and installed permanently by the recipient.
+	FontInstallable FontEmbedding = iota
+	// FontEditable fonts may be embedded into documents which are edited by the recipient.
+	FontEditable
+	// FontPreviewPrint fonts may be embedded into documents which are only viewed and printed by the recipient.
+	FontPreviewPrint
+	// FontRestricted fonts must not be embedded without permission of the owner of the license.
+	FontRestricted
+)
+
+// String returns the name of the permission.
+func (e FontEmbedding) String() string {
+	switch e {
+	case FontEditable:
+		return "editable"
+	case FontPreviewPrint:
+		return "preview & print"
+	case FontRestricted:
+		return "restricted"
+	}
+	return "installable"
+}
+
+// FontInfo describes a font which is about to be embedded, see FontPolicy.
+type FontInfo struct {
+	// Name is the font family name used by the document, e.g. 'Corporate Sans'.
+	Name string
+	// Style is the variant of the family provided by the font file.
+	Style FontStyle
+	// FSType are the raw fsType flags of the OS/2 table, FontInfo.Embedding is derived from them.
+	FSType uint16
+	// Embedding is the embedding permission of the font. Fonts without OS/2 table are restricted.
+	Embedding FontEmbedding
+	// NoSubsetting is true if the font must be embedded completely, which EmbedFont always does.
+	NoSubsetting bool
+	// BitmapOnly is true if only the bitmaps of the font may be embedded, not its outlines.
+	BitmapOnly bool
+}
+
+// FontPolicy keeps automated document generation license-compliant by restricting which fonts may be embedded,
+// e.g. with a separate list of licensed fonts for every tenant of a service. It is applied by EmbedFont.
+type FontPolicy struct {
+	// Allowed are the names of the font families which may be embedded, compared case-insensitively.
+	// If empty, all fonts may be embedded which their embedding permissions allow.
+	Allowed []string
+	// WarnOnly embeds fonts whose embedding permission (FontRestricted or BitmapOnly) does not allow embedding
+	// and reports them to Warn instead of refusing them. Fonts which are not allowed are always refused.
+	WarnOnly bool
+	// Warn is called for every font which is embedded despite a restricted embedding permission, if WarnOnly is set.
+	Warn func(font FontInfo, reason error)
+	// Check is called for every font after the other checks passed, e.g. to look up a license database.
+	// Returning an error refuses the font.
+	Check func(font FontInfo) error
+}
+
+// check returns an error wrapping ErrFontNotPermitted if the font must not be embedded.
+func (p FontPolicy) check(font FontInfo) error {
+	if len(p.Allowed) > 0 && !containsFold(p.Allowed, font.Name) {
+		return fmt.Errorf("%w: font %s is not licensed for embedding", ErrFontNotPermitted, font.Name)
+	}
+
+	var reason error
+	switch {
+	case font.Embedding == FontRestricted:
+		reason = fmt.Errorf("%w: font %s has a restricted license (fsType 0x%04X)", ErrFontNotPermitted, font.Name, font.FSType)
+	case font.BitmapOnly:
+		reason = fmt.Errorf("%w: font %s permits embedding its bitmaps only (fsType 0x%04X)", ErrFontNotPermitted, font.Name, font.FSType)
+	}
+	if reason != nil {
+		if !p.WarnOnly {
+			return reason
+		}
+		if p.Warn != nil {
+			p.Warn(font, reason)
+		}
+	}
+
+	if p.Check != nil {
+		if err := p.Check(font); err != nil {
+			return fmt.Errorf("%w: %w", ErrFontNotPermitted, err)
+		}
+	}
+	return nil
+}
+
+// containsFold returns true if the slice contains the value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFontPolicy sets the policy which is applied to all fonts embedded by EmbedFont.
+// Without a policy, fonts are embedded unless their embedding permission is restricted.
+//
+// Example:
+//
+//	doc.SetFontPolicy(docx.FontPolicy{
+//	    Allowed:  tenant.LicensedFonts,
+//	    WarnOnly: true,
+//	    Warn:     func(font docx.FontInfo, reason error) { log.Println(reason) },
+//	})
+func (d *Document) SetFontPolicy(policy FontPolicy) {
+	d.fontPolicy = policy
+}
+
+// ParseFontInfo returns the embedding permissions of the TrueType or OpenType font file, e.g. to check fonts when
+// they are uploaded. The name and style of the returned FontInfo are empty.
+func ParseFontInfo(data []byte) (FontInfo, error) {
+	if len(data) < 12 {
+		return FontInfo{}, fmt.Errorf("invalid font file, too short")
+	}
+	switch version := binary.BigEndian.Uint32(data); version {
+	case 0x00010000, 0x4F54544F, 0x74727565: // TrueType, OpenType ('OTTO') and Apple TrueType ('true')
+	case 0x74746366:
+		return FontInfo{}, fmt.Errorf("font collections (.ttc) cannot be embedded")
+	default:
+		return FontInfo{}, fmt.Errorf("invalid font file, unknown version 0x%08X", version)
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			return FontInfo{}, fmt.Errorf("invalid font file, truncated table directory")
+		}
+		if string(data[record:record+4]) != "OS/2" {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		if offset < 0 || offset+10 > len(data) {
+			return FontInfo{}, fmt.Errorf("invalid font file, truncated OS/2 table")
+		}
+		fsType := binary.BigEndian.Uint16(data[offset+8:])
+		font := FontInfo{
+			FSType:       fsType,
+			NoSubsetting: fsType&fsTypeNoSubsetting != 0,
+			BitmapOnly:   fsType&fsTypeBitmapOnly != 0,
+		}
+		// the permissions are exclusive, if several are set the least restrictive one applies
+		switch {
+		case fsType&fsTypeEditable != 0:
+			font.Embedding = FontEditable
+		case fsType&fsTypePreviewPrint != 0:
+			font.Embedding = FontPreviewPrint
+		case fsType&fsTypeRestricted != 0:
+			font.Embedding = FontRestricted
+		}
+		return font, nil
+	}
+	// without OS/2 table the permissions are unknown
+	return FontInfo{Embedding: FontRestricted}, nil
+}
+
+// EmbedFont embeds the TrueType or OpenType font file as the given variant of the font family, so the document looks
+// the same on computers without the font. Text uses the font by its family name, e.g. in styles or run properties.
+// The font policy (see SetFontPolicy) is applied first, refused fonts return an error wrapping ErrFontNotPermitted.
+// Embedding a variant again replaces the previous font file.
+//
+// Example:
+//
+//	err := doc.EmbedFont("Corporate Sans", docx.FontRegular, regularBytes)
+func (d *Document) EmbedFont(name string, style FontStyle, data []byte) error {
+	if name == "" {
+		return fmt.Errorf("font name is required")
+	}
+	font, err := ParseFontInfo(data)
+	if err != nil {
+		return err
+	}
+	font.Name, font.Style = name, style
+	if err := d.fontPolicy.check(font); err != nil {
+		return err
+	}
+
+	fontTable, exists, err := d.part(FontTableXml)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := d.addRelationship(DocumentXml, RelationshipTypeFontTable, "fontTable.xml", false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(FontTableXml, ContentTypeFontTable); err != nil {
+			return err
+		}
+		fontTable = emptyFontTable
+	}
+	match := fontsRegex.FindSubmatchIndex(fontTable)
+	if match == nil {
+		return fmt.Errorf("invalid font table %s", FontTableXml)
+	}
+
+	key, obfuscated, err := obfuscateFont(data)
+	if err != nil {
+		return err
+	}
+	partName := ""
+	for i := 1; partName == "" || d.hasPart(partName); i++ {
+		partName = fmt.Sprintf("word/fonts/font%d.odttf", i)
+	}
+	if err := d.setPart(partName, obfuscated); err != nil {
+		return err
+	}
+	if err := d.ensureContentTypeDefault("odttf", ContentTypeObfuscatedFont); err != nil {
+		return err
+	}
+	id, err := d.addRelationship(FontTableXml, RelationshipTypeFont, strings.TrimPrefix(partName, "word/"), false)
+	if err != nil {
+		return err
+	}
+
+	content := string(fontTable[match[4]:match[5]])
+	embed := fmt.Sprintf(`<w:%s r:id="%s" w:fontKey="%s"/>`, style.element(), id, key)
+	if !strings.Contains(string(fontTable[match[2]:match[3]]), "xmlns:r=") {
+		embed = fmt.Sprintf(`<w:%s xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" r:id="%s" w:fontKey="%s"/>`,
+			style.element(), id, key)
+	}
+	content = setFontElement(content, name, style.element(), embed)
+
+	changed := append([]byte{}, fontTable[:match[4]]...)
+	changed = append(changed, content...)
+	changed = append(changed, fontTable[match[5]:]...)
+	if err := d.setPart(FontTableXml, changed); err != nil {
+		return err
+	}
+	return d.setSetting("embedTrueTypeFonts", "<w:embedTrueTypeFonts/>")
+}
+
+// setFontElement sets the element of the font with the given name inside the content of the font table, adding the
+// font if it is not declared yet.
+func setFontElement(content, fontName, name, element string) string {
+	for _, child := range childElements(content) {
+		if child.name != "font" {
+			continue
+		}
+		fontXml := content[child.start:child.end]
+		match := fontNameRegex.FindStringSubmatch(fontXml)
+		if match == nil || html.UnescapeString(match[1]) != fontName {
+			continue
+		}
+		open, inner, closing := splitElement(fontXml)
+		return content[:child.start] + open + setOrderedElement(inner, fontOrder, name, element) + closing + content[child.end:]
+	}
+	return content + `<w:font w:name="` + html.EscapeString(fontName) + `">` + element + `</w:font>`
+}
+
+// obfuscateFont obfuscates the font file as required for embedded fonts (ECMA-376 Part 1, 17.8.1) using a random key.
+// It returns the key (a GUID in braces) and the obfuscated font.
+func obfuscateFont(data []byte) (string, []byte, error) {
+	if len(data) < 32 {
+		return "", nil, fmt.Errorf("invalid font file, too short")
+	}
+	var guid [16]byte
+	if _, err := rand.Read(guid[:]); err != nil {
+		return "", nil, fmt.Errorf("unable to generate font key: %w", err)
+	}
+	digits := strings.ToUpper(hex.EncodeToString(guid[:]))
+	key := "{" + digits[0:8] + "-" + digits[8:12] + "-" + digits[12:16] + "-" + digits[16:20] + "-" + digits[20:] + "}"
+
+	// the first 32 bytes are XORed with the bytes of the GUID in reverse order
+	obfuscated := append([]byte{}, data...)
+	for i := 0; i < 32; i++ {
+		obfuscated[i] ^= guid[15-i%16]
+	}
+	return key, obfuscated, nil
+}
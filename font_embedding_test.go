@@ -0,0 +1,138 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// buildTestFont returns a minimal TrueType font file whose OS/2 table has the given fsType flags.
+func buildTestFont(fsType uint16) []byte {
+	font := make([]byte, 64)
+	binary.BigEndian.PutUint32(font, 0x00010000)
+	binary.BigEndian.PutUint16(font[4:], 1)
+	copy(font[12:], "OS/2")
+	binary.BigEndian.PutUint32(font[20:], 28)
+	binary.BigEndian.PutUint32(font[24:], 36)
+	binary.BigEndian.PutUint16(font[36:], fsType)
+	return font
+}
+
+func TestParseFontInfo(t *testing.T) {
+	for _, test := range []struct {
+		fsType    uint16
+		embedding FontEmbedding
+	}{
+		{0x0000, FontInstallable},
+		{0x0002, FontRestricted},
+		{0x0004, FontPreviewPrint},
+		{0x0008, FontEditable},
+		{0x000C, FontEditable},
+	} {
+		font, err := ParseFontInfo(buildTestFont(test.fsType))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if font.Embedding != test.embedding || font.FSType != test.fsType {
+			t.Errorf("expected %s for fsType 0x%04X, got %s", test.embedding, test.fsType, font.Embedding)
+		}
+	}
+	if font, err := ParseFontInfo(buildTestFont(0x0300)); err != nil || !font.BitmapOnly || !font.NoSubsetting {
+		t.Errorf("expected bitmap only without subsetting, got %+v, %v", font, err)
+	}
+	if _, err := ParseFontInfo([]byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x00")); err == nil {
+		t.Error("expected an error for a font collection")
+	}
+}
+
+func TestDocument_EmbedFont(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	font := buildTestFont(0x0008)
+	if err := doc.EmbedFont("Corporate Sans", FontRegular, font); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.EmbedFont("Corporate Sans", FontBold, font); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	fontTable := readTestPart(t, buf.Bytes(), FontTableXml)
+	match := regexp.MustCompile(`<w:font w:name="Corporate Sans"><w:embedRegular r:id="(rId\d+)" w:fontKey="\{([0-9A-F-]{36})\}"/>` +
+		`<w:embedBold r:id="rId\d+" w:fontKey="\{[0-9A-F-]{36}\}"/></w:font>`).FindStringSubmatch(fontTable)
+	if match == nil {
+		t.Fatalf("expected the embedded fonts in the font table: %s", fontTable)
+	}
+	rels := readTestPart(t, buf.Bytes(), "word/_rels/fontTable.xml.rels")
+	if !strings.Contains(rels, `Id="`+match[1]+`" Type="`+RelationshipTypeFont+`" Target="fonts/font1.odttf"`) {
+		t.Errorf("expected the relationship of the font: %s", rels)
+	}
+	if settings := readTestPart(t, buf.Bytes(), SettingsXml); !strings.Contains(settings, "<w:embedTrueTypeFonts/>") {
+		t.Errorf("expected fonts to be embedded by the settings: %s", settings)
+	}
+	if types := readTestPart(t, buf.Bytes(), ContentTypesXml); !strings.Contains(types, `Extension="odttf"`) ||
+		!strings.Contains(types, `PartName="/word/fontTable.xml"`) {
+		t.Errorf("expected the content types of the fonts: %s", types)
+	}
+
+	// deobfuscating the font with its key returns the original font
+	key, err := hex.DecodeString(strings.ReplaceAll(match[2], "-", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedded := []byte(readTestPart(t, buf.Bytes(), "word/fonts/font1.odttf"))
+	for i := 0; i < 32; i++ {
+		embedded[i] ^= key[15-i%16]
+	}
+	if !bytes.Equal(embedded, font) {
+		t.Error("expected the deobfuscated font to equal the original font")
+	}
+}
+
+func TestDocument_EmbedFontPolicy(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.EmbedFont("Restricted Serif", FontRegular, buildTestFont(0x0002)); !errors.Is(err, ErrFontNotPermitted) {
+		t.Errorf("expected a restricted font to be refused, got %v", err)
+	}
+
+	var warned []string
+	doc.SetFontPolicy(FontPolicy{
+		Allowed:  []string{"restricted serif", "Corporate Sans"},
+		WarnOnly: true,
+		Warn:     func(font FontInfo, reason error) { warned = append(warned, font.Name) },
+		Check: func(font FontInfo) error {
+			if font.Style == FontItalic {
+				return errors.New("italic is not licensed")
+			}
+			return nil
+		},
+	})
+	if err := doc.EmbedFont("Restricted Serif", FontRegular, buildTestFont(0x0002)); err != nil {
+		t.Errorf("expected a warning only, got %v", err)
+	}
+	if strings.Join(warned, ",") != "Restricted Serif" {
+		t.Errorf("unexpected warnings %v", warned)
+	}
+	if err := doc.EmbedFont("Other Sans", FontRegular, buildTestFont(0)); !errors.Is(err, ErrFontNotPermitted) {
+		t.Errorf("expected a font which is not allowed to be refused, got %v", err)
+	}
+	if err := doc.EmbedFont("Corporate Sans", FontItalic, buildTestFont(0)); !errors.Is(err, ErrFontNotPermitted) {
+		t.Errorf("expected the check to refuse the font, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RelationshipTypeFootnotes is the relationship type of the footnotes part.
+	RelationshipTypeFootnotes = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes"
+	// ContentTypeFootnotes is the content type of the footnotes part.
+	ContentTypeFootnotes = "application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml"
+)
+
+var (
+	// footnoteIdRegex matches the footnotes of the footnotes part and captures their ID.
+	footnoteIdRegex = regexp.MustCompile(`<w:footnote\s[^>]*?w:id="(-?[0-9]+)"`)
+
+	// emptyFootnotes is the footnotes part of documents without footnotes, it only contains the separators which
+	// Word requires in front of the footnotes of a page.
+	emptyFootnotes = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<w:footnote w:type="separator" w:id="-1"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr>` +
+		`<w:r><w:separator/></w:r></w:p></w:footnote>` +
+		`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr>` +
+		`<w:r><w:continuationSeparator/></w:r></w:p></w:footnote></w:footnotes>`)
+)
+
+// TextWithFootnote is a replacement value which inserts the text followed by the reference of a new footnote, e.g.
+// to cite the source of a generated claim. The footnote is added to the footnotes part, which is created if the
+// document has no footnotes yet. Word numbers the footnotes by the position of their references, so the numbers
+// are correct no matter in which order the placeholders are replaced.
+//
+// The footnote may be any replacement value, e.g. a string or a Hyperlink to the source. The reference and the
+// footnote use the FootnoteReference and FootnoteText styles of the document, or superscript references if the
+// styles do not exist. Footnotes can only be placed inside the document body, not in headers, footers or notes.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "claim": docx.TextWithFootnote{
+//	        Text:     "Revenue grew by 12% in 2025.",
+//	        Footnote: docx.Hyperlink{Text: "Annual report 2025", URL: "https://example.com/report-2025.pdf"},
+//	    },
+//	})
+type TextWithFootnote struct {
+	Text     string
+	Footnote interface{}
+}
+
+// String returns the text followed by the footnote in brackets.
+func (t TextWithFootnote) String() string {
+	return fmt.Sprintf("%s [%v]", t.Text, t.Footnote)
+}
+
+// inlineXml returns the runs of the text followed by a run with the reference of the footnote.
+func (t TextWithFootnote) inlineXml(ctx *valueContext) (string, error) {
+	if ctx.part != DocumentXml {
+		return "", fmt.Errorf("footnotes must be placed inside the document body, not %s", ctx.part)
+	}
+	text, err := ctx.valueXml(t.Text)
+	if err != nil {
+		return "", err
+	}
+	id, err := ctx.doc.addFootnote(t.Footnote)
+	if err != nil {
+		return "", err
+	}
+	properties := ctx.doc.footnoteReferenceProperties(ctx.runProperties)
+	return text + fmt.Sprintf(`<w:r>%s<w:footnoteReference w:id="%d"/></w:r>`, properties, id), nil
+}
+
+// addFootnote adds a footnote with the content to the footnotes which are inserted after the current replacement
+// and returns its ID.
+func (d *Document) addFootnote(content interface{}) (int, error) {
+	id, err := d.nextFootnoteId()
+	if err != nil {
+		return 0, err
+	}
+	ctx := &valueContext{doc: d, part: FootnotesXml}
+	runs, err := ctx.valueXml(content)
+	if err != nil {
+		return 0, err
+	}
+
+	var paragraphProperties string
+	if d.styleExists("FootnoteText") {
+		paragraphProperties = `<w:pPr><w:pStyle w:val="FootnoteText"/></w:pPr>`
+	}
+	// the footnote starts with its own number, followed by a space
+	mark := `<w:r>` + d.footnoteReferenceProperties("") + `<w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> </w:t></w:r>`
+	d.footnotes = append(d.footnotes, fmt.Sprintf(`<w:footnote w:id="%d"><w:p>%s%s%s</w:p></w:footnote>`,
+		id, paragraphProperties, mark, runs))
+	return id, nil
+}
+
+// nextFootnoteId returns an ID for a new footnote which does not collide with the footnotes of the document.
+func (d *Document) nextFootnoteId() (int, error) {
+	if d.footnoteId == 0 {
+		data, _, err := d.part(FootnotesXml)
+		if err != nil {
+			return 0, err
+		}
+		for _, match := range footnoteIdRegex.FindAllSubmatch(data, -1) {
+			id, err := strconv.Atoi(string(match[1]))
+			if err == nil && id > d.footnoteId {
+				d.footnoteId = id
+			}
+		}
+	}
+	d.footnoteId++
+	return d.footnoteId, nil
+}
+
+// footnoteReferenceProperties returns the run properties with the formatting of footnote references applied: the
+// FootnoteReference character style if the document defines it, otherwise superscript.
+func (d *Document) footnoteReferenceProperties(runProperties string) string {
+	if d.styleExists("FootnoteReference") {
+		return setRunProperty(runProperties, "rStyle", `<w:rStyle w:val="FootnoteReference"/>`)
+	}
+	return setRunProperty(runProperties, "vertAlign", `<w:vertAlign w:val="superscript"/>`)
+}
+
+// insertFootnotes inserts the footnotes which were added by the last replacement into the footnotes part, which is
+// added to the document if it does not exist yet.
+func (d *Document) insertFootnotes() error {
+	if len(d.footnotes) == 0 {
+		return nil
+	}
+	notes := strings.Join(d.footnotes, "")
+	d.footnotes = nil
+
+	data, exists := d.files[FootnotesXml]
+	if !exists {
+		if _, err := d.addRelationship(DocumentXml, RelationshipTypeFootnotes, "footnotes.xml", false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(FootnotesXml, ContentTypeFootnotes); err != nil {
+			return err
+		}
+		data = emptyFootnotes
+	}
+	end := bytes.LastIndex(data, []byte("</w:footnotes>"))
+	if end < 0 {
+		return fmt.Errorf("invalid footnotes part %s", FootnotesXml)
+	}
+	changed := insertAt(data, []int{end, end}, notes)
+
+	if !exists {
+		if err := d.addFile(FootnotesXml, changed); err != nil {
+			return err
+		}
+		d.noteFiles = append(d.noteFiles, FootnotesXml)
+		return nil
+	}
+	if err := d.SetFile(FootnotesXml, changed); err != nil {
+		return err
+	}
+	return d.parseFile(FootnotesXml)
+}
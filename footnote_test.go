@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTextWithFootnote(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{claim}</w:t></w:r></w:p><w:p><w:r><w:t>{source}</w:t></w:r></w:p>`)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	err = doc.ReplaceAll(PlaceholderMap{
+		"claim": TextWithFootnote{Text: "Revenue grew by 12%.", Footnote: "Annual report 2025, p. 4"},
+		"source": TextWithFootnote{
+			Text:     "Costs fell.",
+			Footnote: Hyperlink{Text: "Cost study", URL: "https://example.com/costs"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.Bytes()
+
+	document := readTestPart(t, output, DocumentXml)
+	if !strings.Contains(document, `<w:t xml:space="preserve">Revenue grew by 12%.</w:t></w:r><w:r><w:rPr><w:b/><w:vertAlign w:val="superscript"/></w:rPr><w:footnoteReference w:id="`) {
+		t.Errorf("expected the text followed by a superscript reference in %s", document)
+	}
+	references := regexp.MustCompile(`<w:footnoteReference w:id="([0-9]+)"/>`).FindAllStringSubmatch(document, -1)
+	if len(references) != 2 || references[0][1] == references[1][1] {
+		t.Fatalf("expected two different footnote references in %s", document)
+	}
+
+	footnotes := readTestPart(t, output, FootnotesXml)
+	for _, expected := range []string{
+		`<w:footnote w:type="separator" w:id="-1">`,
+		`<w:footnote w:id="` + references[0][1] + `"><w:p><w:r><w:rPr><w:vertAlign w:val="superscript"/></w:rPr><w:footnoteRef/></w:r>` +
+			`<w:r><w:t xml:space="preserve"> </w:t></w:r><w:r><w:t xml:space="preserve">Annual report 2025, p. 4</w:t></w:r></w:p></w:footnote>`,
+		`<w:footnote w:id="` + references[1][1] + `">`,
+		`<w:hyperlink r:id="rId1" w:history="1">`,
+	} {
+		if !strings.Contains(footnotes, expected) {
+			t.Errorf("expected %s in %s", expected, footnotes)
+		}
+	}
+	if rels := readTestPart(t, output, "word/_rels/document.xml.rels"); !strings.Contains(rels, `Type="`+RelationshipTypeFootnotes+`" Target="footnotes.xml"`) {
+		t.Errorf("expected the footnotes relationship in %s", rels)
+	}
+	if rels := readTestPart(t, output, "word/_rels/footnotes.xml.rels"); !strings.Contains(rels, `Target="https://example.com/costs"`) {
+		t.Errorf("expected the hyperlink relationship of the footnotes in %s", rels)
+	}
+	if types := readTestPart(t, output, ContentTypesXml); !strings.Contains(types, `<Override PartName="/word/footnotes.xml" ContentType="`+ContentTypeFootnotes+`"/>`) {
+		t.Errorf("expected the footnotes content type in %s", types)
+	}
+}
+
+func TestTextWithFootnote_ExistingFootnotes(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Known fact</w:t><w:footnoteReference w:id="1"/></w:r><w:r><w:t>{claim}</w:t></w:r></w:p>`,
+		FootnotesXml, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>`+
+			`<w:footnote w:id="1"><w:p><w:r><w:t>Existing {note}</w:t></w:r></w:p></w:footnote></w:footnotes>`)
+	output, err := ProcessValues(input, PlaceholderMap{
+		"claim": TextWithFootnote{Text: "New claim", Footnote: "New source"},
+		"note":  "note",
+	}, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, `<w:footnoteReference w:id="2"/>`) {
+		t.Errorf("expected the next free footnote ID in %s", document)
+	}
+	footnotes := readTestPart(t, output, FootnotesXml)
+	if !strings.Contains(footnotes, `Existing note`) || !strings.Contains(footnotes, `<w:footnote w:id="2">`) ||
+		!strings.HasSuffix(footnotes, `New source</w:t></w:r></w:p></w:footnote></w:footnotes>`) {
+		t.Errorf("unexpected footnotes %s", footnotes)
+	}
+
+	header := buildTestDocx(t, `<w:p/>`, "word/header1.xml", `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{claim}</w:t></w:r></w:p></w:hdr>`)
+	if _, err := ProcessValues(header, PlaceholderMap{"claim": TextWithFootnote{Text: "claim", Footnote: "source"}}, RenderOptions{}); err == nil {
+		t.Error("expected an error for footnotes in headers")
+	}
+}
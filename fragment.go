@@ -0,0 +1,236 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// BodyContentRegex matches the body of a document and captures its content.
+	BodyContentRegex = regexp.MustCompile(`(?s)<w:body>(.*)</w:body>`)
+	// RelationshipReferenceRegex matches all attributes which reference a relationship (r:id, r:embed, r:link, ...)
+	// and captures the part in front of the ID, the ID and the closing quote.
+	RelationshipReferenceRegex = regexp.MustCompile(`(\sr:(?:id|embed|link|pict|dm|lo|qs|cs)=")([^"]*)(")`)
+)
+
+// Fragment is a replacement value which inserts the body of another DOCX document, e.g. a shared clause or a
+// boilerplate section. If the placeholder is the only text of its paragraph, the paragraph is replaced by the
+// paragraphs and tables of the fragment, otherwise the paragraph is split at the placeholder.
+//
+// Images, external hyperlinks and the styles used by the fragment are copied into the document. Styles which already
+// exist inside the document keep the definition of the document. Numbering is not copied, list paragraphs of the
+// fragment are inserted as regular paragraphs. Fragments using other related parts (e.g. charts) are rejected.
+// Fragments are usually loaded from a FragmentProvider with LoadFragment.
+//
+// Example:
+//
+//	liability, err := docx.LoadFragment(ctx, provider, "clauses/liability", "2.1")
+//	doc.ReplaceAll(docx.PlaceholderMap{"liability": liability})
+type Fragment struct {
+	// Name and Version identify the fragment inside error messages.
+	Name, Version string
+	// Data is the DOCX document of the fragment.
+	Data []byte
+}
+
+// inlineXml returns a run with the marker of the fragment, the fragment is inserted by insertBlocks.
+func (f Fragment) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.blockMarkerRun(f), nil
+}
+
+// blockXml returns the body of the fragment.
+func (f Fragment) blockXml(ctx *valueContext) (string, error) {
+	return ctx.doc.fragmentBody(ctx.part, f)
+}
+
+// String returns the name and version of the fragment.
+func (f Fragment) String() string {
+	if f.Version == "" {
+		return f.Name
+	}
+	return f.Name + "@" + f.Version
+}
+
+// fragmentBody returns the paragraphs and tables of the fragment, prepared for the insertion into the given part.
+// Relationships and styles of the fragment are copied into the document.
+func (d *Document) fragmentBody(part string, fragment Fragment) (string, error) {
+	fragmentRels := relationshipsPart(DocumentXml)
+	parts, err := readArchiveParts(fragment.Data, func(name string) bool {
+		return name == DocumentXml || name == fragmentRels || name == StylesXml || MediaPathRegex.MatchString(name)
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid fragment %s: %w", fragment, err)
+	}
+	match := BodyContentRegex.FindSubmatch(parts[DocumentXml])
+	if match == nil {
+		return "", fmt.Errorf("invalid fragment %s, %s has no body", fragment, DocumentXml)
+	}
+	body := string(match[1])
+
+	// section properties of the fragment would add section breaks to the document
+	sections := sectionProperties([]byte(body))
+	for i := len(sections) - 1; i >= 0; i-- {
+		body = body[:sections[i][0]] + body[sections[i][1]:]
+	}
+
+	source := &contentSource{
+		name: "fragment " + fragment.String(),
+		part: func(name string) ([]byte, bool, error) {
+			data, exists := parts[name]
+			return data, exists, nil
+		},
+	}
+	return d.importContent(part, source, DocumentXml, body, make(map[string]string))
+}
+
+// contentSource is another document whose content is inserted into the document, e.g. a fragment.
+type contentSource struct {
+	// name describes the document inside error messages, e.g. 'fragment clauses/liability@2.1'.
+	name string
+	// part returns the content of the part with the given name, see Document.part.
+	part func(name string) ([]byte, bool, error)
+	// numIds maps the numbering IDs of the document to the IDs of the copied numbering, see Document.copyNumbering.
+	// If it is nil, the numbering is removed and list paragraphs are inserted as regular paragraphs.
+	numIds map[string]string
+}
+
+// importContent prepares the content of the part sourcePart of the source for the insertion into the given part.
+// Relationships, drawing object IDs and numbering are remapped and the referenced styles are copied into the document.
+// Relationships which were already copied are remembered inside relIds, keyed by their ID inside the source.
+func (d *Document) importContent(part string, source *contentSource, sourcePart, content string, relIds map[string]string) (string, error) {
+	if source.numIds == nil {
+		content = numberingPropertiesRegex.ReplaceAllString(content, "")
+	} else {
+		content = source.remapNumbering(content)
+	}
+
+	// relationships are added to the part into which the content is inserted
+	rels, _, err := source.part(relationshipsPart(sourcePart))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", source.name, err)
+	}
+	relationships := make(map[string]relationship)
+	for _, rel := range parseRelationships(rels) {
+		relationships[rel.id] = rel
+	}
+	var relErr error
+	content = RelationshipReferenceRegex.ReplaceAllStringFunc(content, func(reference string) string {
+		groups := RelationshipReferenceRegex.FindStringSubmatch(reference)
+		id, err := d.copyRelationship(part, source, sourcePart, relationships, html.UnescapeString(groups[2]), relIds)
+		if err != nil {
+			if relErr == nil {
+				relErr = err
+			}
+			return reference
+		}
+		return groups[1] + id + groups[3]
+	})
+	if relErr != nil {
+		return "", relErr
+	}
+
+	// drawing object IDs must be unique across the document
+	content = DocPrIdRegex.ReplaceAllStringFunc(content, func(docPr string) string {
+		groups := DocPrIdRegex.FindStringSubmatchIndex(docPr)
+		return docPr[:groups[2]] + strconv.Itoa(d.nextDocPrId()) + docPr[groups[3]:]
+	})
+
+	if err := d.copyStyles(content, source); err != nil {
+		return "", fmt.Errorf("unable to copy styles of %s: %w", source.name, err)
+	}
+	return content, nil
+}
+
+// copyRelationship copies the relationship with the given ID of the part sourcePart of the source into the part and
+// returns the new ID. Copied relationships are remembered inside relIds.
+func (d *Document) copyRelationship(part string, source *contentSource, sourcePart string, relationships map[string]relationship,
+	id string, relIds map[string]string) (string, error) {
+	if copied, exists := relIds[id]; exists {
+		return copied, nil
+	}
+	rel, exists := relationships[id]
+	if !exists {
+		return "", fmt.Errorf("invalid %s, relationship %s does not exist", source.name, id)
+	}
+
+	var copied string
+	var err error
+	switch {
+	case rel.external:
+		copied, err = d.addRelationship(part, rel.relType, rel.target, true)
+	case rel.relType == RelationshipTypeImage:
+		var data []byte
+		if data, exists, err = source.part(rel.partName(sourcePart)); err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", fmt.Errorf("invalid %s, image %s does not exist", source.name, rel.target)
+		}
+		copied, err = d.addImage(part, data)
+	default:
+		return "", fmt.Errorf("%s uses the unsupported relationship type %s", source.name, rel.relType)
+	}
+	if err != nil {
+		return "", err
+	}
+	relIds[id] = copied
+	return copied, nil
+}
+
+// copyStyles copies all styles which are referenced by the content, and the styles they are based on,
+// from the style definitions of the source into the document unless the document already defines them.
+func (d *Document) copyStyles(content string, source *contentSource) error {
+	sourceStyles, _, err := source.part(StylesXml)
+	if err != nil || len(sourceStyles) == 0 {
+		return err
+	}
+	data, styles, err := d.styles()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = emptyStyles
+	}
+	defined := make(map[string]bool)
+	for _, style := range styles {
+		defined[style.id] = true
+	}
+	available := make(map[string]styleInfo)
+	for _, style := range parseStyles(sourceStyles) {
+		available[style.id] = style
+	}
+
+	var definitions strings.Builder
+	pending := StyleReferenceRegex.FindAllStringSubmatch(content, -1)
+	for len(pending) > 0 {
+		id := html.UnescapeString(pending[0][2])
+		pending = pending[1:]
+		style, exists := available[id]
+		if defined[id] || !exists {
+			continue
+		}
+		defined[id] = true
+		if source.numIds == nil {
+			definitions.WriteString(numberingPropertiesRegex.ReplaceAllString(style.definition, ""))
+		} else {
+			definitions.WriteString(source.remapNumbering(style.definition))
+		}
+		pending = append(pending, StyleReferenceRegex.FindAllStringSubmatch(style.definition, -1)...)
+	}
+	if definitions.Len() == 0 {
+		return nil
+	}
+
+	pos := bytes.LastIndex(data, []byte("</w:styles>"))
+	if pos < 0 {
+		return fmt.Errorf("invalid styles part %s", StylesXml)
+	}
+	changed := append([]byte{}, data[:pos]...)
+	changed = append(changed, definitions.String()...)
+	changed = append(changed, data[pos:]...)
+	return d.setStyles(changed)
+}
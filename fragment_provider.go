@@ -0,0 +1,205 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrFragmentNotFound is returned by a FragmentProvider if the requested fragment or version does not exist.
+var ErrFragmentNotFound = errors.New("fragment not found")
+
+// FragmentProvider resolves shared fragments (DOCX documents) by name and version, so that organizations can keep
+// shared clauses and boilerplate in a central, versioned registry instead of copying them into every template.
+// Implementations must be safe for concurrent use.
+type FragmentProvider interface {
+	// Fragment returns the DOCX document of the given version of the fragment.
+	// An empty version requests the latest version.
+	// ErrFragmentNotFound is returned if the fragment or the version does not exist.
+	Fragment(ctx context.Context, name, version string) ([]byte, error)
+}
+
+// LoadFragment resolves the fragment using the provider and returns it as replacement value, see Fragment.
+func LoadFragment(ctx context.Context, provider FragmentProvider, name, version string) (Fragment, error) {
+	data, err := provider.Fragment(ctx, name, version)
+	if err != nil {
+		return Fragment{}, fmt.Errorf("unable to load fragment %s: %w", Fragment{Name: name, Version: version}, err)
+	}
+	return Fragment{Name: name, Version: version, Data: data}, nil
+}
+
+// FSFragmentProvider provides the fragments of a file system.
+// Every fragment is a directory containing one DOCX file per version, e.g. 'clauses/liability/2.1.docx'.
+// The latest version is the highest version number, versions are compared numerically per dot separated segment
+// with an optional 'v' prefix, so '2.10' is later than '2.9'. Unversioned fragments are single files,
+// e.g. 'clauses/liability.docx', which are used if the fragment has no version directory.
+type FSFragmentProvider struct {
+	fsys fs.FS
+}
+
+// NewFSFragmentProvider returns a FragmentProvider for the fragments of the file system.
+//
+// Example:
+//
+//	provider := docx.NewFSFragmentProvider(os.DirFS("/srv/fragments"))
+func NewFSFragmentProvider(fsys fs.FS) *FSFragmentProvider {
+	return &FSFragmentProvider{fsys: fsys}
+}
+
+// Fragment returns the DOCX document of the given version of the fragment.
+func (p *FSFragmentProvider) Fragment(ctx context.Context, name, version string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !fs.ValidPath(name) || name == "." || strings.Contains(version, "/") {
+		return nil, fmt.Errorf("%w: invalid fragment %s version %s", ErrFragmentNotFound, name, version)
+	}
+
+	if version == "" {
+		entries, err := fs.ReadDir(p.fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return p.read(name + ".docx")
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			candidate, isDocx := strings.CutSuffix(entry.Name(), ".docx")
+			if entry.IsDir() || !isDocx {
+				continue
+			}
+			if version == "" || compareVersions(candidate, version) > 0 {
+				version = candidate
+			}
+		}
+		if version == "" {
+			return nil, fmt.Errorf("%w: %s has no versions", ErrFragmentNotFound, name)
+		}
+	}
+	return p.read(path.Join(name, version+".docx"))
+}
+
+// read returns the content of the file, a missing file is reported as ErrFragmentNotFound.
+func (p *FSFragmentProvider) read(name string) ([]byte, error) {
+	data, err := fs.ReadFile(p.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFragmentNotFound, name)
+	}
+	return data, err
+}
+
+// compareVersions compares two versions like '1.2' and 'v1.10' segment by segment.
+// Numeric segments are compared by value, all other segments lexically.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// HTTPFragmentProvider provides the fragments of a registry which is accessed via HTTP.
+// A fragment version is requested with GET from '<BaseURL>/<name>/<version>.docx', the latest version
+// from '<BaseURL>/<name>/latest.docx'. The registry must answer 404 Not Found for unknown fragments.
+type HTTPFragmentProvider struct {
+	// BaseURL is the URL of the registry, e.g. 'https://fragments.example.com/v1'.
+	BaseURL string
+	// Client is the client used for all requests, http.DefaultClient is used if nil.
+	Client *http.Client
+	// MaxSize is the maximum size of a fragment in bytes, DefaultParseLimits.MaxTotalSize is used if zero.
+	MaxSize int64
+	// Fetcher downloads the fragments with its limits if set, Client and MaxSize are not used then.
+	Fetcher *Fetcher
+}
+
+// NewHTTPFragmentProvider returns a FragmentProvider for the registry at the given URL using http.DefaultClient.
+//
+// Example:
+//
+//	provider := docx.NewHTTPFragmentProvider("https://fragments.example.com/v1")
+func NewHTTPFragmentProvider(baseURL string) *HTTPFragmentProvider {
+	return &HTTPFragmentProvider{BaseURL: baseURL}
+}
+
+// Fragment returns the DOCX document of the given version of the fragment.
+func (p *HTTPFragmentProvider) Fragment(ctx context.Context, name, version string) ([]byte, error) {
+	if version == "" {
+		version = "latest"
+	}
+	segments := strings.Split(strings.Trim(name, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	target := strings.TrimSuffix(p.BaseURL, "/") + "/" + strings.Join(segments, "/") + "/" + url.PathEscape(version) + ".docx"
+
+	if p.Fetcher != nil {
+		response, release, err := p.Fetcher.get(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		if err := checkFragmentStatus(response, target); err != nil {
+			return nil, err
+		}
+		return p.Fetcher.read(ctx, response, target)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if err := checkFragmentStatus(response, target); err != nil {
+		return nil, err
+	}
+
+	maxSize := p.MaxSize
+	if maxSize <= 0 {
+		maxSize = currentParseLimits().MaxTotalSize
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: fragment %s exceeds %d bytes", ErrLimitExceeded, target, maxSize)
+	}
+	return data, nil
+}
+
+// checkFragmentStatus returns an error if the response of the registry does not contain the fragment.
+func checkFragmentStatus(response *http.Response, target string) error {
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrFragmentNotFound, target)
+	case response.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %s for %s", response.Status, target)
+	}
+	return nil
+}
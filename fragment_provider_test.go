@@ -0,0 +1,100 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFSFragmentProvider(t *testing.T) {
+	provider := NewFSFragmentProvider(fstest.MapFS{
+		"clauses/liability/2.1.docx":  {Data: []byte("2.1")},
+		"clauses/liability/2.9.docx":  {Data: []byte("2.9")},
+		"clauses/liability/2.10.docx": {Data: []byte("2.10")},
+		"clauses/liability/notes.txt": {Data: []byte("notes")},
+		"clauses/privacy.docx":        {Data: []byte("privacy")},
+	})
+
+	tests := []struct {
+		name, version, expected string
+	}{
+		{"clauses/liability", "2.1", "2.1"},
+		{"clauses/liability", "", "2.10"},
+		{"clauses/privacy", "", "privacy"},
+	}
+	for _, tt := range tests {
+		data, err := provider.Fragment(context.Background(), tt.name, tt.version)
+		if err != nil {
+			t.Fatalf("%s@%s: %s", tt.name, tt.version, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("%s@%s: expected %s, have %s", tt.name, tt.version, tt.expected, data)
+		}
+	}
+
+	for _, missing := range [][2]string{{"clauses/liability", "3.0"}, {"clauses/unknown", ""}, {"../secrets", ""}, {"clauses/liability", "../privacy"}} {
+		if _, err := provider.Fragment(context.Background(), missing[0], missing[1]); !errors.Is(err, ErrFragmentNotFound) {
+			t.Errorf("%s@%s: expected ErrFragmentNotFound, have %v", missing[0], missing[1], err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2", "1.10", -1},
+		{"v2", "1.9", 1},
+		{"1.0", "1.0.1", -1},
+		{"1.0-beta", "1.0-alpha", 1},
+		{"3", "v3", 0},
+	}
+	for _, tt := range tests {
+		if have := compareVersions(tt.a, tt.b); (have > 0) != (tt.expected > 0) || (have < 0) != (tt.expected < 0) {
+			t.Errorf("compareVersions(%s, %s): expected %d, have %d", tt.a, tt.b, tt.expected, have)
+		}
+	}
+}
+
+func TestHTTPFragmentProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/v1/clauses/liability/latest.docx":
+			w.Write([]byte("latest"))
+		case "/v1/clauses/liability/2.1.docx":
+			w.Write([]byte("version 2.1 of the liability clause"))
+		case "/v1/clauses/broken/latest.docx":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewHTTPFragmentProvider(server.URL + "/v1/")
+	data, err := provider.Fragment(context.Background(), "clauses/liability", "")
+	if err != nil || string(data) != "latest" {
+		t.Errorf("expected latest version, have %q, %v", data, err)
+	}
+
+	fragment, err := LoadFragment(context.Background(), provider, "clauses/liability", "2.1")
+	if err != nil || fragment.String() != "clauses/liability@2.1" {
+		t.Errorf("expected fragment 2.1, have %s, %v", fragment, err)
+	}
+
+	if _, err := provider.Fragment(context.Background(), "clauses/unknown", ""); !errors.Is(err, ErrFragmentNotFound) {
+		t.Errorf("expected ErrFragmentNotFound, have %v", err)
+	}
+	if _, err := provider.Fragment(context.Background(), "clauses/broken", ""); err == nil || errors.Is(err, ErrFragmentNotFound) {
+		t.Errorf("expected server error, have %v", err)
+	}
+
+	provider.MaxSize = 10
+	if _, err := provider.Fragment(context.Background(), "clauses/liability", "2.1"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded, have %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package docx
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Fragment(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fragment := Fragment{Name: "clauses/liability", Version: "2.1", Data: buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="Clause"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="3"/></w:numPr></w:pPr>`+
+			`<w:r><w:t>Liability</w:t></w:r><w:hyperlink r:id="rId2"><w:r><w:t>terms</w:t></w:r></w:hyperlink></w:p>`+
+			`<w:p><w:r><w:drawing><wp:inline><wp:docPr id="1" name="Picture"/><a:blip r:embed="rId1"/></wp:inline></w:drawing></w:r></w:p>`+
+			`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeImage+`" Target="media/image1.jpeg"/>`+
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/terms?a=1&amp;b=2" TargetMode="External"/></Relationships>`,
+		StylesXml, `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:styleId="Clause"><w:name w:val="Clause"/><w:basedOn w:val="ClauseBase"/></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="ClauseBase"><w:name w:val="Clause Base"/><w:pPr><w:numPr><w:numId w:val="3"/></w:numPr></w:pPr></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="Unused"><w:name w:val="Unused"/></w:style></w:styles>`,
+		"word/media/image1.jpeg", string(imageBytes),
+	)}
+
+	doc, err := OpenBytes(buildTestDocx(t,
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{clause}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>See {inline} below</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:drawing><wp:inline><wp:docPr id="7" name="existing"/></wp:inline></w:drawing></w:r></w:p>`,
+		StylesXml, `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:styleId="ClauseBase"><w:name w:val="Existing"/></w:style></w:styles>`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"clause": fragment, "inline": fragment}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		// the paragraph containing only the placeholder is replaced
+		`<w:body><w:p><w:pPr><w:pStyle w:val="Clause"/></w:pPr><w:r><w:t>Liability</w:t></w:r><w:hyperlink r:id="rId1">`,
+		// the paragraph is split around the fragment
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">See </w:t></w:r></w:p><w:p><w:pPr><w:pStyle w:val="Clause"/></w:pPr>`,
+		`</w:drawing></w:r></w:p><w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> below</w:t></w:r></w:p>`,
+		`<wp:docPr id="8" name="Picture"/><a:blip r:embed="rId2"/>`,
+		`<wp:docPr id="9" name="Picture"/><a:blip r:embed="rId2"/>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	for _, unexpected := range []string{"<w:numPr>", "<w:pgSz", "\uE000", "{clause}"} {
+		if strings.Contains(document, unexpected) {
+			t.Errorf("unexpected %q in document: %s", unexpected, document)
+		}
+	}
+
+	rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels")
+	if n := strings.Count(rels, RelationshipTypeImage); n != 1 {
+		t.Errorf("expected image to be related once, have %d: %s", n, rels)
+	}
+	if !strings.Contains(rels, `Target="https://example.com/terms?a=1&amp;b=2" TargetMode="External"`) {
+		t.Errorf("expected external hyperlink relationship: %s", rels)
+	}
+	styles := readTestPart(t, buf.Bytes(), StylesXml)
+	if !strings.Contains(styles, `w:styleId="Clause"`) || strings.Contains(styles, "Unused") || strings.Count(styles, `w:styleId="ClauseBase"`) != 1 {
+		t.Errorf("expected only missing, referenced styles to be copied: %s", styles)
+	}
+}
+
+func TestDocument_Fragment_Unsupported(t *testing.T) {
+	fragment := Fragment{Name: "chart", Data: buildTestDocx(t, `<w:p><w:r><c:chart r:id="rId1"/></w:r></w:p>`,
+		"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart" Target="charts/chart1.xml"/></Relationships>`,
+	)}
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{chart}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"chart": fragment}); err == nil || !strings.Contains(err.Error(), "unsupported relationship type") {
+		t.Errorf("expected unsupported relationship error, have %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// unpreservedWhitespaceRegex matches text with leading or trailing whitespace which is not preserved.
+var unpreservedWhitespaceRegex = regexp.MustCompile(`<w:t>(?:\s[^<]*|[^<]*\s)</w:t>`)
+
+// fuzzSeeds are real-world templates which broke or nearly broke the placeholder parser. Runs are separated by '|',
+// paragraphs by newlines. The seeds are run by every 'go test', together with the corpus in testdata/fuzz.
+var fuzzSeeds = []string{
+	"{name}",
+	"Dear {first|name}, your {order|}|{id} is ready",
+	"{{nested}} and {a{b}c} and }reversed{",
+	"{unclosed and closed} {",
+	"}{|}{|}",
+	"“smart {quotes}” and ‘{single}’",
+	"‏{שם}‏ and ‫{اسم}‬",
+	"{emoji 👩‍💻} {combining é}",
+	"{a}\n{b|}\n|{c}",
+	"  {leading}  and trailing {spaces}  ",
+	"{tab\there} {amp & lt <}",
+	"{}{}{ }",
+	"{" + strings.Repeat("x", 300) + "}",
+}
+
+// fuzzBody converts the fuzzed text into paragraphs and runs, see fuzzSeeds.
+func fuzzBody(text string) string {
+	var body strings.Builder
+	for _, paragraph := range strings.Split(text, "\n") {
+		body.WriteString("<w:p>")
+		for _, run := range strings.Split(paragraph, "|") {
+			// like Word, whitespace is only preserved where needed
+			if strings.TrimSpace(run) != run {
+				body.WriteString(`<w:r><w:t xml:space="preserve">` + html.EscapeString(run) + `</w:t></w:r>`)
+			} else {
+				body.WriteString(`<w:r><w:t>` + html.EscapeString(run) + `</w:t></w:r>`)
+			}
+		}
+		body.WriteString("</w:p>")
+	}
+	return body.String()
+}
+
+func FuzzParsePlaceholders(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(testDocumentOpen + fuzzBody(seed) + testDocumentClose))
+	}
+	f.Add([]byte(`<w:p><w:r><w:t>{a</w:t></w:r><w:r></w:r><w:r><w:t/></w:r><w:r><w:t>}</w:t></w:r></w:p>`))
+	f.Add([]byte(`<w:r><w:t>{a}</w:r></w:t><w:r><w:t>`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzParsePlaceholders(t, data)
+	})
+}
+
+// fuzzParsePlaceholders is the target of FuzzParsePlaceholders, data is the XML of a part.
+func fuzzParsePlaceholders(t testing.TB, data []byte) {
+	parser := NewRunParser(data)
+	if err := parser.Execute(); err != nil {
+		return
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), data)
+	if err != nil {
+		return
+	}
+	for _, placeholder := range placeholders {
+		placeholder.Text(data)
+	}
+}
+
+func FuzzReplaceAll(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		fuzzReplaceAll(t, text)
+	})
+}
+
+// fuzzReplaceAll is the target of FuzzReplaceAll, text is converted into the body by fuzzBody.
+func fuzzReplaceAll(t testing.TB, text string) {
+	doc, err := OpenBytes(buildTestDocx(t, fuzzBody(text)))
+	if err != nil {
+		return
+	}
+	defer doc.Close()
+
+	values := PlaceholderMap{}
+	for _, placeholder := range doc.Placeholders() {
+		// the keys keep their delimiters, removing them from keys like '{a{}' would change the key
+		key := placeholder.Text(doc.files[DocumentXml])
+		if _, exists := values[key]; !exists {
+			values[key] = "  value <" + strconv.Itoa(len(values)) + "> & more  "
+		}
+	}
+	if err := doc.ReplaceAll(values); err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the document stays well-formed and all values are inserted with their whitespace
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("document is not well-formed: %s\n%s", err, document)
+	}
+	if unpreserved := unpreservedWhitespaceRegex.FindString(document); unpreserved != "" {
+		t.Fatalf("expected whitespace to be preserved in %s: %s", unpreserved, document)
+	}
+	if len(values) > 0 && !strings.Contains(elementText([]byte(document)), "  value <0> & more  ") {
+		t.Fatalf("expected the values in the document: %s", document)
+	}
+}
+
+// fuzzTargets are the targets of the fuzz tests by the name of their corpus directory in testdata/fuzz.
+// The input is the value of a corpus file, the targets convert it like their fuzz test.
+var fuzzTargets = map[string]struct {
+	run func(t testing.TB, input string)
+	// body returns the XML of the document body which the target processes
+	body func(input string) string
+}{
+	"FuzzParsePlaceholders": {
+		run:  func(t testing.TB, input string) { fuzzParsePlaceholders(t, []byte(input)) },
+		body: func(input string) string { return input },
+	},
+	"FuzzReplaceAll": {run: fuzzReplaceAll, body: fuzzBody},
+}
+
+// fuzzReproduction records the failures of a fuzz target instead of failing the test, see TestFuzzCrashReport.
+type fuzzReproduction struct {
+	testing.TB
+	failures []string
+}
+
+func (r *fuzzReproduction) Helper() {}
+
+func (r *fuzzReproduction) Fail() {
+	r.failures = append(r.failures, "failed")
+}
+
+func (r *fuzzReproduction) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+func (r *fuzzReproduction) Error(args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+
+func (r *fuzzReproduction) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *fuzzReproduction) Fatal(args ...interface{}) {
+	r.Error(args...)
+	runtime.Goexit()
+}
+
+func (r *fuzzReproduction) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// reproduce runs the target with the input and returns its failures, including panics.
+func (r *fuzzReproduction) reproduce(run func(t testing.TB, input string), input string) []string {
+	done := make(chan struct{})
+	go func() {
+		// like the testing package, the target runs in its own goroutine which is ended by Fatal
+		defer close(done)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				r.failures = append(r.failures, fmt.Sprintf("panic: %v\n\n%s", recovered, debug.Stack()))
+			}
+		}()
+		run(r, input)
+	}()
+	<-done
+	return r.failures
+}
+
+// TestFuzzCrashReport reproduces a crash found by fuzzing and prints a report which can be pasted into an issue.
+// It only runs if DOCX_FUZZ_INPUT names a file of the corpus, e.g. after 'go test -fuzz FuzzReplaceAll' failed:
+//
+//	DOCX_FUZZ_INPUT=testdata/fuzz/FuzzReplaceAll/<id> go test -run TestFuzzCrashReport -v
+//
+// The fuzz target is chosen by the directory of the file, so inputs of FuzzParsePlaceholders and FuzzReplaceAll
+// are reproduced with the same checks which failed. The failing input stays in testdata/fuzz, so it is run by
+// every 'go test' once committed with the fix.
+func TestFuzzCrashReport(t *testing.T) {
+	path := os.Getenv("DOCX_FUZZ_INPUT")
+	if path == "" {
+		t.Skip("DOCX_FUZZ_INPUT is not set")
+	}
+	name := filepath.Base(filepath.Dir(path))
+	target, known := fuzzTargets[name]
+	if !known {
+		t.Fatalf("%s is not in the corpus directory of a fuzz test", path)
+	}
+	corpus, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// corpus files consist of the header 'go test fuzz v1' and one quoted Go value per line, e.g. []byte("...")
+	lines := strings.Split(strings.TrimSpace(string(corpus)), "\n")
+	if len(lines) != 2 || lines[0] != "go test fuzz v1" {
+		t.Fatalf("%s is not a corpus file with a single value", path)
+	}
+	value := lines[1]
+	if start := strings.IndexByte(value, '('); start >= 0 && strings.HasSuffix(value, ")") {
+		value = value[start+1 : len(value)-1]
+	}
+	input, err := strconv.Unquote(value)
+	if err != nil {
+		t.Fatalf("unable to parse the value of %s: %v", path, err)
+	}
+
+	failures := (&fuzzReproduction{TB: t}).reproduce(target.run, input)
+	if len(failures) == 0 {
+		t.Logf("the input of %s passes", name)
+		return
+	}
+	t.Errorf("### Fuzzing crash\n\nFuzz test: `%s`\n\nCorpus file: `%s`\n\nBody:\n```xml\n%s\n```\n\nFailure:\n```\n%s\n```",
+		name, path, target.body(input), strings.Join(failures, "\n"))
+}
@@ -0,0 +1,124 @@
+package docx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Glossary is a replacement value which inserts a glossary of terms and their definitions, e.g. the acronyms of a
+// long report. Every term gets its own paragraph in alphabetical order (ignoring case), the term in bold followed
+// by a tab and the definition. Paragraphs use the properties of the placeholder paragraph, text uses the formatting
+// of the placeholder run. Like fragments, the glossary replaces the paragraph of the placeholder, or splits it if
+// the paragraph has more text.
+//
+// Inside templates, terms are marked with {{gloss "SLA" "Service Level Agreement"}}, which writes the term, and the
+// glossary of all marked terms is inserted with {{glossary}}, also in front of the terms. {{gloss "SLA"}} marks a
+// term which is defined elsewhere, {{glossary .Terms}} adds the terms of a map from the data.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "glossary": docx.Glossary{"SLA": "Service Level Agreement", "RTO": "Recovery Time Objective"},
+//	})
+type Glossary map[string]string
+
+// String returns the terms and definitions separated by tabs, one term per line in alphabetical order.
+func (g Glossary) String() string {
+	var lines []string
+	for _, term := range g.terms() {
+		lines = append(lines, term+"\t"+g[term])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inlineXml returns a run with the marker of the glossary, the paragraphs are inserted by insertBlocks.
+func (g Glossary) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.blockMarkerRun(g), nil
+}
+
+// blockXml returns one paragraph per term.
+func (g Glossary) blockXml(ctx *valueContext) (string, error) {
+	properties := htmlBaseProperties(ctx.paragraphProperties)
+	termCtx := *ctx
+	termCtx.runProperties = setRunProperty(ctx.runProperties, "b", "<w:b/>")
+
+	var out strings.Builder
+	for _, term := range g.terms() {
+		if g[term] == "" {
+			return "", fmt.Errorf("glossary term %q has no definition", term)
+		}
+		termRuns, err := termCtx.valueXml(term)
+		if err != nil {
+			return "", err
+		}
+		definitionRuns, err := ctx.valueXml(g[term])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString("<w:p>" + properties + termRuns + "<w:r>" + ctx.runProperties + "<w:tab/></w:r>" + definitionRuns + "</w:p>")
+	}
+	return out.String(), nil
+}
+
+// terms returns the terms in alphabetical order, ignoring case.
+func (g Glossary) terms() []string {
+	terms := make([]string, 0, len(g))
+	for term := range g {
+		terms = append(terms, term)
+	}
+	slices.SortFunc(terms, func(a, b string) int {
+		if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	return terms
+}
+
+// define adds the term to the glossary. An empty definition marks a term which is defined elsewhere.
+// Different definitions of the same term are an error.
+func (g Glossary) define(term, definition string) error {
+	if term == "" {
+		return fmt.Errorf("empty glossary term")
+	}
+	existing := g[term]
+	switch {
+	case definition == "":
+		g[term] = existing
+	case existing != "" && existing != definition:
+		return fmt.Errorf("glossary term %q is defined as %q and %q", term, existing, definition)
+	default:
+		g[term] = definition
+	}
+	return nil
+}
+
+// funcs returns the template functions which collect the terms of this glossary:
+//
+//	{{gloss "SLA" "Service Level Agreement"}}  -> "SLA", the term is added to the glossary
+//	{{gloss "SLA"}}                            -> "SLA", the term is defined by another action or the data
+//	{{glossary}}                               -> the glossary of all terms of the document
+//	{{glossary .Terms}}                        -> the glossary including the terms of the map
+//
+// The glossary is inserted after the whole document was rendered, so it contains the terms marked behind it.
+func (g Glossary) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"gloss": func(term string, definition ...string) (string, error) {
+			if len(definition) > 1 {
+				return "", fmt.Errorf("gloss expects a term and at most one definition")
+			}
+			return term, g.define(term, strings.Join(definition, ""))
+		},
+		"glossary": func(terms ...map[string]string) (Glossary, error) {
+			for _, t := range terms {
+				for term, definition := range t {
+					if err := g.define(term, definition); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return g, nil
+		},
+	}
+}
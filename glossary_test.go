@@ -0,0 +1,69 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_Glossary(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:pPr><w:spacing w:after="0"/></w:pPr><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>{{glossary .Terms}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>The {{gloss "SLA" "Service Level Agreement"}} defines the {{gloss "rto" "recovery time objective"}}.</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Every {{gloss "SLA"}} names an {{gloss "API"}} &amp; an {{gloss "RPO" "Recovery Point Objective"}}.</w:t></w:r></w:p>`)
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Terms": map[string]string{"API": "Application Programming Interface"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+	entry := func(term, definition string) string {
+		return `<w:p><w:pPr><w:spacing w:after="0"/></w:pPr><w:r><w:rPr><w:b/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">` + term +
+			`</w:t></w:r><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:tab/></w:r><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">` + definition + `</w:t></w:r></w:p>`
+	}
+	expected := `<w:body>` + entry("API", "Application Programming Interface") + entry("RPO", "Recovery Point Objective") +
+		entry("rto", "recovery time objective") + entry("SLA", "Service Level Agreement") +
+		`<w:p><w:r><w:t xml:space="preserve">The SLA defines the rto.</w:t></w:r></w:p>`
+	if !strings.Contains(document, expected) {
+		t.Errorf("expected %s in document: %s", expected, document)
+	}
+	if !strings.Contains(document, `Every SLA names an API &amp; an RPO.`) {
+		t.Errorf("expected the terms in the text: %s", document)
+	}
+}
+
+func TestProcessTemplateDocx_GlossaryErrors(t *testing.T) {
+	for _, body := range []string{
+		`{{gloss "SLA" "Service Level Agreement"}} {{gloss "SLA" "Service Level Objective"}}`,
+		`{{gloss "SLA"}}{{glossary}}`,
+		`{{gloss ""}}`,
+	} {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+body+`</w:t></w:r></w:p>`)
+		if _, err := ProcessTemplateDocx(input, nil); err == nil {
+			t.Errorf("expected an error for %s", body)
+		}
+	}
+}
+
+func TestDocument_ReplaceGlossary(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{glossary}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glossary := Glossary{"SLA": "Service Level Agreement", "RTO": "Recovery Time Objective"}
+	if err := doc.ReplaceAll(PlaceholderMap{"glossary": glossary}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if strings.Index(document, "RTO") > strings.Index(document, "SLA") || strings.Count(document, "<w:tab/>") != 2 {
+		t.Errorf("expected two sorted glossary entries: %s", document)
+	}
+	if glossary.String() != "RTO\tRecovery Time Objective\nSLA\tService Level Agreement" {
+		t.Errorf("unexpected string %q", glossary.String())
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/izetmolla/go-docx
 
 go 1.25.1
+
+require golang.org/x/text v0.31.0
@@ -0,0 +1,347 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// RelationshipTypeHeader is the relationship type of header parts.
+	RelationshipTypeHeader = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	// RelationshipTypeFooter is the relationship type of footer parts.
+	RelationshipTypeFooter = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	// ContentTypeHeader is the content type of header parts.
+	ContentTypeHeader = "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	// ContentTypeFooter is the content type of footer parts.
+	ContentTypeFooter = "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+)
+
+// HeaderType selects the pages of a section on which a header or footer is shown. It is used for footers as well.
+type HeaderType string
+
+const (
+	// HeaderDefault is shown on all pages which do not have a first page or even page header.
+	HeaderDefault HeaderType = "default"
+	// HeaderFirstPage is shown on the first page of a section only, if the section has a different first page.
+	HeaderFirstPage HeaderType = "first"
+	// HeaderEvenPage is shown on even pages, if the document has different odd and even headers.
+	HeaderEvenPage HeaderType = "even"
+)
+
+var (
+	// SectionPropertiesTagRegex matches the open, close and self-closing tags of section properties (<w:sectPr>)
+	// and captures the closing slash and the self-closing slash.
+	SectionPropertiesTagRegex = regexp.MustCompile(`<(/?)w:sectPr(?:\s[^>]*?)?(/?)>`)
+
+	// ErrHeaderExists is returned by AddHeader and AddFooter if all sections already have a header or footer of
+	// the given type.
+	ErrHeaderExists = errors.New("header or footer already exists")
+
+	// sectionPropertiesOrder is the order of the child elements of section properties required by the schema.
+	// Header and footer references may appear in any order in front of all other elements.
+	sectionPropertiesOrder = []string{
+		"headerReference", "footerReference", "footnotePr", "endnotePr", "type", "pgSz", "pgMar", "paperSrc", "pgBorders", "lnNumType",
+		"pgNumType", "cols", "formProt", "vAlign", "noEndnote", "titlePg", "textDirection", "bidi", "rtlGutter",
+		"docGrid", "printerSettings", "sectPrChange",
+	}
+)
+
+// headerFooterKind describes the differences between headers and footers.
+type headerFooterKind struct {
+	reference, root, style, relType, contentType string
+}
+
+var (
+	headerKind = headerFooterKind{reference: "headerReference", root: "hdr", style: "Header", relType: RelationshipTypeHeader, contentType: ContentTypeHeader}
+	footerKind = headerFooterKind{reference: "footerReference", root: "ftr", style: "Footer", relType: RelationshipTypeFooter, contentType: ContentTypeFooter}
+)
+
+// SetHeader sets the header of the given type for all sections of the document.
+// The content may be any replacement value, e.g. text containing placeholders, an Image or a Checklist,
+// and is placed into a single paragraph. Placeholders inside the header are replaced by ReplaceAll afterwards.
+// Setting a first page header enables the different first page of the sections, setting an even page header enables
+// different odd and even headers of the document.
+//
+// Example:
+//
+//	doc.SetHeader(docx.HeaderFirstPage, docx.Image{Data: letterheadBytes})
+//	doc.SetHeader(docx.HeaderDefault, "{company} - Confidential")
+func (d *Document) SetHeader(kind HeaderType, content interface{}) error {
+	return d.setHeaderFooter(headerKind, -1, kind, content, false)
+}
+
+// SetFooter sets the footer of the given type for all sections of the document, see SetHeader.
+func (d *Document) SetFooter(kind HeaderType, content interface{}) error {
+	return d.setHeaderFooter(footerKind, -1, kind, content, false)
+}
+
+// AddHeader adds a header of the given type to all sections of the document which do not have one yet, e.g. to a
+// template without any header. Unlike SetHeader, existing headers are kept. ErrHeaderExists is returned if every
+// section already has a header of this type. The header part, its relationship, its content type and the section
+// references are created as needed, see SetHeader for the content.
+//
+// Example:
+//
+//	err := doc.AddFooter(docx.HeaderDefault, "{company}")
+//	if errors.Is(err, docx.ErrHeaderExists) {
+//	    // the template brings its own footer
+//	}
+func (d *Document) AddHeader(kind HeaderType, content interface{}) error {
+	return d.setHeaderFooter(headerKind, -1, kind, content, true)
+}
+
+// AddFooter adds a footer of the given type to all sections of the document which do not have one yet, see AddHeader.
+func (d *Document) AddFooter(kind HeaderType, content interface{}) error {
+	return d.setHeaderFooter(footerKind, -1, kind, content, true)
+}
+
+// SetSectionHeader sets the header of the given type for a single section of the document, see SetHeader.
+// Sections are numbered from 0 in document order, the last section is the one at the end of the body.
+func (d *Document) SetSectionHeader(section int, kind HeaderType, content interface{}) error {
+	if section < 0 {
+		return fmt.Errorf("invalid section %d", section)
+	}
+	return d.setHeaderFooter(headerKind, section, kind, content, false)
+}
+
+// SetSectionFooter sets the footer of the given type for a single section of the document, see SetSectionHeader.
+func (d *Document) SetSectionFooter(section int, kind HeaderType, content interface{}) error {
+	if section < 0 {
+		return fmt.Errorf("invalid section %d", section)
+	}
+	return d.setHeaderFooter(footerKind, section, kind, content, false)
+}
+
+// SetDifferentFirstPage enables or disables the different first page header and footer of all sections (<w:titlePg>).
+func (d *Document) SetDifferentFirstPage(enabled bool) error {
+	element := ""
+	if enabled {
+		element = "<w:titlePg/>"
+	}
+	return d.updateSections(-1, func(content string) string {
+		return setOrderedElement(content, sectionPropertiesOrder, "titlePg", element)
+	})
+}
+
+// SetEvenAndOddHeaders enables or disables different headers and footers on odd and even pages for the whole document.
+func (d *Document) SetEvenAndOddHeaders(enabled bool) error {
+	element := ""
+	if enabled {
+		element = "<w:evenAndOddHeaders/>"
+	}
+	return d.setSetting("evenAndOddHeaders", element)
+}
+
+// setHeaderFooter adds a new header or footer part with the given content and references it from the section,
+// or from all sections if section is negative. If missing is true, only sections without a reference of the same
+// type are changed.
+func (d *Document) setHeaderFooter(kind headerFooterKind, section int, pages HeaderType, content interface{}, missing bool) error {
+	switch pages {
+	case HeaderDefault, HeaderFirstPage, HeaderEvenPage:
+	default:
+		return fmt.Errorf("invalid header type %s", pages)
+	}
+	if missing {
+		data := d.files[DocumentXml]
+		exists := 0
+		sections := sectionProperties(data)
+		for _, r := range sections {
+			if _, content, _ := splitElement(string(data[r[0]:r[1]])); hasHeaderReference(content, kind.reference, pages) {
+				exists++
+			}
+		}
+		if len(sections) > 0 && exists == len(sections) {
+			return fmt.Errorf("%w: every section has a %s %s", ErrHeaderExists, pages, kind.reference)
+		}
+	}
+
+	name := d.newHeaderFooterName(kind.root)
+	paragraphProperties := ""
+	if d.styleExists(kind.style) {
+		paragraphProperties = fmt.Sprintf(`<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, kind.style)
+	}
+	ctx := &valueContext{doc: d, part: name, paragraphProperties: paragraphProperties}
+	runs, err := ctx.valueXml(content)
+	if err != nil {
+		return err
+	}
+	data := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		fmt.Sprintf(`<w:%s xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`, kind.root) +
+		"<w:p>" + paragraphProperties + runs + "</w:p>" +
+		fmt.Sprintf(`</w:%s>`, kind.root))
+
+	relId, err := d.addHeaderFooterPart(kind, name, data)
+	if err != nil {
+		return err
+	}
+
+	reference := fmt.Sprintf(`<w:%s w:type="%s" r:id="%s"/>`, kind.reference, pages, relId)
+	err = d.updateSections(section, func(content string) string {
+		if missing && hasHeaderReference(content, kind.reference, pages) {
+			return content
+		}
+		content = setHeaderReference(content, kind.reference, pages, reference)
+		if pages == HeaderFirstPage {
+			content = setOrderedElement(content, sectionPropertiesOrder, "titlePg", "<w:titlePg/>")
+		}
+		return content
+	})
+	if err != nil {
+		return err
+	}
+	if pages == HeaderEvenPage {
+		return d.SetEvenAndOddHeaders(true)
+	}
+	return nil
+}
+
+// addHeaderFooterPart adds a new header or footer part which is related to the main document and returns the ID of
+// the relationship.
+func (d *Document) addHeaderFooterPart(kind headerFooterKind, name string, data []byte) (string, error) {
+	relId, err := d.addRelationship(DocumentXml, kind.relType, strings.TrimPrefix(name, "word/"), false)
+	if err != nil {
+		return "", err
+	}
+	if err := d.ensureContentTypeOverride(name, kind.contentType); err != nil {
+		return "", err
+	}
+	if err := d.addFile(name, data); err != nil {
+		return "", err
+	}
+	if kind == headerKind {
+		d.headerFiles = append(d.headerFiles, name)
+	} else {
+		d.footerFiles = append(d.footerFiles, name)
+	}
+	return relId, nil
+}
+
+// newHeaderFooterName returns the name of a new header (root 'hdr') or footer (root 'ftr') part.
+func (d *Document) newHeaderFooterName(root string) string {
+	prefix := "header"
+	if root == footerKind.root {
+		prefix = "footer"
+	}
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("word/%s%d.xml", prefix, i)
+		if !d.hasPart(name) {
+			return name
+		}
+	}
+}
+
+// styleExists returns true if a style with the given ID exists inside the document.
+func (d *Document) styleExists(id string) bool {
+	_, styles, err := d.styles()
+	if err != nil {
+		return false
+	}
+	for _, style := range styles {
+		if style.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHeaderReference returns true if the content of section properties has a header or footer reference of the
+// given type.
+func hasHeaderReference(content, element string, pages HeaderType) bool {
+	for _, child := range childElements(content) {
+		if child.name == element && strings.Contains(content[child.start:child.end], fmt.Sprintf(`w:type="%s"`, pages)) {
+			return true
+		}
+	}
+	return false
+}
+
+// setHeaderReference sets the header or footer reference of the given type inside the content of section properties.
+// An existing reference of the same type is replaced, otherwise the reference is added behind all other references.
+func setHeaderReference(content, element string, pages HeaderType, reference string) string {
+	insertAt := 0
+	for _, child := range childElements(content) {
+		if child.name != "headerReference" && child.name != "footerReference" {
+			break
+		}
+		if child.name == element && strings.Contains(content[child.start:child.end], fmt.Sprintf(`w:type="%s"`, pages)) {
+			return content[:child.start] + reference + content[child.end:]
+		}
+		insertAt = child.end
+	}
+	return content[:insertAt] + reference + content[insertAt:]
+}
+
+// sectionProperties returns the [start, end) offsets of all section properties of the document body in document order.
+// Section properties nested inside other section properties (tracked changes) are not included.
+func sectionProperties(data []byte) (sections [][2]int) {
+	depth, start := 0, 0
+	for _, match := range SectionPropertiesTagRegex.FindAllSubmatchIndex(data, -1) {
+		closing := match[3] > match[2]
+		selfClosing := match[5] > match[4]
+		switch {
+		case closing:
+			depth--
+			if depth == 0 {
+				sections = append(sections, [2]int{start, match[1]})
+			}
+		case selfClosing:
+			if depth == 0 {
+				sections = append(sections, [2]int{match[0], match[1]})
+			}
+		default:
+			if depth == 0 {
+				start = match[0]
+			}
+			depth++
+		}
+	}
+	return sections
+}
+
+// updateSections applies update to the content of the given section properties, or of all sections if section is
+// negative. A body without section properties gets section properties at its end.
+func (d *Document) updateSections(section int, update func(content string) string) error {
+	data := d.files[DocumentXml]
+	sections := sectionProperties(data)
+	if len(sections) == 0 {
+		end := strings.LastIndex(string(data), "</w:body>")
+		if end < 0 {
+			return fmt.Errorf("invalid document, body is missing")
+		}
+		data = append(append(append([]byte{}, data[:end]...), "<w:sectPr/>"...), data[end:]...)
+		sections = [][2]int{{end, end + len("<w:sectPr/>")}}
+	}
+	if section >= len(sections) {
+		return fmt.Errorf("invalid section %d, the document has %d sections", section, len(sections))
+	}
+
+	changed := string(data)
+	// sections are updated from the end so the offsets of the preceding sections stay valid
+	for i := len(sections) - 1; i >= 0; i-- {
+		if section >= 0 && i != section {
+			continue
+		}
+		sectPr := changed[sections[i][0]:sections[i][1]]
+		openTag, content, closeTag := splitElement(sectPr)
+		changed = changed[:sections[i][0]] + openTag + update(content) + closeTag + changed[sections[i][1]:]
+	}
+	if err := d.SetFile(DocumentXml, []byte(changed)); err != nil {
+		return err
+	}
+	return d.parseFile(DocumentXml)
+}
+
+// splitElement splits a complete element into its open tag, its content and its close tag.
+// Self-closing elements are split into an open and a close tag with empty content.
+func splitElement(element string) (string, string, string) {
+	openEnd := strings.IndexByte(element, '>') + 1
+	if strings.HasSuffix(element[:openEnd], "/>") {
+		openTag := strings.TrimSpace(strings.TrimSuffix(element[:openEnd], "/>")) + ">"
+		name := strings.Fields(strings.Trim(openTag, "<>"))[0]
+		return openTag, "", "</" + name + ">"
+	}
+	closeStart := strings.LastIndex(element, "</")
+	return element[:openEnd], element[openEnd:closeStart], element[closeStart:]
+}
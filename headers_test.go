@@ -0,0 +1,141 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_SetHeader(t *testing.T) {
+	body := `<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:pPr></w:p>` +
+		`<w:p><w:r><w:t>{name}</w:t></w:r></w:p>` +
+		`<w:sectPr><w:headerReference w:type="default" r:id="rId9"/><w:pgSz w:w="11906" w:h="16838"/>` +
+		`<w:sectPrChange w:id="1"><w:sectPr/></w:sectPrChange></w:sectPr>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.SetHeader(HeaderFirstPage, "Dear {name}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetHeader(HeaderDefault, "Default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetSectionFooter(1, HeaderEvenPage, "Page"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetSectionFooter(2, HeaderDefault, "Page"); err == nil {
+		t.Error("expected error for unknown section")
+	}
+	if err := doc.SetHeader("odd", "Page"); err == nil {
+		t.Error("expected error for invalid header type")
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:sectPr><w:headerReference w:type="first" r:id="rId1"/><w:headerReference w:type="default" r:id="rId2"/><w:pgSz w:w="11906" w:h="16838"/><w:titlePg/></w:sectPr>`,
+		`<w:sectPr><w:headerReference w:type="default" r:id="rId2"/><w:headerReference w:type="first" r:id="rId1"/><w:footerReference w:type="even" r:id="rId3"/>` +
+			`<w:pgSz w:w="11906" w:h="16838"/><w:titlePg/><w:sectPrChange w:id="1"><w:sectPr/></w:sectPrChange></w:sectPr>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+
+	header := readTestPart(t, buf.Bytes(), "word/header1.xml")
+	if !strings.Contains(header, `<w:p><w:r><w:t xml:space="preserve">Dear Jane</w:t></w:r></w:p></w:hdr>`) {
+		t.Errorf("expected replaced placeholder in header: %s", header)
+	}
+	if footer := readTestPart(t, buf.Bytes(), "word/footer1.xml"); !strings.HasSuffix(footer, `</w:ftr>`) {
+		t.Errorf("expected footer part: %s", footer)
+	}
+	rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels")
+	if !strings.Contains(rels, `Type="`+RelationshipTypeFooter+`" Target="footer1.xml"`) {
+		t.Errorf("expected footer relationship: %s", rels)
+	}
+	contentTypes := readTestPart(t, buf.Bytes(), "[Content_Types].xml")
+	if !strings.Contains(contentTypes, `<Override PartName="/word/header2.xml" ContentType="`+ContentTypeHeader+`"/>`) {
+		t.Errorf("expected header content type: %s", contentTypes)
+	}
+	if settings := readTestPart(t, buf.Bytes(), SettingsXml); !strings.Contains(settings, "<w:evenAndOddHeaders/>") {
+		t.Errorf("expected even and odd headers setting: %s", settings)
+	}
+}
+
+func TestDocument_AddFooter(t *testing.T) {
+	body := `<w:p><w:pPr><w:sectPr><w:footerReference w:type="default" r:id="rId9"/><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:pPr></w:p>` +
+		`<w:p><w:r><w:t>Text</w:t></w:r></w:p><w:sectPr/>`
+	doc, err := OpenBytes(buildTestDocx(t, body,
+		"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId9" Type="`+RelationshipTypeFooter+`" Target="footer9.xml"/></Relationships>`,
+		"word/footer9.xml", `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p/></w:ftr>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.AddFooter(HeaderDefault, "{company}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.AddFooter(HeaderDefault, "{company}"); !errors.Is(err, ErrHeaderExists) {
+		t.Errorf("expected ErrHeaderExists, have %v", err)
+	}
+	if err := doc.AddHeader(HeaderFirstPage, "Cover"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.AddHeader("odd", "Page"); err == nil {
+		t.Error("expected error for invalid header type")
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"company": "ACME"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:sectPr><w:footerReference w:type="default" r:id="rId9"/><w:headerReference w:type="first" r:id="rId11"/><w:pgSz w:w="11906" w:h="16838"/><w:titlePg/></w:sectPr>`,
+		`<w:sectPr><w:footerReference w:type="default" r:id="rId10"/><w:headerReference w:type="first" r:id="rId11"/><w:titlePg/></w:sectPr>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if footer := readTestPart(t, buf.Bytes(), "word/footer1.xml"); !strings.Contains(footer, "ACME") {
+		t.Errorf("expected replaced placeholder in footer: %s", footer)
+	}
+	contentTypes := readTestPart(t, buf.Bytes(), "[Content_Types].xml")
+	if !strings.Contains(contentTypes, `<Override PartName="/word/footer1.xml" ContentType="`+ContentTypeFooter+`"/>`) {
+		t.Errorf("expected footer content type: %s", contentTypes)
+	}
+}
+
+func TestDocument_SetDifferentFirstPage(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>Text</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.SetDifferentFirstPage(true); err != nil {
+		t.Fatal(err)
+	}
+	if document := string(doc.GetFile(DocumentXml)); !strings.Contains(document, `<w:sectPr><w:titlePg/></w:sectPr></w:body>`) {
+		t.Errorf("expected section properties at end of body: %s", document)
+	}
+	if err := doc.SetDifferentFirstPage(false); err != nil {
+		t.Fatal(err)
+	}
+	if document := string(doc.GetFile(DocumentXml)); !strings.Contains(document, `<w:sectPr></w:sectPr></w:body>`) {
+		t.Errorf("expected titlePg to be removed: %s", document)
+	}
+}
@@ -0,0 +1,305 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// htmlTagRegex matches comments and the open and close tags of HTML elements,
+	// capturing the slash of close tags, the lowercase or uppercase tag name and the attributes.
+	htmlTagRegex = regexp.MustCompile(`(?s)<!--.*?-->|<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s[^>]*)?)>`)
+	// htmlHrefRegex matches the href attribute of a link and captures the double quoted, single quoted or unquoted value.
+	htmlHrefRegex = regexp.MustCompile(`(?i)\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	// htmlWhitespaceRegex matches sequences of whitespace which HTML collapses into a single space.
+	htmlWhitespaceRegex = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+	// htmlHeadingSizes are the font sizes (half-points) of headings if the document has no heading styles.
+	htmlHeadingSizes = []int{32, 26, 24, 22, 22, 22}
+)
+
+// HTML is a replacement value which converts HTML into paragraphs, e.g. rich text stored by a CMS.
+// The supported elements are p, div, br, b/strong, i/em, u, a (http, https and mailto links), h1-h6 and
+// bulleted or numbered lists (ul, ol, li), which may be nested. Other elements are ignored while their text is kept,
+// the content of script and style elements is removed. Whitespace is collapsed like in browsers.
+//
+// Paragraphs use the properties of the placeholder paragraph, headings and list items use the 'HeadingN'
+// and 'ListParagraph' styles if the document defines them. Text uses the formatting of the placeholder run.
+// Like fragments, the HTML replaces the paragraph of the placeholder, or splits it if the paragraph has more text.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "body": docx.HTML(`<p>Dear <b>Jane</b>,</p><ul><li>first</li><li>second</li></ul>`),
+//	})
+type HTML string
+
+// inlineXml returns a run with the marker of the HTML value, the paragraphs are inserted by insertBlocks.
+func (h HTML) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.blockMarkerRun(h), nil
+}
+
+// blockXml converts the HTML into paragraphs.
+func (h HTML) blockXml(ctx *valueContext) (string, error) {
+	converter := &htmlConverter{ctx: ctx, baseProperties: htmlBaseProperties(ctx.paragraphProperties)}
+	text := string(h)
+	pos := 0
+	for _, match := range htmlTagRegex.FindAllStringSubmatchIndex(text, -1) {
+		converter.text(html.UnescapeString(text[pos:match[0]]))
+		pos = match[1]
+		if match[4] < 0 {
+			continue // comment
+		}
+		closing := match[3] > match[2]
+		if err := converter.tag(strings.ToLower(text[match[4]:match[5]]), text[match[6]:match[7]], closing); err != nil {
+			return "", err
+		}
+	}
+	converter.text(html.UnescapeString(text[pos:]))
+	converter.endParagraph()
+	return converter.body.String(), nil
+}
+
+// htmlBaseProperties returns the properties of the placeholder paragraph without section break and numbering,
+// which are used for the regular paragraphs of HTML values.
+func htmlBaseProperties(paragraphProperties string) string {
+	if sections := sectionProperties([]byte(paragraphProperties)); len(sections) > 0 {
+		paragraphProperties = paragraphProperties[:sections[0][0]] + paragraphProperties[sections[0][1]:]
+	}
+	paragraphProperties = numberingPropertiesRegex.ReplaceAllString(paragraphProperties, "")
+	if paragraphProperties == "<w:pPr></w:pPr>" {
+		return ""
+	}
+	return paragraphProperties
+}
+
+// htmlList is a list (ul or ol) which is open while converting HTML.
+type htmlList struct {
+	ordered bool
+	// numId is the numbering of the list, it is added when the first item is written.
+	numId int
+}
+
+// htmlConverter holds the state of the conversion of an HTML value.
+type htmlConverter struct {
+	ctx *valueContext
+	// baseProperties are the properties of regular paragraphs.
+	baseProperties string
+	body           strings.Builder
+
+	// paragraph is the content of the open paragraph which uses the properties paragraphProperties.
+	paragraph           strings.Builder
+	paragraphProperties string
+	open                bool
+	// space is true if the paragraph is empty or ends with a space, so following whitespace is collapsed.
+	space bool
+
+	bold, italic, underline int
+	heading                 int
+	// link is the relationship ID of the open link, linkOpen is true if the hyperlink element is open.
+	link     string
+	linkOpen bool
+	lists    []htmlList
+	// skip is the depth of script and style elements.
+	skip int
+}
+
+// tag processes the open or close tag of an element.
+func (c *htmlConverter) tag(name, attributes string, closing bool) error {
+	delta := 1
+	if closing {
+		delta = -1
+	}
+	switch name {
+	case "p", "div":
+		c.endParagraph()
+		if !closing {
+			c.startParagraph(c.baseProperties)
+		}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		c.endParagraph()
+		c.heading = 0
+		if !closing {
+			c.heading = int(name[1] - '0')
+			c.startParagraph(c.headingProperties())
+		}
+	case "ul", "ol":
+		c.endParagraph()
+		if closing {
+			if len(c.lists) > 0 {
+				c.lists = c.lists[:len(c.lists)-1]
+			}
+		} else {
+			c.lists = append(c.lists, htmlList{ordered: name == "ol"})
+		}
+	case "li":
+		c.endParagraph()
+		if !closing {
+			properties, err := c.listItemProperties()
+			if err != nil {
+				return err
+			}
+			c.startParagraph(properties)
+		}
+	case "br":
+		if !closing {
+			c.ensureParagraph()
+			c.writeRun(`<w:r>` + c.runProperties() + `<w:br/></w:r>`)
+			c.space = true
+		}
+	case "b", "strong":
+		c.bold = max(0, c.bold+delta)
+	case "i", "em":
+		c.italic = max(0, c.italic+delta)
+	case "u":
+		c.underline = max(0, c.underline+delta)
+	case "a":
+		c.closeLink()
+		c.link = ""
+		if !closing {
+			return c.openLink(attributes)
+		}
+	case "script", "style":
+		c.skip = max(0, c.skip+delta)
+	}
+	return nil
+}
+
+// text adds the text to the open paragraph, a paragraph is started if none is open.
+func (c *htmlConverter) text(text string) {
+	if c.skip > 0 {
+		return
+	}
+	text = htmlWhitespaceRegex.ReplaceAllString(text, " ")
+	if c.space || !c.open {
+		text = strings.TrimLeft(text, " ")
+	}
+	if text == "" {
+		return
+	}
+	c.ensureParagraph()
+	ctx := *c.ctx
+	ctx.runProperties = c.runProperties()
+	policy := c.ctx.doc.textPolicy
+	c.writeRun(policy.lineRuns(&ctx, policy.apply(normalizeText(text))))
+	c.space = strings.HasSuffix(text, " ")
+}
+
+// writeRun adds the runs to the open paragraph, inside the open link if any.
+func (c *htmlConverter) writeRun(runs string) {
+	if c.link != "" && !c.linkOpen {
+		c.paragraph.WriteString(`<w:hyperlink r:id="` + c.link + `">`)
+		c.linkOpen = true
+	}
+	c.paragraph.WriteString(runs)
+}
+
+// startParagraph ends the open paragraph and starts a new one with the given properties.
+func (c *htmlConverter) startParagraph(properties string) {
+	c.endParagraph()
+	c.paragraphProperties = properties
+	c.open = true
+	c.space = true
+}
+
+// ensureParagraph starts a regular paragraph unless a paragraph is open.
+func (c *htmlConverter) ensureParagraph() {
+	if !c.open {
+		c.startParagraph(c.baseProperties)
+	}
+}
+
+// endParagraph writes the open paragraph into the body. Paragraphs without content are omitted.
+func (c *htmlConverter) endParagraph() {
+	if !c.open {
+		return
+	}
+	c.closeLink()
+	if c.paragraph.Len() > 0 {
+		c.body.WriteString("<w:p>" + c.paragraphProperties + c.paragraph.String() + "</w:p>")
+	}
+	c.paragraph.Reset()
+	c.open = false
+}
+
+// openLink adds the relationship of the link given by the attributes of the a element.
+// Links with other schemes than http, https and mailto are inserted as text.
+func (c *htmlConverter) openLink(attributes string) error {
+	match := htmlHrefRegex.FindStringSubmatch(attributes)
+	if match == nil {
+		return nil
+	}
+	href := strings.TrimSpace(html.UnescapeString(match[1] + match[2] + match[3]))
+	if !isLinkTarget(href) {
+		return nil
+	}
+	id, err := c.ctx.doc.addRelationship(c.ctx.part, RelationshipTypeHyperlink, href, true)
+	if err != nil {
+		return err
+	}
+	c.link = id
+	return nil
+}
+
+// closeLink closes the hyperlink element of the open link.
+func (c *htmlConverter) closeLink() {
+	if c.linkOpen {
+		c.paragraph.WriteString("</w:hyperlink>")
+		c.linkOpen = false
+	}
+}
+
+// runProperties returns the properties of the placeholder run with the formatting of the open elements applied.
+func (c *htmlConverter) runProperties() string {
+	properties := c.ctx.runProperties
+	if c.heading > 0 && !c.ctx.doc.styleExists(fmt.Sprintf("Heading%d", c.heading)) {
+		properties = setRunProperty(properties, "b", "<w:b/>")
+		properties = setRunProperty(properties, "sz", fmt.Sprintf(`<w:sz w:val="%d"/>`, htmlHeadingSizes[c.heading-1]))
+	}
+	if c.bold > 0 {
+		properties = setRunProperty(properties, "b", "<w:b/>")
+	}
+	if c.italic > 0 {
+		properties = setRunProperty(properties, "i", "<w:i/>")
+	}
+	if c.underline > 0 {
+		properties = setRunProperty(properties, "u", `<w:u w:val="single"/>`)
+	}
+	if c.link != "" {
+		properties = c.ctx.doc.hyperlinkProperties(properties)
+	}
+	return properties
+}
+
+// headingProperties returns the properties of the paragraph of the open heading.
+func (c *htmlConverter) headingProperties() string {
+	style := fmt.Sprintf("Heading%d", c.heading)
+	if c.ctx.doc.styleExists(style) {
+		return `<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`
+	}
+	return `<w:pPr><w:keepNext/></w:pPr>`
+}
+
+// listItemProperties returns the properties of a list item of the innermost list.
+// List items outside of lists are regular paragraphs.
+func (c *htmlConverter) listItemProperties() (string, error) {
+	if len(c.lists) == 0 {
+		return c.baseProperties, nil
+	}
+	list := &c.lists[len(c.lists)-1]
+	if list.numId == 0 {
+		numId, err := c.ctx.doc.addList(list.ordered)
+		if err != nil {
+			return "", err
+		}
+		list.numId = numId
+	}
+	style := ""
+	if c.ctx.doc.styleExists("ListParagraph") {
+		style = `<w:pStyle w:val="ListParagraph"/>`
+	}
+	level := min(len(c.lists)-1, listLevels-1)
+	return fmt.Sprintf(`<w:pPr>%s<w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr></w:pPr>`, style, level, list.numId), nil
+}
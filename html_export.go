@@ -0,0 +1,588 @@
+package docx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"mime"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// headingStyleRegex matches the ID or the name of heading styles and captures the level.
+	headingStyleRegex = regexp.MustCompile(`^(?i)heading\s*([1-6])$`)
+	// toggleRunPropertyRegex matches the run properties which are exported to HTML and captures the name and the value.
+	toggleRunPropertyRegex = regexp.MustCompile(`<w:(b|i|u|strike|dstrike|vertAlign|vanish)(?:\s+w:val="([^"]*)")?\s*/>`)
+	// alignmentRegex matches the justification of paragraph properties and captures the value.
+	alignmentRegex = regexp.MustCompile(`<w:jc\s+w:val="([^"]*)"`)
+	// listLevelRegex matches the list level of numbering properties and captures the level.
+	listLevelRegex = regexp.MustCompile(`<w:ilvl\s+w:val="([0-9]+)"`)
+	// listLevelFormatRegex matches the levels of abstract numbering definitions and captures the level and the content.
+	listLevelFormatRegex = regexp.MustCompile(`(?s)<w:lvl\s[^>]*?w:ilvl="([0-9]+)"[^>]*>(.*?)</w:lvl>`)
+	// numberFormatRegex matches the number format of a list level and captures the format.
+	numberFormatRegex = regexp.MustCompile(`<w:numFmt\s+w:val="([^"]*)"`)
+	// blipRegex matches the reference of a drawing to its picture and captures the relationship ID.
+	blipRegex = regexp.MustCompile(`<a:blip\s[^>]*?r:embed="([^"]*)"`)
+	// extentRegex matches the size of a drawing and captures the width and the height (EMU).
+	extentRegex = regexp.MustCompile(`<wp:extent\s+cx="([0-9]+)"\s+cy="([0-9]+)"`)
+	// gridSpanRegex matches the number of grid columns of a table cell and captures the number.
+	gridSpanRegex = regexp.MustCompile(`<w:gridSpan\s+w:val="([0-9]+)"`)
+	// cellMergeRegex matches the vertical merge of a table cell and captures the value, which is empty for
+	// cells which continue the merged cell above.
+	cellMergeRegex = regexp.MustCompile(`<w:vMerge(?:\s+w:val="([^"]*)")?\s*/>`)
+)
+
+// HTMLExportOptions configure the export of documents to HTML, see Document.ToHTMLWithOptions.
+type HTMLExportOptions struct {
+	// Image returns the URL of an image, e.g. after writing it to a file next to the HTML. Name is the name of the
+	// image part, e.g. 'word/media/image1.png'. If nil, images are embedded as data URIs.
+	Image func(name string, data []byte) (string, error)
+	// Fragment omits the html, head and body elements, e.g. to embed the content into another page.
+	Fragment bool
+}
+
+// ToHTML exports the body of the document to semantic HTML, e.g. to preview generated documents in a browser.
+// Images are embedded as data URIs, see ToHTMLWithOptions.
+//
+// Paragraphs with heading styles ('Heading 1' to 'Heading 6', 'Title') become h1-h6, list paragraphs become
+// nested ul and ol lists, tables keep merged cells and header rows. Bold, italic, underlined, struck through,
+// superscript and subscript text, line breaks, hyperlinks, bookmarks and the alignment of paragraphs are exported.
+// Hidden text, deleted text, headers, footers and notes are not exported, and no other formatting is applied.
+//
+// Example:
+//
+//	previewBytes, err := doc.ToHTML()
+func (d *Document) ToHTML() ([]byte, error) {
+	return d.ToHTMLWithOptions(HTMLExportOptions{})
+}
+
+// ToHTMLWithOptions works like ToHTML using the given options.
+//
+// Example:
+//
+//	previewBytes, err := doc.ToHTMLWithOptions(docx.HTMLExportOptions{
+//	    Image: func(name string, data []byte) (string, error) {
+//	        file := path.Base(name)
+//	        return "images/" + file, os.WriteFile(filepath.Join(outDir, "images", file), data, 0o644)
+//	    },
+//	})
+func (d *Document) ToHTMLWithOptions(options HTMLExportOptions) ([]byte, error) {
+	exporter := &htmlExporter{
+		doc:      d,
+		options:  options,
+		out:      &strings.Builder{},
+		headings: make(map[string]int),
+		listIds:  make(map[string]string),
+		formats:  make(map[string]map[int]string),
+		images:   make(map[string]string),
+		rels:     make(map[string]relationship),
+	}
+	if err := exporter.load(); err != nil {
+		return nil, err
+	}
+
+	match := BodyContentRegex.FindSubmatch(d.files[DocumentXml])
+	if match == nil {
+		return nil, fmt.Errorf("invalid document, %s has no body", DocumentXml)
+	}
+	if !options.Fragment {
+		exporter.out.WriteString(`<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>`)
+	}
+	if err := exporter.blocks(string(match[1])); err != nil {
+		return nil, err
+	}
+	exporter.closeLists(0)
+	if !options.Fragment {
+		exporter.out.WriteString(`</body></html>`)
+	}
+	return []byte(exporter.out.String()), nil
+}
+
+// htmlExporter holds the state of a single export to HTML.
+type htmlExporter struct {
+	doc     *Document
+	options HTMLExportOptions
+	out     *strings.Builder
+	// headings maps the IDs of heading styles to their level, listIds the IDs of paragraph styles to the
+	// numbering IDs of their numbering properties.
+	headings map[string]int
+	listIds  map[string]string
+	// formats maps the numbering IDs to the number formats of their levels, e.g. 'bullet' or 'decimal'.
+	formats map[string]map[int]string
+	// images maps the names of exported image parts to their URLs.
+	images map[string]string
+	// rels are the relationships of the body by ID.
+	rels map[string]relationship
+	// lists are the tags (ul or ol) of the open lists, one per level.
+	lists []string
+}
+
+// load reads the styles, numbering definitions and relationships which are required for the export.
+func (e *htmlExporter) load() error {
+	_, styles, err := e.doc.styles()
+	if err != nil {
+		return err
+	}
+	for _, style := range styles {
+		if style.styleType != "paragraph" {
+			continue
+		}
+		if level := headingLevel(style); level > 0 {
+			e.headings[style.id] = min(level, 6)
+		} else if style.id == "Title" {
+			e.headings[style.id] = 1
+		}
+		if match := numIdReferenceRegex.FindStringSubmatch(style.definition); match != nil {
+			e.listIds[style.id] = match[2]
+		}
+	}
+
+	numbering, _, err := e.doc.part(NumberingXml)
+	if err != nil {
+		return err
+	}
+	abstracts := make(map[string]map[int]string)
+	content := string(numbering)
+	if root := childElements(content); len(root) > 0 {
+		_, content, _ = splitElement(content[root[0].start:root[0].end])
+	}
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch child.name {
+		case "abstractNum":
+			if match := abstractNumIdRegex.FindStringSubmatch(element); match != nil {
+				levels := make(map[int]string)
+				for _, level := range listLevelFormatRegex.FindAllStringSubmatch(element, -1) {
+					if format := numberFormatRegex.FindStringSubmatch(level[2]); format != nil {
+						ilvl, _ := strconv.Atoi(level[1])
+						levels[ilvl] = format[1]
+					}
+				}
+				abstracts[match[1]] = levels
+			}
+		case "num":
+			numId, abstractId := numIdRegex.FindStringSubmatch(element), abstractNumReferenceRegex.FindStringSubmatch(element)
+			if numId != nil && abstractId != nil {
+				e.formats[numId[1]] = abstracts[abstractId[2]]
+			}
+		}
+	}
+
+	rels, _, err := e.doc.part(relationshipsPart(DocumentXml))
+	if err != nil {
+		return err
+	}
+	for _, rel := range parseRelationships(rels) {
+		e.rels[rel.id] = rel
+	}
+	return nil
+}
+
+// blocks exports the paragraphs and tables of the content. The content of other elements, e.g. content controls,
+// is exported as if it was not wrapped.
+func (e *htmlExporter) blocks(content string) error {
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		var err error
+		switch child.name {
+		case "p":
+			err = e.paragraph(element)
+		case "tbl":
+			e.closeLists(0)
+			err = e.table(element)
+		case "sectPr", "tblPr", "tblGrid", "trPr", "tcPr", "sdtPr", "sdtEndPr", "bookmarkStart", "bookmarkEnd", "del", "moveFrom":
+		default:
+			if _, inner, _ := splitElement(element); inner != "" {
+				err = e.blocks(inner)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paragraph exports the paragraph as heading, list item or paragraph.
+func (e *htmlExporter) paragraph(paragraph string) error {
+	properties := paragraphPropertiesRegex.FindStringSubmatch(paragraph)
+	pPr := properties[2]
+	content, err := e.inline(paragraph[len(properties[0]) : len(paragraph)-len("</w:p>")])
+	if err != nil {
+		return err
+	}
+	// paragraphs whose mark is hidden are hidden if they have no visible content, e.g. by {{hideIf}}
+	if content == "" && RunPropertiesRegex.MatchString(pPr) && runPropertiesOn(RunPropertiesRegex.FindString(pPr))["vanish"] {
+		return nil
+	}
+
+	style := ""
+	if match := paragraphStyleRegex.FindStringSubmatch(pPr); match != nil {
+		style = html.UnescapeString(match[1])
+	}
+	numId, level := e.listIds[style], 0
+	if match := numIdReferenceRegex.FindStringSubmatch(pPr); match != nil {
+		numId = match[2]
+	}
+	if match := listLevelRegex.FindStringSubmatch(pPr); match != nil {
+		level, _ = strconv.Atoi(match[1])
+	}
+	if numId != "" && numId != "0" {
+		tag := "ol"
+		if e.formats[numId][level] == "bullet" {
+			tag = "ul"
+		}
+		e.listItem(min(level, listLevels-1), tag)
+		e.out.WriteString(content)
+		return nil
+	}
+	e.closeLists(0)
+
+	tag := "p"
+	if level, isHeading := e.headings[style]; isHeading {
+		tag = "h" + strconv.Itoa(level)
+	}
+	e.out.WriteString("<" + tag)
+	if match := alignmentRegex.FindStringSubmatch(pPr); match != nil {
+		switch match[1] {
+		case "center":
+			e.out.WriteString(` style="text-align:center"`)
+		case "right", "end":
+			e.out.WriteString(` style="text-align:right"`)
+		case "both", "distribute":
+			e.out.WriteString(` style="text-align:justify"`)
+		}
+	}
+	e.out.WriteString(">" + content + "</" + tag + ">")
+	return nil
+}
+
+// listItem opens a list item on the given level of a list with the given tag, opening and closing lists as needed.
+// The item is closed by the next item or by closeLists.
+func (e *htmlExporter) listItem(level int, tag string) {
+	e.closeLists(level + 1)
+	if len(e.lists) == level+1 {
+		if e.lists[level] == tag {
+			e.out.WriteString("</li><li>")
+			return
+		}
+		e.closeLists(level)
+	}
+	for len(e.lists) <= level {
+		e.out.WriteString("<" + tag + "><li>")
+		e.lists = append(e.lists, tag)
+	}
+}
+
+// closeLists closes the open lists down to the given number of levels.
+func (e *htmlExporter) closeLists(levels int) {
+	for len(e.lists) > levels {
+		e.out.WriteString("</li></" + e.lists[len(e.lists)-1] + ">")
+		e.lists = e.lists[:len(e.lists)-1]
+	}
+}
+
+// inline returns the HTML of the runs, hyperlinks and bookmarks of a paragraph. Adjacent runs with the same
+// formatting share their elements.
+func (e *htmlExporter) inline(content string) (string, error) {
+	var out strings.Builder
+	var open []string
+	setFormat := func(tags []string) {
+		if strings.Join(tags, ",") == strings.Join(open, ",") {
+			return
+		}
+		for i := len(open) - 1; i >= 0; i-- {
+			out.WriteString("</" + open[i] + ">")
+		}
+		for _, tag := range tags {
+			out.WriteString("<" + tag + ">")
+		}
+		open = tags
+	}
+
+	var walk func(content string, link bool) error
+	walk = func(content string, link bool) error {
+		for _, child := range childElements(content) {
+			element := content[child.start:child.end]
+			openTag, inner, _ := splitElement(element)
+			switch child.name {
+			case "r":
+				tags, text, err := e.run(element)
+				if err != nil {
+					return err
+				}
+				if link {
+					// links are underlined anyway
+					tags = slices.DeleteFunc(tags, func(tag string) bool { return tag == "u" })
+				}
+				if text != "" {
+					setFormat(tags)
+					out.WriteString(text)
+				}
+			case "hyperlink":
+				href := ""
+				for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(openTag, -1) {
+					switch attribute[1] {
+					case "r:id":
+						if rel, exists := e.rels[html.UnescapeString(attribute[2])]; exists && rel.external {
+							href = rel.target
+						}
+					case "w:anchor":
+						href = "#" + html.UnescapeString(attribute[2])
+					}
+				}
+				setFormat(nil)
+				out.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+				if err := walk(inner, true); err != nil {
+					return err
+				}
+				setFormat(nil)
+				out.WriteString("</a>")
+			case "bookmarkStart":
+				for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(openTag, -1) {
+					if attribute[1] == "w:name" && !strings.HasPrefix(attribute[2], "_") {
+						setFormat(nil)
+						out.WriteString(`<a id="` + attribute[2] + `"></a>`)
+					}
+				}
+			case "pPr", "del", "moveFrom", "commentRangeStart", "commentRangeEnd", "bookmarkEnd", "proofErr":
+			default:
+				// insertions, content controls, fields, smart tags, ...
+				if err := walk(inner, link); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(content, false); err != nil {
+		return "", err
+	}
+	setFormat(nil)
+	return out.String(), nil
+}
+
+// run returns the formatting elements and the HTML of the content of the run. Hidden runs have no content.
+func (e *htmlExporter) run(run string) ([]string, string, error) {
+	_, content, _ := splitElement(run)
+	var tags []string
+	var out strings.Builder
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		_, inner, _ := splitElement(element)
+		switch child.name {
+		case "rPr":
+			on := runPropertiesOn(element)
+			if on["vanish"] {
+				return nil, "", nil
+			}
+			for _, property := range []struct{ name, tag string }{
+				{"b", "strong"}, {"i", "em"}, {"u", "u"}, {"strike", "s"}, {"dstrike", "s"}, {"superscript", "sup"}, {"subscript", "sub"},
+			} {
+				if on[property.name] && (len(tags) == 0 || tags[len(tags)-1] != property.tag) {
+					tags = append(tags, property.tag)
+				}
+			}
+		case "t":
+			out.WriteString(html.EscapeString(html.UnescapeString(inner)))
+		case "tab", "ptab":
+			out.WriteString("\t")
+		case "br", "cr":
+			if !strings.Contains(element, `w:type="page"`) && !strings.Contains(element, `w:type="column"`) {
+				out.WriteString("<br>")
+			}
+		case "noBreakHyphen":
+			out.WriteString("‑")
+		case "drawing":
+			image, err := e.image(element)
+			if err != nil {
+				return nil, "", err
+			}
+			out.WriteString(image)
+		case "AlternateContent":
+			// the first choice is the drawing which Word displays
+			for _, choice := range childElements(inner) {
+				if choice.name == "Choice" {
+					image, err := e.image(inner[choice.start:choice.end])
+					if err != nil {
+						return nil, "", err
+					}
+					out.WriteString(image)
+					break
+				}
+			}
+		}
+	}
+	return tags, out.String(), nil
+}
+
+// runPropertiesOn returns the exported run properties which are switched on, vertical alignment as superscript
+// or subscript.
+func runPropertiesOn(rPr string) map[string]bool {
+	on := make(map[string]bool)
+	for _, match := range toggleRunPropertyRegex.FindAllStringSubmatch(rPr, -1) {
+		switch value := match[2]; match[1] {
+		case "vertAlign":
+			on[value] = true
+		case "u":
+			on["u"] = value != "none"
+		default:
+			on[match[1]] = value != "0" && value != "false" && value != "off"
+		}
+	}
+	return on
+}
+
+// image returns the img element of the picture of the drawing, or nothing if the drawing is not a picture.
+func (e *htmlExporter) image(drawing string) (string, error) {
+	match := blipRegex.FindStringSubmatch(drawing)
+	if match == nil {
+		return "", nil
+	}
+	rel, exists := e.rels[html.UnescapeString(match[1])]
+	if !exists || rel.external {
+		return "", nil
+	}
+	name := rel.partName(DocumentXml)
+	url, exported := e.images[name]
+	if !exported {
+		data, exists, err := e.doc.part(name)
+		if err != nil || !exists {
+			return "", err
+		}
+		if e.options.Image != nil {
+			if url, err = e.options.Image(name, data); err != nil {
+				return "", fmt.Errorf("unable to export image %s: %w", name, err)
+			}
+		} else {
+			contentType := mime.TypeByExtension(path.Ext(name))
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			url = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		}
+		e.images[name] = url
+	}
+
+	alt := ""
+	if docPr := docPrRegex.FindStringSubmatch(drawing); docPr != nil {
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(docPr[1], -1) {
+			if attribute[1] == "descr" {
+				alt = html.UnescapeString(attribute[2])
+			}
+		}
+	}
+	img := `<img src="` + html.EscapeString(url) + `" alt="` + html.EscapeString(alt) + `"`
+	if extent := extentRegex.FindStringSubmatch(drawing); extent != nil {
+		width, _ := strconv.Atoi(extent[1])
+		height, _ := strconv.Atoi(extent[2])
+		img += fmt.Sprintf(` width="%d" height="%d"`, width/EMUPerPixel, height/EMUPerPixel)
+	}
+	return img + ">", nil
+}
+
+// htmlCell is a table cell which is exported to HTML.
+type htmlCell struct {
+	column, span, rowSpan int
+	// continued is true if the cell continues the vertically merged cell above.
+	continued bool
+	content   string
+}
+
+// table exports the table, vertically merged cells are exported with row spans.
+func (e *htmlExporter) table(table string) error {
+	_, content, _ := splitElement(table)
+	var rows [][]*htmlCell
+	var header []bool
+	for _, child := range childElements(content) {
+		if child.name != "tr" {
+			continue
+		}
+		_, rowContent, _ := splitElement(content[child.start:child.end])
+		var cells []*htmlCell
+		column := 0
+		isHeader := false
+		for _, c := range childElements(rowContent) {
+			element := rowContent[c.start:c.end]
+			switch c.name {
+			case "trPr":
+				isHeader = strings.Contains(element, "<w:tblHeader/>") || strings.Contains(element, `<w:tblHeader w:val="1"/>`)
+			case "tc":
+				_, cellContent, _ := splitElement(element)
+				cell := &htmlCell{column: column, span: 1, rowSpan: 1}
+				if tcPr := childElements(cellContent); len(tcPr) > 0 && tcPr[0].name == "tcPr" {
+					properties := cellContent[tcPr[0].start:tcPr[0].end]
+					if match := gridSpanRegex.FindStringSubmatch(properties); match != nil {
+						cell.span, _ = strconv.Atoi(match[1])
+						cell.span = max(cell.span, 1)
+					}
+					if match := cellMergeRegex.FindStringSubmatch(properties); match != nil {
+						cell.continued = match[1] != "restart"
+					}
+				}
+				column += cell.span
+				if !cell.continued {
+					// the content of the cell is exported on its own, lists end with the cell
+					outer, lists := e.out, e.lists
+					e.out, e.lists = &strings.Builder{}, nil
+					if err := e.blocks(cellContent); err != nil {
+						return err
+					}
+					e.closeLists(0)
+					cell.content = e.out.String()
+					// the paragraph of cells with a single paragraph is omitted
+					if strings.HasPrefix(cell.content, "<p>") && strings.Index(cell.content, "</p>") == len(cell.content)-len("</p>") {
+						cell.content = strings.TrimSuffix(strings.TrimPrefix(cell.content, "<p>"), "</p>")
+					}
+					e.out, e.lists = outer, lists
+				}
+				cells = append(cells, cell)
+			}
+		}
+		rows = append(rows, cells)
+		header = append(header, isHeader)
+	}
+
+	// continued cells extend the row span of the cell above
+	for r := len(rows) - 1; r > 0; r-- {
+		for _, cell := range rows[r] {
+			if !cell.continued {
+				continue
+			}
+			for _, above := range rows[r-1] {
+				if above.column == cell.column {
+					above.rowSpan += cell.rowSpan
+				}
+			}
+		}
+	}
+
+	e.out.WriteString("<table>")
+	for r, cells := range rows {
+		tag := "td"
+		if header[r] {
+			tag = "th"
+		}
+		e.out.WriteString("<tr>")
+		for _, cell := range cells {
+			if cell.continued {
+				continue
+			}
+			e.out.WriteString("<" + tag)
+			if cell.span > 1 {
+				fmt.Fprintf(e.out, ` colspan="%d"`, cell.span)
+			}
+			if cell.rowSpan > 1 {
+				fmt.Fprintf(e.out, ` rowspan="%d"`, cell.rowSpan)
+			}
+			e.out.WriteString(">" + cell.content + "</" + tag + ">")
+		}
+		e.out.WriteString("</tr>")
+	}
+	e.out.WriteString("</table>")
+	return nil
+}
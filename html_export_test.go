@@ -0,0 +1,104 @@
+package docx
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ToHTML(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles := strings.Replace(testStylesXml, `</w:styles>`,
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="Berschrift2"><w:name w:val="Heading 2"/></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:pPr><w:numPr><w:numId w:val="1"/></w:numPr></w:pPr></w:style></w:styles>`, 1)
+	numbering := `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/></w:lvl><w:lvl w:ilvl="1"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>` +
+		`<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num><w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num></w:numbering>`
+	rels := testRelationshipsPart(`<Relationship Id="rId1" Type="` + RelationshipTypeImage + `" Target="media/image1.jpeg"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/?a=1&amp;b=2" TargetMode="External"/>`)
+	input := buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Report &amp; summary</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:pStyle w:val="Berschrift2"/><w:jc w:val="center"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Bold </w:t></w:r><w:r><w:rPr><w:b/><w:i/></w:rPr><w:t>both</w:t></w:r>`+
+			`<w:r><w:rPr><w:b w:val="0"/><w:vertAlign w:val="superscript"/></w:rPr><w:t>2</w:t></w:r><w:r><w:br/><w:t>next</w:t><w:tab/></w:r>`+
+			`<w:hyperlink r:id="rId2"><w:r><w:t>link</w:t></w:r></w:hyperlink><w:bookmarkStart w:id="0" w:name="terms"/><w:bookmarkStart w:id="1" w:name="_GoBack"/>`+
+			`<w:r><w:rPr><w:vanish/></w:rPr><w:t>hidden</w:t></w:r><w:del><w:r><w:delText>deleted</w:delText></w:r></w:del></w:p>`+
+			`<w:p><w:pPr><w:pStyle w:val="ListBullet"/></w:pPr><w:r><w:t>first</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:pStyle w:val="ListBullet"/><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>nested</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:pStyle w:val="ListBullet"/></w:pPr><w:r><w:t>second</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t>numbered</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:rPr><w:vanish/></w:rPr></w:pPr><w:r><w:rPr><w:vanish/></w:rPr><w:t>hidden paragraph</w:t></w:r></w:p>`+
+			`<w:tbl><w:tblPr/><w:tblGrid><w:gridCol/><w:gridCol/></w:tblGrid>`+
+			`<w:tr><w:trPr><w:tblHeader/></w:trPr><w:tc><w:p><w:r><w:t>Name</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Score</w:t></w:r></w:p></w:tc></w:tr>`+
+			`<w:tr><w:tc><w:tcPr><w:vMerge w:val="restart"/></w:tcPr><w:p><w:r><w:t>Jane</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>98</w:t></w:r></w:p></w:tc></w:tr>`+
+			`<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr><w:p/></w:tc><w:tc><w:p><w:r><w:t>87</w:t></w:r></w:p></w:tc></w:tr>`+
+			`<w:tr><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>Total</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:drawing><wp:inline><wp:extent cx="952500" cy="476250"/><wp:docPr id="1" name="Picture 1" descr="Camera &amp; man"/>`+
+			`<a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="rId1"/></pic:blipFill></pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>`+
+			`<w:sectPr/>`,
+		StylesXml, styles, NumberingXml, numbering, "word/_rels/document.xml.rels", rels, "word/media/image1.jpeg", string(imageBytes))
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	output, err := doc.ToHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>` +
+		`<h1>Report &amp; summary</h1><h2 style="text-align:center">Scope</h2>` +
+		`<p><strong>Bold </strong><strong><em>both</em></strong><sup>2</sup><br>next` + "\t" +
+		`<a href="https://example.com/?a=1&amp;b=2">link</a><a id="terms"></a></p>` +
+		`<ul><li>first<ol><li>nested</li></ol></li><li>second</li></ul><ol><li>numbered</li></ol>` +
+		`<table><tr><th>Name</th><th>Score</th></tr><tr><td rowspan="2">Jane</td><td>98</td></tr>` +
+		`<tr><td>87</td></tr><tr><td colspan="2">Total</td></tr></table>` +
+		`<p><img src="data:image/jpeg;base64,` + base64.StdEncoding.EncodeToString(imageBytes) + `" alt="Camera &amp; man" width="100" height="50"></p>` +
+		`</body></html>`
+	if string(output) != expected {
+		t.Errorf("unexpected HTML:\n%s\nexpected:\n%s", output, expected)
+	}
+
+	var exported []string
+	output, err = doc.ToHTMLWithOptions(HTMLExportOptions{
+		Fragment: true,
+		Image: func(name string, data []byte) (string, error) {
+			exported = append(exported, name)
+			return "images/image1.jpeg", nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(output), "<h1>") || !strings.Contains(string(output), `<img src="images/image1.jpeg"`) ||
+		strings.Join(exported, ",") != "word/media/image1.jpeg" {
+		t.Errorf("unexpected fragment %s with images %v", output, exported)
+	}
+}
+
+func TestDocument_ToHTMLRoundTrip(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{body}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	source := `<p>Dear <b>Jane</b>,</p><ul><li>first</li><li>second<ol><li>nested</li></ol></li></ul><p><a href="https://example.com">site</a></p>`
+	if err := doc.ReplaceAll(PlaceholderMap{"body": HTML(source)}); err != nil {
+		t.Fatal(err)
+	}
+	output, err := doc.ToHTMLWithOptions(HTMLExportOptions{Fragment: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.ReplaceAll(strings.ReplaceAll(source, "<b>", "<strong>"), "</b>", "</strong>")
+	if string(output) != expected {
+		t.Errorf("unexpected HTML:\n%s\nexpected:\n%s", output, expected)
+	}
+}
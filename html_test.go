@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_HTML(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t,
+		`<w:p><w:pPr><w:jc w:val="both"/></w:pPr><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>{body}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Note: {note} end</w:t></w:r></w:p>`,
+		StylesXml, `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/></w:style></w:styles>`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"body": HTML(`<h1>Offer</h1>
+			<p>Dear <b>Jane &amp; <i>John</i></b>,<br>see   <a href="https://example.com/?a=1&amp;b=2">our terms</a>.</p>
+			<ol><li>first</li><li>second<ul><li>nested</li></ul></li></ol>
+			<h2>Fallback</h2><script>alert(1)</script><a href="javascript:alert(1)">no link</a>`),
+		"note": HTML(`<u>important</u>`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	run := func(properties, text string) string {
+		return `<w:r><w:rPr><w:sz w:val="20"/>` + properties + `</w:rPr><w:t xml:space="preserve">` + text + `</w:t></w:r>`
+	}
+	for _, expected := range []string{
+		`<w:body><w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr>` + run("", "Offer") + `</w:p>`,
+		`<w:p><w:pPr><w:jc w:val="both"/></w:pPr>` + run("", "Dear ") + `<w:r><w:rPr><w:b/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">Jane &amp; </w:t></w:r>` +
+			`<w:r><w:rPr><w:b/><w:i/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">John</w:t></w:r>` + run("", ",") +
+			`<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:br/></w:r>` + run("", "see ") +
+			`<w:hyperlink r:id="rId1"><w:r><w:rPr><w:color w:val="0563C1"/><w:sz w:val="20"/><w:u w:val="single"/></w:rPr><w:t xml:space="preserve">our terms</w:t></w:r></w:hyperlink>` + run("", ".") + `</w:p>`,
+		`<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr>` + run("", "first") + `</w:p>`,
+		`<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="1"/><w:numId w:val="2"/></w:numPr></w:pPr>` + run("", "nested") + `</w:p>`,
+		`<w:p><w:pPr><w:keepNext/></w:pPr><w:r><w:rPr><w:b/><w:sz w:val="26"/></w:rPr><w:t xml:space="preserve">Fallback</w:t></w:r></w:p>`,
+		`<w:p><w:pPr><w:jc w:val="both"/></w:pPr>` + run("", "no link") + `</w:p>`,
+		// the paragraph of an inline placeholder is split
+		`<w:p><w:r><w:t xml:space="preserve">Note: </w:t></w:r></w:p><w:p><w:r><w:rPr><w:u w:val="single"/></w:rPr><w:t xml:space="preserve">important</w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve"> end</w:t></w:r></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "alert") {
+		t.Errorf("unexpected script in document: %s", document)
+	}
+
+	numbering := readTestPart(t, buf.Bytes(), NumberingXml)
+	for _, expected := range []string{
+		`<w:abstractNum w:abstractNumId="0">`, `<w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/>`,
+		`<w:abstractNum w:abstractNumId="1">`, `<w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="◦"/>`,
+		`</w:abstractNum><w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num><w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num></w:numbering>`,
+	} {
+		if !strings.Contains(numbering, expected) {
+			t.Errorf("expected %s in numbering: %s", expected, numbering)
+		}
+	}
+	if rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels"); !strings.Contains(rels, `Target="https://example.com/?a=1&amp;b=2" TargetMode="External"`) {
+		t.Errorf("expected hyperlink relationship: %s", rels)
+	}
+	if types := readTestPart(t, buf.Bytes(), ContentTypesXml); !strings.Contains(types, ContentTypeNumbering) {
+		t.Errorf("expected numbering content type: %s", types)
+	}
+}
@@ -0,0 +1,181 @@
+// Package httpdocx serves documents generated from DOCX templates over HTTP.
+//
+// Example:
+//
+//	http.Handle("/invoice", httpdocx.Handler(templateBytes, func(r *http.Request) (docx.PlaceholderMap, error) {
+//	    invoice, err := loadInvoice(r.Context(), r.URL.Query().Get("id"))
+//	    if err != nil {
+//	        return nil, httpdocx.Error(http.StatusNotFound, err)
+//	    }
+//	    return docx.PlaceholderMap{"number": invoice.Number, "total": invoice.Total}, nil
+//	}, httpdocx.WithFileName(func(r *http.Request, data docx.PlaceholderMap) string {
+//	    return fmt.Sprintf("invoice-%v.docx", data["number"])
+//	})))
+package httpdocx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/izetmolla/go-docx"
+)
+
+const (
+	// ContentType is the media type of DOCX documents.
+	ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	// DefaultFileName is the file name of served documents if WithFileName is not given.
+	DefaultFileName = "document.docx"
+	// DefaultCacheControl keeps generated documents out of shared caches since they usually contain personal data.
+	DefaultCacheControl = "private, no-cache"
+)
+
+// ErrRender wraps the errors of invalid templates and failed renders passed to the error handler, see WithErrorHandler.
+var ErrRender = errors.New("unable to render document")
+
+// Option configures a Handler.
+type Option func(*handler)
+
+// WithFileName sets the function which returns the file name of the served document, e.g. based on the data. The
+// name is sanitized, see docx.SanitizeFileName, and '.docx' is appended if it has another extension.
+func WithFileName(name func(r *http.Request, data docx.PlaceholderMap) string) Option {
+	return func(h *handler) {
+		h.fileName = name
+	}
+}
+
+// WithInline serves the document with an inline content disposition, so browsers may open it instead of
+// downloading it.
+func WithInline() Option {
+	return func(h *handler) {
+		h.disposition = "inline"
+	}
+}
+
+// WithCacheControl sets the Cache-Control header of served documents, DefaultCacheControl if not given.
+// Use e.g. "no-store" for sensitive documents or "public, max-age=3600" for documents which are the same for all users.
+func WithCacheControl(value string) Option {
+	return func(h *handler) {
+		h.cacheControl = value
+	}
+}
+
+// WithErrorHandler sets the function which responds to errors of the data function and of the render. By default,
+// the status of errors created by Error is sent with their message. All other errors are logged and sent without
+// details, so internal errors never reach the client: errors of the data function as 400 Bad Request and render
+// errors (ErrRender) as 500 Internal Server Error.
+func WithErrorHandler(handle func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(h *handler) {
+		h.handleError = handle
+	}
+}
+
+// StatusError is an error of the data function with the HTTP status to respond with, see Error.
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+// Error creates an error which makes the Handler respond with the status, e.g. http.StatusNotFound if the record
+// requested by the data function does not exist.
+func Error(status int, err error) error {
+	return &StatusError{Status: status, Err: err}
+}
+
+// Error returns the message of the error.
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// handler is the http.Handler returned by Handler.
+type handler struct {
+	prepared        *docx.PreparedTemplate
+	err             error
+	dataFromRequest func(*http.Request) (docx.PlaceholderMap, error)
+	fileName        func(*http.Request, docx.PlaceholderMap) string
+	disposition     string
+	cacheControl    string
+	handleError     func(http.ResponseWriter, *http.Request, error)
+}
+
+// Handler returns an http.Handler which replaces the placeholders of the template with the data returned by
+// dataFromRequest and serves the resulting document. The template is parsed once, see docx.Prepare.
+//
+// The document is rendered completely before anything is sent, so errors always result in a proper error response.
+// It is served with the DOCX content type, an attachment content disposition with the file name, see WithFileName,
+// the Cache-Control header, see WithCacheControl, and an ETag of its content. Conditional and range requests are
+// answered like by http.ServeContent. If the template is invalid, every request is answered with a render error.
+func Handler(template []byte, dataFromRequest func(*http.Request) (docx.PlaceholderMap, error), opts ...Option) http.Handler {
+	h := &handler{
+		dataFromRequest: dataFromRequest,
+		disposition:     "attachment",
+		cacheControl:    DefaultCacheControl,
+		handleError:     defaultErrorHandler,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.prepared, h.err = docx.Prepare(template)
+	return h
+}
+
+// ServeHTTP renders and serves the document.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.err != nil {
+		h.handleError(w, r, fmt.Errorf("%w: %w", ErrRender, h.err))
+		return
+	}
+	data, err := h.dataFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	output, err := h.prepared.Render(data)
+	if err != nil {
+		h.handleError(w, r, fmt.Errorf("%w: %w", ErrRender, err))
+		return
+	}
+
+	name := DefaultFileName
+	if h.fileName != nil {
+		name = docx.SanitizeFileName(h.fileName(r, data))
+		if !strings.HasSuffix(strings.ToLower(name), ".docx") {
+			name += ".docx"
+		}
+	}
+	hash := sha256.Sum256(output)
+	header := w.Header()
+	header.Set("Content-Type", ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType(h.disposition, map[string]string{"filename": name}))
+	header.Set("Cache-Control", h.cacheControl)
+	header.Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(output))
+}
+
+// defaultErrorHandler responds to errors as described by WithErrorHandler.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		http.Error(w, statusErr.Error(), statusErr.Status)
+	case errors.Is(err, ErrRender):
+		log.Printf("httpdocx: %s: %v", r.URL.Path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	default:
+		log.Printf("httpdocx: %s: %v", r.URL.Path, err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,80 @@
+package httpdocx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/izetmolla/go-docx"
+)
+
+func TestHandler(t *testing.T) {
+	template, err := docx.New().AddParagraph("Dear {name}").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := Handler(template, func(r *http.Request) (docx.PlaceholderMap, error) {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			return nil, errors.New("name is required")
+		}
+		if name == "unknown" {
+			return nil, Error(http.StatusNotFound, errors.New("customer not found"))
+		}
+		return docx.PlaceholderMap{"name": name}, nil
+	}, WithFileName(func(r *http.Request, data docx.PlaceholderMap) string {
+		return "letter " + data["name"].(string)
+	}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/?name=Jürgen", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, have %d: %s", response.Code, response.Body)
+	}
+	for name, expected := range map[string]string{
+		"Content-Type":        ContentType,
+		"Content-Disposition": "attachment; filename*=utf-8''letter%20J%C3%BCrgen.docx",
+		"Cache-Control":       DefaultCacheControl,
+	} {
+		if value := response.Header().Get(name); value != expected {
+			t.Errorf("expected %s %q, have %q", name, expected, value)
+		}
+	}
+	doc, err := docx.OpenBytes(response.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := string(doc.GetFile(docx.DocumentXml)); !strings.Contains(document, "Dear Jürgen") {
+		t.Errorf("expected the replaced name in %s", document)
+	}
+
+	etag := response.Header().Get("ETag")
+	request := httptest.NewRequest(http.MethodGet, "/?name=Jürgen", nil)
+	request.Header.Set("If-None-Match", etag)
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	if response.Code != http.StatusNotModified {
+		t.Errorf("expected status 304 for ETag %s, have %d", etag, response.Code)
+	}
+
+	for query, status := range map[string]int{"": http.StatusBadRequest, "?name=unknown": http.StatusNotFound} {
+		response = httptest.NewRecorder()
+		handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/"+query, nil))
+		if response.Code != status {
+			t.Errorf("%s: expected status %d, have %d", query, status, response.Code)
+		}
+	}
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := response.Body.String(); strings.Contains(body, "name is required") {
+		t.Errorf("expected the error of the data function not to be sent: %s", body)
+	}
+
+	response = httptest.NewRecorder()
+	Handler([]byte("invalid"), nil).ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	if response.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 for invalid template, have %d", response.Code)
+	}
+}
@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// Hyperlink is a replacement value which inserts a clickable link (<w:hyperlink>). The URL is added as an external
+// relationship of the part containing the placeholder. URLs starting with '#' link to the bookmark of that name
+// inside the document instead. The text uses the Hyperlink character style of the document, or blue underlined
+// text if the style does not exist.
+//
+// Only http, https and mailto URLs are allowed, other schemes (e.g. javascript) return an error.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "website": docx.Hyperlink{Text: "our website", URL: "https://example.com"},
+//	    "terms":   docx.Hyperlink{Text: "terms and conditions", URL: "#terms"},
+//	})
+type Hyperlink struct {
+	// Text is the displayed text of the link, the URL is displayed if empty.
+	Text string
+	URL  string
+	// Tooltip is shown when hovering the link, optional.
+	Tooltip string
+}
+
+// String returns the text of the link followed by the URL, or the URL only if both are the same.
+func (h Hyperlink) String() string {
+	if h.Text == "" || h.Text == h.URL {
+		return h.URL
+	}
+	return h.Text + " (" + h.URL + ")"
+}
+
+// inlineXml returns the hyperlink element containing the runs of the text.
+func (h Hyperlink) inlineXml(ctx *valueContext) (string, error) {
+	text := h.Text
+	if text == "" {
+		text = h.URL
+	}
+
+	var attributes string
+	if anchor, isAnchor := strings.CutPrefix(h.URL, "#"); isAnchor {
+		if anchor == "" {
+			return "", fmt.Errorf("invalid hyperlink %s, the bookmark name is missing", h.URL)
+		}
+		attributes = ` w:anchor="` + html.EscapeString(anchor) + `"`
+	} else {
+		if !isLinkTarget(h.URL) {
+			return "", fmt.Errorf("invalid hyperlink %s, only http, https and mailto URLs are allowed", h.URL)
+		}
+		id, err := ctx.doc.addRelationship(ctx.part, RelationshipTypeHyperlink, h.URL, true)
+		if err != nil {
+			return "", err
+		}
+		attributes = ` r:id="` + id + `"`
+	}
+	if h.Tooltip != "" {
+		attributes += ` w:tooltip="` + html.EscapeString(h.Tooltip) + `"`
+	}
+
+	linkCtx := *ctx
+	linkCtx.runProperties = ctx.doc.hyperlinkProperties(ctx.runProperties)
+	policy := ctx.doc.textPolicy
+	runs := policy.lineRuns(&linkCtx, policy.apply(normalizeText(text)))
+	return `<w:hyperlink` + attributes + ` w:history="1">` + runs + `</w:hyperlink>`, nil
+}
+
+// isLinkTarget returns true if the URL may be the target of a hyperlink, which is restricted to http, https and
+// mailto URLs.
+func isLinkTarget(href string) bool {
+	target, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(target.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// hyperlinkProperties returns the run properties with the formatting of hyperlinks applied: the Hyperlink character
+// style if the document defines it, otherwise blue underlined text.
+func (d *Document) hyperlinkProperties(runProperties string) string {
+	if d.styleExists("Hyperlink") {
+		return setRunProperty(runProperties, "rStyle", `<w:rStyle w:val="Hyperlink"/>`)
+	}
+	runProperties = setRunProperty(runProperties, "color", `<w:color w:val="0563C1"/>`)
+	return setRunProperty(runProperties, "u", `<w:u w:val="single"/>`)
+}
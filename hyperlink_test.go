@@ -0,0 +1,66 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceHyperlink(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Visit {website} or read the {terms}.</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{mail}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"website": Hyperlink{Text: "our website", URL: "https://example.com/?a=1&b=2", Tooltip: "Example & Co"},
+		"terms":   Hyperlink{Text: "terms", URL: "#terms"},
+		"mail":    Hyperlink{URL: "mailto:jane@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	// the relationships are numbered in the order of replacement, which is not defined
+	websiteId, mailId := "1", "2"
+	if strings.Index(document, `r:id="rId1"`) > strings.Index(document, `r:id="rId2"`) {
+		websiteId, mailId = mailId, websiteId
+	}
+	linkProperties := `<w:rPr><w:b/><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr>`
+	for _, expected := range []string{
+		`Visit </w:t></w:r><w:hyperlink r:id="rId` + websiteId + `" w:tooltip="Example &amp; Co" w:history="1"><w:r>` + linkProperties +
+			`<w:t xml:space="preserve">our website</w:t></w:r></w:hyperlink><w:r><w:rPr><w:b/></w:rPr>`,
+		`<w:hyperlink w:anchor="terms" w:history="1"><w:r>` + linkProperties + `<w:t xml:space="preserve">terms</w:t></w:r></w:hyperlink>`,
+		`<w:hyperlink r:id="rId` + mailId + `" w:history="1"><w:r><w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr><w:t xml:space="preserve">mailto:jane@example.com</w:t></w:r></w:hyperlink>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels")
+	for _, expected := range []string{
+		`<Relationship Id="rId` + websiteId + `" Type="` + RelationshipTypeHyperlink + `" Target="https://example.com/?a=1&amp;b=2" TargetMode="External"/>`,
+		`<Relationship Id="rId` + mailId + `" Type="` + RelationshipTypeHyperlink + `" Target="mailto:jane@example.com" TargetMode="External"/>`,
+	} {
+		if !strings.Contains(rels, expected) {
+			t.Errorf("expected %s in relationships: %s", expected, rels)
+		}
+	}
+}
+
+func TestDocument_ReplaceHyperlink_Invalid(t *testing.T) {
+	for _, link := range []string{"javascript:alert(1)", "#", "file:///etc/passwd"} {
+		doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{link}</w:t></w:r></w:p>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.ReplaceAll(PlaceholderMap{"link": Hyperlink{Text: "click", URL: link}}); err == nil {
+			t.Errorf("expected error for %s", link)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package docx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif"  // register decoder for image dimensions
+	_ "image/jpeg" // register decoder for image dimensions
+	_ "image/png"  // register decoder for image dimensions
+	"net/http"
+	"strings"
+)
+
+const (
+	// EMUPerInch is the amount of English Metric Units (EMU) per inch, the unit used for drawing sizes.
+	EMUPerInch = 914400
+	// EMUPerCentimeter is the amount of English Metric Units per centimeter.
+	EMUPerCentimeter = 360000
+	// EMUPerPixel is the amount of English Metric Units per pixel at 96 DPI.
+	EMUPerPixel = 9525
+)
+
+// ImageAnchor defines how an image is positioned relative to the text.
+type ImageAnchor int
+
+const (
+	// ImageInline places the image inline with the text, like a (large) character. This is the default.
+	ImageInline ImageAnchor = iota
+	// ImageFloating places the image at an offset relative to the paragraph, text wraps according to ImageWrap.
+	ImageFloating
+)
+
+// ImageWrap defines how text wraps around a floating image.
+type ImageWrap int
+
+const (
+	// WrapSquare wraps the text around the bounding box of the image. This is the default.
+	WrapSquare ImageWrap = iota
+	// WrapTopAndBottom places the text above and below the image only.
+	WrapTopAndBottom
+	// WrapBehindText places the image behind the text without wrapping.
+	WrapBehindText
+	// WrapInFrontOfText places the image in front of the text without wrapping.
+	WrapInFrontOfText
+)
+
+var (
+	// ErrUnsupportedImage is returned if the type of the image data cannot be detected.
+	ErrUnsupportedImage = errors.New("unsupported image format")
+
+	imageExtensions = map[string]string{
+		"image/png":  "png",
+		"image/jpeg": "jpeg",
+		"image/gif":  "gif",
+		"image/bmp":  "bmp",
+		"image/webp": "webp",
+	}
+)
+
+// Image is a replacement value which inserts a picture at the position of the placeholder.
+// The image data is added to the archive only once per document, regardless of how often the image is used.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "logo": docx.Image{Data: logoBytes, Width: 3 * docx.EMUPerCentimeter},
+//	})
+type Image struct {
+	Data []byte // Data is the encoded image, PNG, JPEG, GIF, BMP and WEBP are supported.
+	// Width and Height are the size of the image in EMU. If both are zero, the pixel size of the image at 96 DPI is used.
+	// If only one of them is set, the other one is calculated using the aspect ratio of the image.
+	Width, Height int64
+	Description   string      // Description is the alternative text of the image.
+	Anchor        ImageAnchor // Anchor defines whether the image is placed inline or floating.
+	Wrap          ImageWrap   // Wrap defines how text wraps around floating images.
+	// OffsetX and OffsetY are the position of floating images in EMU, relative to the column and paragraph.
+	OffsetX, OffsetY int64
+	// Caption adds a numbered caption paragraph beneath the image, nil for none. Images with caption are inserted
+	// as paragraphs of their own.
+	Caption *ImageCaption
+}
+
+// inlineXml registers the image in the part and returns a run containing the drawing.
+func (img Image) inlineXml(ctx *valueContext) (string, error) {
+	if img.Caption != nil {
+		if err := img.Caption.validate(); err != nil {
+			return "", err
+		}
+		return ctx.blockMarkerRun(captionedImage{img}), nil
+	}
+	relId, err := ctx.doc.addImage(ctx.part, img.Data)
+	if err != nil {
+		return "", err
+	}
+	width, height, err := ctx.doc.imageSize(img)
+	if err != nil {
+		return "", err
+	}
+
+	id := ctx.doc.nextDocPrId()
+	docPr := fmt.Sprintf(`<wp:docPr id="%d" name="Picture %d" descr="%s"/>`, id, id, html.EscapeString(img.Description))
+	graphic := fmt.Sprintf(`<wp:cNvGraphicFramePr><a:graphicFrameLocks xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" noChangeAspect="1"/></wp:cNvGraphicFramePr>`+
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:nvPicPr><pic:cNvPr id="%d" name="Picture %d"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr></pic:pic></a:graphicData></a:graphic>`,
+		id, id, relId, width, height)
+	extent := fmt.Sprintf(`<wp:extent cx="%d" cy="%d"/><wp:effectExtent l="0" t="0" r="0" b="0"/>`, width, height)
+
+	var drawing string
+	switch img.Anchor {
+	case ImageFloating:
+		behindDoc := 0
+		if img.Wrap == WrapBehindText {
+			behindDoc = 1
+		}
+		drawing = fmt.Sprintf(`<wp:anchor xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" distT="0" distB="0" distL="114300" distR="114300" simplePos="0" relativeHeight="%d" behindDoc="%d" locked="0" layoutInCell="1" allowOverlap="1">`+
+			`<wp:simplePos x="0" y="0"/><wp:positionH relativeFrom="column"><wp:posOffset>%d</wp:posOffset></wp:positionH><wp:positionV relativeFrom="paragraph"><wp:posOffset>%d</wp:posOffset></wp:positionV>`+
+			`%s%s%s%s</wp:anchor>`,
+			id, behindDoc, img.OffsetX, img.OffsetY, extent, img.wrapXml(), docPr, graphic)
+	default:
+		drawing = fmt.Sprintf(`<wp:inline xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" distT="0" distB="0" distL="0" distR="0">%s%s%s</wp:inline>`,
+			extent, docPr, graphic)
+	}
+
+	return fmt.Sprintf(`<w:r>%s<w:drawing>%s</w:drawing></w:r>`, ctx.runProperties, drawing), nil
+}
+
+// wrapXml returns the wrapping element of a floating image.
+func (img Image) wrapXml() string {
+	switch img.Wrap {
+	case WrapTopAndBottom:
+		return `<wp:wrapTopAndBottom/>`
+	case WrapBehindText, WrapInFrontOfText:
+		return `<wp:wrapNone/>`
+	default:
+		return `<wp:wrapSquare wrapText="bothSides"/>`
+	}
+}
+
+// size returns the size of the image in EMU, calculating missing dimensions from the image data.
+func (img Image) size() (int64, int64, error) {
+	if img.Width > 0 && img.Height > 0 {
+		return img.Width, img.Height, nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to determine image size, set Width and Height explicitly: %w", ErrUnsupportedImage)
+	}
+	return img.scale(config)
+}
+
+// scale returns the size of the image in EMU, calculating missing dimensions from the given pixel size.
+func (img Image) scale(config image.Config) (int64, int64, error) {
+	if img.Width > 0 && img.Height > 0 {
+		return img.Width, img.Height, nil
+	}
+	if config.Width == 0 || config.Height == 0 {
+		return 0, 0, fmt.Errorf("unable to determine image size, set Width and Height explicitly: %w", ErrUnsupportedImage)
+	}
+
+	switch {
+	case img.Width > 0:
+		return img.Width, img.Width * int64(config.Height) / int64(config.Width), nil
+	case img.Height > 0:
+		return img.Height * int64(config.Width) / int64(config.Height), img.Height, nil
+	default:
+		return int64(config.Width) * EMUPerPixel, int64(config.Height) * EMUPerPixel, nil
+	}
+}
+
+// addImage adds the image data as media part (once per document) and relates it to the given part (once per part).
+// The relationship ID is returned.
+func (d *Document) addImage(part string, data []byte) (string, error) {
+	var stored *storedMedia
+	var digest [sha256.Size]byte
+	var contentType, extension string
+	if d.mediaStore != nil {
+		var err error
+		if stored, err = d.mediaStore.get(data); err != nil {
+			return "", err
+		}
+		digest, contentType, extension = stored.digest, stored.contentType, stored.extension
+	} else {
+		var supported bool
+		contentType = http.DetectContentType(data)
+		if extension, supported = imageExtensions[contentType]; !supported {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+		}
+		digest = sha256.Sum256(data)
+	}
+
+	if d.images == nil {
+		d.images = make(map[[sha256.Size]byte]string)
+		d.imageRels = make(map[string]string)
+	}
+
+	media, known := d.images[digest]
+	if !known {
+		media = d.newMediaName(extension)
+		if err := d.setPart(media, data); err != nil {
+			return "", err
+		}
+		if err := d.ensureContentTypeDefault(extension, contentType); err != nil {
+			return "", err
+		}
+		d.images[digest] = media
+		if stored != nil {
+			if d.storedMedia == nil {
+				d.storedMedia = make(map[string]*storedMedia)
+			}
+			d.storedMedia[media] = stored
+		}
+	}
+
+	relKey := part + "\x00" + media
+	if relId, related := d.imageRels[relKey]; related {
+		return relId, nil
+	}
+	relId, err := d.addRelationship(part, RelationshipTypeImage, strings.TrimPrefix(media, "word/"), false)
+	if err != nil {
+		return "", err
+	}
+	d.imageRels[relKey] = relId
+	return relId, nil
+}
+
+// imageSize returns the size of the image in EMU, using the pixel size cached by the media store if set.
+func (d *Document) imageSize(img Image) (int64, int64, error) {
+	if d.mediaStore == nil || (img.Width > 0 && img.Height > 0) {
+		return img.size()
+	}
+	stored, err := d.mediaStore.get(img.Data)
+	if err != nil {
+		return 0, 0, err
+	}
+	return img.scale(stored.config)
+}
+
+// newMediaName returns a name for a new media part which does not collide with any existing part.
+func (d *Document) newMediaName(extension string) string {
+	for {
+		d.mediaCount++
+		name := fmt.Sprintf("word/media/image%d.%s", d.mediaCount, extension)
+		if !d.hasPart(name) {
+			return name
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// defaultCaptionLabel is the label of image captions without a label.
+const defaultCaptionLabel = "Figure"
+
+var (
+	// BookmarkNameRegex matches valid bookmark names: at most 40 letters, digits and underscores, starting with a
+	// letter or an underscore (hidden bookmarks).
+	BookmarkNameRegex = regexp.MustCompile(`^[\pL_][\pL\pN_]{0,39}$`)
+	// captionLabelRegex matches valid caption labels, which are the names of their SEQ fields.
+	captionLabelRegex = regexp.MustCompile(`^[\pL_][\pL\pN_]*$`)
+
+	// captionFallbackProperties are the run properties of captions in documents without a caption style.
+	captionFallbackProperties = `<w:rPr><w:i/><w:sz w:val="18"/></w:rPr>`
+)
+
+// ImageCaption is the numbered caption which is added beneath an image, see Image.Caption.
+// The caption reads like 'Figure 3: Revenue by region'. The number is a SEQ field, so Word keeps the numbering
+// consistent when figures are added or removed, and the label and number are enclosed in a bookmark which cross
+// references can point to, e.g. a Hyperlink with the URL '#_RefFigure3' or a REF field.
+type ImageCaption struct {
+	// Text follows the label and number, it is omitted if empty.
+	Text string
+	// Label is the label and the name of the numbering sequence, e.g. 'Chart'. It is 'Figure' if empty.
+	Label string
+	// Bookmark is the name of the bookmark around the label and number. If empty, a hidden bookmark named after the
+	// label and number is added, e.g. '_RefFigure3'.
+	Bookmark string
+}
+
+// label returns the label of the caption, 'Figure' if none is set.
+func (c *ImageCaption) label() string {
+	if c.Label == "" {
+		return defaultCaptionLabel
+	}
+	return c.Label
+}
+
+// validate returns an error if the label or the bookmark of the caption are invalid.
+func (c *ImageCaption) validate() error {
+	if !captionLabelRegex.MatchString(c.label()) {
+		return fmt.Errorf("invalid caption label %q, only letters, digits and underscores are allowed", c.label())
+	}
+	if c.Bookmark != "" && !BookmarkNameRegex.MatchString(c.Bookmark) {
+		return fmt.Errorf("invalid caption bookmark %q", c.Bookmark)
+	}
+	return nil
+}
+
+// captionedImage is an image with caption, which is inserted as block: the image paragraph and the caption paragraph.
+type captionedImage struct {
+	image Image
+}
+
+// blockXml returns the paragraph of the image, which is kept with the next one, followed by the caption paragraph.
+func (c captionedImage) blockXml(ctx *valueContext) (string, error) {
+	caption := c.image.Caption
+	label := caption.label()
+
+	image := c.image
+	image.Caption = nil
+	imageRun, err := image.inlineXml(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	number := ctx.doc.nextSequenceNumber(label)
+	bookmark := caption.Bookmark
+	if bookmark == "" {
+		bookmark = fmt.Sprintf("_Ref%s%d", label, number)
+	}
+
+	paragraphProperties := ctx.paragraphProperties
+	content := strings.TrimSuffix(strings.TrimPrefix(paragraphProperties, "<w:pPr>"), "</w:pPr>")
+	if paragraphProperties == "<w:pPr/>" {
+		content = ""
+	}
+	imageProperties := "<w:pPr>" + setOrderedElement(content, []string{"pStyle", "keepNext"}, "keepNext", "<w:keepNext/>") + "</w:pPr>"
+
+	// the caption is aligned like the image
+	captionProperties, runProperties := "", captionFallbackProperties
+	if ctx.doc.styleExists("Caption") {
+		captionProperties, runProperties = `<w:pStyle w:val="Caption"/>`, ""
+	}
+	if match := alignmentRegex.FindStringSubmatch(paragraphProperties); match != nil {
+		captionProperties += `<w:jc w:val="` + match[1] + `"/>`
+	}
+	if captionProperties != "" {
+		captionProperties = "<w:pPr>" + captionProperties + "</w:pPr>"
+	}
+
+	text := func(value string) string {
+		return `<w:r>` + runProperties + `<w:t xml:space="preserve">` + html.EscapeString(normalizeText(value)) + `</w:t></w:r>`
+	}
+	id := ctx.doc.nextRevisionId()
+	var out strings.Builder
+	out.WriteString(`<w:p>` + imageProperties + imageRun + `</w:p>`)
+	out.WriteString(`<w:p>` + captionProperties)
+	fmt.Fprintf(&out, `<w:bookmarkStart w:id="%d" w:name="%s"/>`, id, bookmark)
+	out.WriteString(text(label + " "))
+	fmt.Fprintf(&out, `<w:fldSimple w:instr=" SEQ %s \* ARABIC "><w:r>%s<w:t>%d</w:t></w:r></w:fldSimple>`, label, runProperties, number)
+	fmt.Fprintf(&out, `<w:bookmarkEnd w:id="%d"/>`, id)
+	if caption.Text != "" {
+		out.WriteString(text(": " + caption.Text))
+	}
+	out.WriteString(`</w:p>`)
+	return out.String(), nil
+}
+
+// nextSequenceNumber returns the number of the next item of the SEQ field sequence with the given name, e.g. of
+// figure captions. Items which already exist in the document body are counted, so new items continue their numbering.
+func (d *Document) nextSequenceNumber(name string) int {
+	if d.sequences == nil {
+		d.sequences = make(map[string]int)
+	}
+	if _, counted := d.sequences[name]; !counted {
+		fields, err := parseFields(d.files[DocumentXml])
+		if err == nil {
+			for _, f := range fields {
+				instruction := ParseFieldInstruction(f.Instruction)
+				if instruction.Type == "SEQ" && len(instruction.Arguments) > 0 && instruction.Arguments[0] == name {
+					d.sequences[name]++
+				}
+			}
+		}
+	}
+	d.sequences[name]++
+	return d.sequences[name]
+}
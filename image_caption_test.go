@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceImageCaption(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `<w:p><w:fldSimple w:instr=" SEQ Figure \* ARABIC "><w:r><w:t>1</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{chart}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{photo}</w:t></w:r></w:p>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	err = doc.ReplaceAll(PlaceholderMap{
+		"chart": Image{Data: imageBytes, Caption: &ImageCaption{Text: "Revenue & costs", Bookmark: "revenue"}},
+		"photo": Image{Data: imageBytes, Caption: &ImageCaption{Label: "Photo"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("document is not well-formed: %s", err)
+	}
+	// the numbering continues after the existing figure and the caption is aligned like the image
+	for _, expected := range []string{
+		`<w:p><w:pPr><w:keepNext/><w:jc w:val="center"/></w:pPr><w:r><w:drawing>`,
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:bookmarkStart w:id="1" w:name="revenue"/>` +
+			`<w:r><w:rPr><w:i/><w:sz w:val="18"/></w:rPr><w:t xml:space="preserve">Figure </w:t></w:r>` +
+			`<w:fldSimple w:instr=" SEQ Figure \* ARABIC "><w:r><w:rPr><w:i/><w:sz w:val="18"/></w:rPr><w:t>2</w:t></w:r></w:fldSimple>` +
+			`<w:bookmarkEnd w:id="1"/><w:r><w:rPr><w:i/><w:sz w:val="18"/></w:rPr><w:t xml:space="preserve">: Revenue &amp; costs</w:t></w:r></w:p>`,
+		`<w:p><w:pPr><w:keepNext/></w:pPr><w:r><w:drawing>`,
+		`<w:bookmarkStart w:id="2" w:name="_RefPhoto1"/>`,
+		`<w:fldSimple w:instr=" SEQ Photo \* ARABIC "><w:r><w:rPr><w:i/><w:sz w:val="18"/></w:rPr><w:t>1</w:t></w:r></w:fldSimple><w:bookmarkEnd w:id="2"/></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in the document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "{chart}") || strings.Contains(document, "{photo}") {
+		t.Error("expected the placeholders to be replaced")
+	}
+}
+
+func TestDocument_ReplaceImageCaptionStyle(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	styles := strings.Replace(testStylesXml, `</w:styles>`,
+		`<w:style w:type="paragraph" w:styleId="Caption"><w:name w:val="caption"/></w:style></w:styles>`, 1)
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{image}</w:t></w:r></w:p>`, StylesXml, styles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.ReplaceAll(PlaceholderMap{"image": Image{Data: imageBytes, Caption: &ImageCaption{Text: "Cameraman"}}}); err != nil {
+		t.Fatal(err)
+	}
+	document := string(doc.files[DocumentXml])
+	if !strings.Contains(document, `<w:p><w:pPr><w:pStyle w:val="Caption"/></w:pPr><w:bookmarkStart w:id="1" w:name="_RefFigure1"/>`+
+		`<w:r><w:t xml:space="preserve">Figure </w:t></w:r>`) {
+		t.Errorf("expected a caption with the caption style: %s", document)
+	}
+
+}
+
+func TestImageCaption_Validate(t *testing.T) {
+	for _, caption := range []ImageCaption{{Label: "Abbildung"}, {Label: "Chart_2", Bookmark: "_Toc1"}} {
+		if err := caption.validate(); err != nil {
+			t.Errorf("unexpected error for %+v: %v", caption, err)
+		}
+	}
+	for _, caption := range []ImageCaption{{Label: "Figure 1"}, {Label: `"`}, {Bookmark: "1st"}, {Bookmark: "with space"},
+		{Bookmark: strings.Repeat("a", 41)}} {
+		if err := caption.validate(); err == nil {
+			t.Errorf("expected an error for %+v", caption)
+		}
+	}
+}
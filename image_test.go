@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceImage(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 1000
+	body := `<w:p><w:r><w:drawing><wp:inline><wp:docPr id="5" name="existing"/></wp:inline></w:drawing></w:r></w:p>` +
+		strings.Repeat(`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>before {logo} after</w:t></w:r></w:p>`, count) +
+		`<w:p><w:r><w:t>{floating}</w:t></w:r></w:p>`
+
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"logo":     Image{Data: imageBytes, Width: 2 * EMUPerCentimeter, Description: "Logo & Co"},
+		"floating": Image{Data: imageBytes, Anchor: ImageFloating, Wrap: WrapTopAndBottom},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.Bytes()
+
+	document := readTestPart(t, output, DocumentXml)
+	if err := xml.Unmarshal([]byte(document), new(interface{})); err != nil {
+		t.Fatalf("document is not well-formed: %s", err)
+	}
+
+	// every drawing needs its own id, the existing id must not be reused
+	ids := make(map[string]bool)
+	for _, match := range DocPrIdRegex.FindAllStringSubmatch(document, -1) {
+		if ids[match[1]] {
+			t.Fatalf("duplicate docPr id %s", match[1])
+		}
+		ids[match[1]] = true
+	}
+	if len(ids) != count+2 {
+		t.Errorf("expected %d drawings, have %d", count+2, len(ids))
+	}
+	if !strings.Contains(document, `<wp:anchor`) || !strings.Contains(document, `<wp:wrapTopAndBottom/>`) {
+		t.Error("expected a floating image")
+	}
+	if !strings.Contains(document, `descr="Logo &amp; Co"`) {
+		t.Error("expected escaped image description")
+	}
+
+	// the image must be stored and related only once
+	rels := readTestPart(t, output, "word/_rels/document.xml.rels")
+	if n := strings.Count(rels, RelationshipTypeImage); n != 1 {
+		t.Errorf("expected a single image relationship, have %d", n)
+	}
+	readTestPart(t, output, "word/media/image1.jpeg")
+	if types := readTestPart(t, output, ContentTypesXml); !strings.Contains(types, `Extension="jpeg" ContentType="image/jpeg"`) {
+		t.Errorf("expected jpeg content type, have %s", types)
+	}
+}
+
+func TestImage_Size(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	width, height, err := Image{Data: imageBytes}.size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width <= 0 || height <= 0 {
+		t.Errorf("invalid size %dx%d", width, height)
+	}
+
+	scaledWidth, scaledHeight, err := Image{Data: imageBytes, Width: width / 2}.size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaledWidth != width/2 || scaledHeight != height/2 {
+		t.Errorf("aspect ratio not preserved, have %dx%d", scaledWidth, scaledHeight)
+	}
+
+	if _, _, err := (Image{Data: []byte("no image")}).size(); err == nil {
+		t.Error("expected error for invalid image")
+	}
+}
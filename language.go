@@ -0,0 +1,86 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	// languageElementRegex matches the language element (<w:lang .../>) of run properties.
+	languageElementRegex = regexp.MustCompile(`<w:lang\s[^>]*/>`)
+
+	// eastAsianScripts are the scripts whose languages are set by the w:eastAsia attribute of <w:lang>.
+	eastAsianScripts = []string{"Hani", "Hans", "Hant", "Jpan", "Kore", "Hira", "Kana", "Hang", "Bopo"}
+	// complexScripts are the scripts whose languages are set by the w:bidi attribute of <w:lang>.
+	complexScripts = []string{"Arab", "Hebr", "Syrc", "Thaa", "Nkoo", "Thai", "Deva", "Beng", "Guru", "Gujr", "Orya",
+		"Taml", "Telu", "Knda", "Mlym", "Sinh", "Khmr", "Laoo", "Tibt", "Mymr", "Ethi"}
+)
+
+// LangText is a replacement value which inserts text in a language of its own, e.g. the foreign names and addresses
+// inside a multilingual letter. Word checks the spelling of the text in that language (instead of flagging every
+// word) and screen readers announce it in the right language. Lang is a BCP 47 tag, e.g. 'sq-AL'.
+// East Asian and complex script languages (e.g. 'ja-JP' or 'ar-SA') are set for their script only,
+// so the language of the other scripts of the placeholder run is kept.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "name":    docx.LangText{Text: "Gëzim Krasniqi", Lang: "sq-AL"},
+//	    "address": docx.LangText{Text: "Rruga e Durrësit 12\n1001 Tiranë", Lang: "sq-AL"},
+//	})
+type LangText struct {
+	Text string
+	Lang string
+}
+
+// String returns the text.
+func (l LangText) String() string {
+	return l.Text
+}
+
+// inlineXml returns the runs of the text with the language added to the run properties.
+func (l LangText) inlineXml(ctx *valueContext) (string, error) {
+	tag, err := language.Parse(l.Lang)
+	if err != nil {
+		return "", fmt.Errorf("invalid language %s: %w", l.Lang, err)
+	}
+	langCtx := *ctx
+	langCtx.runProperties = setRunLanguage(ctx.runProperties, languageAttribute(tag), l.Lang)
+	return langCtx.valueXml(l.Text)
+}
+
+// languageAttribute returns the attribute of <w:lang> which sets the language: w:eastAsia for East Asian languages,
+// w:bidi for complex script languages and w:val for all other languages.
+func languageAttribute(tag language.Tag) string {
+	script, _ := tag.Script()
+	switch {
+	case containsString(eastAsianScripts, script.String()):
+		return "w:eastAsia"
+	case containsString(complexScripts, script.String()):
+		return "w:bidi"
+	default:
+		return "w:val"
+	}
+}
+
+// setRunLanguage returns the run properties with the attribute of the language element set to the tag.
+// The other attributes of an existing language element are kept.
+func setRunLanguage(runProperties, attribute, tag string) string {
+	value := attribute + `="` + html.EscapeString(tag) + `"`
+	element := languageElementRegex.FindString(runProperties)
+	if element == "" {
+		return setRunProperty(runProperties, "lang", `<w:lang `+value+`/>`)
+	}
+	var attributes []string
+	for _, match := range xmlAttributeRegex.FindAllStringSubmatch(element, -1) {
+		if match[1] != attribute {
+			attributes = append(attributes, match[0])
+		}
+	}
+	attributes = append(attributes, value)
+	return setRunProperty(runProperties, "lang", `<w:lang `+strings.Join(attributes, " ")+`/>`)
+}
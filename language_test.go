@@ -0,0 +1,50 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceLangText(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr><w:t>Dear {name},</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{city} {company}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"name":    LangText{Text: "Gëzim Krasniqi", Lang: "sq-AL"},
+		"city":    LangText{Text: "القاهرة", Lang: "ar-EG"},
+		"company": LangText{Text: "株式会社", Lang: "ja-JP"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		// the east asian language of the placeholder run is kept
+		`<w:r><w:rPr><w:b/><w:lang w:eastAsia="zh-CN" w:val="sq-AL"/></w:rPr><w:t xml:space="preserve">Gëzim Krasniqi</w:t></w:r>`,
+		`<w:r><w:rPr><w:lang w:bidi="ar-EG"/></w:rPr><w:t xml:space="preserve">القاهرة</w:t></w:r>`,
+		`<w:r><w:rPr><w:lang w:eastAsia="ja-JP"/></w:rPr><w:t xml:space="preserve">株式会社</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
+
+func TestDocument_ReplaceLangText_Invalid(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{name}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{"name": LangText{Text: "Jane", Lang: "not a language"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid language") {
+		t.Errorf("expected invalid language error, have %v", err)
+	}
+}
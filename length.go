@@ -0,0 +1,134 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+var (
+	// ErrValueTooLong is returned by ReplaceAll if a value exceeds the maximum length of its placeholder
+	// and the limit uses LengthError.
+	ErrValueTooLong = errors.New("value exceeds maximum length")
+
+	// fontSizeRegex matches the font size of run properties and captures the size in half-points.
+	fontSizeRegex = regexp.MustCompile(`<w:sz\s+w:val="([0-9]+)"`)
+)
+
+// defaultFontSize is the font size (half-points) of runs if neither the run nor the document defaults specify one.
+const defaultFontSize = 20
+
+// LengthPolicy selects how values which exceed the maximum length of their placeholder are handled.
+type LengthPolicy int
+
+const (
+	// LengthTruncate cuts the value at the maximum length and appends the ellipsis.
+	LengthTruncate LengthPolicy = iota
+	// LengthError fails the replacement with ErrValueTooLong.
+	LengthError
+	// LengthShrink reduces the font size of the value so it takes about the space of the maximum length.
+	// If the minimum font size is reached, the rest of the value is truncated.
+	LengthShrink
+)
+
+// LengthLimit is the maximum length of the values of placeholders, see Document.SetMaxLength.
+type LengthLimit struct {
+	// Max is the maximum number of characters of a value.
+	Max int
+	// Policy selects how longer values are handled, LengthTruncate by default.
+	Policy LengthPolicy
+	// Ellipsis is appended to truncated values, "…" if empty. The ellipsis counts towards the maximum length.
+	Ellipsis string
+	// MinSize is the smallest font size in half-points used by LengthShrink, 12 (6 pt) if zero.
+	MinSize int
+}
+
+// SetMaxLength limits the length of the values which replace the given placeholder keys, so unexpected data
+// (e.g. a product name of 5000 characters) does not silently break the layout of the document.
+// The limit applies to text values, other values such as images are not affected. The keys may be given with
+// or without delimiters. It must be called before ReplaceAll or Replace.
+//
+// Example:
+//
+//	doc.SetMaxLength(docx.LengthLimit{Max: 40}, "product")
+//	doc.SetMaxLength(docx.LengthLimit{Max: 20, Policy: docx.LengthShrink}, "title")
+//	doc.SetMaxLength(docx.LengthLimit{Max: 34, Policy: docx.LengthError}, "iban")
+func (d *Document) SetMaxLength(limit LengthLimit, keys ...string) error {
+	if limit.Max < 1 {
+		return fmt.Errorf("invalid maximum length %d", limit.Max)
+	}
+	if limit.Ellipsis == "" {
+		limit.Ellipsis = "…"
+	}
+	if limit.MinSize <= 0 {
+		limit.MinSize = 12
+	}
+	if d.lengthLimits == nil {
+		d.lengthLimits = make(map[string]LengthLimit)
+	}
+	for _, key := range keys {
+		d.lengthLimits[RemovePlaceholderDelimiter(key)] = limit
+	}
+	return nil
+}
+
+// limitLength applies the length limit of the key to the text. It returns the limited text and the font size
+// (half-points) the text must be shrunk to, which is zero if the font size is kept.
+func (d *Document) limitLength(key, text, runProperties string) (string, int, error) {
+	limit, limited := d.lengthLimits[RemovePlaceholderDelimiter(key)]
+	length := utf8.RuneCountInString(text)
+	if !limited || length <= limit.Max {
+		return text, 0, nil
+	}
+
+	switch limit.Policy {
+	case LengthError:
+		return "", 0, fmt.Errorf("%w: %s has %d characters, maximum %d", ErrValueTooLong, key, length, limit.Max)
+	case LengthShrink:
+		size := d.fontSize(runProperties)
+		shrunk := max(size*limit.Max/length, min(limit.MinSize, size))
+		// the number of characters which fit into the space of the maximum length at the shrunk size
+		fitting := limit.Max * size / shrunk
+		if shrunk == size {
+			shrunk = 0
+		}
+		if length <= fitting {
+			return text, shrunk, nil
+		}
+		return truncateText(text, fitting, limit.Ellipsis), shrunk, nil
+	default:
+		return truncateText(text, limit.Max, limit.Ellipsis), 0, nil
+	}
+}
+
+// fontSize returns the font size (half-points) of runs with the given properties.
+func (d *Document) fontSize(runProperties string) int {
+	if match := fontSizeRegex.FindStringSubmatch(runProperties); match != nil {
+		if size, err := strconv.Atoi(match[1]); err == nil && size > 0 {
+			return size
+		}
+	}
+	styles, _, err := d.styles()
+	if err != nil {
+		return defaultFontSize
+	}
+	if defaults := runPropertiesDefaultRegex.FindSubmatch(styles); defaults != nil {
+		if match := fontSizeRegex.FindSubmatch(defaults[1]); match != nil {
+			if size, err := strconv.Atoi(string(match[1])); err == nil && size > 0 {
+				return size
+			}
+		}
+	}
+	return defaultFontSize
+}
+
+// truncateText cuts the text to the maximum number of characters including the ellipsis.
+func truncateText(text string, maxLength int, ellipsis string) string {
+	keep := maxLength - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return string([]rune(text)[:maxLength])
+	}
+	return string([]rune(text)[:keep]) + ellipsis
+}
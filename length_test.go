@@ -0,0 +1,65 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDocument_SetMaxLength(t *testing.T) {
+	body := `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Name: {name}</w:t></w:r></w:p>`
+	tests := []struct {
+		limit    LengthLimit
+		value    string
+		expected string
+	}{
+		{LengthLimit{Max: 10}, "Short name", `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Name: Short name</w:t></w:r></w:p>`},
+		{LengthLimit{Max: 10}, "A very long product name", `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Name: A very lo…</w:t></w:r></w:p>`},
+		{LengthLimit{Max: 8, Ellipsis: "..."}, "Ä very long", `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Name: Ä ver...</w:t></w:r></w:p>`},
+		{
+			LengthLimit{Max: 10, Policy: LengthShrink}, "Fifteen letters",
+			`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Name: </w:t></w:r>` +
+				`<w:r><w:rPr><w:b/><w:sz w:val="13"/><w:szCs w:val="13"/></w:rPr><w:t xml:space="preserve">Fifteen letters</w:t></w:r>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"></w:t></w:r></w:p>`,
+		},
+		{
+			LengthLimit{Max: 4, Policy: LengthShrink, MinSize: 10}, "Twelve chars",
+			`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Name: </w:t></w:r>` +
+				`<w:r><w:rPr><w:b/><w:sz w:val="10"/><w:szCs w:val="10"/></w:rPr><w:t xml:space="preserve">Twelve …</w:t></w:r>` +
+				`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"></w:t></w:r></w:p>`,
+		},
+	}
+	for _, tt := range tests {
+		doc, err := OpenBytes(buildTestDocx(t, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.SetMaxLength(tt.limit, "{name}"); err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.ReplaceAll(PlaceholderMap{"name": tt.value}); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if document := readTestPart(t, buf.Bytes(), DocumentXml); document != testDocumentOpen+tt.expected+testDocumentClose {
+			t.Errorf("%+v: expected %s, have %s", tt.limit, tt.expected, document)
+		}
+	}
+
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetMaxLength(LengthLimit{Max: 5, Policy: LengthError}, "name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Too long"}); !errors.Is(err, ErrValueTooLong) {
+		t.Errorf("expected ErrValueTooLong, have %v", err)
+	}
+	if err := doc.SetMaxLength(LengthLimit{}, "name"); err == nil {
+		t.Error("expected an error for an invalid maximum length")
+	}
+}
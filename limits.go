@@ -0,0 +1,150 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrLimitExceeded is returned if a DOCX archive exceeds one of the configured ParseLimits.
+	ErrLimitExceeded = errors.New("parse limit exceeded")
+	// ErrDTDForbidden is returned if an XML part contains a document type declaration (<!DOCTYPE ...>).
+	// WordprocessingML never requires a DTD, so they are rejected to rule out entity expansion attacks.
+	ErrDTDForbidden = errors.New("document type declarations are not allowed")
+)
+
+// ParseLimits restricts the resources which may be consumed while reading a DOCX archive.
+// Templates are frequently supplied by users, so every archive is treated as untrusted input.
+// A zero value for any of the numeric limits disables that specific limit.
+type ParseLimits struct {
+	// MaxArchiveEntries is the maximum amount of files inside the ZIP archive.
+	MaxArchiveEntries int
+	// MaxPartSize is the maximum uncompressed size of a single part in bytes.
+	MaxPartSize int64
+	// MaxTotalSize is the maximum uncompressed size of all parts which are read, in bytes.
+	MaxTotalSize int64
+	// MaxNestingDepth is the maximum nesting depth of XML elements inside a part.
+	MaxNestingDepth int
+	// AllowDTD permits document type declarations inside XML parts.
+	// Even if allowed, entities are never expanded.
+	AllowDTD bool
+}
+
+// DefaultParseLimits are the limits used by Open, OpenBytes and all processing functions.
+// They are generous enough for very large documents while still protecting against
+// ZIP bombs, deeply nested XML and DTD based entity expansion. Changing them is not safe while documents are
+// processed, use Defaults.ParseLimits to change the limits of a running service.
+var DefaultParseLimits = ParseLimits{
+	MaxArchiveEntries: 10000,
+	MaxPartSize:       512 << 20,
+	MaxTotalSize:      2 << 30,
+	MaxNestingDepth:   1000,
+	AllowDTD:          false,
+}
+
+// checkArchive validates the archive directory against the limits before any part is read.
+// The sizes declared in the directory are checked here, actual sizes are enforced while reading.
+func (l ParseLimits) checkArchive(zipReader *zip.Reader) error {
+	if l.MaxArchiveEntries > 0 && len(zipReader.File) > l.MaxArchiveEntries {
+		return fmt.Errorf("%w: archive has %d entries, maximum is %d", ErrLimitExceeded, len(zipReader.File), l.MaxArchiveEntries)
+	}
+	var total uint64
+	for _, file := range zipReader.File {
+		if l.MaxPartSize > 0 && file.UncompressedSize64 > uint64(l.MaxPartSize) {
+			return fmt.Errorf("%w: %s has %d bytes, maximum is %d", ErrLimitExceeded, file.Name, file.UncompressedSize64, l.MaxPartSize)
+		}
+		total += file.UncompressedSize64
+	}
+	if l.MaxTotalSize > 0 && total > uint64(l.MaxTotalSize) {
+		return fmt.Errorf("%w: archive has %d uncompressed bytes, maximum is %d", ErrLimitExceeded, total, l.MaxTotalSize)
+	}
+	return nil
+}
+
+// readZipFile reads the given file from the archive, never reading more than MaxPartSize bytes
+// regardless of the size declared inside the archive.
+func (l ParseLimits) readZipFile(file *zip.File) ([]byte, error) {
+	readCloser, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", file.Name, err)
+	}
+	defer readCloser.Close()
+
+	var reader io.Reader = readCloser
+	if l.MaxPartSize > 0 {
+		reader = io.LimitReader(readCloser, l.MaxPartSize+1)
+	}
+	fileBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", file.Name, err)
+	}
+	if l.MaxPartSize > 0 && int64(len(fileBytes)) > l.MaxPartSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrLimitExceeded, file.Name, l.MaxPartSize)
+	}
+	return fileBytes, nil
+}
+
+// copyPart copies the part with the given name from reader to writer, never copying more than MaxPartSize bytes.
+func (l ParseLimits) copyPart(writer io.Writer, reader io.Reader, name string) error {
+	if l.MaxPartSize > 0 {
+		reader = io.LimitReader(reader, l.MaxPartSize+1)
+	}
+	n, err := io.Copy(writer, reader)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", name, err)
+	}
+	if l.MaxPartSize > 0 && n > l.MaxPartSize {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrLimitExceeded, name, l.MaxPartSize)
+	}
+	return nil
+}
+
+// checkXML validates the XML part with the given name against the limits.
+// Parts which are not XML (e.g. media files) are ignored.
+func (l ParseLimits) checkXML(name string, data []byte) error {
+	if !isXMLPart(name) {
+		return nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w in %s: %w", ErrMalformedXML, name, err)
+		}
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if l.MaxNestingDepth > 0 && depth > l.MaxNestingDepth {
+				return fmt.Errorf("%w: %s is nested deeper than %d elements", ErrLimitExceeded, name, l.MaxNestingDepth)
+			}
+		case xml.EndElement:
+			depth--
+		case xml.Directive:
+			if !l.AllowDTD && isDTD(elem) {
+				return fmt.Errorf("%w: found in %s", ErrDTDForbidden, name)
+			}
+		}
+	}
+}
+
+// isXMLPart returns true if the part name denotes an XML part (including relationship parts).
+func isXMLPart(name string) bool {
+	return strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".rels")
+}
+
+// isDTD returns true if the directive is a document type or entity declaration.
+func isDTD(directive xml.Directive) bool {
+	d := strings.ToUpper(strings.TrimSpace(string(directive)))
+	return strings.HasPrefix(d, "DOCTYPE") || strings.HasPrefix(d, "ENTITY")
+}
@@ -0,0 +1,94 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseLimits_CheckXML(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		limits   ParseLimits
+		expected error
+	}{
+		"valid": {
+			data:   `<w:document><w:body><w:p/></w:body></w:document>`,
+			limits: DefaultParseLimits,
+		},
+		"doctype": {
+			data:     `<?xml version="1.0"?><!DOCTYPE foo [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><w:document>&xxe;</w:document>`,
+			limits:   DefaultParseLimits,
+			expected: ErrDTDForbidden,
+		},
+		"billion laughs": {
+			data:     `<?xml version="1.0"?><!DOCTYPE lolz [<!ENTITY lol "lol"><!ENTITY lol2 "&lol;&lol;&lol;">]><lolz>&lol2;</lolz>`,
+			limits:   DefaultParseLimits,
+			expected: ErrDTDForbidden,
+		},
+		"nesting": {
+			data:     strings.Repeat("<a>", 11) + strings.Repeat("</a>", 11),
+			limits:   ParseLimits{MaxNestingDepth: 10},
+			expected: ErrLimitExceeded,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.limits.checkXML("word/document.xml", []byte(tt.data))
+			if tt.expected == nil && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, have %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestOpenBytesWithLimits(t *testing.T) {
+	docBytes := buildTestDocx(t, `<w:p><w:r><w:t>{foo}</w:t></w:r></w:p>`)
+
+	if _, err := OpenBytesWithLimits(docBytes, ParseLimits{MaxPartSize: 64}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for part size, have %v", err)
+	}
+	if _, err := OpenBytesWithLimits(docBytes, ParseLimits{MaxArchiveEntries: 2}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for archive entries, have %v", err)
+	}
+
+	doctype := buildTestDocxWithDocument(t, `<?xml version="1.0"?><!DOCTYPE w:document [<!ENTITY a "b">]><w:document><w:body/></w:document>`)
+	if _, err := OpenBytes(doctype); !errors.Is(err, ErrDTDForbidden) {
+		t.Errorf("expected ErrDTDForbidden, have %v", err)
+	}
+}
+
+// buildTestDocxWithDocument returns a minimal DOCX archive which contains the given document.xml verbatim.
+func buildTestDocxWithDocument(t testing.TB, document string) []byte {
+	docBytes := buildTestDocx(t, "")
+	out, err := rewriteArchive(docBytes, FileMap{DocumentXml: []byte(document)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func FuzzParseLimits_CheckXML(f *testing.F) {
+	f.Add([]byte(`<w:document><w:body><w:p><w:r><w:t>{foo}</w:t></w:r></w:p></w:body></w:document>`))
+	f.Add([]byte(`<!DOCTYPE a [<!ENTITY b "c">]><a>&b;</a>`))
+	f.Add([]byte(`<a><b></a>`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = DefaultParseLimits.checkXML(DocumentXml, data)
+	})
+}
+
+func FuzzOpenBytes(f *testing.F) {
+	f.Add(buildTestDocx(f, `<w:p><w:r><w:t>{a}{b</w:t></w:r><w:r><w:t>}</w:t></w:r></w:p>`))
+	f.Add([]byte("PK\x03\x04"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := OpenBytes(data)
+		if err != nil {
+			return
+		}
+		doc.Close()
+	})
+}
@@ -0,0 +1,393 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// the styles of the locale-aware date function, see localeFuncs.
+const (
+	dateStyleShort  = "short"
+	dateStyleMedium = "medium"
+	dateStyleLong   = "long"
+	dateStyleFull   = "full"
+)
+
+// dateNames are the names and the date patterns of a language. The patterns use the letters of Unicode date
+// patterns: d and dd for the day, M and MM for the month number, MMM and MMMM for the abbreviated and the full
+// month name, yyyy for the year and EEEE for the weekday. Text in single quotes is written as is.
+type dateNames struct {
+	months, shortMonths [12]string
+	// weekdays start with Sunday like time.Weekday
+	weekdays           [7]string
+	medium, long, full string
+}
+
+var (
+	// currencyPatterns are the positions of the currency symbol per language, ¤ is the symbol and # the amount.
+	// More specific tags (e.g. 'de-CH') take precedence over the base language, all other languages put the symbol
+	// in front of the amount, separated by a space. The spaces are written as non-breaking spaces, so amounts never
+	// wrap.
+	currencyPatterns = map[string]string{
+		"en": "¤#", "ja": "¤#", "zh": "¤#", "ko": "¤#", "tr": "¤#", "hi": "¤#", "th": "¤#", "es-MX": "¤#", "es-US": "¤#",
+		"nl": "¤ #", "de-AT": "¤ #", "de-CH": "¤ #", "it-CH": "¤ #", "pt-BR": "¤ #",
+		"de": "# ¤", "fr": "# ¤", "es": "# ¤", "it": "# ¤", "pt": "# ¤", "pl": "# ¤",
+		"ru": "# ¤", "uk": "# ¤", "sv": "# ¤", "da": "# ¤", "nb": "# ¤", "no": "# ¤",
+		"fi": "# ¤", "cs": "# ¤", "sk": "# ¤", "hu": "# ¤", "ro": "# ¤", "hr": "# ¤",
+		"sl": "# ¤", "bg": "# ¤", "el": "# ¤", "lt": "# ¤", "lv": "# ¤", "et": "# ¤",
+	}
+
+	// englishDateNames are used for dates without locale.
+	englishDateNames = dateNames{
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:    [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "EEEE, d MMMM yyyy",
+	}
+
+	// localeDateNames are the date names per language, more specific tags (e.g. 'en-US') take precedence over the
+	// base language. The months are in the form used inside dates, e.g. the genitive in Polish and Russian.
+	localeDateNames = map[string]dateNames{
+		"en": englishDateNames,
+		"en-US": {
+			months: englishDateNames.months, shortMonths: englishDateNames.shortMonths, weekdays: englishDateNames.weekdays,
+			medium: "MMM d, yyyy", long: "MMMM d, yyyy", full: "EEEE, MMMM d, yyyy",
+		},
+		"de": {
+			months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+			weekdays:    [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			medium:      "dd.MM.yyyy", long: "d. MMMM yyyy", full: "EEEE, d. MMMM yyyy",
+		},
+		"fr": {
+			months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			weekdays:    [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "EEEE d MMMM yyyy",
+		},
+		"es": {
+			months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			weekdays:    [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			medium:      "d MMM yyyy", long: "d 'de' MMMM 'de' yyyy", full: "EEEE, d 'de' MMMM 'de' yyyy",
+		},
+		"it": {
+			months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+			weekdays:    [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+			medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "EEEE d MMMM yyyy",
+		},
+		"pt": {
+			months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+			shortMonths: [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+			weekdays:    [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+			medium:      "d 'de' MMM 'de' yyyy", long: "d 'de' MMMM 'de' yyyy", full: "EEEE, d 'de' MMMM 'de' yyyy",
+		},
+		"nl": {
+			months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+			shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+			weekdays:    [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+			medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "EEEE d MMMM yyyy",
+		},
+		"pl": {
+			months:      [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+			shortMonths: [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+			weekdays:    [7]string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+			medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "EEEE, d MMMM yyyy",
+		},
+		"ru": {
+			months:      [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+			shortMonths: [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+			weekdays:    [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+			medium:      "d MMM yyyy 'г'.", long: "d MMMM yyyy 'г'.", full: "EEEE, d MMMM yyyy 'г'.",
+		},
+		"tr": {
+			months:      [12]string{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"},
+			shortMonths: [12]string{"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"},
+			weekdays:    [7]string{"Pazar", "Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi"},
+			medium:      "d MMM yyyy", long: "d MMMM yyyy", full: "d MMMM yyyy EEEE",
+		},
+		"ja": {
+			weekdays: [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+			medium:   "yyyy/MM/dd", long: "yyyy年M月d日", full: "yyyy年M月d日EEEE",
+		},
+		"zh": {
+			weekdays: [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+			medium:   "yyyy年M月d日", long: "yyyy年M月d日", full: "yyyy年M月d日EEEE",
+		},
+	}
+)
+
+// localeFuncs returns the template functions which format numbers, amounts and dates according to a locale. The
+// locale is an optional last argument, the locale of the engine (see RenderOptions.Locale) is used without it.
+//
+//	{{number .Quantity}} {{number .Rate 2 "de-DE"}}   -> 1,234.5 and 0,25 with the given number of decimals
+//	{{currency "EUR" .Total "de-DE"}}                   -> 1.234,50 € rounded to the digits of the currency
+//	{{money .Total "EUR" "de-DE"}}                      -> the same with the amount first, e.g. in pipelines
+//	{{date .DueDate "long" "fr-FR"}}                    -> 14 mars 2025, the styles are short, medium, long and full
+//
+// The date function also accepts a Go layout first, like {{date "02 Jan 2006" .DueDate}}, see commonFuncs.
+// The medium, long and full date styles return an error for locales whose month and weekday names are not known
+// (see localeDateNames), use a Go layout for those.
+func (e *templateEngine) localeFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"number": func(value interface{}, args ...interface{}) (string, error) {
+			if len(args) > 2 {
+				return "", fmt.Errorf("number: too many arguments, expected value [decimals] [locale]")
+			}
+			decimals, locale := -1, e.locale
+			for i, arg := range args {
+				switch v := arg.(type) {
+				case int:
+					if i > 0 {
+						return "", fmt.Errorf("number: the decimals must precede the locale")
+					}
+					decimals = v
+				case string:
+					locale = v
+				default:
+					return "", fmt.Errorf("number: unexpected argument %v, expected decimals or locale", arg)
+				}
+			}
+			return formatNumber("number", value, decimals, locale)
+		},
+		"currency": func(code string, amount interface{}, locale ...string) (string, error) {
+			return formatCurrency("currency", amount, code, e.localeArgument(locale))
+		},
+		"money": func(amount interface{}, code string, locale ...string) (string, error) {
+			return formatCurrency("money", amount, code, e.localeArgument(locale))
+		},
+		"date": func(args ...interface{}) (string, error) {
+			// {{date .DueDate "long"}} is told apart from the Go layout of {{date "2006-01-02" .DueDate}} by the style
+			if len(args) == 2 || len(args) == 3 {
+				if style, isString := args[1].(string); isString && isDateStyle(style) {
+					locale := e.locale
+					if len(args) == 3 {
+						if locale, isString = args[2].(string); !isString {
+							return "", fmt.Errorf("date: the locale must be a string, not %T", args[2])
+						}
+					}
+					return formatDateStyle(args[0], style, locale)
+				}
+			}
+			if len(args) != 2 {
+				return "", fmt.Errorf("date: expected a value, a style and an optional locale or a layout and a value")
+			}
+			layout, isString := args[0].(string)
+			if !isString {
+				return "", fmt.Errorf("date: unknown style %v, expected short, medium, long or full", args[1])
+			}
+			date, isSet, err := templateDate(args[1])
+			if err != nil || !isSet {
+				return "", err
+			}
+			return date.Format(layout), nil
+		},
+	}
+}
+
+// templateLanguage returns the default language of the text of the template document (see Document.SetLanguage),
+// or an empty string if it has none.
+func templateLanguage(input []byte) (string, error) {
+	parts, err := readArchiveParts(input, func(name string) bool { return name == StylesXml })
+	if err != nil {
+		return "", err
+	}
+	defaults := runPropertiesDefaultRegex.FindSubmatch(parts[StylesXml])
+	if defaults == nil {
+		return "", nil
+	}
+	match := languageRegex.FindSubmatch(defaults[1])
+	if match == nil || len(match[1]) == 0 {
+		return "", nil
+	}
+	tag := html.UnescapeString(strings.TrimSuffix(strings.TrimPrefix(string(match[1]), `w:val="`), `"`))
+	if _, err := language.Parse(tag); err != nil {
+		// Word writes tags like 'x-none' which are no locales
+		return "", nil
+	}
+	return tag, nil
+}
+
+// localeArgument returns the optional locale argument of a template function or the locale of the engine.
+func (e *templateEngine) localeArgument(locale []string) string {
+	if len(locale) > 0 {
+		return locale[len(locale)-1]
+	}
+	return e.locale
+}
+
+// parseLocale returns the language tag of the locale, language.Und if it is empty.
+func parseLocale(locale string) (language.Tag, error) {
+	if locale == "" {
+		return language.Und, nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid locale %s: %w", locale, err)
+	}
+	return tag, nil
+}
+
+// lookupLocale returns the entry of the table for the language tag, entries of the language and region (e.g.
+// 'de-CH') take precedence over entries of the base language. There is no entry for language.Und.
+func lookupLocale[T any](table map[string]T, tag language.Tag) (T, bool) {
+	if tag == language.Und {
+		var none T
+		return none, false
+	}
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	if entry, ok := table[base.String()+"-"+region.String()]; ok {
+		return entry, true
+	}
+	entry, ok := table[base.String()]
+	return entry, ok
+}
+
+// formatNumber formats the number with the separators of the locale and the given number of decimals, or at most
+// three decimals if decimals is negative.
+func formatNumber(name string, value interface{}, decimals int, locale string) (string, error) {
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	i, f, isInt, err := templateNumber(name, value)
+	if err != nil {
+		return "", err
+	}
+	printer := message.NewPrinter(tag)
+	switch {
+	case decimals >= 0:
+		return printer.Sprint(number.Decimal(f, number.Scale(decimals))), nil
+	case isInt:
+		return printer.Sprint(number.Decimal(i)), nil
+	}
+	return printer.Sprint(number.Decimal(f)), nil
+}
+
+// formatCurrency formats the amount with the symbol of the currency (an ISO 4217 code like 'EUR') at the position
+// and with the separators of the locale, rounded to the digits of the currency, e.g. 1.234,50 € for 'de-DE' or
+// $1,234.50 for 'en-US'.
+func formatCurrency(name string, amount interface{}, code string, locale string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("%s: invalid currency code %s: %w", name, code, err)
+	}
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	value, err := templateFloat(name, amount)
+	if err != nil {
+		return "", err
+	}
+
+	scale, _ := currency.Standard.Rounding(unit)
+	printer := message.NewPrinter(tag)
+	pattern, found := lookupLocale(currencyPatterns, tag)
+	if !found {
+		pattern = "¤ #"
+	}
+	formatted := strings.NewReplacer(
+		" ", "\u00a0",
+		"¤", printer.Sprint(currency.Symbol(unit)),
+		"#", printer.Sprint(number.Decimal(math.Abs(value), number.Scale(scale))),
+	).Replace(pattern)
+	if value < 0 && math.Round(value*math.Pow(10, float64(scale))) != 0 {
+		formatted = "-" + formatted
+	}
+	return formatted, nil
+}
+
+// isDateStyle returns true for the names of the date styles.
+func isDateStyle(style string) bool {
+	return style == dateStyleShort || style == dateStyleMedium || style == dateStyleLong || style == dateStyleFull
+}
+
+// formatDateStyle formats the date in the style of the locale. The short style is the date of FormatValue, the
+// other styles use the month and weekday names of the locale and return an error for languages without names.
+// Without locale, the short and medium style are ISO 8601 and the other styles use English names.
+func formatDateStyle(value interface{}, style string, locale string) (string, error) {
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", fmt.Errorf("date: %w", err)
+	}
+	date, isSet, err := templateDate(value)
+	if err != nil || !isSet {
+		return "", err
+	}
+	if style == dateStyleShort || locale == "" && style == dateStyleMedium {
+		return date.Format(shortDateLayout(tag, locale != "")), nil
+	}
+
+	names, found := lookupLocale(localeDateNames, tag)
+	if !found && locale != "" {
+		return "", fmt.Errorf("date: the %s style is not supported for locale %s, use a Go layout", style, locale)
+	}
+	if !found {
+		names = englishDateNames
+	}
+	pattern := names.full
+	switch style {
+	case dateStyleMedium:
+		pattern = names.medium
+	case dateStyleLong:
+		pattern = names.long
+	}
+	return formatDatePattern(date, pattern, names), nil
+}
+
+// formatDatePattern formats the date with the date pattern, see dateNames.
+func formatDatePattern(date time.Time, pattern string, names dateNames) string {
+	var formatted strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				end = len(pattern) - i - 1
+			}
+			formatted.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if !strings.ContainsRune("dMyE", rune(c)) {
+			formatted.WriteByte(c)
+			i++
+			continue
+		}
+		count := 1
+		for i+count < len(pattern) && pattern[i+count] == c {
+			count++
+		}
+		switch {
+		case c == 'd' && count == 1:
+			formatted.WriteString(fmt.Sprint(date.Day()))
+		case c == 'd':
+			formatted.WriteString(fmt.Sprintf("%02d", date.Day()))
+		case c == 'M' && count == 1:
+			formatted.WriteString(fmt.Sprint(int(date.Month())))
+		case c == 'M' && count == 2:
+			formatted.WriteString(fmt.Sprintf("%02d", int(date.Month())))
+		case c == 'M' && count == 3:
+			formatted.WriteString(names.shortMonths[date.Month()-1])
+		case c == 'M':
+			formatted.WriteString(names.months[date.Month()-1])
+		case c == 'y':
+			formatted.WriteString(fmt.Sprintf("%04d", date.Year()))
+		case c == 'E':
+			formatted.WriteString(names.weekdays[date.Weekday()])
+		}
+		i += count
+	}
+	return formatted.String()
+}
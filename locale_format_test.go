@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocaleFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"Total":   1234.5,
+		"Refund":  -99.99,
+		"Rate":    0.25,
+		"Count":   12345,
+		"DueDate": time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		action   string
+		expected string
+	}{
+		{`{{currency "EUR" .Total "de-DE"}}`, "1.234,50\u00a0€"},
+		{`{{currency "USD" .Total "en-US"}}`, "$1,234.50"},
+		{`{{currency "CHF" .Total "de-CH"}}`, "CHF\u00a01’234.50"},
+		{`{{currency "EUR" .Total "nl-NL"}}`, "€\u00a01.234,50"},
+		{`{{currency "JPY" .Total "ja-JP"}}`, "￥1,234"},
+		{`{{currency "GBP" .Refund "en-GB"}}`, "-£99.99"},
+		{`{{money .Total "EUR" "de-DE"}}`, "1.234,50\u00a0€"},
+		{`{{number .Count}} {{number .Rate 2 "de-DE"}} {{number .Total "fr-FR"}}`, "12,345 0,25 1\u00a0234,5"},
+		{`{{date .DueDate "long" "fr-FR"}}`, "14 mars 2025"},
+		{`{{date .DueDate "full" "de-DE"}}`, "Freitag, 14. März 2025"},
+		{`{{date .DueDate "medium" "en-US"}}`, "Mar 14, 2025"},
+		{`{{date .DueDate "short" "en-US"}}`, "3/14/2025"},
+		{`{{date .DueDate "long" "es-ES"}}`, "14 de marzo de 2025"},
+		{`{{date .DueDate "long" "pl-PL"}}`, "14 marca 2025"},
+		{`{{date .DueDate "full" "ja-JP"}}`, "2025年3月14日金曜日"},
+		{`{{date .DueDate "medium"}} {{date "2025-03-14" "long"}}`, "2025-03-14 14 March 2025"},
+		{`{{date "02/01/2006" .DueDate}} {{date .Missing "long" "fr-FR"}}`, "14/03/2025 "},
+	}
+	for _, test := range tests {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+test.action+`</w:t></w:r></w:p>`)
+		output, err := ProcessTemplateDocx(input, data)
+		if err != nil {
+			t.Errorf("%s: %v", test.action, err)
+			continue
+		}
+		if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, ">"+test.expected+"<") {
+			t.Errorf("%s: expected %q in document, have %s", test.action, test.expected, document)
+		}
+	}
+}
+
+func TestLocaleFuncs_DocumentLocale(t *testing.T) {
+	body := `<w:p><w:r><w:t>{{currency "EUR" .Total}} {{date .DueDate "long"}} {{locale}}</w:t></w:r></w:p>`
+	styles := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:docDefaults><w:rPrDefault><w:rPr><w:lang w:val="fr-FR" w:eastAsia="en-US" w:bidi="ar-SA"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`
+	input := buildTestDocx(t, body, StylesXml, styles)
+	data := map[string]interface{}{"Total": 1234.5, "DueDate": time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)}
+
+	// without locale, the functions format without locale
+	output, err := ProcessTemplateDocx(input, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "€\u00a01,234.50 14 March 2025 <") {
+		t.Errorf("expected no locale: %s", document)
+	}
+
+	// the language of the template is used on request
+	output, err = Render(FromBytes(input), data, WithTemplateLocale())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "1\u00a0234,50\u00a0€ 14 mars 2025 fr-FR") {
+		t.Errorf("expected the language of the template: %s", document)
+	}
+
+	// the locale option takes precedence
+	output, err = Render(FromBytes(input), data, WithTemplateLocale(), WithLocale("de-DE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "1.234,50\u00a0€ 14. März 2025 de-DE") {
+		t.Errorf("expected the locale option: %s", document)
+	}
+}
+
+func TestLocaleFuncs_Errors(t *testing.T) {
+	for _, action := range []string{
+		`{{currency "EUR" 1 "not a locale"}}`,
+		`{{currency "EURO" 1}}`,
+		`{{money 1 "EUR" "not a locale"}}`,
+		`{{number 1 "de-DE" 2}}`,
+		`{{date "2025-03-14" "long" 42}}`,
+		`{{date 42}}`,
+		`{{date "2025-03-14" "long" "sq-AL"}}`,
+	} {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+action+`</w:t></w:r></w:p>`)
+		if _, err := ProcessTemplateDocx(input, nil); err == nil {
+			t.Errorf("%s: expected error", action)
+		}
+	}
+}
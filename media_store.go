@@ -0,0 +1,156 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"net/http"
+	"sync"
+)
+
+// MediaStore caches images which are inserted into many documents, e.g. logos and signatures of batch renders.
+// Every image is analyzed and compressed only once, all documents which use the store write the compressed
+// bytes directly into their archive instead of encoding the image again.
+// Images are identified by their content, the image data must not be modified after it was used.
+// A MediaStore is safe for concurrent use by multiple documents.
+//
+// Example:
+//
+//	store := docx.NewMediaStore()
+//	for _, customer := range customers {
+//	    doc, _ := docx.OpenBytes(templateBytes)
+//	    doc.SetMediaStore(store)
+//	    doc.ReplaceAll(docx.PlaceholderMap{"logo": docx.Image{Data: logoBytes}})
+//	    ...
+//	}
+type MediaStore struct {
+	mu    sync.Mutex
+	media map[[sha256.Size]byte]*storedMedia
+	// slices maps the image data slices which were already seen to their media, so known slices are not hashed again
+	slices map[mediaSlice]*storedMedia
+}
+
+// mediaSlice identifies an image data slice by its memory location.
+type mediaSlice struct {
+	first *byte
+	len   int
+}
+
+// storedMedia is an image which was analyzed once and is compressed on first use.
+type storedMedia struct {
+	data        []byte
+	digest      [sha256.Size]byte
+	contentType string
+	extension   string
+	// config is the pixel size of the image, it is zero if the size cannot be decoded
+	config image.Config
+
+	compressOnce sync.Once
+	compressed   []byte
+	crc          uint32
+	compressErr  error
+}
+
+// NewMediaStore returns an empty MediaStore.
+func NewMediaStore() *MediaStore {
+	return &MediaStore{
+		media:  make(map[[sha256.Size]byte]*storedMedia),
+		slices: make(map[mediaSlice]*storedMedia),
+	}
+}
+
+// Len returns the amount of distinct images inside the store.
+func (s *MediaStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.media)
+}
+
+// get returns the stored media of the image data, analyzing the image if it is not yet known.
+func (s *MediaStore) get(data []byte) (*storedMedia, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty image data", ErrUnsupportedImage)
+	}
+	slice := mediaSlice{first: &data[0], len: len(data)}
+	s.mu.Lock()
+	media, known := s.slices[slice]
+	s.mu.Unlock()
+	if known {
+		return media, nil
+	}
+
+	digest := sha256.Sum256(data)
+	s.mu.Lock()
+	media, known = s.media[digest]
+	s.mu.Unlock()
+	if !known {
+		// the image is analyzed without holding the lock, concurrent analysis of the same image is harmless
+		contentType := http.DetectContentType(data)
+		extension, supported := imageExtensions[contentType]
+		if !supported {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+		}
+		media = &storedMedia{data: data, digest: digest, contentType: contentType, extension: extension}
+		if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			media.config = config
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, known := s.media[digest]; known {
+		media = existing
+	} else {
+		s.media[digest] = media
+	}
+	s.slices[slice] = media
+	return media, nil
+}
+
+// compress returns the deflate compressed image data and its checksum, compressing the data on first use.
+func (m *storedMedia) compress() ([]byte, uint32, error) {
+	m.compressOnce.Do(func() {
+		var buf bytes.Buffer
+		writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err == nil {
+			_, err = writer.Write(m.data)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		m.compressed, m.crc, m.compressErr = buf.Bytes(), crc32.ChecksumIEEE(m.data), err
+	})
+	return m.compressed, m.crc, m.compressErr
+}
+
+// writeTo writes the media as part with the given name into the archive using the precompressed data.
+func (m *storedMedia) writeTo(zipWriter *zip.Writer, name string) error {
+	compressed, crc, err := m.compress()
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", name, err)
+	}
+	fw, err := zipWriter.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Deflate,
+		CRC32:              crc,
+		CompressedSize64:   uint64(len(compressed)),
+		UncompressedSize64: uint64(len(m.data)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create writer: %s", err)
+	}
+	if _, err := fw.Write(compressed); err != nil {
+		return fmt.Errorf("unable to writeFile %s: %s", name, err)
+	}
+	return nil
+}
+
+// SetMediaStore sets the store which is used for all images inserted into the document afterwards.
+// The same store should be set on all documents of a batch so the images are analyzed and compressed only once.
+func (d *Document) SetMediaStore(store *MediaStore) {
+	d.mediaStore = store
+}
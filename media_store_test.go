@@ -0,0 +1,86 @@
+package docx
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMediaStore(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := buildTestDocx(t, `<w:p><w:r><w:t>{logo} {signature}</w:t></w:r></w:p>`)
+	// a copy of the image must be recognized by its content
+	signature := append([]byte{}, imageBytes...)
+
+	store := NewMediaStore()
+	for i := 0; i < 3; i++ {
+		doc, err := OpenBytes(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.SetMediaStore(store)
+		err = doc.ReplaceAll(PlaceholderMap{
+			"logo":      Image{Data: imageBytes},
+			"signature": Image{Data: signature, Width: EMUPerCentimeter},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if media := readTestPart(t, buf.Bytes(), "word/media/image1.jpeg"); media != string(imageBytes) {
+			t.Error("expected the stored image inside the document")
+		}
+		document := readTestPart(t, buf.Bytes(), DocumentXml)
+		if !strings.Contains(document, `<wp:extent cx="2438400" cy="2438400"/>`) || !strings.Contains(document, `<wp:extent cx="360000" cy="360000"/>`) {
+			t.Errorf("expected image sizes from the store: %s", document)
+		}
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected a single stored image, have %d", store.Len())
+	}
+}
+
+func TestMediaStore_Concurrent(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMediaStore()
+	media := make([]*storedMedia, 8)
+	var wg sync.WaitGroup
+	for i := range media {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m, err := store.get(append([]byte{}, imageBytes...))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, _, err := m.compress(); err != nil {
+				t.Error(err)
+			}
+			media[i] = m
+		}(i)
+	}
+	wg.Wait()
+
+	for _, m := range media {
+		if m != media[0] {
+			t.Fatal("expected all documents to share the stored image")
+		}
+	}
+	if _, err := store.get([]byte("no image")); err == nil {
+		t.Error("expected error for unsupported image data")
+	}
+}
@@ -0,0 +1,188 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// recordSeparatorXml is the paragraph which separates the records of a combined mail merge.
+const recordSeparatorXml = `<w:p><w:r>` + pageBreakXml + `</w:r></w:p>`
+
+// MergeMany performs a mail merge: the placeholders of the template are replaced by the values of every record,
+// producing one document per record in the order of the records. The template is parsed only once, every record
+// is rendered from a copy of the parsed template (see PreparedTemplate), which is considerably faster than calling
+// ProcessBytes per record.
+// Errors name the index of the failing record.
+//
+// Example:
+//
+//	letters, err := docx.MergeMany(templateBytes, []docx.PlaceholderMap{
+//	    {"name": "Jane Doe", "city": "Springfield"},
+//	    {"name": "John Roe", "city": "Shelbyville"},
+//	})
+func MergeMany(template []byte, records []PlaceholderMap) ([][]byte, error) {
+	prepared, err := Prepare(template)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([][]byte, len(records))
+	for i, record := range records {
+		if outputs[i], err = prepared.Render(record); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+	}
+	return outputs, nil
+}
+
+// MergeManyCombined works like MergeMany but returns a single document which contains the body of the template
+// once per record, separated by page breaks, e.g. to print all letters of a batch at once.
+// Headers, footers and notes are rendered with the values of the first record. Images and other values which add
+// parts to the document may be used in every record.
+//
+// Example:
+//
+//	letters, err := docx.MergeManyCombined(templateBytes, records)
+func MergeManyCombined(template []byte, records []PlaceholderMap) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no records to merge")
+	}
+	base, err := OpenBytes(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document from bytes: %w", err)
+	}
+	defer base.Close()
+
+	combined := base.clone()
+	if err := combined.ReplaceAll(records[0]); err != nil {
+		return nil, fmt.Errorf("record 0: failed to replace placeholders: %w", err)
+	}
+	var bodies strings.Builder
+	for i := 1; i < len(records); i++ {
+		doc := base.clone()
+		doc.shareParts(combined)
+		if err := doc.replaceParts(records[i], []string{DocumentXml}); err != nil {
+			return nil, fmt.Errorf("record %d: failed to replace placeholders: %w", i, err)
+		}
+		combined.shareParts(doc)
+
+		content, _, err := bodyContent(doc.files[DocumentXml])
+		if err != nil {
+			return nil, err
+		}
+		bodies.WriteString(recordSeparatorXml + content)
+	}
+
+	if bodies.Len() > 0 {
+		data := combined.files[DocumentXml]
+		_, end, err := bodyContent(data)
+		if err != nil {
+			return nil, err
+		}
+		merged := string(data[:end]) + bodies.String() + string(data[end:])
+		if err := combined.SetFile(DocumentXml, []byte(merged)); err != nil {
+			return nil, err
+		}
+		if err := combined.parseFile(DocumentXml); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := combined.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// bodyContent returns the content of the document body without the final section properties,
+// and the offset at which the content ends inside the document.
+func bodyContent(document []byte) (string, int, error) {
+	match := BodyContentRegex.FindSubmatchIndex(document)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid document, %s has no body", DocumentXml)
+	}
+	start, end := match[2], match[3]
+	content := string(document[start:end])
+	children := childElements(content)
+	if len(children) > 0 && children[len(children)-1].name == "sectPr" {
+		end = start + children[len(children)-1].start
+	}
+	return string(document[start:end]), end, nil
+}
+
+// clone returns a copy of the document which can be modified independently, without parsing the archive again.
+// The copy shares the archive but not the file handle, it does not need to be closed.
+func (d *Document) clone() *Document {
+	clone := *d
+	clone.docxFile = nil
+	clone.source = nil
+	clone.files = make(FileMap, len(d.files))
+	clone.runParsers = make(map[string]*RunParser, len(d.runParsers))
+	clone.filePlaceholders = make(map[string][]*Placeholder, len(d.filePlaceholders))
+	clone.fileReplacers = make(map[string]*Replacer, len(d.fileReplacers))
+	for name, data := range d.files {
+		// the replacers modify the bytes in place
+		clone.files[name] = bytes.Clone(data)
+		parser, exists := d.runParsers[name]
+		if !exists {
+			continue
+		}
+
+		runs := make(map[*Run]*Run, len(parser.runs))
+		clonedParser := &RunParser{doc: clone.files[name], runs: make(DocumentRuns, len(parser.runs))}
+		for i, run := range parser.runs {
+			clonedRun := *run
+			clonedParser.runs[i], runs[run] = &clonedRun, &clonedRun
+		}
+		placeholders := make([]*Placeholder, len(d.filePlaceholders[name]))
+		for i, placeholder := range d.filePlaceholders[name] {
+			fragments := make([]*PlaceholderFragment, len(placeholder.Fragments))
+			for j, fragment := range placeholder.Fragments {
+				clonedFragment := *fragment
+				if run, exists := runs[fragment.Run]; exists {
+					clonedFragment.Run = run
+				} else {
+					clonedRun := *fragment.Run
+					clonedFragment.Run = &clonedRun
+				}
+				fragments[j] = &clonedFragment
+			}
+			placeholders[i] = &Placeholder{Fragments: fragments}
+		}
+		clone.runParsers[name] = clonedParser
+		clone.filePlaceholders[name] = placeholders
+		clone.fileReplacers[name] = NewReplacer(clone.files[name], placeholders)
+	}
+
+	clone.headerFiles = slices.Clone(d.headerFiles)
+	clone.footerFiles = slices.Clone(d.footerFiles)
+	clone.noteFiles = slices.Clone(d.noteFiles)
+	clone.mediaFiles = slices.Clone(d.mediaFiles)
+	clone.chartFiles = slices.Clone(d.chartFiles)
+	clone.modified = maps.Clone(d.modified)
+	clone.parts = make(FileMap, len(d.parts))
+	for name, data := range d.parts {
+		clone.parts[name] = bytes.Clone(data)
+	}
+	clone.newParts = slices.Clone(d.newParts)
+	clone.streamedParts = maps.Clone(d.streamedParts)
+	clone.relIds = maps.Clone(d.relIds)
+	clone.images = maps.Clone(d.images)
+	clone.imageRels = maps.Clone(d.imageRels)
+	clone.storedMedia = maps.Clone(d.storedMedia)
+	clone.blocks = nil
+	clone.authors = maps.Clone(d.authors)
+	clone.lengthLimits = maps.Clone(d.lengthLimits)
+	return &clone
+}
+
+// shareParts makes the document use the parts and the counters of IDs and media of the other document,
+// so content of both documents can be combined without conflicting relationships or media parts.
+func (d *Document) shareParts(other *Document) {
+	d.parts, d.newParts, d.modified = other.parts, other.newParts, other.modified
+	d.relIds, d.docPrId, d.revisionId = other.relIds, other.docPrId, other.revisionId
+	d.images, d.imageRels, d.mediaCount, d.storedMedia = other.images, other.imageRels, other.mediaCount, other.storedMedia
+}
@@ -0,0 +1,132 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SectionScope is a section of a document composed by MergeScoped, e.g. one statement of a bundle, with the values
+// which are visible inside the section only.
+type SectionScope struct {
+	// Name identifies the section in diagnostics, 'section <index>' if it is empty.
+	Name string
+	// Template is the template of the section, the template given to MergeScoped if it is nil.
+	Template []byte
+	// Values are the values of the section, they replace shared values with the same key.
+	Values PlaceholderMap
+}
+
+// ScopeDiagnostic reports a placeholder of a section without a value in its own scope, but with a value in the
+// scope of other sections, e.g. data which was attached to the wrong statement. The placeholder is not replaced.
+type ScopeDiagnostic struct {
+	// Section is the name of the section containing the placeholder.
+	Section string
+	// Key is the placeholder without delimiters.
+	Key string
+	// Part is the name of the part of the section template containing the placeholder, e.g. 'word/header1.xml'.
+	Part string
+	// Scopes are the names of the sections which have a value for the key.
+	Scopes []string
+}
+
+// String describes the diagnostic.
+func (d ScopeDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s in %s (%s) has no value in its scope, only in %s",
+		d.Section, d.Key, d.Part, partKind(d.Part), strings.Join(d.Scopes, ", "))
+}
+
+// MergeScoped composes a single document of the sections, e.g. bundled statements of multiple accounts. Every
+// section is rendered from its template with its own values and the shared values, including the headers and
+// footers of its template; values of other sections never leak into it. Each section after the first starts on a
+// new page, see Document.Append.
+// Placeholders without a value in the scope of their section are not replaced. If another section has a value
+// for them, a ScopeDiagnostic is returned, the document is composed nevertheless.
+//
+// Example:
+//
+//	output, diagnostics, err := docx.MergeScoped(statementTemplate, docx.PlaceholderMap{"bank": "ACME Bank"},
+//	    []docx.SectionScope{
+//	        {Name: "checking", Values: docx.PlaceholderMap{"account": "DE02 1203 0000 0000 2020 51", "balance": "1,250.00"}},
+//	        {Name: "savings", Values: docx.PlaceholderMap{"account": "DE02 1001 0010 0006 8201 01", "balance": "9,800.00"}},
+//	    })
+//	for _, diagnostic := range diagnostics {
+//	    log.Println(diagnostic)
+//	}
+func MergeScoped(template []byte, shared PlaceholderMap, sections []SectionScope) ([]byte, []ScopeDiagnostic, error) {
+	if len(sections) == 0 {
+		return nil, nil, fmt.Errorf("no sections to merge")
+	}
+	names := make([]string, len(sections))
+	scopes := make(map[string][]string)
+	for i, section := range sections {
+		names[i] = section.Name
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("section %d", i)
+		}
+		for key := range section.Values {
+			key = RemovePlaceholderDelimiter(key)
+			scopes[key] = append(scopes[key], names[i])
+		}
+	}
+
+	var combined *Document
+	var diagnostics []ScopeDiagnostic
+	for i, section := range sections {
+		source := section.Template
+		if source == nil {
+			source = template
+		}
+		doc, err := OpenBytes(source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to open document from bytes: %w", names[i], err)
+		}
+		values := make(PlaceholderMap, len(shared)+len(section.Values))
+		for key, value := range shared {
+			values[key] = value
+		}
+		for key, value := range section.Values {
+			values[key] = value
+		}
+		if err := doc.ReplaceAll(values); err != nil {
+			doc.Close()
+			return nil, nil, fmt.Errorf("%s: failed to replace placeholders: %w", names[i], err)
+		}
+
+		unresolved, err := doc.UnresolvedPlaceholders()
+		if err != nil {
+			doc.Close()
+			return nil, nil, err
+		}
+		for _, placeholder := range unresolved {
+			if others := scopes[placeholder.Key]; len(others) > 0 {
+				sorted := append([]string{}, others...)
+				sort.Strings(sorted)
+				diagnostics = append(diagnostics, ScopeDiagnostic{
+					Section: names[i],
+					Key:     placeholder.Key,
+					Part:    placeholder.Part,
+					Scopes:  sorted,
+				})
+			}
+		}
+
+		if combined == nil {
+			combined = doc
+			defer combined.Close()
+			continue
+		}
+		err = combined.Append(doc)
+		doc.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", names[i], err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := combined.Write(&buf); err != nil {
+		return nil, nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), diagnostics, nil
+}
@@ -0,0 +1,57 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeScoped(t *testing.T) {
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{bank} {account}</w:t></w:r></w:p></w:hdr>`
+	rels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="` + RelationshipTypeHeader + `" Target="header1.xml"/></Relationships>`
+	template := buildTestDocx(t, `<w:p><w:r><w:t>{account}: {balance} at {rate}</w:t></w:r></w:p>`+
+		`<w:sectPr><w:headerReference w:type="default" r:id="rId1"/></w:sectPr>`,
+		"word/header1.xml", header, "word/_rels/document.xml.rels", rels)
+	summary := buildTestDocx(t, `<w:p><w:r><w:t>Total {total}</w:t></w:r></w:p>`)
+
+	output, diagnostics, err := MergeScoped(template, PlaceholderMap{"bank": "ACME Bank"}, []SectionScope{
+		{Name: "checking", Values: PlaceholderMap{"account": "Checking", "balance": "1,250.00"}},
+		{Name: "savings", Values: PlaceholderMap{"account": "Savings", "balance": "9,800.00", "rate": "2%"}},
+		{Template: summary, Values: PlaceholderMap{"total": "11,050.00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{"Checking: 1,250.00 at {rate}", "Savings: 9,800.00 at 2%", "Total 11,050.00"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+	for name, expected := range map[string]string{"word/header1.xml": "ACME Bank Checking", "word/header2.xml": "ACME Bank Savings"} {
+		if header := readTestPart(t, output, name); !strings.Contains(header, expected) {
+			t.Errorf("expected %s in %s: %s", expected, name, header)
+		}
+	}
+
+	expected := []ScopeDiagnostic{{Section: "checking", Key: "rate", Part: DocumentXml, Scopes: []string{"savings"}}}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("expected %+v, have %+v", expected, diagnostics)
+	}
+	if message := diagnostics[0].String(); message != "checking: rate in word/document.xml (body) has no value in its scope, only in savings" {
+		t.Errorf("unexpected message %s", message)
+	}
+}
+
+func TestMergeScoped_Errors(t *testing.T) {
+	if _, _, err := MergeScoped(nil, nil, nil); err == nil {
+		t.Error("expected an error without sections")
+	}
+	template := buildTestDocx(t, `<w:p><w:r><w:t>{name}</w:t></w:r></w:p>`)
+	_, _, err := MergeScoped(template, nil, []SectionScope{{Values: PlaceholderMap{"name": "Jane"}}, {Template: []byte("invalid")}})
+	if err == nil || !strings.HasPrefix(err.Error(), "section 1:") {
+		t.Errorf("expected an error of section 1, have %v", err)
+	}
+}
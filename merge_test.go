@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMergeMany(t *testing.T) {
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{name}</w:t></w:r></w:p></w:hdr>`
+	template := buildTestDocx(t, `<w:p><w:r><w:t>Dear {</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>name},</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{city}</w:t></w:r></w:p>`, "word/header1.xml", header)
+
+	outputs, err := MergeMany(template, []PlaceholderMap{
+		{"name": "Jane", "city": "Springfield"},
+		{"name": "John", "city": "Shelbyville"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 {
+		t.Fatalf("expected 2 documents, have %d", len(outputs))
+	}
+	for i, expected := range []string{"Jane", "John"} {
+		document := readTestPart(t, outputs[i], DocumentXml)
+		if !strings.Contains(document, "Dear "+expected) || strings.Contains(document, "{") {
+			t.Errorf("expected document %d for %s: %s", i, expected, document)
+		}
+		if header := readTestPart(t, outputs[i], "word/header1.xml"); !strings.Contains(header, expected) {
+			t.Errorf("expected header %d for %s: %s", i, expected, header)
+		}
+	}
+
+	_, err = MergeMany(template, []PlaceholderMap{{"name": "Jane", "city": "Springfield"}, {"name": LangText{Text: "John", Lang: "?"}, "city": "Shelbyville"}})
+	if err == nil || !strings.HasPrefix(err.Error(), "record 1:") {
+		t.Errorf("expected error of record 1, have %v", err)
+	}
+}
+
+func TestMergeManyCombined(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}</w:t></w:r></w:p><w:p><w:r><w:t>{signature}</w:t></w:r></w:p>`+
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`)
+	signature := Image{Data: imageBytes, Width: 2 * EMUPerCentimeter}
+
+	output, err := MergeManyCombined(template, []PlaceholderMap{
+		{"name": "Jane", "signature": signature},
+		{"name": "John", "signature": signature},
+		{"name": "Jim", "signature": ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{"Dear Jane", "Dear John", "Dear Jim"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if n := strings.Count(document, pageBreakXml); n != 2 {
+		t.Errorf("expected 2 page breaks between the records, have %d", n)
+	}
+	if n := strings.Count(document, "<w:sectPr>"); n != 1 || !strings.HasSuffix(document, "</w:sectPr></w:body></w:document>") {
+		t.Errorf("expected the section properties at the end of the body: %s", document)
+	}
+	if n := strings.Count(document, `r:embed="rId1"`); n != 2 {
+		t.Errorf("expected both records to use the same image relationship, have %d", n)
+	}
+	rels := readTestPart(t, output, "word/_rels/document.xml.rels")
+	if n := strings.Count(rels, RelationshipTypeImage); n != 1 {
+		t.Errorf("expected a single image relationship, have %d: %s", n, rels)
+	}
+}
@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"fmt"
+)
+
+// MergeFieldType is the type of Word mail merge fields.
+const MergeFieldType = "MERGEFIELD"
+
+// MergeFields converts all MERGEFIELD fields of the document body, headers and footers into plain text
+// using the values of the PlaceholderMap, keyed by the field name. This allows legacy mail merge templates
+// to be used alongside placeholders.
+// The formatting switches of the fields (e.g. \# "0.00", \@ "MMMM d, yyyy" or \* Upper) are applied
+// to the values, see FieldInstruction.Format. Values which render their own runs (e.g. Image) are inserted as is.
+// Fields without a value in the PlaceholderMap are left untouched.
+//
+// Example:
+//
+//	// { MERGEFIELD Total \# "#,##0.00" } and { MERGEFIELD Due \@ "MMMM d, yyyy" }
+//	doc.MergeFields(docx.PlaceholderMap{
+//	    "Total": 1234.5,    // 1,234.50
+//	    "Due":   time.Now(), // October 16, 2026
+//	})
+func (d *Document) MergeFields(values PlaceholderMap) error {
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		fields, err := parseFields(data)
+		if err != nil {
+			return fmt.Errorf("unable to parse fields in %s: %w", name, err)
+		}
+
+		changed := false
+		// fields are merged in reverse order so that the offsets of the remaining fields stay valid
+		for i := len(fields) - 1; i >= 0; i-- {
+			f := fields[i]
+			instruction := ParseFieldInstruction(f.Instruction)
+			if f.Depth > 0 || instruction.Type != MergeFieldType || len(instruction.Arguments) == 0 {
+				continue
+			}
+			fieldName := instruction.Arguments[0]
+			value, exists := values[fieldName]
+			if !exists {
+				continue
+			}
+
+			runsXml, err := d.mergeFieldXml(name, f, instruction, value)
+			if err != nil {
+				return fmt.Errorf("unable to merge field %s: %w", fieldName, err)
+			}
+
+			merged := append([]byte{}, data[:f.Start]...)
+			merged = append(merged, runsXml...)
+			data = append(merged, data[f.End:]...)
+			changed = true
+		}
+
+		if changed {
+			if err := d.SetFile(name, data); err != nil {
+				return err
+			}
+			if err := d.parseFile(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeFieldXml returns the runs which replace the merge field.
+func (d *Document) mergeFieldXml(part string, f *field, instruction FieldInstruction, value interface{}) (string, error) {
+	ctx := &valueContext{
+		doc:           d,
+		part:          part,
+		runProperties: f.RunProperties,
+	}
+	if d.textPolicy.Newlines == NewlineParagraph {
+		ctx.paragraphProperties = paragraphPropertiesAt(d.files[part], f.Start)
+	}
+
+	var runsXml string
+	if rich, isRich := value.(inlineValue); isRich {
+		xml, err := rich.inlineXml(ctx)
+		if err != nil {
+			return "", err
+		}
+		runsXml = xml
+	} else {
+		text, err := instruction.Format(value)
+		if err != nil {
+			return "", err
+		}
+		runsXml = d.textPolicy.textRuns(ctx, d.textPolicy.apply(normalizeText(text)))
+	}
+
+	if author, attributed := d.authors[instruction.Arguments[0]]; attributed {
+		runsXml = d.trackedInsertion(author, runsXml)
+	}
+	return runsXml, nil
+}
@@ -0,0 +1,196 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"text/template/parse"
+)
+
+// fieldFuncName is the name of the function which replaces the fields written by template actions if missing
+// fields are preserved or replaced by a default value, see MissingKeyPolicy.
+const fieldFuncName = "_docxField"
+
+// missingKeyMode is the behavior of a MissingKeyPolicy.
+type missingKeyMode int
+
+const (
+	missingKeyUnset missingKeyMode = iota
+	missingKeyEmpty
+	missingKeyPreserve
+	missingKeyError
+	missingKeyDefault
+)
+
+// MissingKeyPolicy controls what templates write for fields which are missing in the data, e.g. {{.Customer.Phone}}
+// if the customer map has no phone. Missing are keys of maps and fields of nil values, fields which a struct does
+// not declare are always an error like in text/template. The zero value is MissingKeyEmptyString.
+//
+// MissingKeyPreserveTag and MissingKeyDefaultValue apply to actions which write a single field, e.g.
+// {{.Customer.Phone}} or {{$item.SKU}}. Missing fields inside conditions or passed to functions are nil like with
+// MissingKeyEmptyString. MissingKeyError applies to all actions.
+//
+// Example:
+//
+//	outputBytes, err := docx.Render(docx.FromBytes(templateBytes), data, docx.WithMissingKey(docx.MissingKeyDefaultValue("n/a")))
+type MissingKeyPolicy struct {
+	mode  missingKeyMode
+	value interface{}
+}
+
+var (
+	// MissingKeyEmptyString writes nothing for missing fields, this is the default.
+	MissingKeyEmptyString = MissingKeyPolicy{mode: missingKeyEmpty}
+	// MissingKeyPreserveTag writes the action of missing fields as is, e.g. {{.Customer.Phone}}, so the document
+	// shows which data is missing.
+	MissingKeyPreserveTag = MissingKeyPolicy{mode: missingKeyPreserve}
+	// MissingKeyError aborts the render with an error naming the missing key, like the missingkey=error option of
+	// text/template.
+	MissingKeyError = MissingKeyPolicy{mode: missingKeyError}
+)
+
+// MissingKeyDefaultValue writes the value for missing fields. The value may be any value which templates write,
+// e.g. a string or an Image.
+func MissingKeyDefaultValue(value interface{}) MissingKeyPolicy {
+	return MissingKeyPolicy{mode: missingKeyDefault, value: value}
+}
+
+// String returns the name of the policy.
+func (p MissingKeyPolicy) String() string {
+	switch p.mode {
+	case missingKeyPreserve:
+		return "preserve-tag"
+	case missingKeyError:
+		return "error"
+	case missingKeyDefault:
+		return fmt.Sprintf("default-value(%v)", p.value)
+	}
+	return "empty-string"
+}
+
+// resolvesFields returns true if the fields written by actions are looked up by the field function of the engine.
+func (p MissingKeyPolicy) resolvesFields() bool {
+	return p.mode == missingKeyPreserve || p.mode == missingKeyDefault
+}
+
+// field returns the field with the given name of the receiver, which is written by the action. If the field is
+// missing, the value of the missing key policy is returned instead.
+func (e *templateEngine) field(action string, receiver interface{}, name string) (interface{}, error) {
+	value, found, err := lookupTemplateField(reflect.ValueOf(receiver), name)
+	if err != nil || found {
+		return value, err
+	}
+	switch e.missingKey.mode {
+	case missingKeyPreserve:
+		return templateXml(html.EscapeString(TemplateOpenDelimiter + action + TemplateCloseDelimiter)), nil
+	case missingKeyDefault:
+		return e.missingKey.value, nil
+	}
+	return nil, nil
+}
+
+// lookupTemplateField returns the field with the given name of the receiver like text/template evaluates it: niladic
+// methods, keys of maps and exported fields of structs. The second return value is false if the receiver is nil or a
+// map without the key.
+func lookupTemplateField(receiver reflect.Value, name string) (interface{}, bool, error) {
+	for receiver.IsValid() && receiver.Kind() == reflect.Interface {
+		receiver = receiver.Elem()
+	}
+	if !receiver.IsValid() {
+		return nil, false, nil
+	}
+	if method := receiver.MethodByName(name); method.IsValid() {
+		return callTemplateMethod(method, name)
+	}
+	for receiver.Kind() == reflect.Pointer || receiver.Kind() == reflect.Interface {
+		if receiver.IsNil() {
+			return nil, false, nil
+		}
+		receiver = receiver.Elem()
+	}
+
+	switch receiver.Kind() {
+	case reflect.Map:
+		if receiver.Type().Key().Kind() != reflect.String {
+			break
+		}
+		value := receiver.MapIndex(reflect.ValueOf(name).Convert(receiver.Type().Key()))
+		if !value.IsValid() {
+			return nil, false, nil
+		}
+		return value.Interface(), true, nil
+	case reflect.Struct:
+		if field, exists := receiver.Type().FieldByName(name); exists && field.IsExported() {
+			return receiver.FieldByIndex(field.Index).Interface(), true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("can't evaluate field %s in type %s", name, receiver.Type())
+}
+
+// callTemplateMethod calls the method which is accessed like a field, it must not have parameters and may return
+// an error as second result.
+func callTemplateMethod(method reflect.Value, name string) (interface{}, bool, error) {
+	methodType := method.Type()
+	if methodType.NumIn() > 0 || methodType.NumOut() == 0 || methodType.NumOut() > 2 {
+		return nil, false, fmt.Errorf("method %s cannot be written, it must not have parameters and return a value", name)
+	}
+	results := method.Call(nil)
+	if len(results) == 2 && !results[1].IsNil() {
+		err, _ := results[1].Interface().(error)
+		return nil, false, fmt.Errorf("error calling %s: %w", name, err)
+	}
+	return results[0].Interface(), true, nil
+}
+
+// resolveFieldActions replaces the field of every action below the node which writes a single field by a call of
+// the field function, e.g. {{.Customer.Phone}} becomes {{_docxField ".Customer.Phone" .Customer "Phone"}}.
+func resolveFieldActions(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			resolveFieldActions(child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+			return
+		}
+		cmd := n.Pipe.Cmds[0]
+		var receiver parse.Node
+		var name string
+		switch arg := cmd.Args[0].(type) {
+		case *parse.FieldNode:
+			name = arg.Ident[len(arg.Ident)-1]
+			receiver = &parse.DotNode{NodeType: parse.NodeDot, Pos: arg.Pos}
+			if len(arg.Ident) > 1 {
+				receiver = &parse.FieldNode{NodeType: parse.NodeField, Pos: arg.Pos, Ident: arg.Ident[:len(arg.Ident)-1]}
+			}
+		case *parse.VariableNode:
+			if len(arg.Ident) < 2 {
+				return
+			}
+			name = arg.Ident[len(arg.Ident)-1]
+			receiver = &parse.VariableNode{NodeType: parse.NodeVariable, Pos: arg.Pos, Ident: arg.Ident[:len(arg.Ident)-1]}
+		default:
+			return
+		}
+		action := cmd.Args[0].String()
+		cmd.Args = []parse.Node{
+			parse.NewIdentifier(fieldFuncName).SetTree(nil).SetPos(n.Pos),
+			&parse.StringNode{NodeType: parse.NodeString, Pos: n.Pos, Quoted: fmt.Sprintf("%q", action), Text: action},
+			receiver,
+			&parse.StringNode{NodeType: parse.NodeString, Pos: n.Pos, Quoted: fmt.Sprintf("%q", name), Text: name},
+		}
+	case *parse.IfNode:
+		resolveFieldActions(n.List)
+		resolveFieldActions(n.ElseList)
+	case *parse.RangeNode:
+		resolveFieldActions(n.List)
+		resolveFieldActions(n.ElseList)
+	case *parse.WithNode:
+		resolveFieldActions(n.List)
+		resolveFieldActions(n.ElseList)
+	}
+}
@@ -0,0 +1,71 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender_MissingKey(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Name}}: {{.Customer.Phone}}, {{.Missing.City}}{{range .Items}} [{{$.Currency}} {{.SKU}}]{{end}}{{if .Flag}}!{{end}}</w:t></w:r></w:p>`)
+	data := map[string]interface{}{
+		"Name":     "Jane",
+		"Customer": map[string]string{"Email": "jane@example.com"},
+		"Items":    []map[string]interface{}{{"SKU": "P-1"}, {"Price": 2}},
+		"Currency": "EUR",
+	}
+
+	tests := []struct {
+		name     string
+		policy   MissingKeyPolicy
+		expected string
+	}{
+		{"default", MissingKeyPolicy{}, `Jane: ,  [EUR P-1] [EUR ]`},
+		{"empty", MissingKeyEmptyString, `Jane: ,  [EUR P-1] [EUR ]`},
+		{"preserve", MissingKeyPreserveTag, `Jane: {{.Customer.Phone}}, {{.Missing.City}} [EUR P-1] [EUR {{.SKU}}]`},
+		{"default value", MissingKeyDefaultValue("n/a"), `Jane: n/a, n/a [EUR P-1] [EUR n/a]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := Render(FromBytes(input), data, WithMissingKey(test.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, `>`+test.expected+`</w:t>`) {
+				t.Errorf("expected %s in %s", test.expected, document)
+			}
+		})
+	}
+
+	_, err := Render(FromBytes(input), data, WithMissingKey(MissingKeyError))
+	if err == nil || !strings.Contains(err.Error(), `map has no entry for key "Phone"`) {
+		t.Errorf("expected an error for the missing key, have %v", err)
+	}
+
+	// struct fields which do not exist are errors with every policy
+	type customer struct{ Name string }
+	structInput := buildTestDocx(t, `<w:p><w:r><w:t>{{.Name}} {{.Phone}}</w:t></w:r></w:p>`)
+	if _, err := Render(FromBytes(structInput), customer{"Jane"}, WithMissingKey(MissingKeyPreserveTag)); err == nil ||
+		!strings.Contains(err.Error(), "can't evaluate field Phone") {
+		t.Errorf("expected an error for the undeclared field, have %v", err)
+	}
+}
+
+func TestPool_MissingKey(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>[{{.Name}}]</w:t></w:r></w:p>`)
+	pool := NewPool(1, RenderOptions{MissingKey: MissingKeyPreserveTag})
+	output, err := pool.Render(t.Context(), input, map[string]interface{}{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, `[{{.Name}}]`) {
+		t.Errorf("expected the preserved tag in %s", document)
+	}
+	// the policy of a single render overrides the policy of the pool
+	output, err = pool.Render(t.Context(), input, map[string]interface{}{}, &RenderOptions{MissingKey: MissingKeyEmptyString})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, `[]`) {
+		t.Errorf("expected an empty value in %s", document)
+	}
+}
@@ -0,0 +1,97 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinStrategy defines how the values of a MultiValue are joined.
+type JoinStrategy int
+
+const (
+	// JoinComma joins the values with commas inside the run of the placeholder, e.g. 'red, green, blue'.
+	JoinComma JoinStrategy = iota
+	// JoinBullets inserts a bulleted list with an item for every value.
+	JoinBullets
+	// JoinParagraphs inserts a paragraph for every value, formatted like the paragraph of the placeholder.
+	JoinParagraphs
+)
+
+// MultiValue is a replacement value which inserts a slice of values into a single placeholder, joined by the
+// strategy, so the simplest repeated values need no template syntax. The list and paragraph strategies replace
+// the paragraph of the placeholder if the placeholder is its only text and split it otherwise.
+// Use Multi to create one.
+//
+// ProcessBytes only accepts strings, pass a PlaceholderMap with the value to Document.ReplaceAll, ProcessValues or
+// Render instead.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "colors":   docx.Multi([]string{"red", "green", "blue"}, docx.JoinComma),
+//	    "features": docx.Multi(product.Features, docx.JoinBullets),
+//	})
+//
+//	// or from bytes
+//	outputBytes, err := docx.ProcessValues(templateBytes, docx.PlaceholderMap{
+//	    "features": docx.Multi(product.Features, docx.JoinBullets),
+//	}, docx.RenderOptions{})
+type MultiValue struct {
+	Values []string
+	Join   JoinStrategy
+	// Separator replaces the separator of JoinComma, ', ' if empty.
+	Separator string
+}
+
+// Multi returns a value inserting all values joined by the strategy, see MultiValue.
+func Multi(values []string, join JoinStrategy) MultiValue {
+	return MultiValue{Values: values, Join: join}
+}
+
+// String returns the values joined by the separator.
+func (m MultiValue) String() string {
+	separator := m.Separator
+	if separator == "" {
+		separator = ", "
+	}
+	return strings.Join(m.Values, separator)
+}
+
+// inlineXml returns the runs of the joined values, or a run with the marker of the paragraphs which are inserted by
+// insertBlocks.
+func (m MultiValue) inlineXml(ctx *valueContext) (string, error) {
+	switch m.Join {
+	case JoinComma:
+		return ctx.valueXml(m.String())
+	case JoinBullets, JoinParagraphs:
+		return ctx.blockMarkerRun(m), nil
+	}
+	return "", fmt.Errorf("invalid join strategy %d", m.Join)
+}
+
+// blockXml returns a paragraph for every value.
+func (m MultiValue) blockXml(ctx *valueContext) (string, error) {
+	paragraphProperties := ctx.paragraphProperties
+	// a section break belongs to the paragraph of the placeholder only
+	if sections := sectionProperties([]byte(paragraphProperties)); len(sections) > 0 {
+		paragraphProperties = paragraphProperties[:sections[0][0]] + paragraphProperties[sections[0][1]:]
+	}
+	if m.Join == JoinBullets && len(m.Values) > 0 {
+		numId, err := ctx.doc.addList(false)
+		if err != nil {
+			return "", err
+		}
+		style := ""
+		if ctx.doc.styleExists("ListParagraph") {
+			style = `<w:pStyle w:val="ListParagraph"/>`
+		}
+		paragraphProperties = fmt.Sprintf(`<w:pPr>%s<w:numPr><w:ilvl w:val="0"/><w:numId w:val="%d"/></w:numPr></w:pPr>`, style, numId)
+	}
+
+	policy := ctx.doc.textPolicy
+	var out strings.Builder
+	for _, value := range m.Values {
+		out.WriteString("<w:p>" + paragraphProperties + policy.lineRuns(ctx, policy.apply(normalizeText(value))) + "</w:p>")
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,67 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Multi(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>Colors: {colors}</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{features}</w:t></w:r></w:p>`+
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{names}</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{empty}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"colors":   Multi([]string{"red", "green", "blue"}, JoinComma),
+		"features": Multi([]string{"Fast", "Safe & sound"}, JoinBullets),
+		"names":    Multi([]string{"Alice", "Bob"}, JoinParagraphs),
+		"empty":    Multi(nil, JoinBullets),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:t xml:space="preserve">red, green, blue</w:t>`,
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Fast</w:t></w:r></w:p>` +
+			`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Safe &amp; sound</w:t></w:r></w:p>`,
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">Alice</w:t></w:r></w:p>` +
+			`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">Bob</w:t></w:r></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "{empty}") {
+		t.Errorf("expected the empty placeholder to be removed: %s", document)
+	}
+	if numbering := readTestPart(t, buf.Bytes(), NumberingXml); !strings.Contains(numbering, `<w:numFmt w:val="bullet"/>`) {
+		t.Errorf("expected a bulleted list definition: %s", numbering)
+	}
+}
+
+func TestProcessValues_Multi(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Colors: {colors}</w:t></w:r></w:p><w:p><w:r><w:t>{features}</w:t></w:r></w:p>`)
+
+	output, err := ProcessValues(input, PlaceholderMap{
+		"colors":   Multi([]string{"red", "green"}, JoinComma),
+		"features": Multi([]string{"Fast", "Safe"}, JoinParagraphs),
+	}, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{`red, green</w:t>`, `<w:t xml:space="preserve">Fast</w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve">Safe</w:t>`} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
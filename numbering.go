@@ -0,0 +1,201 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// NumberingXml is the path of the numbering definitions part.
+	NumberingXml = "word/numbering.xml"
+
+	// RelationshipTypeNumbering is the relationship type of the numbering definitions part.
+	RelationshipTypeNumbering = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	// ContentTypeNumbering is the content type of the numbering definitions part.
+	ContentTypeNumbering = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+
+	// listLevels is the number of levels of a list, the maximum supported by Word.
+	listLevels = 9
+)
+
+var (
+	// abstractNumIdRegex matches abstract numbering definitions and captures their ID.
+	abstractNumIdRegex = regexp.MustCompile(`<w:abstractNum\s[^>]*?w:abstractNumId="([0-9]+)"`)
+	// numIdRegex matches numbering instances and captures their ID.
+	numIdRegex = regexp.MustCompile(`<w:num\s[^>]*?w:numId="([0-9]+)"`)
+	// numInstanceRegex matches the start of the first numbering instance or the elements which follow them.
+	numInstanceRegex = regexp.MustCompile(`<w:num[\s>]|<w:numIdMacAtCleanup[\s/>]|</w:numbering>`)
+	// numEndRegex matches the element which follows the numbering instances.
+	numEndRegex = regexp.MustCompile(`<w:numIdMacAtCleanup[\s/>]|</w:numbering>`)
+	// numIdReferenceRegex matches the numbering ID of numbering properties and captures the part in front of the ID,
+	// the ID and the closing quote.
+	numIdReferenceRegex = regexp.MustCompile(`(<w:numId\s+w:val=")([0-9]+)(")`)
+	// abstractNumReferenceRegex matches the abstract numbering definition of a numbering instance and captures the
+	// part in front of the ID, the ID and the closing quote.
+	abstractNumReferenceRegex = regexp.MustCompile(`(<w:abstractNumId\s+w:val=")([0-9]+)(")`)
+	// nsidRegex matches the list identifier of abstract numbering definitions.
+	nsidRegex = regexp.MustCompile(`<w:nsid\s[^>]*/>`)
+
+	// bulletLevelText and orderedLevelFormat are the bullets and number formats of the list levels, repeating every three levels.
+	bulletLevelText    = []string{"•", "◦", "▪"}
+	orderedLevelFormat = []string{"decimal", "lowerLetter", "lowerRoman"}
+
+	emptyNumbering = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:numbering>`)
+)
+
+// addList adds the numbering definition of a new bulleted or numbered list and returns the ID of the numbering
+// which is referenced by the paragraphs of the list (<w:numId>). Every list is numbered on its own, starting at 1.
+func (d *Document) addList(ordered bool) (int, error) {
+	data, err := d.numbering()
+	if err != nil {
+		return 0, err
+	}
+	abstractId, numId := nextNumberingIds(data)
+
+	var abstract strings.Builder
+	fmt.Fprintf(&abstract, `<w:abstractNum w:abstractNumId="%d"><w:multiLevelType w:val="hybridMultilevel"/>`, abstractId)
+	for level := 0; level < listLevels; level++ {
+		format, text := "bullet", bulletLevelText[level%len(bulletLevelText)]
+		if ordered {
+			format, text = orderedLevelFormat[level%len(orderedLevelFormat)], "%"+strconv.Itoa(level+1)+"."
+		}
+		fmt.Fprintf(&abstract, `<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:lvlText w:val="%s"/>`+
+			`<w:lvlJc w:val="left"/><w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`, level, format, text, 720*(level+1))
+	}
+	abstract.WriteString("</w:abstractNum>")
+	num := fmt.Sprintf(`<w:num w:numId="%d"><w:abstractNumId w:val="%d"/></w:num>`, numId, abstractId)
+
+	if err := d.addNumbering(data, abstract.String(), num); err != nil {
+		return 0, err
+	}
+	return numId, nil
+}
+
+// numbering returns the numbering definitions part, which is added to the document if it does not exist yet.
+func (d *Document) numbering() ([]byte, error) {
+	data, exists, err := d.part(NumberingXml)
+	if err != nil || exists {
+		return data, err
+	}
+	if _, err := d.addRelationship(DocumentXml, RelationshipTypeNumbering, "numbering.xml", false); err != nil {
+		return nil, err
+	}
+	if err := d.ensureContentTypeOverride(NumberingXml, ContentTypeNumbering); err != nil {
+		return nil, err
+	}
+	return emptyNumbering, nil
+}
+
+// nextNumberingIds returns the next free abstract numbering ID and numbering instance ID of the numbering part.
+func nextNumberingIds(data []byte) (abstractId, numId int) {
+	numId = 1
+	for _, match := range abstractNumIdRegex.FindAllSubmatch(data, -1) {
+		id, _ := strconv.Atoi(string(match[1]))
+		abstractId = max(abstractId, id+1)
+	}
+	for _, match := range numIdRegex.FindAllSubmatch(data, -1) {
+		id, _ := strconv.Atoi(string(match[1]))
+		numId = max(numId, id+1)
+	}
+	return abstractId, numId
+}
+
+// addNumbering inserts the abstract numbering definitions and the numbering instances into the numbering part data.
+func (d *Document) addNumbering(data []byte, abstracts, nums string) error {
+	// all abstract numbering definitions precede the numbering instances
+	changed := insertAt(data, numEndRegex.FindIndex(data), nums)
+	changed = insertAt(changed, numInstanceRegex.FindIndex(changed), abstracts)
+	if changed == nil {
+		return fmt.Errorf("invalid numbering part %s", NumberingXml)
+	}
+	return d.setPart(NumberingXml, changed)
+}
+
+// copyNumbering copies all numbering definitions of the numbering part of another document into the document and
+// returns the IDs of the copied numbering instances by their IDs inside the other document.
+func (d *Document) copyNumbering(numbering []byte) (map[string]string, error) {
+	numIds := make(map[string]string)
+	root := childElements(string(numbering))
+	if len(root) == 0 {
+		return numIds, nil
+	}
+	_, content, _ := splitElement(string(numbering)[root[0].start:root[0].end])
+	elements := childElements(content)
+	if !slices.ContainsFunc(elements, func(element childElement) bool { return element.name == "num" }) {
+		return numIds, nil
+	}
+
+	data, err := d.numbering()
+	if err != nil {
+		return nil, err
+	}
+	abstractId, numId := nextNumberingIds(data)
+	abstractIds := make(map[string]string)
+	var abstracts, nums strings.Builder
+	for _, element := range elements {
+		if element.name != "abstractNum" {
+			continue
+		}
+		definition := content[element.start:element.end]
+		match := abstractNumIdRegex.FindStringSubmatchIndex(definition)
+		if match == nil {
+			continue
+		}
+		abstractIds[definition[match[2]:match[3]]] = strconv.Itoa(abstractId)
+		// the list identifier is generated by Word, copies of a list must not share it
+		definition = definition[:match[2]] + strconv.Itoa(abstractId) + nsidRegex.ReplaceAllString(definition[match[3]:], "")
+		abstracts.WriteString(definition)
+		abstractId++
+	}
+	for _, element := range elements {
+		if element.name != "num" {
+			continue
+		}
+		instance := content[element.start:element.end]
+		match := numIdRegex.FindStringSubmatchIndex(instance)
+		if match == nil {
+			continue
+		}
+		numIds[instance[match[2]:match[3]]] = strconv.Itoa(numId)
+		instance = instance[:match[2]] + strconv.Itoa(numId) + instance[match[3]:]
+		instance = abstractNumReferenceRegex.ReplaceAllStringFunc(instance, func(reference string) string {
+			groups := abstractNumReferenceRegex.FindStringSubmatch(reference)
+			return groups[1] + abstractIds[groups[2]] + groups[3]
+		})
+		nums.WriteString(instance)
+		numId++
+	}
+	if err := d.addNumbering(data, abstracts.String(), nums.String()); err != nil {
+		return nil, err
+	}
+	return numIds, nil
+}
+
+// remapNumbering replaces the numbering IDs of the source inside the content by the IDs of the copied numbering.
+// References to numbering which was not copied are kept, the ID 0 removes the numbering of a paragraph.
+func (s *contentSource) remapNumbering(content string) string {
+	return numIdReferenceRegex.ReplaceAllStringFunc(content, func(reference string) string {
+		groups := numIdReferenceRegex.FindStringSubmatch(reference)
+		if id, copied := s.numIds[groups[2]]; copied {
+			return groups[1] + id + groups[3]
+		}
+		return reference
+	})
+}
+
+// insertAt returns a copy of data with the text inserted at the start of loc, or nil if loc or data is nil.
+func insertAt(data []byte, loc []int, text string) []byte {
+	if data == nil || loc == nil {
+		return nil
+	}
+	var changed bytes.Buffer
+	changed.Write(data[:loc[0]])
+	changed.WriteString(text)
+	changed.Write(data[loc[0]:])
+	return changed.Bytes()
+}
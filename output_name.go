@@ -0,0 +1,148 @@
+package docx
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// maxOutputNameLength is the maximum length of generated output names in bytes, including the extension.
+	// Most file systems limit names to 255 bytes, the rest is reserved for collision suffixes.
+	maxOutputNameLength = 200
+	// defaultOutputName is used if the rendered name is empty after sanitization.
+	defaultOutputName = "document"
+)
+
+// reservedOutputNames are names which cannot be used as file names on Windows, regardless of the extension.
+var reservedOutputNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// OutputNamer names the output documents of batch renders from their data using a Go text/template,
+// e.g. 'invoice-{{.InvoiceNumber}}.docx'. Every name is sanitized to a single, portable file name: path separators,
+// characters which are invalid on common file systems and control characters are replaced by '-'.
+// Names which were already returned by the namer get a numeric suffix in front of the extension, e.g.
+// 'invoice-42-2.docx', so no output overwrites another. Names are compared case-insensitively for case-insensitive
+// file systems. Besides the builtin functions, the template can use 'slug' which turns a value into lowercase ASCII
+// words separated by '-', e.g. {{slug .Customer}} turns 'Müller & Söhne' into 'muller-sohne'.
+// An OutputNamer is safe for concurrent use.
+//
+// Example:
+//
+//	namer, err := docx.NewOutputNamer("invoice-{{.InvoiceNumber}}-{{slug .Customer}}.docx")
+//	for _, invoice := range invoices {
+//	    name, err := namer.Name(invoice)
+//	    ...
+//	    doc.WriteToFile(filepath.Join(outputDir, name))
+//	}
+type OutputNamer struct {
+	template *template.Template
+
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+// NewOutputNamer parses the naming pattern and returns an OutputNamer.
+func NewOutputNamer(pattern string) (*OutputNamer, error) {
+	slug := func(value interface{}) string {
+		return Slug(fmt.Sprint(value))
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Funcs(template.FuncMap{"slug": slug}).Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output name pattern: %w", err)
+	}
+	return &OutputNamer{template: tmpl, used: make(map[string]bool)}, nil
+}
+
+// Name renders the name of the output document for the given data.
+// The returned name is unique among all names returned by this namer. Keys missing from map data are an error.
+func (n *OutputNamer) Name(data interface{}) (string, error) {
+	var rendered strings.Builder
+	if err := n.template.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("unable to render output name: %w", err)
+	}
+	name := SanitizeFileName(rendered.String())
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	extension := path.Ext(name)
+	base := strings.TrimSuffix(name, extension)
+	unique := name
+	for i := 2; n.used[strings.ToLower(unique)]; i++ {
+		unique = base + "-" + strconv.Itoa(i) + extension
+	}
+	n.used[strings.ToLower(unique)] = true
+	return unique, nil
+}
+
+// SanitizeFileName turns the text into a portable file name. Path separators, characters which are invalid
+// on common file systems and control characters are replaced by '-', leading and trailing dots and spaces are
+// removed and names reserved by Windows are prefixed with '_'. Overlong names are shortened, keeping the extension.
+// An empty name is replaced by 'document'.
+func SanitizeFileName(name string) string {
+	name = norm.NFC.String(strings.ToValidUTF8(name, "-"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\<>:"|?*`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return defaultOutputName
+	}
+
+	base := strings.TrimSuffix(name, path.Ext(name))
+	for _, reserved := range reservedOutputNames {
+		if strings.EqualFold(strings.TrimRight(base, " "), reserved) {
+			name = "_" + name
+			break
+		}
+	}
+
+	if len(name) > maxOutputNameLength {
+		extension := path.Ext(name)
+		if len(extension) > maxOutputNameLength/2 {
+			extension = ""
+		}
+		base := name[:maxOutputNameLength-len(extension)]
+		// do not cut a multi-byte character in half
+		for len(base) > 0 && !utf8.ValidString(base) {
+			base = base[:len(base)-1]
+		}
+		name = strings.TrimRight(base, ". ") + extension
+	}
+	return name
+}
+
+// Slug turns the text into lowercase ASCII words separated by '-', e.g. 'Müller & Söhne GmbH' into
+// 'muller-sohne-gmbh'. Accents are removed, all other characters which are not ASCII letters or digits separate words.
+func Slug(text string) string {
+	var slug strings.Builder
+	separate := false
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if separate && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(unicode.ToLower(r))
+			separate = false
+		default:
+			separate = true
+		}
+	}
+	return slug.String()
+}
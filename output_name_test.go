@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOutputNamer(t *testing.T) {
+	namer, err := NewOutputNamer("invoice-{{.Number}}-{{slug .Customer}}.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, data := range []map[string]interface{}{
+		{"Number": 42, "Customer": "Müller & Söhne GmbH"},
+		{"Number": 42, "Customer": "MÜLLER & SÖHNE GMBH"},
+		{"Number": "../../etc/passwd", "Customer": ""},
+	} {
+		name, err := namer.Name(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	expected := []string{"invoice-42-muller-sohne-gmbh.docx", "invoice-42-muller-sohne-gmbh-2.docx", "invoice-..-..-etc-passwd-.docx"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %s, have %s", expected[i], names[i])
+		}
+	}
+
+	if _, err := namer.Name(map[string]interface{}{"Number": 1}); err == nil {
+		t.Error("expected error for missing key")
+	}
+	if _, err := NewOutputNamer("invoice-{{.Number"); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestOutputNamer_Concurrent(t *testing.T) {
+	namer, err := NewOutputNamer("report.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name, err := namer.Name(nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[name] {
+				t.Errorf("name %s returned twice", name)
+			}
+			seen[name] = true
+		}()
+	}
+	wg.Wait()
+	if !seen["report.docx"] || !seen["report-50.docx"] {
+		t.Errorf("expected names report.docx to report-50.docx, have %v", seen)
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name, expected string
+	}{
+		{"a/b\\c:d*e?.docx", "a-b-c-d-e-.docx"},
+		{" ..hidden. ", "hidden"},
+		{"", "document"},
+		{"con.docx", "_con.docx"},
+		{"line\nbreak.docx", "line-break.docx"},
+		{strings.Repeat("ä", 150) + ".docx", strings.Repeat("ä", 97) + ".docx"},
+	}
+	for _, tt := range tests {
+		if have := SanitizeFileName(tt.name); have != tt.expected {
+			t.Errorf("SanitizeFileName(%q): expected %q, have %q", tt.name, tt.expected, have)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	if have := Slug("  Müller & Söhne GmbH, 2024 "); have != "muller-sohne-gmbh-2024" {
+		t.Errorf("unexpected slug %q", have)
+	}
+}
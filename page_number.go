@@ -0,0 +1,72 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FieldTypeNumPages is the type of fields showing the number of pages of the document.
+	FieldTypeNumPages = "NUMPAGES"
+	// FieldTypeSectionPages is the type of fields showing the number of pages of the current section.
+	FieldTypeSectionPages = "SECTIONPAGES"
+)
+
+var (
+	// pageNumberTokenRegex matches the tokens of page number formats and captures the name.
+	pageNumberTokenRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+	// pageNumberFields maps the tokens of page number formats to the types of their fields.
+	pageNumberFields = map[string]string{
+		"page":         FieldTypePage,
+		"pages":        FieldTypeNumPages,
+		"sectionpages": FieldTypeSectionPages,
+	}
+)
+
+// PageNumbers is a replacement value which inserts page number fields with text around them. Inside the format,
+// {page} is the number of the current page, {pages} the number of pages of the document and {sectionpages} the
+// number of pages of the current section. Word calculates the fields when it lays out the document, until then they
+// show 1. Page numbers are usually placed into headers or footers, see SetFooter, but the value can be used for
+// placeholders anywhere.
+//
+// Example:
+//
+//	doc.AddFooter(docx.HeaderDefault, docx.PageNumbers("Page {page} of {pages}"))
+//	doc.ReplaceAll(docx.PlaceholderMap{"pages": docx.PageNumbers("{pages} pages")})
+type PageNumbers string
+
+// String returns the text of the format as shown before Word calculates the fields.
+func (p PageNumbers) String() string {
+	return pageNumberTokenRegex.ReplaceAllStringFunc(string(p), func(token string) string {
+		if _, exists := pageNumberFields[token[1:len(token)-1]]; exists {
+			return "1"
+		}
+		return token
+	})
+}
+
+// inlineXml returns the runs of the text and a simple field for every token.
+func (p PageNumbers) inlineXml(ctx *valueContext) (string, error) {
+	policy := ctx.doc.textPolicy
+	format := string(p)
+	var out strings.Builder
+	text := func(value string) {
+		if value != "" {
+			out.WriteString(policy.lineRuns(ctx, policy.apply(normalizeText(value))))
+		}
+	}
+	last := 0
+	for _, match := range pageNumberTokenRegex.FindAllStringSubmatchIndex(format, -1) {
+		fieldType, exists := pageNumberFields[format[match[2]:match[3]]]
+		if !exists {
+			return "", fmt.Errorf("unknown token %s in page number format %q", format[match[0]:match[1]], format)
+		}
+		text(format[last:match[0]])
+		last = match[1]
+		fmt.Fprintf(&out, `<w:fldSimple w:instr=" %s \* MERGEFORMAT "><w:r>%s<w:t>1</w:t></w:r></w:fldSimple>`, fieldType, ctx.runProperties)
+	}
+	text(format[last:])
+	return out.String(), nil
+}
@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPageNumbers(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{count}</w:t></w:r></w:p><w:sectPr/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.AddFooter(HeaderDefault, PageNumbers("Page {page} of {pages}")); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"count": PageNumbers("{sectionpages} pages")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetHeader(HeaderDefault, PageNumbers("{pagess}")); err == nil {
+		t.Error("expected error for unknown token")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	footer := readTestPart(t, buf.Bytes(), "word/footer1.xml")
+	expected := `<w:r><w:t xml:space="preserve">Page </w:t></w:r><w:fldSimple w:instr=" PAGE \* MERGEFORMAT "><w:r><w:t>1</w:t></w:r></w:fldSimple>` +
+		`<w:r><w:t xml:space="preserve"> of </w:t></w:r><w:fldSimple w:instr=" NUMPAGES \* MERGEFORMAT "><w:r><w:t>1</w:t></w:r></w:fldSimple>`
+	if !strings.Contains(footer, expected) {
+		t.Errorf("expected %s in %s", expected, footer)
+	}
+
+	written, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := written.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, field := range fields {
+		types = append(types, field.Type()+"="+field.Result)
+	}
+	if strings.Join(types, ",") != "SECTIONPAGES=1,PAGE=1,NUMPAGES=1" {
+		t.Errorf("unexpected fields %v", types)
+	}
+	if text := PageNumbers("Page {page} of {pages} {x}").String(); text != "Page 1 of 1 {x}" {
+		t.Errorf("unexpected text %q", text)
+	}
+}
@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	// pageBreakXml is a page break inside of a run.
+	pageBreakXml = `<w:br w:type="page"/>`
+)
+
+// PageBreak is a replacement value which inserts a page break at the position of the placeholder
+// if Break is true. Otherwise the placeholder is removed.
+type PageBreak struct {
+	Break bool
+}
+
+// PageBreakIf returns a PageBreak value which only breaks the page if cond is true.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "break": docx.PageBreakIf(len(customers) > 1),
+//	})
+func PageBreakIf(cond bool) PageBreak {
+	return PageBreak{Break: cond}
+}
+
+// inlineXml returns a run containing the page break.
+func (p PageBreak) inlineXml(ctx *valueContext) (string, error) {
+	if !p.Break {
+		return "", nil
+	}
+	return fmt.Sprintf(`<w:r>%s%s</w:r>`, ctx.runProperties, pageBreakXml), nil
+}
+
+// pageBreakFuncs returns the template functions to control page breaks:
+//
+//	{{pagebreak}}                     -> always breaks the page
+//	{{pagebreakIf .NewPage}}          -> breaks the page if the argument is true
+//	{{if not (last $i .Items)}}...    -> last reports whether $i is the last index of a slice, map or array
+//
+// A break between the items of a loop, without a trailing break after the last item, can be written as:
+//
+//	{{range $i, $c := .Customers}}...{{pagebreakIf (not (last $i $.Customers))}}{{end}}
+func pageBreakFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"pagebreak": func() templateXml {
+			return pageBreakTemplateXml
+		},
+		"pagebreakIf": func(cond bool) templateXml {
+			if !cond {
+				return ""
+			}
+			return pageBreakTemplateXml
+		},
+		"last": func(index int, collection interface{}) (bool, error) {
+			value := reflect.ValueOf(collection)
+			switch value.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+				return index == value.Len()-1, nil
+			}
+			return false, fmt.Errorf("last: unsupported collection type %T", collection)
+		},
+	}
+}
+
+// pageBreakTemplateXml is written into the text of a run by the template functions, breaking the text-run.
+const pageBreakTemplateXml templateXml = `</w:t>` + pageBreakXml + `<w:t xml:space="preserve">`
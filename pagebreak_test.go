@@ -0,0 +1,47 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_PageBreaks(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>{{range $i, $c := .Customers}}{{$c}}{{pagebreakIf (not (last $i $.Customers))}}{{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{pagebreak}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Customers": []string{"Alpha", "Beta", "Gamma"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	if count := strings.Count(document, pageBreakXml); count != 3 {
+		t.Errorf("expected 3 page breaks (2 between customers, 1 explicit), have %d: %s", count, document)
+	}
+	if !strings.Contains(document, `Gamma</w:t></w:r>`) {
+		t.Errorf("expected no page break after the last customer: %s", document)
+	}
+}
+
+func TestDocument_ReplacePageBreak(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{a}|{b}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"a": PageBreakIf(true), "b": PageBreakIf(false)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if count := strings.Count(document, pageBreakXml); count != 1 {
+		t.Errorf("expected a single page break, have %d: %s", count, document)
+	}
+}
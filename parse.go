@@ -1,6 +1,7 @@
 package docx
 
 import (
+	"bytes"
 	"container/list"
 	"encoding/xml"
 	"errors"
@@ -15,23 +16,31 @@ const (
 	RunElementName = "r"
 	// TextElementName is the local name of the XML tag for text-runs (<w:t> and </w:t>)
 	TextElementName = "t"
+
+	// wordPrefix is the prefix of WordprocessingML elements.
+	wordPrefix = "w"
+	// drawingPrefix is the prefix of DrawingML elements, which are parsed inside text boxes and shapes.
+	drawingPrefix = "a"
 )
 
 var (
-	// RunOpenTagRegex matches all OpenTags for runs, including eventually set attributes
-	RunOpenTagRegex = regexp.MustCompile(`(<w:r).*>`)
+	// RunOpenTagRegex matches all OpenTags for runs, including eventually set attributes.
+	// Runs are WordprocessingML runs (<w:r>) or DrawingML runs (<a:r>) of text boxes and shapes.
+	RunOpenTagRegex = regexp.MustCompile(`(<[wa]:r).*>`)
 	// RunCloseTagRegex matches the close tag of runs
-	RunCloseTagRegex = regexp.MustCompile(`(</w:r>)`)
+	RunCloseTagRegex = regexp.MustCompile(`(</[wa]:r>)`)
 	// RunSingletonTagRegex matches a singleton run tag
-	RunSingletonTagRegex = regexp.MustCompile(`(<w:r/>)`)
+	RunSingletonTagRegex = regexp.MustCompile(`(<[wa]:r/>)`)
 	// TextOpenTagRegex matches all OpenTags for text-runs, including eventually set attributes
-	TextOpenTagRegex = regexp.MustCompile(`(<w:t).*>`)
+	TextOpenTagRegex = regexp.MustCompile(`(<[wa]:t).*>`)
 	// TextCloseTagRegex matches the close tag of text-runs
-	TextCloseTagRegex = regexp.MustCompile(`(</w:t>)`)
+	TextCloseTagRegex = regexp.MustCompile(`(</[wa]:t>)`)
 	// ErrTagsInvalid is returned if the parsing failed and the result cannot be used.
 	// Typically this means that one or more tag-offsets were not parsed correctly which
 	// would cause the document to become corrupted as soon as replacing starts.
 	ErrTagsInvalid = errors.New("one or more tags are invalid and will cause the XML to be corrupt")
+	// ErrMalformedXML is returned if a part of the document is no well-formed XML.
+	ErrMalformedXML = errors.New("malformed XML")
 )
 
 // RunParser can parse a list of Runs from a given byte slice.
@@ -112,12 +121,19 @@ func (parser *RunParser) findRuns() error {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("error getting token: %s", err)
+			return fmt.Errorf("%w, error getting token: %w", ErrMalformedXML, err)
 		}
 
 		switch elem := tok.(type) {
 		case xml.StartElement:
 			if elem.Name.Local == RunElementName {
+				// tagEndPos points to '>' of the tag
+				tagEndPos := docReader.Pos()
+				// tagStartPos points to '<' of the tag
+				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
+				if markupPrefix(parser.doc[tagStartPos:tagEndPos]) == "" {
+					break // e.g. a math run (<m:r>)
+				}
 
 				nestCount += 1
 				if nestCount > 1 {
@@ -125,11 +141,6 @@ func (parser *RunParser) findRuns() error {
 					tmpRun = NewEmptyRun()
 				}
 
-				// tagEndPos points to '>' of the tag
-				tagEndPos := docReader.Pos()
-				// tagStartPos points to '<' of the tag
-				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
-
 				tmpRun.OpenTag = Position{
 					Start: tagStartPos,
 					End:   tagEndPos,
@@ -145,6 +156,13 @@ func (parser *RunParser) findRuns() error {
 
 		case xml.EndElement:
 			if elem.Name.Local == RunElementName {
+				// tagEndPos points to '>' of the tag
+				tagEndPos := docReader.Pos()
+				// tagStartPos points to '<' of the tag
+				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
+				if markupPrefix(parser.doc[tagStartPos:tagEndPos]) == "" {
+					break
+				}
 
 				// if the run is a singleton tag, it was already identified by the xml.StartElement case
 				// in that case, the CloseTag is the same as the openTag and no further work needs to be done
@@ -155,11 +173,6 @@ func (parser *RunParser) findRuns() error {
 					break
 				}
 
-				// tagEndPos points to '>' of the tag
-				tagEndPos := docReader.Pos()
-				// tagStartPos points to '<' of the tag
-				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
-
 				// add CloseTag and finish the run
 				tmpRun.CloseTag = Position{
 					Start: tagStartPos,
@@ -185,7 +198,8 @@ func (parser *RunParser) findTextRuns() error {
 	docReader := NewReader(string(parser.doc))
 	decoder := xml.NewDecoder(docReader)
 
-	// based on the current position, find out in which run we're at
+	// based on the current position, find out in which run we're at.
+	// Nested runs are finished before the runs containing them, so the innermost run is found.
 	inRun := func(pos int64) *Run {
 		for _, run := range parser.runs {
 			if run.OpenTag.Start < pos && pos < run.CloseTag.End {
@@ -194,6 +208,22 @@ func (parser *RunParser) findTextRuns() error {
 		}
 		return nil
 	}
+	// textRun returns the run of the text tag, the run must use the same markup as the text.
+	// DrawingML texts outside of runs (e.g. fields <a:fld>) are skipped, nil is returned for them.
+	textRun := func(tagStartPos, tagEndPos int64) (*Run, error) {
+		prefix := markupPrefix(parser.doc[tagStartPos:tagEndPos])
+		if prefix == "" {
+			return nil, nil // e.g. a math text (<m:t>)
+		}
+		currentRun := inRun(tagEndPos)
+		if currentRun != nil && markupPrefix(parser.doc[currentRun.OpenTag.Start:currentRun.OpenTag.End]) == prefix {
+			return currentRun, nil
+		}
+		if prefix == drawingPrefix {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to find currentRun for text element")
+	}
 
 	for {
 		tok, err := decoder.Token()
@@ -201,7 +231,7 @@ func (parser *RunParser) findTextRuns() error {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("error getting token: %s", err)
+			return fmt.Errorf("%w, error getting token: %w", ErrMalformedXML, err)
 		}
 
 		switch elem := tok.(type) {
@@ -213,9 +243,12 @@ func (parser *RunParser) findTextRuns() error {
 				// tagStartPos points to '<' of the tag
 				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
 
-				currentRun := inRun(docReader.Pos())
+				currentRun, err := textRun(tagStartPos, tagEndPos)
+				if err != nil {
+					return err
+				}
 				if currentRun == nil {
-					return fmt.Errorf("unable to find currentRun for text start-element")
+					break
 				}
 				currentRun.HasText = true
 				currentRun.Text.OpenTag = Position{
@@ -232,9 +265,12 @@ func (parser *RunParser) findTextRuns() error {
 				// tagStartPos points to '<' of the tag. -1 is required since Pos() points after the '>'
 				tagStartPos := parser.findOpenBracketPos(tagEndPos - 1)
 
-				currentRun := inRun(docReader.Pos())
+				currentRun, err := textRun(tagStartPos, tagEndPos)
+				if err != nil {
+					return err
+				}
 				if currentRun == nil {
-					return fmt.Errorf("unable to find currentRun for text end-element")
+					break
 				}
 				currentRun.Text.CloseTag = Position{
 					Start: tagStartPos,
@@ -247,6 +283,20 @@ func (parser *RunParser) findTextRuns() error {
 	return nil
 }
 
+// markupPrefix returns the prefix of the open or close tag if it belongs to WordprocessingML ('w')
+// or DrawingML ('a'). An empty string is returned for all other markup, which is not parsed.
+func markupPrefix(tag []byte) string {
+	tag = bytes.TrimPrefix(bytes.TrimPrefix(tag, []byte("<")), []byte("/"))
+	switch {
+	case bytes.HasPrefix(tag, []byte(wordPrefix+":")):
+		return wordPrefix
+	case bytes.HasPrefix(tag, []byte(drawingPrefix+":")):
+		return drawingPrefix
+	default:
+		return ""
+	}
+}
+
 // findOpenBracketPos searches the matching '<' for a close bracket ('>') given it's position.
 func (parser *RunParser) findOpenBracketPos(endBracketPos int64) int64 {
 	var found bool
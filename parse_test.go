@@ -82,6 +82,24 @@ func TestRun_WithText(t *testing.T) {
 	}
 }
 
+func TestRunParser_DrawingML(t *testing.T) {
+	docBytes := []byte(`<w:p><w:r><w:drawing><wps:txbx><w:txbxContent><w:p><w:r><w:t>{title}</w:t></w:r></w:p></w:txbxContent></wps:txbx>` +
+		`<wps:txBody><a:p><a:r><a:rPr lang="en-US"/><a:t>{date}</a:t></a:r><a:fld type="slidenum"><a:t>1</a:t></a:fld></a:p></wps:txBody></w:drawing></w:r>` +
+		`<m:oMath><m:r><m:t>x</m:t></m:r></m:oMath></w:p>`)
+
+	sut := NewRunParser(docBytes)
+	if err := sut.Execute(); err != nil {
+		t.Fatalf("parser.Execute failed: %s", err)
+	}
+	var texts []string
+	for _, run := range sut.Runs().WithText() {
+		texts = append(texts, run.GetText(docBytes))
+	}
+	if len(sut.Runs()) != 3 || len(texts) != 2 || texts[0] != "{title}" || texts[1] != "{date}" {
+		t.Errorf("expected the word run, the DrawingML run and the drawing run, have %d runs with texts %v", len(sut.Runs()), texts)
+	}
+}
+
 func readFile(t testing.TB, path string) []byte {
 	f, err := os.Open(path)
 	if err != nil {
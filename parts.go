@@ -0,0 +1,257 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContentTypesXml is the path of the part which declares the content types of all other parts.
+	ContentTypesXml = "[Content_Types].xml"
+
+	// RelationshipTypeImage is the relationship type of images.
+	RelationshipTypeImage = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	// RelationshipTypeHyperlink is the relationship type of hyperlinks, their targets are external.
+	RelationshipTypeHyperlink = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+)
+
+var (
+	// RelationshipIdRegex matches the Id attribute of a relationship and captures the number of 'rIdN' ids.
+	RelationshipIdRegex = regexp.MustCompile(`Id="rId([0-9]+)"`)
+	// RelationshipRegex matches a single relationship and captures its attributes.
+	RelationshipRegex = regexp.MustCompile(`<Relationship\s([^>]*?)/?>`)
+	// DocPrIdRegex matches the id of all drawing object properties (<wp:docPr id="1" .../>) and captures the id.
+	DocPrIdRegex = regexp.MustCompile(`<wp:docPr[^>]*?\sid="([0-9]+)"`)
+
+	emptyRelationships = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`)
+)
+
+// part returns the content of the part with the given name.
+// Parts which are not parsed by the document (e.g. relationships) are loaded from the archive on demand.
+// The second return value is false if the part does not exist.
+func (d *Document) part(name string) ([]byte, bool, error) {
+	if data, exists := d.files[name]; exists {
+		return data, true, nil
+	}
+	if data, exists := d.parts[name]; exists {
+		return data, true, nil
+	}
+	for _, file := range d.zipFile.File {
+		if file.Name != name {
+			continue
+		}
+		data, err := d.limits.readZipFile(file)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := d.limits.checkXML(name, data); err != nil {
+			return nil, false, err
+		}
+		d.parts[name] = data
+		return data, true, nil
+	}
+	return nil, false, nil
+}
+
+// setPart sets the content of the part with the given name.
+// If the part does not exist yet, it is added to the archive when the document is written.
+func (d *Document) setPart(name string, data []byte) error {
+	if _, exists := d.files[name]; exists {
+		return d.SetFile(name, data)
+	}
+	if _, exists, err := d.part(name); err != nil {
+		return err
+	} else if !exists {
+		d.newParts = append(d.newParts, name)
+	}
+	d.parts[name] = data
+	d.modified[name] = true
+	return nil
+}
+
+// addFile adds a new XML file (e.g. a header) which does not exist in the original archive.
+// The file is parsed like the files of the archive, so its placeholders are replaced as well.
+func (d *Document) addFile(name string, data []byte) error {
+	if d.hasPart(name) {
+		return fmt.Errorf("part %s already exists", name)
+	}
+	if err := d.limits.checkXML(name, data); err != nil {
+		return err
+	}
+	d.files[name] = data
+	d.newParts = append(d.newParts, name)
+	d.modified[name] = true
+	return d.parseFile(name)
+}
+
+// hasPart returns true if a part with the given name exists, either in the original archive or added later on.
+func (d *Document) hasPart(name string) bool {
+	if d.streamedParts[name] {
+		return true
+	}
+	_, exists, err := d.part(name)
+	return err == nil && exists
+}
+
+// relationshipsPart returns the name of the relationships part of the given part,
+// e.g. 'word/_rels/document.xml.rels' for 'word/document.xml'.
+func relationshipsPart(name string) string {
+	dir, file := path.Split(name)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// addRelationship adds a new relationship to the relationships part of source and returns its ID.
+// The target is relative to the directory of the source part, unless the relationship is external.
+// IDs are tracked per relationships part so that they stay unique, even if thousands of relationships are added.
+func (d *Document) addRelationship(source, relType, target string, external bool) (string, error) {
+	relsName := relationshipsPart(source)
+	rels, exists, err := d.part(relsName)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		rels = emptyRelationships
+	}
+
+	if _, known := d.relIds[relsName]; !known {
+		for _, match := range RelationshipIdRegex.FindAllSubmatch(rels, -1) {
+			if id, err := strconv.Atoi(string(match[1])); err == nil && id > d.relIds[relsName] {
+				d.relIds[relsName] = id
+			}
+		}
+	}
+	d.relIds[relsName]++
+	id := fmt.Sprintf("rId%d", d.relIds[relsName])
+
+	targetMode := ""
+	if external {
+		targetMode = ` TargetMode="External"`
+	}
+	relationship := fmt.Sprintf(`<Relationship Id="%s" Type="%s" Target="%s"%s/>`, id, relType, html.EscapeString(target), targetMode)
+
+	closing := []byte("</Relationships>")
+	pos := bytes.LastIndex(rels, closing)
+	if pos < 0 {
+		return "", fmt.Errorf("invalid relationships part %s", relsName)
+	}
+	changed := append([]byte{}, rels[:pos]...)
+	changed = append(changed, relationship...)
+	changed = append(changed, rels[pos:]...)
+
+	if err := d.setPart(relsName, changed); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// relationship is a single parsed relationship of a relationships part.
+type relationship struct {
+	id, relType, target string
+	external            bool
+}
+
+// parseRelationships returns all relationships of the relationships part in document order.
+func parseRelationships(rels []byte) []relationship {
+	var relationships []relationship
+	for _, match := range RelationshipRegex.FindAllSubmatch(rels, -1) {
+		var rel relationship
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(string(match[1]), -1) {
+			switch attribute[1] {
+			case "Id":
+				rel.id = attribute[2]
+			case "Target":
+				rel.target = html.UnescapeString(attribute[2])
+			case "Type":
+				rel.relType = attribute[2]
+			case "TargetMode":
+				rel.external = attribute[2] == "External"
+			}
+		}
+		relationships = append(relationships, rel)
+	}
+	return relationships
+}
+
+// partName returns the name of the part which is the target of the relationship of the given source part.
+func (r relationship) partName(source string) string {
+	if strings.HasPrefix(r.target, "/") {
+		return strings.TrimPrefix(r.target, "/")
+	}
+	return path.Join(path.Dir(source), r.target)
+}
+
+// relationshipTarget returns the name of the part which is related to source by the first relationship of the given
+// type. The second return value is false if there is no such relationship or the target is external.
+func (d *Document) relationshipTarget(source, relType string) (string, bool, error) {
+	rels, exists, err := d.part(relationshipsPart(source))
+	if err != nil || !exists {
+		return "", false, err
+	}
+	for _, rel := range parseRelationships(rels) {
+		if rel.relType == relType && !rel.external {
+			return rel.partName(source), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// ensureContentTypeDefault registers the content type for all parts with the given file extension,
+// unless the extension is already registered.
+func (d *Document) ensureContentTypeDefault(extension, contentType string) error {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	entry := fmt.Sprintf(`<Default Extension="%s" ContentType="%s"/>`, extension, contentType)
+	return d.addContentType(fmt.Sprintf(`Extension="%s"`, extension), entry)
+}
+
+// ensureContentTypeOverride registers the content type for the part with the given name, unless already registered.
+func (d *Document) ensureContentTypeOverride(name, contentType string) error {
+	partName := "/" + strings.TrimPrefix(name, "/")
+	entry := fmt.Sprintf(`<Override PartName="%s" ContentType="%s"/>`, partName, contentType)
+	return d.addContentType(fmt.Sprintf(`PartName="%s"`, partName), entry)
+}
+
+// addContentType adds the entry to the content types part if the marker is not yet present.
+func (d *Document) addContentType(marker, entry string) error {
+	types, exists, err := d.part(ContentTypesXml)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w, %s is missing", ErrInvalidArchive, ContentTypesXml)
+	}
+	if bytes.Contains(bytes.ToLower(types), bytes.ToLower([]byte(marker))) {
+		return nil
+	}
+
+	closing := []byte("</Types>")
+	pos := bytes.LastIndex(types, closing)
+	if pos < 0 {
+		return fmt.Errorf("invalid content types part %s", ContentTypesXml)
+	}
+	changed := append([]byte{}, types[:pos]...)
+	changed = append(changed, entry...)
+	changed = append(changed, types[pos:]...)
+	return d.setPart(ContentTypesXml, changed)
+}
+
+// nextDocPrId returns an ID for a new drawing object which is unique inside the whole document.
+// Word considers documents with duplicate drawing object IDs as corrupt.
+func (d *Document) nextDocPrId() int {
+	if d.docPrId == 0 {
+		for _, name := range d.xmlFiles() {
+			for _, match := range DocPrIdRegex.FindAllSubmatch(d.files[name], -1) {
+				if id, err := strconv.Atoi(string(match[1])); err == nil && id > d.docPrId {
+					d.docPrId = id
+				}
+			}
+		}
+	}
+	d.docPrId++
+	return d.docPrId
+}
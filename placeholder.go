@@ -1,6 +1,7 @@
 package docx
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -15,18 +16,23 @@ var (
 )
 
 // ChangeOpenCloseDelimiter changes the global delimiters used for placeholders.
-// Call this before opening a document to use custom delimiters.
+// Call this before opening a document to use custom delimiters, it is not safe while documents are processed.
 // The default delimiters are '{' and '}'.
 func ChangeOpenCloseDelimiter(openDelimiter, closeDelimiter rune) {
 	OpenDelimiter = openDelimiter
 	CloseDelimiter = closeDelimiter
+	OpenDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(openDelimiter)))
+	CloseDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(closeDelimiter)))
 }
 
+// ErrUnbalancedDelimiter is returned if a placeholder is closed which was never opened, e.g. '{foo}}{bar}'.
+var ErrUnbalancedDelimiter = errors.New("unbalanced placeholder delimiter")
+
 var (
 	// OpenDelimiterRegex is used to quickly match the opening delimiter and find it'str positions.
-	OpenDelimiterRegex = regexp.MustCompile(string(OpenDelimiter))
+	OpenDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(OpenDelimiter)))
 	// CloseDelimiterRegex is used to quickly match the closing delimiter and find it'str positions.
-	CloseDelimiterRegex = regexp.MustCompile(string(CloseDelimiter))
+	CloseDelimiterRegex = regexp.MustCompile(regexp.QuoteMeta(string(CloseDelimiter)))
 )
 
 // PlaceholderMap is the type used to map the placeholder keys (without delimiters) to the replacement values
@@ -156,7 +162,7 @@ func ParsePlaceholders(runs DocumentRuns, docBytes []byte) (placeholders []*Plac
 
 				// we MUST be having an unclosedPlaceholder or the user made a typo like double-closing ('{foo}}{bar')
 				if !hasOpenPlaceholder {
-					return nil, fmt.Errorf("unexpected %c in run %d \"%s\"), missing preceeding %c", CloseDelimiter, run.ID, run.GetText(docBytes), OpenDelimiter)
+					return nil, fmt.Errorf("%w: unexpected %c in run %d \"%s\"), missing preceeding %c", ErrUnbalancedDelimiter, CloseDelimiter, run.ID, run.GetText(docBytes), OpenDelimiter)
 				}
 
 				// everything up to firstClosePos belongs to the currently open placeholder
@@ -1,9 +1,12 @@
 package docx
 
-import "fmt"
+import (
+	"fmt"
+	"sync/atomic"
+)
 
 var (
-	fragmentId = 0 // global fragment id counter, incremented on NewPlaceholderFragment
+	fragmentId atomic.Int64 // global fragment id counter, incremented on NewPlaceholderFragment
 )
 
 // PlaceholderFragment is a part of a placeholder within the document.xml
@@ -112,13 +115,12 @@ func (p PlaceholderFragment) Valid() bool {
 		p.Position.Valid()
 }
 
-// NewFragmentID returns the next Fragment.ID
+// NewFragmentID returns the next Fragment.ID, it is safe for concurrent use.
 func NewFragmentID() int {
-	fragmentId += 1
-	return fragmentId
+	return int(fragmentId.Add(1))
 }
 
 // ResetFragmentIdCounter will reset the fragmentId counter to 0
 func ResetFragmentIdCounter() {
-	fragmentId = 0
+	fragmentId.Store(0)
 }
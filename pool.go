@@ -0,0 +1,257 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// RenderOptions configure the rendering of a template by a Pool. The options of the pool apply to every render,
+// each call of Pool.Render may override them.
+type RenderOptions struct {
+	// Timeout limits the duration of a render, including the time spent waiting for a free worker.
+	// The deadline of the context given to Pool.Render applies as well. Zero means no timeout.
+	Timeout time.Duration
+	// Locale is the BCP 47 language tag of the rendered document, e.g. 'de-DE'. It is set as the default language
+	// of the document text (see Document.SetLanguage), returned by the template function {{locale}} and used by the
+	// template functions which format numbers, amounts and dates.
+	Locale string
+	// TemplateLocale uses the default language of the template document as locale of the template functions if
+	// Locale is empty, so a template written in German formats like 'de-DE'. Nil keeps the setting of the defaults
+	// or the pool like StrictValues.
+	TemplateLocale *bool
+	// Funcs are made available to the template in addition to the builtin functions, see ProcessTemplateDocxWithFuncs.
+	Funcs template.FuncMap
+	// Cleanup removes the empty paragraphs and table rows left behind by the template, see Document.Cleanup.
+	Cleanup CleanupOptions
+	// Conformance is the conformance class of the rendered document, see ConvertToStrict.
+	Conformance Conformance
+	// OutputLimits restrict the complexity of the rendered document, see CheckComplexity.
+	OutputLimits OutputLimits
+	// StrictValues only accepts strings as replacement values of ProcessValues instead of formatting numbers,
+	// dates and booleans according to the locale. Nil keeps the setting of the defaults or the pool, so options
+	// can turn it off again with Bool(false).
+	StrictValues *bool
+	// MissingKey controls what templates write for fields which are missing in the data, see MissingKeyPolicy.
+	MissingKey MissingKeyPolicy
+	// Debug logs the duration and the result of the render to the logger of the pool. Nil keeps the setting of
+	// the defaults or the pool like StrictValues.
+	Debug *bool
+}
+
+// Bool returns a pointer to the value, for the optional flags of RenderOptions.
+func Bool(value bool) *bool {
+	return &value
+}
+
+// enabled returns true if the optional flag is set to true.
+func enabled(flag *bool) bool {
+	return flag != nil && *flag
+}
+
+// merge returns the options with all options which are set in override replaced.
+// Functions are combined, functions of override replace functions with the same name.
+func (o RenderOptions) merge(override *RenderOptions) RenderOptions {
+	if override == nil {
+		return o
+	}
+	merged := o
+	if override.Timeout > 0 {
+		merged.Timeout = override.Timeout
+	}
+	if override.Locale != "" {
+		merged.Locale = override.Locale
+	}
+	if len(override.Funcs) > 0 {
+		merged.Funcs = make(template.FuncMap, len(o.Funcs)+len(override.Funcs))
+		for name, fn := range o.Funcs {
+			merged.Funcs[name] = fn
+		}
+		for name, fn := range override.Funcs {
+			merged.Funcs[name] = fn
+		}
+	}
+	if override.Cleanup != (CleanupOptions{}) {
+		merged.Cleanup = override.Cleanup
+	}
+	if override.Conformance != ConformanceTransitional {
+		merged.Conformance = override.Conformance
+	}
+	if override.OutputLimits != (OutputLimits{}) {
+		merged.OutputLimits = override.OutputLimits
+	}
+	if override.MissingKey.mode != missingKeyUnset {
+		merged.MissingKey = override.MissingKey
+	}
+	if override.TemplateLocale != nil {
+		merged.TemplateLocale = override.TemplateLocale
+	}
+	if override.StrictValues != nil {
+		merged.StrictValues = override.StrictValues
+	}
+	if override.Debug != nil {
+		merged.Debug = override.Debug
+	}
+	return merged
+}
+
+// Pool renders templates (see ProcessTemplateDocx) concurrently with a limited number of workers,
+// so a service can share one pool across all requests without overloading the machine.
+// The options of the pool are the defaults of every render, each render may supply its own context and options.
+// The options of the pool override the package-level defaults, see SetDefaults.
+// A Pool is safe for concurrent use.
+//
+// Example:
+//
+//	pool := docx.NewPool(4, docx.RenderOptions{Timeout: 10 * time.Second, Locale: "en-US"})
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    output, err := pool.Render(r.Context(), templateBytes, data, &docx.RenderOptions{Locale: "de-DE"})
+//	    ...
+//	}
+type Pool struct {
+	workers chan struct{}
+	options RenderOptions
+	// Logger receives the records of renders with RenderOptions.Debug, slog.Default() is used if it is nil.
+	Logger *slog.Logger
+}
+
+// NewPool returns a pool which renders at most the given number of documents at once.
+// If workers is less than one, runtime.GOMAXPROCS(0) workers are used.
+func NewPool(workers int, options RenderOptions) *Pool {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{workers: make(chan struct{}, workers), options: options}
+}
+
+// Render renders the template given by input with data once a worker is free. The options override the options
+// of the pool for this render only and may be nil.
+// If the context is done or the timeout expires, Render returns the error of the context. A render which was
+// already running finishes in the background and occupies its worker until then.
+func (p *Pool) Render(ctx context.Context, input []byte, data interface{}, options *RenderOptions) ([]byte, error) {
+	return p.run(ctx, options, func(opts RenderOptions) ([]byte, error) {
+		return renderWithOptions(input, data, opts)
+	})
+}
+
+// run runs the render function with the merged options once a worker is free, see Render.
+func (p *Pool) run(ctx context.Context, options *RenderOptions, render func(RenderOptions) ([]byte, error)) ([]byte, error) {
+	opts := defaultRenderOptions().merge(&p.options).merge(options)
+	if opts.Locale != "" {
+		if _, err := language.Parse(opts.Locale); err != nil {
+			return nil, fmt.Errorf("invalid locale %s: %w", opts.Locale, err)
+		}
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	select {
+	case p.workers <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no render worker available: %w", ctx.Err())
+	}
+	queued := time.Since(start)
+
+	type result struct {
+		output []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-p.workers }()
+		output, err := render(opts)
+		done <- result{output, err}
+	}()
+
+	var r result
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		r.err = fmt.Errorf("render aborted: %w", ctx.Err())
+	}
+	if enabled(opts.Debug) {
+		p.logger().LogAttrs(ctx, slog.LevelDebug, "docx render",
+			slog.Duration("queued", queued),
+			slog.Duration("duration", time.Since(start)-queued),
+			slog.String("locale", opts.Locale),
+			slog.Int("size", len(r.output)),
+			slog.Any("error", r.err),
+		)
+	}
+	return r.output, r.err
+}
+
+// logger returns the logger of the pool.
+func (p *Pool) logger() *slog.Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return slog.Default()
+}
+
+// renderWithOptions renders the template given by input with data using the options.
+func renderWithOptions(input []byte, data interface{}, options RenderOptions) ([]byte, error) {
+	engine := newTemplateEngine()
+	if err := engine.addFuncs(options.Funcs); err != nil {
+		return nil, err
+	}
+	engine.locale = options.Locale
+	if engine.locale == "" && enabled(options.TemplateLocale) {
+		locale, err := templateLanguage(input)
+		if err != nil {
+			return nil, err
+		}
+		engine.locale = locale
+	}
+	engine.missingKey = options.MissingKey
+	output, err := engine.render(input, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Locale != "" || options.Cleanup != (CleanupOptions{}) {
+		if output, err = finishDocument(output, options); err != nil {
+			return nil, err
+		}
+	}
+	if err := CheckComplexity(output, options.OutputLimits); err != nil {
+		return nil, err
+	}
+	if options.Conformance == ConformanceStrict {
+		return ConvertToStrict(output)
+	}
+	return output, nil
+}
+
+// finishDocument applies the cleanup and the locale of the options to the rendered document.
+func finishDocument(output []byte, options RenderOptions) ([]byte, error) {
+	doc, err := openRenderedDocument(output)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if err := doc.Cleanup(options.Cleanup); err != nil {
+		return nil, err
+	}
+	if options.Locale != "" {
+		if err := doc.SetLanguage(options.Locale); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestPool_Render(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{greet .Name}} ({{locale}})</w:t></w:r></w:p>`)
+	pool := NewPool(2, RenderOptions{
+		Locale: "en-US",
+		Funcs:  template.FuncMap{"greet": func(name string) string { return "Hello " + name }},
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var options *RenderOptions
+			expected := fmt.Sprintf("Hello %d (en-US)", i)
+			if i%2 == 1 {
+				options = &RenderOptions{
+					Locale: "de-DE",
+					Funcs:  template.FuncMap{"greet": func(name string) string { return "Hallo " + name }},
+				}
+				expected = fmt.Sprintf("Hallo %d (de-DE)", i)
+			}
+			output, err := pool.Render(context.Background(), input, map[string]string{"Name": fmt.Sprint(i)}, options)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, expected) {
+				errs <- fmt.Errorf("expected %s in document: %s", expected, document)
+			}
+			if styles := readTestPart(t, output, StylesXml); !strings.Contains(styles, expected[len(expected)-6:len(expected)-1]) {
+				errs <- fmt.Errorf("expected language of %s in styles: %s", expected, styles)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPool_Render_Timeout(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{slow}}</w:t></w:r></w:p>`)
+	release := make(chan struct{})
+	defer close(release)
+	var logs bytes.Buffer
+	pool := NewPool(1, RenderOptions{Funcs: template.FuncMap{"slow": func() string { <-release; return "" }}})
+	pool.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := pool.Render(context.Background(), input, nil, &RenderOptions{Timeout: 10 * time.Millisecond, Debug: Bool(true)})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, have %v", err)
+	}
+	if !strings.Contains(logs.String(), "docx render") || !strings.Contains(logs.String(), "render aborted") {
+		t.Errorf("expected debug record of the aborted render, have %s", logs.String())
+	}
+
+	// the only worker is still busy with the aborted render
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pool.Render(ctx, input, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled, have %v", err)
+	}
+
+	if _, err := pool.Render(context.Background(), input, nil, &RenderOptions{Locale: "-"}); err == nil {
+		t.Error("expected error for invalid locale")
+	}
+}
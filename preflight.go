@@ -0,0 +1,286 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PreflightIssueKind is the kind of an issue found by Preflight.
+type PreflightIssueKind string
+
+const (
+	// PreflightFontSubstitution is a font which is neither embedded nor commonly available, so text using it likely
+	// renders with a substitute font on the computers of the recipients.
+	PreflightFontSubstitution PreflightIssueKind = "font-substitution"
+	// PreflightFontNotEmbedded is a font which is not embedded into a document for printing.
+	PreflightFontNotEmbedded PreflightIssueKind = "font-not-embedded"
+	// PreflightPageSize is a page size which does not match the trim size plus the bleed.
+	PreflightPageSize PreflightIssueKind = "page-size"
+	// PreflightMargin is a page margin which leaves less space than the bleed plus the safety margin, so text may be
+	// trimmed.
+	PreflightMargin PreflightIssueKind = "margin"
+	// PreflightImageResolution is an image whose resolution at its printed size is below the minimum.
+	PreflightImageResolution PreflightIssueKind = "image-resolution"
+	// PreflightColorSpace is an image which is not stored in a color space for printing, e.g. RGB instead of CMYK.
+	PreflightColorSpace PreflightIssueKind = "color-space"
+)
+
+// PreflightSeverity is the severity of an issue found by Preflight.
+type PreflightSeverity string
+
+const (
+	// PreflightSeverityError is an issue which fails the preflight, see PreflightReport.Passed.
+	PreflightSeverityError PreflightSeverity = "error"
+	// PreflightSeverityWarning is an issue which should be reviewed but does not fail the preflight.
+	PreflightSeverityWarning PreflightSeverity = "warning"
+)
+
+var (
+	// runFontsRegex matches the font attributes of run properties and captures the attribute name and the value.
+	runFontsRegex = regexp.MustCompile(`w:(ascii|hAnsi|eastAsia|cs|asciiTheme|hAnsiTheme|eastAsiaTheme|cstheme)="([^"]*)"`)
+	// rFontsRegex matches the font element of run properties.
+	rFontsRegex = regexp.MustCompile(`<w:rFonts\s[^>]*>`)
+	// themeFontsRegex matches the major or minor fonts of a theme and captures the kind and the content.
+	themeFontsRegex = regexp.MustCompile(`(?s)<a:(major|minor)Font>(.*?)</a:(?:major|minor)Font>`)
+	// themeTypefaceRegex matches the fonts of a theme font scheme by script and captures the script and the typeface.
+	themeTypefaceRegex = regexp.MustCompile(`<a:(latin|ea|cs)\s[^>]*?typeface="([^"]*)"`)
+	// fontAltNameRegex matches the alternative name of a font of the font table and captures the name.
+	fontAltNameRegex = regexp.MustCompile(`<w:altName\s+w:val="([^"]*)"`)
+	// fontEmbedRegex matches a reference to an embedded font file of a font of the font table.
+	fontEmbedRegex = regexp.MustCompile(`<w:embed(?:Regular|Bold|Italic|BoldItalic)\s`)
+)
+
+// DefaultAvailableFonts are the font families which are installed on most computers with Windows, macOS or
+// Microsoft Office, they are used by Preflight if PreflightOptions.AvailableFonts is empty.
+var DefaultAvailableFonts = []string{
+	"Aptos", "Aptos Display", "Arial", "Arial Black", "Arial Narrow", "Book Antiqua", "Bookman Old Style",
+	"Calibri", "Calibri Light", "Cambria", "Cambria Math", "Candara", "Century Gothic", "Comic Sans MS", "Consolas",
+	"Constantia", "Corbel", "Courier New", "Franklin Gothic Medium", "Garamond", "Georgia", "Helvetica", "Impact",
+	"Lucida Console", "Lucida Sans Unicode", "Palatino Linotype", "Segoe UI", "Segoe UI Emoji", "Segoe UI Symbol",
+	"Symbol", "Tahoma", "Times New Roman", "Trebuchet MS", "Verdana", "Webdings", "Wingdings",
+	"Malgun Gothic", "Microsoft JhengHei", "Microsoft YaHei", "MS Gothic", "MS Mincho", "MS PGothic", "MS PMincho",
+	"PMingLiU", "SimSun", "Yu Gothic", "Yu Mincho",
+}
+
+// PreflightOptions configure the checks of Preflight.
+type PreflightOptions struct {
+	// AvailableFonts are the font families expected on the computers of the recipients, compared
+	// case-insensitively. DefaultAvailableFonts are used if empty.
+	AvailableFonts []string
+	// Print checks the requirements of professional printing in addition, see PrintRequirements. Nil for documents
+	// which are shared digitally.
+	Print *PrintRequirements
+}
+
+// PreflightIssue is a problem found by Preflight which likely shows when the recipient opens the document.
+type PreflightIssue struct {
+	Kind     PreflightIssueKind
+	Severity PreflightSeverity
+	// Font is the name of the font family of font issues.
+	Font string
+	// Image is the name of the media part of image issues, e.g. 'word/media/image1.png'.
+	Image string
+	// Parts are the names of the parts which reference the font or the image, e.g. 'word/document.xml'.
+	Parts   []string
+	Message string
+	// Action describes how the issue can be fixed, it is empty if there is no simple fix.
+	Action string
+}
+
+// String returns the message of the issue followed by the action.
+func (i PreflightIssue) String() string {
+	if i.Action == "" {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+	}
+	return fmt.Sprintf("%s: %s, %s", i.Kind, i.Message, i.Action)
+}
+
+// PreflightReport is the result of Preflight.
+type PreflightReport struct {
+	// Fonts are all font families referenced by the document, sorted by name.
+	Fonts  []string
+	Issues []PreflightIssue
+}
+
+// Passed returns true if the report has no issues of severity PreflightSeverityError.
+func (r *PreflightReport) Passed() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == PreflightSeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Preflight checks the document before it is sent to the recipients, e.g. after the template was rendered so
+// inserted content is checked as well. It reports fonts referenced by the text, the styles, the numbering or the
+// theme which are neither embedded (see EmbedFont) nor in the list of available fonts, as text using them likely
+// renders with a substitute font on the computers of the recipients. With PreflightOptions.Print, the document is
+// checked for professional printing as well, see PrintRequirements.
+//
+// Example:
+//
+//	report, err := doc.Preflight(docx.PreflightOptions{
+//	    AvailableFonts: append(docx.DefaultAvailableFonts, "Corporate Sans"),
+//	})
+//	for _, issue := range report.Issues {
+//	    log.Printf("preflight: %s", issue)
+//	}
+func (d *Document) Preflight(options PreflightOptions) (*PreflightReport, error) {
+	if len(options.AvailableFonts) == 0 {
+		options.AvailableFonts = DefaultAvailableFonts
+	}
+	fonts, err := d.referencedFonts()
+	if err != nil {
+		return nil, err
+	}
+	fontTable, _, err := d.part(FontTableXml)
+	if err != nil {
+		return nil, err
+	}
+	declared := parseFontTable(fontTable)
+
+	report := &PreflightReport{}
+	for name := range fonts {
+		report.Fonts = append(report.Fonts, name)
+	}
+	sort.Strings(report.Fonts)
+	for _, name := range report.Fonts {
+		font := declared[name]
+		if font.embedded {
+			continue
+		}
+		// printers need every font, no matter how common it is
+		if options.Print != nil {
+			report.Issues = append(report.Issues, PreflightIssue{
+				Kind:     PreflightFontNotEmbedded,
+				Severity: PreflightSeverityError,
+				Font:     name,
+				Parts:    fonts[name],
+				Message:  fmt.Sprintf("font %s is not embedded", name),
+				Action:   "embed it with EmbedFont",
+			})
+			continue
+		}
+		if containsFold(options.AvailableFonts, name) {
+			continue
+		}
+		message := fmt.Sprintf("font %s is neither embedded nor commonly available, text likely renders with a substitute", name)
+		if font.altName != "" && containsFold(options.AvailableFonts, font.altName) {
+			message += " (probably " + font.altName + ")"
+		}
+		report.Issues = append(report.Issues, PreflightIssue{
+			Kind:     PreflightFontSubstitution,
+			Severity: PreflightSeverityWarning,
+			Font:     name,
+			Parts:    fonts[name],
+			Message:  message,
+			Action:   "embed it with EmbedFont or use a common font",
+		})
+	}
+
+	if options.Print != nil {
+		issues, err := d.printIssues(*options.Print)
+		if err != nil {
+			return nil, err
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+	return report, nil
+}
+
+// declaredFont is a font of the font table.
+type declaredFont struct {
+	altName  string
+	embedded bool
+}
+
+// parseFontTable returns the fonts of the font table by name.
+func parseFontTable(fontTable []byte) map[string]declaredFont {
+	fonts := map[string]declaredFont{}
+	match := fontsRegex.FindSubmatch(fontTable)
+	if match == nil {
+		return fonts
+	}
+	content := string(match[2])
+	for _, child := range childElements(content) {
+		if child.name != "font" {
+			continue
+		}
+		fontXml := content[child.start:child.end]
+		name := fontNameRegex.FindStringSubmatch(fontXml)
+		if name == nil {
+			continue
+		}
+		font := declaredFont{embedded: fontEmbedRegex.MatchString(fontXml)}
+		if altName := fontAltNameRegex.FindStringSubmatch(fontXml); altName != nil {
+			font.altName = html.UnescapeString(altName[1])
+		}
+		fonts[html.UnescapeString(name[1])] = font
+	}
+	return fonts
+}
+
+// referencedFonts returns the names of the parts referencing each font family. Theme fonts are resolved with the
+// theme of the document.
+func (d *Document) referencedFonts() (map[string][]string, error) {
+	themeFonts := map[string]string{}
+	theme, exists, err := d.relationshipTarget(DocumentXml, RelationshipTypeTheme)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		data, _, err := d.part(theme)
+		if err != nil {
+			return nil, err
+		}
+		for _, scheme := range themeFontsRegex.FindAllSubmatch(data, -1) {
+			for _, typeface := range themeTypefaceRegex.FindAllSubmatch(scheme[2], -1) {
+				themeFonts[string(scheme[1])+"/"+string(typeface[1])] = html.UnescapeString(string(typeface[2]))
+			}
+		}
+	}
+
+	fonts := map[string][]string{}
+	for _, name := range append(d.xmlFiles(), StylesXml, NumberingXml) {
+		data, exists, err := d.part(name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		for _, element := range rFontsRegex.FindAll(data, -1) {
+			for _, attribute := range runFontsRegex.FindAllSubmatch(element, -1) {
+				font := html.UnescapeString(string(attribute[2]))
+				if strings.HasSuffix(strings.ToLower(string(attribute[1])), "theme") {
+					font = themeFonts[themeFontKey(font)]
+				}
+				if font == "" || containsFold(fonts[font], name) {
+					continue
+				}
+				fonts[font] = append(fonts[font], name)
+			}
+		}
+	}
+	return fonts, nil
+}
+
+// themeFontKey returns the key of the theme font referenced by a theme attribute of run fonts, e.g. 'minor/latin'
+// for 'minorHAnsi'.
+func themeFontKey(value string) string {
+	scheme := "minor"
+	if strings.HasPrefix(value, "major") {
+		scheme = "major"
+	}
+	switch strings.TrimPrefix(strings.TrimPrefix(value, "major"), "minor") {
+	case "EastAsia":
+		return scheme + "/ea"
+	case "Bidi":
+		return scheme + "/cs"
+	}
+	return scheme + "/latin"
+}
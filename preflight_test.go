@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Preflight_Fonts(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:rFonts w:ascii="Corporate Sans" w:hAnsi="Corporate Sans"/></w:rPr><w:t>Logo</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:rPr><w:rFonts w:ascii="Fancy Serif" w:hAnsi="Fancy Serif"/></w:rPr><w:t>Title</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:rPr><w:rFonts w:asciiTheme="majorHAnsi" w:hAnsiTheme="majorHAnsi"/></w:rPr><w:t>Heading</w:t></w:r></w:p>`,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeTheme+`" Target="theme/theme1.xml"/></Relationships>`,
+		"word/theme/theme1.xml", `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:themeElements><a:fontScheme name="Office">`+
+			`<a:majorFont><a:latin typeface="Brand Display"/><a:ea typeface=""/><a:cs typeface=""/></a:majorFont>`+
+			`<a:minorFont><a:latin typeface="Calibri"/><a:ea typeface=""/><a:cs typeface=""/></a:minorFont></a:fontScheme></a:themeElements></a:theme>`,
+		StylesXml, `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:docDefaults><w:rPrDefault><w:rPr>`+
+			`<w:rFonts w:asciiTheme="minorHAnsi" w:hAnsiTheme="minorHAnsi" w:eastAsia="MS Mincho" w:cs="Times New Roman"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`,
+		FontTableXml, `<w:fonts xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+			`<w:font w:name="Corporate Sans"><w:embedRegular r:id="rId1" w:fontKey="{00000000-0000-0000-0000-000000000000}"/></w:font>`+
+			`<w:font w:name="Fancy Serif"><w:altName w:val="Georgia"/></w:font></w:fonts>`)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	report, err := doc.Preflight(PreflightOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFonts := []string{"Brand Display", "Calibri", "Corporate Sans", "Fancy Serif", "MS Mincho", "Times New Roman"}
+	if !reflect.DeepEqual(report.Fonts, expectedFonts) {
+		t.Errorf("expected fonts %v, have %v", expectedFonts, report.Fonts)
+	}
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected 2 issues, have %v", report.Issues)
+	}
+	if issue := report.Issues[0]; issue.Kind != PreflightFontSubstitution || issue.Font != "Brand Display" ||
+		!reflect.DeepEqual(issue.Parts, []string{DocumentXml}) {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue := report.Issues[1]; issue.Font != "Fancy Serif" || !strings.Contains(issue.Message, "probably Georgia") {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+
+	report, err = doc.Preflight(PreflightOptions{AvailableFonts: []string{"brand display", "fancy serif", "Calibri", "MS Mincho", "Times New Roman"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("expected no issues with the custom font list, have %v", report.Issues)
+	}
+}
@@ -0,0 +1,44 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PreparedTemplate is a template whose archive was read and whose placeholders were parsed once, so it can be
+// rendered many times without parsing it again. A PreparedTemplate is safe for concurrent use.
+type PreparedTemplate struct {
+	doc *Document
+}
+
+// Prepare reads and parses the DOCX template given by input for repeated rendering, see PreparedTemplate.
+//
+// Example:
+//
+//	prepared, err := docx.Prepare(templateBytes)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// e.g. inside an HTTP handler, called concurrently
+//	outputBytes, err := prepared.Render(docx.PlaceholderMap{"name": "Jane Doe"})
+func Prepare(input []byte) (*PreparedTemplate, error) {
+	doc, err := OpenBytes(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document from bytes: %w", err)
+	}
+	return &PreparedTemplate{doc: doc}, nil
+}
+
+// Render replaces the placeholders of a copy of the template with the values of the map and returns the document,
+// the template itself is never modified.
+func (p *PreparedTemplate) Render(placeholderMap PlaceholderMap) ([]byte, error) {
+	doc := p.doc.clone()
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPreparedTemplate_Render(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prepared, err := Prepare(buildTestDocx(t, `<w:p><w:r><w:t>Dear {na</w:t></w:r><w:r><w:t>me},</w:t></w:r></w:p><w:p><w:r><w:t>{logo}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const renders = 20
+	outputs := make([][]byte, renders)
+	errs := make([]error, renders)
+	var wg sync.WaitGroup
+	for i := range renders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], errs[i] = prepared.Render(PlaceholderMap{
+				"name": fmt.Sprintf("Customer %d", i),
+				"logo": Image{Data: imageBytes, Width: EMUPerCentimeter},
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i := range renders {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		document := readTestPart(t, outputs[i], DocumentXml)
+		if !strings.Contains(document, fmt.Sprintf("Dear Customer %d<", i)) || !strings.Contains(document, `r:embed="rId1"`) {
+			t.Errorf("unexpected document %d: %s", i, document)
+		}
+		rels := readTestPart(t, outputs[i], "word/_rels/document.xml.rels")
+		if n := strings.Count(rels, RelationshipTypeImage); n != 1 {
+			t.Errorf("expected a single image relationship in document %d, have %d", i, n)
+		}
+	}
+
+	// the template itself is never modified
+	output, err := prepared.Render(PlaceholderMap{"name": "Jane", "logo": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "Dear Jane<") || strings.Contains(document, "<w:drawing>") {
+		t.Errorf("unexpected document: %s", document)
+	}
+}
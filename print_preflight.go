@@ -0,0 +1,230 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+)
+
+// printSizeTolerance is the difference in twips between page sizes which is accepted, as Word rounds page sizes.
+const printSizeTolerance = 20
+
+// PrintRequirements are the requirements of professional printing checked by Preflight, see
+// PreflightOptions.Print. Sizes are given in twips, see TwipsPerCentimeter.
+type PrintRequirements struct {
+	// TrimWidth and TrimHeight are the size of the pages after trimming, e.g. 11906 x 16838 for A4 portrait. The page
+	// size of every section must be the trim size plus the bleed on every side, in either orientation. The page size
+	// is not checked if zero.
+	TrimWidth, TrimHeight int
+	// Bleed is the extension of the pages beyond the trim size on every side, e.g. 170 for 3 mm.
+	Bleed int
+	// SafetyMargin is the minimum distance of text from the trimmed edge of the pages, e.g. 283 for 5 mm. The page
+	// margins, including the distances of headers and footers, must be at least the bleed plus the safety margin.
+	SafetyMargin int
+	// MinImageDPI is the minimum resolution of images at their printed size, e.g. 300. It is not checked if zero.
+	// Images in formats without pixel size (e.g. EMF or SVG) are vector graphics and always pass.
+	MinImageDPI int
+	// ColorSpaceHints warns about images which are neither CMYK nor grayscale, as the printer converts them.
+	ColorSpaceHints bool
+}
+
+// DefaultPrintRequirements are the common requirements of print shops: 3 mm bleed, 5 mm safety margin and images
+// with at least 300 DPI. The trim size depends on the product and must be set as needed.
+var DefaultPrintRequirements = PrintRequirements{Bleed: 170, SafetyMargin: 283, MinImageDPI: 300, ColorSpaceHints: true}
+
+// printIssues returns the issues of the document for professional printing with the requirements.
+func (d *Document) printIssues(requirements PrintRequirements) ([]PreflightIssue, error) {
+	sections, err := d.Sections()
+	if err != nil {
+		return nil, err
+	}
+	var issues []PreflightIssue
+	for i, section := range sections {
+		issues = append(issues, d.sectionPrintIssues(i+1, section, requirements)...)
+	}
+	imageIssues, err := d.imagePrintIssues(requirements)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, imageIssues...), nil
+}
+
+// sectionPrintIssues returns the issues of the page size and the margins of the section with the given number.
+func (d *Document) sectionPrintIssues(number int, section Section, requirements PrintRequirements) []PreflightIssue {
+	var issues []PreflightIssue
+	if requirements.TrimWidth > 0 && requirements.TrimHeight > 0 {
+		width, height := requirements.TrimWidth+2*requirements.Bleed, requirements.TrimHeight+2*requirements.Bleed
+		if section.PageWidth > section.PageHeight {
+			width, height = height, width
+		}
+		if abs(section.PageWidth-width) > printSizeTolerance || abs(section.PageHeight-height) > printSizeTolerance {
+			issues = append(issues, PreflightIssue{
+				Kind:     PreflightPageSize,
+				Severity: PreflightSeverityError,
+				Parts:    []string{DocumentXml},
+				Message: fmt.Sprintf("section %d: the page size is %s x %s, the trim size plus bleed is %s x %s", number,
+					formatCentimeters(section.PageWidth), formatCentimeters(section.PageHeight), formatCentimeters(width), formatCentimeters(height)),
+				Action: fmt.Sprintf("set the page size to %d x %d twips with SetSectionPageSize", width, height),
+			})
+		}
+	}
+
+	required := requirements.Bleed + requirements.SafetyMargin
+	if required <= 0 {
+		return issues
+	}
+	margins := []struct {
+		name  string
+		value int
+		check bool
+	}{
+		{"top", section.Margins.Top, true},
+		{"right", section.Margins.Right, true},
+		{"bottom", section.Margins.Bottom, true},
+		{"left", section.Margins.Left + section.Margins.Gutter, true},
+		{"header", section.Margins.Header, len(d.headerFiles) > 0},
+		{"footer", section.Margins.Footer, len(d.footerFiles) > 0},
+	}
+	for _, margin := range margins {
+		// negative top and bottom margins are exact margins, the text does not move them
+		if !margin.check || abs(margin.value) >= required {
+			continue
+		}
+		issues = append(issues, PreflightIssue{
+			Kind:     PreflightMargin,
+			Severity: PreflightSeverityError,
+			Parts:    []string{DocumentXml},
+			Message: fmt.Sprintf("section %d: the %s margin is %s, text closer than %s to the page edge may be trimmed",
+				number, margin.name, formatCentimeters(abs(margin.value)), formatCentimeters(required)),
+			Action: fmt.Sprintf("increase the %s margin to at least %s", margin.name, formatCentimeters(required)),
+		})
+	}
+	return issues
+}
+
+// printedImage is an image of the document and its lowest resolution at the printed sizes.
+type printedImage struct {
+	media  string
+	parts  []string
+	config image.Config
+	dpi    float64
+	// width and height are the printed size with the lowest resolution in EMU
+	width, height int64
+}
+
+// imagePrintIssues returns the issues of the resolution and the color space of the images of the document.
+func (d *Document) imagePrintIssues(requirements PrintRequirements) ([]PreflightIssue, error) {
+	if requirements.MinImageDPI <= 0 && !requirements.ColorSpaceHints {
+		return nil, nil
+	}
+
+	var images []*printedImage
+	byMedia := make(map[string]*printedImage)
+	for _, name := range d.xmlFiles() {
+		drawings := drawingRegex.FindAll(d.files[name], -1)
+		if len(drawings) == 0 {
+			continue
+		}
+		rels, _, err := d.part(relationshipsPart(name))
+		if err != nil {
+			return nil, err
+		}
+		targets := make(map[string]string)
+		for _, rel := range parseRelationships(rels) {
+			if !rel.external {
+				targets[rel.id] = rel.partName(name)
+			}
+		}
+
+		for _, drawing := range drawings {
+			extent, blip := extentRegex.FindSubmatch(drawing), blipRegex.FindSubmatch(drawing)
+			if extent == nil || blip == nil || targets[string(blip[1])] == "" {
+				continue
+			}
+			media := targets[string(blip[1])]
+			width, _ := strconv.ParseInt(string(extent[1]), 10, 64)
+			height, _ := strconv.ParseInt(string(extent[2]), 10, 64)
+			if width <= 0 || height <= 0 {
+				continue
+			}
+
+			printed := byMedia[media]
+			if printed == nil {
+				data, exists, err := d.part(media)
+				if err != nil {
+					return nil, err
+				}
+				if !exists {
+					continue
+				}
+				config, _, err := image.DecodeConfig(bytes.NewReader(data))
+				if err != nil {
+					// vector graphics and unknown formats have no resolution
+					continue
+				}
+				printed = &printedImage{media: media, config: config, dpi: math.Inf(1)}
+				byMedia[media] = printed
+				images = append(images, printed)
+			}
+			if !containsString(printed.parts, name) {
+				printed.parts = append(printed.parts, name)
+			}
+			dpi := min(float64(printed.config.Width)*EMUPerInch/float64(width), float64(printed.config.Height)*EMUPerInch/float64(height))
+			if dpi < printed.dpi {
+				printed.dpi, printed.width, printed.height = dpi, width, height
+			}
+		}
+	}
+
+	var issues []PreflightIssue
+	for _, printed := range images {
+		if requirements.MinImageDPI > 0 && printed.dpi < float64(requirements.MinImageDPI) {
+			pixels := func(size int64) int {
+				return int(math.Ceil(float64(size) * float64(requirements.MinImageDPI) / EMUPerInch))
+			}
+			issues = append(issues, PreflightIssue{
+				Kind:     PreflightImageResolution,
+				Severity: PreflightSeverityError,
+				Image:    printed.media,
+				Parts:    printed.parts,
+				Message: fmt.Sprintf("image %s has %.0f DPI at its printed size of %.1f x %.1f cm, at least %d DPI are required",
+					printed.media, math.Floor(printed.dpi), float64(printed.width)/EMUPerCentimeter, float64(printed.height)/EMUPerCentimeter,
+					requirements.MinImageDPI),
+				Action: fmt.Sprintf("replace it by an image of at least %d x %d pixels or print it smaller",
+					pixels(printed.width), pixels(printed.height)),
+			})
+		}
+		if requirements.ColorSpaceHints && !isPrintColorModel(printed.config.ColorModel) {
+			issues = append(issues, PreflightIssue{
+				Kind:     PreflightColorSpace,
+				Severity: PreflightSeverityWarning,
+				Image:    printed.media,
+				Parts:    printed.parts,
+				Message:  fmt.Sprintf("image %s is stored as RGB, the printer converts it to CMYK and colors may shift", printed.media),
+				Action:   "convert it to CMYK with the color profile of the print shop",
+			})
+		}
+	}
+	return issues, nil
+}
+
+// isPrintColorModel returns true for the color models which printers use as is, CMYK and grayscale.
+func isPrintColorModel(model color.Model) bool {
+	return model == color.CMYKModel || model == color.GrayModel || model == color.Gray16Model
+}
+
+// formatCentimeters returns the length in twips as centimeters, e.g. '2.5 cm'.
+func formatCentimeters(twips int) string {
+	return fmt.Sprintf("%.1f cm", float64(twips)/TwipsPerCentimeter)
+}
+
+// abs returns the absolute value of the integer.
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package docx
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Preflight_Print(t *testing.T) {
+	photo, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logo bytes.Buffer
+	if err := png.Encode(&logo, image.NewRGBA(image.Rect(0, 0, 600, 300))); err != nil {
+		t.Fatal(err)
+	}
+
+	input := buildTestDocx(t, `<w:p><w:r><w:rPr><w:rFonts w:ascii="Arial" w:hAnsi="Arial"/></w:rPr><w:t>{photo} {logo}</w:t></w:r></w:p>`+
+		`<w:sectPr><w:pgSz w:w="12246" w:h="17178"/><w:pgMar w:top="1440" w:right="1440" w:bottom="284" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	err = doc.ReplaceAll(PlaceholderMap{
+		"photo": Image{Data: photo, Width: 10 * EMUPerCentimeter},
+		"logo":  Image{Data: logo.Bytes(), Width: 5 * EMUPerCentimeter},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requirements := DefaultPrintRequirements
+	requirements.TrimWidth, requirements.TrimHeight = 11906, 16838
+	report, err := doc.Preflight(PreflightOptions{Print: &requirements})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed() {
+		t.Error("expected the preflight to fail")
+	}
+	var kinds []string
+	for _, issue := range report.Issues {
+		kinds = append(kinds, string(issue.Kind)+"/"+string(issue.Severity))
+	}
+	expected := "font-not-embedded/error margin/error image-resolution/error color-space/warning color-space/warning"
+	if strings.Join(kinds, " ") != expected {
+		t.Fatalf("expected issues %s, have %v", expected, report.Issues)
+	}
+	if issue := report.Issues[1]; issue.Message != "section 1: the bottom margin is 0.5 cm, text closer than 0.8 cm to the page edge may be trimmed" ||
+		issue.Action != "increase the bottom margin to at least 0.8 cm" {
+		t.Errorf("unexpected margin issue %+v", issue)
+	}
+	if issue := report.Issues[2]; !strings.HasPrefix(issue.Image, "word/media/") || !strings.Contains(issue.Message, "at its printed size of 10.0 x 10.0 cm") ||
+		!strings.Contains(issue.Action, "1182 x 1182 pixels") {
+		t.Errorf("unexpected resolution issue %+v", issue)
+	}
+	if issue := report.Issues[4]; !strings.HasSuffix(issue.Image, ".png") {
+		t.Errorf("expected the color hint for the RGB logo, have %+v", issue)
+	}
+
+	// an A4 page without bleed does not match the trim size plus bleed
+	if err := doc.SetSectionPageSize(0, 11906, 16838); err != nil {
+		t.Fatal(err)
+	}
+	report, err = doc.Preflight(PreflightOptions{Print: &PrintRequirements{TrimWidth: 11906, TrimHeight: 16838, Bleed: 170}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 2 || report.Issues[1].Kind != PreflightPageSize || report.Passed() {
+		t.Errorf("expected the page size issue, have %v", report.Issues)
+	}
+
+	// without print requirements only the font substitution is checked
+	report, err = doc.Preflight(PreflightOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 0 || !report.Passed() {
+		t.Errorf("expected no issues, have %v", report.Issues)
+	}
+}
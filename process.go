@@ -1,15 +1,17 @@
 package docx
 
 import (
+	"archive/zip"
 	"bytes"
 	"fmt"
+	"io"
 )
 
 // ProcessBytes takes a byte slice representing a DOCX document and a map of
 // placeholder replacements. It opens the document from the byte slice,
 // performs the replacements, and returns the modified document as a new
 // byte slice. This function is ideal for in-memory processing without
-// requiring file system operations.
+// requiring file system operations. Render offers the same with options, e.g. a timeout.
 //
 // Parameters:
 //   - input: The DOCX file as a byte slice
@@ -60,3 +62,84 @@ func ProcessBytes(input []byte, replacements map[string]string) ([]byte, error)
 
 	return buf.Bytes(), nil
 }
+
+// Process reads a DOCX document from r, replaces the placeholders and writes the resulting document to w.
+// Unlike ProcessBytes, the document is never buffered as a whole: the archive is read sequentially and all
+// parts which cannot contain placeholders (e.g. media files) are copied to w right away.
+// Only the XML parts are held in memory, they are written after all other parts.
+// This allows processing templates from HTTP request bodies or object store streams of any size.
+//
+// Archives whose uncompressed entries do not declare their size up front cannot be read sequentially,
+// ErrStreamUnsupported is returned for them.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+//	    err := docx.Process(r.Body, w, docx.PlaceholderMap{"company": "ACME Corp"})
+//	    ...
+//	}
+func Process(r io.Reader, w io.Writer, replacements PlaceholderMap) error {
+	limits := currentParseLimits()
+	zipWriter := zip.NewWriter(w)
+
+	// the XML parts are collected inside an uncompressed in-memory archive from which the document is opened
+	var xmlArchive bytes.Buffer
+	xmlWriter := zip.NewWriter(&xmlArchive)
+	streamed := make(map[string]bool)
+
+	streamReader := newZipStreamReader(r)
+	for entries := 1; ; entries++ {
+		entry, err := streamReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read document from stream: %w", err)
+		}
+		if limits.MaxArchiveEntries > 0 && entries > limits.MaxArchiveEntries {
+			return fmt.Errorf("%w: archive has more than %d entries", ErrLimitExceeded, limits.MaxArchiveEntries)
+		}
+
+		var fw io.Writer
+		if isXMLPart(entry.Name) {
+			fw, err = xmlWriter.CreateHeader(&zip.FileHeader{Name: entry.Name, Method: zip.Store})
+		} else {
+			fw, err = zipWriter.Create(entry.Name)
+			streamed[entry.Name] = true
+		}
+		if err != nil {
+			return fmt.Errorf("unable to create writer: %w", err)
+		}
+		if err := limits.copyPart(fw, entry, entry.Name); err != nil {
+			return err
+		}
+	}
+	if err := xmlWriter.Close(); err != nil {
+		return fmt.Errorf("unable to close ZIP writer: %w", err)
+	}
+
+	xmlReader, err := zip.NewReader(bytes.NewReader(xmlArchive.Bytes()), int64(xmlArchive.Len()))
+	if err != nil {
+		return fmt.Errorf("failed to open document from stream: %w", err)
+	}
+	doc, err := newDocument(xmlReader, "", nil, limits)
+	if err != nil {
+		return fmt.Errorf("failed to open document from stream: %w", err)
+	}
+	doc.streamedParts = streamed
+
+	if err := doc.ReplaceAll(replacements); err != nil {
+		return fmt.Errorf("failed to replace placeholders: %w", err)
+	}
+	if err := doc.prepareWrite(); err != nil {
+		return err
+	}
+	if err := doc.writeArchive(zipWriter); err != nil {
+		return fmt.Errorf("failed to write document to stream: %w", err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("unable to close ZIP writer: %w", err)
+	}
+	return nil
+}
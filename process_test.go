@@ -1,7 +1,12 @@
 package docx
 
 import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -53,3 +58,89 @@ func TestProcessBytes_InvalidZip(t *testing.T) {
 		t.Fatal("expected error for invalid ZIP input")
 	}
 }
+
+func TestProcess(t *testing.T) {
+	templateBytes, err := os.ReadFile("./test/template.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacements := PlaceholderMap{"key": "REPLACED_VALUE"}
+
+	var streamed bytes.Buffer
+	if err := Process(bytes.NewReader(templateBytes), &streamed, replacements); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	expected, err := ProcessBytes(templateBytes, map[string]string{"key": "REPLACED_VALUE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := OpenBytes(streamed.Bytes())
+	if err != nil {
+		t.Fatalf("Process output is not a valid DOCX: %v", err)
+	}
+	defer output.Close()
+	if readTestPart(t, streamed.Bytes(), DocumentXml) != readTestPart(t, expected, DocumentXml) {
+		t.Error("expected the same document as ProcessBytes")
+	}
+	inputReader, _ := zip.NewReader(bytes.NewReader(templateBytes), int64(len(templateBytes)))
+	if len(output.zipFile.File) != len(inputReader.File) {
+		t.Errorf("expected %d parts, have %d", len(inputReader.File), len(output.zipFile.File))
+	}
+}
+
+func TestProcess_StreamedMedia(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// archives written by archive/zip use data descriptors, the sizes are unknown until the content was read
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{photo}</w:t></w:r></w:p>`, "word/media/image1.jpeg", string(imageBytes))
+
+	var output bytes.Buffer
+	err = Process(bytes.NewReader(input), &output, PlaceholderMap{"photo": Image{Data: imageBytes}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if media := readTestPart(t, output.Bytes(), "word/media/image1.jpeg"); media != string(imageBytes) {
+		t.Error("expected the streamed media part to be copied unchanged")
+	}
+	// the inserted image must not overwrite the streamed media part
+	readTestPart(t, output.Bytes(), "word/media/image2.jpeg")
+	if rels := readTestPart(t, output.Bytes(), "word/_rels/document.xml.rels"); !strings.Contains(rels, `Target="media/image2.jpeg"`) {
+		t.Errorf("expected relationship to the inserted image: %s", rels)
+	}
+}
+
+func TestProcess_InvalidRelationships(t *testing.T) {
+	// the document is checked like by Write before it is written to the stream
+	input := buildTestDocx(t, `<w:p><w:hyperlink r:id="rId2"/></w:p>`, "word/_rels/document.xml.rels", testRelationshipsPart(""))
+
+	var relErr *RelationshipError
+	err := Process(bytes.NewReader(input), io.Discard, PlaceholderMap{})
+	if !errors.As(err, &relErr) || relErr.ID != "rId2" {
+		t.Errorf("expected relationship error, have %v", err)
+	}
+}
+
+func TestProcess_Unsupported(t *testing.T) {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	fw, err := zipWriter.CreateHeader(&zip.FileHeader{Name: DocumentXml, Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(testDocumentOpen + testDocumentClose))
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = Process(bytes.NewReader(buf.Bytes()), io.Discard, PlaceholderMap{})
+	if !errors.Is(err, ErrStreamUnsupported) {
+		t.Errorf("expected ErrStreamUnsupported, have %v", err)
+	}
+	if err := Process(strings.NewReader("no archive"), io.Discard, PlaceholderMap{}); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
@@ -0,0 +1,476 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CorePropertiesXml is the default path of the core properties part (title, author, ...).
+	CorePropertiesXml = "docProps/core.xml"
+	// CustomPropertiesXml is the default path of the custom properties part.
+	CustomPropertiesXml = "docProps/custom.xml"
+
+	// RelationshipTypeCoreProperties is the relationship type of the core properties part.
+	RelationshipTypeCoreProperties = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	// RelationshipTypeCustomProperties is the relationship type of the custom properties part.
+	RelationshipTypeCustomProperties = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	// ContentTypeCoreProperties is the content type of the core properties part.
+	ContentTypeCoreProperties = "application/vnd.openxmlformats-package.core-properties+xml"
+	// ContentTypeCustomProperties is the content type of the custom properties part.
+	ContentTypeCustomProperties = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+
+	// DocPropertyFieldType is the type of fields which show a document property.
+	DocPropertyFieldType = "DOCPROPERTY"
+
+	// customPropertyFormatId is the format ID of all user defined custom properties.
+	customPropertyFormatId = "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}"
+	// propertyTimeLayout is the format of date properties.
+	propertyTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+var (
+	// corePropertiesRegex matches the root element of the core properties part and captures the open tag and the content.
+	corePropertiesRegex = regexp.MustCompile(`(?s)(<cp:coreProperties(?:\s[^>]*)?>)(.*)</cp:coreProperties>`)
+	// customPropertiesRegex matches the root element of the custom properties part and captures the open tag and the content.
+	customPropertiesRegex = regexp.MustCompile(`(?s)(<Properties(?:\s[^>]*)?>)(.*)</Properties>`)
+	// customPropertyRegex matches a single custom property and captures its attributes and its value element.
+	customPropertyRegex = regexp.MustCompile(`(?s)<property\s([^>]*)>(.*?)</property>`)
+	// variantRegex matches the typed value of a custom property and captures the type and the value.
+	variantRegex = regexp.MustCompile(`(?s)<vt:(\w+)(?:\s[^>]*?)?(?:/>|>(.*?)</vt:\w+>)`)
+
+	// coreProperties maps the names of the core properties to their elements.
+	coreProperties = map[string]string{
+		"Title":          "dc:title",
+		"Subject":        "dc:subject",
+		"Creator":        "dc:creator",
+		"Keywords":       "cp:keywords",
+		"Description":    "dc:description",
+		"LastModifiedBy": "cp:lastModifiedBy",
+		"Revision":       "cp:revision",
+		"Category":       "cp:category",
+		"ContentStatus":  "cp:contentStatus",
+		"Language":       "dc:language",
+		"Identifier":     "dc:identifier",
+		"Version":        "cp:version",
+		"Created":        "dcterms:created",
+		"Modified":       "dcterms:modified",
+		"LastPrinted":    "cp:lastPrinted",
+	}
+	// corePropertyAliases maps the names Word uses for core properties, e.g. in DOCPROPERTY fields, to their names.
+	corePropertyAliases = map[string]string{
+		"Author":         "Creator",
+		"Comments":       "Description",
+		"LastSavedBy":    "LastModifiedBy",
+		"RevisionNumber": "Revision",
+		"CreateTime":     "Created",
+		"LastSavedTime":  "Modified",
+	}
+	// propertyNamespaces are the namespaces of the prefixes used in the core properties part.
+	propertyNamespaces = map[string]string{
+		"cp":      "http://schemas.openxmlformats.org/package/2006/metadata/core-properties",
+		"dc":      "http://purl.org/dc/elements/1.1/",
+		"dcterms": "http://purl.org/dc/terms/",
+		"xsi":     "http://www.w3.org/2001/XMLSchema-instance",
+	}
+
+	emptyCoreProperties = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" ` +
+		`xmlns:dcmitype="http://purl.org/dc/dcmitype/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"></cp:coreProperties>`)
+	emptyCustomProperties = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" ` +
+		`xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"></Properties>`)
+)
+
+// corePropertyName returns the name of the core property, resolving the names Word uses and ignoring the case.
+// The second return value is false if there is no such core property.
+func corePropertyName(name string) (string, bool) {
+	for alias, property := range corePropertyAliases {
+		if strings.EqualFold(alias, name) {
+			return property, true
+		}
+	}
+	for property := range coreProperties {
+		if strings.EqualFold(property, name) {
+			return property, true
+		}
+	}
+	return "", false
+}
+
+// isDateProperty returns true if the core property is a date.
+func isDateProperty(name string) bool {
+	return name == "Created" || name == "Modified" || name == "LastPrinted"
+}
+
+// propertiesPart returns the name and the content of the properties part with the given relationship type.
+// If the document does not have the part, the default name and no content are returned.
+func (d *Document) propertiesPart(relType, defaultName string) (string, []byte, error) {
+	name, exists, err := d.relationshipTarget("", relType)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		return defaultName, nil, nil
+	}
+	data, exists, err := d.part(name)
+	if err != nil || !exists {
+		return name, nil, err
+	}
+	return name, data, nil
+}
+
+// setPropertiesPart sets the content of the properties part, adding its relationship and content type if needed.
+func (d *Document) setPropertiesPart(name string, data []byte, relType, contentType string) error {
+	if !d.hasPart(name) {
+		if _, err := d.addRelationship("", relType, name, false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(name, contentType); err != nil {
+			return err
+		}
+	}
+	return d.setPart(name, data)
+}
+
+// Properties returns the core properties of the document (docProps/core.xml) which are set, keyed by their names:
+// Title, Subject, Creator, Keywords, Description, LastModifiedBy, Revision, Category, ContentStatus, Language,
+// Identifier, Version, Created, Modified and LastPrinted.
+func (d *Document) Properties() (map[string]string, error) {
+	_, data, err := d.propertiesPart(RelationshipTypeCoreProperties, CorePropertiesXml)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[string]string)
+	for name, element := range coreProperties {
+		if match := corePropertyRegex(element).FindSubmatch(data); match != nil {
+			properties[name] = html.UnescapeString(string(match[1]))
+		}
+	}
+	return properties, nil
+}
+
+// Property returns the value of the core property with the given name, see Properties. The names Word uses
+// (Author, Comments, LastSavedBy, RevisionNumber, CreateTime and LastSavedTime) are supported as well.
+// The second return value is false if the property is not set.
+func (d *Document) Property(name string) (string, bool, error) {
+	property, known := corePropertyName(name)
+	if !known {
+		return "", false, fmt.Errorf("unknown core property %q", name)
+	}
+	properties, err := d.Properties()
+	if err != nil {
+		return "", false, err
+	}
+	value, exists := properties[property]
+	return value, exists, nil
+}
+
+// SetProperty sets the core property with the given name, see Property. The core properties part is created if the
+// document does not have one. Dates (Created, Modified and LastPrinted) must be formatted as RFC 3339.
+//
+// Example:
+//
+//	doc.SetProperty("Title", "Service Agreement")
+//	doc.SetProperty("Author", "ACME Legal")
+func (d *Document) SetProperty(name, value string) error {
+	property, known := corePropertyName(name)
+	if !known {
+		return fmt.Errorf("unknown core property %q", name)
+	}
+	element := coreProperties[property]
+
+	attributes := ""
+	if isDateProperty(property) {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid date of core property %s: %w", property, err)
+		}
+		value = date.UTC().Format(propertyTimeLayout)
+		if strings.HasPrefix(element, "dcterms:") {
+			attributes = ` xsi:type="dcterms:W3CDTF"`
+		}
+	}
+	xml := "<" + element + attributes + ">" + html.EscapeString(normalizeText(value)) + "</" + element + ">"
+
+	partName, data, err := d.propertiesPart(RelationshipTypeCoreProperties, CorePropertiesXml)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = emptyCoreProperties
+	}
+	match := corePropertiesRegex.FindSubmatchIndex(data)
+	if match == nil {
+		return fmt.Errorf("invalid core properties part %s", partName)
+	}
+	openTag, content := string(data[match[2]:match[3]]), string(data[match[4]:match[5]])
+	if existing := corePropertyRegex(element).FindStringIndex(content); existing != nil {
+		content = content[:existing[0]] + xml + content[existing[1]:]
+	} else {
+		content += xml
+	}
+	for _, prefix := range []string{strings.Split(element, ":")[0], "xsi"} {
+		if prefix == "xsi" && attributes == "" {
+			continue
+		}
+		if !strings.Contains(openTag, "xmlns:"+prefix+"=") {
+			openTag = strings.TrimSuffix(openTag, ">") + ` xmlns:` + prefix + `="` + propertyNamespaces[prefix] + `">`
+		}
+	}
+
+	changed := string(data[:match[2]]) + openTag + content + string(data[match[5]:])
+	return d.setPropertiesPart(partName, []byte(changed), RelationshipTypeCoreProperties, ContentTypeCoreProperties)
+}
+
+// corePropertyRegex returns a regex which matches the element of a core property and captures its value.
+func corePropertyRegex(element string) *regexp.Regexp {
+	return regexp.MustCompile(`(?s)<` + regexp.QuoteMeta(element) + `(?:\s[^>]*?)?(?:/>|>(.*?)</` + regexp.QuoteMeta(element) + `>)`)
+}
+
+// customProperty is a parsed custom property, described by byte offsets inside the content of the custom properties.
+type customProperty struct {
+	name       string
+	pid        int
+	start, end int
+	value      interface{}
+}
+
+// parseCustomProperties returns the custom properties inside the content of the custom properties part.
+func parseCustomProperties(content string) []customProperty {
+	var properties []customProperty
+	for _, match := range customPropertyRegex.FindAllStringSubmatchIndex(content, -1) {
+		property := customProperty{start: match[0], end: match[1]}
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(content[match[2]:match[3]], -1) {
+			switch attribute[1] {
+			case "name":
+				property.name = html.UnescapeString(attribute[2])
+			case "pid":
+				property.pid, _ = strconv.Atoi(attribute[2])
+			}
+		}
+		if variant := variantRegex.FindStringSubmatch(content[match[4]:match[5]]); variant != nil {
+			property.value = parseVariant(variant[1], html.UnescapeString(variant[2]))
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// parseVariant converts the value of a custom property into a string, int, float64, bool or time.Time.
+// Values of unknown types are returned as string.
+func parseVariant(variantType, value string) interface{} {
+	switch variantType {
+	case "i1", "i2", "i4", "i8", "int", "ui1", "ui2", "ui4", "ui8", "uint":
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return int(number)
+		}
+	case "r4", "r8", "decimal":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "bool":
+		return value == "true" || value == "1"
+	case "filetime", "date":
+		if date, err := time.Parse(time.RFC3339, value); err == nil {
+			return date
+		}
+	}
+	return value
+}
+
+// variantXml returns the typed value element of a custom property.
+func variantXml(value interface{}) (string, error) {
+	var variantType, text string
+	switch v := value.(type) {
+	case string:
+		variantType, text = "lpwstr", html.EscapeString(normalizeText(v))
+	case bool:
+		variantType, text = "bool", strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		number, _ := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		variantType, text = "i4", strconv.FormatInt(number, 10)
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			variantType = "i8"
+		}
+	case float32:
+		variantType, text = "r8", strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		variantType, text = "r8", strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		variantType, text = "filetime", v.UTC().Format(propertyTimeLayout)
+	default:
+		return "", fmt.Errorf("unsupported type %T of custom property", value)
+	}
+	return "<vt:" + variantType + ">" + text + "</vt:" + variantType + ">", nil
+}
+
+// CustomProperties returns the custom properties of the document (docProps/custom.xml) keyed by their names.
+// The values are strings, ints, float64s, bools or time.Times depending on the type of the property.
+func (d *Document) CustomProperties() (map[string]interface{}, error) {
+	_, data, err := d.propertiesPart(RelationshipTypeCustomProperties, CustomPropertiesXml)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[string]interface{})
+	for _, property := range parseCustomProperties(string(data)) {
+		properties[property.name] = property.value
+	}
+	return properties, nil
+}
+
+// CustomProperty returns the value of the custom property with the given name, ignoring the case of the name,
+// see CustomProperties. The second return value is false if the property does not exist.
+func (d *Document) CustomProperty(name string) (interface{}, bool, error) {
+	properties, err := d.CustomProperties()
+	if err != nil {
+		return nil, false, err
+	}
+	for propertyName, value := range properties {
+		if strings.EqualFold(propertyName, name) {
+			return value, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// SetCustomProperty sets the custom property with the given name, replacing an existing property of the same name
+// regardless of the case. Strings, integers, floats, bools and time.Times are supported. The custom properties part
+// is created if the document does not have one.
+//
+// Example:
+//
+//	doc.SetCustomProperty("ContractID", 42)
+//	doc.SetCustomProperty("Confidential", true)
+func (d *Document) SetCustomProperty(name string, value interface{}) error {
+	if name == "" {
+		return fmt.Errorf("custom property without name")
+	}
+	variant, err := variantXml(value)
+	if err != nil {
+		return fmt.Errorf("unable to set custom property %s: %w", name, err)
+	}
+
+	partName, data, err := d.propertiesPart(RelationshipTypeCustomProperties, CustomPropertiesXml)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = emptyCustomProperties
+	}
+	match := customPropertiesRegex.FindSubmatchIndex(data)
+	if match == nil {
+		return fmt.Errorf("invalid custom properties part %s", partName)
+	}
+	content := string(data[match[4]:match[5]])
+
+	// property IDs start at 2, an existing property keeps its ID
+	pid, replaced := 2, false
+	properties := parseCustomProperties(content)
+	sort.Slice(properties, func(i, j int) bool { return properties[i].start > properties[j].start })
+	for _, property := range properties {
+		if property.pid >= pid {
+			pid = property.pid + 1
+		}
+	}
+	for _, property := range properties {
+		if strings.EqualFold(property.name, name) {
+			xml := fmt.Sprintf(`<property fmtid="%s" pid="%d" name="%s">%s</property>`, customPropertyFormatId, property.pid, html.EscapeString(name), variant)
+			content = content[:property.start] + xml + content[property.end:]
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		content += fmt.Sprintf(`<property fmtid="%s" pid="%d" name="%s">%s</property>`, customPropertyFormatId, pid, html.EscapeString(name), variant)
+	}
+
+	changed := string(data[:match[4]]) + content + string(data[match[5]:])
+	return d.setPropertiesPart(partName, []byte(changed), RelationshipTypeCustomProperties, ContentTypeCustomProperties)
+}
+
+// UpdatePropertyFields updates the results of the DOCPROPERTY fields of the document body, headers and footers to
+// the current core and custom properties, e.g. after SetProperty and SetCustomProperty. The formatting switches of the
+// fields are applied, see FieldInstruction.Format. Fields of unknown properties and fields without a result are
+// left untouched.
+func (d *Document) UpdatePropertyFields() error {
+	core, err := d.Properties()
+	if err != nil {
+		return err
+	}
+	custom, err := d.CustomProperties()
+	if err != nil {
+		return err
+	}
+	propertyValue := func(name string) (interface{}, bool) {
+		if property, known := corePropertyName(name); known {
+			value, exists := core[property]
+			if exists && isDateProperty(property) {
+				if date, err := time.Parse(time.RFC3339, value); err == nil {
+					return date, true
+				}
+			}
+			return value, exists
+		}
+		for propertyName, value := range custom {
+			if strings.EqualFold(propertyName, name) {
+				return value, true
+			}
+		}
+		return nil, false
+	}
+
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		fields, err := parseFields(data)
+		if err != nil {
+			return fmt.Errorf("unable to parse fields in %s: %w", name, err)
+		}
+
+		changed := false
+		// fields are updated in reverse order so that the offsets of the remaining fields stay valid
+		for i := len(fields) - 1; i >= 0; i-- {
+			f := fields[i]
+			instruction := ParseFieldInstruction(f.Instruction)
+			if f.Depth > 0 || instruction.Type != DocPropertyFieldType || len(instruction.Arguments) == 0 || f.Result.Start == 0 {
+				continue
+			}
+			value, exists := propertyValue(instruction.Arguments[0])
+			if !exists {
+				continue
+			}
+			if date, isDate := value.(time.Time); isDate {
+				if _, formatted := instruction.Switch(`\@`); !formatted {
+					value = date.Format("1/2/2006 3:04:05 PM")
+				}
+			}
+			text, err := instruction.Format(value)
+			if err != nil {
+				return fmt.Errorf("unable to update field %s: %w", instruction.Arguments[0], err)
+			}
+
+			result := `<w:r>` + f.RunProperties + `<w:t xml:space="preserve">` + html.EscapeString(normalizeText(text)) + `</w:t></w:r>`
+			updated := append([]byte{}, data[:f.Result.Start]...)
+			updated = append(updated, result...)
+			data = append(updated, data[f.Result.End:]...)
+			changed = true
+		}
+
+		if changed {
+			if err := d.SetFile(name, data); err != nil {
+				return err
+			}
+			if err := d.parseFile(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
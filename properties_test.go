@@ -0,0 +1,149 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_SetProperty(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.SetProperty("Title", "Service & Support"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("Author", "Jane Doe"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("title", "Service Agreement"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("Created", "2026-03-01T10:00:00+02:00"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("Created", "yesterday"); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+	if err := doc.SetProperty("Colour", "red"); err == nil {
+		t.Error("expected an error for an unknown property")
+	}
+
+	properties, err := doc.Properties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"Title": "Service Agreement", "Creator": "Jane Doe", "Created": "2026-03-01T08:00:00Z"}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected %v, got %v", expected, properties)
+	}
+	if author, exists, err := doc.Property("author"); err != nil || !exists || author != "Jane Doe" {
+		t.Errorf("expected the author, got %q, %v, %v", author, exists, err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	core := readTestPart(t, buf.Bytes(), CorePropertiesXml)
+	if !strings.Contains(core, `<dc:title>Service Agreement</dc:title><dc:creator>Jane Doe</dc:creator>`+
+		`<dcterms:created xsi:type="dcterms:W3CDTF">2026-03-01T08:00:00Z</dcterms:created></cp:coreProperties>`) {
+		t.Errorf("unexpected core properties: %s", core)
+	}
+	if rels := readTestPart(t, buf.Bytes(), "_rels/.rels"); !strings.Contains(rels, `Type="`+RelationshipTypeCoreProperties+`" Target="docProps/core.xml"`) {
+		t.Errorf("expected the relationship of the core properties: %s", rels)
+	}
+	if types := readTestPart(t, buf.Bytes(), ContentTypesXml); !strings.Contains(types, `<Override PartName="/docProps/core.xml" ContentType="`+ContentTypeCoreProperties+`"/>`) {
+		t.Errorf("expected the content type of the core properties: %s", types)
+	}
+}
+
+func TestDocument_SetCustomProperty(t *testing.T) {
+	custom := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">` +
+		`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="5" name="Client"><vt:lpwstr>ACME</vt:lpwstr></property></Properties>`
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`,
+		"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>`+
+			`<Relationship Id="rId2" Type="`+RelationshipTypeCustomProperties+`" Target="docProps/custom.xml"/></Relationships>`,
+		CustomPropertiesXml, custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	signed := time.Date(2026, time.May, 4, 12, 30, 0, 0, time.UTC)
+	for name, value := range map[string]interface{}{"ContractID": 42, "client": "ACME & Co", "Rate": 0.25, "Signed": signed, "Confidential": true} {
+		if err := doc.SetCustomProperty(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := doc.SetCustomProperty("Invalid", []string{"a"}); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+
+	properties, err := doc.CustomProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"ContractID": 42, "client": "ACME & Co", "Rate": 0.25, "Signed": signed, "Confidential": true}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected %v, got %v", expected, properties)
+	}
+	if value, exists, err := doc.CustomProperty("contractid"); err != nil || !exists || value != 42 {
+		t.Errorf("expected the contract ID, got %v, %v, %v", value, exists, err)
+	}
+
+	data, _, _ := doc.part(CustomPropertiesXml)
+	if !strings.Contains(string(data), `pid="5" name="client"><vt:lpwstr>ACME &amp; Co</vt:lpwstr>`) ||
+		!strings.Contains(string(data), `name="ContractID"><vt:i4>42</vt:i4>`) || strings.Count(string(data), "<property ") != 5 {
+		t.Errorf("unexpected custom properties: %s", data)
+	}
+	for _, pid := range []string{`pid="6"`, `pid="7"`, `pid="8"`, `pid="9"`} {
+		if !strings.Contains(string(data), pid) {
+			t.Errorf("expected new properties to have unique IDs: %s", data)
+		}
+	}
+}
+
+func TestDocument_UpdatePropertyFields(t *testing.T) {
+	body := `<w:p><w:fldSimple w:instr=" DOCPROPERTY Title \* Upper "><w:r><w:rPr><w:b/></w:rPr><w:t>OLD</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> DOCPROPERTY ContractID \# "0000" </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>0001</w:t></w:r><w:r><w:t>old</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:fldSimple w:instr=" DOCPROPERTY CreateTime \@ &quot;yyyy-MM-dd&quot; "><w:r><w:t>old</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:fldSimple w:instr=" DOCPROPERTY Unknown "><w:r><w:t>kept</w:t></w:r></w:fldSimple></w:p>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.SetProperty("Title", "Service Agreement"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("Created", "2026-03-01T10:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCustomProperty("ContractID", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.UpdatePropertyFields(); err != nil {
+		t.Fatal(err)
+	}
+
+	document := string(doc.files[DocumentXml])
+	for _, expected := range []string{
+		`<w:fldSimple w:instr=" DOCPROPERTY Title \* Upper "><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">SERVICE AGREEMENT</w:t></w:r></w:fldSimple>`,
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t xml:space="preserve">0042</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>`,
+		`<w:r><w:t xml:space="preserve">2026-03-01</w:t></w:r></w:fldSimple>`,
+		`<w:r><w:t>kept</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in the document: %s", expected, document)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ProtectionType defines which kind of editing is allowed in a protected document.
+type ProtectionType string
+
+const (
+	// ProtectionReadOnly allows editing of editable ranges only.
+	ProtectionReadOnly ProtectionType = "readOnly"
+	// ProtectionComments allows adding comments and editing of editable ranges.
+	ProtectionComments ProtectionType = "comments"
+	// ProtectionTrackedChanges allows all edits, but forces change tracking.
+	ProtectionTrackedChanges ProtectionType = "trackedChanges"
+	// ProtectionForms allows filling in form fields only.
+	ProtectionForms ProtectionType = "forms"
+)
+
+// EditorGroup is a group of users which is allowed to edit an editable range.
+type EditorGroup string
+
+const (
+	// EditorsEveryone allows all users to edit the range.
+	EditorsEveryone EditorGroup = "everyone"
+	// EditorsCurrent allows the user who opened the document to edit the range.
+	EditorsCurrent EditorGroup = "current"
+	// EditorsAdministrators allows administrators to edit the range.
+	EditorsAdministrators EditorGroup = "administrators"
+	// EditorsContributors allows contributors to edit the range.
+	EditorsContributors EditorGroup = "contributors"
+	// EditorsEditors allows editors to edit the range.
+	EditorsEditors EditorGroup = "editors"
+	// EditorsOwners allows owners to edit the range.
+	EditorsOwners EditorGroup = "owners"
+)
+
+var (
+	// PermStartRegex matches the start of editable ranges (<w:permStart .../>) and captures the attributes.
+	PermStartRegex = regexp.MustCompile(`<w:permStart\s([^>]*?)/?>`)
+	// bookmarkBeforeRegex matches a bookmark start at the end of the data and captures the bookmark name.
+	bookmarkBeforeRegex = regexp.MustCompile(`<w:bookmarkStart\s[^>]*?w:name="([^"]*)"[^>]*/>$`)
+	// xmlAttributeRegex matches a single attribute and captures the qualified name and the value.
+	xmlAttributeRegex = regexp.MustCompile(`([\w:]+)="([^"]*)"`)
+)
+
+// Protect enables the document protection of the given type. If the protection is read-only or comments,
+// the document can only be edited inside editable ranges, see Editable.
+// No password is set, the protection guides recipients but does not prevent them from disabling it.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "signature": docx.Editable{Name: "Signature", Group: docx.EditorsEveryone},
+//	})
+//	doc.Protect(docx.ProtectionReadOnly)
+func (d *Document) Protect(protection ProtectionType) error {
+	element := fmt.Sprintf(`<w:documentProtection w:edit="%s" w:enforcement="1"/>`, protection)
+	return d.setSetting("documentProtection", element)
+}
+
+// Editable is a replacement value which inserts an editable range (<w:permStart>/<w:permEnd>) at the position of the
+// placeholder. Inside a protected document, only the editable ranges can be edited, e.g. by recipients who must
+// complete a signature or comment field.
+type Editable struct {
+	// Name is the name of the range which is added as bookmark, so the range can be found in the document.
+	// It is optional and must be unique.
+	Name string
+	// Value is the initial content of the range and may be any replacement value, e.g. text or an Image.
+	Value interface{}
+	// Group is the group of users which may edit the range. If Group and Editor are empty, everyone may edit the range.
+	Group EditorGroup
+	// Editor is a single user which may edit the range, e.g. 'jane@example.com' or 'DOMAIN\jane'.
+	Editor string
+}
+
+// inlineXml returns the editable range including its content.
+func (e Editable) inlineXml(ctx *valueContext) (string, error) {
+	content, err := ctx.valueXml(e.Value)
+	if err != nil {
+		return "", err
+	}
+
+	var attributes strings.Builder
+	if e.Editor != "" {
+		fmt.Fprintf(&attributes, ` w:ed="%s"`, html.EscapeString(e.Editor))
+	}
+	if e.Group != "" || e.Editor == "" {
+		group := e.Group
+		if group == "" {
+			group = EditorsEveryone
+		}
+		fmt.Fprintf(&attributes, ` w:edGrp="%s"`, group)
+	}
+
+	id := ctx.doc.nextRevisionId()
+	rangeXml := fmt.Sprintf(`<w:permStart w:id="%d"%s/>%s<w:permEnd w:id="%d"/>`, id, attributes.String(), content, id)
+	if e.Name == "" {
+		return rangeXml, nil
+	}
+	bookmarkId := ctx.doc.nextRevisionId()
+	return fmt.Sprintf(`<w:bookmarkStart w:id="%d" w:name="%s"/>%s<w:bookmarkEnd w:id="%d"/>`,
+		bookmarkId, html.EscapeString(e.Name), rangeXml, bookmarkId), nil
+}
+
+// EditableRange is an editable range of a document.
+type EditableRange struct {
+	Part   string      // Part is the name of the file inside the archive which contains the range.
+	ID     string      // ID is the identifier of the range.
+	Name   string      // Name is the name of the bookmark directly in front of the range, if any.
+	Group  EditorGroup // Group is the group of users which may edit the range.
+	Editor string      // Editor is the single user which may edit the range.
+	Text   string      // Text is the current text of the range.
+}
+
+// EditableRanges returns all editable ranges of the document body, headers and footers.
+func (d *Document) EditableRanges() []EditableRange {
+	var ranges []EditableRange
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		for _, match := range PermStartRegex.FindAllSubmatchIndex(data, -1) {
+			editable := EditableRange{Part: name}
+			for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(string(data[match[2]:match[3]]), -1) {
+				value := html.UnescapeString(attribute[2])
+				switch attribute[1] {
+				case "w:id":
+					editable.ID = value
+				case "w:edGrp":
+					editable.Group = EditorGroup(value)
+				case "w:ed":
+					editable.Editor = value
+				}
+			}
+			if bookmark := bookmarkBeforeRegex.FindSubmatch(data[:match[0]]); bookmark != nil {
+				editable.Name = html.UnescapeString(string(bookmark[1]))
+			}
+
+			end := strings.Index(string(data[match[1]:]), fmt.Sprintf(`<w:permEnd w:id="%s"`, editable.ID))
+			if end >= 0 {
+				editable.Text = elementText(data[match[1] : match[1]+end])
+			}
+			ranges = append(ranges, editable)
+		}
+	}
+	return ranges
+}
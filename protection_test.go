@@ -0,0 +1,93 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_EditableRanges(t *testing.T) {
+	settings := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:zoom w:percent="100"/><w:defaultTabStop w:val="720"/><w:compat><w:compatSetting w:name="compatibilityMode" w:val="15"/></w:compat></w:settings>`
+	doc, err := OpenBytes(buildTestDocx(t,
+		`<w:p><w:r><w:t>Signature: {signature}</w:t></w:r></w:p><w:p><w:r><w:t>Comments: {comments}</w:t></w:r></w:p>`,
+		SettingsXml, settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = doc.ReplaceAll(PlaceholderMap{
+		"signature": Editable{Name: "Signature", Editor: "jane@example.com"},
+		"comments":  Editable{Name: "Comments", Value: "none", Group: EditorsContributors},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Protect(ProtectionReadOnly); err != nil {
+		t.Fatal(err)
+	}
+
+	ranges := doc.EditableRanges()
+	if len(ranges) != 2 {
+		t.Fatalf("expected 2 editable ranges, have %+v", ranges)
+	}
+	if r := ranges[0]; r.Name != "Signature" || r.Editor != "jane@example.com" || r.Group != "" || r.Text != "" {
+		t.Errorf("unexpected signature range %+v", r)
+	}
+	if r := ranges[1]; r.Name != "Comments" || r.Group != EditorsContributors || r.Text != "none" {
+		t.Errorf("unexpected comments range %+v", r)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if !strings.Contains(document, `<w:permStart w:id="`+ranges[1].ID+`" w:edGrp="contributors"/><w:r><w:t xml:space="preserve">none</w:t></w:r><w:permEnd w:id="`+ranges[1].ID+`"/>`) {
+		t.Errorf("expected editable range in document: %s", document)
+	}
+
+	expected := `<w:zoom w:percent="100"/><w:documentProtection w:edit="readOnly" w:enforcement="1"/><w:defaultTabStop w:val="720"/>`
+	if written := readTestPart(t, buf.Bytes(), SettingsXml); !strings.Contains(written, expected) {
+		t.Errorf("expected document protection in settings: %s", written)
+	}
+}
+
+func TestDocument_ProtectCreatesSettings(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Protect(ProtectionComments); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if settings := readTestPart(t, buf.Bytes(), SettingsXml); !strings.Contains(settings, `<w:documentProtection w:edit="comments" w:enforcement="1"/></w:settings>`) {
+		t.Errorf("unexpected settings: %s", settings)
+	}
+	if rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels"); !strings.Contains(rels, RelationshipTypeSettings) {
+		t.Errorf("expected settings relationship: %s", rels)
+	}
+	if types := readTestPart(t, buf.Bytes(), ContentTypesXml); !strings.Contains(types, ContentTypeSettings) {
+		t.Errorf("expected settings content type: %s", types)
+	}
+}
+
+func TestSetOrderedElement(t *testing.T) {
+	content := `<w:zoom w:percent="100"/><w:compat><w:compatSetting w:name="x" w:val="1"/></w:compat><w14:docId w14:val="1"/>`
+	tests := []struct {
+		name, element, expected string
+	}{
+		{"zoom", `<w:zoom w:percent="90"/>`, `<w:zoom w:percent="90"/><w:compat><w:compatSetting w:name="x" w:val="1"/></w:compat><w14:docId w14:val="1"/>`},
+		{"updateFields", `<w:updateFields w:val="true"/>`, `<w:zoom w:percent="100"/><w:updateFields w:val="true"/><w:compat><w:compatSetting w:name="x" w:val="1"/></w:compat><w14:docId w14:val="1"/>`},
+		{"listSeparator", `<w:listSeparator w:val=";"/>`, `<w:zoom w:percent="100"/><w:compat><w:compatSetting w:name="x" w:val="1"/></w:compat><w:listSeparator w:val=";"/><w14:docId w14:val="1"/>`},
+	}
+	for _, test := range tests {
+		if result := setOrderedElement(content, settingsOrder, test.name, test.element); result != test.expected {
+			t.Errorf("%s: expected %s, have %s", test.name, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTemplateNotFound is returned if the registry does not contain a template with the requested name.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ReloadEvent describes a change of a template which was picked up by the TemplateRegistry.
+type ReloadEvent struct {
+	// Name is the path of the template inside the file system, e.g. 'invoices/default.docx'.
+	// It is empty if the file system could not be scanned at all.
+	Name    string
+	Removed bool // Removed is true if the template was deleted.
+	// Err is set if the changed template could not be loaded. The previous version of the template is kept in that case.
+	Err error
+}
+
+// registeredTemplate is a loaded template together with the file information used to detect changes.
+// A changed template is loaded into a new registeredTemplate, which drops its prepared version as well.
+type registeredTemplate struct {
+	data    []byte
+	modTime time.Time
+	size    int64
+
+	prepareOnce sync.Once
+	prepared    *PreparedTemplate
+	prepareErr  error
+}
+
+// TemplateRegistry holds all DOCX templates of a file system in memory and reloads them when they change,
+// so long-running services pick up template edits without a restart.
+// Changed templates are validated before they replace the previous version; all templates which changed
+// within a single reload are swapped atomically, together with their prepared versions returned by Prepared.
+// The registry is safe for concurrent use.
+//
+// Only file systems are watched. A BlobStore cannot list its objects or report their changes, so templates
+// of an object storage have to be synchronized into a directory first, e.g. by a sidecar.
+// Caches of the application, e.g. of rendered documents, are invalidated by OnReload hooks.
+//
+// Example:
+//
+//	registry := docx.NewTemplateRegistry(os.DirFS("templates"))
+//	if err := registry.Reload(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	registry.OnReload(func(event docx.ReloadEvent) {
+//	    cache.Invalidate(event.Name)
+//	})
+//	go registry.Watch(ctx, 5*time.Second)
+//
+//	doc, err := registry.Open("invoice.docx")
+type TemplateRegistry struct {
+	fsys fs.FS
+
+	// reloadMu serializes reloads, mu guards the loaded templates
+	reloadMu  sync.Mutex
+	mu        sync.RWMutex
+	templates map[string]*registeredTemplate
+
+	hooksMu sync.Mutex
+	hooks   []func(ReloadEvent)
+}
+
+// NewTemplateRegistry creates a registry for all .docx files of the file system, including subdirectories.
+// The templates are loaded with the first call of Reload or Watch.
+func NewTemplateRegistry(fsys fs.FS) *TemplateRegistry {
+	return &TemplateRegistry{
+		fsys:      fsys,
+		templates: make(map[string]*registeredTemplate),
+	}
+}
+
+// OnReload registers a hook which is called for every template which was added, changed or removed.
+// Hooks can be used to invalidate caches of rendered documents or prepared templates.
+func (r *TemplateRegistry) OnReload(hook func(event ReloadEvent)) {
+	r.hooksMu.Lock()
+	defer r.hooksMu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Names returns the names of all loaded templates in lexical order.
+func (r *TemplateRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bytes returns the current content of the template with the given name.
+// The returned slice is shared and must not be modified.
+func (r *TemplateRegistry) Bytes(name string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	template, exists := r.templates[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return template.data, nil
+}
+
+// Open opens the current version of the template with the given name as Document.
+func (r *TemplateRegistry) Open(name string) (*Document, error) {
+	data, err := r.Bytes(name)
+	if err != nil {
+		return nil, err
+	}
+	return OpenBytes(data)
+}
+
+// Prepared returns the current version of the template with the given name as PreparedTemplate.
+// The template is prepared once per version; after a reload changed the template, it is prepared again.
+func (r *TemplateRegistry) Prepared(name string) (*PreparedTemplate, error) {
+	r.mu.RLock()
+	template, exists := r.templates[name]
+	r.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	template.prepareOnce.Do(func() {
+		template.prepared, template.prepareErr = Prepare(template.data)
+	})
+	return template.prepared, template.prepareErr
+}
+
+// Reload checks all templates of the file system for changes and reloads the changed ones.
+// Templates which cannot be read or are not valid DOCX files keep their previous version and are reported
+// to the hooks and the returned error. If the file system cannot be scanned, all templates are kept.
+func (r *TemplateRegistry) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	r.mu.RLock()
+	current := r.templates
+	r.mu.RUnlock()
+
+	next := make(map[string]*registeredTemplate, len(current))
+	var events []ReloadEvent
+	var loadErrors []error
+
+	err := fs.WalkDir(r.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(path.Ext(name), ".docx") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		previous, known := current[name]
+		if known && previous.modTime.Equal(info.ModTime()) && previous.size == info.Size() {
+			next[name] = previous
+			return nil
+		}
+
+		template, err := r.load(name, info)
+		if err != nil {
+			events = append(events, ReloadEvent{Name: name, Err: err})
+			loadErrors = append(loadErrors, err)
+			if known {
+				next[name] = previous
+			}
+			return nil
+		}
+		next[name] = template
+		events = append(events, ReloadEvent{Name: name})
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("unable to scan templates: %w", err)
+		r.notify([]ReloadEvent{{Err: err}})
+		return err
+	}
+
+	for name := range current {
+		if _, exists := next[name]; !exists {
+			events = append(events, ReloadEvent{Name: name, Removed: true})
+		}
+	}
+
+	r.mu.Lock()
+	r.templates = next
+	r.mu.Unlock()
+
+	r.notify(events)
+	return errors.Join(loadErrors...)
+}
+
+// notify calls all hooks for every event.
+func (r *TemplateRegistry) notify(events []ReloadEvent) {
+	r.hooksMu.Lock()
+	hooks := append([]func(ReloadEvent){}, r.hooks...)
+	r.hooksMu.Unlock()
+	for _, event := range events {
+		for _, hook := range hooks {
+			hook(event)
+		}
+	}
+}
+
+// load reads and validates a single template.
+func (r *TemplateRegistry) load(name string, info fs.FileInfo) (*registeredTemplate, error) {
+	data, err := fs.ReadFile(r.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read template %s: %w", name, err)
+	}
+	doc, err := OpenBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	doc.Close()
+	return &registeredTemplate{data: data, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// Watch reloads the templates immediately and then polls the file system for changes in the given interval
+// until the context is cancelled. Errors of individual reloads are reported to the OnReload hooks.
+// Watch blocks, so it is usually started in its own goroutine. It returns the error of the context,
+// or an error without reloading if the interval is not positive.
+func (r *TemplateRegistry) Watch(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid watch interval %s: must be positive", interval)
+	}
+	_ = r.Reload()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_ = r.Reload()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package docx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTemplateRegistry_Reload(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"invoice.docx":         {Data: buildTestDocx(t, `<w:p><w:r><w:t>{version} 1</w:t></w:r></w:p>`), ModTime: start},
+		"letters/welcome.docx": {Data: buildTestDocx(t, `<w:p><w:r><w:t>welcome</w:t></w:r></w:p>`), ModTime: start},
+		"readme.txt":           {Data: []byte("not a template"), ModTime: start},
+	}
+
+	registry := NewTemplateRegistry(fsys)
+	var events []ReloadEvent
+	registry.OnReload(func(event ReloadEvent) {
+		events = append(events, event)
+	})
+
+	if err := registry.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if names := strings.Join(registry.Names(), ","); names != "invoice.docx,letters/welcome.docx" {
+		t.Fatalf("unexpected templates: %s", names)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 load events, have %+v", events)
+	}
+
+	// unchanged templates are not reloaded
+	events = nil
+	if err := registry.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, have %+v", events)
+	}
+
+	// changed, broken and removed templates
+	fsys["invoice.docx"] = &fstest.MapFile{Data: buildTestDocx(t, `<w:p><w:r><w:t>{version} 2</w:t></w:r></w:p>`), ModTime: start.Add(time.Minute)}
+	fsys["broken.docx"] = &fstest.MapFile{Data: []byte("broken"), ModTime: start}
+	delete(fsys, "letters/welcome.docx")
+
+	err := registry.Reload()
+	if err == nil || !strings.Contains(err.Error(), "broken.docx") {
+		t.Fatalf("expected error for broken template, have %v", err)
+	}
+	if names := strings.Join(registry.Names(), ","); names != "invoice.docx" {
+		t.Fatalf("unexpected templates: %s", names)
+	}
+
+	doc, err := registry.Open("invoice.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc.GetFile(DocumentXml)), "{version} 2") {
+		t.Errorf("expected the changed template to be loaded")
+	}
+
+	var changed, failed, removed bool
+	for _, event := range events {
+		switch {
+		case event.Name == "invoice.docx" && event.Err == nil:
+			changed = true
+		case event.Name == "broken.docx" && event.Err != nil:
+			failed = true
+		case event.Name == "letters/welcome.docx" && event.Removed:
+			removed = true
+		}
+	}
+	if !changed || !failed || !removed {
+		t.Errorf("missing events: %+v", events)
+	}
+
+	if _, err := registry.Bytes("letters/welcome.docx"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, have %v", err)
+	}
+}
+
+func TestTemplateRegistry_KeepsPreviousVersionOnError(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	valid := buildTestDocx(t, `<w:p><w:r><w:t>valid</w:t></w:r></w:p>`)
+	fsys := fstest.MapFS{"contract.docx": {Data: valid, ModTime: start}}
+
+	registry := NewTemplateRegistry(fsys)
+	if err := registry.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys["contract.docx"] = &fstest.MapFile{Data: []byte("half written"), ModTime: start.Add(time.Second)}
+	if err := registry.Reload(); err == nil {
+		t.Fatal("expected an error for the invalid template")
+	}
+
+	data, err := registry.Bytes("contract.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(valid) {
+		t.Errorf("expected the previous version to be kept")
+	}
+}
+
+func TestTemplateRegistry_Prepared(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{"invoice.docx": {Data: buildTestDocx(t, `<w:p><w:r><w:t>{version} 1</w:t></w:r></w:p>`), ModTime: start}}
+	registry := NewTemplateRegistry(fsys)
+	if err := registry.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := registry.Prepared("invoice.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := registry.Prepared("invoice.docx"); again != first {
+		t.Errorf("expected the prepared template to be reused")
+	}
+
+	fsys["invoice.docx"] = &fstest.MapFile{Data: buildTestDocx(t, `<w:p><w:r><w:t>{version} 2</w:t></w:r></w:p>`), ModTime: start.Add(time.Minute)}
+	if err := registry.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	prepared, err := registry.Prepared("invoice.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prepared == first {
+		t.Fatal("expected the changed template to be prepared again")
+	}
+	output, err := prepared.Render(PlaceholderMap{"version": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "v 2") {
+		t.Errorf("expected the changed template to be rendered: %s", document)
+	}
+
+	if _, err := registry.Prepared("missing.docx"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, have %v", err)
+	}
+}
+
+func TestTemplateRegistry_WatchInvalidInterval(t *testing.T) {
+	registry := NewTemplateRegistry(fstest.MapFS{})
+	if err := registry.Watch(context.Background(), 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
+
+func TestTemplateRegistry_Watch(t *testing.T) {
+	fsys := fstest.MapFS{"a.docx": {Data: buildTestDocx(t, `<w:p/>`), ModTime: time.Now()}}
+	registry := NewTemplateRegistry(fsys)
+
+	loaded := make(chan struct{}, 1)
+	registry.OnReload(func(event ReloadEvent) {
+		if event.Name == "a.docx" {
+			select {
+			case loaded <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- registry.Watch(ctx, time.Millisecond)
+	}()
+
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("template was not loaded")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, have %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrInvalidRelationships is returned when writing a document whose relationships Word would repair,
+	// the error is a *RelationshipError.
+	ErrInvalidRelationships = errors.New("invalid relationships")
+
+	// relationshipIdAttributeRegex matches the Id attribute of a relationship and captures the ID.
+	relationshipIdAttributeRegex = regexp.MustCompile(`\sId="([^"]*)"`)
+)
+
+// Relationship is a relationship of a part to another part or to an external resource, e.g. of the main document
+// to an image or a hyperlink target.
+type Relationship struct {
+	ID   string
+	Type string
+	// Target is the name of the related part relative to the directory of the source part, or the URL of
+	// external relationships.
+	Target   string
+	External bool
+}
+
+// RelationshipError describes relationships which Word considers as corrupt.
+type RelationshipError struct {
+	// Part is the relationships part which contains the duplicate ID, or the part which references the missing ID.
+	Part string
+	ID   string
+	// Duplicate is true if several relationships with different targets use the ID, false if a relationship with
+	// the ID is referenced but does not exist.
+	Duplicate bool
+}
+
+// Error describes the invalid relationship.
+func (e *RelationshipError) Error() string {
+	if e.Duplicate {
+		return fmt.Sprintf("%s: %s is used by different relationships of %s", ErrInvalidRelationships, e.ID, e.Part)
+	}
+	return fmt.Sprintf("%s: %s references %s which does not exist", ErrInvalidRelationships, e.Part, e.ID)
+}
+
+// Unwrap returns ErrInvalidRelationships.
+func (e *RelationshipError) Unwrap() error {
+	return ErrInvalidRelationships
+}
+
+// Relationships returns the relationships of the given part (e.g. 'word/document.xml') in the order of its
+// relationships part, including the relationships added by replacements. Parts without relationships have none.
+func (d *Document) Relationships(part string) ([]Relationship, error) {
+	rels, _, err := d.part(relationshipsPart(part))
+	if err != nil {
+		return nil, err
+	}
+	var relationships []Relationship
+	for _, rel := range parseRelationships(rels) {
+		relationships = append(relationships, Relationship{ID: rel.id, Type: rel.relType, Target: rel.target, External: rel.external})
+	}
+	return relationships, nil
+}
+
+// CompactRelationships renumbers the relationships of the body, headers, footers and notes as rId1, rId2, ...
+// in the order of their relationships parts and updates all references, e.g. after many values were inserted
+// and removed again. Duplicates of the same relationship are merged.
+func (d *Document) CompactRelationships() error {
+	for _, name := range d.xmlFiles() {
+		if err := d.normalizeRelationships(name, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRelationships checks the relationships of the body, headers, footers and notes before the document is
+// written: different relationships must not share an ID and every referenced relationship must exist.
+// Duplicates of the same relationship, e.g. from merged content, are merged.
+func (d *Document) validateRelationships() error {
+	for _, name := range d.xmlFiles() {
+		if err := d.normalizeRelationships(name, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeRelationships validates the relationships of the source part and merges duplicates of the same
+// relationship. If compact is true, the relationships are renumbered and the references of source are updated.
+func (d *Document) normalizeRelationships(source string, compact bool) error {
+	relsName := relationshipsPart(source)
+	rels, exists, err := d.part(relsName)
+	if err != nil {
+		return err
+	}
+	data := string(d.files[source])
+
+	// duplicates of the same relationship are merged, different relationships must not share an ID
+	matches := RelationshipRegex.FindAllStringIndex(string(rels), -1)
+	relationships := parseRelationships(rels)
+	known := make(map[string]relationship, len(relationships))
+	duplicate := make([]bool, len(relationships))
+	for i, rel := range relationships {
+		if first, exists := known[rel.id]; exists {
+			if first != rel {
+				return &RelationshipError{Part: relsName, ID: rel.id, Duplicate: true}
+			}
+			duplicate[i] = true
+			continue
+		}
+		known[rel.id] = rel
+	}
+	// every referenced relationship must exist
+	for _, match := range RelationshipReferenceRegex.FindAllStringSubmatch(data, -1) {
+		if _, exists := known[html.UnescapeString(match[2])]; !exists {
+			return &RelationshipError{Part: source, ID: html.UnescapeString(match[2])}
+		}
+	}
+	if !exists {
+		return nil
+	}
+
+	// the relationships part is rebuilt without the duplicates and, if compacting, with the new IDs
+	ids := make(map[string]string, len(known))
+	var changed strings.Builder
+	pos := 0
+	for i, match := range matches {
+		changed.WriteString(string(rels[pos:match[0]]))
+		pos = match[1]
+		if duplicate[i] {
+			continue
+		}
+		element := string(rels[match[0]:match[1]])
+		if compact {
+			id := fmt.Sprintf("rId%d", len(ids)+1)
+			ids[relationships[i].id] = id
+			element = relationshipIdAttributeRegex.ReplaceAllLiteralString(element, ` Id="`+id+`"`)
+		}
+		changed.WriteString(element)
+	}
+	changed.WriteString(string(rels[pos:]))
+	if changed.String() != string(rels) {
+		if err := d.setPart(relsName, []byte(changed.String())); err != nil {
+			return err
+		}
+	}
+
+	if !compact {
+		return nil
+	}
+	delete(d.relIds, relsName)
+	renumbered := RelationshipReferenceRegex.ReplaceAllStringFunc(data, func(reference string) string {
+		groups := RelationshipReferenceRegex.FindStringSubmatch(reference)
+		return groups[1] + ids[html.UnescapeString(groups[2])] + groups[3]
+	})
+	if renumbered == data {
+		return nil
+	}
+	if err := d.SetFile(source, []byte(renumbered)); err != nil {
+		return err
+	}
+	return d.parseFile(source)
+}
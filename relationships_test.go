@@ -0,0 +1,99 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testRelationshipsPart(relationships string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		relationships + `</Relationships>`
+}
+
+func TestDocument_CompactRelationships(t *testing.T) {
+	rels := testRelationshipsPart(`<Relationship Id="rId3" Type="` + RelationshipTypeStyles + `" Target="styles.xml"/>` +
+		`<Relationship Id="rId7" Type="` + RelationshipTypeHyperlink + `" Target="https://example.com" TargetMode="External"/>` +
+		`<Relationship Id="rId12" Type="` + RelationshipTypeImage + `" Target="media/image1.png"/>` +
+		`<Relationship Id="rId7" Type="` + RelationshipTypeHyperlink + `" Target="https://example.com" TargetMode="External"/>`)
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:hyperlink r:id="rId7"><w:r><w:t>{a}</w:t></w:r></w:hyperlink></w:p>`+
+		`<w:p><w:r><w:drawing><a:blip r:embed="rId12"/></w:drawing></w:r></w:p>`, "word/_rels/document.xml.rels", rels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.CompactRelationships(); err != nil {
+		t.Fatal(err)
+	}
+	// IDs of added relationships follow the renumbered ones and placeholders are still found
+	if err := doc.ReplaceAll(PlaceholderMap{"a": Hyperlink{Text: "link", URL: "https://example.org"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	relationships, err := doc.Relationships(DocumentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Relationship{
+		{ID: "rId1", Type: RelationshipTypeStyles, Target: "styles.xml"},
+		{ID: "rId2", Type: RelationshipTypeHyperlink, Target: "https://example.com", External: true},
+		{ID: "rId3", Type: RelationshipTypeImage, Target: "media/image1.png"},
+		{ID: "rId4", Type: RelationshipTypeHyperlink, Target: "https://example.org", External: true},
+	}
+	if len(relationships) != len(expected) {
+		t.Fatalf("expected %d relationships, have %+v", len(expected), relationships)
+	}
+	for i := range expected {
+		if relationships[i] != expected[i] {
+			t.Errorf("expected relationship %+v, have %+v", expected[i], relationships[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, reference := range []string{`<w:hyperlink r:id="rId2">`, `<a:blip r:embed="rId3"/>`, `<w:hyperlink r:id="rId4" w:history="1">`} {
+		if !strings.Contains(document, reference) {
+			t.Errorf("expected %s in document: %s", reference, document)
+		}
+	}
+}
+
+func TestDocument_Write_InvalidRelationships(t *testing.T) {
+	link := `<Relationship Id="rId1" Type="` + RelationshipTypeHyperlink + `" Target="https://example.com" TargetMode="External"/>`
+	for _, tc := range []struct {
+		name          string
+		body, rels    string
+		expectedError *RelationshipError
+	}{
+		{"valid", `<w:p><w:hyperlink r:id="rId1"/></w:p>`, link, nil},
+		{"same relationship twice", `<w:p><w:hyperlink r:id="rId1"/></w:p>`, link + link, nil},
+		{"missing", `<w:p><w:hyperlink r:id="rId2"/></w:p>`, link, &RelationshipError{Part: DocumentXml, ID: "rId2"}},
+		{"collision", `<w:p/>`, link + strings.Replace(link, "example.com", "example.org", 1),
+			&RelationshipError{Part: "word/_rels/document.xml.rels", ID: "rId1", Duplicate: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := OpenBytes(buildTestDocx(t, tc.body, "word/_rels/document.xml.rels", testRelationshipsPart(tc.rels)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			err = doc.Write(&buf)
+			var relErr *RelationshipError
+			switch {
+			case tc.expectedError == nil && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tc.expectedError == nil:
+				if rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels"); strings.Count(rels, "<Relationship ") != 1 {
+					t.Errorf("expected a single relationship: %s", rels)
+				}
+			case !errors.As(err, &relErr) || !errors.Is(err, ErrInvalidRelationships):
+				t.Errorf("expected relationship error, have %v", err)
+			case *relErr != *tc.expectedError:
+				t.Errorf("expected %+v, have %+v", tc.expectedError, relErr)
+			}
+		})
+	}
+}
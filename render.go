@@ -0,0 +1,278 @@
+package docx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Source provides the DOCX template rendered by Render, see FromBytes, FromFile, FromReader and FromRegistry.
+type Source interface {
+	// Load returns the content of the DOCX archive.
+	Load() ([]byte, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func() ([]byte, error)
+
+// Load calls the function.
+func (f SourceFunc) Load() ([]byte, error) {
+	return f()
+}
+
+// FromBytes returns a Source of the DOCX archive given by input.
+func FromBytes(input []byte) Source {
+	return SourceFunc(func() ([]byte, error) {
+		return input, nil
+	})
+}
+
+// FromFile returns a Source which reads the DOCX archive from the file at path when the template is rendered.
+func FromFile(path string) Source {
+	return SourceFunc(func() ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
+
+// FromFS returns a Source which reads the DOCX file with the given name from the file system, e.g. an embed.FS,
+// when the template is rendered.
+func FromFS(fsys fs.FS, name string) Source {
+	return SourceFunc(func() ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	})
+}
+
+// FromReader returns a Source which reads the DOCX archive from r when the template is rendered.
+func FromReader(r io.Reader) Source {
+	return SourceFunc(func() ([]byte, error) {
+		return io.ReadAll(r)
+	})
+}
+
+// FromRegistry returns a Source of the template with the given name in the registry, see TemplateRegistry.Bytes.
+func FromRegistry(registry *TemplateRegistry, name string) Source {
+	return SourceFunc(func() ([]byte, error) {
+		return registry.Bytes(name)
+	})
+}
+
+// Syntax is the syntax of the template rendered by Render.
+type Syntax int
+
+const (
+	// SyntaxAuto renders templates containing template actions ({{ ... }}) like ProcessTemplateDocx and all other
+	// templates like ProcessValues.
+	SyntaxAuto Syntax = iota
+	// SyntaxPlaceholders replaces placeholders like {name} like ProcessValues, the data must be a map.
+	SyntaxPlaceholders
+	// SyntaxTemplate renders the template like ProcessTemplateDocx.
+	SyntaxTemplate
+)
+
+// Option configures a single call of Render.
+type Option func(*renderConfig)
+
+// renderConfig is the configuration of a single call of Render.
+type renderConfig struct {
+	ctx        context.Context
+	pool       *Pool
+	syntax     Syntax
+	options    RenderOptions
+	middleware []Middleware
+}
+
+// WithContext aborts the render once the context is done.
+func WithContext(ctx context.Context) Option {
+	return func(c *renderConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithPool renders with a worker of the pool, whose options are the defaults of the render.
+func WithPool(pool *Pool) Option {
+	return func(c *renderConfig) {
+		c.pool = pool
+	}
+}
+
+// WithSyntax sets the syntax of the template instead of detecting it, see SyntaxAuto.
+func WithSyntax(syntax Syntax) Option {
+	return func(c *renderConfig) {
+		c.syntax = syntax
+	}
+}
+
+// WithOptions sets all options at once, options given after it override single options.
+func WithOptions(options RenderOptions) Option {
+	return func(c *renderConfig) {
+		c.options = options
+	}
+}
+
+// WithTimeout limits the duration of the render, see RenderOptions.Timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *renderConfig) {
+		c.options.Timeout = timeout
+	}
+}
+
+// WithLocale sets the locale of the rendered document, see RenderOptions.Locale.
+func WithLocale(locale string) Option {
+	return func(c *renderConfig) {
+		c.options.Locale = locale
+	}
+}
+
+// WithTemplateLocale uses the language of the template document as locale, see RenderOptions.TemplateLocale.
+func WithTemplateLocale() Option {
+	return func(c *renderConfig) {
+		c.options.TemplateLocale = Bool(true)
+	}
+}
+
+// WithFuncs makes the functions available to the template, see ProcessTemplateDocxWithFuncs.
+// It may be given multiple times, later functions replace earlier functions with the same name.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(c *renderConfig) {
+		c.options.Funcs = RenderOptions{Funcs: c.options.Funcs}.merge(&RenderOptions{Funcs: funcs}).Funcs
+	}
+}
+
+// WithCleanup removes the empty paragraphs and table rows left behind, see RenderOptions.Cleanup.
+func WithCleanup(cleanup CleanupOptions) Option {
+	return func(c *renderConfig) {
+		c.options.Cleanup = cleanup
+	}
+}
+
+// WithConformance sets the conformance class of the rendered document, see RenderOptions.Conformance.
+func WithConformance(conformance Conformance) Option {
+	return func(c *renderConfig) {
+		c.options.Conformance = conformance
+	}
+}
+
+// WithOutputLimits restricts the complexity of the rendered document, see RenderOptions.OutputLimits.
+func WithOutputLimits(limits OutputLimits) Option {
+	return func(c *renderConfig) {
+		c.options.OutputLimits = limits
+	}
+}
+
+// WithStrictValues only accepts strings as placeholder values, see RenderOptions.StrictValues.
+func WithStrictValues() Option {
+	return func(c *renderConfig) {
+		c.options.StrictValues = Bool(true)
+	}
+}
+
+// WithMissingKey sets what templates write for fields which are missing in the data, see MissingKeyPolicy.
+func WithMissingKey(policy MissingKeyPolicy) Option {
+	return func(c *renderConfig) {
+		c.options.MissingKey = policy
+	}
+}
+
+// Render renders the template given by src with data and returns the resulting DOCX archive. It is the single
+// entry point for both template syntaxes:
+//   - templates with template actions ({{ ... }}) are rendered like ProcessTemplateDocx, data may be of any type.
+//   - all other templates have their placeholders replaced like ProcessValues, data must be a PlaceholderMap,
+//     a map[string]interface{} or a map[string]string.
+//
+// The syntax is detected from the template unless it is set with WithSyntax. ProcessBytes, ProcessValues,
+// ProcessTemplateDocx and ProcessTemplateDocxWithFuncs remain available and behave like Render with the
+// corresponding options.
+//
+// Example:
+//
+//	output, err := docx.Render(docx.FromFile("invoice.docx"), invoice,
+//	    docx.WithLocale("de-DE"),
+//	    docx.WithTimeout(10*time.Second),
+//	    docx.WithFuncs(template.FuncMap{"upper": strings.ToUpper}),
+//	)
+func Render(src Source, data interface{}, opts ...Option) ([]byte, error) {
+	config := renderConfig{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	input, err := src.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load template: %w", err)
+	}
+
+	pool := config.pool
+	if pool == nil {
+		pool = NewPool(1, RenderOptions{})
+	}
+	render := func(request *RenderRequest) ([]byte, error) {
+		syntax := request.Syntax
+		if syntax == SyntaxAuto {
+			detected, err := detectSyntax(request.Template)
+			if err != nil {
+				return nil, err
+			}
+			syntax = detected
+		}
+		if syntax == SyntaxTemplate {
+			return pool.Render(request.Context, request.Template, request.Data, &request.Options)
+		}
+
+		values, err := placeholderValues(request.Data)
+		if err != nil {
+			return nil, err
+		}
+		return pool.run(request.Context, &request.Options, func(options RenderOptions) ([]byte, error) {
+			return ProcessValues(request.Template, values, options)
+		})
+	}
+	// the first middleware is the outermost, it sees the request first and the document last
+	for i := len(config.middleware) - 1; i >= 0; i-- {
+		render = config.middleware[i](render)
+	}
+	return render(&RenderRequest{
+		Context:  config.ctx,
+		Template: input,
+		Data:     data,
+		Syntax:   config.syntax,
+		Options:  config.options,
+	})
+}
+
+// detectSyntax returns SyntaxTemplate if a part of the template contains a template action, otherwise
+// SyntaxPlaceholders.
+func detectSyntax(input []byte) (Syntax, error) {
+	parts, err := readArchiveParts(input, isTemplatePart)
+	if err != nil {
+		return SyntaxAuto, err
+	}
+	for _, part := range parts {
+		if strings.Contains(prepareTemplateSource(part), TemplateOpenDelimiter) {
+			return SyntaxTemplate, nil
+		}
+	}
+	return SyntaxPlaceholders, nil
+}
+
+// placeholderValues returns the data of a render as placeholder values.
+func placeholderValues(data interface{}) (PlaceholderMap, error) {
+	switch values := data.(type) {
+	case PlaceholderMap:
+		return values, nil
+	case map[string]interface{}:
+		return values, nil
+	case map[string]string:
+		converted := make(PlaceholderMap, len(values))
+		for key, value := range values {
+			converted[key] = value
+		}
+		return converted, nil
+	case nil:
+		return PlaceholderMap{}, nil
+	}
+	return nil, fmt.Errorf("%w: placeholders need a map of values, not %T", ErrUnsupportedValue, data)
+}
@@ -0,0 +1,43 @@
+package docx
+
+import "context"
+
+// RenderRequest is a single call of Render as seen by middleware.
+type RenderRequest struct {
+	Context context.Context
+	// Template is the loaded DOCX archive of the template.
+	Template []byte
+	// Data are the values of the render, see Render.
+	Data interface{}
+	// Syntax is the syntax of the template, SyntaxAuto detects it when the request reaches the renderer.
+	Syntax  Syntax
+	Options RenderOptions
+}
+
+// RenderFunc renders the request and returns the DOCX archive of the document.
+type RenderFunc func(request *RenderRequest) ([]byte, error)
+
+// Middleware wraps the render of every call of Render, e.g. to log renders, validate the data, redact values or
+// post-process the document. It may change the request before calling next, inspect or replace the document
+// returned by next, or return an error without calling next at all.
+type Middleware func(next RenderFunc) RenderFunc
+
+// WithMiddleware wraps the render with the middleware, the first middleware sees the request first and the
+// document last. It may be given multiple times, the middleware is appended.
+//
+// Example:
+//
+//	logging := func(next docx.RenderFunc) docx.RenderFunc {
+//	    return func(request *docx.RenderRequest) ([]byte, error) {
+//	        start := time.Now()
+//	        output, err := next(request)
+//	        log.Printf("rendered %d bytes in %s: %v", len(output), time.Since(start), err)
+//	        return output, err
+//	    }
+//	}
+//	output, err := docx.Render(docx.FromFile("invoice.docx"), invoice, docx.WithMiddleware(logging, validation))
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *renderConfig) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
@@ -0,0 +1,63 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRender_Middleware(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{name} pays {amount}</w:t></w:r></w:p>`)
+
+	var calls []string
+	logging := func(next RenderFunc) RenderFunc {
+		return func(request *RenderRequest) ([]byte, error) {
+			calls = append(calls, "logging")
+			output, err := next(request)
+			calls = append(calls, "logged")
+			return output, err
+		}
+	}
+	validation := func(next RenderFunc) RenderFunc {
+		return func(request *RenderRequest) ([]byte, error) {
+			calls = append(calls, "validation")
+			if _, ok := request.Data.(PlaceholderMap)["name"]; !ok {
+				return nil, errors.New("name is required")
+			}
+			return next(request)
+		}
+	}
+	redaction := func(next RenderFunc) RenderFunc {
+		return func(request *RenderRequest) ([]byte, error) {
+			calls = append(calls, "redaction")
+			values := PlaceholderMap{}
+			for key, value := range request.Data.(PlaceholderMap) {
+				values[key] = value
+			}
+			values["amount"] = "***"
+			request.Data = values
+			request.Options.Locale = "de-DE"
+			return next(request)
+		}
+	}
+
+	output, err := Render(FromBytes(input), PlaceholderMap{"name": "Jane", "amount": 5}, WithMiddleware(logging, validation), WithMiddleware(redaction))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "Jane pays ***") {
+		t.Errorf("expected the redacted amount in %s", document)
+	}
+	if expected := "logging validation redaction logged"; strings.Join(calls, " ") != expected {
+		t.Errorf("expected calls %s, have %v", expected, calls)
+	}
+
+	calls = nil
+	if _, err := Render(FromBytes(input), PlaceholderMap{}, WithMiddleware(logging, validation, redaction)); err == nil ||
+		err.Error() != "name is required" {
+		t.Errorf("expected the validation error, have %v", err)
+	}
+	if expected := "logging validation logged"; strings.Join(calls, " ") != expected {
+		t.Errorf("expected calls %s, have %v", expected, calls)
+	}
+}
@@ -0,0 +1,91 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	placeholders := buildTestDocx(t, `<w:p><w:r><w:t>{name} owes {amount}</w:t></w:r></w:p>`)
+	templated := buildTestDocx(t, `<w:p><w:r><w:t>{{upper .Name}} owes {{.Amount}}</w:t></w:r></w:p>`)
+	path := filepath.Join(t.TempDir(), "template.docx")
+	if err := os.WriteFile(path, placeholders, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		src      Source
+		data     interface{}
+		opts     []Option
+		expected string
+	}{
+		{"placeholders", FromBytes(placeholders), PlaceholderMap{"name": "Jane", "amount": 1234.5}, []Option{WithLocale("de-DE")}, "Jane owes 1.234,5"},
+		{"string map", FromFile(path), map[string]string{"name": "Jane", "amount": "5"}, nil, "Jane owes 5"},
+		{"fs", FromFS(os.DirFS(filepath.Dir(path)), "template.docx"), PlaceholderMap{"name": "Jane", "amount": 5}, nil, "Jane owes 5"},
+		{"template", FromReader(bytes.NewReader(templated)), struct{ Name, Amount string }{"Jane", "5"},
+			[]Option{WithFuncs(template.FuncMap{"upper": strings.ToUpper})}, "JANE owes 5"},
+		{"pool", FromBytes(templated), map[string]string{"Name": "jane", "Amount": "5"},
+			[]Option{WithPool(NewPool(1, RenderOptions{Funcs: template.FuncMap{"upper": strings.ToLower}}))}, "jane owes 5"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := Render(test.src, test.data, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text := elementText([]byte(readTestPart(t, output, DocumentXml))); text != test.expected {
+				t.Errorf("expected %s, have %s", test.expected, text)
+			}
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	placeholders := buildTestDocx(t, `<w:p><w:r><w:t>{name}</w:t></w:r></w:p>`)
+	if _, err := Render(FromBytes(placeholders), struct{ Name string }{"Jane"}); !errors.Is(err, ErrUnsupportedValue) {
+		t.Errorf("expected ErrUnsupportedValue for struct data, have %v", err)
+	}
+	if _, err := Render(FromBytes(placeholders), PlaceholderMap{"name": 1}, WithStrictValues()); !errors.Is(err, ErrUnsupportedValue) {
+		t.Errorf("expected ErrUnsupportedValue with strict values, have %v", err)
+	}
+	if _, err := Render(FromFile(filepath.Join(t.TempDir(), "missing.docx")), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, have %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Render(FromBytes(placeholders), nil, WithContext(ctx), WithTimeout(time.Second)); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, have %v", err)
+	}
+}
+
+func TestRender_Syntax(t *testing.T) {
+	// without WithSyntax, the template action would be detected and {name} would stay
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{name} {{.Name}}</w:t></w:r></w:p>`)
+	output, err := Render(FromBytes(input), PlaceholderMap{"name": "Jane"}, WithSyntax(SyntaxPlaceholders))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "<w:t>Jane {{.Name}}</w:t>") {
+		t.Errorf("expected the placeholder to be replaced in %s", document)
+	}
+}
+
+func TestRender_LocaleWithDelimiters(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Note}}</w:t></w:r></w:p>`)
+
+	output, err := Render(FromBytes(input), map[string]interface{}{"Note": "a } b { c"}, WithLocale("de-DE"), WithCleanup(CleanupOptions{CollapseEmptyParagraphs: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "a } b { c") {
+		t.Errorf("expected the text with delimiters: %s", document)
+	}
+}
@@ -0,0 +1,413 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// RepairKind is the kind of a defect fixed by Repair.
+type RepairKind string
+
+const (
+	// RepairPartName is a part whose name is not a valid part name, e.g. with backslashes as separators or encoded
+	// in a legacy code page instead of UTF-8.
+	RepairPartName RepairKind = "part-name"
+	// RepairContentType is a missing content types part, a part without content type or an override of the content
+	// types part which is duplicate, generic or refers to a part which does not exist.
+	RepairContentType RepairKind = "content-type"
+	// RepairRelationship is a relationship whose ID is used several times or whose target uses backslashes.
+	RepairRelationship RepairKind = "relationship"
+)
+
+const (
+	// ContentTypeMainDocument is the content type of the main document part.
+	ContentTypeMainDocument = "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"
+	// RelationshipTypeOfficeDocument is the relationship type of the main document part, its source is the package.
+	RelationshipTypeOfficeDocument = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	// ContentTypeRelationships is the content type of relationships parts.
+	ContentTypeRelationships = "application/vnd.openxmlformats-package.relationships+xml"
+
+	// emptyContentTypes is the content types part which is created if an archive has none.
+	emptyContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"></Types>`
+)
+
+var (
+	// contentTypeEntryRegex matches the Default and Override entries of the content types part and captures the
+	// element name and the attributes.
+	contentTypeEntryRegex = regexp.MustCompile(`<(Default|Override)\s([^>]*?)/?>(?:</(?:Default|Override)>)?`)
+	// relationshipTargetRegex matches the Target attribute of a relationship and captures the target.
+	relationshipTargetRegex = regexp.MustCompile(`\sTarget="([^"]*)"`)
+
+	// partContentTypes are the content types of the well-known parts of DOCX archives.
+	partContentTypes = map[string]string{
+		StylesXml:              ContentTypeStyles,
+		SettingsXml:            ContentTypeSettings,
+		FontTableXml:           ContentTypeFontTable,
+		NumberingXml:           ContentTypeNumbering,
+		FootnotesXml:           "application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml",
+		EndnotesXml:            "application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml",
+		"word/comments.xml":    "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml",
+		"word/webSettings.xml": "application/vnd.openxmlformats-officedocument.wordprocessingml.webSettings+xml",
+		CorePropertiesXml:      ContentTypeCoreProperties,
+		"docProps/app.xml":     "application/vnd.openxmlformats-officedocument.extended-properties+xml",
+		CustomPropertiesXml:    ContentTypeCustomProperties,
+	}
+	// extensionContentTypes are the content types of parts without override by file extension.
+	extensionContentTypes = map[string]string{
+		"rels":  ContentTypeRelationships,
+		"xml":   "application/xml",
+		"png":   "image/png",
+		"jpeg":  "image/jpeg",
+		"jpg":   "image/jpeg",
+		"gif":   "image/gif",
+		"bmp":   "image/bmp",
+		"webp":  "image/webp",
+		"tif":   "image/tiff",
+		"tiff":  "image/tiff",
+		"svg":   "image/svg+xml",
+		"emf":   "image/x-emf",
+		"wmf":   "image/x-wmf",
+		"odttf": ContentTypeObfuscatedFont,
+		"bin":   "application/vnd.openxmlformats-officedocument.oleObject",
+	}
+)
+
+// RepairFix is a single defect fixed by Repair.
+type RepairFix struct {
+	Kind RepairKind
+	// Part is the name of the part which was fixed, after its name was repaired.
+	Part    string
+	Message string
+}
+
+// String returns the message of the fix.
+func (f RepairFix) String() string {
+	return fmt.Sprintf("%s: %s", f.Kind, f.Message)
+}
+
+// RepairReport lists the defects fixed by Repair, it is empty if the archive had none.
+type RepairReport struct {
+	Fixes []RepairFix
+}
+
+// repairPart is a part of the archive which is repaired.
+type repairPart struct {
+	name string
+	data []byte
+	file *zip.File
+}
+
+// Repair fixes common defects of DOCX archives created by third-party tools, e.g. templates uploaded by customers,
+// so they can be processed:
+//
+//   - part names with backslashes, a leading slash or in a legacy code page (CP437) instead of UTF-8 are renamed
+//   - a missing content types part is created and parts without content type are added to it
+//   - overrides of the content types part which are duplicate, refer to missing parts or declare a generic content
+//     type for a well-known part (e.g. application/xml for the main document) are fixed
+//   - relationships which are duplicate are removed, those sharing the ID of a different relationship get a new ID
+//     and targets with backslashes are fixed
+//
+// The archive is returned unchanged if it has no such defects. Archives which are no ZIP archive at all cannot be
+// repaired, ErrInvalidArchive is returned for them.
+//
+// Example:
+//
+//	repaired, report, err := docx.Repair(uploadBytes)
+//	for _, fix := range report.Fixes {
+//	    log.Printf("repaired template: %s", fix)
+//	}
+func Repair(input []byte) ([]byte, RepairReport, error) {
+	var report RepairReport
+	zipReader, err := zip.NewReader(bytes.NewReader(input), int64(len(input)))
+	if err != nil {
+		return nil, report, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	limits := currentParseLimits()
+	if err := limits.checkArchive(zipReader); err != nil {
+		return nil, report, err
+	}
+	fix := func(kind RepairKind, part, format string, args ...interface{}) {
+		report.Fixes = append(report.Fixes, RepairFix{Kind: kind, Part: part, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var parts []*repairPart
+	byName := make(map[string]*repairPart)
+	for _, file := range zipReader.File {
+		name := repairPartName(file.Name)
+		if name != file.Name {
+			if _, exists := byName[strings.ToLower(name)]; !exists {
+				fix(RepairPartName, name, "renamed %q to %s", file.Name, name)
+			} else {
+				name = file.Name
+			}
+		}
+		part := &repairPart{name: name, file: file}
+		parts = append(parts, part)
+		byName[strings.ToLower(name)] = part
+	}
+
+	// relationships
+	for _, part := range parts {
+		if !strings.HasSuffix(part.name, ".rels") {
+			continue
+		}
+		data, err := part.read(limits)
+		if err != nil {
+			return nil, report, err
+		}
+		repaired := repairRelationships(data, func(format string, args ...interface{}) {
+			fix(RepairRelationship, part.name, format, args...)
+		})
+		if repaired != string(data) {
+			part.data = []byte(repaired)
+		}
+	}
+
+	// content types
+	mainDocument := DocumentXml
+	if rels := byName["_rels/.rels"]; rels != nil {
+		data, err := rels.read(limits)
+		if err != nil {
+			return nil, report, err
+		}
+		for _, rel := range parseRelationships(data) {
+			if rel.relType == RelationshipTypeOfficeDocument && !rel.external {
+				mainDocument = rel.partName("")
+			}
+		}
+	}
+	typesPart := byName[strings.ToLower(ContentTypesXml)]
+	if typesPart == nil {
+		typesPart = &repairPart{name: ContentTypesXml, data: []byte(emptyContentTypes)}
+		parts = append([]*repairPart{typesPart}, parts...)
+		fix(RepairContentType, ContentTypesXml, "created the missing content types part")
+	}
+	types, err := typesPart.read(limits)
+	if err != nil {
+		return nil, report, err
+	}
+	repaired, err := repairContentTypes(string(types), parts, mainDocument, func(part, format string, args ...interface{}) {
+		fix(RepairContentType, part, format, args...)
+	})
+	if err != nil {
+		return nil, report, err
+	}
+	if repaired != string(types) {
+		typesPart.data = []byte(repaired)
+	}
+
+	if len(report.Fixes) == 0 {
+		return input, report, nil
+	}
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, part := range parts {
+		if part.file != nil && part.file.FileInfo().IsDir() {
+			continue
+		}
+		data, err := part.read(limits)
+		if err != nil {
+			return nil, report, err
+		}
+		writer, err := zipWriter.Create(part.name)
+		if err != nil {
+			return nil, report, fmt.Errorf("unable to create writer: %w", err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, report, fmt.Errorf("unable to write %s: %w", part.name, err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, report, fmt.Errorf("unable to close ZIP writer: %w", err)
+	}
+	return buf.Bytes(), report, nil
+}
+
+// read returns the repaired or the original content of the part.
+func (p *repairPart) read(limits ParseLimits) ([]byte, error) {
+	if p.data != nil || p.file == nil {
+		return p.data, nil
+	}
+	data, err := limits.readZipFile(p.file)
+	if err != nil {
+		return nil, err
+	}
+	p.data = data
+	return data, nil
+}
+
+// repairPartName returns the valid part name of the archive entry, e.g. 'word/media/image1.png' for
+// '\word\media\image1.png'. Names which are no valid UTF-8 are decoded from CP437, the legacy encoding of ZIP.
+func repairPartName(name string) string {
+	if !utf8.ValidString(name) {
+		if decoded, err := charmap.CodePage437.NewDecoder().String(name); err == nil {
+			name = decoded
+		}
+	}
+	return strings.TrimLeft(strings.ReplaceAll(name, `\`, "/"), "/")
+}
+
+// repairRelationships removes duplicate relationships of the relationships part, gives relationships sharing the ID
+// of a different relationship a new ID and fixes targets with backslashes. Every fix is reported.
+func repairRelationships(data []byte, report func(format string, args ...interface{})) string {
+	rels := string(data)
+	maxId := 0
+	for _, match := range RelationshipIdRegex.FindAllStringSubmatch(rels, -1) {
+		if id, err := strconv.Atoi(match[1]); err == nil && id > maxId {
+			maxId = id
+		}
+	}
+
+	known := make(map[string]relationship)
+	var out strings.Builder
+	pos := 0
+	for _, match := range RelationshipRegex.FindAllStringIndex(rels, -1) {
+		out.WriteString(rels[pos:match[0]])
+		pos = match[1]
+		element := rels[match[0]:match[1]]
+		rel := parseRelationships([]byte(element))[0]
+		if !rel.external && strings.Contains(rel.target, `\`) {
+			target := strings.ReplaceAll(rel.target, `\`, "/")
+			element = relationshipTargetRegex.ReplaceAllLiteralString(element, ` Target="`+html.EscapeString(target)+`"`)
+			report("replaced the backslashes of target %s of %s", rel.target, rel.id)
+			rel.target = target
+		}
+		if first, exists := known[rel.id]; exists {
+			if first == rel {
+				report("removed duplicate relationship %s", rel.id)
+				continue
+			}
+			maxId++
+			id := fmt.Sprintf("rId%d", maxId)
+			element = relationshipIdAttributeRegex.ReplaceAllLiteralString(element, ` Id="`+id+`"`)
+			report("relationship %s to %s shares its ID with %s, renamed it to %s", rel.id, rel.target, first.target, id)
+			rel.id = id
+		}
+		known[rel.id] = rel
+		out.WriteString(element)
+	}
+	out.WriteString(rels[pos:])
+	return out.String()
+}
+
+// repairContentTypes removes the overrides of the content types part which are duplicate or refer to missing parts,
+// replaces generic content types of well-known parts and adds the parts without content type. Every fix is reported.
+func repairContentTypes(types string, parts []*repairPart, mainDocument string, report func(part, format string, args ...interface{})) (string, error) {
+	exists := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		exists[strings.ToLower(part.name)] = true
+	}
+
+	defaults := make(map[string]bool)
+	overrides := make(map[string]bool)
+	var out strings.Builder
+	pos := 0
+	for _, match := range contentTypeEntryRegex.FindAllStringSubmatchIndex(types, -1) {
+		out.WriteString(types[pos:match[0]])
+		pos = match[1]
+		element := types[match[0]:match[1]]
+		attributes := make(map[string]string)
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(types[match[4]:match[5]], -1) {
+			attributes[attribute[1]] = html.UnescapeString(attribute[2])
+		}
+
+		if types[match[2]:match[3]] == "Default" {
+			extension := strings.ToLower(attributes["Extension"])
+			if defaults[extension] {
+				report(ContentTypesXml, "removed duplicate default of extension %s", extension)
+				continue
+			}
+			defaults[extension] = true
+			out.WriteString(element)
+			continue
+		}
+
+		name := repairPartName(attributes["PartName"])
+		key := strings.ToLower(name)
+		switch {
+		case !exists[key]:
+			report(name, "removed the override of the missing part %s", name)
+			continue
+		case overrides[key]:
+			report(name, "removed duplicate override of %s", name)
+			continue
+		}
+		overrides[key] = true
+		contentType := attributes["ContentType"]
+		if known := knownContentType(name, mainDocument); known != "" && isGenericContentType(contentType) {
+			report(name, "replaced content type %q of %s by %s", contentType, name, known)
+			contentType = known
+		}
+		if contentType != attributes["ContentType"] || "/"+name != attributes["PartName"] {
+			element = fmt.Sprintf(`<Override PartName="/%s" ContentType="%s"/>`, html.EscapeString(name), html.EscapeString(contentType))
+		}
+		out.WriteString(element)
+	}
+	rest := types[pos:]
+	end := strings.LastIndex(rest, "</Types>")
+	if end < 0 {
+		return "", fmt.Errorf("invalid content types part %s", ContentTypesXml)
+	}
+	out.WriteString(rest[:end])
+
+	for _, part := range parts {
+		key := strings.ToLower(part.name)
+		extension := strings.ToLower(strings.TrimPrefix(path.Ext(part.name), "."))
+		if part.name == ContentTypesXml || strings.HasSuffix(part.name, "/") || overrides[key] {
+			continue
+		}
+		if known := knownContentType(part.name, mainDocument); known != "" {
+			fmt.Fprintf(&out, `<Override PartName="/%s" ContentType="%s"/>`, html.EscapeString(part.name), known)
+			overrides[key] = true
+			report(part.name, "added content type %s of %s", known, part.name)
+			continue
+		}
+		if defaults[extension] {
+			continue
+		}
+		contentType, known := extensionContentTypes[extension]
+		if !known {
+			contentType = "application/octet-stream"
+		}
+		fmt.Fprintf(&out, `<Default Extension="%s" ContentType="%s"/>`, html.EscapeString(extension), contentType)
+		defaults[extension] = true
+		report(part.name, "added content type %s of extension %s", contentType, extension)
+	}
+	out.WriteString(rest[end:])
+	return out.String(), nil
+}
+
+// knownContentType returns the content type of well-known parts, empty for all other parts.
+func knownContentType(name, mainDocument string) string {
+	switch {
+	case name == mainDocument:
+		return ContentTypeMainDocument
+	case HeaderPathRegex.MatchString(name):
+		return ContentTypeHeader
+	case FooterPathRegex.MatchString(name):
+		return ContentTypeFooter
+	case strings.HasPrefix(name, "word/theme/") && strings.HasSuffix(name, ".xml"):
+		return ContentTypeTheme
+	}
+	return partContentTypes[name]
+}
+
+// isGenericContentType returns true for the content types of arbitrary XML parts, which well-known parts must not
+// have.
+func isGenericContentType(contentType string) bool {
+	switch strings.ToLower(strings.TrimSpace(contentType)) {
+	case "", "application/xml", "text/xml", "application/octet-stream":
+		return true
+	}
+	return false
+}
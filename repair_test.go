@@ -0,0 +1,118 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// buildRawTestArchive returns a ZIP archive of the name and content pairs, names are written as given.
+func buildRawTestArchive(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{Name: files[i], Method: zip.Deflate, NonUTF8: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRepair(t *testing.T) {
+	input := buildRawTestArchive(t,
+		`_rels\.rels`, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeOfficeDocument+`" Target="word/document.xml"/></Relationships>`,
+		`word\document.xml`, testDocumentOpen+`<w:p><w:r><w:t>Dear {name}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:drawing><a:blip r:embed="rId1"/></w:drawing></w:r></w:p>`+testDocumentClose,
+		`word/_rels/document.xml.rels`, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeImage+`" Target="media\bildü.png"/>`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeImage+`" Target="media\bildü.png"/>`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeStyles+`" Target="styles.xml"/></Relationships>`,
+		"word/media/bild\x81.png", "png",
+	)
+	if _, err := OpenBytes(input); err == nil {
+		t.Fatal("expected the broken archive not to open")
+	}
+
+	repaired, report, err := Repair(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, fix := range report.Fixes {
+		kinds = append(kinds, string(fix.Kind))
+	}
+	if expected := "part-name part-name part-name relationship relationship relationship relationship content-type content-type content-type content-type"; strings.Join(kinds, " ") != expected {
+		t.Errorf("expected fixes %s, have %v", expected, report.Fixes)
+	}
+
+	output, err := ProcessBytes(repaired, map[string]string{"name": "Jürgen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "Dear Jürgen") {
+		t.Errorf("expected the replaced name in %s", document)
+	}
+	types := readTestPart(t, output, ContentTypesXml)
+	for _, expected := range []string{
+		`<Override PartName="/word/document.xml" ContentType="` + ContentTypeMainDocument + `"/>`,
+		`<Default Extension="rels" ContentType="` + ContentTypeRelationships + `"/>`,
+		`<Default Extension="png" ContentType="image/png"/>`,
+	} {
+		if !strings.Contains(types, expected) {
+			t.Errorf("expected %s in %s", expected, types)
+		}
+	}
+	rels := readTestPart(t, output, "word/_rels/document.xml.rels")
+	if !strings.Contains(rels, `Id="rId1" Type="`+RelationshipTypeImage+`" Target="media/bildü.png"`) ||
+		!strings.Contains(rels, `Id="rId2" Type="`+RelationshipTypeStyles+`"`) || strings.Count(rels, "<Relationship ") != 2 {
+		t.Errorf("unexpected relationships %s", rels)
+	}
+	doc, err := OpenBytes(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if _, exists, _ := doc.part("word/media/bildü.png"); !exists {
+		t.Error("expected the image to be renamed to UTF-8")
+	}
+}
+
+func TestRepair_ContentTypes(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`,
+		ContentTypesXml, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`+
+			`<Default Extension="rels" ContentType="`+ContentTypeRelationships+`"/><Default Extension="xml" ContentType="application/xml"/>`+
+			`<Override PartName="word/document.xml" ContentType="application/xml"/>`+
+			`<Override PartName="/word/document.xml" ContentType="application/xml"/>`+
+			`<Override PartName="/word/missing.xml" ContentType="application/xml"/></Types>`)
+	repaired, report, err := Repair(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Fixes) != 3 {
+		t.Errorf("expected 3 fixes, have %v", report.Fixes)
+	}
+	expected := `<Default Extension="rels" ContentType="` + ContentTypeRelationships + `"/><Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="` + ContentTypeMainDocument + `"/></Types>`
+	if types := readTestPart(t, repaired, ContentTypesXml); !strings.HasSuffix(types, expected) {
+		t.Errorf("expected %s in %s", expected, types)
+	}
+
+	valid := buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`)
+	output, report, err := Repair(valid)
+	if err != nil || len(report.Fixes) != 0 || !bytes.Equal(output, valid) {
+		t.Errorf("expected valid archives to stay unchanged, have %v %v", report.Fixes, err)
+	}
+	if _, _, err := Repair([]byte("no archive")); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("expected ErrInvalidArchive, have %v", err)
+	}
+}
@@ -1,10 +1,10 @@
 package docx
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"html"
+	"regexp"
 	"strings"
 	"sync"
 )
@@ -12,6 +12,13 @@ import (
 var (
 	// ErrPlaceholderNotFound is returned if there is no placeholder inside the document.
 	ErrPlaceholderNotFound = errors.New("placeholder not found in document")
+	// RunPropertiesRegex matches the run properties of a run.
+	RunPropertiesRegex = regexp.MustCompile(`(?s)<w:rPr>.*?</w:rPr>|<w:rPr/>`)
+	// DrawingRunPropertiesRegex matches the run properties of DrawingML runs, which usually have attributes.
+	DrawingRunPropertiesRegex = regexp.MustCompile(`(?s)<a:rPr(?:\s[^>]*)?/>|<a:rPr(?:\s[^>]*)?>.*?</a:rPr>`)
+	// edgeWhitespaceTextRegex matches text elements without attributes which start or end with whitespace,
+	// which Word collapses, and captures the text.
+	edgeWhitespaceTextRegex = regexp.MustCompile(`<w:t>(\s[^<]*|[^<]*\s)</w:t>`)
 )
 
 // Replacer is the key struct which works on the parsed DOCX document.
@@ -39,6 +46,22 @@ func NewReplacer(docBytes []byte, placeholder []*Placeholder) *Replacer {
 // Replace will replace all occurrences of the placeholderKey with the given value.
 // The function is synced with a mutex as it is not concurrency safe.
 func (r *Replacer) Replace(placeholderKey string, value string) error {
+	// ensure html escaping of special chars
+	valueXml := escapeRunText(value)
+	return r.ReplaceFunc(placeholderKey, func(run *Run) string {
+		if run.isDrawingML(r.document) {
+			return escapeDrawingText(value, r.RunProperties(run))
+		}
+		return valueXml
+	})
+}
+
+// ReplaceFunc will replace all occurrences of the placeholderKey with the XML returned by valueFunc.
+// The valueFunc is called for every occurrence with the run in which the placeholder starts.
+// The returned XML is inserted verbatim into the text of that run, so it must be properly escaped
+// and may only close and re-open the surrounding <w:t> and <w:r> tags if it wants to insert additional elements.
+// The function is synced with a mutex as it is not concurrency safe.
+func (r *Replacer) ReplaceFunc(placeholderKey string, valueFunc func(run *Run) string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if !strings.ContainsRune(placeholderKey, OpenDelimiter) ||
@@ -54,15 +77,9 @@ func (r *Replacer) Replace(placeholderKey string, value string) error {
 		if placeholder.Text(r.document) == placeholderKey {
 			found = true
 
-			// ensure html escaping of special chars
-			// reassign to prevent overwriting the actual value which would cause multiple-escapes
-			tmpVal := html.EscapeString(value)
-			valueInBytes := bytes.Replace(
-				[]byte(tmpVal),
-				[]byte("\n"), []byte("</w:t><w:br/><w:t>"), -1)
-
 			// replace text of the placeholder'str first fragment with the actual value
-			r.replaceFragmentValue(placeholder.Fragments[0], string(valueInBytes))
+			value := valueFunc(placeholder.Fragments[0].Run)
+			r.replaceFragmentValue(placeholder.Fragments[0], value)
 
 			// the other fragments of the placeholder are cut, leaving only the value inside the document.
 			for i := 1; i < len(placeholder.Fragments); i++ {
@@ -83,6 +100,50 @@ func (r *Replacer) Replace(placeholderKey string, value string) error {
 	return nil
 }
 
+// RunProperties returns the run properties (<w:rPr>...</w:rPr>, or <a:rPr> of DrawingML runs) of the given run, if any.
+// The properties can be used to create new runs which look exactly like the given one.
+func (r *Replacer) RunProperties(run *Run) string {
+	if !run.HasText || run.OpenTag.End > run.Text.OpenTag.Start || int64(len(r.document)) < run.Text.OpenTag.Start {
+		return ""
+	}
+	if run.isDrawingML(r.document) {
+		return DrawingRunPropertiesRegex.FindString(string(r.document[run.OpenTag.End:run.Text.OpenTag.Start]))
+	}
+	return RunPropertiesRegex.FindString(string(r.document[run.OpenTag.End:run.Text.OpenTag.Start]))
+}
+
+// ParagraphProperties returns the paragraph properties (<w:pPr>...</w:pPr>) of the paragraph containing the given run, if any.
+func (r *Replacer) ParagraphProperties(run *Run) string {
+	if int64(len(r.document)) < run.OpenTag.Start {
+		return ""
+	}
+	return paragraphPropertiesAt(r.document, run.OpenTag.Start)
+}
+
+// escapeRunText escapes the given value so that it can be inserted into a text-run.
+// The value is normalized to Unicode NFC and newlines are converted into line breaks.
+func escapeRunText(value string) string {
+	escaped := html.EscapeString(normalizeText(value))
+	return strings.ReplaceAll(escaped, "\n", `</w:t><w:br/><w:t xml:space="preserve">`)
+}
+
+// preserveEdgeWhitespace preserves the whitespace of all text elements which start or end with whitespace, which
+// Word would collapse otherwise. This happens if a value starts or ends with a space, or if the run of a placeholder
+// is split behind 'Dear '. The second return value is false if no text element was changed.
+func preserveEdgeWhitespace(data []byte) ([]byte, bool) {
+	if !edgeWhitespaceTextRegex.Match(data) {
+		return data, false
+	}
+	return edgeWhitespaceTextRegex.ReplaceAll(data, []byte(`<w:t xml:space="preserve">$1</w:t>`)), true
+}
+
+// escapeDrawingText escapes the given value so that it can be inserted into a DrawingML text-run.
+// Newlines are converted into DrawingML line breaks between runs with the given runProperties.
+func escapeDrawingText(value, runProperties string) string {
+	escaped := html.EscapeString(normalizeText(value))
+	return strings.ReplaceAll(escaped, "\n", `</a:t></a:r><a:br/><a:r>`+runProperties+`<a:t>`)
+}
+
 // replaceFragmentValue will replace the fragment text with the given value, adjusting all following
 // fragments afterwards.
 func (r *Replacer) replaceFragmentValue(fragment *PlaceholderFragment, value string) {
@@ -236,7 +297,7 @@ func (r *Replacer) getDistinctRuns(placeholder []*Placeholder) []*Run {
 	var seenRuns []int
 	seen := func(runID int) bool {
 		for _, id := range seenRuns {
-			if runId == id {
+			if runID == id {
 				return true
 			}
 		}
@@ -0,0 +1,110 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReplacementReport is the result of a replacement dry run, see AuditReplacements.
+type ReplacementReport struct {
+	// Replaced are the placeholders which have a value, ordered by key.
+	Replaced []ReplacedPlaceholder
+	// Unresolved are the placeholders without value in document order, like UnresolvedPlaceholders after ReplaceAll.
+	Unresolved []UnresolvedPlaceholder
+}
+
+// ReplacedPlaceholder is a placeholder which would be replaced, see ReplacementReport.
+type ReplacedPlaceholder struct {
+	// Key is the placeholder without delimiters, e.g. 'customer' for '{customer}'.
+	Key string
+	// Value is the text which would be inserted. Values which render their own runs (e.g. images or tables) are
+	// given by their String method, or by their type if they have none.
+	Value string
+	// Occurrences is the number of occurrences of the placeholder in all parts.
+	Occurrences int
+	// Parts are the names of the parts which contain the placeholder, in document order.
+	Parts []string
+}
+
+// AuditReplacements matches the placeholders of the body, headers, footers and notes with the values like
+// ReplaceAll, but leaves the document unchanged. The report lists which placeholders would be replaced, with which
+// value and how often, and which placeholders would remain unresolved, e.g. to validate the data of a document
+// before it is generated.
+//
+// Example:
+//
+//	report, err := doc.AuditReplacements(values)
+//	for _, placeholder := range report.Replaced {
+//	    log.Printf("%s = %q (%d times)", placeholder.Key, placeholder.Value, placeholder.Occurrences)
+//	}
+//	for _, placeholder := range report.Unresolved {
+//	    log.Printf("no value for %s in %s", placeholder.Key, placeholder.Part)
+//	}
+func (d *Document) AuditReplacements(values PlaceholderMap) (*ReplacementReport, error) {
+	values, err := values.resolveComputed()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplacementReport{}
+	replaced := make(map[string]*ReplacedPlaceholder)
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		parser := NewRunParser(data)
+		if err := parser.Execute(); err != nil {
+			return nil, err
+		}
+		placeholders, err := ParsePlaceholders(parser.Runs(), data)
+		if err != nil {
+			return nil, err
+		}
+		for _, placeholder := range placeholders {
+			key := RemovePlaceholderDelimiter(placeholder.Text(data))
+			value, exists := values[key]
+			if !exists {
+				report.Unresolved = append(report.Unresolved, UnresolvedPlaceholder{Key: key, Part: name, Kind: partKind(name)})
+				continue
+			}
+			entry := replaced[key]
+			if entry == nil {
+				entry = &ReplacedPlaceholder{Key: key, Value: d.auditValue(value)}
+				replaced[key] = entry
+			}
+			entry.Occurrences++
+			if len(entry.Parts) == 0 || entry.Parts[len(entry.Parts)-1] != name {
+				entry.Parts = append(entry.Parts, name)
+			}
+		}
+	}
+
+	for _, entry := range replaced {
+		report.Replaced = append(report.Replaced, *entry)
+	}
+	sort.Slice(report.Replaced, func(i, j int) bool { return report.Replaced[i].Key < report.Replaced[j].Key })
+	return report, nil
+}
+
+// DryRunReplace opens the DOCX document given by input and returns the report of AuditReplacements for the values,
+// no document is written.
+func DryRunReplace(input []byte, values PlaceholderMap) (*ReplacementReport, error) {
+	doc, err := OpenBytes(input)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	return doc.AuditReplacements(values)
+}
+
+// auditValue returns the text of the value as it would be inserted.
+func (d *Document) auditValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if _, isRich := value.(inlineValue); isRich {
+		if stringer, isStringer := value.(fmt.Stringer); isStringer {
+			return stringer.String()
+		}
+		return fmt.Sprintf("%T", value)
+	}
+	return d.textPolicy.apply(normalizeText(fmt.Sprint(value)))
+}
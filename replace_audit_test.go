@@ -0,0 +1,52 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDocument_AuditReplacements(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}, your order {order} ships to {city}.</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{na</w:t></w:r><w:r><w:t>me}</w:t></w:r><w:r><w:t>{signature}</w:t></w:r></w:p>`)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	before := append([]byte{}, doc.GetFile(DocumentXml)...)
+
+	report, err := doc.AuditReplacements(PlaceholderMap{
+		"name":  "Jane  Doe",
+		"order": 42,
+		"logo":  Hyperlink{Text: "ACME", URL: "https://acme.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ReplacedPlaceholder{
+		{Key: "name", Value: "Jane  Doe", Occurrences: 2, Parts: []string{DocumentXml}},
+		{Key: "order", Value: "42", Occurrences: 1, Parts: []string{DocumentXml}},
+	}
+	if !reflect.DeepEqual(report.Replaced, expected) {
+		t.Errorf("expected replaced %+v, have %+v", expected, report.Replaced)
+	}
+	expectedUnresolved := []UnresolvedPlaceholder{
+		{Key: "city", Part: DocumentXml, Kind: PartBody},
+		{Key: "signature", Part: DocumentXml, Kind: PartBody},
+	}
+	if !reflect.DeepEqual(report.Unresolved, expectedUnresolved) {
+		t.Errorf("expected unresolved %+v, have %+v", expectedUnresolved, report.Unresolved)
+	}
+	if !bytes.Equal(doc.GetFile(DocumentXml), before) {
+		t.Error("expected the document to stay unchanged")
+	}
+
+	report, err = DryRunReplace(input, PlaceholderMap{"city": "Springfield"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Replaced) != 1 || report.Replaced[0].Value != "Springfield" || len(report.Unresolved) != 4 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
@@ -1,8 +1,10 @@
 package docx
 
 import (
+	"bytes"
 	"encoding/xml"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -67,3 +69,56 @@ func TestReplacer_Replace(t *testing.T) {
 	// cleanup
 	_ = os.Remove("./test/out.docx")
 }
+
+func TestDocument_ReplaceAllDrawingML(t *testing.T) {
+	body := `<w:p><w:r><w:drawing><wps:wsp><wps:txbx><w:txbxContent><w:p><w:r><w:t>{title}</w:t></w:r></w:p></w:txbxContent></wps:txbx>` +
+		`<wps:txBody><a:p><a:r><a:rPr lang="en-US" b="1"/><a:t>{date} - {title}</a:t></a:r></a:p></wps:txBody></wps:wsp></w:drawing></w:r></w:p>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"title": "Annual <Report>",
+		"date":  Contact{Name: "Jane Doe", City: "Springfield"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<w:p><w:r><w:drawing><wps:wsp><wps:txbx><w:txbxContent><w:p><w:r><w:t>Annual &lt;Report&gt;</w:t></w:r></w:p></w:txbxContent></wps:txbx>` +
+		`<wps:txBody><a:p><a:r><a:rPr lang="en-US" b="1"/><a:t>Jane Doe</a:t></a:r><a:br/><a:r><a:rPr lang="en-US" b="1"/><a:t>Springfield - Annual &lt;Report&gt;</a:t></a:r></a:p></wps:txBody></wps:wsp></w:drawing></w:r></w:p>`
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); document != testDocumentOpen+expected+testDocumentClose {
+		t.Errorf("expected %s, have %s", expected, document)
+	}
+
+	doc, err = OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"title": PageBreak{}, "date": "today"}); err == nil {
+		t.Error("expected an error for a page break inside DrawingML text")
+	}
+}
+
+func TestDocument_ReplacePreservesWhitespace(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{title}</w:t></w:r></w:p><w:p><w:r><w:t>Dear {name}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.ReplaceAll(PlaceholderMap{"title": " Offer ", "name": Hyperlink{Text: "Jane", URL: "https://example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	document := string(doc.files[DocumentXml])
+	if !strings.Contains(document, `<w:t xml:space="preserve"> Offer </w:t>`) || !strings.Contains(document, `<w:t xml:space="preserve">Dear </w:t>`) {
+		t.Errorf("expected the whitespace to be preserved: %s", document)
+	}
+	// the document is parsed again, so further placeholders can be replaced
+	if err := doc.ReplaceAll(PlaceholderMap{"missing": "value"}); err != nil {
+		t.Fatal(err)
+	}
+}
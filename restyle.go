@@ -0,0 +1,207 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RelationshipTypeTheme is the relationship type of the theme part which defines theme fonts and colors.
+	RelationshipTypeTheme = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	// ContentTypeTheme is the content type of the theme part.
+	ContentTypeTheme = "application/vnd.openxmlformats-officedocument.theme+xml"
+)
+
+var (
+	// StyleReferenceRegex matches all references to a style ID, inside the content as well as inside style
+	// definitions, and captures the part in front of the ID, the ID and the closing quote.
+	StyleReferenceRegex = regexp.MustCompile(`(<w:(?:pStyle|rStyle|tblStyle|basedOn|next|link|styleLink|numStyleLink)\s+w:val=")([^"]*)(")`)
+	// numberingPropertiesRegex matches the numbering properties of a paragraph or a paragraph style.
+	numberingPropertiesRegex = regexp.MustCompile(`(?s)<w:numPr>.*?</w:numPr>|<w:numPr/>`)
+
+	// restyleParts are the parts besides body, headers and footers which may reference styles.
+	restyleParts = []string{"word/numbering.xml", "word/footnotes.xml", "word/endnotes.xml", "word/comments.xml"}
+)
+
+// Restyle re-maps the styles of the content document onto the style definitions of the reference document, so that
+// documents which originate elsewhere look like the reference (fonts, spacing, headings, theme).
+// Styles are matched by ID first and by name second, e.g. a German 'Überschrift1' with the name 'heading 1' uses
+// the 'Heading1' style of the reference. Styles without a counterpart inside the reference are kept.
+// The theme of the reference replaces the theme of the content. Direct formatting inside the content is kept.
+//
+// Example:
+//
+//	branded, err := docx.Restyle(supplierBytes, corporateTemplateBytes)
+func Restyle(content, reference []byte) ([]byte, error) {
+	doc, err := OpenBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open content document: %w", err)
+	}
+	defer doc.Close()
+	ref, err := OpenBytes(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reference document: %w", err)
+	}
+	defer ref.Close()
+
+	if err := doc.restyle(ref); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// restyle replaces the style definitions and the theme of the document with those of the reference.
+func (d *Document) restyle(ref *Document) error {
+	refData, refStyles, err := ref.styles()
+	if err != nil {
+		return err
+	}
+	if refData == nil {
+		return fmt.Errorf("%w: reference document has no style definitions", ErrStyleNotFound)
+	}
+	_, styles, err := d.styles()
+	if err != nil {
+		return err
+	}
+
+	// map the style IDs of the document to those of the reference, unmatched styles are copied
+	refIds := make(map[string]styleInfo)
+	for _, style := range refStyles {
+		refIds[style.id] = style
+	}
+	mapping := make(map[string]string)
+	var unmatched []styleInfo
+	for _, style := range styles {
+		if refStyle, exists := refIds[style.id]; exists && refStyle.styleType == style.styleType {
+			mapping[style.id] = style.id
+			continue
+		}
+		if refStyle, found := findStyleByName(refStyles, style.name, style.styleType); found {
+			mapping[style.id] = refStyle.id
+			continue
+		}
+		id := style.id
+		for i := 1; ; i++ {
+			if _, exists := refIds[id]; !exists {
+				break
+			}
+			id = style.id + strconv.Itoa(i)
+		}
+		mapping[style.id] = id
+		refIds[id] = style
+		unmatched = append(unmatched, style)
+	}
+	remap := func(data []byte) []byte {
+		return StyleReferenceRegex.ReplaceAllFunc(data, func(ref []byte) []byte {
+			match := StyleReferenceRegex.FindSubmatch(ref)
+			id, mapped := mapping[html.UnescapeString(string(match[2]))]
+			if !mapped {
+				return ref
+			}
+			return []byte(string(match[1]) + html.EscapeString(id) + string(match[3]))
+		})
+	}
+
+	// the numbering of the reference is not copied, so styles keep the numbering of the document
+	numbering := make(map[string]string)
+	for _, style := range styles {
+		if numPr := numberingPropertiesRegex.FindString(style.definition); numPr != "" {
+			numbering[mapping[style.id]] = numPr
+		}
+	}
+	styled := StyleRegex.ReplaceAllFunc(refData, func(definition []byte) []byte {
+		parsed := parseStyles(definition)
+		if len(parsed) != 1 {
+			return definition
+		}
+		return numberingPropertiesRegex.ReplaceAllLiteral(definition, []byte(numbering[parsed[0].id]))
+	})
+	var definitions strings.Builder
+	for _, style := range unmatched {
+		definition := strings.Replace(style.definition, `w:styleId="`+html.EscapeString(style.id)+`"`,
+			`w:styleId="`+html.EscapeString(mapping[style.id])+`"`, 1)
+		definitions.Write(remap([]byte(definition)))
+	}
+
+	closing := []byte("</w:styles>")
+	pos := bytes.LastIndex(styled, closing)
+	if pos < 0 {
+		return fmt.Errorf("invalid styles part %s of reference document", StylesXml)
+	}
+	changed := append([]byte{}, styled[:pos]...)
+	changed = append(changed, definitions.String()...)
+	changed = append(changed, styled[pos:]...)
+	if err := d.setStyles(changed); err != nil {
+		return err
+	}
+
+	// all style references of the content must use the IDs of the reference
+	for _, name := range d.xmlFiles() {
+		if err := d.SetFile(name, remap(d.files[name])); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	for _, name := range restyleParts {
+		data, exists, err := d.part(name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := d.setPart(name, remap(data)); err != nil {
+				return err
+			}
+		}
+	}
+	return d.restyleTheme(ref)
+}
+
+// restyleTheme replaces the theme of the document with the theme of the reference, if the reference has one.
+func (d *Document) restyleTheme(ref *Document) error {
+	refTheme, exists, err := ref.relationshipTarget(DocumentXml, RelationshipTypeTheme)
+	if err != nil || !exists {
+		return err
+	}
+	themeData, exists, err := ref.part(refTheme)
+	if err != nil || !exists {
+		return err
+	}
+
+	theme, exists, err := d.relationshipTarget(DocumentXml, RelationshipTypeTheme)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		theme = "word/theme/theme1.xml"
+		if _, err := d.addRelationship(DocumentXml, RelationshipTypeTheme, "theme/theme1.xml", false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(theme, ContentTypeTheme); err != nil {
+			return err
+		}
+	}
+	return d.setPart(theme, themeData)
+}
+
+// findStyleByName returns the style of the given type with the given name, ignoring case.
+func findStyleByName(styles []styleInfo, name, styleType string) (styleInfo, bool) {
+	if name == "" {
+		return styleInfo{}, false
+	}
+	for _, style := range styles {
+		if style.styleType == styleType && strings.EqualFold(style.name, name) {
+			return style, true
+		}
+	}
+	return styleInfo{}, false
+}
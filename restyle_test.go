@@ -0,0 +1,63 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRestyle(t *testing.T) {
+	themeRel := `<Relationship Id="rId9" Type="` + RelationshipTypeTheme + `" Target="theme/theme1.xml"/>`
+	content := buildTestDocx(t,
+		`<w:p><w:pPr><w:pStyle w:val="berschrift1"/></w:pPr><w:r><w:t>Intro</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:pStyle w:val="Callout"/></w:pPr><w:r><w:rPr><w:rStyle w:val="Fett"/></w:rPr><w:t>Note</w:t></w:r></w:p>`,
+		StylesXml, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Standard"><w:name w:val="Normal"/><w:rPr><w:rFonts w:ascii="Arial"/></w:rPr></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="berschrift1"><w:name w:val="heading 1"/><w:basedOn w:val="Standard"/><w:pPr><w:numPr><w:numId w:val="3"/></w:numPr></w:pPr></w:style>`+
+			`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Callout"><w:name w:val="Callout"/><w:basedOn w:val="Standard"/></w:style>`+
+			`<w:style w:type="character" w:styleId="Fett"><w:name w:val="Strong"/><w:rPr><w:b/></w:rPr></w:style>`+
+			`</w:styles>`,
+		"word/theme/theme1.xml", `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Content"/>`,
+		"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+themeRel+`</Relationships>`,
+	)
+	reference := buildTestDocx(t, `<w:p/>`,
+		StylesXml, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:rPr><w:rFonts w:ascii="Corporate Sans"/></w:rPr></w:style>`+
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:numPr><w:numId w:val="7"/></w:numPr><w:spacing w:before="480"/></w:pPr></w:style>`+
+			`<w:style w:type="character" w:styleId="Strong"><w:name w:val="Strong"/><w:rPr><w:b/><w:color w:val="004488"/></w:rPr></w:style>`+
+			`</w:styles>`,
+		"word/theme/theme1.xml", `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Corporate"/>`,
+		"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+themeRel+`</Relationships>`,
+	)
+
+	output, err := Restyle(content, reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{`<w:pStyle w:val="Heading1"/>`, `<w:pStyle w:val="Callout"/>`, `<w:rStyle w:val="Strong"/>`} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+
+	styles := readTestPart(t, output, StylesXml)
+	for _, expected := range []string{
+		`<w:rFonts w:ascii="Corporate Sans"/>`,
+		// the heading looks like the reference but keeps the numbering of the content
+		`<w:pPr><w:numPr><w:numId w:val="3"/></w:numPr><w:spacing w:before="480"/></w:pPr>`,
+		`<w:color w:val="004488"/>`,
+		// styles unknown to the reference are kept and based on the styles of the reference
+		`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Callout"><w:name w:val="Callout"/><w:basedOn w:val="Normal"/></w:style>`,
+	} {
+		if !strings.Contains(styles, expected) {
+			t.Errorf("expected %s in styles: %s", expected, styles)
+		}
+	}
+	if strings.Contains(styles, "Arial") || strings.Contains(styles, `w:styleId="berschrift1"`) {
+		t.Errorf("expected the styles of the content to be replaced: %s", styles)
+	}
+	if theme := readTestPart(t, output, "word/theme/theme1.xml"); !strings.Contains(theme, `name="Corporate"`) {
+		t.Errorf("expected the theme of the reference: %s", theme)
+	}
+}
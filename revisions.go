@@ -0,0 +1,220 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// deletedTextRegex matches deleted text and deleted field instructions of tracked changes, which are not visible.
+	deletedTextRegex = regexp.MustCompile(`(?s)<w:del(?:Text|InstrText)(?:\s[^>]*)?>.*?</w:del(?:Text|InstrText)>`)
+	// deletedTextReplacer turns deleted text and field instructions into regular ones when their deletion is rejected.
+	deletedTextReplacer = strings.NewReplacer(
+		"<w:delText", "<w:t", "</w:delText>", "</w:t>",
+		"<w:delInstrText", "<w:instrText", "</w:delInstrText>", "</w:instrText>",
+	)
+)
+
+// revisionWrappers are the elements which enclose inserted or deleted content, true for insertions.
+// Inside properties they are empty and mark inserted or deleted paragraph marks and table rows.
+var revisionWrappers = map[string]bool{
+	"ins":      true,
+	"moveTo":   true,
+	"del":      false,
+	"moveFrom": false,
+}
+
+// revisionMarkers are the elements which only mark the range of a revision, they are removed either way.
+var revisionMarkers = map[string]bool{
+	"moveFromRangeStart":          true,
+	"moveFromRangeEnd":            true,
+	"moveToRangeStart":            true,
+	"moveToRangeEnd":              true,
+	"customXmlInsRangeStart":      true,
+	"customXmlInsRangeEnd":        true,
+	"customXmlDelRangeStart":      true,
+	"customXmlDelRangeEnd":        true,
+	"customXmlMoveFromRangeStart": true,
+	"customXmlMoveFromRangeEnd":   true,
+	"customXmlMoveToRangeStart":   true,
+	"customXmlMoveToRangeEnd":     true,
+	"cellIns":                     true,
+	"cellDel":                     true,
+	"cellMerge":                   true,
+}
+
+// AcceptAllRevisions accepts all tracked changes in the body, headers, footers and notes like 'Accept All Changes'
+// in Word: inserted content is kept, deleted content is removed and formatting changes are kept.
+// Paragraphs whose paragraph mark was deleted are merged with the following paragraph.
+func (d *Document) AcceptAllRevisions() error {
+	return d.resolveAllRevisions(true)
+}
+
+// RejectAllRevisions rejects all tracked changes in the body, headers, footers and notes like 'Reject All Changes'
+// in Word: inserted content is removed, deleted content is restored and the previous formatting is restored.
+// Paragraphs whose paragraph mark was inserted are merged with the following paragraph.
+func (d *Document) RejectAllRevisions() error {
+	return d.resolveAllRevisions(false)
+}
+
+// resolveAllRevisions accepts or rejects the tracked changes of all XML files.
+func (d *Document) resolveAllRevisions(accept bool) error {
+	for _, name := range d.xmlFiles() {
+		data := string(d.files[name])
+		root := childElements(data)
+		if len(root) == 0 {
+			continue
+		}
+		start := root[0].start + strings.IndexByte(data[root[0].start:], '>') + 1
+		end := strings.LastIndex(data[:root[0].end], "</")
+		if end < start {
+			continue // empty, self-closing root element
+		}
+
+		content := resolveRevisions(data[start:end], accept)
+		if content == data[start:end] {
+			continue
+		}
+		if err := d.SetFile(name, []byte(data[:start]+content+data[end:])); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRevisions accepts or rejects the tracked changes of an XML fragment and returns the resulting fragment.
+func resolveRevisions(content string, accept bool) string {
+	children := childElements(content)
+	if len(children) == 0 {
+		return content
+	}
+	var out strings.Builder
+	pos := 0
+	// pending is a paragraph whose paragraph mark was removed, its content is moved into the following paragraph
+	pending := ""
+	for _, child := range children {
+		element := content[child.start:child.end]
+		if pending != "" && child.name != "p" {
+			out.WriteString(pending)
+			pending = ""
+		}
+		out.WriteString(content[pos:child.start])
+		pos = child.end
+
+		open, inner, close := splitElement(element)
+		inserted, wrapper := revisionWrappers[child.name]
+		switch {
+		case revisionMarkers[child.name] || strings.HasSuffix(child.name, "Change"):
+			// property changes are resolved together with the properties they belong to
+		case wrapper:
+			if inner == "" || inserted != accept {
+				break // a mark inside properties or removed content
+			}
+			inner = resolveRevisions(inner, accept)
+			if !inserted {
+				inner = deletedTextReplacer.Replace(inner)
+			}
+			out.WriteString(inner)
+		case child.name == "tr" && revisionRemoved(inner, "trPr", accept):
+			// removed table row
+		case child.name == "p":
+			removed := revisionRemoved(paragraphMarkProperties(inner), "rPr", accept)
+			inner = resolveRevisions(inner, accept)
+			if pending != "" {
+				properties := 0
+				if first := childElements(inner); len(first) > 0 && first[0].name == "pPr" {
+					properties = first[0].end
+				}
+				inner = inner[:properties] + paragraphContent(pending) + inner[properties:]
+				pending = ""
+			}
+			if removed {
+				pending = open + inner + close
+			} else {
+				out.WriteString(open + inner + close)
+			}
+		case inner == "":
+			out.WriteString(element)
+		default:
+			if !accept {
+				inner = restoreProperties(child.name, inner)
+			}
+			out.WriteString(open + resolveRevisions(inner, accept) + close)
+		}
+	}
+	// the last paragraph of a body, cell or text box cannot be merged
+	out.WriteString(pending)
+	out.WriteString(content[pos:])
+	return out.String()
+}
+
+// revisionRemoved returns true if the properties element of the given name, a child of content, marks content as
+// inserted and the revisions are rejected or marks it as deleted and the revisions are accepted.
+func revisionRemoved(content, properties string, accept bool) bool {
+	for _, child := range childElements(content) {
+		if child.name != properties {
+			continue
+		}
+		_, inner, _ := splitElement(content[child.start:child.end])
+		for _, mark := range childElements(inner) {
+			if inserted, wrapper := revisionWrappers[mark.name]; wrapper && inserted != accept {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paragraphMarkProperties returns the paragraph properties of the paragraph content, which hold the run properties
+// of the paragraph mark.
+func paragraphMarkProperties(content string) string {
+	for _, child := range childElements(content) {
+		if child.name == "pPr" {
+			_, inner, _ := splitElement(content[child.start:child.end])
+			return inner
+		}
+	}
+	return ""
+}
+
+// paragraphContent returns the content of the paragraph without its properties.
+func paragraphContent(paragraph string) string {
+	_, inner, _ := splitElement(paragraph)
+	if first := childElements(inner); len(first) > 0 && first[0].name == "pPr" {
+		return inner[first[0].end:]
+	}
+	return inner
+}
+
+// restoreProperties restores the previous properties recorded by a property change, e.g. <w:rPrChange> inside the
+// run properties <w:rPr>, and returns the new content of the properties element. Content without a change of the
+// given properties is returned unchanged.
+func restoreProperties(name, content string) string {
+	var change string
+	var front, back strings.Builder
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch {
+		case child.name == name+"Change":
+			change = element
+		case child.name == "headerReference" || child.name == "footerReference":
+			// header and footer references are not part of section property changes
+			front.WriteString(element)
+		case name == "pPr" && (child.name == "rPr" || child.name == "sectPr"):
+			// the properties of the paragraph mark and the section are not part of paragraph property changes
+			back.WriteString(element)
+		}
+	}
+	if change == "" {
+		return content
+	}
+	previous := ""
+	_, inner, _ := splitElement(change)
+	if old := childElements(inner); len(old) > 0 {
+		_, previous, _ = splitElement(inner[old[0].start:old[0].end])
+	}
+	return front.String() + previous + back.String()
+}
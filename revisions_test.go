@@ -0,0 +1,81 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// trackedChangesBody is a body edited with Track Changes: an insertion, a deletion, a move, a formatting change,
+// a deleted paragraph mark, an inserted table row and a paragraph property change.
+const trackedChangesBody = `<w:p><w:r><w:t xml:space="preserve">Hello </w:t></w:r>` +
+	`<w:ins w:id="1" w:author="A"><w:r><w:t xml:space="preserve">dear </w:t></w:r></w:ins>` +
+	`<w:del w:id="2" w:author="A"><w:r><w:delText xml:space="preserve">old </w:delText></w:r></w:del>` +
+	`<w:r><w:rPr><w:b/><w:rPrChange w:id="3" w:author="A"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr><w:t>world</w:t></w:r></w:p>` +
+	`<w:p><w:pPr><w:rPr><w:del w:id="4" w:author="A"/></w:rPr></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r></w:p>` +
+	`<w:p><w:pPr><w:jc w:val="center"/><w:pPrChange w:id="5" w:author="A"><w:pPr><w:jc w:val="left"/></w:pPr></w:pPrChange></w:pPr>` +
+	`<w:moveFromRangeStart w:id="6" w:name="move1"/><w:moveFrom w:id="7" w:author="A"><w:r><w:delText>moved</w:delText></w:r></w:moveFrom><w:moveFromRangeEnd w:id="6"/>` +
+	`<w:r><w:t>second</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc></w:tr>` +
+	`<w:tr><w:trPr><w:ins w:id="8" w:author="A"/></w:trPr><w:tc><w:p><w:ins w:id="9" w:author="A"><w:r><w:t>B</w:t></w:r></w:ins></w:p></w:tc></w:tr></w:tbl>` +
+	`<w:sectPr/>`
+
+func TestDocument_AcceptAllRevisions(t *testing.T) {
+	expected := `<w:p><w:r><w:t xml:space="preserve">Hello </w:t></w:r><w:r><w:t xml:space="preserve">dear </w:t></w:r>` +
+		`<w:r><w:rPr><w:b/></w:rPr><w:t>world</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r><w:r><w:t>second</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:trPr></w:trPr><w:tc><w:p><w:r><w:t>B</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:sectPr/>`
+	testResolveRevisions(t, (*Document).AcceptAllRevisions, expected)
+}
+
+func TestDocument_RejectAllRevisions(t *testing.T) {
+	expected := `<w:p><w:r><w:t xml:space="preserve">Hello </w:t></w:r><w:r><w:t xml:space="preserve">old </w:t></w:r>` +
+		`<w:r><w:rPr><w:i/></w:rPr><w:t>world</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:rPr></w:rPr></w:pPr><w:r><w:t xml:space="preserve">First </w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:jc w:val="left"/></w:pPr><w:r><w:t>moved</w:t></w:r><w:r><w:t>second</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:sectPr/>`
+	testResolveRevisions(t, (*Document).RejectAllRevisions, expected)
+}
+
+func testResolveRevisions(t *testing.T, resolve func(*Document) error, expected string) {
+	t.Helper()
+	doc, err := OpenBytes(buildTestDocx(t, trackedChangesBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resolve(doc); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); document != testDocumentOpen+expected+testDocumentClose {
+		t.Errorf("expected %s, have %s", expected, document)
+	}
+}
+
+func TestDocument_ReplaceAcrossRevisions(t *testing.T) {
+	body := `<w:p><w:r><w:t>{na</w:t></w:r><w:ins w:id="1" w:author="A"><w:r><w:t>me}</w:t></w:r></w:ins></w:p>` +
+		`<w:p><w:r><w:t>{cit</w:t></w:r><w:del w:id="2" w:author="A"><w:r><w:delText>ies}</w:delText></w:r></w:del><w:r><w:t>y}</w:t></w:r></w:p>` +
+		`<w:p><w:del w:id="3" w:author="A"><w:r><w:delText>{name}</w:delText></w:r></w:del></w:p>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placeholders := doc.Placeholders(); len(placeholders) != 2 {
+		t.Fatalf("expected 2 placeholders, have %d", len(placeholders))
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"name": "Jane", "city": "Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+	document := string(doc.GetFile(DocumentXml))
+	for _, expected := range []string{"<w:t>Jane</w:t>", "<w:t>Berlin</w:t>", "<w:delText>ies}</w:delText>", "<w:delText>{name}</w:delText>"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"strings"
+)
+
+// TextRun is a fragment of RichText with its formatting. The formatting is added to the formatting of the
+// placeholder run, formatting which is not set is inherited from the placeholder.
+type TextRun struct {
+	Text      string
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+	// Color is the text color as hex RGB value, e.g. 'C00000' or '#C00000'.
+	Color string
+	// Highlight is one of the highlight colors of Word, e.g. 'yellow'.
+	Highlight string
+	// Size is the font size in points.
+	Size float64
+	// Font is the name of the font, e.g. 'Arial'.
+	Font string
+}
+
+// RichText is a replacement value which inserts text with partly different formatting, one run per fragment.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "total": docx.RichText{
+//	        {Text: "Total due: "},
+//	        {Text: "$4,500", Bold: true, Color: "C00000"},
+//	    },
+//	})
+type RichText []TextRun
+
+// String returns the text of all fragments.
+func (r RichText) String() string {
+	var text strings.Builder
+	for _, run := range r {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+// inlineXml returns the runs of the fragments.
+func (r RichText) inlineXml(ctx *valueContext) (string, error) {
+	policy := ctx.doc.textPolicy
+	var out strings.Builder
+	for _, run := range r {
+		if run.Text == "" {
+			continue
+		}
+		runCtx := *ctx
+		for name, value := range map[string]interface{}{
+			"bold":      run.Bold,
+			"italic":    run.Italic,
+			"underline": run.Underline,
+			"strike":    run.Strike,
+			"color":     run.Color,
+			"highlight": run.Highlight,
+			"size":      run.Size,
+			"font":      run.Font,
+		} {
+			property := runStyleProperties[name]
+			element, err := property.xml(value)
+			if err != nil {
+				return "", err
+			}
+			if element != "" {
+				runCtx.runProperties = setRunProperty(runCtx.runProperties, property.element, element)
+			}
+		}
+		out.WriteString(policy.lineRuns(&runCtx, policy.apply(normalizeText(run.Text))))
+	}
+	return out.String(), nil
+}
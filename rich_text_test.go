@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRichText(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>{total}.</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := RichText{
+		{Text: "Total due: "},
+		{Text: "$4,500", Bold: true, Underline: true, Color: "#c00000", Font: "Arial"},
+		{},
+		{Text: " & more", Italic: true, Size: 12, Highlight: "yellow", Strike: true},
+	}
+	if value.String() != "Total due: $4,500 & more" {
+		t.Errorf("unexpected text %s", value.String())
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"total": value}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">Total due: </w:t></w:r>` +
+		`<w:r><w:rPr><w:rFonts w:ascii="Arial" w:hAnsi="Arial" w:cs="Arial"/><w:b/><w:color w:val="C00000"/><w:sz w:val="20"/><w:u w:val="single"/></w:rPr><w:t xml:space="preserve">$4,500</w:t></w:r>` +
+		`<w:r><w:rPr><w:i/><w:strike/><w:sz w:val="24"/><w:highlight w:val="yellow"/></w:rPr><w:t xml:space="preserve"> &amp; more</w:t></w:r>` +
+		`<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">.</w:t></w:r>`
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); !strings.Contains(document, expected) {
+		t.Errorf("expected %s in %s", expected, document)
+	}
+}
+
+func TestRichText_Invalid(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{total}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"total": RichText{{Text: "x", Color: "red"}}}); err == nil {
+		t.Error("expected an error for an invalid color")
+	}
+}
@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+)
+
+// RubyAlign is the alignment of the ruby text above its base text (w:rubyAlign).
+type RubyAlign string
+
+const (
+	// RubyDistributeSpace distributes the ruby text over the width of the base text with space at both ends.
+	// This is the default, used by Word for Japanese furigana.
+	RubyDistributeSpace RubyAlign = "distributeSpace"
+	// RubyDistributeLetter distributes the ruby text over the full width of the base text.
+	RubyDistributeLetter RubyAlign = "distributeLetter"
+	// RubyCenter centers the ruby text above the base text.
+	RubyCenter RubyAlign = "center"
+	// RubyLeft aligns the ruby text with the start of the base text.
+	RubyLeft RubyAlign = "left"
+	// RubyRight aligns the ruby text with the end of the base text.
+	RubyRight RubyAlign = "right"
+)
+
+// Ruby is a replacement value which renders a phonetic guide (ruby text) above its base text, e.g. the furigana
+// reading of a Japanese name. The ruby text is half the font size of the base text, which keeps the formatting
+// of the placeholder.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "familyName": docx.Ruby{Base: "山田", Text: "やまだ"},
+//	    "givenName":  docx.Ruby{Base: "太郎", Text: "たろう"},
+//	})
+type Ruby struct {
+	// Base is the annotated text, e.g. the kanji of a name.
+	Base string
+	// Text is the ruby text shown above the base text, e.g. the reading in hiragana.
+	Text string
+	// Language is the language of the ruby text, "ja-JP" if empty.
+	Language string
+	// Align is the alignment of the ruby text, RubyDistributeSpace if empty.
+	Align RubyAlign
+}
+
+// String returns the base text followed by the ruby text in parentheses, which is used where ruby cannot be
+// rendered, e.g. inside the text of shapes.
+func (r Ruby) String() string {
+	if r.Text == "" {
+		return r.Base
+	}
+	return r.Base + " (" + r.Text + ")"
+}
+
+// inlineXml returns a run containing the ruby (<w:ruby>). Values without ruby text are inserted as plain text.
+func (r Ruby) inlineXml(ctx *valueContext) (string, error) {
+	if r.Text == "" {
+		return ctx.valueXml(r.Base)
+	}
+	language, align := r.Language, r.Align
+	if language == "" {
+		language = "ja-JP"
+	}
+	if align == "" {
+		align = RubyDistributeSpace
+	}
+
+	size := ctx.doc.fontSize(ctx.runProperties)
+	rubySize := max(size/2, 1)
+	textProperties := setRunProperty(ctx.runProperties, "sz", fmt.Sprintf(`<w:sz w:val="%d"/>`, rubySize))
+	textProperties = setRunProperty(textProperties, "szCs", fmt.Sprintf(`<w:szCs w:val="%d"/>`, rubySize))
+
+	return fmt.Sprintf(`<w:r>%s<w:ruby><w:rubyPr><w:rubyAlign w:val="%s"/><w:hps w:val="%d"/><w:hpsRaise w:val="%d"/>`+
+		`<w:hpsBaseText w:val="%d"/><w:lid w:val="%s"/></w:rubyPr>`+
+		`<w:rt><w:r>%s<w:t xml:space="preserve">%s</w:t></w:r></w:rt>`+
+		`<w:rubyBase><w:r>%s<w:t xml:space="preserve">%s</w:t></w:r></w:rubyBase></w:ruby></w:r>`,
+		ctx.runProperties, align, rubySize, max(size-2, 1), size, html.EscapeString(language),
+		textProperties, html.EscapeString(normalizeText(r.Text)),
+		ctx.runProperties, html.EscapeString(normalizeText(r.Base))), nil
+}
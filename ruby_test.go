@@ -0,0 +1,60 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceRuby(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/><w:sz w:val="24"/></w:rPr><w:t>Name: {name}</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>{plain}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"name":  Ruby{Base: "山田", Text: "やまだ"},
+		"plain": Ruby{Base: "A&B"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:ruby><w:rubyPr><w:rubyAlign w:val="distributeSpace"/><w:hps w:val="12"/><w:hpsRaise w:val="22"/><w:hpsBaseText w:val="24"/><w:lid w:val="ja-JP"/></w:rubyPr>`,
+		`<w:rt><w:r><w:rPr><w:b/><w:sz w:val="12"/><w:szCs w:val="12"/></w:rPr><w:t xml:space="preserve">やまだ</w:t></w:r></w:rt>`,
+		`<w:rubyBase><w:r><w:rPr><w:b/><w:sz w:val="24"/></w:rPr><w:t xml:space="preserve">山田</w:t></w:r></w:rubyBase></w:ruby></w:r>`,
+		// values without ruby text are plain text
+		`<w:t xml:space="preserve">A&amp;B</w:t>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Count(document, "<w:ruby>") != 1 {
+		t.Errorf("expected a single ruby: %s", document)
+	}
+}
+
+func TestProcessTemplateDocx_Ruby(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{range .Names}}{{.}} {{end}}</w:t></w:r></w:p>`)
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Names": []Ruby{{Base: "山田", Text: "やまだ", Align: RubyCenter}, {Base: "太郎", Text: "たろう", Align: RubyCenter}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	if n := strings.Count(document, `<w:rubyAlign w:val="center"/>`); n != 2 {
+		t.Errorf("expected 2 centered rubies, have %d: %s", n, document)
+	}
+	if !strings.Contains(document, `<w:hpsBaseText w:val="20"/>`) {
+		t.Errorf("expected the default font size as base size: %s", document)
+	}
+}
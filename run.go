@@ -1,9 +1,12 @@
 package docx
 
-import "fmt"
+import (
+	"fmt"
+	"sync/atomic"
+)
 
 var (
-	runId = 0 // global Run ID counter. Incremented by NewRun()
+	runId atomic.Int64 // global Run ID counter. Incremented by NewRun()
 )
 
 // TagPair describes an opening and closing tag position.
@@ -45,6 +48,15 @@ func (r *Run) GetText(documentBytes []byte) string {
 	return string(documentBytes[startPos:endPos])
 }
 
+// isDrawingML returns true if the run is a DrawingML run (<a:r>) of a text box or shape, given the source bytes.
+// DrawingML runs can only contain plain text.
+func (r *Run) isDrawingML(documentBytes []byte) bool {
+	if int64(len(documentBytes)) < r.OpenTag.End {
+		return false
+	}
+	return markupPrefix(documentBytes[r.OpenTag.Start:r.OpenTag.End]) == drawingPrefix
+}
+
 // String returns a string representation of the run, given the source bytes.
 // It may be helpful in debugging.
 func (r *Run) String(bytes []byte) string {
@@ -93,13 +105,12 @@ func (dr *DocumentRuns) Pop() *Run {
 	return ret
 }
 
-// NewRunID returns the next Fragment.ID
+// NewRunID returns the next Run.ID, it is safe for concurrent use.
 func NewRunID() int {
-	runId += 1
-	return runId
+	return int(runId.Add(1))
 }
 
 // ResetRunIdCounter will reset the runId counter to 0
 func ResetRunIdCounter() {
-	runId = 0
+	runId.Store(0)
 }
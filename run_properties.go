@@ -0,0 +1,27 @@
+package docx
+
+import (
+	"strings"
+)
+
+var (
+	// runPropertyOrder is the order of the child elements of run properties (<w:rPr>) required by the schema.
+	// Word reports documents with elements in a different order as corrupt.
+	runPropertyOrder = []string{
+		"rStyle", "rFonts", "b", "bCs", "i", "iCs", "caps", "smallCaps", "strike", "dstrike", "outline", "shadow",
+		"emboss", "imprint", "noProof", "snapToGrid", "vanish", "webHidden", "color", "spacing", "w", "kern",
+		"position", "sz", "szCs", "highlight", "u", "effect", "bdr", "shd", "fitText", "vertAlign", "rtl", "cs",
+		"em", "lang", "eastAsianLayout", "specVanish", "oMath", "rPrChange",
+	}
+)
+
+// setRunProperty returns the run properties (<w:rPr>...</w:rPr>, <w:rPr/> or empty) with the given element set.
+// An existing element with the same name is replaced, otherwise the element is inserted at the position required by
+// the schema. The element must be a complete XML element of the given local name, e.g. setRunProperty(rPr, "vanish", "<w:vanish/>").
+func setRunProperty(runProperties, name, element string) string {
+	if runProperties == "" || runProperties == "<w:rPr/>" {
+		return "<w:rPr>" + element + "</w:rPr>"
+	}
+	content := strings.TrimSuffix(strings.TrimPrefix(runProperties, "<w:rPr>"), "</w:rPr>")
+	return "<w:rPr>" + setOrderedElement(content, runPropertyOrder, name, element) + "</w:rPr>"
+}
@@ -0,0 +1,414 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+	"time"
+	"unicode"
+)
+
+// sampleListLength is the number of items of generated sample lists.
+const sampleListLength = 3
+
+var (
+	// sampleDate is the first date of generated sample dates, following dates are a week apart.
+	sampleDate = time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	samplePeople    = []string{"Jane Doe", "John Smith", "Maria Garcia"}
+	sampleFirstName = []string{"Jane", "John", "Maria"}
+	sampleLastName  = []string{"Doe", "Smith", "Garcia"}
+	sampleCompanies = []string{"ACME Corp", "Globex Inc", "Initech LLC"}
+	sampleCities    = []string{"Springfield", "Riverside", "Fairview"}
+	sampleStatuses  = []string{"Open", "Paid", "Overdue"}
+	sampleAmounts   = []float64{1250.75, 349.9, 89.5}
+	sampleCounts    = []int{3, 12, 7}
+
+	// genericSampleNames are field names which depend on the value they belong to, e.g. the name of a company.
+	genericSampleNames = []string{"name", "number", "id", "no", "code", "title", "type", "value"}
+)
+
+// sampleField is the inferred shape of a value of the template data.
+type sampleField struct {
+	name string
+	// fields are the fields accessed on the value, the value is a map if there are any
+	fields map[string]*sampleField
+	// element is the shape of the items if the value is ranged over
+	element *sampleField
+	// condition is true if the value is used as condition, e.g. {{if .Paid}}
+	condition bool
+	// output is true if the value is written by an action or passed to a function
+	output bool
+	// argument is true if the value is passed to a function, e.g. {{formatDate .Due}}
+	argument bool
+}
+
+// field returns the shape of the field with the given name, adding it if it is not known yet.
+func (f *sampleField) field(name string) *sampleField {
+	if f.fields == nil {
+		f.fields = make(map[string]*sampleField)
+	}
+	if _, exists := f.fields[name]; !exists {
+		f.fields[name] = &sampleField{name: name}
+	}
+	return f.fields[name]
+}
+
+// GenerateSampleData returns sample data for the template given by input (see ProcessTemplateDocx), e.g. for previews
+// and tests in authoring tools. The shape of the data is inferred from the template actions: fields accessed with
+// {{.Customer.Name}} become nested maps, {{range .Items}} becomes a list of three items and fields which are only
+// used as condition ({{if .Paid}}) become true. The values are realistic placeholders chosen by the names of the
+// fields, e.g. names for 'Customer', dates for 'DueDate' and amounts for 'Total'. Dates written by an action are
+// formatted strings, dates passed to a function are time.Time values.
+//
+// Example:
+//
+//	data, err := docx.GenerateSampleData(templateBytes)
+//	preview, err := docx.ProcessTemplateDocx(templateBytes, data)
+func GenerateSampleData(input []byte) (map[string]interface{}, error) {
+	root, err := inferTemplateData(input)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := root.value("", 0).(map[string]interface{})
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return data, nil
+}
+
+// inferTemplateData returns the shape of the data used by the template actions of all parts of the template.
+func inferTemplateData(input []byte) (*sampleField, error) {
+	root := &sampleField{}
+	err := parseTemplateParts(input, func(tree *parse.Tree, trees map[string]*parse.Tree) {
+		inferrer := &sampleInferrer{trees: trees, expanding: make(map[string]bool)}
+		inferrer.walk(tree.Root, root, map[string]*sampleField{"$": root})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// parseTemplateParts parses the template actions of all parts of the template and calls visit with the parse tree
+// of every part which contains actions, together with the templates defined by the part.
+func parseTemplateParts(input []byte, visit func(tree *parse.Tree, trees map[string]*parse.Tree)) error {
+	parts, err := readArchiveParts(input, isTemplatePart)
+	if err != nil {
+		return err
+	}
+	if _, exists := parts[DocumentXml]; !exists {
+		return fmt.Errorf("%w, %s is missing", ErrInvalidArchive, DocumentXml)
+	}
+
+	engine := newTemplateEngine()
+	for name, part := range parts {
+		source := prepareTemplateSource(part)
+		if !strings.Contains(source, TemplateOpenDelimiter) {
+			continue
+		}
+		if source, err = rewriteStyleActions(source); err != nil {
+			return engine.diagnoseTemplate(name, part, err)
+		}
+		if source, err = rewriteColumnActions(hoistRowActions(source)); err != nil {
+			return engine.diagnoseTemplate(name, part, err)
+		}
+		trees := make(map[string]*parse.Tree)
+		tree := parse.New(name)
+		// the functions are unknown, e.g. those passed to ProcessTemplateDocxWithFuncs
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(source, TemplateOpenDelimiter, TemplateCloseDelimiter, trees); err != nil {
+			return engine.diagnoseTemplate(name, part, err)
+		}
+		visit(tree, trees)
+	}
+	return nil
+}
+
+// sampleInferrer infers the shape of the template data from the parse tree of a template part.
+type sampleInferrer struct {
+	// trees are the templates defined by the part, e.g. with {{define}} or {{block}}
+	trees map[string]*parse.Tree
+	// expanding are the names of the templates which are currently walked, to stop recursive templates
+	expanding map[string]bool
+}
+
+// walk infers the shape of the data used by the node, dot is the shape of {{.}} or nil if unknown.
+func (s *sampleInferrer) walk(node parse.Node, dot *sampleField, vars map[string]*sampleField) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		// variables declared by actions are visible until the end of the list
+		vars = copyVars(vars)
+		for _, child := range n.Nodes {
+			s.walk(child, dot, vars)
+		}
+	case *parse.ActionNode:
+		value := s.pipe(n.Pipe, dot, vars, len(n.Pipe.Decl) == 0)
+		for _, variable := range n.Pipe.Decl {
+			vars[variable.Ident[0]] = value
+		}
+	case *parse.IfNode:
+		if value := s.pipe(n.Pipe, dot, vars, false); value != nil {
+			value.condition = true
+		}
+		s.walk(n.List, dot, vars)
+		s.walk(n.ElseList, dot, vars)
+	case *parse.WithNode:
+		value := s.pipe(n.Pipe, dot, vars, false)
+		inner := copyVars(vars)
+		for _, variable := range n.Pipe.Decl {
+			inner[variable.Ident[0]] = value
+		}
+		s.walk(n.List, value, inner)
+		s.walk(n.ElseList, dot, vars)
+	case *parse.RangeNode:
+		var element *sampleField
+		if value := s.pipe(n.Pipe, dot, vars, false); value != nil {
+			if value.element == nil {
+				value.element = &sampleField{name: singular(value.name)}
+			}
+			element = value.element
+		}
+		inner := copyVars(vars)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			inner[n.Pipe.Decl[0].Ident[0]] = element
+		case 2:
+			inner[n.Pipe.Decl[1].Ident[0]] = element
+		}
+		s.walk(n.List, element, inner)
+		s.walk(n.ElseList, dot, vars)
+	case *parse.TemplateNode:
+		tree, defined := s.trees[n.Name]
+		if !defined || s.expanding[n.Name] {
+			return
+		}
+		var value *sampleField
+		if n.Pipe != nil {
+			value = s.pipe(n.Pipe, dot, vars, false)
+		}
+		s.expanding[n.Name] = true
+		s.walk(tree.Root, value, map[string]*sampleField{"$": value})
+		delete(s.expanding, n.Name)
+	}
+}
+
+// pipe infers the shape of the data used by the pipeline and returns the shape of its value, nil if the value is not
+// a field of the data. Output is true if the value of the pipeline is written into the document.
+func (s *sampleInferrer) pipe(pipe *parse.PipeNode, dot *sampleField, vars map[string]*sampleField, output bool) *sampleField {
+	if pipe == nil {
+		return nil
+	}
+	var value *sampleField
+	for i, cmd := range pipe.Cmds {
+		// the first argument of a command is the function if it is an identifier, all others are its arguments
+		isCall := len(cmd.Args) > 1 || i > 0
+		if _, isFunction := cmd.Args[0].(*parse.IdentifierNode); isFunction {
+			isCall = true
+		}
+		for _, arg := range cmd.Args {
+			field := s.operand(arg, dot, vars)
+			if field == nil {
+				continue
+			}
+			if isCall {
+				field.output, field.argument = true, true
+			}
+			if len(pipe.Cmds) == 1 && len(cmd.Args) == 1 {
+				value = field
+			}
+		}
+	}
+	if value != nil && output {
+		value.output = true
+	}
+	return value
+}
+
+// operand returns the shape of the field which the operand accesses, nil if it is not a field of the data.
+func (s *sampleInferrer) operand(node parse.Node, dot *sampleField, vars map[string]*sampleField) *sampleField {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return fieldPath(dot, n.Ident)
+	case *parse.VariableNode:
+		return fieldPath(vars[n.Ident[0]], n.Ident[1:])
+	case *parse.ChainNode:
+		if pipe, isPipe := n.Node.(*parse.PipeNode); isPipe {
+			return fieldPath(s.pipe(pipe, dot, vars, false), n.Field)
+		}
+	case *parse.PipeNode:
+		return s.pipe(n, dot, vars, false)
+	}
+	return nil
+}
+
+// fieldPath returns the shape of the field at the path below the value, nil if the value is unknown.
+func fieldPath(value *sampleField, path []string) *sampleField {
+	for _, name := range path {
+		if value == nil {
+			return nil
+		}
+		value = value.field(name)
+	}
+	return value
+}
+
+// copyVars returns a copy of the variables, so declarations inside a block are not visible outside.
+func copyVars(vars map[string]*sampleField) map[string]*sampleField {
+	copied := make(map[string]*sampleField, len(vars))
+	for name, value := range vars {
+		copied[name] = value
+	}
+	return copied
+}
+
+// value returns the sample value of the field, parent is the name of the value the field belongs to and index is
+// the position inside its list.
+func (f *sampleField) value(parent string, index int) interface{} {
+	switch {
+	case f.element != nil:
+		items := make([]interface{}, sampleListLength)
+		for i := range items {
+			items[i] = f.element.value(parent, i)
+		}
+		return items
+	case len(f.fields) > 0:
+		values := make(map[string]interface{}, len(f.fields))
+		for name, field := range f.fields {
+			values[name] = field.value(f.name, index)
+		}
+		return values
+	case f.condition && !f.output:
+		return index%2 == 0
+	}
+	name := f.name
+	if words := splitWords(name); len(words) == 1 && containsString(genericSampleNames, words[0]) {
+		name = parent + name
+	}
+	return sampleValue(name, index, f.argument)
+}
+
+// sampleValue returns a realistic value for a field with the given name, e.g. a date for 'DueDate'.
+// Dates are time.Time values if they are passed to a function, formatted strings otherwise.
+func sampleValue(name string, index int, argument bool) interface{} {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return fmt.Sprintf("Sample %d", index+1)
+	}
+	first, last := words[0], words[len(words)-1]
+	has := func(candidates ...string) bool {
+		for _, word := range words {
+			if containsString(candidates, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case containsString([]string{"is", "has", "can", "should", "show", "include", "enable", "enabled"}, first):
+		return index%2 == 0
+	case has("date", "deadline", "birthday", "timestamp") || containsString([]string{"at", "on", "day", "time"}, last):
+		date := sampleDate.AddDate(0, 0, 7*index)
+		if argument {
+			return date
+		}
+		return date.Format("January 2, 2006")
+	case has("email", "mail"):
+		return strings.ToLower(strings.ReplaceAll(samplePeople[index%len(samplePeople)], " ", ".")) + "@example.com"
+	case has("phone", "tel", "mobile", "fax"):
+		return fmt.Sprintf("+1 555 01%02d", index)
+	case has("url", "website", "link", "homepage"):
+		return "https://example.com"
+	case has("amount", "total", "subtotal", "price", "cost", "sum", "fee", "balance", "salary", "tax", "vat",
+		"discount", "payment", "budget", "revenue"):
+		return sampleAmounts[index%len(sampleAmounts)]
+	case has("percent", "percentage", "rate", "ratio"):
+		return 19.0
+	case last == "year":
+		return sampleDate.Year() + index
+	case has("count", "quantity", "qty", "age", "years", "days", "hours", "units", "pages"):
+		return sampleCounts[index%len(sampleCounts)]
+	case has("number", "id", "code", "ref", "reference", "no"):
+		prefix := strings.ToUpper(first)
+		if len(prefix) > 3 {
+			prefix = prefix[:3]
+		}
+		if len(words) == 1 {
+			return fmt.Sprintf("%d", 1001+index)
+		}
+		return fmt.Sprintf("%s-%d", prefix, 1001+index)
+	case has("first", "given") && has("name") || first == "firstname":
+		return sampleFirstName[index%len(sampleFirstName)]
+	case has("last", "family", "surname") || first == "lastname":
+		return sampleLastName[index%len(sampleLastName)]
+	case has("company", "organization", "organisation", "employer", "vendor", "supplier", "client", "firm", "business"):
+		return sampleCompanies[index%len(sampleCompanies)]
+	case has("name", "author", "contact", "manager", "owner", "person", "signer", "recipient", "employee", "user",
+		"customer"):
+		return samplePeople[index%len(samplePeople)]
+	case has("city", "town"):
+		return sampleCities[index%len(sampleCities)]
+	case has("zip", "postal", "postcode"):
+		return fmt.Sprintf("%d", 12345+index)
+	case has("country"):
+		return "United States"
+	case has("street", "address"):
+		return fmt.Sprintf("Main Street %d", index+1)
+	case has("status", "state"):
+		return sampleStatuses[index%len(sampleStatuses)]
+	case has("currency"):
+		return "USD"
+	case has("description", "notes", "note", "comment", "comments", "summary", "text", "body", "message", "content",
+		"details"):
+		return "Lorem ipsum dolor sit amet, consectetur adipiscing elit."
+	}
+
+	value := "Sample " + strings.Join(words, " ")
+	if index > 0 {
+		value += fmt.Sprintf(" %d", index+1)
+	}
+	return value
+}
+
+// splitWords splits the field name into lowercase words at case changes, digits, underscores and hyphens,
+// e.g. 'InvoiceNumber' into 'invoice' and 'number'.
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		boundary := !unicode.IsLetter(r)
+		startsWord := unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+		if (boundary || startsWord) && len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+		if !boundary {
+			word = append(word, r)
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, strings.ToLower(string(word)))
+	}
+	return words
+}
+
+// singular returns the singular of the plural field name, e.g. 'Item' for 'Items'.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ses") || strings.HasSuffix(name, "xes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return name[:len(name)-1]
+	}
+	return name
+}
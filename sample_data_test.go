@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSampleData(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>Invoice {{.InvoiceNumber}} for {{.Customer.Name}}, {{.Customer.Email}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Due {{.DueDate}}, issued {{formatDate .IssueDate}}{{if .Paid}} (paid){{end}}</w:t></w:r></w:p>`+
+			`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{range $i, $item := .Items}}{{$item.Description}}</w:t></w:r></w:p></w:tc>`+
+			`<w:tc><w:p><w:r><w:t>{{.Quantity}} x {{.Price}} {{$.Currency}}{{end}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:t>{{with .Company}}{{.Name}} {{.Address.City}}{{end}} {{range .Tags}}{{.}} {{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{style bold=.IsOverdue}}{{.Total}}{{end}} {{template "signature" .Signer}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{define "signature"}}{{.FirstName}} {{.LastName}}{{end}}</w:t></w:r></w:p>`)
+
+	data, err := GenerateSampleData(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"InvoiceNumber": "INV-1001",
+		"Customer":      map[string]interface{}{"Name": "Jane Doe", "Email": "jane.doe@example.com"},
+		"DueDate":       "March 14, 2025",
+		"IssueDate":     time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+		"Paid":          true,
+		"Items": []interface{}{
+			map[string]interface{}{"Description": "Lorem ipsum dolor sit amet, consectetur adipiscing elit.", "Quantity": 3, "Price": 1250.75},
+			map[string]interface{}{"Description": "Lorem ipsum dolor sit amet, consectetur adipiscing elit.", "Quantity": 12, "Price": 349.9},
+			map[string]interface{}{"Description": "Lorem ipsum dolor sit amet, consectetur adipiscing elit.", "Quantity": 7, "Price": 89.5},
+		},
+		"Currency":  "USD",
+		"Company":   map[string]interface{}{"Name": "ACME Corp", "Address": map[string]interface{}{"City": "Springfield"}},
+		"Tags":      []interface{}{"Sample tag", "Sample tag 2", "Sample tag 3"},
+		"IsOverdue": true,
+		"Total":     1250.75,
+		"Signer":    map[string]interface{}{"FirstName": "Jane", "LastName": "Doe"},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("unexpected sample data:\n%#v\nexpected:\n%#v", data, expected)
+	}
+
+	// the template renders with the sample data
+	output, err := ProcessTemplateDocxWithFuncs(input, data, map[string]interface{}{
+		"formatDate": func(date time.Time) string { return date.Format("2006-01-02") },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "Invoice INV-1001 for Jane Doe") {
+		t.Errorf("expected the sample data in the document: %s", document)
+	}
+}
+
+func TestGenerateSampleData_ParseError(t *testing.T) {
+	if _, err := GenerateSampleData(buildTestDocx(t, `<w:p><w:r><w:t>{{if .Paid}}</w:t></w:r></w:p>`)); err == nil {
+		t.Error("expected an error for an unclosed block")
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	for name, expected := range map[string]string{
+		"InvoiceNumber": "invoice number",
+		"createdAt":     "created at",
+		"VATRate":       "vat rate",
+		"due_date":      "due date",
+		"Address2":      "address",
+	} {
+		if words := strings.Join(splitWords(name), " "); words != expected {
+			t.Errorf("expected %q for %s, got %q", expected, name, words)
+		}
+	}
+}
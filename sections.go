@@ -0,0 +1,243 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TwipsPerInch is the amount of twentieths of a point (twips) per inch, the unit of page sizes and margins.
+	TwipsPerInch = 1440
+	// TwipsPerCentimeter is the amount of twips per centimeter, rounded like Word does.
+	TwipsPerCentimeter = 567
+)
+
+// Orientation is the orientation of the pages of a section.
+type Orientation string
+
+const (
+	// OrientationPortrait are pages which are higher than wide.
+	OrientationPortrait Orientation = "portrait"
+	// OrientationLandscape are pages which are wider than high.
+	OrientationLandscape Orientation = "landscape"
+)
+
+// PageMargins are the margins of the pages of a section in twips, see TwipsPerCentimeter.
+type PageMargins struct {
+	Top, Right, Bottom, Left int
+	// Header and Footer are the distances of the header and the footer from the edge of the page.
+	Header, Footer int
+	// Gutter is the extra space which is added to the margin for binding.
+	Gutter int
+}
+
+// Section is the page setup of a section of the document body, see Sections.
+// Sizes are given in twips (twentieths of a point), values the section does not define are zero.
+type Section struct {
+	PageWidth   int
+	PageHeight  int
+	Orientation Orientation
+	Margins     PageMargins
+}
+
+// Sections returns the page setup of all sections of the document body in document order.
+// A document always has at least one section, the one at the end of the body.
+//
+// Example:
+//
+//	sections, err := doc.Sections()
+//	for i, section := range sections {
+//	    fmt.Printf("section %d: %.1f x %.1f cm\n", i,
+//	        float64(section.PageWidth)/docx.TwipsPerCentimeter, float64(section.PageHeight)/docx.TwipsPerCentimeter)
+//	}
+func (d *Document) Sections() ([]Section, error) {
+	data := d.files[DocumentXml]
+	ranges := sectionProperties(data)
+	if len(ranges) == 0 {
+		return []Section{{Orientation: OrientationPortrait}}, nil
+	}
+	sections := make([]Section, len(ranges))
+	for i, r := range ranges {
+		_, content, _ := splitElement(string(data[r[0]:r[1]]))
+		sections[i] = parseSection(content)
+	}
+	return sections, nil
+}
+
+// parseSection returns the page setup of the content of section properties.
+func parseSection(content string) Section {
+	section := Section{Orientation: OrientationPortrait}
+	for _, child := range childElements(content) {
+		attributes := elementAttributes(content[child.start:child.end])
+		switch child.name {
+		case "pgSz":
+			section.PageWidth, _ = strconv.Atoi(attributes["w:w"])
+			section.PageHeight, _ = strconv.Atoi(attributes["w:h"])
+			if attributes["w:orient"] == string(OrientationLandscape) {
+				section.Orientation = OrientationLandscape
+			}
+		case "pgMar":
+			margins := &section.Margins
+			for name, value := range map[string]*int{
+				"w:top": &margins.Top, "w:right": &margins.Right, "w:bottom": &margins.Bottom, "w:left": &margins.Left,
+				"w:header": &margins.Header, "w:footer": &margins.Footer, "w:gutter": &margins.Gutter,
+			} {
+				*value, _ = strconv.Atoi(attributes[name])
+			}
+		}
+	}
+	return section
+}
+
+// elementAttributes returns the attributes of the open tag of the element by their qualified names.
+func elementAttributes(element string) map[string]string {
+	openTag := element[:strings.IndexByte(element, '>')+1]
+	attributes := make(map[string]string)
+	for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(openTag, -1) {
+		attributes[attribute[1]] = attribute[2]
+	}
+	return attributes
+}
+
+// SetSectionPageSize sets the page size of a section in twips, e.g. 11906 x 16838 for A4 portrait.
+// The orientation follows the size: pages which are wider than high are landscape.
+// Sections are numbered from 0 in document order, see Sections.
+func (d *Document) SetSectionPageSize(section, width, height int) error {
+	if section < 0 {
+		return fmt.Errorf("invalid section %d", section)
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid page size %d x %d", width, height)
+	}
+	return d.updateSections(section, func(content string) string {
+		return setOrderedElement(content, sectionPropertiesOrder, "pgSz", pageSizeXml(content, width, height))
+	})
+}
+
+// SetSectionOrientation sets the orientation of a section by swapping the width and the height of its pages if
+// needed. The margins are kept. The section must have a page size, see SetSectionPageSize.
+func (d *Document) SetSectionOrientation(section int, orientation Orientation) error {
+	if section < 0 {
+		return fmt.Errorf("invalid section %d", section)
+	}
+	sections, err := d.Sections()
+	if err != nil {
+		return err
+	}
+	if section >= len(sections) {
+		return fmt.Errorf("invalid section %d, the document has %d sections", section, len(sections))
+	}
+	if sections[section].PageWidth == 0 || sections[section].PageHeight == 0 {
+		return fmt.Errorf("section %d has no page size", section)
+	}
+	if orientation != OrientationPortrait && orientation != OrientationLandscape {
+		return fmt.Errorf("invalid orientation %q", orientation)
+	}
+	return d.updateSections(section, func(content string) string {
+		content, _ = orientSection(content, orientation)
+		return content
+	})
+}
+
+// orientSection returns the content of section properties with pages of the given orientation.
+func orientSection(content string, orientation Orientation) (string, error) {
+	section := parseSection(content)
+	width, height := section.PageWidth, section.PageHeight
+	switch orientation {
+	case OrientationLandscape:
+		width, height = max(width, height), min(width, height)
+	case OrientationPortrait:
+		width, height = min(width, height), max(width, height)
+	default:
+		return content, fmt.Errorf("invalid orientation %q", orientation)
+	}
+	return setOrderedElement(content, sectionPropertiesOrder, "pgSz", pageSizeXml(content, width, height)), nil
+}
+
+// pageSizeXml returns the page size element of the given size, keeping the paper code of the existing element.
+func pageSizeXml(content string, width, height int) string {
+	element := fmt.Sprintf(`<w:pgSz w:w="%d" w:h="%d"`, width, height)
+	if width > height {
+		element += ` w:orient="landscape"`
+	}
+	for _, child := range childElements(content) {
+		if code, exists := elementAttributes(content[child.start:child.end])["w:code"]; child.name == "pgSz" && exists {
+			element += ` w:code="` + code + `"`
+		}
+	}
+	return element + "/>"
+}
+
+// SetSectionMargins sets the page margins of a section in twips, see SetSectionPageSize.
+func (d *Document) SetSectionMargins(section int, margins PageMargins) error {
+	if section < 0 {
+		return fmt.Errorf("invalid section %d", section)
+	}
+	element := fmt.Sprintf(`<w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="%d" w:footer="%d" w:gutter="%d"/>`,
+		margins.Top, margins.Right, margins.Bottom, margins.Left, margins.Header, margins.Footer, margins.Gutter)
+	return d.updateSections(section, func(content string) string {
+		return setOrderedElement(content, sectionPropertiesOrder, "pgMar", element)
+	})
+}
+
+// landscapeSection is a replacement value which places another value into a landscape section of its own.
+type landscapeSection struct {
+	value interface{}
+}
+
+// Landscape returns a replacement value which places the value, e.g. a wide Table, into a landscape section of its
+// own: the pages before and after it keep the page setup of the section of the placeholder. The placeholder must be
+// located inside the document body, and its section must have a page size.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "transactions": docx.Landscape(docx.Table{Headers: columns, Rows: rows}),
+//	})
+func Landscape(value interface{}) interface{} {
+	return landscapeSection{value: value}
+}
+
+// String returns the text of the value.
+func (l landscapeSection) String() string {
+	return fmt.Sprint(l.value)
+}
+
+// inlineXml returns a run with the marker of the section, which is inserted by insertBlocks.
+func (l landscapeSection) inlineXml(ctx *valueContext) (string, error) {
+	if ctx.part != DocumentXml {
+		return "", fmt.Errorf("landscape sections must be placed inside the document body, not %s", ctx.part)
+	}
+	return ctx.blockMarkerRun(l), nil
+}
+
+// blockXml returns the value between a paragraph which ends the section in front of it and a paragraph which ends
+// the landscape section.
+func (l landscapeSection) blockXml(ctx *valueContext) (string, error) {
+	openTag, content, closeTag := splitElement(ctx.sectionProperties)
+	section := parseSection(content)
+	if section.PageWidth == 0 || section.PageHeight == 0 {
+		return "", fmt.Errorf("the section of a landscape section must have a page size")
+	}
+	landscape, err := orientSection(content, OrientationLandscape)
+	if err != nil {
+		return "", err
+	}
+	// the landscape section starts on a new page
+	landscape = setOrderedElement(landscape, sectionPropertiesOrder, "type", "")
+
+	var body string
+	if block, isBlock := l.value.(blockValue); isBlock {
+		body, err = block.blockXml(ctx)
+	} else {
+		var runs string
+		runs, err = ctx.valueXml(l.value)
+		body = "<w:p>" + runs + "</w:p>"
+	}
+	if err != nil {
+		return "", err
+	}
+	return "<w:p><w:pPr>" + ctx.sectionProperties + "</w:pPr></w:p>" + body +
+		"<w:p><w:pPr>" + openTag + landscape + closeTag + "</w:pPr></w:p>", nil
+}
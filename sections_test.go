@@ -0,0 +1,113 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Sections(t *testing.T) {
+	body := `<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906" w:h="16838" w:code="9"/>` +
+		`<w:pgMar w:top="1417" w:right="1417" w:bottom="1134" w:left="1417" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr></w:pPr></w:p>` +
+		`<w:p><w:r><w:t>text</w:t></w:r></w:p>` +
+		`<w:sectPr><w:type w:val="continuous"/><w:cols w:space="708"/></w:sectPr>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections, err := doc.Sections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Section{
+		{PageWidth: 11906, PageHeight: 16838, Orientation: OrientationPortrait,
+			Margins: PageMargins{Top: 1417, Right: 1417, Bottom: 1134, Left: 1417, Header: 708, Footer: 708}},
+		{Orientation: OrientationPortrait},
+	}
+	if !reflect.DeepEqual(sections, expected) {
+		t.Fatalf("expected %+v, have %+v", expected, sections)
+	}
+
+	if err := doc.SetSectionOrientation(0, OrientationLandscape); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetSectionOrientation(1, OrientationLandscape); err == nil {
+		t.Error("expected error for section without page size")
+	}
+	if err := doc.SetSectionOrientation(0, "sideways"); err == nil {
+		t.Error("expected error for invalid orientation")
+	}
+	if err := doc.SetSectionPageSize(1, 12240, 15840); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetSectionMargins(1, PageMargins{Top: TwipsPerInch, Right: TwipsPerInch, Bottom: TwipsPerInch, Left: TwipsPerInch}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetSectionPageSize(2, 12240, 15840); err == nil {
+		t.Error("expected error for unknown section")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:sectPr><w:pgSz w:w="16838" w:h="11906" w:orient="landscape" w:code="9"/><w:pgMar w:top="1417"`,
+		`<w:sectPr><w:type w:val="continuous"/><w:pgSz w:w="12240" w:h="15840"/>` +
+			`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="0" w:footer="0" w:gutter="0"/><w:cols w:space="708"/></w:sectPr>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+}
+
+func TestLandscape(t *testing.T) {
+	body := `<w:p><w:r><w:t>Before</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{table}</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>After</w:t></w:r></w:p>` +
+		`<w:sectPr><w:type w:val="continuous"/><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1417" w:right="1417" w:bottom="1134" w:left="1417" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{
+		"table": Landscape(Table{Headers: []string{"A", "B"}, Rows: [][]string{{"1", "2"}}}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sections, err := doc.Sections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var orientations []Orientation
+	for _, section := range sections {
+		orientations = append(orientations, section.Orientation)
+	}
+	if expected := []Orientation{OrientationPortrait, OrientationLandscape, OrientationPortrait}; !reflect.DeepEqual(orientations, expected) {
+		t.Fatalf("expected %v, have %v", expected, orientations)
+	}
+	document := string(doc.files[DocumentXml])
+	for _, expected := range []string{
+		`Before</w:t></w:r></w:p><w:p><w:pPr><w:sectPr><w:type w:val="continuous"/><w:pgSz w:w="11906" w:h="16838"/>`,
+		`</w:tbl><w:p><w:pPr><w:sectPr><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/><w:pgMar w:top="1417"`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+	if sections[1].Margins != sections[0].Margins {
+		t.Errorf("expected the margins to be kept, have %+v", sections[1].Margins)
+	}
+
+	doc, err = OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{table}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"table": Landscape("wide")}); err == nil {
+		t.Error("expected error for section without page size")
+	}
+}
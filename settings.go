@@ -0,0 +1,78 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// SettingsXml is the path of the document settings part.
+	SettingsXml = "word/settings.xml"
+
+	// RelationshipTypeSettings is the relationship type of the document settings part.
+	RelationshipTypeSettings = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	// ContentTypeSettings is the content type of the document settings part.
+	ContentTypeSettings = "application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"
+)
+
+var (
+	// settingsRegex matches the root element of the settings part and captures the open tag and the content.
+	settingsRegex = regexp.MustCompile(`(?s)(<w:settings(?:\s[^>]*)?>)(.*)</w:settings>`)
+
+	// settingsOrder is the order of the child elements of the document settings required by the schema.
+	settingsOrder = []string{
+		"writeProtection", "view", "zoom", "removePersonalInformation", "removeDateAndTime", "doNotDisplayPageBoundaries",
+		"displayBackgroundShape", "printPostScriptOverText", "printFractionalCharacterWidth", "printFormsData",
+		"embedTrueTypeFonts", "embedSystemFonts", "saveSubsetFonts", "saveFormsData", "mirrorMargins",
+		"alignBordersAndEdges", "bordersDoNotSurroundHeader", "bordersDoNotSurroundFooter", "gutterAtTop",
+		"hideSpellingErrors", "hideGrammaticalErrors", "activeWritingStyle", "proofState", "formsDesign",
+		"attachedTemplate", "linkStyles", "stylePaneFormatFilter", "stylePaneSortMethod", "documentType", "mailMerge",
+		"revisionView", "trackRevisions", "doNotTrackMoves", "doNotTrackFormatting", "documentProtection",
+		"autoFormatOverride", "styleLockTheme", "styleLockQFSet", "defaultTabStop", "autoHyphenation",
+		"consecutiveHyphenLimit", "hyphenationZone", "doNotHyphenateCaps", "showEnvelope", "summaryLength",
+		"clickAndTypeStyle", "defaultTableStyle", "evenAndOddHeaders", "bookFoldRevPrinting", "bookFoldPrinting",
+		"bookFoldPrintingSheets", "drawingGridHorizontalSpacing", "drawingGridVerticalSpacing",
+		"displayHorizontalDrawingGridEvery", "displayVerticalDrawingGridEvery", "doNotUseMarginsForDrawingGridOrigin",
+		"drawingGridHorizontalOrigin", "drawingGridVerticalOrigin", "doNotShadeFormData", "noPunctuationKerning",
+		"characterSpacingControl", "printTwoOnOne", "strictFirstAndLastChars", "noLineBreaksAfter",
+		"noLineBreaksBefore", "savePreviewPicture", "doNotValidateAgainstSchema", "saveInvalidXml",
+		"ignoreMixedContent", "alwaysShowPlaceholderText", "doNotDemarcateInvalidXml", "saveXmlDataOnly",
+		"useXSLTWhenSaving", "saveThroughXslt", "showXMLTags", "alwaysMergeEmptyNamespace", "updateFields",
+		"hdrShapeDefaults", "footnotePr", "endnotePr", "compat", "docVars", "rsids", "mathPr", "attachedSchema",
+		"themeFontLang", "clrSchemeMapping", "doNotIncludeSubdocsInStats", "doNotAutoCompressPictures", "forceUpgrade",
+		"captions", "readModeInkLockDown", "smartTagType", "schemaLibrary", "shapeDefaults", "doNotEmbedSmartTags",
+		"decimalSymbol", "listSeparator",
+	}
+
+	emptySettings = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:settings>`)
+)
+
+// setSetting sets the element of the given local name inside the document settings, replacing an existing element
+// of the same name. The settings part is created if the document does not have one.
+func (d *Document) setSetting(name, element string) error {
+	settings, exists, err := d.part(SettingsXml)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := d.addRelationship(DocumentXml, RelationshipTypeSettings, "settings.xml", false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(SettingsXml, ContentTypeSettings); err != nil {
+			return err
+		}
+		settings = emptySettings
+	}
+
+	match := settingsRegex.FindSubmatchIndex(settings)
+	if match == nil {
+		return fmt.Errorf("invalid settings part %s", SettingsXml)
+	}
+	content := setOrderedElement(string(settings[match[4]:match[5]]), settingsOrder, name, element)
+
+	changed := append([]byte{}, settings[:match[5]]...)
+	changed = append(changed[:match[4]], content...)
+	changed = append(changed, settings[match[5]:]...)
+	return d.setPart(SettingsXml, changed)
+}
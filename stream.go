@@ -0,0 +1,200 @@
+package docx
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	zipLocalHeaderSignature    = 0x04034b50
+	zipDataDescriptorSignature = 0x08074b50
+	zipLocalHeaderLen          = 30
+	zipDataDescriptorFlag      = 0x8
+	zip64ExtraID               = 0x0001
+)
+
+// ErrStreamUnsupported is returned if a ZIP archive cannot be read sequentially,
+// e.g. because an uncompressed entry does not declare its size up front. ProcessBytes must be used instead.
+var ErrStreamUnsupported = errors.New("archive cannot be read as stream")
+
+// zipStreamReader reads the entries of a ZIP archive sequentially using the local file headers.
+// Unlike zip.Reader it does not need random access, so archives can be read from any io.Reader.
+type zipStreamReader struct {
+	r     *bufio.Reader
+	entry *zipStreamEntry
+}
+
+// zipStreamEntry is a single entry of a ZIP archive which is read sequentially.
+// Its content is only available until the next entry is requested.
+type zipStreamEntry struct {
+	Name string
+
+	src *bufio.Reader
+	// limited is the compressed content if its size is known, the remainder is skipped after reading
+	limited *io.LimitedReader
+	reader  io.Reader
+	crc     hash.Hash32
+	flags   uint16
+	zip64   bool
+	wantCRC uint32
+	size    uint64
+	read    uint64
+	done    bool
+}
+
+// newZipStreamReader returns a reader for the ZIP archive given by r.
+func newZipStreamReader(r io.Reader) *zipStreamReader {
+	return &zipStreamReader{r: bufio.NewReader(r)}
+}
+
+// Next skips the remaining content of the current entry and returns the next entry.
+// io.EOF is returned once the central directory is reached.
+func (z *zipStreamReader) Next() (*zipStreamEntry, error) {
+	if entry := z.entry; entry != nil {
+		z.entry = nil
+		if _, err := io.Copy(io.Discard, entry); err != nil {
+			return nil, err
+		}
+		if entry.limited != nil {
+			if _, err := io.Copy(io.Discard, entry.limited); err != nil {
+				return nil, fmt.Errorf("unable to read %s: %w", entry.Name, err)
+			}
+		}
+	}
+
+	var header [zipLocalHeaderLen]byte
+	if _, err := io.ReadFull(z.r, header[:4]); err != nil {
+		return nil, fmt.Errorf("%w: unable to read ZIP entry: %w", ErrInvalidArchive, err)
+	}
+	if binary.LittleEndian.Uint32(header[:4]) != zipLocalHeaderSignature {
+		// the central directory follows after the last entry
+		return nil, io.EOF
+	}
+	if _, err := io.ReadFull(z.r, header[4:]); err != nil {
+		return nil, fmt.Errorf("%w: unable to read ZIP entry: %w", ErrInvalidArchive, err)
+	}
+
+	flags := binary.LittleEndian.Uint16(header[6:8])
+	method := binary.LittleEndian.Uint16(header[8:10])
+	compressedSize := uint64(binary.LittleEndian.Uint32(header[18:22]))
+	nameLen := int(binary.LittleEndian.Uint16(header[26:28]))
+	extraLen := int(binary.LittleEndian.Uint16(header[28:30]))
+
+	nameAndExtra := make([]byte, nameLen+extraLen)
+	if _, err := io.ReadFull(z.r, nameAndExtra); err != nil {
+		return nil, fmt.Errorf("%w: unable to read ZIP entry: %w", ErrInvalidArchive, err)
+	}
+	entry := &zipStreamEntry{
+		Name:    string(nameAndExtra[:nameLen]),
+		src:     z.r,
+		crc:     crc32.NewIEEE(),
+		flags:   flags,
+		wantCRC: binary.LittleEndian.Uint32(header[14:18]),
+		size:    uint64(binary.LittleEndian.Uint32(header[22:26])),
+	}
+
+	// ZIP64 entries keep the real sizes inside an extra field
+	extra := nameAndExtra[nameLen:]
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[:2])
+		fieldLen := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if 4+fieldLen > len(extra) {
+			break
+		}
+		if id == zip64ExtraID {
+			entry.zip64 = true
+			field := extra[4 : 4+fieldLen]
+			if entry.size == 0xFFFFFFFF && len(field) >= 8 {
+				entry.size = binary.LittleEndian.Uint64(field[:8])
+				field = field[8:]
+			}
+			if compressedSize == 0xFFFFFFFF && len(field) >= 8 {
+				compressedSize = binary.LittleEndian.Uint64(field[:8])
+			}
+		}
+		extra = extra[4+fieldLen:]
+	}
+
+	hasDataDescriptor := flags&zipDataDescriptorFlag != 0
+	var compressed io.Reader = z.r
+	if !hasDataDescriptor {
+		entry.limited = &io.LimitedReader{R: z.r, N: int64(compressedSize)}
+		compressed = entry.limited
+	}
+	switch {
+	case method == zip.Deflate:
+		// deflate streams are self-terminating and flate does not read beyond them from a io.ByteReader,
+		// so the size is not required up front
+		entry.reader = flate.NewReader(compressed)
+	case method == zip.Store && !hasDataDescriptor:
+		entry.reader = compressed
+	case method == zip.Store:
+		return nil, fmt.Errorf("%w: size of stored entry %s is unknown", ErrStreamUnsupported, entry.Name)
+	default:
+		return nil, fmt.Errorf("%w: %s uses compression method %d", zip.ErrAlgorithm, entry.Name, method)
+	}
+
+	z.entry = entry
+	return entry, nil
+}
+
+// Read reads the uncompressed content of the entry and verifies its checksum at the end.
+func (e *zipStreamEntry) Read(p []byte) (int, error) {
+	if e.done {
+		return 0, io.EOF
+	}
+	n, err := e.reader.Read(p)
+	e.crc.Write(p[:n])
+	e.read += uint64(n)
+	if err != io.EOF {
+		return n, err
+	}
+
+	e.done = true
+	if e.flags&zipDataDescriptorFlag != 0 {
+		if err := e.readDataDescriptor(); err != nil {
+			return n, err
+		}
+	}
+	if e.read != e.size || e.crc.Sum32() != e.wantCRC {
+		return n, fmt.Errorf("%w: %s", zip.ErrChecksum, e.Name)
+	}
+	return n, io.EOF
+}
+
+// readDataDescriptor reads the checksum and the sizes which follow the content of the entry.
+// The signature of the data descriptor is optional.
+func (e *zipStreamEntry) readDataDescriptor() error {
+	sizeLen := 4
+	if e.zip64 {
+		sizeLen = 8
+	}
+	descriptor := make([]byte, 4+2*sizeLen)
+	if _, err := io.ReadFull(e.src, descriptor[:4]); err != nil {
+		return fmt.Errorf("unable to read data descriptor of %s: %w", e.Name, err)
+	}
+	if binary.LittleEndian.Uint32(descriptor[:4]) == zipDataDescriptorSignature {
+		if _, err := io.ReadFull(e.src, descriptor[:4]); err != nil {
+			return fmt.Errorf("unable to read data descriptor of %s: %w", e.Name, err)
+		}
+	}
+	if _, err := io.ReadFull(e.src, descriptor[4:]); err != nil {
+		return fmt.Errorf("unable to read data descriptor of %s: %w", e.Name, err)
+	}
+
+	e.wantCRC = binary.LittleEndian.Uint32(descriptor[:4])
+	sizes := descriptor[4+sizeLen:]
+	if e.zip64 {
+		e.size = binary.LittleEndian.Uint64(sizes)
+	} else {
+		e.size = uint64(binary.LittleEndian.Uint32(sizes))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnresolvedPlaceholders is returned by strict processing if the document still contains placeholders after
+// all replacements, the error is an *UnresolvedPlaceholdersError which lists the placeholders.
+var ErrUnresolvedPlaceholders = errors.New("unresolved placeholders")
+
+// PartKind describes the kind of part in which a placeholder was found.
+type PartKind string
+
+const (
+	// PartBody is the main document part (word/document.xml).
+	PartBody PartKind = "body"
+	// PartHeader is a header part (word/headerN.xml).
+	PartHeader PartKind = "header"
+	// PartFooter is a footer part (word/footerN.xml).
+	PartFooter PartKind = "footer"
+	// PartFootnotes is the footnotes part (word/footnotes.xml).
+	PartFootnotes PartKind = "footnotes"
+	// PartEndnotes is the endnotes part (word/endnotes.xml).
+	PartEndnotes PartKind = "endnotes"
+	// PartChart is a chart part (word/charts/chartN.xml).
+	PartChart PartKind = "chart"
+)
+
+// partKind returns the kind of the given part.
+func partKind(name string) PartKind {
+	switch {
+	case HeaderPathRegex.MatchString(name):
+		return PartHeader
+	case FooterPathRegex.MatchString(name):
+		return PartFooter
+	case name == FootnotesXml:
+		return PartFootnotes
+	case name == EndnotesXml:
+		return PartEndnotes
+	case ChartPathRegex.MatchString(name):
+		return PartChart
+	default:
+		return PartBody
+	}
+}
+
+// UnresolvedPlaceholder is a placeholder which had no replacement.
+type UnresolvedPlaceholder struct {
+	// Key is the placeholder without delimiters, e.g. 'customer' for '{customer}'.
+	Key string
+	// Part is the name of the part inside the archive, e.g. 'word/header1.xml'.
+	Part string
+	Kind PartKind
+}
+
+// UnresolvedPlaceholdersError lists all placeholders which had no replacement, in document order.
+type UnresolvedPlaceholdersError struct {
+	Placeholders []UnresolvedPlaceholder
+}
+
+// Error lists the unresolved placeholders grouped by their part.
+func (e *UnresolvedPlaceholdersError) Error() string {
+	var parts []string
+	keys := make(map[string][]string)
+	for _, placeholder := range e.Placeholders {
+		if _, known := keys[placeholder.Part]; !known {
+			parts = append(parts, placeholder.Part)
+		}
+		keys[placeholder.Part] = append(keys[placeholder.Part], placeholder.Key)
+	}
+	var msg strings.Builder
+	msg.WriteString(ErrUnresolvedPlaceholders.Error())
+	for i, part := range parts {
+		if i > 0 {
+			msg.WriteString(";")
+		}
+		fmt.Fprintf(&msg, " %s (%s): %s", part, partKind(part), strings.Join(keys[part], ", "))
+	}
+	return msg.String()
+}
+
+// Unwrap returns ErrUnresolvedPlaceholders.
+func (e *UnresolvedPlaceholdersError) Unwrap() error {
+	return ErrUnresolvedPlaceholders
+}
+
+// UnresolvedPlaceholders returns all placeholders which are still inside the body, headers, footers, notes and
+// charts of the document, in document order. After ReplaceAll, these are the placeholders for which no value was
+// given.
+func (d *Document) UnresolvedPlaceholders() ([]UnresolvedPlaceholder, error) {
+	var unresolved []UnresolvedPlaceholder
+	for _, name := range append(d.xmlFiles(), d.chartFiles...) {
+		data := d.files[name]
+		parser := NewRunParser(data)
+		if err := parser.Execute(); err != nil {
+			return nil, err
+		}
+		placeholders, err := ParsePlaceholders(parser.Runs(), data)
+		if err != nil {
+			return nil, err
+		}
+		for _, placeholder := range placeholders {
+			unresolved = append(unresolved, UnresolvedPlaceholder{
+				Key:  RemovePlaceholderDelimiter(placeholder.Text(data)),
+				Part: name,
+				Kind: partKind(name),
+			})
+		}
+		if ChartPathRegex.MatchString(name) {
+			for _, key := range chartTextPlaceholders(data) {
+				unresolved = append(unresolved, UnresolvedPlaceholder{Key: key, Part: name, Kind: PartChart})
+			}
+		}
+	}
+	return unresolved, nil
+}
+
+// ProcessBytesStrict works like ProcessBytes but fails if the document contains placeholders without a
+// replacement. The returned *UnresolvedPlaceholdersError lists every unresolved placeholder with its part,
+// so templates and data which drifted apart are noticed before the document is delivered.
+//
+// Example:
+//
+//	outputBytes, err := docx.ProcessBytesStrict(templateBytes, replacements)
+//	var unresolved *docx.UnresolvedPlaceholdersError
+//	if errors.As(err, &unresolved) {
+//	    for _, p := range unresolved.Placeholders {
+//	        log.Printf("missing value for %s in %s", p.Key, p.Kind)
+//	    }
+//	}
+func ProcessBytesStrict(input []byte, replacements map[string]string) ([]byte, error) {
+	doc, err := OpenBytes(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document from bytes: %w", err)
+	}
+	defer doc.Close()
+
+	placeholderMap := make(PlaceholderMap)
+	for k, v := range replacements {
+		placeholderMap[k] = v
+	}
+	if err := doc.ReplaceAll(placeholderMap); err != nil {
+		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
+	}
+
+	unresolved, err := doc.UnresolvedPlaceholders()
+	if err != nil {
+		return nil, err
+	}
+	if len(unresolved) > 0 {
+		return nil, &UnresolvedPlaceholdersError{Placeholders: unresolved}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProcessBytesStrict(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}, {greeting}</w:t></w:r></w:p>`,
+		"word/header1.xml", `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{com</w:t></w:r><w:r><w:t>pany}</w:t></w:r></w:p></w:hdr>`,
+		"word/footer1.xml", `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{page}</w:t></w:r></w:p></w:ftr>`,
+	)
+
+	_, err := ProcessBytesStrict(input, map[string]string{"name": "Jane", "page": "1"})
+	var unresolved *UnresolvedPlaceholdersError
+	if !errors.As(err, &unresolved) || !errors.Is(err, ErrUnresolvedPlaceholders) {
+		t.Fatalf("expected UnresolvedPlaceholdersError, have %v", err)
+	}
+	expected := []UnresolvedPlaceholder{
+		{Key: "greeting", Part: DocumentXml, Kind: PartBody},
+		{Key: "company", Part: "word/header1.xml", Kind: PartHeader},
+	}
+	if len(unresolved.Placeholders) != len(expected) {
+		t.Fatalf("expected %v, have %v", expected, unresolved.Placeholders)
+	}
+	for i := range expected {
+		if unresolved.Placeholders[i] != expected[i] {
+			t.Errorf("expected %v, have %v", expected[i], unresolved.Placeholders[i])
+		}
+	}
+	if msg := err.Error(); !strings.Contains(msg, "word/document.xml (body): greeting; word/header1.xml (header): company") {
+		t.Errorf("unexpected error message: %s", msg)
+	}
+
+	if _, err := ProcessBytesStrict(input, map[string]string{"name": "Jane", "greeting": "hi", "company": "ACME", "page": "1"}); err != nil {
+		t.Errorf("expected no error if all placeholders are resolved, have %v", err)
+	}
+}
+
+func TestProcessBytesStrict_Chart(t *testing.T) {
+	chart := testChart(`<a:r><a:t>{report}</a:t></a:r>`, `<a:r><a:t>Revenue in {unit}</a:t></a:r>`, `{region}`)
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{report}</w:t></w:r></w:p>`, "word/charts/chart1.xml", chart)
+
+	_, err := ProcessBytesStrict(input, map[string]string{"report": "Sales"})
+	var unresolved *UnresolvedPlaceholdersError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("expected UnresolvedPlaceholdersError, have %v", err)
+	}
+	expected := []UnresolvedPlaceholder{
+		{Key: "unit", Part: "word/charts/chart1.xml", Kind: PartChart},
+		{Key: "region", Part: "word/charts/chart1.xml", Kind: PartChart},
+	}
+	if len(unresolved.Placeholders) != len(expected) {
+		t.Fatalf("expected %v, have %v", expected, unresolved.Placeholders)
+	}
+	for i := range expected {
+		if unresolved.Placeholders[i] != expected[i] {
+			t.Errorf("expected %v, have %v", expected[i], unresolved.Placeholders[i])
+		}
+	}
+
+	if _, err := ProcessBytesStrict(input, map[string]string{"report": "Sales", "unit": "EUR", "region": "EMEA"}); err != nil {
+		t.Errorf("expected no error if all placeholders are resolved, have %v", err)
+	}
+}
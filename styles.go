@@ -0,0 +1,435 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// StylesXml is the path of the style definitions part.
+	StylesXml = "word/styles.xml"
+
+	// RelationshipTypeStyles is the relationship type of the style definitions part.
+	RelationshipTypeStyles = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	// ContentTypeStyles is the content type of the style definitions part.
+	ContentTypeStyles = "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"
+
+	// linkedCharacterStyleSuffix is appended to the ID and name of the character style of linked styles,
+	// following the naming used by Word (e.g. 'Heading1' and 'Heading1Char').
+	linkedCharacterStyleSuffix = "Char"
+)
+
+var (
+	// ErrStyleNotFound is returned if a referenced style does not exist inside the document.
+	ErrStyleNotFound = errors.New("style not found")
+
+	// StyleRegex matches a complete style definition (<w:style>...</w:style>) and captures its attributes and content.
+	StyleRegex = regexp.MustCompile(`(?s)<w:style\s([^>]*)>(.*?)</w:style>`)
+	// styleValueRegex matches child elements with a w:val attribute and captures the local name and the value.
+	styleValueRegex = regexp.MustCompile(`<w:(name|basedOn|link)\s[^>]*?w:val="([^"]*)"`)
+
+	// stylesOpenRegex matches the open tag of the root element of the styles part.
+	stylesOpenRegex = regexp.MustCompile(`<w:styles(?:\s[^>]*)?>`)
+	// docDefaultsRegex matches the document defaults of the styles part and captures their content.
+	docDefaultsRegex = regexp.MustCompile(`(?s)<w:docDefaults>(.*?)</w:docDefaults>|<w:docDefaults/>`)
+	// runPropertiesDefaultRegex matches the default run properties and captures their content (<w:rPr>...</w:rPr>).
+	runPropertiesDefaultRegex = regexp.MustCompile(`(?s)<w:rPrDefault>(.*?)</w:rPrDefault>|<w:rPrDefault/>`)
+	// languageRegex matches the language element of run properties and captures the w:val attribute.
+	languageRegex = regexp.MustCompile(`<w:lang\s[^>]*?(w:val="[^"]*")[^>]*/>|<w:lang\s[^>]*/>`)
+
+	emptyStyles = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:styles>`)
+)
+
+// styleInfo is the parsed header of a style definition.
+type styleInfo struct {
+	id, name, styleType, basedOn, link string
+	// definition is the complete style definition (<w:style>...</w:style>)
+	definition string
+}
+
+// parseStyles returns all style definitions of the style definitions part.
+func parseStyles(data []byte) []styleInfo {
+	var styles []styleInfo
+	for _, match := range StyleRegex.FindAllSubmatch(data, -1) {
+		style := styleInfo{definition: string(match[0])}
+		for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(string(match[1]), -1) {
+			switch attribute[1] {
+			case "w:styleId":
+				style.id = html.UnescapeString(attribute[2])
+			case "w:type":
+				style.styleType = attribute[2]
+			}
+		}
+		for _, value := range styleValueRegex.FindAllSubmatch(match[2], -1) {
+			v := html.UnescapeString(string(value[2]))
+			switch string(value[1]) {
+			case "name":
+				style.name = v
+			case "basedOn":
+				style.basedOn = v
+			case "link":
+				style.link = v
+			}
+		}
+		styles = append(styles, style)
+	}
+	return styles
+}
+
+// styles returns the style definitions part and the parsed styles, the part is empty if it does not exist.
+func (d *Document) styles() ([]byte, []styleInfo, error) {
+	data, exists, err := d.part(StylesXml)
+	if err != nil || !exists {
+		return nil, nil, err
+	}
+	return data, parseStyles(data), nil
+}
+
+// characterStyleId returns the ID of the character style with the given ID or name.
+// Paragraph styles are resolved to their linked character style.
+func (d *Document) characterStyleId(style string) (string, error) {
+	_, styles, err := d.styles()
+	if err != nil {
+		return "", err
+	}
+
+	var found *styleInfo
+	for i := range styles {
+		if styles[i].id == style {
+			found = &styles[i]
+			break
+		}
+	}
+	if found == nil {
+		for i := range styles {
+			if strings.EqualFold(styles[i].name, style) {
+				found = &styles[i]
+				if styles[i].styleType == "character" {
+					break
+				}
+			}
+		}
+	}
+
+	switch {
+	case found == nil:
+		return "", fmt.Errorf("%w: %s", ErrStyleNotFound, style)
+	case found.styleType == "character":
+		return found.id, nil
+	case found.styleType == "paragraph" && found.link != "":
+		return found.link, nil
+	}
+	return "", fmt.Errorf("%w: %s is a %s style without linked character style", ErrStyleNotFound, style, found.styleType)
+}
+
+// LinkedStyle defines a pair of linked paragraph and character styles. Linked styles can be applied to whole
+// paragraphs as well as to parts of a paragraph, Word shows them as a single style.
+type LinkedStyle struct {
+	// ID is the style ID of the paragraph style, the character style uses the ID with the suffix 'Char'.
+	ID string
+	// Name is the name of the style shown in Word. If empty, the ID is used.
+	Name string
+	// BasedOn is the ID of the paragraph style from which the paragraph style inherits, e.g. 'Normal'.
+	BasedOn string
+	// RunProperties are the inner XML of the run properties shared by both styles, e.g. `<w:b/><w:color w:val="C00000"/>`.
+	RunProperties string
+	// ParagraphProperties are the inner XML of the paragraph properties of the paragraph style, e.g. `<w:spacing w:after="120"/>`.
+	ParagraphProperties string
+}
+
+// AddLinkedStyle adds the linked paragraph and character styles to the style definitions of the document.
+// Styles which already exist inside the document are kept unchanged, so the design of the template wins.
+// The IDs of the paragraph and the character style are returned.
+//
+// Example:
+//
+//	paragraphStyle, characterStyle, err := doc.AddLinkedStyle(docx.LinkedStyle{
+//	    ID:            "Highlight",
+//	    RunProperties: `<w:b/><w:color w:val="C00000"/>`,
+//	})
+//	doc.ReplaceAll(docx.PlaceholderMap{"total": docx.WithCharacterStyle("42 EUR", characterStyle)})
+func (d *Document) AddLinkedStyle(style LinkedStyle) (string, string, error) {
+	if style.ID == "" {
+		return "", "", fmt.Errorf("linked style requires an ID")
+	}
+	name := style.Name
+	if name == "" {
+		name = style.ID
+	}
+	characterId := style.ID + linkedCharacterStyleSuffix
+
+	data, styles, err := d.styles()
+	if err != nil {
+		return "", "", err
+	}
+	if data == nil {
+		data = emptyStyles
+	}
+
+	exists := func(id string) bool {
+		for _, s := range styles {
+			if s.id == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	var definitions strings.Builder
+	if !exists(style.ID) {
+		definitions.WriteString(fmt.Sprintf(`<w:style w:type="paragraph" w:customStyle="1" w:styleId="%s"><w:name w:val="%s"/>`,
+			html.EscapeString(style.ID), html.EscapeString(name)))
+		if style.BasedOn != "" {
+			definitions.WriteString(fmt.Sprintf(`<w:basedOn w:val="%s"/>`, html.EscapeString(style.BasedOn)))
+		}
+		definitions.WriteString(fmt.Sprintf(`<w:link w:val="%s"/><w:qFormat/>`, html.EscapeString(characterId)))
+		if style.ParagraphProperties != "" {
+			definitions.WriteString("<w:pPr>" + style.ParagraphProperties + "</w:pPr>")
+		}
+		if style.RunProperties != "" {
+			definitions.WriteString("<w:rPr>" + style.RunProperties + "</w:rPr>")
+		}
+		definitions.WriteString("</w:style>")
+	}
+	if !exists(characterId) {
+		definitions.WriteString(fmt.Sprintf(`<w:style w:type="character" w:customStyle="1" w:styleId="%s"><w:name w:val="%s"/>`+
+			`<w:basedOn w:val="DefaultParagraphFont"/><w:link w:val="%s"/>`,
+			html.EscapeString(characterId), html.EscapeString(name+" "+linkedCharacterStyleSuffix), html.EscapeString(style.ID)))
+		if style.RunProperties != "" {
+			definitions.WriteString("<w:rPr>" + style.RunProperties + "</w:rPr>")
+		}
+		definitions.WriteString("</w:style>")
+	}
+	if definitions.Len() == 0 {
+		return style.ID, characterId, nil
+	}
+
+	closing := []byte("</w:styles>")
+	pos := bytes.LastIndex(data, closing)
+	if pos < 0 {
+		return "", "", fmt.Errorf("invalid styles part %s", StylesXml)
+	}
+	changed := append([]byte{}, data[:pos]...)
+	changed = append(changed, definitions.String()...)
+	changed = append(changed, data[pos:]...)
+	if err := d.setStyles(changed); err != nil {
+		return "", "", err
+	}
+	return style.ID, characterId, nil
+}
+
+// setStyles sets the style definitions part, the part is added to the document if it does not exist.
+func (d *Document) setStyles(data []byte) error {
+	if !d.hasPart(StylesXml) {
+		if _, err := d.addRelationship(DocumentXml, RelationshipTypeStyles, "styles.xml", false); err != nil {
+			return err
+		}
+		if err := d.ensureContentTypeOverride(StylesXml, ContentTypeStyles); err != nil {
+			return err
+		}
+	}
+	return d.setPart(StylesXml, data)
+}
+
+// SetLanguage sets the default language of the document text (e.g. 'de-DE'), which Word uses for spell checking,
+// hyphenation and which screen readers announce. The language is a BCP 47 tag and is set in the document defaults
+// of the styles, so text with a language of its own keeps it.
+func (d *Document) SetLanguage(tag string) error {
+	if _, err := language.Parse(tag); err != nil {
+		return fmt.Errorf("invalid language %s: %w", tag, err)
+	}
+	data, _, err := d.styles()
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = emptyStyles
+	}
+	styles := string(data)
+	value := `w:val="` + html.EscapeString(tag) + `"`
+
+	setLanguage := func(runProperties string) string {
+		if match := languageRegex.FindStringSubmatch(runProperties); match != nil {
+			element := `<w:lang ` + value + `/>`
+			if match[1] != "" {
+				// keep the east asian and complex script languages
+				element = strings.Replace(match[0], match[1], value, 1)
+			}
+			return strings.Replace(runProperties, match[0], element, 1)
+		}
+		return setRunProperty(runProperties, "lang", `<w:lang `+value+`/>`)
+	}
+
+	var changed string
+	if loc := runPropertiesDefaultRegex.FindStringSubmatchIndex(styles); loc != nil {
+		runProperties := ""
+		if loc[2] >= 0 {
+			runProperties = strings.TrimSpace(styles[loc[2]:loc[3]])
+		}
+		changed = styles[:loc[0]] + "<w:rPrDefault>" + setLanguage(runProperties) + "</w:rPrDefault>" + styles[loc[1]:]
+	} else {
+		defaults := "<w:rPrDefault>" + setLanguage("") + "</w:rPrDefault>"
+		if loc := docDefaultsRegex.FindStringSubmatchIndex(styles); loc != nil {
+			// the default run properties are the first child of the document defaults
+			content := ""
+			if loc[2] >= 0 {
+				content = styles[loc[2]:loc[3]]
+			}
+			changed = styles[:loc[0]] + "<w:docDefaults>" + defaults + content + "</w:docDefaults>" + styles[loc[1]:]
+		} else {
+			// the document defaults are the first child of the styles
+			loc := stylesOpenRegex.FindStringIndex(styles)
+			if loc == nil {
+				return fmt.Errorf("invalid styles part %s", StylesXml)
+			}
+			changed = styles[:loc[1]] + "<w:docDefaults>" + defaults + "</w:docDefaults>" + styles[loc[1]:]
+		}
+	}
+	return d.setStyles([]byte(changed))
+}
+
+// characterStyled is a replacement value which applies a character style to another value.
+type characterStyled struct {
+	value interface{}
+	style string
+}
+
+// WithCharacterStyle returns a replacement value which inserts the value using the given character style (<w:rStyle>)
+// instead of relying on the formatting of the placeholder run. The style is given by its ID or name,
+// paragraph styles are applied using their linked character style. Direct formatting of the placeholder run is kept.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "warning": docx.WithCharacterStyle("Payment overdue", "Intense Emphasis"),
+//	})
+func WithCharacterStyle(value interface{}, style string) interface{} {
+	return characterStyled{value: value, style: style}
+}
+
+// inlineXml renders the value with the character style added to the run properties.
+func (c characterStyled) inlineXml(ctx *valueContext) (string, error) {
+	styleId, err := ctx.doc.characterStyleId(c.style)
+	if err != nil {
+		return "", err
+	}
+	styledCtx := *ctx
+	styledCtx.runProperties = setRunProperty(ctx.runProperties, "rStyle", fmt.Sprintf(`<w:rStyle w:val="%s"/>`, html.EscapeString(styleId)))
+	return styledCtx.valueXml(c.value)
+}
+
+// Style is a style of the style definitions of the document (word/styles.xml), see Styles.
+type Style struct {
+	// ID is the style ID which is referenced by the content, e.g. 'Heading2'.
+	ID string
+	// Name is the name shown by Word, e.g. 'heading 2'. Word shows the names of builtin styles capitalized.
+	Name string
+	// Type is the type of the style: 'paragraph', 'character', 'table' or 'numbering'.
+	Type string
+	// BasedOn is the ID of the style the style inherits from, if any.
+	BasedOn string
+	// Link is the ID of the linked character or paragraph style, if any.
+	Link string
+}
+
+// Styles returns the styles defined in the document in the order of their definition, e.g. to check which
+// styles can be used by Styled values. The list is empty if the document has no style definitions.
+func (d *Document) Styles() ([]Style, error) {
+	_, styles, err := d.styles()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Style, len(styles))
+	for i, style := range styles {
+		list[i] = Style{ID: style.id, Name: style.name, Type: style.styleType, BasedOn: style.basedOn, Link: style.link}
+	}
+	return list, nil
+}
+
+// Styled is a replacement value which inserts the text with a style of the document instead of the formatting of
+// the placeholder run. StyleName is the ID or the name of a paragraph or character style, e.g. 'Heading 2' or
+// 'Quote'. Text with a paragraph style replaces the paragraph of the placeholder, or splits it if the paragraph
+// contains other text, and every line of the text becomes a paragraph of the style. Text with a character style is
+// inserted as a run of the style. An ErrStyleNotFound error is returned for other styles.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "section_title": docx.Styled{Text: "Payment terms", StyleName: "Heading 2"},
+//	    "citation":      docx.Styled{Text: "Time is money.", StyleName: "Quote"},
+//	})
+type Styled struct {
+	Text      string
+	StyleName string
+}
+
+// String returns the text.
+func (s Styled) String() string {
+	return s.Text
+}
+
+// inlineXml returns a run of the character style or a run with the marker of the paragraphs, which are inserted
+// by insertBlocks.
+func (s Styled) inlineXml(ctx *valueContext) (string, error) {
+	style, err := ctx.doc.styledStyle(s.StyleName)
+	if err != nil {
+		return "", err
+	}
+	if style.styleType == "paragraph" {
+		return ctx.blockMarkerRun(s), nil
+	}
+	styledCtx := *ctx
+	styledCtx.runProperties = `<w:rPr><w:rStyle w:val="` + html.EscapeString(style.id) + `"/></w:rPr>`
+	styledCtx.paragraphProperties = ""
+	policy := ctx.doc.textPolicy
+	return policy.lineRuns(&styledCtx, policy.apply(normalizeText(s.Text))), nil
+}
+
+// blockXml returns a paragraph of the paragraph style for every line of the text.
+func (s Styled) blockXml(ctx *valueContext) (string, error) {
+	style, err := ctx.doc.styledStyle(s.StyleName)
+	if err != nil {
+		return "", err
+	}
+	plainCtx := *ctx
+	plainCtx.runProperties, plainCtx.paragraphProperties = "", ""
+	policy := ctx.doc.textPolicy
+	var out strings.Builder
+	for _, line := range strings.Split(policy.apply(normalizeText(s.Text)), "\n") {
+		out.WriteString(`<w:p><w:pPr><w:pStyle w:val="` + html.EscapeString(style.id) + `"/></w:pPr>`)
+		if line != "" {
+			out.WriteString(policy.lineRuns(&plainCtx, line))
+		}
+		out.WriteString("</w:p>")
+	}
+	return out.String(), nil
+}
+
+// styledStyle returns the paragraph or character style with the given ID or name, see Styled.
+func (d *Document) styledStyle(style string) (styleInfo, error) {
+	_, styles, err := d.styles()
+	if err != nil {
+		return styleInfo{}, err
+	}
+	usable := func(s styleInfo) bool {
+		return s.styleType == "paragraph" || s.styleType == "character"
+	}
+	for _, s := range styles {
+		if usable(s) && s.id == style {
+			return s, nil
+		}
+	}
+	for _, s := range styles {
+		if usable(s) && strings.EqualFold(s.name, style) {
+			return s, nil
+		}
+	}
+	return styleInfo{}, fmt.Errorf("%w: %s is not a paragraph or character style", ErrStyleNotFound, style)
+}
@@ -0,0 +1,208 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testStylesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	`<w:style w:type="character" w:styleId="Emphasis"><w:name w:val="Emphasis"/><w:rPr><w:i/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="IntenseEmphasis"><w:name w:val="Intense Emphasis"/><w:rPr><w:b/><w:i/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:link w:val="QuoteChar"/></w:style>` +
+	`<w:style w:type="character" w:customStyle="1" w:styleId="QuoteChar"><w:name w:val="Quote Char"/><w:link w:val="Quote"/></w:style>` +
+	`</w:styles>`
+
+func TestDocument_WithCharacterStyle(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>{a} {b} {c}</w:t></w:r></w:p>`,
+		StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = doc.ReplaceAll(PlaceholderMap{
+		"a": WithCharacterStyle("by id", "Emphasis"),
+		"b": WithCharacterStyle("by name", "intense emphasis"),
+		"c": WithCharacterStyle("linked", "Quote"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:rStyle w:val="Emphasis"/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">by id</w:t></w:r>`,
+		`<w:r><w:rPr><w:rStyle w:val="IntenseEmphasis"/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">by name</w:t></w:r>`,
+		`<w:r><w:rPr><w:rStyle w:val="QuoteChar"/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">linked</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
+
+func TestDocument_WithCharacterStyleNotFound(t *testing.T) {
+	for _, style := range []string{"Normal", "Missing"} {
+		t.Run(style, func(t *testing.T) {
+			doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{a}</w:t></w:r></w:p>`, StylesXml, testStylesXml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = doc.ReplaceAll(PlaceholderMap{"a": WithCharacterStyle("text", style)})
+			if !errors.Is(err, ErrStyleNotFound) {
+				t.Errorf("expected ErrStyleNotFound, have %v", err)
+			}
+		})
+	}
+}
+
+func TestDocument_Styles(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p/>`, StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err := doc.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(styles) != 5 {
+		t.Fatalf("expected 5 styles, have %+v", styles)
+	}
+	expected := Style{ID: "Quote", Name: "Quote", Type: "paragraph", Link: "QuoteChar"}
+	if styles[3] != expected {
+		t.Errorf("expected %+v, have %+v", expected, styles[3])
+	}
+}
+
+func TestDocument_Styled(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>Note: {note}</w:t></w:r></w:p><w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{quote}</w:t></w:r></w:p>`,
+		StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"note":  Styled{Text: "important", StyleName: "emphasis"},
+		"quote": Styled{Text: "Time is money.\nBenjamin Franklin", StyleName: "Quote"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:rStyle w:val="Emphasis"/></w:rPr><w:t xml:space="preserve">important</w:t></w:r>`,
+		`<w:p><w:pPr><w:pStyle w:val="Quote"/></w:pPr><w:r><w:t xml:space="preserve">Time is money.</w:t></w:r></w:p>` +
+			`<w:p><w:pPr><w:pStyle w:val="Quote"/></w:pPr><w:r><w:t xml:space="preserve">Benjamin Franklin</w:t></w:r></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "<w:b/>") {
+		t.Errorf("expected the formatting of the placeholder to be dropped: %s", document)
+	}
+}
+
+func TestDocument_StyledNotFound(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{a}</w:t></w:r></w:p>`, StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"a": Styled{Text: "text", StyleName: "Heading 2"}}); !errors.Is(err, ErrStyleNotFound) {
+		t.Errorf("expected ErrStyleNotFound, have %v", err)
+	}
+}
+
+func TestDocument_AddLinkedStyle(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{total}</w:t></w:r></w:p>`, StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	style := LinkedStyle{ID: "Highlight", BasedOn: "Normal", RunProperties: `<w:b/><w:color w:val="C00000"/>`}
+	paragraphStyle, characterStyle, err := doc.AddLinkedStyle(style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paragraphStyle != "Highlight" || characterStyle != "HighlightChar" {
+		t.Errorf("unexpected style IDs %s and %s", paragraphStyle, characterStyle)
+	}
+	// adding the style again keeps the existing definitions
+	if _, _, err := doc.AddLinkedStyle(style); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"total": WithCharacterStyle("42 EUR", "Highlight")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	styles := readTestPart(t, buf.Bytes(), StylesXml)
+	expected := `<w:style w:type="paragraph" w:customStyle="1" w:styleId="Highlight"><w:name w:val="Highlight"/><w:basedOn w:val="Normal"/><w:link w:val="HighlightChar"/><w:qFormat/><w:rPr><w:b/><w:color w:val="C00000"/></w:rPr></w:style>` +
+		`<w:style w:type="character" w:customStyle="1" w:styleId="HighlightChar"><w:name w:val="Highlight Char"/><w:basedOn w:val="DefaultParagraphFont"/><w:link w:val="Highlight"/><w:rPr><w:b/><w:color w:val="C00000"/></w:rPr></w:style></w:styles>`
+	if !strings.HasSuffix(styles, expected) {
+		t.Errorf("expected linked styles: %s", styles)
+	}
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); !strings.Contains(document, `<w:rStyle w:val="HighlightChar"/>`) {
+		t.Errorf("expected the linked character style to be applied: %s", document)
+	}
+}
+
+func TestDocument_SetLanguage(t *testing.T) {
+	tests := []struct {
+		styles, expected string
+	}{
+		{"", `<w:docDefaults><w:rPrDefault><w:rPr><w:lang w:val="de-DE"/></w:rPr></w:rPrDefault></w:docDefaults>`},
+		{testStylesXml, `main"><w:docDefaults><w:rPrDefault><w:rPr><w:lang w:val="de-DE"/></w:rPr></w:rPrDefault></w:docDefaults><w:style `},
+		{
+			`<w:styles><w:docDefaults><w:pPrDefault/></w:docDefaults></w:styles>`,
+			`<w:docDefaults><w:rPrDefault><w:rPr><w:lang w:val="de-DE"/></w:rPr></w:rPrDefault><w:pPrDefault/></w:docDefaults>`,
+		},
+		{
+			`<w:styles><w:docDefaults><w:rPrDefault><w:rPr><w:sz w:val="22"/><w:lang w:val="en-US" w:eastAsia="ja-JP"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`,
+			`<w:rPr><w:sz w:val="22"/><w:lang w:val="de-DE" w:eastAsia="ja-JP"/></w:rPr>`,
+		},
+	}
+	for _, tt := range tests {
+		var files []string
+		if tt.styles != "" {
+			files = []string{StylesXml, tt.styles}
+		}
+		doc, err := OpenBytes(buildTestDocx(t, `<w:p/>`, files...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.SetLanguage("de-DE"); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if styles := readTestPart(t, buf.Bytes(), StylesXml); !strings.Contains(styles, tt.expected) {
+			t.Errorf("expected %s in styles: %s", tt.expected, styles)
+		}
+	}
+
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetLanguage("not a language"); err == nil {
+		t.Error("expected error for invalid language")
+	}
+}
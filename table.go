@@ -0,0 +1,108 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const (
+	// TableElementName is the local name of the XML tag for tables (<w:tbl>)
+	TableElementName = "tbl"
+	// TableRowElementName is the local name of the XML tag for table rows (<w:tr>)
+	TableRowElementName = "tr"
+	// TableCellElementName is the local name of the XML tag for table cells (<w:tc>)
+	TableCellElementName = "tc"
+)
+
+// tableElement is a table inside an XML part, described by the byte offsets of the complete element.
+type tableElement struct {
+	Position
+	Rows []*tableRowElement
+}
+
+// tableRowElement is a row of a tableElement.
+type tableRowElement struct {
+	Position
+	Cells []Position
+}
+
+// parseTables locates all tables inside the given XML part in document order.
+// Nested tables are returned as well, their rows are not part of the rows of the outer table.
+func parseTables(data []byte) ([]*tableElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var tables []*tableElement
+	var tableStack []*tableElement
+	var rowStack []*tableRowElement
+	var cellStart []int64
+
+	for {
+		start := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting token: %w", err)
+		}
+		end := decoder.InputOffset()
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case TableElementName:
+				table := &tableElement{Position: Position{Start: start}}
+				tables = append(tables, table)
+				tableStack = append(tableStack, table)
+			case TableRowElementName:
+				if len(tableStack) == 0 {
+					continue
+				}
+				row := &tableRowElement{Position: Position{Start: start}}
+				table := tableStack[len(tableStack)-1]
+				table.Rows = append(table.Rows, row)
+				rowStack = append(rowStack, row)
+			case TableCellElementName:
+				cellStart = append(cellStart, start)
+			}
+
+		case xml.EndElement:
+			switch elem.Name.Local {
+			case TableElementName:
+				if len(tableStack) == 0 {
+					continue
+				}
+				tableStack[len(tableStack)-1].End = end
+				tableStack = tableStack[:len(tableStack)-1]
+			case TableRowElementName:
+				if len(rowStack) == 0 {
+					continue
+				}
+				rowStack[len(rowStack)-1].End = end
+				rowStack = rowStack[:len(rowStack)-1]
+			case TableCellElementName:
+				if len(cellStart) == 0 || len(rowStack) == 0 {
+					continue
+				}
+				row := rowStack[len(rowStack)-1]
+				row.Cells = append(row.Cells, Position{Start: cellStart[len(cellStart)-1], End: end})
+				cellStart = cellStart[:len(cellStart)-1]
+			}
+		}
+	}
+
+	return tables, nil
+}
+
+// elementText returns the plain text of all text-runs inside the given XML fragment, XML unescaped.
+func elementText(data []byte) string {
+	var text strings.Builder
+	for _, match := range TextNodeRegex.FindAllSubmatch(data, -1) {
+		text.Write(match[2])
+	}
+	return html.UnescapeString(text.String())
+}
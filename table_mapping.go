@@ -0,0 +1,307 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TableMapping describes how a slice is bound to a template table.
+// A template table contains a row in which all placeholders address fields of the same collection,
+// e.g. a row with the cells '{items.description}', '{items.qty}' and '{items.price}'.
+// That row is repeated for every element of the collection. The elements are expected to be of the type
+// returned by RowType, or any map or struct which provides the same fields, see BindTable.
+type TableMapping struct {
+	Part       string          // Part is the name of the file inside the archive which contains the table.
+	Table      int             // Table is the index of the table inside the part, in document order.
+	Row        int             // Row is the index of the template row inside the table.
+	Collection string          // Collection is the name of the bound collection, e.g. 'items' for '{items.qty}'.
+	Columns    []ColumnMapping // Columns maps the table columns to the fields of the row elements.
+}
+
+// ColumnMapping maps a single column of a template table to a field of the row elements.
+type ColumnMapping struct {
+	Column int    // Column is the index of the cell inside the template row.
+	Header string // Header is the text of the cell above the template row, empty if there is none.
+	Field  string // Field is the name of the field of the row element, e.g. 'qty' for '{items.qty}'.
+}
+
+// Fields returns the names of all fields which the row elements are expected to provide, in column order.
+func (m TableMapping) Fields() []string {
+	var fields []string
+	for _, column := range m.Columns {
+		fields = append(fields, column.Field)
+	}
+	return fields
+}
+
+// RowType returns the type of the row elements expected by the template row: a struct with one string field per
+// field of the row, in column order. The struct fields are named after the fields of the row, e.g. 'UnitPrice' for
+// '{items.unit_price}', and carry the field in their 'docx' tag, so slices of the type can be passed to BindTable.
+// Placeholders are replaced by text, which is why all fields are strings.
+//
+// Example:
+//
+//	rows := reflect.MakeSlice(reflect.SliceOf(mapping.RowType()), 0, len(records))
+//	// fill the rows, e.g. from a generic data source, then
+//	err := doc.BindTable(mapping.Collection, rows.Interface())
+func (m TableMapping) RowType() reflect.Type {
+	var fields []reflect.StructField
+	seenFields := make(map[string]bool)
+	seenNames := make(map[string]bool)
+	for _, field := range m.Fields() {
+		if seenFields[field] {
+			continue
+		}
+		seenFields[field] = true
+		name := rowFieldName(field)
+		for i := 2; seenNames[name]; i++ {
+			name = fmt.Sprintf("%s%d", rowFieldName(field), i)
+		}
+		seenNames[name] = true
+		fields = append(fields, reflect.StructField{
+			Name: name,
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf("docx:%q", field)),
+		})
+	}
+	return reflect.StructOf(fields)
+}
+
+// rowFieldName returns the exported Go name of the field of a row element, e.g. 'UnitPrice' for 'unit_price'.
+func rowFieldName(field string) string {
+	var name strings.Builder
+	upper := true
+	for _, r := range field {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+	if name.Len() == 0 || !unicode.IsUpper([]rune(name.String())[0]) {
+		return "F" + name.String()
+	}
+	return name.String()
+}
+
+// collectionPlaceholderRegex returns a regex matching placeholders of the form '{collection.field}'
+// using the currently configured delimiters.
+func collectionPlaceholderRegex() *regexp.Regexp {
+	open := regexp.QuoteMeta(string(OpenDelimiter))
+	closing := regexp.QuoteMeta(string(CloseDelimiter))
+	return regexp.MustCompile(fmt.Sprintf(`%s([^%s%s.]+)\.([^%s%s]+)%s`, open, open, closing, open, closing, closing))
+}
+
+// TableMappings extracts the mappings of all template tables inside the document body, headers and footers.
+// Tables without a template row are skipped.
+func (d *Document) TableMappings() ([]TableMapping, error) {
+	var mappings []TableMapping
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		tables, err := parseTables(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse tables in %s: %w", name, err)
+		}
+		for tableIndex, table := range tables {
+			if mapping, found := extractTableMapping(data, table); found {
+				mapping.Part = name
+				mapping.Table = tableIndex
+				mappings = append(mappings, mapping)
+			}
+		}
+	}
+	return mappings, nil
+}
+
+// extractTableMapping searches the template row of the given table and returns its mapping.
+func extractTableMapping(data []byte, table *tableElement) (TableMapping, bool) {
+	placeholderRegex := collectionPlaceholderRegex()
+	anyPlaceholderRegex := regexp.MustCompile(fmt.Sprintf("%s[^%s]*%s",
+		regexp.QuoteMeta(string(OpenDelimiter)), regexp.QuoteMeta(string(CloseDelimiter)), regexp.QuoteMeta(string(CloseDelimiter))))
+
+	for rowIndex, row := range table.Rows {
+		mapping := TableMapping{Row: rowIndex}
+		valid := true
+
+		for cellIndex, cell := range row.Cells {
+			text := elementText(data[cell.Start:cell.End])
+			placeholders := anyPlaceholderRegex.FindAllString(text, -1)
+			for _, placeholder := range placeholders {
+				match := placeholderRegex.FindStringSubmatch(placeholder)
+				if match == nil || (mapping.Collection != "" && mapping.Collection != match[1]) {
+					valid = false
+					break
+				}
+				mapping.Collection = match[1]
+				mapping.Columns = append(mapping.Columns, ColumnMapping{
+					Column: cellIndex,
+					Header: tableHeaderText(data, table, rowIndex, cellIndex),
+					Field:  match[2],
+				})
+			}
+		}
+
+		if valid && mapping.Collection != "" {
+			return mapping, true
+		}
+	}
+	return TableMapping{}, false
+}
+
+// tableHeaderText returns the text of the cell directly above the given cell, if any.
+func tableHeaderText(data []byte, table *tableElement, row, cell int) string {
+	if row == 0 || cell >= len(table.Rows[row-1].Cells) {
+		return ""
+	}
+	header := table.Rows[row-1].Cells[cell]
+	return strings.TrimSpace(elementText(data[header.Start:header.End]))
+}
+
+// BindTable binds the given slice to all template tables of the collection.
+// The template row is repeated for every element of rows, its placeholders are replaced by the fields of the element.
+// The elements may be maps with string keys or structs. Struct fields are matched using the 'docx' struct tag
+// or, if no tag is set, by their case-insensitive name.
+//
+// Example:
+//
+//	// template row: | {items.description} | {items.qty} |
+//	doc.BindTable("items", []struct {
+//	    Description string
+//	    Qty         int
+//	}{{"Apples", 3}, {"Pears", 5}})
+func (d *Document) BindTable(collection string, rows interface{}) error {
+	rowValues := reflect.ValueOf(rows)
+	if rowValues.Kind() != reflect.Slice && rowValues.Kind() != reflect.Array {
+		return fmt.Errorf("rows must be a slice, got %T", rows)
+	}
+
+	mappings, err := d.TableMappings()
+	if err != nil {
+		return err
+	}
+
+	bound := false
+	// tables are bound in reverse order so that the offsets of the remaining tables stay valid
+	for i := len(mappings) - 1; i >= 0; i-- {
+		mapping := mappings[i]
+		if mapping.Collection != collection {
+			continue
+		}
+		if err := d.bindTable(mapping, rowValues); err != nil {
+			return err
+		}
+		bound = true
+	}
+
+	if !bound {
+		return fmt.Errorf("no template table found for collection %q", collection)
+	}
+	return nil
+}
+
+// bindTable repeats the template row of the mapping for every element of rows and re-parses the part.
+func (d *Document) bindTable(mapping TableMapping, rows reflect.Value) error {
+	data := d.files[mapping.Part]
+	tables, err := parseTables(data)
+	if err != nil {
+		return err
+	}
+	row := tables[mapping.Table].Rows[mapping.Row]
+	rowXml := data[row.Start:row.End]
+
+	var rendered []byte
+	for i := 0; i < rows.Len(); i++ {
+		values := make(PlaceholderMap)
+		for _, field := range mapping.Fields() {
+			value, found := lookupField(rows.Index(i), field)
+			if !found {
+				return fmt.Errorf("row %d of collection %q has no field %q", i, mapping.Collection, field)
+			}
+			values[mapping.Collection+"."+field] = value
+		}
+
+		renderedRow, err := replaceFragment(rowXml, values)
+		if err != nil {
+			return fmt.Errorf("unable to render row %d of collection %q: %w", i, mapping.Collection, err)
+		}
+		rendered = append(rendered, renderedRow...)
+	}
+
+	changed := append([]byte{}, data[:row.Start]...)
+	changed = append(changed, rendered...)
+	changed = append(changed, data[row.End:]...)
+	if err := d.SetFile(mapping.Part, changed); err != nil {
+		return err
+	}
+	return d.parseFile(mapping.Part)
+}
+
+// replaceFragment replaces all placeholders of the PlaceholderMap inside a well-formed XML fragment.
+func replaceFragment(fragment []byte, values PlaceholderMap) ([]byte, error) {
+	data := append([]byte{}, fragment...)
+	parser := NewRunParser(data)
+	if err := parser.Execute(); err != nil {
+		return nil, err
+	}
+	placeholders, err := ParsePlaceholders(parser.Runs(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	replacer := NewReplacer(data, placeholders)
+	for key, value := range values {
+		if err := replacer.Replace(key, fmt.Sprint(value)); err != nil && !errors.Is(err, ErrPlaceholderNotFound) {
+			return nil, err
+		}
+	}
+	return replacer.Bytes(), nil
+}
+
+// lookupField returns the value of the field with the given name of a map or struct value.
+func lookupField(value reflect.Value, field string) (interface{}, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		fieldValue := value.MapIndex(reflect.ValueOf(field).Convert(value.Type().Key()))
+		if !fieldValue.IsValid() {
+			return nil, false
+		}
+		return fieldValue.Interface(), true
+
+	case reflect.Struct:
+		structType := value.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			structField := structType.Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+			if tag, ok := structField.Tag.Lookup("docx"); ok {
+				if tag == field {
+					return value.Field(i).Interface(), true
+				}
+				continue
+			}
+			if strings.EqualFold(structField.Name, field) {
+				return value.Field(i).Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
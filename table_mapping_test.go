@@ -0,0 +1,127 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testItemsTable = `<w:tbl><w:tblGrid><w:gridCol/><w:gridCol/></w:tblGrid>` +
+	`<w:tr><w:tc><w:p><w:r><w:t>Description</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Qty</w:t></w:r></w:p></w:tc></w:tr>` +
+	`<w:tr><w:tc><w:p><w:r><w:t>{items.</w:t></w:r><w:r><w:t>description}</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>{items.qty}</w:t></w:r></w:p></w:tc></w:tr>` +
+	`</w:tbl>`
+
+func TestDocument_TableMappings(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{title}</w:t></w:r></w:p>`+testItemsTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mappings, err := doc.TableMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TableMapping{{
+		Part:       DocumentXml,
+		Table:      0,
+		Row:        1,
+		Collection: "items",
+		Columns: []ColumnMapping{
+			{Column: 0, Header: "Description", Field: "description"},
+			{Column: 1, Header: "Qty", Field: "qty"},
+		},
+	}}
+	if !reflect.DeepEqual(mappings, expected) {
+		t.Errorf("unexpected mappings, want=%+v, have=%+v", expected, mappings)
+	}
+}
+
+func TestDocument_BindTable(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{title}</w:t></w:r></w:p>`+testItemsTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []struct {
+		Name string `docx:"description"`
+		Qty  int
+	}{{"Apples & Pears", 3}, {"Plums", 5}}
+	if err := doc.BindTable("items", items); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"title": "Invoice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	if count := strings.Count(document, "<w:tr>"); count != 3 {
+		t.Errorf("expected 3 rows, have %d", count)
+	}
+	for _, expected := range []string{"Invoice", "Apples &amp; Pears", ">3<", "Plums", ">5<"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document", expected)
+		}
+	}
+
+	if err := doc.BindTable("missing", items); err == nil {
+		t.Error("expected error for unknown collection")
+	}
+}
+
+func TestTableMapping_RowType(t *testing.T) {
+	mapping := TableMapping{Collection: "items", Columns: []ColumnMapping{
+		{Column: 0, Field: "description"}, {Column: 1, Field: "unit_price"}, {Column: 2, Field: "unit-price"},
+		{Column: 3, Field: "2nd"}, {Column: 4, Field: "description"},
+	}}
+	rowType := mapping.RowType()
+	expected := []string{`Description docx:"description"`, `UnitPrice docx:"unit_price"`, `UnitPrice2 docx:"unit-price"`, `F2nd docx:"2nd"`}
+	if rowType.NumField() != len(expected) {
+		t.Fatalf("expected %d fields, have %s", len(expected), rowType)
+	}
+	for i, want := range expected {
+		field := rowType.Field(i)
+		if have := field.Name + " " + string(field.Tag); have != want || field.Type.Kind() != reflect.String {
+			t.Errorf("expected field %s, have %s %s", want, have, field.Type)
+		}
+	}
+}
+
+func TestDocument_BindTable_RowType(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, testItemsTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mappings, err := doc.TableMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a generic binding which does not know the columns of the table
+	records := []map[string]string{{"description": "Apples", "qty": "3"}, {"description": "Plums", "qty": "5"}}
+	rowType := mappings[0].RowType()
+	rows := reflect.MakeSlice(reflect.SliceOf(rowType), len(records), len(records))
+	for i, record := range records {
+		for j := 0; j < rowType.NumField(); j++ {
+			rows.Index(i).Field(j).SetString(record[rowType.Field(j).Tag.Get("docx")])
+		}
+	}
+	if err := doc.BindTable(mappings[0].Collection, rows.Interface()); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	for _, expected := range []string{">Apples<", ">3<", ">Plums<", ">5<"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document", expected)
+		}
+	}
+}
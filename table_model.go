@@ -0,0 +1,193 @@
+package docx
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// gridBeforeRegex matches the number of grid columns skipped in front of the first cell of a row and captures it.
+	gridBeforeRegex = regexp.MustCompile(`<w:gridBefore\s+w:val="([0-9]+)"`)
+)
+
+// TableModel is the read-only content of a table, e.g. of a completed form returned by a customer, see Tables.
+type TableModel struct {
+	Part  string             // Part is the name of the file inside the archive which contains the table.
+	Table int                // Table is the index of the table inside the part in document order, like TableMapping.Table.
+	Rows  [][]TableCellModel // Rows holds the cells of each row.
+}
+
+// TableCellModel is a cell of a TableModel.
+type TableCellModel struct {
+	// Text is the text of the cell, paragraphs are separated by newlines. The text of nested tables is not included.
+	Text string
+	// Column is the index of the first grid column of the cell. It differs from the index inside the row if cells
+	// of the row span multiple columns or the row starts with skipped columns.
+	Column int
+	// ColSpan is the number of grid columns spanned by the cell, at least 1.
+	ColSpan int
+	// RowSpan is the number of rows spanned by a vertically merged cell, 1 for regular cells and 0 for the cells
+	// which continue a merged cell of a row above.
+	RowSpan int
+	// Tables are the tables nested inside the cell.
+	Tables []TableModel
+}
+
+// Merged returns true if the cell continues a vertically merged cell of a row above, its text belongs to that cell.
+func (c TableCellModel) Merged() bool {
+	return c.RowSpan == 0
+}
+
+// Tables returns the content of all tables inside the document body, headers, footers and notes in document order.
+// Nested tables are part of the cells which contain them. Deleted text of tracked changes is not included.
+//
+// Example:
+//
+//	for _, table := range doc.Tables() {
+//	    for _, row := range table.Rows[1:] {
+//	        fmt.Println(row[0].Text, row[1].Text)
+//	    }
+//	}
+func (d *Document) Tables() []TableModel {
+	var models []TableModel
+	for _, name := range d.xmlFiles() {
+		data := d.files[name]
+		tables, err := parseTables(data)
+		if err != nil {
+			continue
+		}
+		// parseTables returns nested tables as well, they are indexed by their start offset
+		indexes := make(map[int64]int, len(tables))
+		for i, table := range tables {
+			indexes[table.Start] = i
+		}
+		end := int64(0)
+		for i, table := range tables {
+			if table.Start < end {
+				continue // nested
+			}
+			end = table.End
+			models = append(models, tableModel(string(data), name, tables, i, indexes))
+		}
+	}
+	return models
+}
+
+// tableModel returns the model of the table with the given index, indexes maps the start offsets of the tables to
+// their index.
+func tableModel(data, part string, tables []*tableElement, index int, indexes map[int64]int) TableModel {
+	model := TableModel{Part: part, Table: index}
+	table := tables[index]
+	open, inner, _ := splitElement(data[table.Start:table.End])
+	offset := table.Start + int64(len(open))
+
+	var rows []string
+	var rowOffsets []int64
+	collectElements(data, offset, offset+int64(len(inner)), TableRowElementName, &rows, &rowOffsets)
+	// spans tracks the cell which is continued by vertical merges for each grid column
+	spans := make(map[int]*TableCellModel)
+	for r, row := range rows {
+		var cells []string
+		var cellOffsets []int64
+		collectElements(data, rowOffsets[r], rowOffsets[r]+int64(len(row)), TableCellElementName, &cells, &cellOffsets)
+
+		column := 0
+		if match := gridBeforeRegex.FindStringSubmatch(childProperties(row, "trPr")); match != nil {
+			column, _ = strconv.Atoi(match[1])
+		}
+		models := make([]TableCellModel, len(cells))
+		for c, cell := range cells {
+			cellModel := TableCellModel{Column: column, ColSpan: 1, RowSpan: 1}
+			properties := childProperties(cell, "tcPr")
+			if match := gridSpanRegex.FindStringSubmatch(properties); match != nil {
+				if span, err := strconv.Atoi(match[1]); err == nil && span > 1 {
+					cellModel.ColSpan = span
+				}
+			}
+			if match := cellMergeRegex.FindStringSubmatch(properties); match != nil && match[1] != "restart" {
+				cellModel.RowSpan = 0
+			}
+			cellModel.Text, cellModel.Tables = cellContent(data, cellOffsets[c], cell, part, tables, indexes)
+			models[c] = cellModel
+			column += cellModel.ColSpan
+		}
+		model.Rows = append(model.Rows, models)
+		// the rows spanned by a vertically merged cell are counted at its first cell
+		for c := range models {
+			cell := &model.Rows[r][c]
+			if cell.Merged() {
+				if first, exists := spans[cell.Column]; exists {
+					first.RowSpan++
+					continue
+				}
+				cell.RowSpan = 1 // a continuation without a merged cell above
+			}
+			spans[cell.Column] = cell
+		}
+	}
+	return model
+}
+
+// collectElements collects the elements of the given local name inside data[start:end] and their offsets.
+// Elements nested inside other elements, e.g. rows inside content controls, are collected as well, except for the
+// content of nested tables.
+func collectElements(data string, start, end int64, name string, elements *[]string, offsets *[]int64) {
+	content := data[start:end]
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch {
+		case child.name == name:
+			*elements = append(*elements, element)
+			*offsets = append(*offsets, start+int64(child.start))
+		case child.name == TableElementName || strings.HasSuffix(child.name, "Pr"):
+		default:
+			open, inner, _ := splitElement(element)
+			if inner != "" {
+				innerStart := start + int64(child.start+len(open))
+				collectElements(data, innerStart, innerStart+int64(len(inner)), name, elements, offsets)
+			}
+		}
+	}
+}
+
+// cellContent returns the text of the paragraphs and the nested tables of the cell starting at the given offset.
+func cellContent(data string, start int64, cell, part string, tables []*tableElement, indexes map[int64]int) (string, []TableModel) {
+	var paragraphs []string
+	var nested []TableModel
+	var walk func(start int64, content string)
+	walk = func(start int64, content string) {
+		for _, child := range childElements(content) {
+			element := content[child.start:child.end]
+			switch child.name {
+			case "p":
+				paragraphs = append(paragraphs, elementText([]byte(element)))
+			case TableElementName:
+				if index, exists := indexes[start+int64(child.start)]; exists {
+					nested = append(nested, tableModel(data, part, tables, index, indexes))
+				}
+			case "tcPr":
+			default:
+				open, inner, _ := splitElement(element)
+				if inner != "" {
+					walk(start+int64(child.start+len(open)), inner)
+				}
+			}
+		}
+	}
+	open, inner, _ := splitElement(cell)
+	walk(start+int64(len(open)), inner)
+	return strings.Join(paragraphs, "\n"), nested
+}
+
+// childProperties returns the properties of the element, the child with the given local name (e.g. <w:tcPr> of a
+// table cell), empty if there are none.
+func childProperties(element, name string) string {
+	_, inner, _ := splitElement(element)
+	for _, child := range childElements(inner) {
+		if child.name == name {
+			return inner[child.start:child.end]
+		}
+	}
+	return ""
+}
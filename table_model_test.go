@@ -0,0 +1,54 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_Tables(t *testing.T) {
+	cell := func(properties, text string) string {
+		return `<w:tc>` + properties + `<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:tc>`
+	}
+	nested := `<w:tbl><w:tr>` + cell("", "inner") + `</w:tr></w:tbl>`
+	body := `<w:p><w:r><w:t>Form</w:t></w:r></w:p>` +
+		`<w:tbl><w:tblPr/><w:tblGrid><w:gridCol/><w:gridCol/><w:gridCol/></w:tblGrid>` +
+		`<w:tr><w:trPr/>` + cell(`<w:tcPr><w:gridSpan w:val="2"/></w:tcPr>`, "Name") + cell("", "Age") + `</w:tr>` +
+		`<w:tr>` + cell(`<w:tcPr><w:vMerge w:val="restart"/></w:tcPr>`, "Jane") +
+		`<w:tc><w:p><w:r><w:t>Doe</w:t></w:r></w:p><w:p><w:r><w:t>née </w:t></w:r><w:del><w:r><w:delText>x</w:delText></w:r></w:del><w:r><w:t>Roe</w:t></w:r></w:p></w:tc>` +
+		cell("", "42") + `</w:tr>` +
+		`<w:tr><w:trPr><w:gridBefore w:val="0"/></w:trPr>` + cell(`<w:tcPr><w:vMerge/></w:tcPr>`, "") +
+		`<w:tc><w:p/>` + nested + `<w:p/></w:tc>` + `<w:sdt><w:sdtContent>` + cell("", "43") + `</w:sdtContent></w:sdt></w:tr>` +
+		`</w:tbl><w:p/>`
+
+	doc, err := OpenBytes(buildTestDocx(t, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TableModel{{
+		Part:  DocumentXml,
+		Table: 0,
+		Rows: [][]TableCellModel{
+			{{Text: "Name", Column: 0, ColSpan: 2, RowSpan: 1}, {Text: "Age", Column: 2, ColSpan: 1, RowSpan: 1}},
+			{
+				{Text: "Jane", Column: 0, ColSpan: 1, RowSpan: 2},
+				{Text: "Doe\nnée Roe", Column: 1, ColSpan: 1, RowSpan: 1},
+				{Text: "42", Column: 2, ColSpan: 1, RowSpan: 1},
+			},
+			{
+				{Text: "", Column: 0, ColSpan: 1, RowSpan: 0},
+				{Text: "\n", Column: 1, ColSpan: 1, RowSpan: 1, Tables: []TableModel{{
+					Part:  DocumentXml,
+					Table: 1,
+					Rows:  [][]TableCellModel{{{Text: "inner", ColSpan: 1, RowSpan: 1}}},
+				}}},
+				{Text: "43", Column: 2, ColSpan: 1, RowSpan: 1},
+			},
+		},
+	}}
+	if tables := doc.Tables(); !reflect.DeepEqual(tables, expected) {
+		t.Errorf("unexpected tables:\n%+v\nexpected:\n%+v", tables, expected)
+	}
+	if !doc.Tables()[0].Rows[2][0].Merged() {
+		t.Error("expected the continued cell to be merged")
+	}
+}
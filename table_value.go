@@ -0,0 +1,155 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	// tableTextWidth is the width (twips) of the grid of inserted tables, the text width of a letter page with
+	// margins of one inch. The tables themselves span the full text width of their section.
+	tableTextWidth = 9360
+	// tableBorders are the borders of inserted tables without table style.
+	tableBorders = `<w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+		`<w:left w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+		`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+		`<w:right w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+		`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+		`<w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/></w:tblBorders>`
+)
+
+// Table is a replacement value which inserts a table built from structured data, e.g. the results of a query.
+// The header row is repeated on every page the table spans, rows with fewer cells than the widest row are
+// filled with empty cells. Cell text uses the formatting of the placeholder run and the text policy of the document.
+//
+// StyleName is the ID or the name of a table style of the document, e.g. 'GridTable4-Accent1' or
+// 'Grid Table 4 Accent 1'. If it is empty, the 'TableGrid' style is used if the document defines it, otherwise
+// the table gets single borders and a bold header row.
+// Like fragments, the table replaces the paragraph of the placeholder, or splits it if the paragraph has more text.
+//
+// Example:
+//
+//	doc.ReplaceAll(docx.PlaceholderMap{
+//	    "results_table": docx.Table{
+//	        Headers: []string{"Name", "Score"},
+//	        Rows:    [][]string{{"Jane", "98"}, {"John", "87"}},
+//	    },
+//	})
+type Table struct {
+	Headers   []string
+	Rows      [][]string
+	StyleName string
+}
+
+// String returns the cells of the table separated by tabs, one row per line.
+func (t Table) String() string {
+	var lines []string
+	if len(t.Headers) > 0 {
+		lines = append(lines, strings.Join(t.Headers, "\t"))
+	}
+	for _, row := range t.Rows {
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inlineXml returns a run with the marker of the table, the table is inserted by insertBlocks.
+func (t Table) inlineXml(ctx *valueContext) (string, error) {
+	return ctx.blockMarkerRun(t), nil
+}
+
+// blockXml returns the table.
+func (t Table) blockXml(ctx *valueContext) (string, error) {
+	columns := len(t.Headers)
+	for _, row := range t.Rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return "", nil
+	}
+
+	style := ""
+	switch {
+	case t.StyleName != "":
+		id, err := ctx.doc.tableStyleId(t.StyleName)
+		if err != nil {
+			return "", err
+		}
+		style = id
+	case ctx.doc.styleExists("TableGrid"):
+		style = "TableGrid"
+	}
+
+	var out strings.Builder
+	out.WriteString("<w:tbl><w:tblPr>")
+	if style != "" {
+		out.WriteString(`<w:tblStyle w:val="` + html.EscapeString(style) + `"/>`)
+	}
+	out.WriteString(`<w:tblW w:w="5000" w:type="pct"/>`)
+	if style == "" {
+		out.WriteString(tableBorders)
+	}
+	out.WriteString(`<w:tblLook w:val="04A0" w:firstRow="1" w:lastRow="0" w:firstColumn="1" w:lastColumn="0" w:noHBand="0" w:noVBand="1"/>`)
+	out.WriteString("</w:tblPr><w:tblGrid>")
+	width := tableTextWidth / columns
+	for range columns {
+		fmt.Fprintf(&out, `<w:gridCol w:w="%d"/>`, width)
+	}
+	out.WriteString("</w:tblGrid>")
+
+	cellCtx := *ctx
+	cellCtx.paragraphProperties = ""
+	writeRow := func(rowProperties string, cells []string, cellCtx *valueContext) error {
+		out.WriteString("<w:tr>" + rowProperties)
+		for i := range columns {
+			text := ""
+			if i < len(cells) {
+				text = cells[i]
+			}
+			runs, err := cellCtx.valueXml(text)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&out, `<w:tc><w:tcPr><w:tcW w:w="%d" w:type="dxa"/></w:tcPr><w:p>%s</w:p></w:tc>`, width, runs)
+		}
+		out.WriteString("</w:tr>")
+		return nil
+	}
+
+	if len(t.Headers) > 0 {
+		headerCtx := cellCtx
+		if style == "" {
+			headerCtx.runProperties = setRunProperty(headerCtx.runProperties, "b", "<w:b/>")
+		}
+		if err := writeRow("<w:trPr><w:tblHeader/></w:trPr>", t.Headers, &headerCtx); err != nil {
+			return "", err
+		}
+	}
+	for _, row := range t.Rows {
+		if err := writeRow("", row, &cellCtx); err != nil {
+			return "", err
+		}
+	}
+	out.WriteString("</w:tbl>")
+	return out.String(), nil
+}
+
+// tableStyleId returns the ID of the table style with the given ID or name.
+func (d *Document) tableStyleId(style string) (string, error) {
+	_, styles, err := d.styles()
+	if err != nil {
+		return "", err
+	}
+	for _, s := range styles {
+		if s.styleType == "table" && s.id == style {
+			return s.id, nil
+		}
+	}
+	for _, s := range styles {
+		if s.styleType == "table" && strings.EqualFold(s.name, style) {
+			return s.id, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not a table style", ErrStyleNotFound, style)
+}
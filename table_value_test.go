@@ -0,0 +1,67 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceTable(t *testing.T) {
+	styles := strings.Replace(testStylesXml, `</w:styles>`,
+		`<w:style w:type="table" w:styleId="GridTable4-Accent1"><w:name w:val="Grid Table 4 Accent 1"/></w:style></w:styles>`, 1)
+	input := buildTestDocx(t, `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:i/></w:rPr><w:t>{results_table}</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>Styled: {styled}</w:t></w:r></w:p>`, StylesXml, styles)
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = doc.ReplaceAll(PlaceholderMap{
+		"results_table": Table{
+			Headers: []string{"Name", "Score"},
+			Rows:    [][]string{{"Jane & Co", "98"}, {"John"}},
+		},
+		"styled": Table{Rows: [][]string{{"a", "b", "c"}}, StyleName: "grid table 4 accent 1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+	cell := func(runProperties, text string) string {
+		return `<w:tc><w:tcPr><w:tcW w:w="4680" w:type="dxa"/></w:tcPr><w:p><w:r>` + runProperties +
+			`<w:t xml:space="preserve">` + text + `</w:t></w:r></w:p></w:tc>`
+	}
+	for _, expected := range []string{
+		// the paragraph of the placeholder is replaced
+		`<w:body><w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/>` + tableBorders,
+		`<w:tblGrid><w:gridCol w:w="4680"/><w:gridCol w:w="4680"/></w:tblGrid>`,
+		`<w:tr><w:trPr><w:tblHeader/></w:trPr>` + cell(`<w:rPr><w:b/><w:i/></w:rPr>`, "Name") + cell(`<w:rPr><w:b/><w:i/></w:rPr>`, "Score") + `</w:tr>`,
+		`<w:tr>` + cell(`<w:rPr><w:i/></w:rPr>`, "Jane &amp; Co") + cell(`<w:rPr><w:i/></w:rPr>`, "98") + `</w:tr>`,
+		`<w:tr>` + cell(`<w:rPr><w:i/></w:rPr>`, "John") + cell(`<w:rPr><w:i/></w:rPr>`, "") + `</w:tr></w:tbl>`,
+		// a paragraph follows tables so the text behind them can be edited
+		`</w:tbl><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r>`,
+		`Styled: </w:t></w:r></w:p><w:tbl><w:tblPr><w:tblStyle w:val="GridTable4-Accent1"/><w:tblW w:w="5000" w:type="pct"/><w:tblLook`,
+		`<w:gridCol w:w="3120"/><w:gridCol w:w="3120"/><w:gridCol w:w="3120"/></w:tblGrid><w:tr><w:tc>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
+
+func TestDocument_ReplaceTable_UnknownStyle(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{table}</w:t></w:r></w:p>`, StylesXml, testStylesXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// paragraph styles are no table styles
+	err = doc.ReplaceAll(PlaceholderMap{"table": Table{Rows: [][]string{{"a"}}, StyleName: "Quote"}})
+	if !errors.Is(err, ErrStyleNotFound) {
+		t.Errorf("expected ErrStyleNotFound, have %v", err)
+	}
+}
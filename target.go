@@ -0,0 +1,187 @@
+package docx
+
+import (
+	"fmt"
+)
+
+// PartTarget selects the parts of a document in which ReplaceAllIn replaces placeholders,
+// e.g. to replace a placeholder inside the body while the same placeholder inside the footer is kept.
+type PartTarget struct {
+	// Body selects the main document.
+	Body bool
+	// Headers selects the headers.
+	Headers bool
+	// Footers selects the footers.
+	Footers bool
+	// Notes selects the footnotes and endnotes.
+	Notes bool
+	// Charts selects the charts, i.e. their titles, axis titles and series names.
+	Charts bool
+	// Sections restricts the headers and footers to those shown in the sections with the given indices.
+	// Sections are numbered from 0 in document order like in SetSectionHeader, a section without its own header
+	// shows the header of the preceding section. All headers and footers are selected if Sections is empty.
+	Sections []int
+	// Pages restricts the headers and footers to the given type, e.g. HeaderFirstPage. All types if empty.
+	Pages HeaderType
+}
+
+var (
+	// TargetBody selects the main document without headers and footers.
+	TargetBody = PartTarget{Body: true}
+	// TargetHeaders selects all headers.
+	TargetHeaders = PartTarget{Headers: true}
+	// TargetFooters selects all footers.
+	TargetFooters = PartTarget{Footers: true}
+	// TargetNotes selects the footnotes and endnotes.
+	TargetNotes = PartTarget{Notes: true}
+	// TargetCharts selects all charts.
+	TargetCharts = PartTarget{Charts: true}
+	// TargetAll selects the main document, all headers and footers, the notes and the charts, like ReplaceAll.
+	TargetAll = PartTarget{Body: true, Headers: true, Footers: true, Notes: true, Charts: true}
+)
+
+// ReplaceAllIn works like ReplaceAll but replaces the placeholders only inside the parts selected by the target.
+// Placeholders of other parts are kept and can be replaced by later calls.
+//
+// Example:
+//
+//	// replace {client} inside the body, the footer keeps the placeholder
+//	doc.ReplaceAllIn(docx.TargetBody, docx.PlaceholderMap{"client": "ACME Corp"})
+//	// replace {title} inside the first page header of the second section only
+//	doc.ReplaceAllIn(docx.PartTarget{Headers: true, Sections: []int{1}, Pages: docx.HeaderFirstPage}, placeholders)
+func (d *Document) ReplaceAllIn(target PartTarget, placeholderMap PlaceholderMap) error {
+	names, err := d.TargetParts(target)
+	if err != nil {
+		return err
+	}
+	return d.replaceParts(placeholderMap, names)
+}
+
+// TargetParts returns the names of the parts selected by the target, the main document, the notes and the charts
+// first.
+func (d *Document) TargetParts(target PartTarget) ([]string, error) {
+	switch target.Pages {
+	case "", HeaderDefault, HeaderFirstPage, HeaderEvenPage:
+	default:
+		return nil, fmt.Errorf("invalid header type %s", target.Pages)
+	}
+
+	var names []string
+	if target.Body {
+		names = append(names, DocumentXml)
+	}
+	if target.Notes {
+		names = append(names, d.noteFiles...)
+	}
+	if target.Charts {
+		names = append(names, d.chartFiles...)
+	}
+	if !target.Headers && !target.Footers {
+		return names, nil
+	}
+	if len(target.Sections) == 0 && target.Pages == "" {
+		if target.Headers {
+			names = append(names, d.headerFiles...)
+		}
+		if target.Footers {
+			names = append(names, d.footerFiles...)
+		}
+		return names, nil
+	}
+
+	sections, err := d.sectionHeaderFooters()
+	if err != nil {
+		return nil, err
+	}
+	selected := target.Sections
+	if len(selected) == 0 {
+		selected = make([]int, len(sections))
+		for i := range sections {
+			selected[i] = i
+		}
+	}
+	add := func(references map[HeaderType]string) {
+		for _, pages := range []HeaderType{HeaderDefault, HeaderFirstPage, HeaderEvenPage} {
+			name, exists := references[pages]
+			if exists && (target.Pages == "" || target.Pages == pages) && !containsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, section := range selected {
+		if section < 0 || section >= len(sections) {
+			return nil, fmt.Errorf("invalid section %d, the document has %d sections", section, len(sections))
+		}
+		if target.Headers {
+			add(sections[section].headers)
+		}
+		if target.Footers {
+			add(sections[section].footers)
+		}
+	}
+	return names, nil
+}
+
+// sectionHeaderFooter holds the header and footer parts shown in a section by their type.
+type sectionHeaderFooter struct {
+	headers, footers map[HeaderType]string
+}
+
+// sectionHeaderFooters returns the headers and footers shown in each section of the document body.
+// References which are missing in a section are inherited from the preceding section.
+func (d *Document) sectionHeaderFooters() ([]sectionHeaderFooter, error) {
+	rels, _, err := d.part(relationshipsPart(DocumentXml))
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	for _, rel := range parseRelationships(rels) {
+		if !rel.external {
+			targets[rel.id] = rel.partName(DocumentXml)
+		}
+	}
+
+	data := d.files[DocumentXml]
+	headers, footers := make(map[HeaderType]string), make(map[HeaderType]string)
+	var sections []sectionHeaderFooter
+	for _, section := range sectionProperties(data) {
+		_, content, _ := splitElement(string(data[section[0]:section[1]]))
+		for _, child := range childElements(content) {
+			var references map[HeaderType]string
+			switch child.name {
+			case headerKind.reference:
+				references = headers
+			case footerKind.reference:
+				references = footers
+			default:
+				continue
+			}
+			pages, id := HeaderDefault, ""
+			for _, attribute := range xmlAttributeRegex.FindAllStringSubmatch(content[child.start:child.end], -1) {
+				switch attribute[1] {
+				case "w:type":
+					pages = HeaderType(attribute[2])
+				case "r:id":
+					id = attribute[2]
+				}
+			}
+			if name, exists := targets[id]; exists {
+				references[pages] = name
+			}
+		}
+		sections = append(sections, sectionHeaderFooter{headers: copyHeaderTypes(headers), footers: copyHeaderTypes(footers)})
+	}
+	if len(sections) == 0 {
+		sections = append(sections, sectionHeaderFooter{headers: headers, footers: footers})
+	}
+	return sections, nil
+}
+
+// copyHeaderTypes returns a copy of the references.
+func copyHeaderTypes(references map[HeaderType]string) map[HeaderType]string {
+	copied := make(map[HeaderType]string, len(references))
+	for pages, name := range references {
+		copied[pages] = name
+	}
+	return copied
+}
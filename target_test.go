@@ -0,0 +1,138 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ReplaceAllIn(t *testing.T) {
+	part := func(root, text string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:` + root + ` xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:` + root + `>`
+	}
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>{client}</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:sectPr><w:headerReference w:type="default" r:id="rId1"/><w:footerReference w:type="default" r:id="rId3"/></w:sectPr></w:pPr></w:p>`+
+			`<w:p><w:r><w:t>{client}</w:t></w:r></w:p>`+
+			`<w:sectPr><w:headerReference w:type="first" r:id="rId2"/><w:titlePg/></w:sectPr>`,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="`+RelationshipTypeHeader+`" Target="header1.xml"/>`+
+			`<Relationship Id="rId2" Type="`+RelationshipTypeHeader+`" Target="header2.xml"/>`+
+			`<Relationship Id="rId3" Type="`+RelationshipTypeFooter+`" Target="footer1.xml"/></Relationships>`,
+		"word/header1.xml", part("hdr", "{client} header"),
+		"word/header2.xml", part("hdr", "{client} first"),
+		"word/footer1.xml", part("ftr", "{client} footer"),
+	)
+
+	tests := []struct {
+		target   PartTarget
+		expected []string
+	}{
+		{TargetBody, []string{DocumentXml}},
+		{TargetFooters, []string{"word/footer1.xml"}},
+		{PartTarget{Headers: true, Sections: []int{0}}, []string{"word/header1.xml"}},
+		{PartTarget{Headers: true, Footers: true, Sections: []int{1}}, []string{"word/header1.xml", "word/header2.xml", "word/footer1.xml"}},
+		{PartTarget{Body: true, Headers: true, Pages: HeaderFirstPage}, []string{DocumentXml, "word/header2.xml"}},
+	}
+	for _, tt := range tests {
+		doc, err := OpenBytes(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts, err := doc.TargetParts(tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parts, tt.expected) {
+			t.Errorf("%+v: expected parts %v, have %v", tt.target, tt.expected, parts)
+		}
+
+		if err := doc.ReplaceAllIn(tt.target, PlaceholderMap{"client": "ACME"}); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{DocumentXml, "word/header1.xml", "word/header2.xml", "word/footer1.xml"} {
+			replaced := !strings.Contains(readTestPart(t, buf.Bytes(), name), "{client}")
+			if replaced != containsString(tt.expected, name) {
+				t.Errorf("%+v: unexpected replacement state of %s, replaced: %v", tt.target, name, replaced)
+			}
+		}
+	}
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.TargetParts(PartTarget{Headers: true, Sections: []int{2}}); err == nil {
+		t.Error("expected an error for an invalid section")
+	}
+}
+
+func TestDocument_ReplaceAllNotes(t *testing.T) {
+	notes := func(root, text string) string {
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:` + root + `s xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:` + root + ` w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:` + root + `>` +
+			`<w:` + root + ` w:id="1"><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:` + root + `></w:` + root + `s>`
+	}
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Report</w:t></w:r><w:r><w:footnoteReference w:id="1"/></w:r></w:p>`,
+		FootnotesXml, notes("footnote", "Source: {source}"),
+		EndnotesXml, notes("endnote", "Dataset: {dataset}"),
+	)
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts, err := doc.TargetParts(TargetNotes); err != nil || !reflect.DeepEqual(parts, []string{FootnotesXml, EndnotesXml}) {
+		t.Errorf("expected the notes parts, have %v (%v)", parts, err)
+	}
+	if err := doc.ReplaceAll(PlaceholderMap{"source": "Census 2020", "dataset": "DS-42"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if footnotes := readTestPart(t, buf.Bytes(), FootnotesXml); !strings.Contains(footnotes, "Source: Census 2020") {
+		t.Errorf("expected the footnote to be replaced: %s", footnotes)
+	}
+	if endnotes := readTestPart(t, buf.Bytes(), EndnotesXml); !strings.Contains(endnotes, "Dataset: DS-42") {
+		t.Errorf("expected the endnote to be replaced: %s", endnotes)
+	}
+
+	output, err := ProcessTemplateDocx(buildTestDocx(t, `<w:p/>`, FootnotesXml, notes("footnote", "Source: {{.Source}}")), map[string]string{"Source": "Census"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if footnotes := readTestPart(t, output, FootnotesXml); !strings.Contains(footnotes, "Source: Census") {
+		t.Errorf("expected the footnote template to be rendered: %s", footnotes)
+	}
+}
+
+func TestDocument_ReplaceAllInCharts(t *testing.T) {
+	chart := testChart(`<a:r><a:t>{report}</a:t></a:r>`, `<a:r><a:t>Revenue</a:t></a:r>`, `{region}`)
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{report}</w:t></w:r></w:p>`, "word/charts/chart1.xml", chart))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts, err := doc.TargetParts(TargetAll); err != nil || !reflect.DeepEqual(parts, []string{DocumentXml, "word/charts/chart1.xml"}) {
+		t.Errorf("expected the body and the chart, have %v (%v)", parts, err)
+	}
+	if err := doc.ReplaceAllIn(TargetCharts, PlaceholderMap{"report": "Sales", "region": "EMEA"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if chart := readTestPart(t, buf.Bytes(), "word/charts/chart1.xml"); !strings.Contains(chart, "<a:t>Sales</a:t>") || !strings.Contains(chart, "<c:v>EMEA</c:v>") {
+		t.Errorf("expected the placeholders of the chart to be replaced: %s", chart)
+	}
+	if document := readTestPart(t, buf.Bytes(), DocumentXml); !strings.Contains(document, "{report}") {
+		t.Errorf("expected the placeholder of the body to be kept: %s", document)
+	}
+}
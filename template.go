@@ -0,0 +1,423 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"maps"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+const (
+	// TemplateOpenDelimiter is the delimiter which opens a template action, e.g. '{{.Name}}'.
+	TemplateOpenDelimiter = "{{"
+	// TemplateCloseDelimiter is the delimiter which closes a template action.
+	TemplateCloseDelimiter = "}}"
+
+	// escapeFuncName is the name of the function which is appended to every template action
+	// in order to make the action output safe to embed into WordprocessingML.
+	escapeFuncName = "_docxEscape"
+	// templateLineBreak replaces the line breaks of values written by templates.
+	templateLineBreak = `</w:t><w:br/><w:t xml:space="preserve">`
+)
+
+var (
+	// TextNodeRegex matches a complete text-run tag (<w:t>...</w:t>), capturing the open tag, the text and the close tag.
+	TextNodeRegex = regexp.MustCompile(`(<w:t(?:\s[^>]*)?>)([^<]*)(</w:t>)`)
+	// drawingTextNodeRegex matches a complete DrawingML text tag (<a:t>...</a:t>), e.g. of chart titles, capturing the
+	// open tag, the text and the close tag.
+	drawingTextNodeRegex = regexp.MustCompile(`(<a:t(?:\s[^>]*)?>)([^<]*)(</a:t>)`)
+)
+
+// ProcessTemplateDocx renders the DOCX document given by input as a Go text/template using data.
+// Template actions ({{ ... }}) may be placed anywhere inside the text of the document body, headers and footers,
+// as well as inside chart titles, axis titles and series names, e.g. {{.Year}} revenue in {{.Currency}}.
+// Word frequently splits the text of an action into multiple runs, those actions are merged before rendering.
+// All values written by the template are XML escaped. Structured values like Image, Checklist or PageBreak are
+// inserted as WordprocessingML, e.g. {{.Logo}} with an Image value inserts the picture.
+// The style directive formats text depending on the data, e.g. {{style bold=.IsOverdue}}{{.Amount}}{{end}}.
+// The column directive inside a table cell shows the column of the cell only if the condition is true, e.g.
+// {{column .HasDiscount}}Discount in the header cell removes the whole discount column including its grid column.
+// Functions for common formatting are builtin with the names of the Sprig library, e.g. {{default "n/a" .Phone}},
+// {{join ", " .Tags}}, {{date "02 Jan 2006" .DueDate}}, {{pluralize .Count "item" "items"}} or {{currency "EUR" .Total}}.
+// If a template action cannot be parsed, a *TemplateError is returned which locates the action and suggests a fix.
+// Render renders templates with options like the locale or a timeout. The package-level defaults apply, see
+// SetDefaults.
+//
+// Example:
+//
+//	outputBytes, err := docx.ProcessTemplateDocx(templateBytes, map[string]interface{}{
+//	    "Company": "ACME Corp",
+//	})
+func ProcessTemplateDocx(input []byte, data interface{}) ([]byte, error) {
+	return renderWithOptions(input, data, defaultRenderOptions())
+}
+
+// ProcessTemplateDocxWithFuncs works like ProcessTemplateDocx but makes the given functions available to the template
+// in addition to the builtin functions. Functions with the name of a builtin function replace the builtin one.
+// The values returned by the functions are XML escaped like all other values. Render with WithFuncs does the same.
+//
+// Example:
+//
+//	outputBytes, err := docx.ProcessTemplateDocxWithFuncs(templateBytes, data, template.FuncMap{
+//	    "upper":          strings.ToUpper,
+//	    "formatCurrency": func(v float64) string { return fmt.Sprintf("%.2f EUR", v) },
+//	})
+func ProcessTemplateDocxWithFuncs(input []byte, data interface{}, funcs template.FuncMap) ([]byte, error) {
+	return renderWithOptions(input, data, defaultRenderOptions().merge(&RenderOptions{Funcs: funcs}))
+}
+
+// validateFuncs returns an error for invalid template functions, which text/template would panic on, and for
+// functions with a reserved name.
+func validateFuncs(funcs template.FuncMap) (err error) {
+	for name := range funcs {
+		if name == escapeFuncName || name == fieldFuncName {
+			return fmt.Errorf("template function name %s is reserved", name)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid template function: %v", r)
+		}
+	}()
+	template.New("").Funcs(funcs)
+	return nil
+}
+
+// addFuncs makes the given functions available to the template, replacing builtin functions of the same name.
+func (e *templateEngine) addFuncs(funcs template.FuncMap) error {
+	if err := validateFuncs(funcs); err != nil {
+		return err
+	}
+	for name, fn := range funcs {
+		e.funcs[name] = fn
+	}
+	return nil
+}
+
+// isTemplatePart returns true if the part with the given name may contain template actions.
+func isTemplatePart(name string) bool {
+	return name == DocumentXml ||
+		HeaderPathRegex.MatchString(name) ||
+		FooterPathRegex.MatchString(name) ||
+		name == FootnotesXml ||
+		name == EndnotesXml ||
+		ChartPathRegex.MatchString(name)
+}
+
+// templateEngine holds the state of a single template rendering.
+// A new engine must be used for every rendered document since helper functions (e.g. clause numbering)
+// keep state across the parts of a document.
+type templateEngine struct {
+	funcs   template.FuncMap
+	clauses *clauseNumbering
+	values  *templateValues
+	styles  *runStyles
+	// glossary collects the terms marked with 'gloss', see Glossary.funcs.
+	glossary Glossary
+	// locale is the language tag returned by the 'locale' function, see RenderOptions.Locale.
+	locale string
+	// missingKey controls what actions write for missing fields, see RenderOptions.MissingKey.
+	missingKey MissingKeyPolicy
+}
+
+// newTemplateEngine returns a templateEngine with all builtin functions registered.
+func newTemplateEngine() *templateEngine {
+	engine := &templateEngine{
+		funcs:    make(template.FuncMap),
+		clauses:  newClauseNumbering(),
+		values:   newTemplateValues(),
+		styles:   newRunStyles(),
+		glossary: make(Glossary),
+	}
+	engine.funcs[escapeFuncName] = engine.values.escape
+	engine.funcs[fieldFuncName] = engine.field
+	engine.funcs["locale"] = func() string { return engine.locale }
+	for name, fn := range engine.commonFuncs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range engine.localeFuncs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range engine.clauses.funcs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range pageBreakFuncs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range visibilityFuncs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range engine.glossary.funcs() {
+		engine.funcs[name] = fn
+	}
+	for name, fn := range engine.styles.funcs() {
+		engine.funcs[name] = fn
+	}
+	return engine
+}
+
+// render executes the template in every template part of the input document and returns the rendered document.
+func (e *templateEngine) render(input []byte, data interface{}) ([]byte, error) {
+	parts, err := readArchiveParts(input, isTemplatePart)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := parts[DocumentXml]; !exists {
+		return nil, fmt.Errorf("%w, %s is missing", ErrInvalidArchive, DocumentXml)
+	}
+
+	// the document body is always rendered first so that helpers with state (e.g. clauses) are numbered in reading order.
+	names := []string{DocumentXml}
+	for name := range parts {
+		if name != DocumentXml {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		rendered, err := e.renderPart(name, parts[name], data)
+		if err != nil {
+			return nil, err
+		}
+		parts[name] = rendered
+	}
+
+	// second pass, resolve references which are only known after all parts were rendered
+	for name, part := range parts {
+		resolved, err := e.clauses.resolve(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve clause references in %s: %w", name, err)
+		}
+		resolved, err = e.styles.apply(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply styles in %s: %w", name, err)
+		}
+		resolved, err = applyVisibility(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply visibility in %s: %w", name, err)
+		}
+		parts[name] = resolved
+	}
+
+	rendered, err := rewriteArchive(input, parts)
+	if err != nil {
+		return nil, err
+	}
+	return e.values.replace(rendered)
+}
+
+// renderPart parses and executes a single XML part as template.
+func (e *templateEngine) renderPart(name string, part []byte, data interface{}) ([]byte, error) {
+	source := prepareTemplateSource(part)
+	if !strings.Contains(source, TemplateOpenDelimiter) {
+		return part, nil
+	}
+	// a function named style replaces the style directive like all builtin functions
+	if _, replaced := e.funcs[styleFuncName]; !replaced {
+		var err error
+		if source, err = rewriteStyleActions(source); err != nil {
+			return nil, e.diagnoseTemplate(name, part, err)
+		}
+	}
+	source = hoistRowActions(source)
+	source = hoistMarkerActions(source)
+	if _, replaced := e.funcs[columnFuncName]; !replaced {
+		var err error
+		if source, err = rewriteColumnActions(source); err != nil {
+			return nil, e.diagnoseTemplate(name, part, err)
+		}
+	}
+
+	funcs := e.funcs
+	if ChartPathRegex.MatchString(name) {
+		funcs = maps.Clone(e.funcs)
+		funcs[escapeFuncName] = e.drawingEscape
+	}
+	tmpl := template.New(name).Funcs(funcs)
+	if e.missingKey.mode == missingKeyError {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	tmpl, err := tmpl.Parse(source)
+	if err != nil {
+		return nil, e.diagnoseTemplate(name, part, err)
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			if e.missingKey.resolvesFields() {
+				resolveFieldActions(t.Tree.Root)
+			}
+			escapeTemplateNode(t.Tree.Root)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to execute template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// prepareTemplateSource turns the XML part into a template source.
+// Template actions which are split over multiple text-runs are moved completely into the text-run
+// in which the action starts. The text of actions is XML unescaped since it's template code and not document text.
+// Actions inside DrawingML text, e.g. of chart titles, are merged separately.
+func prepareTemplateSource(part []byte) string {
+	source := mergeTemplateActions(part, TextNodeRegex)
+	if !bytes.Contains(part, []byte("<a:t")) {
+		return source
+	}
+	return mergeTemplateActions([]byte(source), drawingTextNodeRegex)
+}
+
+// mergeTemplateActions moves the template actions which are split over multiple text nodes matched by textNodeRegex
+// into the node in which they start, see prepareTemplateSource.
+func mergeTemplateActions(part []byte, textNodeRegex *regexp.Regexp) string {
+	matches := textNodeRegex.FindAllSubmatchIndex(part, -1)
+	if len(matches) == 0 {
+		return string(part)
+	}
+
+	// concatenate all texts to find the actions, remembering where each text starts
+	var all strings.Builder
+	nodeStart := make([]int, len(matches))
+	for i, m := range matches {
+		nodeStart[i] = all.Len()
+		all.Write(part[m[4]:m[5]])
+	}
+	text := all.String()
+	spans := findActionSpans(text)
+	if len(spans) == 0 {
+		return string(part)
+	}
+
+	var out strings.Builder
+	last := 0
+	for i, m := range matches {
+		start := nodeStart[i]
+		end := start + (m[5] - m[4])
+
+		var nodeText strings.Builder
+		hasAction := false
+		pos := start
+		for _, span := range spans {
+			if span[1] <= pos || span[0] >= end {
+				continue
+			}
+			if span[0] >= pos {
+				nodeText.WriteString(text[pos:span[0]])
+				nodeText.WriteString(html.UnescapeString(text[span[0]:span[1]]))
+				hasAction = true
+			}
+			pos = min(span[1], end)
+		}
+		if pos < end {
+			nodeText.WriteString(text[pos:end])
+		}
+
+		// DrawingML text always preserves whitespace
+		openTag := string(part[m[2]:m[3]])
+		if hasAction && textNodeRegex == TextNodeRegex && !strings.Contains(openTag, "xml:space") {
+			openTag = `<w:t xml:space="preserve">`
+		}
+
+		out.Write(part[last:m[0]])
+		out.WriteString(openTag)
+		out.WriteString(nodeText.String())
+		out.Write(part[m[6]:m[7]])
+		last = m[1]
+	}
+	out.Write(part[last:])
+	return out.String()
+}
+
+// findActionSpans returns the [start, end) offsets of all template actions inside text.
+// An action which is never closed spans until the end of the text, the template parser will report it.
+func findActionSpans(text string) (spans [][2]int) {
+	pos := 0
+	for {
+		open := strings.Index(text[pos:], TemplateOpenDelimiter)
+		if open < 0 {
+			return spans
+		}
+		open += pos
+		end := len(text)
+		if closing := strings.Index(text[open+len(TemplateOpenDelimiter):], TemplateCloseDelimiter); closing >= 0 {
+			end = open + len(TemplateOpenDelimiter) + closing + len(TemplateCloseDelimiter)
+		}
+		spans = append(spans, [2]int{open, end})
+		pos = end
+	}
+}
+
+// escapeTemplateNode walks the template tree and appends the escape function to every action which
+// produces output, similar to what html/template does.
+func escapeTemplateNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeTemplateNode(child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		identifier := parse.NewIdentifier(escapeFuncName).SetTree(nil).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{identifier},
+		})
+	case *parse.IfNode:
+		escapeTemplateNode(n.List)
+		escapeTemplateNode(n.ElseList)
+	case *parse.RangeNode:
+		escapeTemplateNode(n.List)
+		escapeTemplateNode(n.ElseList)
+	case *parse.WithNode:
+		escapeTemplateNode(n.List)
+		escapeTemplateNode(n.ElseList)
+	}
+}
+
+// templateXml is WordprocessingML which is written by builtin template functions.
+// It is not escaped and must be valid inside the text of a run (<w:t>), closing and re-opening it as needed.
+type templateXml string
+
+// templateEscape converts the value of a template action into text which can be embedded into a text-run.
+// Line breaks are converted into <w:br/> elements.
+func templateEscape(args ...interface{}) string {
+	if len(args) == 1 {
+		if xml, ok := args[0].(templateXml); ok {
+			return string(xml)
+		}
+	}
+	value := fmt.Sprint(args...)
+	if len(args) == 1 && args[0] == nil {
+		value = ""
+	}
+	value = html.EscapeString(normalizeText(value))
+	return strings.ReplaceAll(value, "\n", templateLineBreak)
+}
+
+// drawingEscape escapes values like templateEscape for DrawingML text, e.g. of charts, in which the line breaks of
+// WordprocessingML are not valid. Line breaks are replaced by spaces. Structured values are written as the text
+// of their String method, if any.
+func (e *templateEngine) drawingEscape(args ...interface{}) (string, error) {
+	if len(args) == 1 {
+		if value, isRich := args[0].(inlineValue); isRich {
+			stringer, isStringer := value.(fmt.Stringer)
+			if !isStringer {
+				return "", fmt.Errorf("%T cannot be inserted into DrawingML text", value)
+			}
+			args = []interface{}{stringer.String()}
+		}
+	}
+	return strings.ReplaceAll(templateEscape(args...), templateLineBreak, " "), nil
+}
@@ -0,0 +1,171 @@
+package docx
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// ParagraphTagRegex matches the open, close and self-closing tags of paragraphs (<w:p>, </w:p> and <w:p/>)
+	// and captures the closing slash and the self-closing slash.
+	ParagraphTagRegex = regexp.MustCompile(`<(/?)w:p(?:\s[^>]*?)?(/?)>`)
+	// blockContainerTagRegex matches the open and close tags of the elements which contain paragraphs or rows
+	// besides the body and captures the closing slash.
+	blockContainerTagRegex = regexp.MustCompile(`<(/?)w:(?:tbl|tc|txbxContent)(?:\s[^>]*)?>`)
+	// containerEndRegex matches the close tags of elements which must end with a paragraph.
+	containerEndRegex = regexp.MustCompile(`^\s*</w:(?:tc|txbxContent)>`)
+)
+
+// markerBlockKeywords are the keywords of the actions which are hoisted out of their paragraph or table row.
+var markerBlockKeywords = []string{"if", "with", "range", "else", "end"}
+
+// markerSpan is a paragraph or table row which contains nothing but a single block action.
+type markerSpan struct {
+	start, end int
+	// container is the offset of the element containing the span (table, cell or text box), -1 for the body.
+	container int
+}
+
+// hoistMarkerActions replaces the paragraphs and table rows which contain nothing but a block action with the bare
+// action, so blocks may span multiple paragraphs and rows: if the condition of {{if .Flag}} alone in a paragraph is
+// false, all paragraphs and tables up to the {{end}} alone in another paragraph are removed, including both marker
+// paragraphs. If it is true, only the marker paragraphs are removed.
+// A block is only hoisted if all of its actions (including else) are markers inside the same container, e.g. the
+// body or the same table cell. The last paragraph of a cell or text box is never removed since it is required.
+// The source must be prepared by prepareTemplateSource, so that every action is part of a single text.
+func hoistMarkerActions(source string) string {
+	actions := templateActionRegex.FindAllStringIndex(source, -1)
+	if len(actions) < 2 {
+		return source
+	}
+
+	// group the actions into blocks, each block holds the indices of its opening, else and end actions
+	var blocks, open [][]int
+	for i, action := range actions {
+		switch keyword := actionKeyword(source[action[0]:action[1]]); {
+		case containsString(templateBlockKeywords, keyword):
+			open = append(open, []int{i})
+		case keyword == "else" && len(open) > 0:
+			open[len(open)-1] = append(open[len(open)-1], i)
+		case keyword == "end" && len(open) > 0:
+			blocks = append(blocks, append(open[len(open)-1], i))
+			open = open[:len(open)-1]
+		}
+	}
+
+	rows := tableRows(source)
+	paragraphs := paragraphSpans(source)
+	var hoisted []markerSpan
+	var hoistedActions [][2]int
+	for _, block := range blocks {
+		if !containsString(markerBlockKeywords, actionKeyword(source[actions[block[0]][0]:actions[block[0]][1]])) {
+			continue // define and block
+		}
+		spans := make([]markerSpan, len(block))
+		hoistable := true
+		for i, index := range block {
+			span, isMarker := markerOf(source, actions[index], rows, paragraphs)
+			if !isMarker || i > 0 && span.container != spans[0].container {
+				hoistable = false
+				break
+			}
+			spans[i] = span
+		}
+		if !hoistable {
+			continue
+		}
+		for i, index := range block {
+			hoisted = append(hoisted, spans[i])
+			hoistedActions = append(hoistedActions, [2]int{actions[index][0], actions[index][1]})
+		}
+	}
+
+	// the spans are replaced from the end so the offsets of the preceding spans stay valid
+	order := make([]int, len(hoisted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return hoisted[order[i]].start > hoisted[order[j]].start })
+	for _, i := range order {
+		span, action := hoisted[i], hoistedActions[i]
+		source = source[:span.start] + source[action[0]:action[1]] + source[span.end:]
+	}
+	return source
+}
+
+// markerOf returns the table row or paragraph which contains nothing but the given action.
+// The second return value is false if the action shares its paragraph with other content.
+func markerOf(source string, action []int, rows, paragraphs [][2]int) (markerSpan, bool) {
+	without := func(span [2]int) string {
+		return source[span[0]:action[0]] + source[action[1]:span[1]]
+	}
+
+	if row, found := innermostSpan(rows, action[0]); found {
+		content := source[row[0]:row[1]]
+		if !strings.Contains(content, "<w:tbl>") && !strings.Contains(content, "<w:tbl ") && len(templateActionRegex.FindAllStringIndex(content, 2)) == 1 &&
+			isEmptyRow(without(row)) {
+			return markerSpan{row[0], row[1], blockContainer(source, row[0])}, true
+		}
+	}
+
+	paragraph, found := innermostSpan(paragraphs, action[0])
+	if !found {
+		return markerSpan{}, false
+	}
+	content := source[paragraph[0]:paragraph[1]]
+	if ParagraphTagRegex.MatchString(content[1:len(content)-len("</w:p>")]) ||
+		len(templateActionRegex.FindAllStringIndex(content, 2)) != 1 ||
+		!isEmptyParagraph(without(paragraph)) ||
+		containerEndRegex.MatchString(source[paragraph[1]:]) {
+		return markerSpan{}, false
+	}
+	return markerSpan{paragraph[0], paragraph[1], blockContainer(source, paragraph[0])}, true
+}
+
+// innermostSpan returns the span with the largest start offset which contains the position.
+func innermostSpan(spans [][2]int, pos int) ([2]int, bool) {
+	var innermost [2]int
+	found := false
+	for _, span := range spans {
+		if span[0] <= pos && pos < span[1] && (!found || span[0] > innermost[0]) {
+			innermost, found = span, true
+		}
+	}
+	return innermost, found
+}
+
+// paragraphSpans returns the [start, end) offsets of all paragraphs including nested ones (e.g. inside text boxes).
+// Self-closing paragraphs are not included since they cannot contain actions.
+func paragraphSpans(source string) (spans [][2]int) {
+	var open []int
+	for _, match := range ParagraphTagRegex.FindAllStringSubmatchIndex(source, -1) {
+		switch {
+		case match[5] > match[4]:
+			// self-closing
+		case match[3] == match[2]:
+			open = append(open, match[0])
+		case len(open) > 0:
+			spans = append(spans, [2]int{open[len(open)-1], match[1]})
+			open = open[:len(open)-1]
+		}
+	}
+	return spans
+}
+
+// blockContainer returns the offset of the innermost table, cell or text box which contains the position,
+// -1 if the position is directly inside the body (or the root element of a header, footer or notes part).
+func blockContainer(source string, pos int) int {
+	var open []int
+	for _, match := range blockContainerTagRegex.FindAllStringSubmatchIndex(source[:pos], -1) {
+		if match[3] == match[2] {
+			open = append(open, match[0])
+		} else if len(open) > 0 {
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) == 0 {
+		return -1
+	}
+	return open[len(open)-1]
+}
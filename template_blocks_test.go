@@ -0,0 +1,88 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHoistMarkerActions(t *testing.T) {
+	tests := []struct {
+		name, source, expected string
+	}{
+		{
+			name:     "paragraphs",
+			source:   `<w:p><w:pPr><w:jc w:val="left"/></w:pPr><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:r><w:t>Clause</w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve"> {{end}} </w:t></w:r></w:p><w:p/>`,
+			expected: `{{if .Flag}}<w:p><w:r><w:t>Clause</w:t></w:r></w:p>{{end}}<w:p/>`,
+		},
+		{
+			name:     "else and nested blocks",
+			source:   `<w:p><w:r><w:t>{{if .A}}</w:t></w:r></w:p><w:p><w:r><w:t>{{range .B}}{{.}}{{end}}</w:t></w:r></w:p><w:p><w:r><w:t>{{else}}</w:t></w:r></w:p><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`,
+			expected: `{{if .A}}<w:p><w:r><w:t>{{range .B}}{{.}}{{end}}</w:t></w:r></w:p>{{else}}{{end}}`,
+		},
+		{
+			name:     "table rows",
+			source:   `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p></w:tc><w:tc><w:p/></w:tc></w:tr><w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc></w:tr><w:tr><w:tc><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`,
+			expected: `<w:tbl>{{if .Flag}}<w:tr><w:tc><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc></w:tr>{{end}}</w:tbl>`,
+		},
+		{
+			name:     "table between marker paragraphs",
+			source:   `<w:p><w:r><w:t>{{with .Offer}}</w:t></w:r></w:p><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{.Price}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`,
+			expected: `{{with .Offer}}<w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{.Price}}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>{{end}}`,
+		},
+		{
+			name:     "end shares its paragraph",
+			source:   `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:r><w:t>Clause{{end}}</w:t></w:r></w:p>`,
+			expected: `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:r><w:t>Clause{{end}}</w:t></w:r></w:p>`,
+		},
+		{
+			name:     "different containers",
+			source:   `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p><w:p/></w:tc><w:tc><w:p/></w:tc></w:tr></w:tbl>`,
+			expected: `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p><w:p/></w:tc><w:tc><w:p/></w:tc></w:tr></w:tbl>`,
+		},
+		{
+			name:     "last paragraph of a cell",
+			source:   `<w:tc><w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:r><w:t>A</w:t></w:r></w:p><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p></w:tc>`,
+			expected: `<w:tc><w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:r><w:t>A</w:t></w:r></w:p><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p></w:tc>`,
+		},
+		{
+			name:     "section break",
+			source:   `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:pPr><w:sectPr/></w:pPr><w:r><w:t>{{end}}</w:t></w:r></w:p>`,
+			expected: `<w:p><w:r><w:t>{{if .Flag}}</w:t></w:r></w:p><w:p><w:pPr><w:sectPr/></w:pPr><w:r><w:t>{{end}}</w:t></w:r></w:p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if hoisted := hoistMarkerActions(tt.source); hoisted != tt.expected {
+				t.Errorf("expected %s, have %s", tt.expected, hoisted)
+			}
+		})
+	}
+}
+
+func TestProcessTemplateDocx_ParagraphBlocks(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>Contract</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{if .</w:t></w:r><w:r><w:t>Liability}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Liability clause</w:t></w:r></w:p>`+
+			`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Cap</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Signature</w:t></w:r></w:p>`)
+
+	for _, liability := range []bool{true, false} {
+		output, err := ProcessTemplateDocx(input, map[string]bool{"Liability": liability})
+		if err != nil {
+			t.Fatal(err)
+		}
+		document := readTestPart(t, output, DocumentXml)
+		if strings.Contains(document, "Liability clause") != liability || strings.Contains(document, "<w:tbl>") != liability {
+			t.Errorf("%v: unexpected clause: %s", liability, document)
+		}
+		paragraphs := 2
+		if liability {
+			paragraphs = 4
+		}
+		if n := strings.Count(document, "<w:p>"); n != paragraphs {
+			t.Errorf("%v: expected %d paragraphs without the marker paragraphs, have %d: %s", liability, paragraphs, n, document)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+const (
+	// columnFuncName is the keyword of the column directive, e.g. '{{column .HasDiscount}}'.
+	columnFuncName = "column"
+	// columnVariablePrefix is the prefix of the variables holding the conditions of column directives.
+	columnVariablePrefix = "$_docxColumn"
+)
+
+var (
+	// columnActionRegex matches the column directive and captures the condition.
+	columnActionRegex = regexp.MustCompile(`(?s)^\{\{-?\s*column(?:\s+(.*?))?\s*-?\}\}$`)
+	// tableTagRegex matches the open and close tags of tables and captures the slash.
+	tableTagRegex = regexp.MustCompile(`<(/?)w:tbl[\s>]`)
+)
+
+// sourceEdit replaces source[start:end] with text, edits at the same position are applied in the order of seq.
+type sourceEdit struct {
+	start, end, seq int
+	text            string
+}
+
+// rewriteColumnActions rewrites the column directives of the prepared template source. {{column .HasDiscount}} inside
+// a cell keeps the columns of the cell in all rows of the table only if the condition is true, e.g. to hide a
+// discount column when no line has a discount. The condition is evaluated once in front of the table and stored in a
+// variable, the cells and the grid columns of the table are enclosed in {{if}} actions testing the variable. Cells
+// spanning hidden and visible columns are kept and span fewer columns. The source must be prepared and its row
+// actions hoisted, so no block action spans cells.
+func rewriteColumnActions(source string) (string, error) {
+	for n := 0; ; n++ {
+		var action []int
+		for _, match := range templateActionRegex.FindAllStringIndex(source, -1) {
+			if actionKeyword(source[match[0]:match[1]]) == columnFuncName {
+				action = match
+				break
+			}
+		}
+		if action == nil {
+			return source, nil
+		}
+		match := columnActionRegex.FindStringSubmatch(source[action[0]:action[1]])
+		if match == nil || match[1] == "" {
+			return "", fmt.Errorf("invalid column directive %s, expected {{column condition}}", source[action[0]:action[1]])
+		}
+		rewritten, err := rewriteColumnAction(source, action, match[1], fmt.Sprintf("%s%d", columnVariablePrefix, n))
+		if err != nil {
+			return "", err
+		}
+		source = rewritten
+	}
+}
+
+// rewriteColumnAction rewrites a single column directive at the position of the action with the variable.
+func rewriteColumnAction(source string, action []int, condition, variable string) (string, error) {
+	// the innermost table containing the action
+	var open []int
+	for _, match := range tableTagRegex.FindAllStringSubmatchIndex(source[:action[0]], -1) {
+		if match[3] == match[2] {
+			open = append(open, match[0])
+		} else if len(open) > 0 {
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) == 0 {
+		return "", fmt.Errorf("column directive %s is not inside a table", source[action[0]:action[1]])
+	}
+	tableStart := open[len(open)-1]
+	tableEnd := tableStart + childElements(source[tableStart:])[0].end
+	table := source[tableStart:tableEnd]
+	openTag, inner, _ := splitElement(table)
+	innerStart := tableStart + len(openTag)
+
+	var rows []string
+	var rowOffsets []int64
+	collectElements(source, int64(innerStart), int64(innerStart+len(inner)), TableRowElementName, &rows, &rowOffsets)
+	type cell struct {
+		start, end, column, span int
+	}
+	rowCells := make([][]cell, len(rows))
+	first, count := -1, 0
+	for r, row := range rows {
+		var cells []string
+		var cellOffsets []int64
+		collectElements(source, rowOffsets[r], rowOffsets[r]+int64(len(row)), TableCellElementName, &cells, &cellOffsets)
+		column := 0
+		if match := gridBeforeRegex.FindStringSubmatch(childProperties(row, "trPr")); match != nil {
+			column, _ = strconv.Atoi(match[1])
+		}
+		for c, element := range cells {
+			span := 1
+			if match := gridSpanRegex.FindStringSubmatch(childProperties(element, "tcPr")); match != nil {
+				if value, err := strconv.Atoi(match[1]); err == nil && value > 1 {
+					span = value
+				}
+			}
+			start := int(cellOffsets[c])
+			rowCells[r] = append(rowCells[r], cell{start, start + len(element), column, span})
+			if start < action[0] && action[1] <= start+len(element) {
+				first, count = column, span
+			}
+			column += span
+		}
+	}
+	if first < 0 {
+		return "", fmt.Errorf("column directive %s is not inside a table cell", source[action[0]:action[1]])
+	}
+
+	ifAction := TemplateOpenDelimiter + "if " + variable + TemplateCloseDelimiter
+	endAction := TemplateOpenDelimiter + "end" + TemplateCloseDelimiter
+	var edits []sourceEdit
+	edit := func(start, end int, text string) {
+		edits = append(edits, sourceEdit{start: start, end: end, seq: len(edits), text: text})
+	}
+	edit(tableStart, tableStart, fmt.Sprintf("%s%s := (%s)%s", TemplateOpenDelimiter, variable, condition, TemplateCloseDelimiter))
+	for _, child := range childElements(inner) {
+		if child.name != "tblGrid" {
+			continue
+		}
+		gridOpen, grid, _ := splitElement(inner[child.start:child.end])
+		gridStart := innerStart + child.start + len(gridOpen)
+		column := 0
+		for _, gridCol := range childElements(grid) {
+			if gridCol.name != "gridCol" {
+				continue
+			}
+			if column >= first && column < first+count {
+				edit(gridStart+gridCol.start, gridStart+gridCol.start, ifAction)
+				edit(gridStart+gridCol.end, gridStart+gridCol.end, endAction)
+			}
+			column++
+		}
+	}
+	edit(action[0], action[1], "")
+	for _, cells := range rowCells {
+		for _, c := range cells {
+			hidden := min(c.column+c.span, first+count) - max(c.column, first)
+			switch {
+			case hidden <= 0:
+			case hidden == c.span:
+				edit(c.start, c.start, ifAction)
+				edit(c.end, c.end, endAction)
+			default:
+				element := source[c.start:c.end]
+				span := gridSpanRegex.FindStringSubmatchIndex(element)
+				edit(c.start+span[2], c.start+span[3], fmt.Sprintf("%s%d%selse%s%d%s", ifAction, c.span, TemplateOpenDelimiter, TemplateCloseDelimiter, c.span-hidden, endAction))
+			}
+		}
+	}
+
+	// the edits are applied from the end so the offsets of the preceding edits stay valid
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start > edits[j].start
+		}
+		return edits[i].seq > edits[j].seq
+	})
+	for _, e := range edits {
+		source = source[:e.start] + e.text + source[e.end:]
+	}
+	return source, nil
+}
@@ -0,0 +1,49 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_Column(t *testing.T) {
+	cell := func(text string) string {
+		return `<w:tc><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:tc>`
+	}
+	input := buildTestDocx(t, `<w:tbl><w:tblPr/><w:tblGrid><w:gridCol w:w="3000"/><w:gridCol w:w="1000"/><w:gridCol w:w="2000"/></w:tblGrid>`+
+		`<w:tr>`+cell("Item")+cell("{{column .HasDiscount}}Discount")+cell("Total")+`</w:tr>`+
+		`<w:tr>`+cell("{{range .Items}}{{.Name}}")+cell("{{.Discount}}")+cell("{{.Total}}{{end}}")+`</w:tr>`+
+		`<w:tr><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>Sum</w:t></w:r></w:p></w:tc>`+cell("{{.Sum}}")+`</w:tr>`+
+		`</w:tbl>`)
+	items := []map[string]interface{}{{"Name": "Apples", "Discount": "", "Total": "3.00"}}
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{"HasDiscount": false, "Items": items, "Sum": "3.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<w:tbl><w:tblPr/><w:tblGrid><w:gridCol w:w="3000"/><w:gridCol w:w="2000"/></w:tblGrid>` +
+		`<w:tr>` + cell("Item") + cell("Total") + `</w:tr>` +
+		`<w:tr>` + cell("Apples") + cell("3.00") + `</w:tr>` +
+		`<w:tr><w:tc><w:tcPr><w:gridSpan w:val="1"/></w:tcPr><w:p><w:r><w:t>Sum</w:t></w:r></w:p></w:tc>` + cell("3.00") + `</w:tr>` +
+		`</w:tbl>`
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(stripPreserve(document), expected) {
+		t.Errorf("expected %s in %s", expected, document)
+	}
+
+	output, err = ProcessTemplateDocx(input, map[string]interface{}{"HasDiscount": true, "Items": items, "Sum": "3.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := stripPreserve(readTestPart(t, output, DocumentXml)); !strings.Contains(document, `<w:gridCol w:w="1000"/>`) ||
+		!strings.Contains(document, cell("Discount")) || !strings.Contains(document, `<w:gridSpan w:val="2"/>`) {
+		t.Errorf("expected the discount column in %s", document)
+	}
+
+	if _, err := ProcessTemplateDocx(buildTestDocx(t, `<w:p><w:r><w:t>{{column .HasDiscount}}</w:t></w:r></w:p>`), nil); err == nil {
+		t.Error("expected error for column directive outside of a table")
+	}
+}
+
+// stripPreserve removes the xml:space attributes which the template engine adds to text nodes containing actions.
+func stripPreserve(document string) string {
+	return strings.ReplaceAll(document, ` xml:space="preserve"`, "")
+}
@@ -0,0 +1,268 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrTemplateParse is matched by all errors of templates which cannot be parsed, use errors.As with a *TemplateError
+// for the location of the offending action.
+var ErrTemplateParse = errors.New("unable to parse template")
+
+var (
+	// undefinedFuncRegex matches the error of text/template for calls of unknown functions and captures the name.
+	undefinedFuncRegex = regexp.MustCompile(`function "([^"]+)" not defined`)
+	// smartQuoteReplacer replaces typographic quotes which Word inserts by autocorrect with straight quotes.
+	smartQuoteReplacer = strings.NewReplacer(
+		"\u201C", `"`, "\u201D", `"`, "\u201E", `"`, "\u201F", `"`,
+		"\u2018", `"`, "\u2019", `"`, "\u201A", `"`, "\u201B", `"`,
+	)
+
+	// builtinTemplateFuncs are the names of the functions predefined by text/template.
+	builtinTemplateFuncs = []string{
+		"and", "call", "html", "index", "slice", "js", "len", "not", "or", "print", "printf", "println", "urlquery",
+		"eq", "ge", "gt", "le", "lt", "ne",
+	}
+	// templateBlockKeywords are the actions which must be closed by {{end}}.
+	templateBlockKeywords = []string{"if", "range", "with", "define", "block"}
+)
+
+// TemplateError describes a template action which cannot be parsed, including where it is located and how it
+// can probably be fixed. It is returned by ProcessTemplateDocx and can be retrieved using errors.As, it matches
+// ErrTemplateParse with errors.Is.
+type TemplateError struct {
+	// Part is the name of the file inside the archive which contains the action, e.g. 'word/document.xml'.
+	Part string
+	// Paragraph is the 1-based number of the paragraph inside the part which contains the action, 0 if unknown.
+	Paragraph int
+	// Line is the 1-based line of the XML source of the part reported by the template parser, 0 if unknown.
+	Line int
+	// Expression is the offending action as written in the document, e.g. '{{if .Paid}}'. It is empty if unknown.
+	Expression string
+	// Message describes the problem.
+	Message string
+	// Suggestion describes how the problem can probably be fixed, it is empty if no suggestion is known.
+	Suggestion string
+	// Err is the error of the template parser.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TemplateError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%s %s", ErrTemplateParse, e.Part)
+	if e.Paragraph > 0 {
+		fmt.Fprintf(&msg, ", paragraph %d", e.Paragraph)
+	} else if e.Line > 0 {
+		fmt.Fprintf(&msg, ", line %d", e.Line)
+	}
+	if e.Expression != "" {
+		fmt.Fprintf(&msg, ", %s", e.Expression)
+	}
+	fmt.Fprintf(&msg, ": %s", e.Message)
+	if e.Suggestion != "" {
+		fmt.Fprintf(&msg, " (%s)", e.Suggestion)
+	}
+	return msg.String()
+}
+
+// Unwrap returns the error of the template parser.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrTemplateParse.
+func (e *TemplateError) Is(target error) bool {
+	return target == ErrTemplateParse
+}
+
+// templateAction is a template action inside the text of a part.
+type templateAction struct {
+	text      string
+	paragraph int
+	closed    bool
+}
+
+// templateActions returns all template actions inside the text of the part in document order.
+func templateActions(part []byte) []templateAction {
+	paragraphs := ParagraphOpenRegex.FindAllIndex(part, -1)
+	matches := TextNodeRegex.FindAllSubmatchIndex(part, -1)
+
+	var all strings.Builder
+	nodeStart := make([]int, len(matches))
+	nodeParagraph := make([]int, len(matches))
+	for i, m := range matches {
+		nodeStart[i] = all.Len()
+		nodeParagraph[i] = sort.Search(len(paragraphs), func(p int) bool { return paragraphs[p][0] > m[0] })
+		all.WriteString(html.UnescapeString(string(part[m[4]:m[5]])))
+	}
+	text := all.String()
+
+	var actions []templateAction
+	for _, span := range findActionSpans(text) {
+		node := sort.Search(len(nodeStart), func(i int) bool { return nodeStart[i] > span[0] }) - 1
+		action := templateAction{text: text[span[0]:span[1]], closed: strings.HasSuffix(text[span[0]:span[1]], TemplateCloseDelimiter)}
+		if node >= 0 {
+			action.paragraph = nodeParagraph[node]
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// actionKeyword returns the first word of the action, e.g. 'if' for '{{- if .Paid}}'.
+func actionKeyword(action string) string {
+	inner := strings.TrimPrefix(action, TemplateOpenDelimiter)
+	inner = strings.TrimSuffix(inner, TemplateCloseDelimiter)
+	inner = strings.Trim(strings.TrimSpace(inner), "-")
+	fields := strings.Fields(inner)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// diagnoseTemplate turns the parse error of the template part into a TemplateError which points to the offending
+// action and suggests a fix for the most common authoring errors.
+func (e *templateEngine) diagnoseTemplate(name string, part []byte, parseErr error) *TemplateError {
+	templateErr := &TemplateError{Part: name, Message: parseErr.Error(), Err: parseErr}
+	if match := regexp.MustCompile(`^template: ` + regexp.QuoteMeta(name) + `:([0-9]+):`).FindStringSubmatch(parseErr.Error()); match != nil {
+		templateErr.Line, _ = strconv.Atoi(match[1])
+	}
+	actions := templateActions(part)
+	at := func(action templateAction, message, suggestion string) *TemplateError {
+		templateErr.Paragraph = action.paragraph
+		templateErr.Expression = action.text
+		templateErr.Message = message
+		templateErr.Suggestion = suggestion
+		return templateErr
+	}
+
+	// Word replaces straight quotes by typographic ones while typing, which breaks string literals
+	for _, action := range actions {
+		if fixed := smartQuoteReplacer.Replace(action.text); fixed != action.text {
+			return at(action, "typographic quotes are not allowed inside template actions",
+				fmt.Sprintf("use straight quotes: %s", fixed))
+		}
+	}
+
+	for _, action := range actions {
+		if !action.closed {
+			return at(action, "action is not closed", fmt.Sprintf("add %s at the end of the action", TemplateCloseDelimiter))
+		}
+	}
+
+	if match := undefinedFuncRegex.FindStringSubmatch(parseErr.Error()); match != nil {
+		identifier := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(match[1]) + `\b`)
+		for _, action := range actions {
+			if !identifier.MatchString(action.text) {
+				continue
+			}
+			suggestion := fmt.Sprintf("fields must start with a dot: %s", identifier.ReplaceAllString(action.text, "${1}."+match[1]))
+			// all functions start lowercase, capitalized names are almost always fields
+			if similar := e.similarFunc(match[1]); similar != "" && !unicode.IsUpper([]rune(match[1])[0]) {
+				suggestion = fmt.Sprintf("did you mean %s?", similar)
+			}
+			return at(action, fmt.Sprintf("function %s is not defined", match[1]), suggestion)
+		}
+	}
+
+	// the style directive is a block unless a function named style replaces it
+	_, styleReplaced := e.funcs[styleFuncName]
+	if !styleReplaced {
+		for _, action := range actions {
+			if actionKeyword(action.text) != styleFuncName {
+				continue
+			}
+			if _, err := rewriteStyleAction(action.text); err != nil {
+				return at(action, err.Error(), "write the properties as name=value, e.g. {{style bold=.IsOverdue}}")
+			}
+		}
+	}
+
+	// every block must be closed by {{end}} and {{else}} and {{end}} require an open block
+	var open []templateAction
+	for _, action := range actions {
+		switch keyword := actionKeyword(action.text); {
+		case keyword == "end" && len(open) == 0:
+			return at(action, "{{end}} without matching block", "remove it or add the missing {{if}}, {{range}} or {{with}}")
+		case keyword == "end":
+			open = open[:len(open)-1]
+		case keyword == "else" && len(open) == 0:
+			return at(action, "{{else}} without matching block", "add the missing {{if}}, {{range}} or {{with}}")
+		case containsString(templateBlockKeywords, keyword) || keyword == styleFuncName && !styleReplaced:
+			open = append(open, action)
+		}
+	}
+	if len(open) > 0 {
+		action := open[len(open)-1]
+		return at(action, fmt.Sprintf("{{%s}} is never closed", actionKeyword(action.text)), "add {{end}} at the end of the block")
+	}
+	return templateErr
+}
+
+// similarFunc returns the name of the template function which is most similar to the given name,
+// or an empty string if there is no function with a similar name.
+func (e *templateEngine) similarFunc(name string) string {
+	names := append([]string{}, builtinTemplateFuncs...)
+	for fn := range e.funcs {
+		if fn != escapeFuncName && fn != fieldFuncName {
+			names = append(names, fn)
+		}
+	}
+	return similarName(name, names)
+}
+
+// similarName returns the candidate which is most similar to the given name ignoring case, or an empty string if
+// no candidate is similar.
+func similarName(name string, candidates []string) string {
+	sorted := append([]string{}, candidates...)
+	sort.Strings(sorted)
+
+	// at most a third of the name may differ
+	best, bestDistance := "", len(name)/3+1
+	for _, candidate := range sorted {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance of both strings.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// containsString returns true if the slice contains the value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
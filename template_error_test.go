@@ -0,0 +1,88 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_TemplateError(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		paragraph  int
+		expression string
+		suggestion string
+	}{
+		{
+			name:       "smart quotes",
+			body:       "<w:p><w:r><w:t>Status</w:t></w:r></w:p><w:p><w:r><w:t>{{if eq .Status “paid”}}</w:t></w:r><w:r><w:t>paid{{end}}</w:t></w:r></w:p>",
+			paragraph:  2,
+			expression: "{{if eq .Status “paid”}}",
+			suggestion: `use straight quotes: {{if eq .Status "paid"}}`,
+		},
+		{
+			name:       "missing dot",
+			body:       `<w:p><w:r><w:t>Dear {{Na</w:t></w:r><w:r><w:t>me}}</w:t></w:r></w:p>`,
+			paragraph:  1,
+			expression: "{{Name}}",
+			suggestion: "fields must start with a dot: {{.Name}}",
+		},
+		{
+			name:       "misspelled function",
+			body:       `<w:p><w:r><w:t>{{hideif .Draft}}draft</w:t></w:r></w:p>`,
+			paragraph:  1,
+			expression: "{{hideif .Draft}}",
+			suggestion: "did you mean hideIf?",
+		},
+		{
+			name:       "unmatched if",
+			body:       `<w:p><w:r><w:t>{{if .Paid}}paid</w:t></w:r></w:p><w:p><w:r><w:t>{{range .Items}}{{.}}{{end}}</w:t></w:r></w:p>`,
+			paragraph:  1,
+			expression: "{{if .Paid}}",
+			suggestion: "add {{end}} at the end of the block",
+		},
+		{
+			name:       "stray end",
+			body:       `<w:p><w:r><w:t>{{.Name}}</w:t></w:r></w:p><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p>`,
+			paragraph:  2,
+			expression: "{{end}}",
+			suggestion: "remove it or add the missing {{if}}, {{range}} or {{with}}",
+		},
+		{
+			name:       "unclosed action",
+			body:       `<w:p><w:r><w:t>{{.Name</w:t></w:r></w:p>`,
+			paragraph:  1,
+			expression: "{{.Name",
+			suggestion: "add }} at the end of the action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ProcessTemplateDocx(buildTestDocx(t, tt.body), map[string]interface{}{})
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("expected TemplateError, have %v", err)
+			}
+			if templateErr.Part != DocumentXml || templateErr.Paragraph != tt.paragraph {
+				t.Errorf("expected %s paragraph %d, have %s paragraph %d", DocumentXml, tt.paragraph, templateErr.Part, templateErr.Paragraph)
+			}
+			if templateErr.Line < 1 {
+				t.Errorf("expected the line of the parser error, have %d", templateErr.Line)
+			}
+			if templateErr.Expression != tt.expression {
+				t.Errorf("expected expression %q, have %q", tt.expression, templateErr.Expression)
+			}
+			if templateErr.Suggestion != tt.suggestion {
+				t.Errorf("expected suggestion %q, have %q", tt.suggestion, templateErr.Suggestion)
+			}
+			if !errors.Is(err, ErrTemplateParse) {
+				t.Errorf("expected ErrTemplateParse, have %v", err)
+			}
+			if templateErr.Err == nil || !strings.Contains(err.Error(), tt.expression) {
+				t.Errorf("expected the parser error to be wrapped and the expression in the message: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// commonFuncs returns the template functions for common formatting, modeled on the Sprig library so template
+// authors can use the names and argument order they know. The value a function works on is always the last
+// argument, which allows pipelines like {{.Name | trim | upper}}.
+//
+//	{{upper .Name}} {{lower .Name}} {{title .Name}}   -> changes the case
+//	{{trim .Name}} {{trimPrefix "Dr. " .Name}}         -> removes white space or a prefix (trimSuffix for a suffix)
+//	{{replace "-" " " .Code}}                          -> replaces all occurrences of the first argument
+//	{{contains "GmbH" .Company}}                       -> reports whether the text contains the first argument
+//	{{hasPrefix "DE" .VatId}} {{hasSuffix ".pdf" .File}}
+//	{{repeat 3 "*"}} {{trunc 20 .Note}} {{abbrev 20 .Note}}   -> abbrev ends truncated text with '...'
+//	{{splitList "," .Tags}}                            -> splits the text into a list
+//	{{join ", " .Items}}                               -> joins the elements of a list
+//	{{add 1 $i}} {{sub .Total .Paid}} {{mul .Price .Quantity}} {{div .Total 3}} {{mod $i 2}}
+//	{{max .A .B}} {{min .A .B}} {{round .Total 2}} {{ceil .Hours}} {{floor .Hours}}
+//	{{default "n/a" .Phone}}                           -> the first argument if the value is empty
+//	{{coalesce .Mobile .Phone "n/a"}}                  -> the first argument which is not empty
+//	{{empty .Items}} {{ternary "paid" "open" .Paid}}
+//	{{date "02 Jan 2006" .DueDate}} {{now | date "2006"}}       -> Go layouts, see localeFuncs for the styles of a locale
+//	{{pluralize .Count "item" "items"}}                -> the singular if the count is one, the plural otherwise
+//	{{currency "EUR" .Total}}                          -> the amount in the locale of the engine, see localeFuncs
+//
+// The arithmetic functions compute with integers if all arguments are integers, so {{div 7 2}} is 3, and with
+// floating point numbers otherwise. Numeric strings are accepted as numbers. Empty are false, zero, nil and empty
+// texts and collections, like for {{if}}. Functions which write longer texts or lists than they are given (repeat,
+// trunc, abbrev and splitList) return ErrLimitExceeded if the result would exceed ParseLimits.MaxPartSize.
+func (e *templateEngine) commonFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"upper": func(s interface{}) string { return strings.ToUpper(templateText(s)) },
+		"lower": func(s interface{}) string { return strings.ToLower(templateText(s)) },
+		"title": func(s interface{}) string { return cases.Title(language.Und, cases.NoLower).String(templateText(s)) },
+		"trim":  func(s interface{}) string { return strings.TrimSpace(templateText(s)) },
+		"trimPrefix": func(prefix string, s interface{}) string {
+			return strings.TrimPrefix(templateText(s), prefix)
+		},
+		"trimSuffix": func(suffix string, s interface{}) string {
+			return strings.TrimSuffix(templateText(s), suffix)
+		},
+		"replace": func(old, new string, s interface{}) string {
+			return strings.ReplaceAll(templateText(s), old, new)
+		},
+		"contains":  func(substr string, s interface{}) bool { return strings.Contains(templateText(s), substr) },
+		"hasPrefix": func(prefix string, s interface{}) bool { return strings.HasPrefix(templateText(s), prefix) },
+		"hasSuffix": func(suffix string, s interface{}) bool { return strings.HasSuffix(templateText(s), suffix) },
+		"repeat": func(count int, s interface{}) (string, error) {
+			if count < 0 {
+				return "", fmt.Errorf("repeat: negative count %d", count)
+			}
+			text := templateText(s)
+			if err := checkTemplateTextSize("repeat", count, len(text)); err != nil {
+				return "", err
+			}
+			return strings.Repeat(text, count), nil
+		},
+		"trunc": func(length int, s interface{}) (string, error) {
+			return truncateTemplateText("trunc", templateText(s), length, "")
+		},
+		"abbrev": func(width int, s interface{}) (string, error) {
+			return truncateTemplateText("abbrev", templateText(s), width, "...")
+		},
+		"splitList": func(sep string, s interface{}) ([]string, error) {
+			text := templateText(s)
+			if err := checkTemplateTextSize("splitList", strings.Count(text, sep)+1, len(sep)+stringHeaderSize); err != nil {
+				return nil, err
+			}
+			return strings.Split(text, sep), nil
+		},
+		"join": joinList,
+
+		"add": func(a, b interface{}, more ...interface{}) (interface{}, error) {
+			return arithmetic("add", append([]interface{}{a, b}, more...), func(x, y int64) (int64, error) { return x + y, nil },
+				func(x, y float64) float64 { return x + y })
+		},
+		"sub": func(a, b interface{}) (interface{}, error) {
+			return arithmetic("sub", []interface{}{a, b}, func(x, y int64) (int64, error) { return x - y, nil },
+				func(x, y float64) float64 { return x - y })
+		},
+		"mul": func(a, b interface{}, more ...interface{}) (interface{}, error) {
+			return arithmetic("mul", append([]interface{}{a, b}, more...), func(x, y int64) (int64, error) { return x * y, nil },
+				func(x, y float64) float64 { return x * y })
+		},
+		"div": func(a, b interface{}) (interface{}, error) {
+			return arithmetic("div", []interface{}{a, b}, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, fmt.Errorf("div: division by zero")
+				}
+				return x / y, nil
+			}, func(x, y float64) float64 { return x / y })
+		},
+		"mod": func(a, b interface{}) (interface{}, error) {
+			return arithmetic("mod", []interface{}{a, b}, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, fmt.Errorf("mod: division by zero")
+				}
+				return x % y, nil
+			}, math.Mod)
+		},
+		"max": func(a interface{}, more ...interface{}) (interface{}, error) {
+			return arithmetic("max", append([]interface{}{a}, more...), func(x, y int64) (int64, error) { return max(x, y), nil },
+				math.Max)
+		},
+		"min": func(a interface{}, more ...interface{}) (interface{}, error) {
+			return arithmetic("min", append([]interface{}{a}, more...), func(x, y int64) (int64, error) { return min(x, y), nil },
+				math.Min)
+		},
+		"round": func(value interface{}, places int) (float64, error) {
+			v, err := templateFloat("round", value)
+			if err != nil {
+				return 0, err
+			}
+			scale := math.Pow(10, float64(places))
+			return math.Round(v*scale) / scale, nil
+		},
+		"ceil": func(value interface{}) (float64, error) {
+			v, err := templateFloat("ceil", value)
+			return math.Ceil(v), err
+		},
+		"floor": func(value interface{}) (float64, error) {
+			v, err := templateFloat("floor", value)
+			return math.Floor(v), err
+		},
+
+		"default": func(fallback interface{}, value ...interface{}) interface{} {
+			if len(value) == 0 || !isTrue(value[0]) {
+				return fallback
+			}
+			return value[0]
+		},
+		"coalesce": func(values ...interface{}) interface{} {
+			for _, value := range values {
+				if isTrue(value) {
+					return value
+				}
+			}
+			return nil
+		},
+		"empty": func(value interface{}) bool { return !isTrue(value) },
+		"ternary": func(whenTrue, whenFalse, cond interface{}) interface{} {
+			if isTrue(cond) {
+				return whenTrue
+			}
+			return whenFalse
+		},
+
+		"now": time.Now,
+
+		"pluralize": func(count interface{}, singular string, plural ...string) (string, error) {
+			n, err := templateFloat("pluralize", count)
+			if err != nil {
+				return "", err
+			}
+			if n == 1 {
+				return singular, nil
+			}
+			if len(plural) > 0 {
+				return plural[0], nil
+			}
+			return singular + "s", nil
+		},
+	}
+}
+
+// templateText returns the text of a value passed to a string function, nil is empty.
+func templateText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// stringHeaderSize is the size of a string value without its bytes, used to estimate the size of lists of texts.
+var stringHeaderSize = int(reflect.TypeOf("").Size())
+
+// checkTemplateTextSize returns ErrLimitExceeded if the function with the given name would write count elements of
+// the given size, which together exceed the maximum size of a part (see ParseLimits.MaxPartSize). The size is checked
+// before any memory is allocated, so templates cannot exhaust the memory with e.g. {{repeat 100000000000 "xy"}}.
+func checkTemplateTextSize(name string, count, size int) error {
+	limit := templateTextLimit()
+	if limit > 0 && size > 0 && int64(count) > limit/int64(size) {
+		return fmt.Errorf("%w: %s would write more than %d bytes", ErrLimitExceeded, name, limit)
+	}
+	return nil
+}
+
+// templateTextLimit returns the maximum size of texts written by template functions in bytes, 0 if unlimited.
+func templateTextLimit() int64 {
+	return currentParseLimits().MaxPartSize
+}
+
+// truncateTemplateText returns the first characters of the text which fit into the width including the ellipsis,
+// or the last characters if the width is negative. Texts which fit are returned as they are.
+// The width must not exceed the maximum size of a part, name is the function used in errors.
+func truncateTemplateText(name, text string, width int, ellipsis string) (string, error) {
+	if limit := templateTextLimit(); limit > 0 && (int64(width) > limit || int64(width) < -limit) {
+		return "", fmt.Errorf("%w: %s width %d exceeds %d", ErrLimitExceeded, name, width, limit)
+	}
+	length := utf8.RuneCountInString(text)
+	if width >= 0 && length <= width || width < 0 && length <= -width {
+		return text, nil
+	}
+	if width < 0 {
+		return string([]rune(text)[length+width:]), nil
+	}
+	return truncateText(text, width, ellipsis), nil
+}
+
+// joinList joins the elements of the list, which may be a slice or an array of any type, with the separator.
+func joinList(sep string, list interface{}) (string, error) {
+	if list == nil {
+		return "", nil
+	}
+	if texts, ok := list.([]string); ok {
+		return strings.Join(texts, sep), nil
+	}
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: %T is not a list", list)
+	}
+	texts := make([]string, value.Len())
+	for i := range texts {
+		texts[i] = templateText(value.Index(i).Interface())
+	}
+	return strings.Join(texts, sep), nil
+}
+
+// templateNumber returns the value of an argument of the arithmetic functions, isInt is false for floating point
+// numbers.
+func templateNumber(name string, value interface{}) (i int64, f float64, isInt bool, err error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), float64(v.Int()), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), float64(v.Uint()), true, nil
+	case reflect.Float32, reflect.Float64:
+		return 0, v.Float(), false, nil
+	case reflect.String:
+		text := strings.TrimSpace(v.String())
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return i, float64(i), true, nil
+		}
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return 0, f, false, nil
+		}
+		return 0, 0, false, fmt.Errorf("%s: %q is not a number", name, v.String())
+	}
+	return 0, 0, false, fmt.Errorf("%s: %T is not a number", name, value)
+}
+
+// templateFloat returns the value of a numeric argument as floating point number.
+func templateFloat(name string, value interface{}) (float64, error) {
+	_, f, _, err := templateNumber(name, value)
+	return f, err
+}
+
+// arithmetic combines the arguments from left to right, with integers if all arguments are integers and with
+// floating point numbers otherwise.
+func arithmetic(name string, args []interface{}, ints func(x, y int64) (int64, error), floats func(x, y float64) float64) (interface{}, error) {
+	intArgs, floatArgs, allInts := make([]int64, len(args)), make([]float64, len(args)), true
+	for n, arg := range args {
+		i, f, isInt, err := templateNumber(name, arg)
+		if err != nil {
+			return nil, err
+		}
+		intArgs[n], floatArgs[n], allInts = i, f, allInts && isInt
+	}
+	if allInts {
+		result := intArgs[0]
+		for _, arg := range intArgs[1:] {
+			var err error
+			if result, err = ints(result, arg); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	result := floatArgs[0]
+	for _, arg := range floatArgs[1:] {
+		result = floats(result, arg)
+	}
+	return result, nil
+}
+
+// templateDate returns the date of a value passed to the date function: a time.Time, a Unix timestamp in seconds or
+// a text in RFC 3339 or ISO 8601 date format. isSet is false for nil and zero dates, which are written as empty text.
+func templateDate(value interface{}) (date time.Time, isSet bool, err error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return v, !v.IsZero(), nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false, nil
+		}
+		return *v, !v.IsZero(), nil
+	case string:
+		if v == "" {
+			return time.Time{}, false, nil
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+			if date, err := time.Parse(layout, v); err == nil {
+				return date, true, nil
+			}
+		}
+		return time.Time{}, false, fmt.Errorf("date: %q is not a date, use RFC 3339 or 2006-01-02", v)
+	}
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(rv.Int(), 0), true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("date: %T is not a date", value)
+}
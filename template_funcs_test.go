@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommonFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"Name":    "  ada lovelace ",
+		"Company": "ACME GmbH",
+		"Note":    "Payment within fourteen days",
+		"Tags":    []string{"urgent", "vip"},
+		"Items":   []interface{}{"Pen", 3, 1.5},
+		"Price":   2.5,
+		"Count":   3,
+		"Phone":   "",
+		"Due":     time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		"Total":   1234.5,
+	}
+	tests := []struct {
+		action   string
+		expected string
+	}{
+		{`{{.Name | trim | title}}`, "Ada Lovelace"},
+		{`{{upper .Company}} {{lower .Company}}`, "ACME GMBH acme gmbh"},
+		{`{{trimSuffix " GmbH" .Company}} {{trimPrefix "AC" .Company}}`, "ACME ME GmbH"},
+		{`{{replace " " "-" .Note}}`, "Payment-within-fourteen-days"},
+		{`{{contains "GmbH" .Company}} {{hasPrefix "AC" .Company}} {{hasSuffix "AG" .Company}}`, "true true false"},
+		{`{{repeat 3 "*"}} {{trunc 7 .Note}} {{trunc -4 .Note}} {{abbrev 10 .Note}} {{abbrev 40 .Note}}`, "*** Payment days Payment... Payment within fourteen days"},
+		{`{{join ", " .Tags}}; {{join "/" .Items}}; {{join "+" (splitList "," "a,b")}}`, "urgent, vip; Pen/3/1.5; a+b"},
+		{`{{add 1 2 3}} {{sub 10 .Count}} {{mul .Price .Count}} {{div 7 2}} {{div 7.0 2}} {{mod 7 3}}`, "6 7 7.5 3 3.5 1"},
+		{`{{max 2 .Count 1}} {{min .Price 4}} {{round 2.345 2}} {{ceil 1.2}} {{floor "1.8"}}`, "3 2.5 2.35 2 1"},
+		{`{{default "n/a" .Phone}} {{default "n/a" .Company}} {{default "n/a" .Missing}}`, "n/a ACME GmbH n/a"},
+		{`{{coalesce .Phone .Missing "none"}} {{empty .Tags}} {{ternary "paid" "open" false}}`, "none false open"},
+		{`{{date "02 Jan 2006" .Due}} {{date "2006" "2024-12-31"}} {{date "2006" .Missing}}`, "14 Mar 2025 2024 "},
+		{`{{.Count}} {{pluralize .Count "item"}}, 1 {{pluralize 1 "child" "children"}}, 0 {{pluralize 0 "child" "children"}}`, "3 items, 1 child, 0 children"},
+		{`{{currency "EUR" .Total}} {{currency "JPY" 1234.6}}`, "€\u00a01,234.50 JP¥\u00a01,235"},
+	}
+	for _, test := range tests {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+test.action+`</w:t></w:r></w:p>`)
+		output, err := ProcessTemplateDocx(input, data)
+		if err != nil {
+			t.Errorf("%s: %v", test.action, err)
+			continue
+		}
+		if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, ">"+test.expected+"<") {
+			t.Errorf("%s: expected %q in document, have %s", test.action, test.expected, document)
+		}
+	}
+}
+
+func TestCommonFuncs_Locale(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{currency "EUR" .Total}}</w:t></w:r></w:p>`)
+
+	output, err := Render(FromBytes(input), map[string]interface{}{"Total": 1234.5}, WithLocale("de-DE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "1.234,50\u00a0€") {
+		t.Errorf("expected the amount in the German format: %s", document)
+	}
+}
+
+func TestCommonFuncs_Errors(t *testing.T) {
+	for _, action := range []string{`{{div 1 0}}`, `{{add 1 "one"}}`, `{{join ", " 42}}`, `{{date "2006" "tomorrow"}}`, `{{currency "XYZW" 1}}`} {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+action+`</w:t></w:r></w:p>`)
+		if _, err := ProcessTemplateDocx(input, nil); err == nil {
+			t.Errorf("%s: expected error", action)
+		}
+	}
+}
+
+func TestCommonFuncs_Limits(t *testing.T) {
+	for _, action := range []string{
+		`{{repeat 100000000000 "xy"}}`,
+		`{{trunc 100000000000000 "xy"}}`,
+		`{{abbrev -100000000000000 "xy"}}`,
+		`{{splitList "" (repeat 20000000 "xy")}}`,
+	} {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+action+`</w:t></w:r></w:p>`)
+		if _, err := ProcessTemplateDocx(input, nil); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: expected ErrLimitExceeded, have %v", action, err)
+		}
+	}
+}
+
+func TestCommonFuncs_Override(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{upper .Name}}</w:t></w:r></w:p>`)
+
+	output, err := Render(FromBytes(input), map[string]interface{}{"Name": "acme"}, WithFuncs(map[string]interface{}{
+		"upper": func(s string) string { return "<" + s + ">" },
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "&lt;acme&gt;") {
+		t.Errorf("expected the function of the caller to replace the builtin one: %s", document)
+	}
+}
@@ -0,0 +1,96 @@
+package docx
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// TableRowTagRegex matches the open and close tags of table rows (<w:tr> and </w:tr>) and captures the slash.
+	TableRowTagRegex = regexp.MustCompile(`<(/?)w:tr(?:\s[^>]*)?>`)
+	// templateActionRegex matches complete template actions inside a prepared template source.
+	templateActionRegex = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+	// rowBlockKeywords are the actions which repeat or hide a table row if they enclose the row.
+	rowBlockKeywords = []string{"range", "if", "with"}
+)
+
+// hoistRowActions moves block actions which enclose the content of a table row out of the row.
+// If the first cell of a row starts with {{range .Items}} (or if/with) and the last cell ends with the matching {{end}},
+// both actions are moved in front of and behind the row element, so the whole row (<w:tr>) is repeated or hidden.
+// Blocks which start and end inside the same paragraph are kept inside the cell, e.g. {{range .Tags}}{{.}} {{end}}.
+// The source must be prepared by prepareTemplateSource, so that every action is part of a single text.
+func hoistRowActions(source string) string {
+	rows := tableRows(source)
+	// rows are processed from the end, hoisting is length preserving so the offsets of all preceding rows stay valid
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] > rows[j][0] })
+	for _, row := range rows {
+		source = source[:row[0]] + hoistRow(source[row[0]:row[1]]) + source[row[1]:]
+	}
+	return source
+}
+
+// tableRows returns the [start, end) offsets of all table rows including nested ones.
+func tableRows(source string) (rows [][2]int) {
+	var open []int
+	for _, match := range TableRowTagRegex.FindAllStringSubmatchIndex(source, -1) {
+		if match[3] == match[2] {
+			open = append(open, match[0])
+			continue
+		}
+		if len(open) == 0 {
+			continue
+		}
+		rows = append(rows, [2]int{open[len(open)-1], match[1]})
+		open = open[:len(open)-1]
+	}
+	return rows
+}
+
+// hoistRow returns the row with the enclosing block actions moved out of it, or the unchanged row.
+func hoistRow(row string) string {
+	openTagEnd := strings.IndexByte(row, '>') + 1
+	closeTagStart := strings.LastIndex(row, "</w:tr>")
+	if openTagEnd <= 0 || closeTagStart < openTagEnd {
+		return row
+	}
+	var cells []childElement
+	for _, child := range childElements(row[openTagEnd:closeTagStart]) {
+		if child.name == "tc" {
+			cells = append(cells, child)
+		}
+	}
+	actions := templateActionRegex.FindAllStringIndex(row, -1)
+	if len(cells) == 0 || len(actions) < 2 {
+		return row
+	}
+
+	first, last := actions[0], actions[len(actions)-1]
+	if !containsString(rowBlockKeywords, actionKeyword(row[first[0]:first[1]])) ||
+		actionKeyword(row[last[0]:last[1]]) != "end" ||
+		first[1] > openTagEnd+cells[0].end ||
+		last[0] < openTagEnd+cells[len(cells)-1].start ||
+		!strings.Contains(row[first[1]:last[0]], "</w:p>") {
+		return row
+	}
+
+	// the end action must close the block of the first action
+	depth := 0
+	for i, action := range actions {
+		switch keyword := actionKeyword(row[action[0]:action[1]]); {
+		case containsString(templateBlockKeywords, keyword):
+			depth++
+		case keyword == "end":
+			depth--
+		}
+		if depth == 0 && i < len(actions)-1 {
+			return row
+		}
+	}
+	if depth != 0 {
+		return row
+	}
+
+	return row[first[0]:first[1]] + row[:first[0]] + row[first[1]:last[0]] + row[last[1]:] + row[last[0]:last[1]]
+}
@@ -0,0 +1,83 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_TableRows(t *testing.T) {
+	row := `<w:tr><w:tc><w:p><w:r><w:t>{{range .Items}}{{.Name}}</w:t></w:r></w:p></w:tc>` +
+		`<w:tc><w:p><w:r><w:t>{{.Price}}{{</w:t></w:r><w:r><w:t>end}}</w:t></w:r></w:p></w:tc></w:tr>`
+	input := buildTestDocx(t, `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Price</w:t></w:r></w:p></w:tc></w:tr>`+
+		row+
+		`<w:tr><w:tc><w:p><w:r><w:t>{{if .Discount}}Discount</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>{{.Discount}}{{end}}</w:t></w:r></w:p></w:tc></w:tr>`+
+		`</w:tbl>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Items": []map[string]string{
+			{"Name": "Apple", "Price": "1.00"},
+			{"Name": "Pear", "Price": "2.00"},
+		},
+		"Discount": "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	if n := strings.Count(document, "<w:tr>"); n != 3 {
+		t.Errorf("expected the header and two item rows, have %d rows: %s", n, document)
+	}
+	for _, expected := range []string{
+		`<w:t xml:space="preserve">Apple</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t xml:space="preserve">1.00</w:t></w:r><w:r><w:t></w:t></w:r></w:p></w:tc></w:tr>`,
+		`<w:t xml:space="preserve">Pear</w:t>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "Discount") {
+		t.Errorf("expected the discount row to be removed: %s", document)
+	}
+}
+
+func TestHoistRowActions(t *testing.T) {
+	tests := []struct {
+		name, source, expected string
+	}{
+		{
+			name:     "range enclosing the row",
+			source:   `<w:tbl><w:tr w:rsidR="1"><w:tc><w:p><w:t>{{range .Items}}{{.}}</w:t></w:p></w:tc><w:tc><w:p><w:t>{{end}}</w:t></w:p></w:tc></w:tr></w:tbl>`,
+			expected: `<w:tbl>{{range .Items}}<w:tr w:rsidR="1"><w:tc><w:p><w:t>{{.}}</w:t></w:p></w:tc><w:tc><w:p><w:t></w:t></w:p></w:tc></w:tr>{{end}}</w:tbl>`,
+		},
+		{
+			name:     "block inside a single paragraph",
+			source:   `<w:tr><w:tc><w:p><w:t>{{range .Items}}{{.}}{{end}}</w:t></w:p></w:tc></w:tr>`,
+			expected: `<w:tr><w:tc><w:p><w:t>{{range .Items}}{{.}}{{end}}</w:t></w:p></w:tc></w:tr>`,
+		},
+		{
+			name:     "single column",
+			source:   `<w:tr><w:tc><w:p><w:t>{{range .Items}}{{.}}</w:t></w:p><w:p><w:t>{{end}}</w:t></w:p></w:tc></w:tr>`,
+			expected: `{{range .Items}}<w:tr><w:tc><w:p><w:t>{{.}}</w:t></w:p><w:p><w:t></w:t></w:p></w:tc></w:tr>{{end}}`,
+		},
+		{
+			name:     "end closing an inner block",
+			source:   `<w:tr><w:tc><w:p><w:t>{{if .A}}a{{end}}</w:t></w:p></w:tc><w:tc><w:p><w:t>{{if .B}}b{{end}}</w:t></w:p></w:tc></w:tr>`,
+			expected: `<w:tr><w:tc><w:p><w:t>{{if .A}}a{{end}}</w:t></w:p></w:tc><w:tc><w:p><w:t>{{if .B}}b{{end}}</w:t></w:p></w:tc></w:tr>`,
+		},
+		{
+			name: "nested table",
+			source: `<w:tr><w:tc><w:p><w:t>{{with .Order}}</w:t></w:p><w:tbl><w:tr><w:tc><w:p><w:t>{{range .Items}}{{.}}{{end}}</w:t></w:p></w:tc></w:tr>` +
+				`<w:tr><w:tc><w:p><w:t>{{range .Notes}}{{.}}</w:t></w:p></w:tc><w:tc><w:p><w:t>{{end}}</w:t></w:p></w:tc></w:tr></w:tbl></w:tc><w:tc><w:p><w:t>{{end}}</w:t></w:p></w:tc></w:tr>`,
+			expected: `{{with .Order}}<w:tr><w:tc><w:p><w:t></w:t></w:p><w:tbl><w:tr><w:tc><w:p><w:t>{{range .Items}}{{.}}{{end}}</w:t></w:p></w:tc></w:tr>` +
+				`{{range .Notes}}<w:tr><w:tc><w:p><w:t>{{.}}</w:t></w:p></w:tc><w:tc><w:p><w:t></w:t></w:p></w:tc></w:tr>{{end}}</w:tbl></w:tc><w:tc><w:p><w:t></w:t></w:p></w:tc></w:tr>{{end}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := hoistRowActions(tt.source); actual != tt.expected {
+				t.Errorf("unexpected source\nhave %s\nwant %s", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package docx
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// styleFuncName is the keyword of the style directive, e.g. '{{style bold=.IsOverdue}}amount{{end}}'.
+	styleFuncName = "style"
+	// styleBeginFuncName and styleEndFuncName are the functions which the style directive and its {{end}} are
+	// rewritten to before parsing.
+	styleBeginFuncName = "_docxStyleBegin"
+	styleEndFuncName   = "_docxStyleEnd"
+
+	// styleEndMarker is the marker of the end of a style directive, see runStyles.apply.
+	styleEndMarker = "styleEnd"
+)
+
+var (
+	// styleActionRegex matches the style directive and captures the trim markers and the properties.
+	styleActionRegex = regexp.MustCompile(`(?s)^\{\{(-\s)?\s*style(?:\s+(.*?))?\s*(\s-)?\}\}$`)
+	// stylePropertyNameRegex matches the name of a property of the style directive.
+	stylePropertyNameRegex = regexp.MustCompile(`^[A-Za-z]+$`)
+	// styleTokenRegex matches the open tags of runs with their properties, the close tags of runs and the markers
+	// written by the style directive, capturing the open tag, the run properties and the index of the style.
+	styleTokenRegex = regexp.MustCompile(`(?s)(<w:r(?:\s[^>]*[^/>])?>)(<w:rPr>.*?</w:rPr>|<w:rPr/>)?|</w:r>|` +
+		`\x{E000}style(?:(\d+)|End)\x{E001}`)
+	// styleColorRegex matches the colors of the style directive, hexadecimal RGB values with an optional '#'.
+	styleColorRegex = regexp.MustCompile(`^#?([0-9A-Fa-f]{6})$`)
+
+	// underlineStyles are the values of underline besides true, see the w:u element.
+	underlineStyles = []string{"single", "words", "double", "thick", "dotted", "dash", "dotDash", "dotDotDash", "wave"}
+	// highlightColors are the values of highlight, see the w:highlight element.
+	highlightColors = []string{
+		"yellow", "green", "cyan", "magenta", "blue", "red", "darkBlue", "darkCyan", "darkGreen", "darkMagenta",
+		"darkRed", "darkYellow", "darkGray", "lightGray", "black", "white",
+	}
+)
+
+// runStyleProperty is a property of the style directive.
+type runStyleProperty struct {
+	// element is the local name of the run property, e.g. 'b' for bold.
+	element string
+	// xml returns the run property for the value, an empty string leaves the formatting unchanged.
+	xml func(value interface{}) (string, error)
+}
+
+// runStyleProperties are the properties of the style directive by name.
+var runStyleProperties = map[string]runStyleProperty{
+	"bold":      {"b", toggleStyleProperty("<w:b/>")},
+	"italic":    {"i", toggleStyleProperty("<w:i/>")},
+	"strike":    {"strike", toggleStyleProperty("<w:strike/>")},
+	"caps":      {"caps", toggleStyleProperty("<w:caps/>")},
+	"underline": {"u", underlineStyleProperty},
+	"color":     {"color", colorStyleProperty},
+	"highlight": {"highlight", highlightStyleProperty},
+	"size":      {"sz", sizeStyleProperty},
+	"font":      {"rFonts", fontStyleProperty},
+}
+
+// toggleStyleProperty returns the property which sets the element if the value is true in the sense of {{if}}.
+func toggleStyleProperty(element string) func(value interface{}) (string, error) {
+	return func(value interface{}) (string, error) {
+		if isTrue(value) {
+			return element, nil
+		}
+		return "", nil
+	}
+}
+
+// underlineStyleProperty underlines the text if the value is true or the name of an underline style, e.g. 'double'.
+func underlineStyleProperty(value interface{}) (string, error) {
+	if name, isString := value.(string); isString && name != "" {
+		if !containsString(underlineStyles, name) {
+			return "", fmt.Errorf("unknown underline %q, expected one of %s", name, strings.Join(underlineStyles, ", "))
+		}
+		return `<w:u w:val="` + name + `"/>`, nil
+	}
+	if isTrue(value) {
+		return `<w:u w:val="single"/>`, nil
+	}
+	return "", nil
+}
+
+// colorStyleProperty colors the text, the value is a hexadecimal RGB value like 'C00000' or '#C00000'.
+func colorStyleProperty(value interface{}) (string, error) {
+	color := fmt.Sprint(value)
+	if value == nil || color == "" {
+		return "", nil
+	}
+	match := styleColorRegex.FindStringSubmatch(color)
+	if match == nil {
+		return "", fmt.Errorf("invalid color %q, expected a hexadecimal RGB value like C00000", color)
+	}
+	return `<w:color w:val="` + strings.ToUpper(match[1]) + `"/>`, nil
+}
+
+// highlightStyleProperty highlights the text with one of the highlight colors, e.g. 'yellow'.
+func highlightStyleProperty(value interface{}) (string, error) {
+	color := fmt.Sprint(value)
+	if value == nil || color == "" {
+		return "", nil
+	}
+	if !containsString(highlightColors, color) {
+		return "", fmt.Errorf("unknown highlight color %q, expected one of %s", color, strings.Join(highlightColors, ", "))
+	}
+	return `<w:highlight w:val="` + color + `"/>`, nil
+}
+
+// sizeStyleProperty sets the font size, the value is a number of points.
+func sizeStyleProperty(value interface{}) (string, error) {
+	if value == nil || value == "" {
+		return "", nil
+	}
+	points, err := toFloat(value)
+	if err != nil || points < 0 || math.IsInf(points, 0) || math.IsNaN(points) {
+		return "", fmt.Errorf("invalid size %v, expected a number of points", value)
+	}
+	if points == 0 {
+		return "", nil
+	}
+	// the size is given in half-points
+	return fmt.Sprintf(`<w:sz w:val="%d"/>`, int(math.Round(points*2))), nil
+}
+
+// fontStyleProperty sets the font of the text, the value is the name of the font.
+func fontStyleProperty(value interface{}) (string, error) {
+	font := fmt.Sprint(value)
+	if value == nil || font == "" {
+		return "", nil
+	}
+	font = html.EscapeString(font)
+	return `<w:rFonts w:ascii="` + font + `" w:hAnsi="` + font + `" w:cs="` + font + `"/>`, nil
+}
+
+// runStyles collects the run properties of the style directives of a single rendering.
+//
+// The style directive formats the text up to its {{end}} with run properties computed from the data, so emphasis
+// logic can live in the template instead of the caller:
+//
+//	{{style bold=.IsOverdue color=(statusColor .Status)}}{{.Amount}}{{end}}
+//
+// The properties are bold, italic, strike, caps, underline (true or a style like "double"), color (RGB like
+// "C00000"), highlight (e.g. "yellow"), size (in points) and font. A property without a value is true, e.g.
+// {{style bold}}. Properties which are false, empty or zero leave the formatting of the text unchanged.
+// The styled text may span multiple runs and paragraphs and contain other actions, its runs keep their own
+// formatting and get the properties of the directive in addition.
+type runStyles struct {
+	// properties are the run properties of every executed directive, referenced by the markers.
+	properties [][]runStyleValue
+}
+
+// runStyleValue is a run property set by a style directive.
+type runStyleValue struct {
+	element, xml string
+}
+
+// newRunStyles returns an empty runStyles.
+func newRunStyles() *runStyles {
+	return &runStyles{}
+}
+
+// funcs returns the template functions which the style directive is rewritten to, see rewriteStyleActions.
+func (s *runStyles) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		styleBeginFuncName: s.begin,
+		styleEndFuncName:   func() string { return visibilityMarker(styleEndMarker) },
+	}
+}
+
+// begin computes the run properties of a style directive from pairs of property names and values and returns the
+// marker of the start of the styled text.
+func (s *runStyles) begin(args ...interface{}) (string, error) {
+	var values []runStyleValue
+	for i := 0; i+1 < len(args); i += 2 {
+		name := fmt.Sprint(args[i])
+		property, known := runStyleProperties[name]
+		if !known {
+			return "", fmt.Errorf("unknown style property %s", name)
+		}
+		xml, err := property.xml(args[i+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid style property %s: %w", name, err)
+		}
+		if xml != "" {
+			values = append(values, runStyleValue{element: property.element, xml: xml})
+		}
+	}
+	s.properties = append(s.properties, values)
+	return visibilityMarker(fmt.Sprintf("style%d", len(s.properties)-1)), nil
+}
+
+// runProperties returns the run properties with the properties of the active styles applied, innermost last.
+func (s *runStyles) runProperties(properties string, active []int) string {
+	for _, style := range active {
+		for _, value := range s.properties[style] {
+			properties = setRunProperty(properties, value.element, value.xml)
+		}
+	}
+	return properties
+}
+
+// apply formats the runs between the markers of the style directives and removes the markers. Runs which contain a
+// marker are split at the marker, so only the text following it is formatted.
+func (s *runStyles) apply(data []byte) ([]byte, error) {
+	if !strings.Contains(string(data), "\uE000style") {
+		return data, nil
+	}
+
+	type openRun struct {
+		tag, properties string
+	}
+	var runs []openRun
+	var active []int
+	var out strings.Builder
+	last := 0
+	for _, match := range styleTokenRegex.FindAllSubmatchIndex(data, -1) {
+		out.Write(data[last:match[0]])
+		last = match[1]
+		switch {
+		case match[2] >= 0:
+			run := openRun{tag: string(data[match[2]:match[3]])}
+			if match[4] >= 0 {
+				run.properties = string(data[match[4]:match[5]])
+			}
+			runs = append(runs, run)
+			out.WriteString(run.tag + s.runProperties(run.properties, active))
+			continue
+		case string(data[match[0]:match[1]]) == "</w:r>":
+			if len(runs) > 0 {
+				runs = runs[:len(runs)-1]
+			}
+			out.Write(data[match[0]:match[1]])
+			continue
+		case match[6] >= 0:
+			style, err := strconv.Atoi(string(data[match[6]:match[7]]))
+			if err != nil || style >= len(s.properties) {
+				return nil, fmt.Errorf("invalid style marker %d", style)
+			}
+			active = append(active, style)
+		case len(active) == 0:
+			return nil, fmt.Errorf("{{end}} of style without {{style}}")
+		default:
+			active = active[:len(active)-1]
+		}
+
+		// the marker is part of the text of the run, which is split so the following text gets the active styles
+		if len(runs) > 0 {
+			run := runs[len(runs)-1]
+			out.WriteString(`</w:t></w:r>` + run.tag + s.runProperties(run.properties, active) + `<w:t xml:space="preserve">`)
+		}
+	}
+	out.Write(data[last:])
+	if len(active) > 0 {
+		return nil, fmt.Errorf("{{style}} without {{end}}")
+	}
+	return []byte(out.String()), nil
+}
+
+// rewriteStyleActions rewrites the style directives of the prepared template source and their {{end}} into calls of
+// the functions of runStyles, since text/template supports neither custom blocks nor named arguments:
+// {{style bold=.IsOverdue}} becomes {{_docxStyleBegin "bold" (.IsOverdue)}} and its {{end}} becomes {{_docxStyleEnd}}.
+func rewriteStyleActions(source string) (string, error) {
+	var out strings.Builder
+	var open []bool
+	last := 0
+	for _, action := range templateActionRegex.FindAllStringIndex(source, -1) {
+		text := source[action[0]:action[1]]
+		switch keyword := actionKeyword(text); {
+		case keyword == styleFuncName:
+			rewritten, err := rewriteStyleAction(text)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(source[last:action[0]] + rewritten)
+			last = action[1]
+			open = append(open, true)
+		case containsString(templateBlockKeywords, keyword):
+			open = append(open, false)
+		case keyword == "end" && len(open) > 0:
+			if open[len(open)-1] {
+				out.WriteString(source[last:action[0]] + strings.Replace(text, "end", styleEndFuncName, 1))
+				last = action[1]
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	for _, isStyle := range open {
+		if isStyle {
+			return "", fmt.Errorf("{{style}} is never closed")
+		}
+	}
+	out.WriteString(source[last:])
+	return out.String(), nil
+}
+
+// rewriteStyleAction rewrites a single style directive into the call of the function which begins the style.
+func rewriteStyleAction(action string) (string, error) {
+	match := styleActionRegex.FindStringSubmatch(action)
+	if match == nil {
+		return "", fmt.Errorf("invalid style directive %s", action)
+	}
+	properties, err := splitStyleProperties(match[2])
+	if err != nil {
+		return "", err
+	}
+
+	var call strings.Builder
+	call.WriteString(TemplateOpenDelimiter + match[1] + styleBeginFuncName)
+	for _, property := range properties {
+		name, value, hasValue := strings.Cut(property, "=")
+		if !stylePropertyNameRegex.MatchString(name) || hasValue && value == "" {
+			return "", fmt.Errorf("invalid style property %s, expected name=value", property)
+		}
+		if _, known := runStyleProperties[name]; !known {
+			return "", fmt.Errorf("unknown style property %s", name)
+		}
+		if !hasValue {
+			value = "true"
+		}
+		fmt.Fprintf(&call, " %q (%s)", name, value)
+	}
+	call.WriteString(match[3] + TemplateCloseDelimiter)
+	return call.String(), nil
+}
+
+// splitStyleProperties splits the properties of a style directive at whitespace outside of parentheses and quotes.
+func splitStyleProperties(properties string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range properties {
+		switch {
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && quote != '`':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unexpected ) in style properties %s", properties)
+			}
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteRune(r)
+	}
+	if quote != 0 || depth > 0 {
+		return nil, fmt.Errorf("unterminated quote or parenthesis in style properties %s", properties)
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,89 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestProcessTemplateDocx_Style(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t>Total: {{style bold=.IsOverdue color=(statusColor .Status)}}{{.Amount}}{{end}} due</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{style italic underline="double" size=14}}start </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>bold{{end}} plain</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{range .Items}}{{style bold=.Important}}{{.Name}}{{end}};{{end}}</w:t></w:r></w:p>`)
+	funcs := template.FuncMap{
+		"statusColor": func(status string) string {
+			if status == "late" {
+				return "#c00000"
+			}
+			return ""
+		},
+	}
+	output, err := ProcessTemplateDocxWithFuncs(input, map[string]interface{}{
+		"IsOverdue": true,
+		"Status":    "late",
+		"Amount":    "12.50",
+		"Items": []map[string]interface{}{
+			{"Name": "first", "Important": true},
+			{"Name": "second", "Important": false},
+		},
+	}, funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">Total: </w:t></w:r>` +
+			`<w:r><w:rPr><w:b/><w:color w:val="C00000"/><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve">12.50</w:t></w:r>` +
+			`<w:r><w:rPr><w:sz w:val="20"/></w:rPr><w:t xml:space="preserve"> due</w:t></w:r>`,
+		`<w:r><w:rPr><w:i/><w:sz w:val="28"/><w:u w:val="double"/></w:rPr><w:t xml:space="preserve">start </w:t></w:r>`,
+		`<w:r><w:rPr><w:b/><w:i/><w:sz w:val="28"/><w:u w:val="double"/></w:rPr><w:t xml:space="preserve">bold</w:t></w:r>` +
+			`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> plain</w:t></w:r>`,
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">first</w:t></w:r><w:r><w:t xml:space="preserve">;</w:t></w:r>` +
+			`<w:r><w:t xml:space="preserve">second</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "") {
+		t.Errorf("expected all markers to be removed: %s", document)
+	}
+}
+
+func TestProcessTemplateDocx_StyleErrors(t *testing.T) {
+	for _, body := range []string{
+		`{{style bold=.X}}text`,
+		`{{style weight=.X}}text{{end}}`,
+		`{{style bold = .X}}text{{end}}`,
+		`{{style color=(.X}}text{{end}}`,
+		`{{style color="red"}}text{{end}}`,
+		`{{style size="large"}}text{{end}}`,
+	} {
+		input := buildTestDocx(t, `<w:p><w:r><w:t>`+body+`</w:t></w:r></w:p>`)
+		if _, err := ProcessTemplateDocx(input, nil); err == nil {
+			t.Errorf("expected an error for %s", body)
+		}
+	}
+
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{style bold=.X}}text</w:t></w:r></w:p>`)
+	_, err := ProcessTemplateDocx(input, nil)
+	var templateErr *TemplateError
+	if !errors.As(err, &templateErr) || templateErr.Expression != "{{style bold=.X}}" || templateErr.Paragraph != 1 {
+		t.Errorf("expected a template error locating the unclosed directive, got %v", err)
+	}
+}
+
+func TestProcessTemplateDocxWithFuncs_StyleReplaced(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{style "x"}}</w:t></w:r></w:p>`)
+	output, err := ProcessTemplateDocxWithFuncs(input, nil, template.FuncMap{
+		"style": func(name string) string { return "custom " + name },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "custom x") {
+		t.Errorf("expected the custom function to be called: %s", document)
+	}
+}
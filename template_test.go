@@ -0,0 +1,126 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+const (
+	testDocumentOpen  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>`
+	testDocumentClose = `</w:body></w:document>`
+)
+
+// buildTestDocx assembles a minimal DOCX archive given the inner body XML of the document and optional additional parts.
+// Additional parts with the name of a default part replace the default part.
+func buildTestDocx(t testing.TB, body string, parts ...string) []byte {
+	files := []string{
+		"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`,
+		"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`,
+		DocumentXml, testDocumentOpen + body + testDocumentClose,
+		"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`,
+	}
+	for i := 0; i < len(parts); i += 2 {
+		replaced := false
+		for j := 0; j < len(files); j += 2 {
+			if files[j] == parts[i] {
+				files[j+1], replaced = parts[i+1], true
+			}
+		}
+		if !replaced {
+			files = append(files, parts[i], parts[i+1])
+		}
+	}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		fw, err := zipWriter.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// readTestPart returns the given part of the DOCX archive.
+func readTestPart(t testing.TB, docx []byte, name string) string {
+	parts, err := readArchiveParts(docx, func(n string) bool { return n == name })
+	if err != nil {
+		t.Fatal(err)
+	}
+	part, ok := parts[name]
+	if !ok {
+		t.Fatalf("part %s not found", name)
+	}
+	return string(part)
+}
+
+func TestProcessTemplateDocx(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>Dear {{.</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>Name}},</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{if .Paid}}paid{{else}}open{{end}} &amp; {{.Note}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Name": "Jane <Doe>",
+		"Paid": true,
+		"Note": "a & b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{"Dear Jane &lt;Doe&gt;", "paid &amp; a &amp; b"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %q in document, have %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "{{") {
+		t.Errorf("template actions were not rendered: %s", document)
+	}
+}
+
+func TestProcessTemplateDocxWithFuncs(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{upper .Name}}: {{formatCurrency .Total}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocxWithFuncs(input, map[string]interface{}{"Name": "acme & co", "Total": 42.5}, template.FuncMap{
+		"upper":          strings.ToUpper,
+		"formatCurrency": func(v float64) string { return fmt.Sprintf("%.2f EUR", v) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "ACME &amp; CO: 42.50 EUR") {
+		t.Errorf("expected the functions to be applied and escaped: %s", document)
+	}
+
+	if _, err := ProcessTemplateDocxWithFuncs(input, nil, template.FuncMap{"upper": "no function"}); err == nil {
+		t.Error("expected error for invalid function")
+	}
+}
+
+func TestProcessTemplateDocx_ParseError(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{if .Paid}}paid</w:t></w:r></w:p>`)
+	if _, err := ProcessTemplateDocx(input, nil); err == nil {
+		t.Error("expected error for unclosed if")
+	}
+}
+
+func TestPrepareTemplateSource(t *testing.T) {
+	part := []byte(`<w:r><w:t>a{</w:t></w:r><w:r><w:t>{.Foo &quot;x&quot;}</w:t></w:r><w:r><w:t>}b</w:t></w:r>`)
+	expected := `<w:r><w:t xml:space="preserve">a{{.Foo "x"}}</w:t></w:r><w:r><w:t></w:t></w:r><w:r><w:t>b</w:t></w:r>`
+
+	if source := prepareTemplateSource(part); source != expected {
+		t.Errorf("unexpected template source, want=%s, have=%s", expected, source)
+	}
+}
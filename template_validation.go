@@ -0,0 +1,430 @@
+package docx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchema is returned by ValidateTemplate if the schema of the data cannot be read.
+var ErrInvalidSchema = errors.New("invalid data schema")
+
+// TemplateIssueKind is the kind of a problem found by ValidateTemplate.
+type TemplateIssueKind string
+
+const (
+	// TemplateIssueSyntax is a template action which cannot be parsed.
+	TemplateIssueSyntax TemplateIssueKind = "syntax"
+	// TemplateIssueUnknownField is a field which the template accesses but the schema does not define.
+	TemplateIssueUnknownField TemplateIssueKind = "unknown-field"
+	// TemplateIssueTypeMismatch is a field which the template uses in a way its type does not allow, e.g. ranging
+	// over a string.
+	TemplateIssueTypeMismatch TemplateIssueKind = "type-mismatch"
+)
+
+// TemplateIssue is a problem of a template found by ValidateTemplate.
+type TemplateIssue struct {
+	Kind TemplateIssueKind
+	// Field is the path of the field, e.g. 'Customer.Email'. Fields of list items are given without index, e.g.
+	// 'Items.SKU'. It is empty for syntax errors.
+	Field   string
+	Message string
+	// Suggestion describes how the problem can probably be fixed, it is empty if no suggestion is known.
+	Suggestion string
+	// Err is the *TemplateError of syntax errors, which locates the offending action.
+	Err error
+}
+
+// String returns a readable description of the issue.
+func (i TemplateIssue) String() string {
+	msg := fmt.Sprintf("%s: %s", i.Kind, i.Message)
+	if i.Suggestion != "" {
+		msg += fmt.Sprintf(" (%s)", i.Suggestion)
+	}
+	return msg
+}
+
+// schemaKind is the kind of a value described by a schema.
+type schemaKind string
+
+const (
+	schemaAny     schemaKind = "value"
+	schemaObject  schemaKind = "object"
+	schemaArray   schemaKind = "list"
+	schemaString  schemaKind = "string"
+	schemaNumber  schemaKind = "number"
+	schemaInteger schemaKind = "integer"
+	schemaBoolean schemaKind = "boolean"
+)
+
+// schemaType is the type of a value of the template data described by a schema.
+type schemaType struct {
+	kind schemaKind
+	// fields are the known fields of objects, e.g. the fields and methods of structs
+	fields map[string]*schemaType
+	// additional is the type of all other fields of objects, e.g. the values of maps, nil if there are none
+	additional *schemaType
+	// element is the type of the items of lists
+	element *schemaType
+	// text is true if objects are written as text, e.g. time.Time and values implementing fmt.Stringer
+	text bool
+}
+
+// ValidateTemplate checks the template actions of the template given by input (see ProcessTemplateDocx) against the
+// schema of its data before any document is generated. It reports actions which cannot be parsed, fields which the
+// schema does not define (with the most similar defined field as suggestion) and fields which are used in a way
+// their type does not allow, e.g. fields of strings, ranging over numbers or writing objects and lists as text.
+// Issues are ordered by field, a syntax error stops the validation of the fields.
+//
+// The schema is one of:
+//   - a Go value or reflect.Type whose type describes the data, e.g. Invoice{} or (*Invoice)(nil). Exported fields
+//     and methods of structs are fields, maps with string keys allow all fields.
+//   - a JSON Schema as []byte, json.RawMessage, string or decoded map[string]interface{}. Objects only allow the
+//     fields listed in properties unless additionalProperties is true or a schema, local references ($ref to
+//     #/$defs/... or #/definitions/...) are resolved.
+//
+// Fields which are only passed to functions are not checked, as the types of custom functions are unknown.
+//
+// Example:
+//
+//	issues, err := docx.ValidateTemplate(templateBytes, Invoice{})
+//	for _, issue := range issues {
+//	    log.Printf("%s: %s", issue.Field, issue)
+//	}
+func ValidateTemplate(input []byte, schema interface{}) ([]TemplateIssue, error) {
+	root, err := readSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	shape, err := inferTemplateData(input)
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
+		// the suggestion is part of the issue, not of its message
+		location := *templateErr
+		location.Suggestion = ""
+		return []TemplateIssue{{
+			Kind:       TemplateIssueSyntax,
+			Message:    location.Error(),
+			Suggestion: templateErr.Suggestion,
+			Err:        templateErr,
+		}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []TemplateIssue
+	validateTemplateField(shape, root, "", &issues)
+	return issues, nil
+}
+
+// validateTemplateField adds the issues of the field with the given shape and path which has the given type.
+func validateTemplateField(shape *sampleField, typ *schemaType, path string, issues *[]TemplateIssue) {
+	if typ == nil || typ.kind == schemaAny {
+		return
+	}
+	mismatch := func(message, suggestion string) {
+		*issues = append(*issues, TemplateIssue{Kind: TemplateIssueTypeMismatch, Field: path, Message: message, Suggestion: suggestion})
+	}
+	describe := func() string {
+		if path == "" {
+			return fmt.Sprintf("the data is %s %s", article(typ.kind), typ.kind)
+		}
+		return fmt.Sprintf("%s is %s %s", path, article(typ.kind), typ.kind)
+	}
+
+	if shape.output && !shape.argument && (typ.kind == schemaObject || typ.kind == schemaArray) && !typ.text {
+		mismatch(describe()+" and cannot be written as text", "write its fields or pass it to a function")
+	}
+	if shape.element != nil {
+		switch {
+		case typ.kind == schemaArray:
+			validateTemplateField(shape.element, typ.element, path, issues)
+		case typ.kind == schemaObject && typ.additional != nil:
+			validateTemplateField(shape.element, typ.additional, path, issues)
+		case typ.kind != schemaInteger:
+			mismatch(describe()+" and cannot be ranged over", "")
+		}
+	}
+
+	if len(shape.fields) > 0 {
+		names := make([]string, 0, len(shape.fields))
+		for name := range shape.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		switch typ.kind {
+		case schemaObject:
+			known := make([]string, 0, len(typ.fields))
+			for name := range typ.fields {
+				known = append(known, name)
+			}
+			for _, name := range names {
+				child := name
+				if path != "" {
+					child = path + "." + name
+				}
+				fieldType, exists := typ.fields[name]
+				if !exists && typ.additional == nil {
+					issue := TemplateIssue{Kind: TemplateIssueUnknownField, Field: child, Message: fmt.Sprintf("field %s is not defined", child)}
+					if similar := similarName(name, known); similar != "" {
+						issue.Suggestion = fmt.Sprintf("did you mean %s?", similar)
+					}
+					*issues = append(*issues, issue)
+					continue
+				}
+				if !exists {
+					fieldType = typ.additional
+				}
+				validateTemplateField(shape.fields[name], fieldType, child, issues)
+			}
+		case schemaArray:
+			mismatch(fmt.Sprintf("%s and has no field %s", describe(), names[0]),
+				"access the fields of the items inside {{range}}")
+		default:
+			mismatch(fmt.Sprintf("%s and has no field %s", describe(), names[0]), "")
+		}
+	}
+}
+
+// article returns the indefinite article of the kind.
+func article(kind schemaKind) string {
+	if strings.ContainsAny(string(kind[:1]), "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// readSchema returns the type of the data described by the schema, see ValidateTemplate.
+func readSchema(schema interface{}) (*schemaType, error) {
+	var document interface{}
+	switch s := schema.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: schema is nil", ErrInvalidSchema)
+	case reflect.Type:
+		return goSchemaType(s, make(map[reflect.Type]*schemaType)), nil
+	case json.RawMessage:
+		document = []byte(s)
+	case []byte, string, map[string]interface{}:
+		document = s
+	default:
+		return goSchemaType(reflect.TypeOf(schema), make(map[reflect.Type]*schemaType)), nil
+	}
+
+	if text, isText := document.(string); isText {
+		document = []byte(text)
+	}
+	if raw, isRaw := document.([]byte); isRaw {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+		}
+		document = decoded
+	}
+	root := document.(map[string]interface{})
+	reader := &jsonSchemaReader{root: root, refs: make(map[string]*schemaType)}
+	return reader.read(root)
+}
+
+// textTypes are the types which implement interfaces to be written as text.
+var textTypes = []reflect.Type{
+	reflect.TypeOf((*fmt.Stringer)(nil)).Elem(),
+	reflect.TypeOf((*error)(nil)).Elem(),
+	reflect.TypeOf((*inlineValue)(nil)).Elem(),
+	reflect.TypeOf((*blockValue)(nil)).Elem(),
+}
+
+// goSchemaType returns the type of the data described by the Go type, known are the types which were converted
+// already to support recursive types.
+func goSchemaType(t reflect.Type, known map[reflect.Type]*schemaType) *schemaType {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if typ, exists := known[t]; exists {
+		return typ
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &schemaType{kind: schemaString}
+	case reflect.Bool:
+		return &schemaType{kind: schemaBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &schemaType{kind: schemaInteger}
+	case reflect.Float32, reflect.Float64:
+		return &schemaType{kind: schemaNumber}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schemaType{kind: schemaString}
+		}
+		typ := &schemaType{kind: schemaArray}
+		known[t] = typ
+		typ.element = goSchemaType(t.Elem(), known)
+		return typ
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return &schemaType{kind: schemaAny}
+		}
+		typ := &schemaType{kind: schemaObject}
+		known[t] = typ
+		typ.additional = goSchemaType(t.Elem(), known)
+		return typ
+	case reflect.Struct:
+		typ := &schemaType{kind: schemaObject, fields: make(map[string]*schemaType)}
+		known[t] = typ
+		for _, field := range reflect.VisibleFields(t) {
+			if field.IsExported() {
+				typ.fields[field.Name] = goSchemaType(field.Type, known)
+			}
+		}
+		// methods of values and pointers can be called like fields, e.g. {{.Total}}
+		methods := reflect.PointerTo(t)
+		for i := 0; i < methods.NumMethod(); i++ {
+			method := methods.Method(i)
+			if method.Type.NumOut() == 0 {
+				continue
+			}
+			typ.fields[method.Name] = goSchemaType(method.Type.Out(0), known)
+		}
+		for _, textType := range textTypes {
+			typ.text = typ.text || methods.Implements(textType)
+		}
+		typ.text = typ.text || t == reflect.TypeOf(time.Time{})
+		return typ
+	}
+	return &schemaType{kind: schemaAny}
+}
+
+// jsonSchemaReader reads the types of a decoded JSON Schema.
+type jsonSchemaReader struct {
+	root map[string]interface{}
+	// refs are the types of the references which were read already, to support recursive schemas
+	refs map[string]*schemaType
+}
+
+// read returns the type described by the schema.
+func (r *jsonSchemaReader) read(schema interface{}) (*schemaType, error) {
+	definition, isObject := schema.(map[string]interface{})
+	if !isObject {
+		// true and false are valid schemas which allow any value or none
+		return &schemaType{kind: schemaAny}, nil
+	}
+
+	if ref, isRef := definition["$ref"].(string); isRef {
+		return r.reference(ref)
+	}
+
+	kind := schemaAny
+	switch types := definition["type"].(type) {
+	case string:
+		kind = jsonSchemaKind(types)
+	case []interface{}:
+		// nullable types are given as ["string", "null"], all other unions allow any value
+		var named []string
+		for _, t := range types {
+			if name, isName := t.(string); isName && name != "null" {
+				named = append(named, name)
+			}
+		}
+		if len(named) == 1 {
+			kind = jsonSchemaKind(named[0])
+		}
+	case nil:
+		if _, hasProperties := definition["properties"]; hasProperties {
+			kind = schemaObject
+		} else if _, hasItems := definition["items"]; hasItems {
+			kind = schemaArray
+		}
+	default:
+		return nil, fmt.Errorf("%w: type must be a string or a list", ErrInvalidSchema)
+	}
+
+	typ := &schemaType{kind: kind}
+	var err error
+	switch kind {
+	case schemaObject:
+		properties, _ := definition["properties"].(map[string]interface{})
+		typ.fields = make(map[string]*schemaType, len(properties))
+		for name, property := range properties {
+			if typ.fields[name], err = r.read(property); err != nil {
+				return nil, err
+			}
+		}
+		switch additional := definition["additionalProperties"].(type) {
+		case bool:
+			if additional {
+				typ.additional = &schemaType{kind: schemaAny}
+			}
+		case map[string]interface{}:
+			if typ.additional, err = r.read(additional); err != nil {
+				return nil, err
+			}
+		}
+	case schemaArray:
+		if typ.element, err = r.read(definition["items"]); err != nil {
+			return nil, err
+		}
+	}
+	return typ, nil
+}
+
+// reference returns the type of a local reference like '#/$defs/Customer'.
+func (r *jsonSchemaReader) reference(ref string) (*schemaType, error) {
+	if typ, exists := r.refs[ref]; exists {
+		return typ, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("%w: only local references are supported, have %s", ErrInvalidSchema, ref)
+	}
+
+	var target interface{} = r.root
+	for _, name := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if name == "" {
+			continue
+		}
+		object, isObject := target.(map[string]interface{})
+		if !isObject {
+			return nil, fmt.Errorf("%w: reference %s not found", ErrInvalidSchema, ref)
+		}
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+		if target, isObject = object[name]; !isObject {
+			return nil, fmt.Errorf("%w: reference %s not found", ErrInvalidSchema, ref)
+		}
+	}
+
+	// the type is registered before it is read, so recursive references resolve to it
+	typ := &schemaType{}
+	r.refs[ref] = typ
+	read, err := r.read(target)
+	if err != nil {
+		return nil, err
+	}
+	*typ = *read
+	return typ, nil
+}
+
+// jsonSchemaKind returns the kind of a JSON Schema type name.
+func jsonSchemaKind(name string) schemaKind {
+	switch name {
+	case "object":
+		return schemaObject
+	case "array":
+		return schemaArray
+	case "string":
+		return schemaString
+	case "number":
+		return schemaNumber
+	case "integer":
+		return schemaInteger
+	case "boolean":
+		return schemaBoolean
+	}
+	return schemaAny
+}
@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type validationCustomer struct {
+	Name  string
+	Email string
+}
+
+type validationItem struct {
+	Description string
+	Price       float64
+}
+
+type validationInvoice struct {
+	Number   string
+	Customer validationCustomer
+	Items    []validationItem
+	Tags     map[string]string
+	Due      time.Time
+	Paid     bool
+}
+
+// Total returns the sum of all items.
+func (i validationInvoice) Total() float64 {
+	total := 0.0
+	for _, item := range i.Items {
+		total += item.Price
+	}
+	return total
+}
+
+func TestValidateTemplate(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>Invoice {{.Number}} for {{.Customer.Name}}, {{.Customer.Emal}}, due {{.Due}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{range .Items}}{{.Description}} {{.Price}} {{.Quantity}}{{end}} {{.Total}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{range .Number}}{{end}}{{.Paid.Date}} {{.Tags.urgent}} {{.Customer}}{{if .Paid}} (paid){{end}}</w:t></w:r></w:p>`)
+
+	expected := []TemplateIssue{
+		{Kind: TemplateIssueTypeMismatch, Field: "Customer", Message: "Customer is an object and cannot be written as text", Suggestion: "write its fields or pass it to a function"},
+		{Kind: TemplateIssueUnknownField, Field: "Customer.Emal", Message: "field Customer.Emal is not defined", Suggestion: "did you mean Email?"},
+		{Kind: TemplateIssueUnknownField, Field: "Items.Quantity", Message: "field Items.Quantity is not defined"},
+		{Kind: TemplateIssueTypeMismatch, Field: "Number", Message: "Number is a string and cannot be ranged over"},
+		{Kind: TemplateIssueTypeMismatch, Field: "Paid", Message: "Paid is a boolean and has no field Date"},
+	}
+	for _, schema := range []interface{}{validationInvoice{}, (*validationInvoice)(nil), reflect.TypeOf(validationInvoice{})} {
+		issues, err := ValidateTemplate(input, schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(issues, expected) {
+			t.Errorf("unexpected issues for %T:\n%v\nexpected:\n%v", schema, issues, expected)
+		}
+	}
+}
+
+func TestValidateTemplate_JSONSchema(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Customer.Name}} {{.Customer.Phone}}{{range .Items}}{{.SKU}} {{.Parent.SKU}}{{end}}{{.Meta.source}}</w:t></w:r></w:p>`)
+	schema := `{
+		"type": "object",
+		"properties": {
+			"Customer": {"type": ["object", "null"], "properties": {"Name": {"type": "string"}}},
+			"Items": {"type": "array", "items": {"$ref": "#/$defs/Item"}},
+			"Meta": {"type": "object", "additionalProperties": {"type": "string"}}
+		},
+		"$defs": {
+			"Item": {"type": "object", "properties": {"SKU": {"type": "string"}, "Parent": {"$ref": "#/$defs/Item"}}}
+		}
+	}`
+	issues, err := ValidateTemplate(input, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != TemplateIssueUnknownField || issues[0].Field != "Customer.Phone" {
+		t.Errorf("expected Customer.Phone to be unknown, have %v", issues)
+	}
+
+	if _, err := ValidateTemplate(input, `{"type": `); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema, have %v", err)
+	}
+	if _, err := ValidateTemplate(input, `{"$ref": "#/$defs/Missing"}`); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("expected ErrInvalidSchema for a missing reference, have %v", err)
+	}
+}
+
+func TestValidateTemplate_Syntax(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{if .Paid}}paid</w:t></w:r></w:p>`)
+	issues, err := ValidateTemplate(input, validationInvoice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var templateErr *TemplateError
+	if len(issues) != 1 || issues[0].Kind != TemplateIssueSyntax || !errors.As(issues[0].Err, &templateErr) ||
+		issues[0].Suggestion != "add {{end}} at the end of the block" {
+		t.Errorf("expected a syntax issue, have %v", issues)
+	}
+}
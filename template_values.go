@@ -0,0 +1,153 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// templateValueMarkerRegex matches the markers of the structured values written by template actions.
+// The markers are XML comments, which are valid inside any text and cannot be written by escaped values.
+var templateValueMarkerRegex = regexp.MustCompile(`<!--docxTemplateValue:([0-9]+)-->`)
+
+// templateValues collects the structured values (e.g. Image or Checklist) written by the template actions of a
+// single rendering. Those values are WordprocessingML and not text, so the action writes a marker into the
+// text-run which is replaced by the runs of the value after all parts were rendered, splitting the run as needed.
+type templateValues struct {
+	values []inlineValue
+}
+
+// newTemplateValues returns an empty templateValues.
+func newTemplateValues() *templateValues {
+	return &templateValues{}
+}
+
+// escape is the escape function of template actions. Structured values are replaced by a marker,
+// all other values are escaped by templateEscape.
+func (v *templateValues) escape(args ...interface{}) string {
+	if len(args) == 1 {
+		if value, isRich := args[0].(inlineValue); isRich {
+			v.values = append(v.values, value)
+			return fmt.Sprintf("<!--docxTemplateValue:%d-->", len(v.values))
+		}
+	}
+	return templateEscape(args...)
+}
+
+// replace replaces the markers of all collected values inside the rendered document.
+// The text of the rendered document is not parsed for placeholders, it may contain any delimiters.
+func (v *templateValues) replace(rendered []byte) ([]byte, error) {
+	if len(v.values) == 0 {
+		return rendered, nil
+	}
+	doc, err := openRenderedDocument(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open rendered document: %w", err)
+	}
+	defer doc.Close()
+
+	var names []string
+	for name, data := range doc.files {
+		if templateValueMarkerRegex.Match(data) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := v.insert(doc, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to insert template values: %w", err)
+		}
+		if err := doc.SetFile(name, data); err != nil {
+			return nil, err
+		}
+		if err := doc.parseFile(name); err != nil {
+			return nil, err
+		}
+	}
+	if err := doc.insertFootnotes(); err != nil {
+		return nil, fmt.Errorf("unable to insert template values: %w", err)
+	}
+	if err := doc.insertBlocks(); err != nil {
+		return nil, fmt.Errorf("unable to insert template values: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// openRenderedDocument opens a rendered template. Its text is not parsed for placeholders, since the values
+// written by the template may contain any delimiters.
+func openRenderedDocument(rendered []byte) (*Document, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(rendered), int64(len(rendered)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	doc := emptyDocument(zipReader, "", nil, currentParseLimits())
+	doc.ignorePlaceholders = true
+	if err := doc.load(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// insert returns the file with the given name in which all markers are replaced by the runs of their values.
+// The run of a marker is split, the runs of the value are inserted with its properties.
+func (v *templateValues) insert(doc *Document, name string) ([]byte, error) {
+	data := doc.files[name]
+	runs := doc.runParsers[name].Runs()
+	replacer := NewReplacer(data, nil)
+
+	var out bytes.Buffer
+	last := 0
+	for _, m := range templateValueMarkerRegex.FindAllSubmatchIndex(data, -1) {
+		index, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil || index < 1 || index > len(v.values) {
+			return nil, fmt.Errorf("invalid template value marker %s in %s", data[m[0]:m[1]], name)
+		}
+		value := v.values[index-1]
+		run := enclosingRun(runs, int64(m[0]))
+		if run == nil {
+			return nil, fmt.Errorf("%T must be written into the text of a run in %s", value, name)
+		}
+
+		var runsXml string
+		if run.isDrawingML(data) {
+			text, err := doc.drawingText("", value, "")
+			if err != nil {
+				return nil, err
+			}
+			runsXml = escapeDrawingText(text, replacer.RunProperties(run))
+		} else {
+			ctx := &valueContext{doc: doc, part: name, runProperties: replacer.RunProperties(run)}
+			if runsXml, err = ctx.valueXml(value); err != nil {
+				return nil, fmt.Errorf("unable to render template value %T: %w", value, err)
+			}
+			runsXml = splitRun(ctx.runProperties, runsXml)
+		}
+		out.Write(data[last:m[0]])
+		out.WriteString(runsXml)
+		last = m[1]
+	}
+	out.Write(data[last:])
+	return out.Bytes(), nil
+}
+
+// enclosingRun returns the innermost run which contains the position, nil if there is none.
+// Runs are nested if a run contains a text box.
+func enclosingRun(runs DocumentRuns, pos int64) (enclosing *Run) {
+	for _, run := range runs {
+		if run.OpenTag.End <= pos && pos < run.CloseTag.Start {
+			if enclosing == nil || run.OpenTag.Start > enclosing.OpenTag.Start {
+				enclosing = run
+			}
+		}
+	}
+	return enclosing
+}
@@ -0,0 +1,72 @@
+package docx
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_StructuredValues(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Logo: {{.Logo}} of {{.Name}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{range .Lists}}{{.}}{{end}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Logo": Image{Data: imageBytes, Width: 2 * EMUPerCentimeter},
+		"Name": "{ACME}",
+		"Lists": []interface{}{
+			Checklist{Items: []ChecklistItem{{Label: "First", Checked: true}}},
+			Checklist{Items: []ChecklistItem{{Label: "Second"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Logo: </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:drawing>`,
+		`<w:t xml:space="preserve"> of {ACME}</w:t>`,
+		`<w:t>` + CheckedGlyph + `</w:t>`,
+		`<w:t xml:space="preserve"> Second</w:t>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if templateValueMarkerRegex.MatchString(document) {
+		t.Errorf("expected all value markers to be replaced: %s", document)
+	}
+	if media := readTestPart(t, output, "word/media/image1.jpeg"); len(media) != len(imageBytes) {
+		t.Errorf("expected image part with %d bytes, have %d", len(imageBytes), len(media))
+	}
+}
+
+func TestProcessTemplateDocx_StructuredValuesWithDelimiters(t *testing.T) {
+	imageBytes, err := os.ReadFile("./test/cameraman.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{.Logo}} {{.Note}} {{.Text}}</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{
+		"Logo": Image{Data: imageBytes, Width: 2 * EMUPerCentimeter},
+		"Note": "a } b { c",
+		"Text": "{docxTemplateValue1} <!--docxTemplateValue:1-->",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := readTestPart(t, output, DocumentXml)
+	if count := strings.Count(document, "<w:drawing>"); count != 1 {
+		t.Errorf("expected the image to be inserted once, have %d: %s", count, document)
+	}
+	if expected := ` a } b { c {docxTemplateValue1} &lt;!--docxTemplateValue:1--&gt;</w:t>`; !strings.Contains(document, expected) {
+		t.Errorf("expected the text values unchanged %s in document: %s", expected, document)
+	}
+}
@@ -0,0 +1,197 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// TextPolicy controls how text values are prepared before they are inserted into the document.
+// Independent of the policy, all inserted text is normalized to Unicode NFC and characters which are not allowed
+// in XML (e.g. control characters or invalid UTF-8) are removed.
+type TextPolicy struct {
+	// EmojiFont is the font of the runs into which emoji are placed, e.g. "Segoe UI Emoji".
+	// Fonts of regular text usually do not contain emoji which makes some viewers render them as garbage.
+	// If empty, emoji use the font of the placeholder run.
+	EmojiFont string
+	// Supported reports whether the fonts of the target document can display the rune.
+	// Unsupported runes are replaced by Substitute. If nil, all runes are supported.
+	Supported func(r rune) bool
+	// Substitute replaces every unsupported rune, e.g. "?". If empty, unsupported runes are removed.
+	Substitute string
+	// Newlines controls how the lines of multi-line values are inserted, as line breaks by default.
+	Newlines NewlineMode
+}
+
+// NewlineMode controls how newlines (\n, \r\n or \r) inside text values are inserted.
+type NewlineMode int
+
+const (
+	// NewlineBreak inserts a line break (<w:br/>), all lines stay inside the paragraph of the placeholder.
+	NewlineBreak NewlineMode = iota
+	// NewlineParagraph starts a new paragraph for every line which uses the properties (style, numbering, spacing)
+	// of the paragraph of the placeholder. The placeholder must be a direct child of its paragraph,
+	// e.g. not inside a hyperlink or content control.
+	NewlineParagraph
+)
+
+// SetTextPolicy sets the policy which is applied to all text values inserted by ReplaceAll, Replace and MergeFields.
+//
+// Example:
+//
+//	doc.SetTextPolicy(docx.TextPolicy{
+//	    EmojiFont: "Segoe UI Emoji",
+//	    Supported: func(r rune) bool { return r <= unicode.MaxLatin1 || docx.IsEmoji(r) },
+//	    Substitute: "?",
+//	})
+func (d *Document) SetTextPolicy(policy TextPolicy) {
+	d.textPolicy = policy
+}
+
+// apply substitutes all runes which are not supported by the policy.
+func (p TextPolicy) apply(text string) string {
+	if p.Supported == nil {
+		return text
+	}
+	var result strings.Builder
+	for _, r := range text {
+		if r == '\n' || r == '\t' || p.Supported(r) {
+			result.WriteRune(r)
+			continue
+		}
+		result.WriteString(p.Substitute)
+	}
+	return result.String()
+}
+
+// splitsRuns returns true if the text must be inserted as multiple runs according to the policy.
+func (p TextPolicy) splitsRuns(text string) bool {
+	return p.EmojiFont != "" && strings.IndexFunc(text, IsEmoji) >= 0 ||
+		p.Newlines == NewlineParagraph && strings.Contains(text, "\n")
+}
+
+// textRuns returns the runs for the given, not yet escaped, text. Emoji are placed into their own runs
+// using the EmojiFont of the policy. With NewlineParagraph, the lines are separated by paragraphs.
+func (p TextPolicy) textRuns(ctx *valueContext, text string) string {
+	if p.Newlines != NewlineParagraph || !strings.Contains(text, "\n") {
+		return p.lineRuns(ctx, text)
+	}
+	// a section break belongs to the paragraph of the placeholder only
+	paragraphProperties := ctx.paragraphProperties
+	if sections := sectionProperties([]byte(paragraphProperties)); len(sections) > 0 {
+		paragraphProperties = paragraphProperties[:sections[0][0]] + paragraphProperties[sections[0][1]:]
+		if paragraphProperties == "<w:pPr></w:pPr>" {
+			paragraphProperties = ""
+		}
+	}
+	lines := strings.Split(text, "\n")
+	runs := make([]string, len(lines))
+	for i, line := range lines {
+		runs[i] = p.lineRuns(ctx, line)
+	}
+	return strings.Join(runs, "</w:p><w:p>"+paragraphProperties)
+}
+
+// lineRuns returns the runs for the given, not yet escaped, text without splitting it into paragraphs.
+func (p TextPolicy) lineRuns(ctx *valueContext, text string) string {
+	if p.EmojiFont == "" || strings.IndexFunc(text, IsEmoji) < 0 {
+		return ctx.textRun(escapeRunText(text))
+	}
+
+	emojiCtx := *ctx
+	font := fmt.Sprintf(`<w:rFonts w:ascii="%[1]s" w:hAnsi="%[1]s" w:eastAsia="%[1]s" w:cs="%[1]s"/>`, p.EmojiFont)
+	emojiCtx.runProperties = setRunProperty(ctx.runProperties, "rFonts", font)
+
+	var runs strings.Builder
+	for _, segment := range splitEmoji(text) {
+		if segment.emoji {
+			runs.WriteString(emojiCtx.textRun(escapeRunText(segment.text)))
+		} else {
+			runs.WriteString(ctx.textRun(escapeRunText(segment.text)))
+		}
+	}
+	return runs.String()
+}
+
+// textSegment is a part of a text which either consists of emoji only or does not contain any emoji.
+type textSegment struct {
+	text  string
+	emoji bool
+}
+
+// splitEmoji splits the text into segments of emoji and regular text.
+// Joiners, variation selectors and modifiers which follow an emoji are kept in its segment.
+func splitEmoji(text string) []textSegment {
+	var segments []textSegment
+	start := 0
+	inEmoji := false
+	for i, r := range text {
+		isPart := IsEmoji(r) || (inEmoji && isEmojiComponent(r))
+		if i > start && isPart != inEmoji {
+			segments = append(segments, textSegment{text: text[start:i], emoji: inEmoji})
+			start = i
+		}
+		inEmoji = isPart
+	}
+	if start < len(text) {
+		segments = append(segments, textSegment{text: text[start:], emoji: inEmoji})
+	}
+	return segments
+}
+
+// IsEmoji reports whether the rune is an emoji which requires an emoji font to be displayed.
+func IsEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // mahjong, cards, regional indicators, pictographs, emoticons, transport, symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B05 && r <= 0x2B55: // arrows, stars and circles
+		return true
+	case r >= 0x231A && r <= 0x23FF: // watch, hourglass and media controls
+		return true
+	}
+	return false
+}
+
+// isEmojiComponent reports whether the rune modifies or joins emoji, e.g. the zero width joiner of '👩‍💻'.
+func isEmojiComponent(r rune) bool {
+	return r == 0x200D || r == 0xFE0F || r == 0x20E3 || (r >= 0xE0020 && r <= 0xE007F)
+}
+
+// newlineReplacer converts Windows (\r\n) and classic Mac OS (\r) newlines into \n.
+var newlineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// normalizeText normalizes the text to Unicode NFC, converts all newlines into \n and removes all characters
+// which are not allowed in XML.
+func normalizeText(text string) string {
+	text = strings.ToValidUTF8(text, string(utf8.RuneError))
+	if strings.IndexByte(text, '\r') >= 0 {
+		text = newlineReplacer.Replace(text)
+	}
+	text = norm.NFC.String(text)
+	if strings.IndexFunc(text, isInvalidXMLChar) < 0 {
+		return text
+	}
+	return strings.Map(func(r rune) rune {
+		if isInvalidXMLChar(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// isInvalidXMLChar reports whether the rune is not allowed inside XML 1.0 documents.
+func isInvalidXMLChar(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return false
+	case r < 0x20:
+		return true
+	case r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE, r == 0xFFFF:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Cafe\u0301", "Caf\u00e9"},
+		{"bell\u0007 and null\u0000", "bell and null"},
+		{"tab\tand\nnewline", "tab\tand\nnewline"},
+		{"windows\r\nand mac\rnewlines", "windows\nand mac\nnewlines"},
+		{"invalid \xff utf8", "invalid � utf8"},
+		{"astral 😀 𝄞", "astral 😀 𝄞"},
+	}
+	for _, test := range tests {
+		if normalized := normalizeText(test.text); normalized != test.expected {
+			t.Errorf("%q: expected %q, have %q", test.text, test.expected, normalized)
+		}
+	}
+}
+
+func TestSplitEmoji(t *testing.T) {
+	segments := splitEmoji("Hi 👩‍💻👍🏽! ❤️")
+	expected := []textSegment{
+		{text: "Hi ", emoji: false},
+		{text: "👩‍💻👍🏽", emoji: true},
+		{text: "! ", emoji: false},
+		{text: "❤️", emoji: true},
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Errorf("expected %+v, have %+v", expected, segments)
+	}
+}
+
+func TestTextPolicy_Apply(t *testing.T) {
+	policy := TextPolicy{
+		Supported:  func(r rune) bool { return r < 0x250 },
+		Substitute: "?",
+	}
+	if text := policy.apply("Łódź → 😀\nok"); text != "Łódź ? ?\nok" {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	policy.Substitute = ""
+	if text := policy.apply("a😀b"); text != "ab" {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestDocument_ReplaceEmoji(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>{greeting}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetTextPolicy(TextPolicy{EmojiFont: "Segoe UI Emoji"})
+	if err := doc.ReplaceAll(PlaceholderMap{"greeting": "Cafe\u0301 🎉"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, buf.Bytes(), DocumentXml)
+
+	for _, expected := range []string{
+		`<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Caf` + "\u00e9" + ` </w:t></w:r>`,
+		`<w:r><w:rPr><w:rFonts w:ascii="Segoe UI Emoji" w:hAnsi="Segoe UI Emoji" w:eastAsia="Segoe UI Emoji" w:cs="Segoe UI Emoji"/><w:b/></w:rPr><w:t xml:space="preserve">🎉</w:t></w:r>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+}
+
+func TestDocument_ReplaceMultiline(t *testing.T) {
+	body := `<w:p><w:pPr><w:pStyle w:val="Address"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>To: {address}</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906"/></w:sectPr></w:pPr><w:r><w:t>{address}</w:t></w:r></w:p>`
+	value := "Jane Doe\r\n Main Street 1\n12345 Springfield"
+
+	tests := []struct {
+		newlines NewlineMode
+		expected []string
+	}{
+		{NewlineBreak, []string{
+			`To: Jane Doe</w:t><w:br/><w:t xml:space="preserve"> Main Street 1</w:t><w:br/><w:t xml:space="preserve">12345 Springfield</w:t>`,
+		}},
+		{NewlineParagraph, []string{
+			`<w:t xml:space="preserve">Jane Doe</w:t></w:r></w:p><w:p><w:pPr><w:pStyle w:val="Address"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> Main Street 1</w:t></w:r>` +
+				`</w:p><w:p><w:pPr><w:pStyle w:val="Address"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">12345 Springfield</w:t></w:r>`,
+			// the section break is not copied
+			`</w:p><w:p><w:r><w:t xml:space="preserve">12345 Springfield</w:t></w:r>`,
+		}},
+	}
+	for _, tt := range tests {
+		doc, err := OpenBytes(buildTestDocx(t, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.SetTextPolicy(TextPolicy{Newlines: tt.newlines})
+		if err := doc.ReplaceAll(PlaceholderMap{"address": value}); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		document := readTestPart(t, buf.Bytes(), DocumentXml)
+		for _, expected := range tt.expected {
+			if !strings.Contains(document, expected) {
+				t.Errorf("expected %s in document: %s", expected, document)
+			}
+		}
+		if n := strings.Count(document, "<w:sectPr>"); n != 1 {
+			t.Errorf("expected a single section break, have %d: %s", n, document)
+		}
+	}
+}
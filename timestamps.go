@@ -0,0 +1,92 @@
+package docx
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimestampMode controls how a timestamp of the core properties is written, see TimestampPolicy.
+type TimestampMode int
+
+const (
+	// TimestampKeep keeps the timestamp of the template, the default.
+	TimestampKeep TimestampMode = iota
+	// TimestampSet sets the timestamp to TimestampPolicy.Time, the time of writing if it is zero.
+	TimestampSet
+	// TimestampZero removes the timestamp, so documents rendered from the same template and data are identical.
+	TimestampZero
+)
+
+// TimestampPolicy controls the timestamps of the core properties (docProps/core.xml) when the document is written.
+type TimestampPolicy struct {
+	Created     TimestampMode
+	Modified    TimestampMode
+	LastPrinted TimestampMode
+	// Time is the time set by TimestampSet, the time of writing if it is zero.
+	Time time.Time
+}
+
+// SetTimestampPolicy sets the policy applied to the timestamps of the core properties whenever the document is
+// written. Without a policy, the timestamps of the template are kept as they are.
+//
+// Example:
+//
+//	// a new document created now, never printed
+//	doc.SetTimestampPolicy(docx.TimestampPolicy{Created: docx.TimestampSet, Modified: docx.TimestampSet,
+//	    LastPrinted: docx.TimestampZero})
+//
+//	// deterministic output, e.g. for golden files
+//	doc.SetTimestampPolicy(docx.TimestampPolicy{Created: docx.TimestampZero, Modified: docx.TimestampZero,
+//	    LastPrinted: docx.TimestampZero})
+func (d *Document) SetTimestampPolicy(policy TimestampPolicy) {
+	d.timestampPolicy = policy
+}
+
+// applyTimestampPolicy updates the timestamps of the core properties according to the timestamp policy.
+func (d *Document) applyTimestampPolicy() error {
+	policy := d.timestampPolicy
+	now := policy.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	for _, timestamp := range []struct {
+		name string
+		mode TimestampMode
+	}{
+		{"Created", policy.Created},
+		{"Modified", policy.Modified},
+		{"LastPrinted", policy.LastPrinted},
+	} {
+		switch timestamp.mode {
+		case TimestampSet:
+			if err := d.SetProperty(timestamp.name, now.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+		case TimestampZero:
+			if err := d.removeProperty(timestamp.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeProperty removes the core property with the given name if it is set.
+func (d *Document) removeProperty(name string) error {
+	partName, data, err := d.propertiesPart(RelationshipTypeCoreProperties, CorePropertiesXml)
+	if err != nil || data == nil {
+		return err
+	}
+	match := corePropertiesRegex.FindSubmatchIndex(data)
+	if match == nil {
+		return fmt.Errorf("invalid core properties part %s", partName)
+	}
+	content := string(data[match[4]:match[5]])
+	existing := corePropertyRegex(coreProperties[name]).FindStringIndex(content)
+	if existing == nil {
+		return nil
+	}
+	content = content[:existing[0]] + content[existing[1]:]
+	changed := string(data[:match[4]]) + content + string(data[match[5]:])
+	return d.setPart(partName, []byte(changed))
+}
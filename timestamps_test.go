@@ -0,0 +1,71 @@
+package docx
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDocument_SetTimestampPolicy(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>text</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{
+		"Title": "Invoice", "Created": "2020-01-01T08:00:00Z", "Modified": "2021-01-01T08:00:00Z", "LastPrinted": "2022-01-01T08:00:00Z",
+	} {
+		if err := doc.SetProperty(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var template bytes.Buffer
+	if err := doc.Write(&template); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func(policy TimestampPolicy) map[string]string {
+		t.Helper()
+		doc, err := OpenBytes(template.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.SetTimestampPolicy(policy)
+		var buf bytes.Buffer
+		if err := doc.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		written, err := OpenBytes(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		properties, err := written.Properties()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return properties
+	}
+
+	tests := []struct {
+		policy   TimestampPolicy
+		expected map[string]string
+	}{
+		{TimestampPolicy{}, map[string]string{
+			"Title": "Invoice", "Created": "2020-01-01T08:00:00Z", "Modified": "2021-01-01T08:00:00Z", "LastPrinted": "2022-01-01T08:00:00Z",
+		}},
+		{TimestampPolicy{Modified: TimestampSet, LastPrinted: TimestampZero, Time: time.Date(2026, 5, 4, 12, 0, 0, 0, time.FixedZone("CEST", 7200))},
+			map[string]string{"Title": "Invoice", "Created": "2020-01-01T08:00:00Z", "Modified": "2026-05-04T10:00:00Z"}},
+		{TimestampPolicy{Created: TimestampZero, Modified: TimestampZero, LastPrinted: TimestampZero}, map[string]string{"Title": "Invoice"}},
+	}
+	for _, test := range tests {
+		if properties := render(test.policy); !reflect.DeepEqual(properties, test.expected) {
+			t.Errorf("%+v: expected %v, have %v", test.policy, test.expected, properties)
+		}
+	}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	created, err := time.Parse(time.RFC3339, render(TimestampPolicy{Created: TimestampSet})["Created"])
+	if err != nil || created.Before(before) {
+		t.Errorf("expected the time of writing, have %s (%v)", created, err)
+	}
+}
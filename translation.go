@@ -0,0 +1,501 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// translationAnchorRegex matches the anchors inside the text of translation segments, e.g. '<1>', '</1>' and
+	// '<2/>', and captures the closing slash, the number and the self-closing slash.
+	translationAnchorRegex = regexp.MustCompile(`<(/?)([0-9]+)(/?)>`)
+	// translationPlaceholderRegex matches placeholders and template actions inside the text of translation segments.
+	translationPlaceholderRegex = regexp.MustCompile(`\{\{.*?\}\}|\{[^{}]*\}`)
+)
+
+// ErrTranslationMismatch is returned by ApplyTranslations if a translation does not fit the template, e.g. because
+// the template was changed after the extraction or the translated text lost a placeholder or an anchor.
+var ErrTranslationMismatch = errors.New("translation does not match the template")
+
+// TranslationSegment is a paragraph of static text of a template, see ExtractTranslations.
+type TranslationSegment struct {
+	// ID identifies the paragraph inside the template, e.g. 'word/document.xml#4'.
+	ID string `json:"id"`
+	// Source is the text of the paragraph. Placeholders are kept as they are. Text with other formatting than the
+	// first text of the paragraph is wrapped in numbered anchors, e.g. 'Pay <1>now</1>', other content like images,
+	// tabs or fields is replaced by empty anchors, e.g. 'Total:<2/>{total}'.
+	Source string `json:"source"`
+	// Target is the translated text with the same placeholders and anchors. The anchors may be moved, formatting
+	// anchors may also be repeated or dropped. Segments without target keep their source text.
+	Target string `json:"target,omitempty"`
+}
+
+// Translations are the translation segments of a template, which can be stored as JSON or as XLIFF 1.2 file.
+type Translations struct {
+	SourceLanguage string               `json:"sourceLanguage,omitempty"`
+	TargetLanguage string               `json:"targetLanguage,omitempty"`
+	Segments       []TranslationSegment `json:"segments"`
+}
+
+// translationParagraph is a paragraph of a part with static text, and its content split into text and anchors.
+type translationParagraph struct {
+	part       string
+	id         string
+	start, end int
+	// properties are the open tag and the properties of the paragraph.
+	properties string
+	source     string
+	// formats are the run properties of the formatting anchors, elements the XML of the element anchors.
+	formats  map[int]string
+	elements map[int]string
+	// base are the run properties of text outside of anchors.
+	base string
+}
+
+// ExtractTranslations returns all paragraphs of the document body, the headers, the footers and the notes which
+// contain static text, i.e. text besides placeholders, as translation segments. The text of text boxes and of
+// hyperlinks is kept as anchor. Translate the segments, e.g. as JSON or with WriteXLIFF, and create the localized
+// template with ApplyTranslations or TranslateTemplate.
+//
+// Example:
+//
+//	translations, err := doc.ExtractTranslations()
+//	translations.SourceLanguage, translations.TargetLanguage = "en", "de"
+//	err = translations.WriteXLIFF(file)
+func (d *Document) ExtractTranslations() (*Translations, error) {
+	paragraphs, err := d.translationParagraphs()
+	if err != nil {
+		return nil, err
+	}
+	translations := &Translations{Segments: make([]TranslationSegment, len(paragraphs))}
+	for i, paragraph := range paragraphs {
+		translations.Segments[i] = TranslationSegment{ID: paragraph.id, Source: paragraph.source}
+	}
+	return translations, nil
+}
+
+// ApplyTranslations replaces the text of all translated segments by their target, keeping the paragraph properties,
+// the formatting of the anchored text and the anchored elements. The document must be the template the segments
+// were extracted from, ErrTranslationMismatch is returned otherwise.
+func (d *Document) ApplyTranslations(translations *Translations) error {
+	paragraphs, err := d.translationParagraphs()
+	if err != nil {
+		return err
+	}
+	byId := make(map[string]*translationParagraph, len(paragraphs))
+	for i := range paragraphs {
+		byId[paragraphs[i].id] = &paragraphs[i]
+	}
+	translated := make(map[string]string)
+	for _, segment := range translations.Segments {
+		paragraph, exists := byId[segment.ID]
+		if !exists {
+			return fmt.Errorf("%w: segment %s not found", ErrTranslationMismatch, segment.ID)
+		}
+		if paragraph.source != segment.Source {
+			return fmt.Errorf("%w: segment %s has the source %q instead of %q", ErrTranslationMismatch, segment.ID, paragraph.source, segment.Source)
+		}
+		if segment.Target == "" {
+			continue
+		}
+		paragraphXml, err := paragraph.translatedXml(segment.Target)
+		if err != nil {
+			return fmt.Errorf("%w: segment %s: %v", ErrTranslationMismatch, segment.ID, err)
+		}
+		translated[segment.ID] = paragraphXml
+	}
+
+	for _, name := range d.xmlFiles() {
+		changed := string(d.files[name])
+		modified := false
+		// paragraphs are replaced from the end so the offsets of the preceding paragraphs stay valid
+		for i := len(paragraphs) - 1; i >= 0; i-- {
+			paragraph := paragraphs[i]
+			if paragraphXml, exists := translated[paragraph.id]; exists && paragraph.part == name {
+				changed = changed[:paragraph.start] + paragraphXml + changed[paragraph.end:]
+				modified = true
+			}
+		}
+		if !modified {
+			continue
+		}
+		if err := d.SetFile(name, []byte(changed)); err != nil {
+			return err
+		}
+		if err := d.parseFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TranslateTemplate returns a localized variant of the template, see ApplyTranslations.
+//
+// Example:
+//
+//	translations, err := docx.ReadXLIFF(file)
+//	german, err := docx.TranslateTemplate(template, translations)
+func TranslateTemplate(template []byte, translations *Translations) ([]byte, error) {
+	doc, err := OpenBytes(template)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if err := doc.ApplyTranslations(translations); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// translationParagraphs returns the paragraphs with static text of all parts in document order.
+func (d *Document) translationParagraphs() ([]translationParagraph, error) {
+	var paragraphs []translationParagraph
+	for _, name := range d.xmlFiles() {
+		data := string(d.files[name])
+		var positions [][2]int
+		collectParagraphs(data, 0, &positions)
+		for i, position := range positions {
+			paragraph, err := parseTranslationParagraph(data[position[0]:position[1]])
+			if err != nil {
+				return nil, fmt.Errorf("paragraph %d of %s: %w", i, name, err)
+			}
+			if paragraph == nil {
+				continue
+			}
+			paragraph.part, paragraph.id = name, name+"#"+strconv.Itoa(i)
+			paragraph.start, paragraph.end = position[0], position[1]
+			paragraphs = append(paragraphs, *paragraph)
+		}
+	}
+	return paragraphs, nil
+}
+
+// collectParagraphs adds the positions of all paragraphs inside the content to the paragraphs, including the
+// paragraphs of tables and content controls, but not those inside of paragraphs like text boxes.
+func collectParagraphs(content string, offset int, paragraphs *[][2]int) {
+	for _, child := range childElements(content) {
+		switch child.name {
+		case "p":
+			*paragraphs = append(*paragraphs, [2]int{offset + child.start, offset + child.end})
+		case "sectPr", "tblPr", "tblGrid", "trPr", "tcPr", "sdtPr", "sdtEndPr", "del", "moveFrom":
+		default:
+			openTag, inner, _ := splitElement(content[child.start:child.end])
+			if inner != "" {
+				collectParagraphs(inner, offset+child.start+len(openTag), paragraphs)
+			}
+		}
+	}
+}
+
+// parseTranslationParagraph splits the paragraph into text and anchors. It returns nil if the paragraph has no
+// static text.
+func parseTranslationParagraph(paragraph string) (*translationParagraph, error) {
+	if !strings.HasSuffix(paragraph, "</w:p>") {
+		return nil, nil
+	}
+	properties := paragraphPropertiesRegex.FindString(paragraph)
+	content := paragraph[len(properties) : len(paragraph)-len("</w:p>")]
+
+	// the text of the paragraph with the run properties of every byte, and the elements at their text offsets
+	var text strings.Builder
+	var runProperties []string
+	type anchoredElement struct {
+		pos     int
+		element string
+	}
+	var elements []anchoredElement
+	for _, child := range childElements(content) {
+		element := content[child.start:child.end]
+		switch {
+		case child.name == "proofErr":
+		case child.name == "r" && isTextRun(element):
+			_, inner, _ := splitElement(element)
+			rPr := RunPropertiesRegex.FindString(inner)
+			for _, match := range TextNodeRegex.FindAllStringSubmatch(inner, -1) {
+				t := html.UnescapeString(match[2])
+				text.WriteString(t)
+				for range len(t) {
+					runProperties = append(runProperties, rPr)
+				}
+			}
+		default:
+			elements = append(elements, anchoredElement{text.Len(), element})
+		}
+	}
+	plain := text.String()
+	if !hasStaticText(plain) {
+		return nil, nil
+	}
+	if anchor := translationAnchorRegex.FindString(plain); anchor != "" {
+		return nil, fmt.Errorf("text %q cannot be told apart from an anchor", anchor)
+	}
+	// placeholders must not be split by anchors, they get the formatting of their first character
+	for _, match := range translationPlaceholderRegex.FindAllStringIndex(plain, -1) {
+		for i := match[0] + 1; i < match[1]; i++ {
+			runProperties[i] = runProperties[match[0]]
+		}
+	}
+
+	p := &translationParagraph{properties: properties, formats: map[int]string{}, elements: map[int]string{}, base: runProperties[0]}
+	anchors := map[string]int{}
+	var source strings.Builder
+	open := 0
+	closeAnchor := func() {
+		if open != 0 {
+			source.WriteString("</" + strconv.Itoa(open) + ">")
+			open = 0
+		}
+	}
+	number := 0
+	for pos := 0; pos <= len(plain); pos++ {
+		for len(elements) > 0 && elements[0].pos == pos {
+			closeAnchor()
+			number++
+			p.elements[number] = elements[0].element
+			source.WriteString("<" + strconv.Itoa(number) + "/>")
+			elements = elements[1:]
+		}
+		if pos == len(plain) {
+			break
+		}
+		if rPr := runProperties[pos]; rPr == p.base {
+			closeAnchor()
+		} else if anchor, exists := anchors[rPr]; !exists || anchor != open {
+			closeAnchor()
+			if !exists {
+				number++
+				anchor, anchors[rPr], p.formats[number] = number, number, rPr
+			}
+			source.WriteString("<" + strconv.Itoa(anchor) + ">")
+			open = anchor
+		}
+		source.WriteByte(plain[pos])
+	}
+	closeAnchor()
+	p.source = source.String()
+	return p, nil
+}
+
+// isTextRun returns true if the run contains nothing but text.
+func isTextRun(run string) bool {
+	_, inner, _ := splitElement(run)
+	for _, child := range childElements(inner) {
+		if child.name != "rPr" && child.name != "t" && child.name != "lastRenderedPageBreak" {
+			return false
+		}
+	}
+	return true
+}
+
+// hasStaticText returns true if the text contains letters outside of placeholders.
+func hasStaticText(text string) bool {
+	return strings.IndexFunc(translationPlaceholderRegex.ReplaceAllString(text, ""), unicode.IsLetter) >= 0
+}
+
+// translatedXml returns the paragraph with the translated text, which must contain the same placeholders and
+// element anchors as the source.
+func (p *translationParagraph) translatedXml(target string) (string, error) {
+	plain := func(text string) []string {
+		placeholders := translationPlaceholderRegex.FindAllString(translationAnchorRegex.ReplaceAllString(text, ""), -1)
+		slices.Sort(placeholders)
+		return placeholders
+	}
+	if source, translated := plain(p.source), plain(target); !slices.Equal(source, translated) {
+		return "", fmt.Errorf("expected the placeholders %v, have %v", source, translated)
+	}
+
+	var out strings.Builder
+	out.WriteString(p.properties)
+	rPr, open := p.base, 0
+	used := map[int]bool{}
+	writeText := func(text string) {
+		if text != "" {
+			out.WriteString("<w:r>" + rPr + `<w:t xml:space="preserve">` + escapeRunText(text) + "</w:t></w:r>")
+		}
+	}
+	last := 0
+	for _, match := range translationAnchorRegex.FindAllStringSubmatchIndex(target, -1) {
+		writeText(target[last:match[0]])
+		last = match[1]
+		closing, selfClosing := match[3] > match[2], match[7] > match[6]
+		number, _ := strconv.Atoi(target[match[4]:match[5]])
+		switch {
+		case selfClosing:
+			element, exists := p.elements[number]
+			if !exists || used[number] {
+				return "", fmt.Errorf("unexpected anchor <%d/>", number)
+			}
+			used[number] = true
+			out.WriteString(element)
+		case closing:
+			if number != open {
+				return "", fmt.Errorf("unexpected anchor </%d>", number)
+			}
+			rPr, open = p.base, 0
+		default:
+			format, exists := p.formats[number]
+			if !exists || open != 0 {
+				return "", fmt.Errorf("unexpected anchor <%d>", number)
+			}
+			rPr, open = format, number
+		}
+	}
+	writeText(target[last:])
+	if open != 0 {
+		return "", fmt.Errorf("anchor <%d> is not closed", open)
+	}
+	if len(used) != len(p.elements) {
+		return "", fmt.Errorf("expected %d element anchors, have %d", len(p.elements), len(used))
+	}
+	out.WriteString("</w:p>")
+	return out.String(), nil
+}
+
+// xliffDocument is an XLIFF 1.2 file with the translation segments of a template.
+type xliffDocument struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string   `xml:"version,attr"`
+	File    struct {
+		Original       string      `xml:"original,attr"`
+		SourceLanguage string      `xml:"source-language,attr"`
+		TargetLanguage string      `xml:"target-language,attr,omitempty"`
+		Datatype       string      `xml:"datatype,attr"`
+		Units          []xliffUnit `xml:"body>trans-unit"`
+	} `xml:"file"`
+}
+
+// xliffUnit is a translation segment inside an XLIFF file.
+type xliffUnit struct {
+	ID     string      `xml:"id,attr"`
+	Source xliffInner  `xml:"source"`
+	Target *xliffInner `xml:"target"`
+}
+
+// xliffInner is the content of a source or target element of XLIFF, text with inline elements.
+type xliffInner struct {
+	Content string `xml:",innerxml"`
+}
+
+// WriteXLIFF writes the translations as XLIFF 1.2 file. Formatting anchors are written as <g> elements, element
+// anchors as <x/> elements, so translation tools protect them. The source language defaults to 'en'.
+func (t *Translations) WriteXLIFF(w io.Writer) error {
+	var doc xliffDocument
+	doc.Version = "1.2"
+	doc.File.Original, doc.File.Datatype = DocumentXml, "x-docx"
+	doc.File.SourceLanguage, doc.File.TargetLanguage = t.SourceLanguage, t.TargetLanguage
+	if doc.File.SourceLanguage == "" {
+		doc.File.SourceLanguage = "en"
+	}
+	for _, segment := range t.Segments {
+		unit := xliffUnit{ID: segment.ID, Source: xliffInner{anchorsToXliff(segment.Source)}}
+		if segment.Target != "" {
+			unit.Target = &xliffInner{anchorsToXliff(segment.Target)}
+		}
+		doc.File.Units = append(doc.File.Units, unit)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// ReadXLIFF reads translations written by WriteXLIFF and translated by a translation tool.
+func ReadXLIFF(r io.Reader) (*Translations, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid XLIFF file: %w", err)
+	}
+	translations := &Translations{SourceLanguage: doc.File.SourceLanguage, TargetLanguage: doc.File.TargetLanguage}
+	for _, unit := range doc.File.Units {
+		source, err := xliffToAnchors(unit.Source.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source of %s: %w", unit.ID, err)
+		}
+		segment := TranslationSegment{ID: unit.ID, Source: source}
+		if unit.Target != nil {
+			if segment.Target, err = xliffToAnchors(unit.Target.Content); err != nil {
+				return nil, fmt.Errorf("invalid target of %s: %w", unit.ID, err)
+			}
+		}
+		translations.Segments = append(translations.Segments, segment)
+	}
+	return translations, nil
+}
+
+// anchorsToXliff returns the text of a segment as XLIFF content.
+func anchorsToXliff(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, match := range translationAnchorRegex.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(html.EscapeString(text[last:match[0]]))
+		last = match[1]
+		number := text[match[4]:match[5]]
+		switch {
+		case match[7] > match[6]:
+			out.WriteString(`<x id="` + number + `"/>`)
+		case match[3] > match[2]:
+			out.WriteString("</g>")
+		default:
+			out.WriteString(`<g id="` + number + `">`)
+		}
+	}
+	out.WriteString(html.EscapeString(text[last:]))
+	return out.String()
+}
+
+// xliffToAnchors returns the XLIFF content as text of a segment.
+func xliffToAnchors(content string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader("<content>" + content + "</content>"))
+	var out strings.Builder
+	var open []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return out.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch token := token.(type) {
+		case xml.CharData:
+			out.Write(token)
+		case xml.StartElement:
+			id := ""
+			for _, attribute := range token.Attr {
+				if attribute.Name.Local == "id" {
+					id = attribute.Value
+				}
+			}
+			switch token.Name.Local {
+			case "content":
+			case "g":
+				out.WriteString("<" + id + ">")
+				open = append(open, id)
+			case "x":
+				out.WriteString("<" + id + "/>")
+			default:
+				return "", fmt.Errorf("unsupported element <%s>", token.Name.Local)
+			}
+		case xml.EndElement:
+			if token.Name.Local == "g" && len(open) > 0 {
+				out.WriteString("</" + open[len(open)-1] + ">")
+				open = open[:len(open)-1]
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_ExtractTranslations(t *testing.T) {
+	body := `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:r><w:t>{na</w:t></w:r>` +
+		`<w:proofErr w:type="spellStart"/><w:r><w:rPr><w:b/></w:rPr><w:t>me}</w:t></w:r><w:r><w:t>,</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{total}</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">Please pay </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>now</w:t></w:r>` +
+		`<w:r><w:tab/></w:r><w:r><w:t>{total}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:sectPr/>`
+	template := buildTestDocx(t, body)
+	doc, err := OpenBytes(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	translations, err := doc.ExtractTranslations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TranslationSegment{
+		{ID: DocumentXml + "#0", Source: "Dear {name},"},
+		{ID: DocumentXml + "#2", Source: "Please pay <1>now</1><2/>{total}"},
+	}
+	if !reflect.DeepEqual(translations.Segments, expected) {
+		t.Fatalf("expected %+v, have %+v", expected, translations.Segments)
+	}
+
+	translations.Segments[0].Target = "Liebe(r) {name},"
+	translations.Segments[1].Target = "Bitte <1>sofort</1> zahlen:<2/>{total}"
+	translated, err := TranslateTemplate(template, translations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, translated, DocumentXml)
+	for _, expected := range []string{
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">Liebe(r) {name},</w:t></w:r></w:p>`,
+		`<w:p><w:r><w:t xml:space="preserve">Bitte </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">sofort</w:t></w:r>` +
+			`<w:r><w:t xml:space="preserve"> zahlen:</w:t></w:r><w:r><w:tab/></w:r><w:r><w:t xml:space="preserve">{total}</w:t></w:r></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in %s", expected, document)
+		}
+	}
+
+	for _, target := range []string{
+		"Bitte sofort zahlen:<2/>",
+		"Bitte sofort zahlen:{total}",
+		"Bitte <1>sofort zahlen:<2/>{total}",
+		"Bitte <3>sofort</3> zahlen:<2/>{total}",
+		"Bitte sofort zahlen:<2/><2/>{total}",
+	} {
+		translations.Segments[1].Target = target
+		if _, err := TranslateTemplate(template, translations); !errors.Is(err, ErrTranslationMismatch) {
+			t.Errorf("%s: expected ErrTranslationMismatch, have %v", target, err)
+		}
+	}
+	translations.Segments[1].Target = ""
+	translations.Segments[1].Source = "Please pay now"
+	if _, err := TranslateTemplate(template, translations); !errors.Is(err, ErrTranslationMismatch) {
+		t.Errorf("expected ErrTranslationMismatch for changed source, have %v", err)
+	}
+}
+
+func TestTranslations_XLIFF(t *testing.T) {
+	translations := &Translations{SourceLanguage: "en", TargetLanguage: "de", Segments: []TranslationSegment{
+		{ID: DocumentXml + "#0", Source: "Dear {name} & <1>friends</1>"},
+		{ID: DocumentXml + "#2", Source: "Pay <1>now</1><2/>{total}", Target: "Bitte <1>sofort</1> zahlen<2/>{total}"},
+	}}
+	var buf bytes.Buffer
+	if err := translations.WriteXLIFF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<file original="word/document.xml" source-language="en" target-language="de" datatype="x-docx">`,
+		`<source>Dear {name} &amp; <g id="1">friends</g></source>`,
+		`<target>Bitte <g id="1">sofort</g> zahlen<x id="2"/>{total}</target>`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %s in %s", expected, buf.String())
+		}
+	}
+	read, err := ReadXLIFF(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, translations) {
+		t.Errorf("expected %+v, have %+v", translations, read)
+	}
+	if _, err := ReadXLIFF(strings.NewReader(`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2"><file><body>` +
+		`<trans-unit id="1"><source>a <bpt id="1">b</bpt></source></trans-unit></body></file></xliff>`)); err == nil {
+		t.Error("expected error for unsupported inline element")
+	}
+}
@@ -0,0 +1,149 @@
+package docx
+
+import (
+	"reflect"
+	"sort"
+)
+
+// UnusedFields returns the fields of the data which are never referenced by the template given by input, sorted by
+// path, e.g. to trim bloated payloads or to notice fields which were renamed in the data but not in the template.
+// The syntax of the template is detected like Render does:
+//   - for placeholders, these are the keys of the map without placeholder in the document, e.g. 'signature'.
+//   - for template actions, these are the paths of the fields of maps and structs which no action accesses, e.g.
+//     'Customer.Email'. Fields of list items are given without index, e.g. 'Items.SKU'. Values written by an action
+//     or passed to a function are used completely, including their fields.
+//
+// Fields which are only used by custom template functions, e.g. by reading them from the data passed to the
+// function, cannot be detected. See WithUnusedFields to report the fields after every render.
+//
+// Example:
+//
+//	unused, err := docx.UnusedFields(templateBytes, invoice)
+//	if len(unused) > 0 {
+//	    log.Printf("template does not use %v", unused)
+//	}
+func UnusedFields(input []byte, data interface{}) ([]string, error) {
+	syntax, err := detectSyntax(input)
+	if err != nil {
+		return nil, err
+	}
+	return unusedFields(input, data, syntax)
+}
+
+// WithUnusedFields calls report with the unused fields of the data after the template was rendered successfully,
+// see UnusedFields. The fields are not determined if the render fails.
+func WithUnusedFields(report func(fields []string)) Option {
+	return WithMiddleware(func(next RenderFunc) RenderFunc {
+		return func(request *RenderRequest) ([]byte, error) {
+			output, err := next(request)
+			if err != nil {
+				return nil, err
+			}
+			syntax := request.Syntax
+			if syntax == SyntaxAuto {
+				if syntax, err = detectSyntax(request.Template); err != nil {
+					return nil, err
+				}
+			}
+			fields, err := unusedFields(request.Template, request.Data, syntax)
+			if err != nil {
+				return nil, err
+			}
+			report(fields)
+			return output, nil
+		}
+	})
+}
+
+// unusedFields returns the unused fields of the data for a template of the given syntax.
+func unusedFields(input []byte, data interface{}, syntax Syntax) ([]string, error) {
+	var unused []string
+	if syntax != SyntaxTemplate {
+		values, err := placeholderValues(data)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := OpenBytes(input)
+		if err != nil {
+			return nil, err
+		}
+		defer doc.Close()
+		placeholders, err := doc.UnresolvedPlaceholders()
+		if err != nil {
+			return nil, err
+		}
+		used := make(map[string]bool, len(placeholders))
+		for _, placeholder := range placeholders {
+			used[placeholder.Key] = true
+		}
+		for key := range values {
+			if !used[key] {
+				unused = append(unused, key)
+			}
+		}
+		sort.Strings(unused)
+		return unused, nil
+	}
+
+	root, err := inferTemplateData(input)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	collectUnusedFields(reflect.ValueOf(data), root, "", seen, &unused)
+	sort.Strings(unused)
+	return unused, nil
+}
+
+// collectUnusedFields adds the paths of the fields of value which are not accessed according to its shape to
+// unused, seen holds the paths which were added already.
+func collectUnusedFields(value reflect.Value, shape *sampleField, path string, seen map[string]bool, unused *[]string) {
+	if shape == nil {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			*unused = append(*unused, path)
+		}
+		return
+	}
+	// values which are written or passed to functions are used with all their fields
+	if shape.output || shape.argument || len(shape.fields) == 0 && shape.element == nil {
+		return
+	}
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	child := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			name := iter.Key().String()
+			collectUnusedFields(iter.Value(), shape.fields[name], child(name), seen, unused)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				collectUnusedFields(value.Field(i), shape.fields[field.Name], child(field.Name), seen, unused)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if shape.element == nil {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			collectUnusedFields(value.Index(i), shape.element, path, seen, unused)
+		}
+	}
+}
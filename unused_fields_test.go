@@ -0,0 +1,55 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnusedFields_Template(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:t>Invoice {{.Number}} for {{.Customer.Name}}{{if .Paid}} (paid){{end}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{range .Items}}{{.Description}} {{.Price}}{{end}} {{.Address}}</w:t></w:r></w:p>`)
+
+	type item struct {
+		Description string
+		Price       float64
+		SKU         string
+	}
+	type address struct{ City, Zip string }
+	data := map[string]interface{}{
+		"Number":   "INV-1",
+		"Customer": map[string]interface{}{"Name": "Jane", "Email": "jane@example.com"},
+		"Paid":     true,
+		"Items":    []item{{"Pen", 1.5, "P-1"}, {"Ink", 3, "I-1"}},
+		"Address":  &address{"Springfield", "12345"},
+		"Notes":    "internal",
+	}
+	unused, err := UnusedFields(input, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Customer.Email", "Items.SKU", "Notes"}; !reflect.DeepEqual(unused, expected) {
+		t.Errorf("expected unused fields %v, have %v", expected, unused)
+	}
+}
+
+func TestUnusedFields_Placeholders(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Dear {name}, your order {order}</w:t></w:r></w:p>`)
+	unused, err := UnusedFields(input, PlaceholderMap{"name": "Jane", "order": 42, "signature": "J. Doe", "city": "Springfield"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"city", "signature"}; !reflect.DeepEqual(unused, expected) {
+		t.Errorf("expected unused fields %v, have %v", expected, unused)
+	}
+
+	var reported []string
+	if _, err := Render(FromBytes(input), map[string]string{"name": "Jane", "order": "42", "nmae": "typo"}, WithUnusedFields(func(fields []string) {
+		reported = fields
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"nmae"}; !reflect.DeepEqual(reported, expected) {
+		t.Errorf("expected reported fields %v, have %v", expected, reported)
+	}
+}
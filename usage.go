@@ -0,0 +1,225 @@
+package docx
+
+import (
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// UsageReport describes which placeholders are used by a set of templates.
+type UsageReport struct {
+	// Templates is the amount of analyzed templates.
+	Templates int
+	// Placeholders lists the usage of every placeholder found in any template, sorted by key.
+	Placeholders []PlaceholderUsage
+	// Errors maps the index of every template which could not be analyzed to the error.
+	Errors map[int]error
+}
+
+// PlaceholderUsage describes the usage of a single placeholder across templates.
+type PlaceholderUsage struct {
+	// Key is the placeholder key without delimiters. Fields of template actions (see ProcessTemplateDocx) are
+	// reported with their path from the root of the data, e.g. '.Customer.Name' for {{.Customer.Name}} and
+	// '.Items.Qty' for {{.Qty}} inside of {{range .Items}}.
+	Key string
+	// Occurrences is the total amount of occurrences in all templates.
+	Occurrences int
+	// Templates maps the index of every template which uses the placeholder to the amount of occurrences in it.
+	Templates map[int]int
+}
+
+// AnalyzeUsage aggregates which placeholders and fields of template actions appear in which of the given templates
+// and how often.
+// The templates are identified by their index in the given slice. Templates which cannot be opened
+// are reported in UsageReport.Errors and do not prevent the analysis of the remaining ones.
+//
+// Example:
+//
+//	report := docx.AnalyzeUsage([][]byte{invoiceTemplate, reminderTemplate})
+//	for _, usage := range report.Placeholders {
+//	    fmt.Printf("%s is used %d times in %d templates\n", usage.Key, usage.Occurrences, len(usage.Templates))
+//	}
+func AnalyzeUsage(templates [][]byte) UsageReport {
+	report := UsageReport{
+		Templates: len(templates),
+		Errors:    make(map[int]error),
+	}
+
+	usages := make(map[string]*PlaceholderUsage)
+	for index, template := range templates {
+		keys, err := templatePlaceholders(template)
+		if err != nil {
+			report.Errors[index] = err
+			continue
+		}
+		for _, key := range keys {
+			usage, exists := usages[key]
+			if !exists {
+				usage = &PlaceholderUsage{Key: key, Templates: make(map[int]int)}
+				usages[key] = usage
+			}
+			usage.Occurrences++
+			usage.Templates[index]++
+		}
+	}
+
+	for _, usage := range usages {
+		report.Placeholders = append(report.Placeholders, *usage)
+	}
+	sort.Slice(report.Placeholders, func(i, j int) bool {
+		return report.Placeholders[i].Key < report.Placeholders[j].Key
+	})
+	return report
+}
+
+// templatePlaceholders returns the keys (without delimiters) of all placeholder occurrences inside the template,
+// followed by the paths of all fields accessed by its template actions.
+func templatePlaceholders(template []byte) ([]string, error) {
+	doc, err := OpenBytes(template)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	placeholders, err := doc.GetPlaceHoldersList()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(placeholders))
+	for i, placeholder := range placeholders {
+		keys[i] = RemovePlaceholderDelimiter(placeholder)
+	}
+
+	err = parseTemplateParts(template, func(tree *parse.Tree, trees map[string]*parse.Tree) {
+		root := ""
+		walker := &usageWalker{trees: trees, expanding: make(map[string]bool)}
+		walker.walk(tree.Root, &root, map[string]*string{"$": &root})
+		keys = append(keys, walker.fields...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// usageWalker collects the fields accessed by the template actions of a parse tree. The paths of the values are
+// tracked like the shapes of sampleInferrer, nil is a value which is not a field of the data, e.g. a function result.
+type usageWalker struct {
+	// trees are the templates defined by the part, e.g. with {{define}} or {{block}}
+	trees map[string]*parse.Tree
+	// expanding are the names of the templates which are currently walked, to stop recursive templates
+	expanding map[string]bool
+	// fields are the paths of all accessed fields, once per access
+	fields []string
+}
+
+// walk collects the fields accessed by the node, dot is the path of {{.}}.
+func (u *usageWalker) walk(node parse.Node, dot *string, vars map[string]*string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		vars = copyUsageVars(vars)
+		for _, child := range n.Nodes {
+			u.walk(child, dot, vars)
+		}
+	case *parse.ActionNode:
+		value := u.pipe(n.Pipe, dot, vars)
+		for _, variable := range n.Pipe.Decl {
+			vars[variable.Ident[0]] = value
+		}
+	case *parse.IfNode:
+		u.pipe(n.Pipe, dot, vars)
+		u.walk(n.List, dot, vars)
+		u.walk(n.ElseList, dot, vars)
+	case *parse.WithNode:
+		value := u.pipe(n.Pipe, dot, vars)
+		inner := copyUsageVars(vars)
+		for _, variable := range n.Pipe.Decl {
+			inner[variable.Ident[0]] = value
+		}
+		u.walk(n.List, value, inner)
+		u.walk(n.ElseList, dot, vars)
+	case *parse.RangeNode:
+		// the fields of the elements are reported below the path of the collection
+		element := u.pipe(n.Pipe, dot, vars)
+		inner := copyUsageVars(vars)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			inner[n.Pipe.Decl[0].Ident[0]] = element
+		case 2:
+			inner[n.Pipe.Decl[0].Ident[0]] = nil
+			inner[n.Pipe.Decl[1].Ident[0]] = element
+		}
+		u.walk(n.List, element, inner)
+		u.walk(n.ElseList, dot, vars)
+	case *parse.TemplateNode:
+		tree, defined := u.trees[n.Name]
+		if !defined || u.expanding[n.Name] {
+			return
+		}
+		var value *string
+		if n.Pipe != nil {
+			value = u.pipe(n.Pipe, dot, vars)
+		}
+		u.expanding[n.Name] = true
+		u.walk(tree.Root, value, map[string]*string{"$": value})
+		delete(u.expanding, n.Name)
+	}
+}
+
+// pipe collects the fields accessed by the pipeline and returns the path of its value, nil if it is not a field.
+func (u *usageWalker) pipe(pipe *parse.PipeNode, dot *string, vars map[string]*string) *string {
+	if pipe == nil {
+		return nil
+	}
+	var value *string
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			field := u.operand(arg, dot, vars)
+			if field != nil && len(pipe.Cmds) == 1 && len(cmd.Args) == 1 {
+				value = field
+			}
+		}
+	}
+	return value
+}
+
+// operand collects the fields accessed by the operand and returns the path of its value, nil if it is not a field.
+func (u *usageWalker) operand(node parse.Node, dot *string, vars map[string]*string) *string {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return u.field(dot, n.Ident)
+	case *parse.VariableNode:
+		return u.field(vars[n.Ident[0]], n.Ident[1:])
+	case *parse.ChainNode:
+		if pipe, isPipe := n.Node.(*parse.PipeNode); isPipe {
+			return u.field(u.pipe(pipe, dot, vars), n.Field)
+		}
+	case *parse.PipeNode:
+		return u.pipe(n, dot, vars)
+	}
+	return nil
+}
+
+// field records the access of the field at the path below the value and returns its path.
+func (u *usageWalker) field(value *string, path []string) *string {
+	if value == nil || len(path) == 0 {
+		return value
+	}
+	field := *value + "." + strings.Join(path, ".")
+	u.fields = append(u.fields, field)
+	return &field
+}
+
+// copyUsageVars returns a copy of the variables, so declarations inside a block are not visible outside.
+func copyUsageVars(vars map[string]*string) map[string]*string {
+	copied := make(map[string]*string, len(vars))
+	for name, value := range vars {
+		copied[name] = value
+	}
+	return copied
+}
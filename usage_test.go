@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeUsage(t *testing.T) {
+	templates := [][]byte{
+		buildTestDocx(t, `<w:p><w:r><w:t>{name} {name} {total}</w:t></w:r></w:p>`),
+		[]byte("invalid"),
+		buildTestDocx(t, `<w:p><w:r><w:t>{na</w:t></w:r><w:r><w:t>me}</w:t></w:r><w:r><w:t> {date}</w:t></w:r></w:p>`),
+	}
+
+	report := AnalyzeUsage(templates)
+
+	expected := []PlaceholderUsage{
+		{Key: "date", Occurrences: 1, Templates: map[int]int{2: 1}},
+		{Key: "name", Occurrences: 3, Templates: map[int]int{0: 2, 2: 1}},
+		{Key: "total", Occurrences: 1, Templates: map[int]int{0: 1}},
+	}
+	if !reflect.DeepEqual(report.Placeholders, expected) {
+		t.Errorf("unexpected usage, want=%+v, have=%+v", expected, report.Placeholders)
+	}
+	if report.Templates != 3 {
+		t.Errorf("expected 3 templates, have %d", report.Templates)
+	}
+	if _, failed := report.Errors[1]; !failed || len(report.Errors) != 1 {
+		t.Errorf("expected an error for template 1 only, have %v", report.Errors)
+	}
+}
+
+func TestAnalyzeUsage_TemplateFields(t *testing.T) {
+	templates := [][]byte{
+		buildTestDocx(t, `<w:p><w:r><w:t>{{.Customer.Name}} {{upper .Customer.Name}} {{range $item := .Items}}{{.Qty}} {{$item.Price}}{{end}} {{if .Paid}}{{$.Total}}{{end}}</w:t></w:r></w:p>`),
+		buildTestDocx(t, `<w:p><w:r><w:t>{{with .Customer}}{{.Name}}{{end}}</w:t></w:r></w:p>`),
+	}
+
+	report := AnalyzeUsage(templates)
+
+	expected := []PlaceholderUsage{
+		{Key: ".Customer", Occurrences: 1, Templates: map[int]int{1: 1}},
+		{Key: ".Customer.Name", Occurrences: 3, Templates: map[int]int{0: 2, 1: 1}},
+		{Key: ".Items", Occurrences: 1, Templates: map[int]int{0: 1}},
+		{Key: ".Items.Price", Occurrences: 1, Templates: map[int]int{0: 1}},
+		{Key: ".Items.Qty", Occurrences: 1, Templates: map[int]int{0: 1}},
+		{Key: ".Paid", Occurrences: 1, Templates: map[int]int{0: 1}},
+		{Key: ".Total", Occurrences: 1, Templates: map[int]int{0: 1}},
+	}
+	if !reflect.DeepEqual(report.Placeholders, expected) {
+		t.Errorf("unexpected usage, want=%+v, have=%+v", expected, report.Placeholders)
+	}
+	if len(report.Errors) != 0 {
+		t.Errorf("unexpected errors %v", report.Errors)
+	}
+}
@@ -0,0 +1,190 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// inlineValue is implemented by replacement values which render their own runs (<w:r>) instead of plain text,
+// e.g. images or hyperlinks. Such values may be used inside a PlaceholderMap just like strings.
+type inlineValue interface {
+	// inlineXml returns the runs which are inserted at the position of the placeholder.
+	inlineXml(ctx *valueContext) (string, error)
+}
+
+// valueContext describes the position at which a replacement value is inserted.
+type valueContext struct {
+	doc *Document
+	// part is the name of the file in which the placeholder is located, e.g. 'word/document.xml'.
+	part string
+	// runProperties are the properties of the run in which the placeholder is located.
+	runProperties string
+	// paragraphProperties are the properties of the paragraph in which the placeholder is located.
+	// They are only set if values may start new paragraphs, see NewlineParagraph.
+	paragraphProperties string
+	// sectionProperties are the properties (<w:sectPr>...</w:sectPr>) of the section in which the placeholder is
+	// located. They are only set for values which insert paragraphs into the document body.
+	sectionProperties string
+}
+
+// paragraphPropertiesAt returns the properties (<w:pPr>...</w:pPr>) of the paragraph which contains the position.
+func paragraphPropertiesAt(data []byte, pos int64) string {
+	start := -1
+	for _, openTag := range []string{"<w:p>", "<w:p "} {
+		start = max(start, bytes.LastIndex(data[:pos], []byte(openTag)))
+	}
+	if start < 0 {
+		return ""
+	}
+	return string(paragraphPropertiesRegex.FindSubmatch(data[start:])[2])
+}
+
+// textRun returns a run containing the given, already escaped, text which uses the properties of the placeholder run.
+func (ctx *valueContext) textRun(textXml string) string {
+	return fmt.Sprintf(`<w:r>%s<w:t xml:space="preserve">%s</w:t></w:r>`, ctx.runProperties, textXml)
+}
+
+// valueXml returns the runs of any replacement value. Values which render their own runs are rendered as is,
+// all other values are inserted as text according to the text policy of the document. Nil values are empty.
+func (ctx *valueContext) valueXml(value interface{}) (string, error) {
+	if rich, isRich := value.(inlineValue); isRich {
+		return rich.inlineXml(ctx)
+	}
+	if value == nil {
+		return "", nil
+	}
+	policy := ctx.doc.textPolicy
+	return policy.textRuns(ctx, policy.apply(normalizeText(fmt.Sprint(value)))), nil
+}
+
+// replaceValue replaces all occurrences of the key inside file using the given replacer.
+// Plain values are inserted as text into the run of the placeholder. Values which render their own runs
+// and values which are subject to document level settings (e.g. author attribution or emoji fonts) are inserted
+// by splitting the run of the placeholder.
+func (d *Document) replaceValue(replacer *Replacer, file, key string, value interface{}) error {
+	author, attributed := d.authors[RemovePlaceholderDelimiter(key)]
+	if !attributed && d.trackReplacements {
+		author, attributed = d.trackAuthor, true
+	}
+	_, isRich := value.(inlineValue)
+	limit, limited := d.lengthLimits[RemovePlaceholderDelimiter(key)]
+	shrinks := limited && limit.Policy == LengthShrink
+	var text string
+	if !isRich {
+		text = d.textPolicy.apply(normalizeText(fmt.Sprint(value)))
+		if !shrinks {
+			var err error
+			if text, _, err = d.limitLength(key, text, ""); err != nil {
+				return err
+			}
+		}
+	}
+	if !attributed && !isRich && !shrinks && !d.textPolicy.splitsRuns(text) {
+		err := replacer.Replace(key, text)
+		if err == nil {
+			d.recordValueChecksum(key, text)
+		}
+		return err
+	}
+
+	var renderErr error
+	rendered, isRendered := "", false
+	err := replacer.ReplaceFunc(key, func(run *Run) string {
+		if run.isDrawingML(replacer.document) {
+			drawingText, err := d.drawingText(key, value, text)
+			if err != nil {
+				if renderErr == nil {
+					renderErr = err
+				}
+				return ""
+			}
+			return escapeDrawingText(drawingText, replacer.RunProperties(run))
+		}
+
+		ctx := &valueContext{
+			doc:           d,
+			part:          file,
+			runProperties: replacer.RunProperties(run),
+		}
+		if d.textPolicy.Newlines == NewlineParagraph {
+			ctx.paragraphProperties = replacer.ParagraphProperties(run)
+		}
+
+		runProperties := ctx.runProperties
+
+		var runsXml string
+		var err error
+		if isRich || value == nil {
+			runsXml, err = ctx.valueXml(value)
+		} else {
+			limitedText, size := text, 0
+			if shrinks {
+				limitedText, size, err = d.limitLength(key, text, runProperties)
+			}
+			if size > 0 {
+				ctx.runProperties = setRunProperty(ctx.runProperties, "sz", fmt.Sprintf(`<w:sz w:val="%d"/>`, size))
+				ctx.runProperties = setRunProperty(ctx.runProperties, "szCs", fmt.Sprintf(`<w:szCs w:val="%d"/>`, size))
+			}
+			runsXml = d.textPolicy.textRuns(ctx, limitedText)
+			if !isRendered {
+				rendered, isRendered = limitedText, true
+			}
+		}
+		if err != nil {
+			if renderErr == nil {
+				renderErr = err
+			}
+			return ""
+		}
+
+		// the checksum of values which render their own runs covers their text
+		if !isRendered && d.valueChecksums != nil {
+			rendered, isRendered = elementText([]byte(runsXml)), true
+		}
+		if attributed {
+			if blockMarkerRegex.MatchString(runsXml) {
+				// paragraphs and tables are tracked once they are inserted, see insertBlocks
+				d.blocks[len(d.blocks)-1].author = &author
+			} else {
+				runsXml = d.trackedInsertion(author, runsXml)
+			}
+		}
+		return splitRun(runProperties, runsXml)
+	})
+	if renderErr != nil {
+		return fmt.Errorf("unable to render value of %s: %w", key, renderErr)
+	}
+	if err == nil && isRendered {
+		d.recordValueChecksum(key, rendered)
+	}
+	return err
+}
+
+// drawingText returns the text which replaces the key inside a DrawingML run (text boxes and shapes), which can only
+// contain plain text. Values which render their own runs are inserted by their String method, if any.
+// Length limits which shrink the font size truncate the text instead.
+func (d *Document) drawingText(key string, value interface{}, text string) (string, error) {
+	if _, isRich := value.(inlineValue); isRich {
+		stringer, isStringer := value.(fmt.Stringer)
+		if !isStringer {
+			return "", fmt.Errorf("%T cannot be inserted into the DrawingML text of a shape", value)
+		}
+		text = d.textPolicy.apply(normalizeText(stringer.String()))
+	}
+	limit, limited := d.lengthLimits[RemovePlaceholderDelimiter(key)]
+	if limited && limit.Policy == LengthShrink {
+		if utf8.RuneCountInString(text) > limit.Max {
+			text = truncateText(text, limit.Max, limit.Ellipsis)
+		}
+		return text, nil
+	}
+	text, _, err := d.limitLength(key, text, "")
+	return text, err
+}
+
+// splitRun returns the XML which is inserted into the text of a run in order to place the given runs
+// at that position. The surrounding run is closed and re-opened using the given runProperties.
+func splitRun(runProperties, runsXml string) string {
+	return fmt.Sprintf(`</w:t></w:r>%s<w:r>%s<w:t xml:space="preserve">`, runsXml, runProperties)
+}
@@ -0,0 +1,217 @@
+package docx
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// RelationshipTypeCustomXml is the relationship type of custom XML data parts.
+	RelationshipTypeCustomXml = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	// RelationshipTypeCustomXmlProps is the relationship type of the properties of a custom XML data part.
+	RelationshipTypeCustomXmlProps = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps"
+	// ContentTypeCustomXmlProperties is the content type of the properties of a custom XML data part.
+	ContentTypeCustomXmlProperties = "application/vnd.openxmlformats-officedocument.customXmlProperties+xml"
+
+	// ValueChecksumNamespace is the namespace of the custom XML data part holding the value checksums.
+	ValueChecksumNamespace = "urn:go-docx:value-checksums"
+)
+
+var (
+	// valueChecksumsRegex matches the root element of the value checksums part and captures the algorithm.
+	valueChecksumsRegex = regexp.MustCompile(`<valueChecksums\s[^>]*?algorithm="([^"]*)"`)
+	// valueChecksumRegex matches the checksum of a single value and captures the key and the checksum.
+	valueChecksumRegex = regexp.MustCompile(`<value\s+key="([^"]*)"\s+checksum="([0-9a-f]*)"\s*/>`)
+)
+
+// RecordValueChecksums records a checksum of the rendered text of each replaced placeholder. The checksums are
+// stored in a hidden custom XML data part when the document is written, so audits can later prove which values
+// were present when the document was generated, even if the visible text was edited afterwards.
+// With a secret, the checksums are HMAC-SHA256 signatures which cannot be forged without the secret; otherwise they
+// are plain SHA-256 hashes. It must be called before ReplaceAll or Replace.
+//
+// Example:
+//
+//	doc.RecordValueChecksums(signingKey)
+//	doc.ReplaceAll(values)
+//	doc.WriteToFile("contract.docx")
+//
+//	// later on
+//	valid, err := generated.VerifyValueChecksum(signingKey, "amount", "1,250.00 EUR")
+func (d *Document) RecordValueChecksums(secret []byte) {
+	d.valueChecksumSecret = secret
+	if d.valueChecksums == nil {
+		d.valueChecksums = make(map[string]string)
+	}
+}
+
+// recordValueChecksum records the checksum of the rendered text of the placeholder key, if checksums are recorded.
+func (d *Document) recordValueChecksum(key, text string) {
+	if d.valueChecksums != nil {
+		d.valueChecksums[RemovePlaceholderDelimiter(key)] = valueChecksum(d.valueChecksumSecret, key, text)
+	}
+}
+
+// valueChecksum returns the hex encoded checksum of the text of the placeholder key.
+// The key is part of the checksum, so checksums cannot be swapped between values.
+func valueChecksum(secret []byte, key, text string) string {
+	message := RemovePlaceholderDelimiter(key) + "\x00" + normalizeText(text)
+	if len(secret) == 0 {
+		digest := sha256.Sum256([]byte(message))
+		return hex.EncodeToString(digest[:])
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// valueChecksumAlgorithm returns the name of the checksum algorithm used with the secret.
+func valueChecksumAlgorithm(secret []byte) string {
+	if len(secret) == 0 {
+		return "SHA-256"
+	}
+	return "HMAC-SHA256"
+}
+
+// valueChecksumPart returns the name and the content of the custom XML data part holding the value checksums.
+// The name is empty if the document does not have such a part.
+func (d *Document) valueChecksumPart() (string, []byte, error) {
+	rels, exists, err := d.part(relationshipsPart(DocumentXml))
+	if err != nil || !exists {
+		return "", nil, err
+	}
+	for _, rel := range parseRelationships(rels) {
+		if rel.relType != RelationshipTypeCustomXml || rel.external {
+			continue
+		}
+		name := rel.partName(DocumentXml)
+		data, exists, err := d.part(name)
+		if err != nil {
+			return "", nil, err
+		}
+		if exists && valueChecksumsRegex.Match(data) && strings.Contains(string(data), ValueChecksumNamespace) {
+			return name, data, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// ValueChecksums returns the value checksums stored in the document keyed by the placeholder keys (without
+// delimiters), and the name of their algorithm, see RecordValueChecksums. The map is empty if none are stored.
+func (d *Document) ValueChecksums() (map[string]string, string, error) {
+	_, data, err := d.valueChecksumPart()
+	if err != nil {
+		return nil, "", err
+	}
+	checksums := make(map[string]string)
+	for _, match := range valueChecksumRegex.FindAllSubmatch(data, -1) {
+		checksums[html.UnescapeString(string(match[1]))] = string(match[2])
+	}
+	algorithm := ""
+	if match := valueChecksumsRegex.FindSubmatch(data); match != nil {
+		algorithm = string(match[1])
+	}
+	return checksums, algorithm, nil
+}
+
+// VerifyValueChecksum returns true if the text is the rendered text of the placeholder key which was recorded when
+// the document was generated, see RecordValueChecksums. The secret must be the one used for recording.
+// An error is returned if the document has no checksum of the key.
+func (d *Document) VerifyValueChecksum(secret []byte, key, text string) (bool, error) {
+	checksums, algorithm, err := d.ValueChecksums()
+	if err != nil {
+		return false, err
+	}
+	checksum, exists := checksums[RemovePlaceholderDelimiter(key)]
+	if !exists {
+		return false, fmt.Errorf("no value checksum of %s", key)
+	}
+	if algorithm != valueChecksumAlgorithm(secret) {
+		return false, fmt.Errorf("value checksums use %s, not %s", algorithm, valueChecksumAlgorithm(secret))
+	}
+	return hmac.Equal([]byte(checksum), []byte(valueChecksum(secret, key, text))), nil
+}
+
+// storeValueChecksums writes the recorded value checksums into the custom XML data part, which is created if the
+// document does not have one yet. Checksums of keys which were not replaced again are kept.
+func (d *Document) storeValueChecksums() error {
+	if len(d.valueChecksums) == 0 {
+		return nil
+	}
+	name, _, err := d.valueChecksumPart()
+	if err != nil {
+		return err
+	}
+	checksums, algorithm, err := d.ValueChecksums()
+	if err != nil {
+		return err
+	}
+	if algorithm != valueChecksumAlgorithm(d.valueChecksumSecret) {
+		checksums = make(map[string]string)
+	}
+	for key, checksum := range d.valueChecksums {
+		checksums[key] = checksum
+	}
+
+	keys := make([]string, 0, len(checksums))
+	for key := range checksums {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out strings.Builder
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	fmt.Fprintf(&out, `<valueChecksums xmlns="%s" algorithm="%s">`, ValueChecksumNamespace, valueChecksumAlgorithm(d.valueChecksumSecret))
+	for _, key := range keys {
+		fmt.Fprintf(&out, `<value key="%s" checksum="%s"/>`, html.EscapeString(key), checksums[key])
+	}
+	out.WriteString(`</valueChecksums>`)
+
+	if name == "" {
+		if name, err = d.addValueChecksumPart(); err != nil {
+			return err
+		}
+	}
+	return d.setPart(name, []byte(out.String()))
+}
+
+// addValueChecksumPart adds a new custom XML data part with its properties and returns its name.
+func (d *Document) addValueChecksumPart() (string, error) {
+	number := 1
+	for d.hasPart(fmt.Sprintf("customXml/item%d.xml", number)) || d.hasPart(fmt.Sprintf("customXml/itemProps%d.xml", number)) {
+		number++
+	}
+	name, propsName := fmt.Sprintf("customXml/item%d.xml", number), fmt.Sprintf("customXml/itemProps%d.xml", number)
+
+	var guid [16]byte
+	if _, err := rand.Read(guid[:]); err != nil {
+		return "", fmt.Errorf("unable to generate custom XML item ID: %w", err)
+	}
+	digits := strings.ToUpper(hex.EncodeToString(guid[:]))
+	itemId := "{" + digits[0:8] + "-" + digits[8:12] + "-" + digits[12:16] + "-" + digits[16:20] + "-" + digits[20:] + "}"
+	props := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+		`<ds:datastoreItem ds:itemID="` + itemId + `" xmlns:ds="http://schemas.openxmlformats.org/officeDocument/2006/customXml">` +
+		`<ds:schemaRefs><ds:schemaRef ds:uri="` + ValueChecksumNamespace + `"/></ds:schemaRefs></ds:datastoreItem>`
+	if err := d.setPart(propsName, []byte(props)); err != nil {
+		return "", err
+	}
+	if err := d.ensureContentTypeOverride(propsName, ContentTypeCustomXmlProperties); err != nil {
+		return "", err
+	}
+	if err := d.ensureContentTypeDefault("xml", "application/xml"); err != nil {
+		return "", err
+	}
+	if _, err := d.addRelationship(name, RelationshipTypeCustomXmlProps, fmt.Sprintf("itemProps%d.xml", number), false); err != nil {
+		return "", err
+	}
+	if _, err := d.addRelationship(DocumentXml, RelationshipTypeCustomXml, "../"+name, false); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,116 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDocument_RecordValueChecksums(t *testing.T) {
+	secret := []byte("signing key")
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>Pay {amount} to {name} via {link}</w:t></w:r></w:p><w:p><w:r><w:t>{unrecorded}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+
+	if err := doc.Replace("unrecorded", "before recording"); err != nil {
+		t.Fatal(err)
+	}
+	doc.RecordValueChecksums(secret)
+	err = doc.ReplaceAll(PlaceholderMap{
+		"amount": "1,250.00 EUR",
+		"name":   "Jane <Doe>",
+		"link":   Hyperlink{Text: "the portal", URL: "https://example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if item := readTestPart(t, buf.Bytes(), "customXml/item1.xml"); !strings.Contains(item, `<valueChecksums xmlns="`+ValueChecksumNamespace+`" algorithm="HMAC-SHA256">`+
+		`<value key="amount" checksum="`+valueChecksum(secret, "amount", "1,250.00 EUR")+`"/>`) {
+		t.Errorf("unexpected value checksums: %s", item)
+	}
+	if rels := readTestPart(t, buf.Bytes(), "word/_rels/document.xml.rels"); !strings.Contains(rels, `Type="`+RelationshipTypeCustomXml+`" Target="../customXml/item1.xml"`) {
+		t.Errorf("expected the relationship of the custom XML part: %s", rels)
+	}
+	if rels := readTestPart(t, buf.Bytes(), "customXml/_rels/item1.xml.rels"); !strings.Contains(rels, `Target="itemProps1.xml"`) {
+		t.Errorf("expected the relationship of the custom XML properties: %s", rels)
+	}
+	if types := readTestPart(t, buf.Bytes(), ContentTypesXml); !strings.Contains(types, `PartName="/customXml/itemProps1.xml"`) {
+		t.Errorf("expected the content type of the custom XML properties: %s", types)
+	}
+
+	generated, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer generated.Close()
+	checksums, algorithm, err := generated.ValueChecksums()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checksums) != 3 || algorithm != "HMAC-SHA256" {
+		t.Errorf("expected checksums of the three replaced values, got %v (%s)", checksums, algorithm)
+	}
+	for key, text := range map[string]string{"amount": "1,250.00 EUR", "{name}": "Jane <Doe>", "link": "the portal"} {
+		if valid, err := generated.VerifyValueChecksum(secret, key, text); err != nil || !valid {
+			t.Errorf("expected %q to be the value of %s, got %v, %v", text, key, valid, err)
+		}
+	}
+	if valid, err := generated.VerifyValueChecksum(secret, "amount", "9,250.00 EUR"); err != nil || valid {
+		t.Errorf("expected an edited value to be detected, got %v, %v", valid, err)
+	}
+	if valid, err := generated.VerifyValueChecksum([]byte("other key"), "amount", "1,250.00 EUR"); err != nil || valid {
+		t.Errorf("expected another secret to fail, got %v, %v", valid, err)
+	}
+	if _, err := generated.VerifyValueChecksum(nil, "amount", "1,250.00 EUR"); err == nil {
+		t.Error("expected an error for a different algorithm")
+	}
+	if _, err := generated.VerifyValueChecksum(secret, "unrecorded", "before recording"); err == nil {
+		t.Error("expected an error for a value without checksum")
+	}
+}
+
+func TestDocument_RecordValueChecksumsKeepsExisting(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p><w:r><w:t>{first}</w:t></w:r></w:p><w:p><w:r><w:t>{second}</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	doc.RecordValueChecksums(nil)
+	if err := doc.Replace("first", "one"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// processing the generated document again adds the checksums to the existing part
+	generated, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer generated.Close()
+	generated.RecordValueChecksums(nil)
+	if err := generated.Replace("second", "two"); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := generated.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if parts, err := readArchiveParts(buf.Bytes(), func(name string) bool { return name == "customXml/item2.xml" }); err != nil || len(parts) > 0 {
+		t.Errorf("expected the existing part to be reused, %v", err)
+	}
+	item := readTestPart(t, buf.Bytes(), "customXml/item1.xml")
+	if !strings.Contains(item, `algorithm="SHA-256"><value key="first" checksum="`+valueChecksum(nil, "first", "one")+`"/>`+
+		`<value key="second" checksum="`+valueChecksum(nil, "second", "two")+`"/>`) {
+		t.Errorf("unexpected value checksums: %s", item)
+	}
+}
@@ -0,0 +1,171 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	// ErrUnsupportedValue is returned by ProcessValues with RenderOptions.StrictValues if a value is not a string.
+	ErrUnsupportedValue = errors.New("unsupported replacement value")
+
+	// dateLayouts are the layouts of dates per language, more specific tags (e.g. 'en-US') take precedence over
+	// the base language. Languages without a layout use ISO 8601.
+	dateLayouts = map[string]string{
+		"en-US": "1/2/2006",
+		"en":    "02/01/2006",
+		"de":    "02.01.2006",
+		"fr":    "02/01/2006",
+		"es":    "02/01/2006",
+		"it":    "02/01/2006",
+		"pt":    "02/01/2006",
+		"nl":    "02-01-2006",
+		"pl":    "02.01.2006",
+		"ru":    "02.01.2006",
+		"tr":    "02.01.2006",
+		"ja":    "2006/01/02",
+		"zh":    "2006/01/02",
+	}
+)
+
+// FormatValue formats a replacement value as text according to the locale (a BCP 47 tag such as 'de-DE'):
+//
+//   - integers are formatted without digit grouping, e.g. 10042
+//   - floating point numbers use the decimal and grouping separators of the locale and at most three fraction
+//     digits, e.g. 1.234,5 for 'de-DE'
+//   - dates (time.Time) use the short date of the locale, e.g. 31.12.2024 for 'de-DE' or 12/31/2024 for 'en-US',
+//     and the time of day is appended unless it is midnight
+//   - booleans are formatted as true and false
+//
+// Without locale, floating point numbers are formatted without grouping and dates as ISO 8601.
+// Types which implement fmt.Stringer and all other values are formatted with fmt.Sprint, nil is empty.
+func FormatValue(value interface{}, locale string) (string, error) {
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case time.Time:
+		return formatDate(v, tag, locale != ""), nil
+	case *time.Time:
+		if v == nil {
+			return "", nil
+		}
+		return formatDate(*v, tag, locale != ""), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		if locale == "" {
+			return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()), nil
+		}
+		return message.NewPrinter(tag).Sprint(number.Decimal(rv.Float())), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// formatDate formats the date using the short date layout of the language, or ISO 8601 if localized is false.
+func formatDate(date time.Time, tag language.Tag, localized bool) string {
+	layout, clock := shortDateLayout(tag, localized), " 15:04"
+	if region, _ := tag.Region(); localized && region.String() == "US" {
+		clock = " 3:04 PM"
+	}
+	if hour, minute, second := date.Clock(); hour != 0 || minute != 0 || second != 0 {
+		layout += clock
+	}
+	return date.Format(layout)
+}
+
+// shortDateLayout returns the short date layout of the language, or ISO 8601 if localized is false.
+func shortDateLayout(tag language.Tag, localized bool) string {
+	if localized {
+		if layout, ok := lookupLocale(dateLayouts, tag); ok {
+			return layout
+		}
+	}
+	return "2006-01-02"
+}
+
+// ProcessValues works like ProcessBytes but accepts values of any type: numbers, dates and booleans are formatted
+// according to the locale of the options (see FormatValue), values which render their own content (e.g. Image or
+// Hyperlink) are inserted as they are. With RenderOptions.StrictValues all values must be strings, just like for
+// ProcessBytes, and ErrUnsupportedValue is returned otherwise.
+//
+// The locale is also set as the default language of the document. Of the other options, only Cleanup,
+// Conformance and OutputLimits apply. The options override the package-level defaults, see SetDefaults. Render
+// offers the same for any source of the template.
+//
+// Example:
+//
+//	outputBytes, err := docx.ProcessValues(templateBytes, docx.PlaceholderMap{
+//	    "total": 1234.5,                                       // 1.234,5
+//	    "date":  time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), // 31.12.2024
+//	}, docx.RenderOptions{Locale: "de-DE"})
+func ProcessValues(input []byte, values PlaceholderMap, options RenderOptions) ([]byte, error) {
+	options = defaultRenderOptions().merge(&options)
+	formatted := make(PlaceholderMap, len(values))
+	for key, value := range values {
+		if _, isString := value.(string); enabled(options.StrictValues) && !isString {
+			return nil, fmt.Errorf("%w: %s is %T, not a string", ErrUnsupportedValue, key, value)
+		}
+		if _, isRich := value.(inlineValue); isRich {
+			formatted[key] = value
+			continue
+		}
+		text, err := FormatValue(value, options.Locale)
+		if err != nil {
+			return nil, err
+		}
+		formatted[key] = text
+	}
+
+	doc, err := OpenBytes(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document from bytes: %w", err)
+	}
+	defer doc.Close()
+
+	if err := doc.ReplaceAll(formatted); err != nil {
+		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
+	}
+	if err := doc.Cleanup(options.Cleanup); err != nil {
+		return nil, err
+	}
+	if options.Locale != "" {
+		if err := doc.SetLanguage(options.Locale); err != nil {
+			return nil, err
+		}
+	}
+	doc.SetOutputLimits(options.OutputLimits)
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write document to bytes: %w", err)
+	}
+	if options.Conformance == ConformanceStrict {
+		return ConvertToStrict(buf.Bytes())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testQuantity int
+
+func TestFormatValue(t *testing.T) {
+	date := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value    interface{}
+		locale   string
+		expected string
+	}{
+		{nil, "de-DE", ""},
+		{"text", "de-DE", "text"},
+		{10042, "en-US", "10042"},
+		{testQuantity(-3), "de-DE", "-3"},
+		{uint8(7), "", "7"},
+		{1234.5, "", "1234.5"},
+		{1234.5, "en-US", "1,234.5"},
+		{1234.5, "de-DE", "1.234,5"},
+		{float32(0.25), "fr-FR", "0,25"},
+		{true, "de-DE", "true"},
+		{date, "", "2024-12-31"},
+		{date, "en-US", "12/31/2024"},
+		{date, "en-GB", "31/12/2024"},
+		{&date, "de-DE", "31.12.2024"},
+		{date, "fi-FI", "2024-12-31"},
+		{date.Add(14*time.Hour + 5*time.Minute), "en-US", "12/31/2024 2:05 PM"},
+		{date.Add(14*time.Hour + 5*time.Minute), "de-AT", "31.12.2024 14:05"},
+		{time.Second, "de-DE", "1s"},
+	} {
+		text, err := FormatValue(tc.value, tc.locale)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", tc.value, err)
+		} else if text != tc.expected {
+			t.Errorf("expected %v with locale %q to be %q, have %q", tc.value, tc.locale, tc.expected, text)
+		}
+	}
+
+	if _, err := FormatValue(1.5, "not a locale"); err == nil {
+		t.Error("expected error for invalid locale")
+	}
+}
+
+func TestProcessValues(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{total} due on {date}, paid: {paid}, {name}</w:t></w:r></w:p>`)
+	values := PlaceholderMap{
+		"total": 1234.5,
+		"date":  time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		"paid":  false,
+		"name":  "Jane",
+	}
+
+	output, err := ProcessValues(input, values, RenderOptions{Locale: "de-DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "1.234,5 due on 31.12.2024, paid: false, Jane") {
+		t.Errorf("unexpected document: %s", document)
+	}
+	if styles := readTestPart(t, output, StylesXml); !strings.Contains(styles, `<w:lang w:val="de-DE"/>`) {
+		t.Errorf("expected the locale as document language: %s", styles)
+	}
+
+	_, err = ProcessValues(input, values, RenderOptions{Locale: "de-DE", StrictValues: Bool(true)})
+	if !errors.Is(err, ErrUnsupportedValue) {
+		t.Errorf("expected unsupported value error, have %v", err)
+	}
+	output, err = ProcessValues(input, PlaceholderMap{"total": "1234.50"}, RenderOptions{StrictValues: Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, output, DocumentXml); !strings.Contains(document, "1234.50 due on {date}") {
+		t.Errorf("unexpected document: %s", document)
+	}
+}
@@ -0,0 +1,197 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	// vanishXml is the run property which hides text.
+	vanishXml = `<w:vanish/>`
+
+	// visibility markers are written by the template functions and resolved after rendering.
+	// They use characters from the unicode private use area which are never part of a regular document.
+	hideParagraphMarker = "hide"
+	hideBeginMarker     = "hideBegin"
+	showBeginMarker     = "showBegin"
+	hideEndMarker       = "hideEnd"
+)
+
+var (
+	// visibilityMarkerRegex matches the markers which are written by the visibility template functions.
+	visibilityMarkerRegex = regexp.MustCompile(`\x{E000}(hide|hideBegin|showBegin|hideEnd)\x{E001}`)
+	// ParagraphOpenRegex matches the open tag of a paragraph.
+	ParagraphOpenRegex = regexp.MustCompile(`<w:p(?:\s[^>]*)?>`)
+	// paragraphPropertiesRegex matches a paragraph open tag followed by its optional paragraph properties.
+	paragraphPropertiesRegex = regexp.MustCompile(`(?s)(<w:p(?:\s[^>]*)?>)(<w:pPr>.*?</w:pPr>|<w:pPr/>)?`)
+	// runPropertiesOpenRegex matches a run open tag followed by its optional run properties.
+	runPropertiesOpenRegex = regexp.MustCompile(`(?s)(<w:r(?:\s[^>]*)?>)(<w:rPr>.*?</w:rPr>|<w:rPr/>)?`)
+)
+
+// visibilityFuncs returns the template functions which hide paragraphs using hidden text (<w:vanish/>).
+// Hidden text stays in the document, it is just not displayed or printed, which makes hiding a non-destructive
+// alternative to removing content with {{if}}, e.g. when downstream tooling must still see the text.
+//
+//	{{hideIf .Cond}}        -> hides the paragraph containing the action if the argument is true
+//	{{showIf .Cond}}        -> hides the paragraph containing the action unless the argument is true
+//	{{hideBlockIf .Cond}}   -> hides all paragraphs from this one up to the paragraph containing {{endHide}}
+//	{{showBlockIf .Cond}}   -> the opposite of hideBlockIf
+//	{{endHide}}             -> ends a block of hideBlockIf or showBlockIf
+//
+// The truth of the arguments is determined like in {{if}}. Blocks may be nested.
+func visibilityFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"hideIf": func(cond interface{}) string {
+			if isTrue(cond) {
+				return visibilityMarker(hideParagraphMarker)
+			}
+			return ""
+		},
+		"showIf": func(cond interface{}) string {
+			if !isTrue(cond) {
+				return visibilityMarker(hideParagraphMarker)
+			}
+			return ""
+		},
+		"hideBlockIf": func(cond interface{}) string {
+			if isTrue(cond) {
+				return visibilityMarker(hideBeginMarker)
+			}
+			return visibilityMarker(showBeginMarker)
+		},
+		"showBlockIf": func(cond interface{}) string {
+			if isTrue(cond) {
+				return visibilityMarker(showBeginMarker)
+			}
+			return visibilityMarker(hideBeginMarker)
+		},
+		"endHide": func() string {
+			return visibilityMarker(hideEndMarker)
+		},
+	}
+}
+
+// isTrue reports whether the value is true in the sense of {{if}}.
+func isTrue(value interface{}) bool {
+	truth, _ := template.IsTrue(value)
+	return truth
+}
+
+// visibilityMarker returns the marker of the given kind.
+func visibilityMarker(kind string) string {
+	return "\uE000" + kind + "\uE001"
+}
+
+// applyVisibility hides all paragraphs which are marked by the visibility template functions and removes the markers.
+func applyVisibility(data []byte) ([]byte, error) {
+	markers := visibilityMarkerRegex.FindAllSubmatchIndex(data, -1)
+	if len(markers) == 0 {
+		return data, nil
+	}
+
+	paragraphStarts := ParagraphOpenRegex.FindAllIndex(data, -1)
+	paragraphOf := func(pos int) (Position, error) {
+		i := sort.Search(len(paragraphStarts), func(i int) bool {
+			return paragraphStarts[i][0] > pos
+		}) - 1
+		end := strings.Index(string(data[pos:]), "</w:p>")
+		if i < 0 || end < 0 {
+			return Position{}, fmt.Errorf("visibility functions must be used inside of a paragraph")
+		}
+		return Position{Start: int64(paragraphStarts[i][0]), End: int64(pos + end + len("</w:p>"))}, nil
+	}
+
+	type block struct {
+		start  Position
+		hidden bool
+	}
+	var blocks []block
+	var hidden []Position
+
+	for _, marker := range markers {
+		paragraph, err := paragraphOf(marker[0])
+		if err != nil {
+			return nil, err
+		}
+		switch string(data[marker[2]:marker[3]]) {
+		case hideParagraphMarker:
+			hidden = append(hidden, paragraph)
+		case hideBeginMarker, showBeginMarker:
+			blocks = append(blocks, block{start: paragraph, hidden: string(data[marker[2]:marker[3]]) == hideBeginMarker})
+		case hideEndMarker:
+			if len(blocks) == 0 {
+				return nil, fmt.Errorf("endHide without hideBlockIf or showBlockIf")
+			}
+			b := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+			if b.hidden {
+				hidden = append(hidden, Position{Start: b.start.Start, End: paragraph.End})
+			}
+		}
+	}
+	if len(blocks) > 0 {
+		return nil, fmt.Errorf("hideBlockIf or showBlockIf without endHide")
+	}
+
+	var out strings.Builder
+	last := int64(0)
+	for _, r := range mergePositions(hidden) {
+		out.Write(data[last:r.Start])
+		out.WriteString(hideRuns(string(data[r.Start:r.End])))
+		last = r.End
+	}
+	out.Write(data[last:])
+
+	return visibilityMarkerRegex.ReplaceAll([]byte(out.String()), nil), nil
+}
+
+// mergePositions sorts the positions and merges overlapping ones.
+func mergePositions(positions []Position) []Position {
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Start < positions[j].Start
+	})
+	var merged []Position
+	for _, p := range positions {
+		if len(merged) > 0 && p.Start <= merged[len(merged)-1].End {
+			if p.End > merged[len(merged)-1].End {
+				merged[len(merged)-1].End = p.End
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// hideRuns marks all runs and paragraph marks inside the given paragraphs as hidden text.
+func hideRuns(paragraphs string) string {
+	paragraphs = runPropertiesOpenRegex.ReplaceAllStringFunc(paragraphs, func(match string) string {
+		parts := runPropertiesOpenRegex.FindStringSubmatch(match)
+		return parts[1] + setRunProperty(parts[2], "vanish", vanishXml)
+	})
+
+	// the paragraph mark is hidden as well, otherwise an empty line remains
+	return paragraphPropertiesRegex.ReplaceAllStringFunc(paragraphs, func(match string) string {
+		parts := paragraphPropertiesRegex.FindStringSubmatch(match)
+		properties := parts[2]
+		switch {
+		case properties == "" || properties == "<w:pPr/>":
+			return parts[1] + "<w:pPr><w:rPr>" + vanishXml + "</w:rPr></w:pPr>"
+		case RunPropertiesRegex.MatchString(properties):
+			return parts[1] + RunPropertiesRegex.ReplaceAllStringFunc(properties, func(rPr string) string {
+				return setRunProperty(rPr, "vanish", vanishXml)
+			})
+		}
+		// the run properties of the paragraph mark precede the section properties and the change tracking
+		insert := len(properties) - len("</w:pPr>")
+		for _, following := range []string{"<w:sectPr", "<w:pPrChange"} {
+			if pos := strings.Index(properties, following); pos >= 0 && pos < insert {
+				insert = pos
+			}
+		}
+		return parts[1] + properties[:insert] + "<w:rPr>" + vanishXml + "</w:rPr>" + properties[insert:]
+	})
+}
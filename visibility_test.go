@@ -0,0 +1,64 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessTemplateDocx_Visibility(t *testing.T) {
+	input := buildTestDocx(t,
+		`<w:p><w:r><w:rPr><w:b/><w:color w:val="FF0000"/></w:rPr><w:t>{{hideIf .Internal}}Internal note</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>{{showIf .Internal}}Public note</w:t></w:r></w:p>`+
+			`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>{{hideBlockIf .Draft}}Draft start</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Draft body</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Draft end{{endHide}}</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Always visible</w:t></w:r></w:p>`)
+
+	output, err := ProcessTemplateDocx(input, map[string]interface{}{"Internal": true, "Draft": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readTestPart(t, output, DocumentXml)
+
+	for _, expected := range []string{
+		`<w:p><w:pPr><w:rPr><w:vanish/></w:rPr></w:pPr><w:r><w:rPr><w:b/><w:vanish/><w:color w:val="FF0000"/></w:rPr><w:t xml:space="preserve">Internal note</w:t></w:r></w:p>`,
+		`<w:p><w:pPr><w:jc w:val="center"/><w:rPr><w:vanish/></w:rPr></w:pPr><w:r><w:rPr><w:vanish/></w:rPr><w:t xml:space="preserve">Draft start</w:t></w:r></w:p>`,
+		`<w:r><w:rPr><w:vanish/></w:rPr><w:t>Draft body</w:t></w:r>`,
+		`<w:r><w:rPr><w:vanish/></w:rPr><w:t xml:space="preserve">Draft end</w:t></w:r>`,
+		`<w:p><w:r><w:t xml:space="preserve">Public note</w:t></w:r></w:p>`,
+		`<w:p><w:r><w:t>Always visible</w:t></w:r></w:p>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected %s in document: %s", expected, document)
+		}
+	}
+	if strings.Contains(document, "") {
+		t.Errorf("expected all markers to be removed: %s", document)
+	}
+}
+
+func TestProcessTemplateDocx_VisibilityUnbalanced(t *testing.T) {
+	input := buildTestDocx(t, `<w:p><w:r><w:t>{{hideBlockIf true}}text</w:t></w:r></w:p>`)
+	if _, err := ProcessTemplateDocx(input, nil); err == nil {
+		t.Error("expected an error for a block without endHide")
+	}
+}
+
+func TestSetRunProperty(t *testing.T) {
+	tests := []struct {
+		runProperties string
+		expected      string
+	}{
+		{``, `<w:rPr><w:vanish/></w:rPr>`},
+		{`<w:rPr/>`, `<w:rPr><w:vanish/></w:rPr>`},
+		{`<w:rPr><w:rStyle w:val="Strong"/><w:sz w:val="24"/></w:rPr>`, `<w:rPr><w:rStyle w:val="Strong"/><w:vanish/><w:sz w:val="24"/></w:rPr>`},
+		{`<w:rPr><w:b/></w:rPr>`, `<w:rPr><w:b/><w:vanish/></w:rPr>`},
+		{`<w:rPr><w:vanish w:val="0"/></w:rPr>`, `<w:rPr><w:vanish/></w:rPr>`},
+		{`<w:rPr><w:b/><w:rPrChange w:id="1"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr>`, `<w:rPr><w:b/><w:vanish/><w:rPrChange w:id="1"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr>`},
+	}
+	for _, test := range tests {
+		if result := setRunProperty(test.runProperties, "vanish", vanishXml); result != test.expected {
+			t.Errorf("%s: expected %s, have %s", test.runProperties, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	zipEndOfCentralDirSignature    = 0x06054b50
+	zipEndOfCentralDirLength       = 22
+	zipCentralDirSignature         = 0x02014b50
+	zipCentralDirHeaderLength      = 46
+	zipDirectory64LocatorSignature = 0x07064b50
+	zipDirectory64LocatorLength    = 20
+	zipMaxCommentLength            = 0xffff
+)
+
+// ErrPatchUnsupported is returned if an archive cannot be patched in place, e.g. because it uses the ZIP64 format.
+// Such archives must be written completely instead.
+var ErrPatchUnsupported = errors.New("archive cannot be patched in place")
+
+// zipDirectory is the parsed central directory of a ZIP archive.
+type zipDirectory struct {
+	offset  int64
+	comment []byte
+	// records are the raw central directory records in archive order
+	records []zipDirectoryRecord
+}
+
+// zipDirectoryRecord is a single raw central directory record.
+type zipDirectoryRecord struct {
+	name string
+	raw  []byte
+}
+
+// PatchZipFile replaces the entries of the ZIP archive at path with the contents of the FileMap and adds entries
+// which do not exist yet. Instead of rewriting the whole archive, only the changed entries and the central directory
+// are appended behind the existing entries. All other entries are left untouched on disk, which makes updating a few
+// small parts of a huge archive (e.g. the document.xml of a catalog with hundreds of megabytes of images) fast.
+// The space of the replaced entries is not reclaimed, writing the archive completely compacts it again.
+//
+// The file is modified in place and is corrupt if the process is interrupted while patching.
+// ErrPatchUnsupported is returned for ZIP64 archives.
+func PatchZipFile(path string, entries FileMap) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	directory, err := readZipDirectory(file, info.Size(), 0)
+	if err != nil {
+		return err
+	}
+
+	// the changed entries are written by a zip.Writer as if they were appended to the existing entries
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	zipWriter.SetOffset(directory.offset)
+	for _, name := range names {
+		fw, err := zipWriter.Create(name)
+		if err != nil {
+			return fmt.Errorf("unable to create writer: %w", err)
+		}
+		if err := entries.Write(fw, name); err != nil {
+			return err
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("unable to close ZIP writer: %w", err)
+	}
+	written, err := readZipDirectory(bytes.NewReader(buf.Bytes()), int64(buf.Len()), directory.offset)
+	if err != nil {
+		return err
+	}
+	localEntries := buf.Bytes()[:written.offset-directory.offset]
+
+	// the new central directory references the untouched entries at their original position
+	replaced := make(map[string][]byte, len(written.records))
+	for _, record := range written.records {
+		replaced[record.name] = record.raw
+	}
+	var central bytes.Buffer
+	count := 0
+	for _, record := range directory.records {
+		raw := record.raw
+		if patched, exists := replaced[record.name]; exists {
+			raw = patched
+			delete(replaced, record.name)
+		}
+		central.Write(raw)
+		count++
+	}
+	for _, record := range written.records {
+		if raw, added := replaced[record.name]; added {
+			central.Write(raw)
+			count++
+		}
+	}
+
+	centralOffset := directory.offset + int64(len(localEntries))
+	if count > 0xffff || centralOffset+int64(central.Len()) > 0xffffffff {
+		return fmt.Errorf("%w: patched archive requires ZIP64", ErrPatchUnsupported)
+	}
+
+	end := make([]byte, zipEndOfCentralDirLength, zipEndOfCentralDirLength+len(directory.comment))
+	binary.LittleEndian.PutUint32(end[0:], zipEndOfCentralDirSignature)
+	binary.LittleEndian.PutUint16(end[8:], uint16(count))
+	binary.LittleEndian.PutUint16(end[10:], uint16(count))
+	binary.LittleEndian.PutUint32(end[12:], uint32(central.Len()))
+	binary.LittleEndian.PutUint32(end[16:], uint32(centralOffset))
+	binary.LittleEndian.PutUint16(end[20:], uint16(len(directory.comment)))
+	end = append(end, directory.comment...)
+
+	var tail bytes.Buffer
+	tail.Write(localEntries)
+	tail.Write(central.Bytes())
+	tail.Write(end)
+	if _, err := file.WriteAt(tail.Bytes(), directory.offset); err != nil {
+		return fmt.Errorf("unable to patch %s: %w", path, err)
+	}
+	if err := file.Truncate(directory.offset + int64(tail.Len())); err != nil {
+		return fmt.Errorf("unable to patch %s: %w", path, err)
+	}
+	return file.Sync()
+}
+
+// readZipDirectory locates and parses the central directory of the ZIP archive.
+// The offsets inside the archive are relative to base, the position at which r starts.
+func readZipDirectory(r io.ReaderAt, size, base int64) (*zipDirectory, error) {
+	searchLength := int64(zipEndOfCentralDirLength + zipMaxCommentLength)
+	if searchLength > size {
+		searchLength = size
+	}
+	tail := make([]byte, searchLength)
+	if _, err := r.ReadAt(tail, size-searchLength); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	endPos := -1
+	for i := len(tail) - zipEndOfCentralDirLength; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) == zipEndOfCentralDirSignature {
+			endPos = i
+			break
+		}
+	}
+	if endPos < 0 {
+		return nil, fmt.Errorf("%w: end of central directory not found", zip.ErrFormat)
+	}
+	if endPos >= zipDirectory64LocatorLength &&
+		binary.LittleEndian.Uint32(tail[endPos-zipDirectory64LocatorLength:]) == zipDirectory64LocatorSignature {
+		return nil, fmt.Errorf("%w: ZIP64 archives are not supported", ErrPatchUnsupported)
+	}
+
+	end := tail[endPos:]
+	count := int(binary.LittleEndian.Uint16(end[10:]))
+	directorySize := int64(binary.LittleEndian.Uint32(end[12:]))
+	directory := &zipDirectory{offset: int64(binary.LittleEndian.Uint32(end[16:]))}
+	commentLength := int(binary.LittleEndian.Uint16(end[20:]))
+	if zipEndOfCentralDirLength+commentLength <= len(end) {
+		directory.comment = append([]byte{}, end[zipEndOfCentralDirLength:zipEndOfCentralDirLength+commentLength]...)
+	}
+	position := directory.offset - base
+	if position < 0 || position+directorySize > size-int64(len(tail)-endPos) {
+		return nil, fmt.Errorf("%w: invalid central directory offset", zip.ErrFormat)
+	}
+
+	data := make([]byte, directorySize)
+	if _, err := r.ReadAt(data, position); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for pos := 0; len(directory.records) < count; {
+		if pos+zipCentralDirHeaderLength > len(data) || binary.LittleEndian.Uint32(data[pos:]) != zipCentralDirSignature {
+			return nil, fmt.Errorf("%w: invalid central directory record", zip.ErrFormat)
+		}
+		nameLength := int(binary.LittleEndian.Uint16(data[pos+28:]))
+		extraLength := int(binary.LittleEndian.Uint16(data[pos+30:]))
+		commentLength := int(binary.LittleEndian.Uint16(data[pos+32:]))
+		recordEnd := pos + zipCentralDirHeaderLength + nameLength + extraLength + commentLength
+		if recordEnd > len(data) {
+			return nil, fmt.Errorf("%w: invalid central directory record", zip.ErrFormat)
+		}
+		directory.records = append(directory.records, zipDirectoryRecord{
+			name: string(data[pos+zipCentralDirHeaderLength : pos+zipCentralDirHeaderLength+nameLength]),
+			raw:  data[pos:recordEnd],
+		})
+		pos = recordEnd
+	}
+	return directory, nil
+}
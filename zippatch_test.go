@@ -0,0 +1,151 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPatchZipFile(t *testing.T) {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		fw, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(strings.Repeat(name, 100)))
+	}
+	zipWriter.SetComment("catalog")
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	original := buf.Bytes()
+
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := PatchZipFile(path, FileMap{"b.txt": []byte("patched"), "d.txt": []byte("added")}); err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory, err := readZipDirectory(bytes.NewReader(original), int64(len(original)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(patched[:directory.offset], original[:directory.offset]) {
+		t.Error("expected the existing entries to be left untouched")
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(patched), int64(len(patched)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader.Comment != "catalog" {
+		t.Errorf("expected the comment to be preserved, have %q", reader.Comment)
+	}
+	expected := map[string]string{
+		"a.txt": strings.Repeat("a.txt", 100),
+		"b.txt": "patched",
+		"c.txt": strings.Repeat("c.txt", 100),
+		"d.txt": "added",
+	}
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := readBytes(rc)
+		rc.Close()
+		if string(content) != expected[file.Name] {
+			t.Errorf("%s: expected %q, have %q", file.Name, expected[file.Name], content)
+		}
+	}
+	if strings.Join(names, ",") != "a.txt,b.txt,c.txt,d.txt" {
+		t.Errorf("unexpected entry order: %v", names)
+	}
+}
+
+func TestPatchZipFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.zip")
+	if err := os.WriteFile(path, []byte("not a zip archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := PatchZipFile(path, FileMap{"a.txt": nil}); !errors.Is(err, zip.ErrFormat) {
+		t.Errorf("expected zip.ErrFormat, have %v", err)
+	}
+}
+
+func TestDocument_WriteInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.docx")
+	input := buildTestDocx(t, `<w:p><w:r><w:t>Valid until {date}</w:t></w:r></w:p>`,
+		"word/media/image1.png", strings.Repeat("large image ", 1000))
+	if err := os.WriteFile(path, input, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Replace("date", "2025-01-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.WriteInPlace(); err != nil {
+		t.Fatal(err)
+	}
+	// the document stays usable after patching
+	if err := doc.WriteInPlace(); err != nil {
+		t.Fatal(err)
+	}
+	doc.Close()
+
+	patched, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document := readTestPart(t, patched, DocumentXml); !strings.Contains(document, "Valid until 2025-01-31") {
+		t.Errorf("expected the placeholder to be replaced: %s", document)
+	}
+	if media := readTestPart(t, patched, "word/media/image1.png"); media != strings.Repeat("large image ", 1000) {
+		t.Error("expected the media part to be unchanged")
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(patched), int64(len(patched)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range reader.File {
+		if file.Name == DocumentXml {
+			continue
+		}
+		offset, err := file.DataOffset()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if offset > int64(len(input)) {
+			t.Errorf("expected %s to stay at its original position", file.Name)
+		}
+	}
+}
+
+func TestDocument_WriteInPlaceRequiresFile(t *testing.T) {
+	doc, err := OpenBytes(buildTestDocx(t, `<w:p/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.WriteInPlace(); !errors.Is(err, ErrPatchUnsupported) {
+		t.Errorf("expected ErrPatchUnsupported, have %v", err)
+	}
+}